@@ -2,6 +2,7 @@ package assembler
 
 import (
 	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/assembler/communication"
 	"uPIMulator/src/misc"
 )
 
@@ -16,3 +17,17 @@ type Assemblable interface {
 
 	NumExecutions() int
 }
+
+// Assemblables that move data between DPUs through the host in between executions implement
+// Communicable in addition to Assemblable.
+type Communicable interface {
+	Communications(execution int) []*communication.Communication
+}
+
+// Assemblables that know the element types of their outputs implement ElementTypeable so that
+// mismatches are reported as values rather than raw bytes. The keys are the host symbol names (or
+// DPU_MRAM_HEAP_POINTER_NAME), optionally followed by "+offset" when the type starts at a byte
+// offset other than 0, and the values are int32, uint32, int64, uint64, float, or double.
+type ElementTypeable interface {
+	ElementTypes() map[string]string
+}
@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
-	"uPIMulator/src/assembler/prim"
+	"slices"
+	"uPIMulator/src/assembler/spec"
 	"uPIMulator/src/misc"
 )
 
@@ -20,7 +21,7 @@ type Assembler struct {
 
 	num_tasklets int
 
-	assemblables map[string]Assemblable
+	assemblable Assemblable
 }
 
 func (this *Assembler) Init(command_line_parser *misc.CommandLineParser) {
@@ -35,28 +36,21 @@ func (this *Assembler) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
-	this.assemblables = make(map[string]Assemblable, 0)
-
-	this.assemblables["BS"] = new(prim.Bs)
-	this.assemblables["GEMV"] = new(prim.Gemv)
-	this.assemblables["HST-L"] = new(prim.HstL)
-	this.assemblables["HST-S"] = new(prim.HstS)
-	this.assemblables["MLP"] = new(prim.Mlp)
-	this.assemblables["RED"] = new(prim.Red)
-	this.assemblables["SCAN-RSS"] = new(prim.ScanRss)
-	this.assemblables["SCAN-SSA"] = new(prim.ScanSsa)
-	this.assemblables["SEL"] = new(prim.Sel)
-	this.assemblables["TRNS"] = new(prim.Trns)
-	this.assemblables["TS"] = new(prim.Ts)
-	this.assemblables["UNI"] = new(prim.Uni)
-	this.assemblables["VA"] = new(prim.Va)
-
-	if assemblable, found := this.assemblables[this.benchmark]; found {
-		assemblable.Init(command_line_parser)
+	if command_line_parser.StringParameter("spec_filepath") != "" {
+		this.assemblable = new(spec.Spec)
+	} else if IsRegistered(this.benchmark) {
+		this.assemblable = NewAssemblable(this.benchmark)
 	} else {
-		err := errors.New("assemblable is not found")
+		err_msg := fmt.Sprintf(
+			"assemblable (%s) is not found among %v",
+			this.benchmark,
+			Registered(),
+		)
+		err := errors.New(err_msg)
 		panic(err)
 	}
+
+	this.assemblable.Init(command_line_parser)
 }
 
 func (this *Assembler) Assemble() {
@@ -65,10 +59,12 @@ func (this *Assembler) Assemble() {
 	this.AssembleInputDpuMramHeapPointerName()
 	this.AssembleOutputDpuMramHeapPointerName()
 	this.AssembleNumExecutions()
+	this.AssembleCommunications()
+	this.AssembleElementTypes()
 }
 
 func (this *Assembler) AssembleInputDpuHost() {
-	assemblable := this.assemblables[this.benchmark]
+	assemblable := this.assemblable
 
 	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
 		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
@@ -95,7 +91,7 @@ func (this *Assembler) AssembleInputDpuHost() {
 }
 
 func (this *Assembler) AssembleOutputDpuHost() {
-	assemblable := this.assemblables[this.benchmark]
+	assemblable := this.assemblable
 
 	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
 		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
@@ -122,7 +118,7 @@ func (this *Assembler) AssembleOutputDpuHost() {
 }
 
 func (this *Assembler) AssembleInputDpuMramHeapPointerName() {
-	assemblable := this.assemblables[this.benchmark]
+	assemblable := this.assemblable
 
 	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
 		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
@@ -152,7 +148,7 @@ func (this *Assembler) AssembleInputDpuMramHeapPointerName() {
 }
 
 func (this *Assembler) AssembleOutputDpuMramHeapPointerName() {
-	assemblable := this.assemblables[this.benchmark]
+	assemblable := this.assemblable
 
 	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
 		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
@@ -182,7 +178,7 @@ func (this *Assembler) AssembleOutputDpuMramHeapPointerName() {
 }
 
 func (this *Assembler) AssembleNumExecutions() {
-	assemblable := this.assemblables[this.benchmark]
+	assemblable := this.assemblable
 
 	path := filepath.Join(this.bin_dirpath, "num_executions.txt")
 
@@ -195,3 +191,56 @@ func (this *Assembler) AssembleNumExecutions() {
 
 	file_dumper.WriteLines(lines)
 }
+
+func (this *Assembler) AssembleCommunications() {
+	assemblable := this.assemblable
+
+	path := filepath.Join(this.bin_dirpath, "communications.txt")
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+
+	lines := make([]string, 0)
+
+	if communicable, ok := assemblable.(Communicable); ok {
+		for execution := 0; execution < assemblable.NumExecutions(); execution++ {
+			for _, communication_ := range communicable.Communications(execution) {
+				if communication_.Execution() != execution {
+					err := errors.New("communication's execution != execution")
+					panic(err)
+				}
+
+				lines = append(lines, communication_.Stringify())
+			}
+		}
+	}
+
+	file_dumper.WriteLines(lines)
+}
+
+func (this *Assembler) AssembleElementTypes() {
+	assemblable := this.assemblable
+
+	path := filepath.Join(this.bin_dirpath, "element_types.txt")
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+
+	lines := make([]string, 0)
+
+	if element_typeable, ok := assemblable.(ElementTypeable); ok {
+		element_types := element_typeable.ElementTypes()
+
+		names := make([]string, 0)
+		for name, _ := range element_types {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, element_types[name]))
+		}
+	}
+
+	file_dumper.WriteLines(lines)
+}
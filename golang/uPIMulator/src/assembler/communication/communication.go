@@ -0,0 +1,400 @@
+package communication
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type CommunicationType int
+
+// Explanation of communication types
+// BROADCAST: a single source DPU's region is copied to every destination DPU
+// SCATTER: a single source DPU's region is split into equal slices, one per destination DPU
+// GATHER: every source DPU's region is concatenated and copied to every destination DPU
+// COPY: the i-th source DPU's region is copied to the i-th destination DPU
+// REDUCE: every source DPU's region is summed element-wise and the sum is copied to every
+// destination DPU
+// SCAN: the element-wise sum of the regions of the source DPUs before the i-th one is copied to
+// the i-th destination DPU, so that the first destination DPU receives zeros
+// LOAD: the program image is loaded again to the destination DPUs, as dpu_load does
+const (
+	BROADCAST CommunicationType = iota
+	SCATTER
+	GATHER
+	COPY
+	REDUCE
+	SCAN
+	LOAD
+)
+
+// A communication step is performed by the host after the given execution finishes and before
+// the next execution is scheduled. Each endpoint is addressed by a symbol name (or
+// DPU_MRAM_HEAP_POINTER_NAME for the MRAM heap) plus a byte offset, and the size is the number of
+// bytes read from each source DPU. REDUCE and SCAN add the regions as the host does in between
+// executions, element by element as little-endian 64-bit integers.
+type Communication struct {
+	communication_type CommunicationType
+	execution          int
+
+	src_dpu_ids []int
+	src_name    string
+	src_offset  int64
+
+	dst_dpu_ids []int
+	dst_name    string
+	dst_offset  int64
+
+	size int64
+}
+
+func (this *Communication) InitBroadcast(
+	execution int,
+	src_dpu_id int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	this.InitCommunication(
+		BROADCAST,
+		execution,
+		[]int{src_dpu_id},
+		src_name,
+		src_offset,
+		dst_dpu_ids,
+		dst_name,
+		dst_offset,
+		size,
+	)
+}
+
+func (this *Communication) InitScatter(
+	execution int,
+	src_dpu_id int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	this.InitCommunication(
+		SCATTER,
+		execution,
+		[]int{src_dpu_id},
+		src_name,
+		src_offset,
+		dst_dpu_ids,
+		dst_name,
+		dst_offset,
+		size,
+	)
+}
+
+func (this *Communication) InitGather(
+	execution int,
+	src_dpu_ids []int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	this.InitCommunication(
+		GATHER,
+		execution,
+		src_dpu_ids,
+		src_name,
+		src_offset,
+		dst_dpu_ids,
+		dst_name,
+		dst_offset,
+		size,
+	)
+}
+
+func (this *Communication) InitCopy(
+	execution int,
+	src_dpu_ids []int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	this.InitCommunication(
+		COPY,
+		execution,
+		src_dpu_ids,
+		src_name,
+		src_offset,
+		dst_dpu_ids,
+		dst_name,
+		dst_offset,
+		size,
+	)
+}
+
+func (this *Communication) InitReduce(
+	execution int,
+	src_dpu_ids []int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	this.InitCommunication(
+		REDUCE,
+		execution,
+		src_dpu_ids,
+		src_name,
+		src_offset,
+		dst_dpu_ids,
+		dst_name,
+		dst_offset,
+		size,
+	)
+}
+
+func (this *Communication) InitScan(
+	execution int,
+	src_dpu_ids []int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	this.InitCommunication(
+		SCAN,
+		execution,
+		src_dpu_ids,
+		src_name,
+		src_offset,
+		dst_dpu_ids,
+		dst_name,
+		dst_offset,
+		size,
+	)
+}
+
+func (this *Communication) InitLoad(execution int, dpu_ids []int) {
+	this.InitCommunication(LOAD, execution, dpu_ids, "DPU_BINARY", 0, dpu_ids, "DPU_BINARY", 0, 0)
+}
+
+func (this *Communication) InitLine(line string) {
+	words := strings.Split(line, " ")
+
+	if len(words) != 9 {
+		err := errors.New("communication line cannot be parsed")
+		panic(err)
+	}
+
+	execution, execution_err := strconv.Atoi(words[0])
+	if execution_err != nil {
+		panic(execution_err)
+	}
+
+	var communication_type CommunicationType
+	if words[1] == "BROADCAST" {
+		communication_type = BROADCAST
+	} else if words[1] == "SCATTER" {
+		communication_type = SCATTER
+	} else if words[1] == "GATHER" {
+		communication_type = GATHER
+	} else if words[1] == "COPY" {
+		communication_type = COPY
+	} else if words[1] == "REDUCE" {
+		communication_type = REDUCE
+	} else if words[1] == "SCAN" {
+		communication_type = SCAN
+	} else if words[1] == "LOAD" {
+		communication_type = LOAD
+	} else {
+		err := errors.New("communication type is not valid")
+		panic(err)
+	}
+
+	src_offset, src_offset_err := strconv.ParseInt(words[4], 10, 64)
+	if src_offset_err != nil {
+		panic(src_offset_err)
+	}
+
+	dst_offset, dst_offset_err := strconv.ParseInt(words[7], 10, 64)
+	if dst_offset_err != nil {
+		panic(dst_offset_err)
+	}
+
+	size, size_err := strconv.ParseInt(words[8], 10, 64)
+	if size_err != nil {
+		panic(size_err)
+	}
+
+	this.InitCommunication(
+		communication_type,
+		execution,
+		this.ParseDpuIds(words[2]),
+		words[3],
+		src_offset,
+		this.ParseDpuIds(words[5]),
+		words[6],
+		dst_offset,
+		size,
+	)
+}
+
+// NOTE: the checks of every communication type are made here so that a hand-written
+// communications.txt parsed by InitLine is held to the same rules as the typed initializers
+func (this *Communication) InitCommunication(
+	communication_type CommunicationType,
+	execution int,
+	src_dpu_ids []int,
+	src_name string,
+	src_offset int64,
+	dst_dpu_ids []int,
+	dst_name string,
+	dst_offset int64,
+	size int64,
+) {
+	if execution < 0 {
+		err := errors.New("execution < 0")
+		panic(err)
+	} else if len(src_dpu_ids) == 0 {
+		err := errors.New("source DPU IDs are empty")
+		panic(err)
+	} else if len(dst_dpu_ids) == 0 {
+		err := errors.New("destination DPU IDs are empty")
+		panic(err)
+	} else if communication_type != LOAD && size <= 0 {
+		err := errors.New("size <= 0")
+		panic(err)
+	} else if communication_type == SCATTER && size%int64(len(dst_dpu_ids)) != 0 {
+		err := errors.New("size is not divisible by the number of destination DPUs")
+		panic(err)
+	} else if (communication_type == COPY || communication_type == SCAN) &&
+		len(src_dpu_ids) != len(dst_dpu_ids) {
+		err := errors.New("source DPU IDs' length != destination DPU IDs' length")
+		panic(err)
+	} else if (communication_type == REDUCE || communication_type == SCAN) && size%8 != 0 {
+		err := errors.New("size is not a multiple of 64-bit elements")
+		panic(err)
+	}
+
+	for _, dpu_id := range append(append([]int{}, src_dpu_ids...), dst_dpu_ids...) {
+		if dpu_id < 0 {
+			err := errors.New("DPU ID < 0")
+			panic(err)
+		}
+	}
+
+	this.communication_type = communication_type
+	this.execution = execution
+	this.src_dpu_ids = src_dpu_ids
+	this.src_name = src_name
+	this.src_offset = src_offset
+	this.dst_dpu_ids = dst_dpu_ids
+	this.dst_name = dst_name
+	this.dst_offset = dst_offset
+	this.size = size
+}
+
+func (this *Communication) CommunicationType() CommunicationType {
+	return this.communication_type
+}
+
+func (this *Communication) Execution() int {
+	return this.execution
+}
+
+func (this *Communication) SrcDpuIds() []int {
+	return this.src_dpu_ids
+}
+
+func (this *Communication) SrcName() string {
+	return this.src_name
+}
+
+func (this *Communication) SrcOffset() int64 {
+	return this.src_offset
+}
+
+func (this *Communication) DstDpuIds() []int {
+	return this.dst_dpu_ids
+}
+
+func (this *Communication) DstName() string {
+	return this.dst_name
+}
+
+func (this *Communication) DstOffset() int64 {
+	return this.dst_offset
+}
+
+func (this *Communication) Size() int64 {
+	return this.size
+}
+
+func (this *Communication) Stringify() string {
+	var communication_type string
+	if this.communication_type == BROADCAST {
+		communication_type = "BROADCAST"
+	} else if this.communication_type == SCATTER {
+		communication_type = "SCATTER"
+	} else if this.communication_type == GATHER {
+		communication_type = "GATHER"
+	} else if this.communication_type == COPY {
+		communication_type = "COPY"
+	} else if this.communication_type == REDUCE {
+		communication_type = "REDUCE"
+	} else if this.communication_type == SCAN {
+		communication_type = "SCAN"
+	} else if this.communication_type == LOAD {
+		communication_type = "LOAD"
+	} else {
+		err := errors.New("communication type is not valid")
+		panic(err)
+	}
+
+	return fmt.Sprintf(
+		"%d %s %s %s %d %s %s %d %d",
+		this.execution,
+		communication_type,
+		this.StringifyDpuIds(this.src_dpu_ids),
+		this.src_name,
+		this.src_offset,
+		this.StringifyDpuIds(this.dst_dpu_ids),
+		this.dst_name,
+		this.dst_offset,
+		this.size,
+	)
+}
+
+func (this *Communication) StringifyDpuIds(dpu_ids []int) string {
+	words := make([]string, 0)
+	for _, dpu_id := range dpu_ids {
+		words = append(words, strconv.Itoa(dpu_id))
+	}
+	return strings.Join(words, ",")
+}
+
+func (this *Communication) ParseDpuIds(word string) []int {
+	dpu_ids := make([]int, 0)
+	for _, dpu_id_word := range strings.Split(word, ",") {
+		dpu_id, err := strconv.Atoi(dpu_id_word)
+		if err != nil {
+			panic(err)
+		}
+
+		dpu_ids = append(dpu_ids, dpu_id)
+	}
+	return dpu_ids
+}
@@ -0,0 +1,129 @@
+package communication
+
+import (
+	"testing"
+)
+
+func isPanicking(function func()) (is_panicking bool) {
+	defer func() {
+		if recover() != nil {
+			is_panicking = true
+		}
+	}()
+
+	function()
+	return false
+}
+
+func TestCommunicationLines(t *testing.T) {
+	broadcast := new(Communication)
+	broadcast.InitBroadcast(
+		0,
+		1,
+		"DPU_RESULTS",
+		8,
+		[]int{0, 2, 3},
+		"DPU_MRAM_HEAP_POINTER_NAME",
+		0,
+		16,
+	)
+
+	scatter := new(Communication)
+	scatter.InitScatter(1, 0, "DPU_MRAM_HEAP_POINTER_NAME", 0, []int{1, 2}, "buffer", 4, 8)
+
+	gather := new(Communication)
+	gather.InitGather(2, []int{0, 1}, "sums", 0, []int{0}, "DPU_MRAM_HEAP_POINTER_NAME", 64, 4)
+
+	copy_ := new(Communication)
+	copy_.InitCopy(3, []int{0, 1}, "sums", 0, []int{1, 0}, "sums", 4, 4)
+
+	load := new(Communication)
+	load.InitLoad(4, []int{0, 1, 2, 3})
+
+	reduce := new(Communication)
+	reduce.InitReduce(
+		5,
+		[]int{0, 1},
+		"DPU_RESULTS",
+		8,
+		[]int{0, 1},
+		"DPU_MRAM_HEAP_POINTER_NAME",
+		32,
+		8,
+	)
+
+	scan := new(Communication)
+	scan.InitScan(6, []int{0, 1}, "DPU_RESULTS", 0, []int{0, 1}, "DPU_INPUT_ARGUMENTS", 8, 8)
+
+	lines := map[*Communication]string{
+		broadcast: "0 BROADCAST 1 DPU_RESULTS 8 0,2,3 DPU_MRAM_HEAP_POINTER_NAME 0 16",
+		scatter:   "1 SCATTER 0 DPU_MRAM_HEAP_POINTER_NAME 0 1,2 buffer 4 8",
+		gather:    "2 GATHER 0,1 sums 0 0 DPU_MRAM_HEAP_POINTER_NAME 64 4",
+		copy_:     "3 COPY 0,1 sums 0 1,0 sums 4 4",
+		load:      "4 LOAD 0,1,2,3 DPU_BINARY 0 0,1,2,3 DPU_BINARY 0 0",
+		reduce:    "5 REDUCE 0,1 DPU_RESULTS 8 0,1 DPU_MRAM_HEAP_POINTER_NAME 32 8",
+		scan:      "6 SCAN 0,1 DPU_RESULTS 0 0,1 DPU_INPUT_ARGUMENTS 8 8",
+	}
+
+	for communication_, line := range lines {
+		if communication_.Stringify() != line {
+			t.Errorf("%s is stringified, but %s is expected", communication_.Stringify(), line)
+		}
+
+		parsed := new(Communication)
+		parsed.InitLine(line)
+
+		if parsed.Stringify() != line {
+			t.Errorf("%s is parsed into %s", line, parsed.Stringify())
+		} else if parsed.CommunicationType() != communication_.CommunicationType() {
+			t.Errorf("%s is parsed into a different communication type", line)
+		}
+	}
+}
+
+func TestInvalidCommunications(t *testing.T) {
+	invalids := map[string]func(){
+		"indivisible scatter": func() {
+			new(Communication).InitScatter(0, 0, "a", 0, []int{1, 2, 3}, "a", 0, 8)
+		},
+		"unpaired copy": func() {
+			new(Communication).InitCopy(0, []int{0, 1}, "a", 0, []int{1}, "a", 0, 8)
+		},
+		"partial element reduce": func() {
+			new(Communication).InitReduce(0, []int{0, 1}, "a", 0, []int{0}, "a", 0, 4)
+		},
+		"unpaired scan": func() {
+			new(Communication).InitScan(0, []int{0, 1}, "a", 0, []int{1}, "a", 0, 8)
+		},
+		"empty size": func() {
+			new(Communication).InitBroadcast(0, 0, "a", 0, []int{1}, "a", 0, 0)
+		},
+		"negative DPU ID": func() {
+			new(Communication).InitLoad(0, []int{-1})
+		},
+		"indivisible scatter line": func() {
+			new(Communication).InitLine("0 SCATTER 0 a 0 1,2,3 a 0 8")
+		},
+		"unpaired copy line": func() {
+			new(Communication).InitLine("0 COPY 0,1 a 0 1 a 0 8")
+		},
+		"partial element reduce line": func() {
+			new(Communication).InitLine("0 REDUCE 0,1 a 0 0 a 0 4")
+		},
+		"unpaired scan line": func() {
+			new(Communication).InitLine("0 SCAN 0,1 a 0 1 a 0 8")
+		},
+		"unknown type": func() {
+			new(Communication).InitLine("0 SHUFFLE 0 a 0 1 a 0 8")
+		},
+		"missing field": func() {
+			new(Communication).InitLine("0 COPY 0 a 0 1 a 0")
+		},
+	}
+
+	for name, invalid := range invalids {
+		if !isPanicking(invalid) {
+			t.Errorf("%s is accepted", name)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package prim
 
 import (
 	"errors"
+	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -12,6 +13,8 @@ type Bs struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	size              int64
 	input_buffer      []int64
 	query_buffer      []int64
@@ -29,11 +32,18 @@ func (this *Bs) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	this.size = int64(command_line_parser.DataPrepParams()[0])
 	num_queries := int64(command_line_parser.DataPrepParams()[0] / 8)
 
+	is_strong_scaling := command_line_parser.IsStrongScaling()
+	if !is_strong_scaling {
+		num_queries *= int64(this.num_dpus)
+	}
+
 	if num_queries%int64(this.num_dpus*this.num_tasklets) != 0 {
 		num_queries += int64(this.num_dpus*this.num_tasklets) - num_queries%int64(this.num_dpus*this.num_tasklets)
 	}
@@ -50,7 +60,7 @@ func (this *Bs) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.query_buffer = make([]int64, 0)
 	for i := int64(0); i < num_queries; i++ {
-		this.query_buffer = append(this.query_buffer, i)
+		this.query_buffer = append(this.query_buffer, this.random.Int63n(this.size)+1)
 	}
 
 	this.results = make([][]int64, 0)
@@ -212,6 +222,12 @@ func (this *Bs) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Bs) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_RESULTS": "int64",
+	}
+}
+
 func (this *Bs) Sum(s []int64) int64 {
 	sum := int64(0)
 	for _, element := range s {
@@ -13,6 +13,8 @@ type Gemv struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	m_size      int64
 	n_size      int64
 	n_size_pads []int64
@@ -31,9 +33,16 @@ func (this *Gemv) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.m_size = int64(command_line_parser.DataPrepParams()[0])
 	this.n_size = 64
 
+	is_strong_scaling := command_line_parser.IsStrongScaling()
+	if !is_strong_scaling {
+		this.m_size *= int64(this.num_dpus)
+	}
+
 	this.num_executions = 1
 
 	if this.m_size%int64(this.num_dpus) != 0 {
@@ -69,13 +78,13 @@ func (this *Gemv) Init(command_line_parser *misc.CommandLineParser) {
 		this.buffer_a = append(this.buffer_a, make([]int64, 0))
 
 		for j := int64(0); j < this.n_size; j++ {
-			this.buffer_a[i] = append(this.buffer_a[i], int64(rand.Intn(50)))
+			this.buffer_a[i] = append(this.buffer_a[i], int64(this.random.Intn(50)))
 		}
 	}
 
 	this.buffer_b = make([]int64, 0)
 	for i := int64(0); i < this.n_size; i++ {
-		this.buffer_b = append(this.buffer_b, int64(rand.Intn(50)))
+		this.buffer_b = append(this.buffer_b, int64(this.random.Intn(50)))
 	}
 
 	this.buffer_c = make([][]int64, 0)
@@ -221,6 +230,12 @@ func (this *Gemv) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Gemv) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "uint32",
+	}
+}
+
 func (this *Gemv) MatMul(x [][]int64, y []int64) []int64 {
 	results := make([]int64, 0)
 	for i := 0; i < len(x); i++ {
@@ -14,6 +14,8 @@ type HstL struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	num_bins              int64
 	input_size_dpu_8bytes int64
 	buffer_a              []int64
@@ -32,6 +34,8 @@ func (this *HstL) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	size := int64(command_line_parser.DataPrepParams()[0])
@@ -39,7 +43,7 @@ func (this *HstL) Init(command_line_parser *misc.CommandLineParser) {
 
 	elem_size := int64(4)
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 
 	var input_size int64
 	if is_strong_scaling {
@@ -65,7 +69,7 @@ func (this *HstL) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.buffer_a = make([]int64, 0)
 	for i := int64(0); i < input_size; i++ {
-		this.buffer_a = append(this.buffer_a, int64(rand.Intn(4096)))
+		this.buffer_a = append(this.buffer_a, int64(this.random.Intn(4096)))
 	}
 
 	depth := int64(12)
@@ -208,6 +212,12 @@ func (this *HstL) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *HstL) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "uint32",
+	}
+}
+
 func (this *HstL) Pow2(exponent int) int {
 	if exponent < 0 {
 		err := errors.New("exponent < 0")
@@ -2,6 +2,7 @@ package prim
 
 import (
 	"errors"
+	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -12,6 +13,8 @@ type Mlp struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	m_size      int64
 	n_size      int64
 	num_layers  int64
@@ -31,9 +34,17 @@ func (this *Mlp) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.m_size = int64(command_line_parser.DataPrepParams()[0])
 	this.n_size = int64(command_line_parser.DataPrepParams()[0])
 
+	// NOTE: weak scaling grows the rows only, so every DPU keeps the same share of each layer
+	is_strong_scaling := command_line_parser.IsStrongScaling()
+	if !is_strong_scaling {
+		this.m_size *= int64(this.num_dpus)
+	}
+
 	this.num_layers = 3
 
 	this.num_executions = int(this.num_layers)
@@ -77,7 +88,7 @@ func (this *Mlp) Init(command_line_parser *misc.CommandLineParser) {
 				if k%100 < 98 {
 					this.buffer_a[i][j] = append(this.buffer_a[i][j], 0)
 				} else {
-					this.buffer_a[i][j] = append(this.buffer_a[i][j], (i+k)%2)
+					this.buffer_a[i][j] = append(this.buffer_a[i][j], this.random.Int63n(2))
 				}
 			}
 		}
@@ -91,7 +102,7 @@ func (this *Mlp) Init(command_line_parser *misc.CommandLineParser) {
 			if j%50 < 48 {
 				this.buffer_b[i] = append(this.buffer_b[i], 0)
 			} else {
-				this.buffer_b[i] = append(this.buffer_b[i], j%2)
+				this.buffer_b[i] = append(this.buffer_b[i], this.random.Int63n(2))
 			}
 		}
 	}
@@ -118,14 +129,17 @@ func (this *Mlp) Init(command_line_parser *misc.CommandLineParser) {
 				}
 			}
 
+			// NOTE: as B = C in the host of PrIM, the next layer takes the first n size rows of C
 			if i < this.num_layers-1 {
-				if len(this.buffer_b[i+1][start_row:end_row]) != len(this.buffer_c[i][j]) {
-					err := errors.New("buffer b [i+1][start_row:end_row]'s length != buffer c [i][j]'s length")
+				if end_row-start_row != int64(len(this.buffer_c[i][j])) {
+					err := errors.New("num rows != buffer c [i][j]'s length")
 					panic(err)
 				}
 
 				for k, element := range this.buffer_c[i][j] {
-					this.buffer_b[i+1][k] = element
+					if start_row+int64(k) < this.n_size {
+						this.buffer_b[i+1][start_row+int64(k)] = element
+					}
 				}
 			}
 		}
@@ -262,6 +276,12 @@ func (this *Mlp) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Mlp) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "int32",
+	}
+}
+
 func (this *Mlp) MatMul(x [][]int64, y []int64) []int64 {
 	results := make([]int64, 0)
 	for i := 0; i < len(x); i++ {
@@ -0,0 +1,21 @@
+package prim
+
+import (
+	"uPIMulator/src/assembler"
+)
+
+func init() {
+	assembler.Register("BS", func() assembler.Assemblable { return new(Bs) })
+	assembler.Register("GEMV", func() assembler.Assemblable { return new(Gemv) })
+	assembler.Register("HST-L", func() assembler.Assemblable { return new(HstL) })
+	assembler.Register("HST-S", func() assembler.Assemblable { return new(HstS) })
+	assembler.Register("MLP", func() assembler.Assemblable { return new(Mlp) })
+	assembler.Register("RED", func() assembler.Assemblable { return new(Red) })
+	assembler.Register("SCAN-RSS", func() assembler.Assemblable { return new(ScanRss) })
+	assembler.Register("SCAN-SSA", func() assembler.Assemblable { return new(ScanSsa) })
+	assembler.Register("SEL", func() assembler.Assemblable { return new(Sel) })
+	assembler.Register("TRNS", func() assembler.Assemblable { return new(Trns) })
+	assembler.Register("TS", func() assembler.Assemblable { return new(Ts) })
+	assembler.Register("UNI", func() assembler.Assemblable { return new(Uni) })
+	assembler.Register("VA", func() assembler.Assemblable { return new(Va) })
+}
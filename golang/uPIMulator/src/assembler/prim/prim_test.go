@@ -0,0 +1,952 @@
+package prim
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/assembler"
+	"uPIMulator/src/assembler/communication"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: every reference below is computed from the byte streams an Assemblable hands to the
+// host only, never from its internal buffers, so that the data preparation of a benchmark and
+// the expected outputs it derives are checked against an independent CPU implementation.
+
+type primConfig struct {
+	num_dpus_per_rank int
+	num_tasklets      int
+	data_prep_params  string
+}
+
+var prim_configs = map[string][]primConfig{
+	"BS":       {{1, 1, "256"}, {4, 4, "256"}},
+	"GEMV":     {{1, 1, "8"}, {4, 2, "32"}},
+	"HST-L":    {{1, 1, "256"}, {4, 4, "1024"}},
+	"HST-S":    {{1, 1, "256"}, {4, 4, "1024"}},
+	"MLP":      {{1, 1, "128"}, {4, 2, "128"}},
+	"RED":      {{1, 1, "256"}, {4, 4, "1000"}},
+	"SCAN-RSS": {{1, 1, "256"}, {2, 2, "1000"}},
+	"SCAN-SSA": {{1, 1, "256"}, {2, 2, "1000"}},
+	"SEL":      {{1, 1, "256"}, {4, 2, "1000"}},
+	"TRNS":     {{1, 1, "2"}, {4, 2, "2"}},
+	"TS":       {{1, 1, "256"}, {2, 2, "512"}},
+	"UNI":      {{1, 1, "256"}, {4, 2, "1000"}},
+	"VA":       {{1, 1, "256"}, {4, 4, "1000"}},
+}
+
+var prim_references = map[string]func(*testing.T, assembler.Assemblable, primConfig){
+	"BS":       referenceBs,
+	"GEMV":     referenceGemv,
+	"HST-L":    referenceHst,
+	"HST-S":    referenceHst,
+	"MLP":      referenceMlp,
+	"RED":      referenceRed,
+	"SCAN-RSS": referenceScanRss,
+	"SCAN-SSA": referenceScanSsa,
+	"SEL":      referenceSel,
+	"TRNS":     referenceTrns,
+	"TS":       referenceTs,
+	"UNI":      referenceUni,
+	"VA":       referenceVa,
+}
+
+func newCommandLineParser(config primConfig) *misc.CommandLineParser {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+
+	command_line_parser.AddOption(misc.INT, "num_channels", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_ranks_per_channel", "1", "")
+	command_line_parser.AddOption(
+		misc.INT,
+		"num_dpus_per_rank",
+		strconv.Itoa(config.num_dpus_per_rank),
+		"",
+	)
+	command_line_parser.AddOption(misc.INT, "num_tasklets", strconv.Itoa(config.num_tasklets), "")
+	command_line_parser.AddOption(misc.STRING, "data_prep_params", config.data_prep_params, "")
+	command_line_parser.AddOption(misc.INT, "seed", "0", "")
+	command_line_parser.AddOption(misc.STRING, "scaling", "strong", "")
+
+	return command_line_parser
+}
+
+func newAssemblable(name string, config primConfig) assembler.Assemblable {
+	assemblable := assembler.NewAssemblable(name)
+	assemblable.Init(newCommandLineParser(config))
+	return assemblable
+}
+
+func forEachConfig(t *testing.T, run func(*testing.T, string, primConfig)) {
+	for _, name := range assembler.Registered() {
+		configs, found := prim_configs[name]
+		if !found {
+			t.Errorf("%s has no test configuration", name)
+			continue
+		}
+
+		for _, config := range configs {
+			test_name := fmt.Sprintf(
+				"%s/dpus=%d/tasklets=%d/params=%s",
+				name,
+				config.num_dpus_per_rank,
+				config.num_tasklets,
+				config.data_prep_params,
+			)
+
+			t.Run(test_name, func(t *testing.T) {
+				run(t, name, config)
+			})
+		}
+	}
+}
+
+func isPanicking(function func()) (is_panicking bool) {
+	defer func() {
+		if recover() != nil {
+			is_panicking = true
+		}
+	}()
+
+	function()
+	return false
+}
+
+func decode(
+	byte_stream *encoding.ByteStream,
+	begin int,
+	element_size int,
+	num_elements int,
+	is_signed bool,
+) []int64 {
+	elements := make([]int64, 0)
+
+	for i := 0; i < num_elements; i++ {
+		bits := uint64(0)
+		for j := 0; j < element_size; j++ {
+			bits |= uint64(byte_stream.Get(begin+i*element_size+j)) << (8 * j)
+		}
+
+		if is_signed && element_size < 8 && bits&(uint64(1)<<(8*element_size-1)) != 0 {
+			bits |= ^uint64(0) << (8 * element_size)
+		}
+
+		elements = append(elements, int64(bits))
+	}
+
+	return elements
+}
+
+func decodeAll(byte_stream *encoding.ByteStream, element_size int, is_signed bool) []int64 {
+	return decode(byte_stream, 0, element_size, int(byte_stream.Size())/element_size, is_signed)
+}
+
+func expectElements(
+	t *testing.T,
+	label string,
+	byte_stream *encoding.ByteStream,
+	element_size int,
+	is_signed bool,
+	expected []int64,
+) {
+	t.Helper()
+
+	if byte_stream.Size() != int64(len(expected)*element_size) {
+		t.Errorf(
+			"%s: size is %d bytes, but the reference has %d bytes",
+			label,
+			byte_stream.Size(),
+			len(expected)*element_size,
+		)
+		return
+	}
+
+	actual := decodeAll(byte_stream, element_size, is_signed)
+
+	num_mismatches := 0
+	for i := range expected {
+		if actual[i] != expected[i] {
+			if num_mismatches < 4 {
+				t.Errorf("%s[%d]: %d, but the reference is %d", label, i, actual[i], expected[i])
+			}
+			num_mismatches++
+		}
+	}
+
+	if num_mismatches >= 4 {
+		t.Errorf("%s: %d mismatches in total", label, num_mismatches)
+	}
+}
+
+func expectOffset(t *testing.T, label string, offset int64, expected int64) {
+	t.Helper()
+
+	if offset != expected {
+		t.Errorf("%s: offset is %d, but the reference is %d", label, offset, expected)
+	}
+}
+
+// NOTE: the communication must span every DPU in order, as the host of PrIM does
+func expectCommunication(
+	t *testing.T,
+	assemblable assembler.Assemblable,
+	execution int,
+	communication_type communication.CommunicationType,
+	num_dpus int,
+) *communication.Communication {
+	t.Helper()
+
+	communicable, ok := assemblable.(assembler.Communicable)
+	if !ok {
+		t.Errorf("execution %d: the host does not communicate", execution)
+		return nil
+	}
+
+	communications := communicable.Communications(execution)
+	if len(communications) != 1 {
+		t.Errorf("execution %d: %d communications", execution, len(communications))
+		return nil
+	} else if communications[0].CommunicationType() != communication_type {
+		t.Errorf("execution %d: %s is of another type", execution, communications[0].Stringify())
+		return nil
+	}
+
+	for _, dpu_ids := range [][]int{communications[0].SrcDpuIds(), communications[0].DstDpuIds()} {
+		if len(dpu_ids) != num_dpus {
+			t.Errorf("execution %d: %s skips DPUs", execution, communications[0].Stringify())
+		}
+
+		for i, dpu_id := range dpu_ids {
+			if dpu_id != i {
+				t.Errorf("execution %d: %s is out of order", execution, communications[0].Stringify())
+			}
+		}
+	}
+
+	return communications[0]
+}
+
+func numDpus(config primConfig) int {
+	return config.num_dpus_per_rank
+}
+
+func TestPrimBenchmarksHaveReferences(t *testing.T) {
+	for _, name := range assembler.Registered() {
+		if _, found := prim_references[name]; !found {
+			t.Errorf("%s has no reference implementation", name)
+		}
+	}
+
+	for name, _ := range prim_references {
+		if !assembler.IsRegistered(name) {
+			t.Errorf("%s has a reference implementation, but is not registered", name)
+		}
+	}
+}
+
+func TestPrimCoverage(t *testing.T) {
+	forEachConfig(t, func(t *testing.T, name string, config primConfig) {
+		assemblable := newAssemblable(name, config)
+		num_dpus := numDpus(config)
+		num_executions := assemblable.NumExecutions()
+
+		if num_executions <= 0 {
+			t.Fatalf("num executions (%d) <= 0", num_executions)
+		}
+
+		symbols := make(map[string]bool, 0)
+		for execution := 0; execution < num_executions; execution++ {
+			var input_names []string
+			var output_names []string
+
+			for dpu_id := 0; dpu_id < num_dpus; dpu_id++ {
+				label := fmt.Sprintf("execution %d, DPU %d", execution, dpu_id)
+
+				var input_dpu_host map[string]*encoding.ByteStream
+				var output_dpu_host map[string]*encoding.ByteStream
+				var input_offset int64
+				var input_byte_stream *encoding.ByteStream
+				var output_offset int64
+				var output_byte_stream *encoding.ByteStream
+
+				if isPanicking(func() {
+					input_dpu_host = assemblable.InputDpuHost(execution, dpu_id)
+					output_dpu_host = assemblable.OutputDpuHost(execution, dpu_id)
+					input_offset, input_byte_stream = assemblable.InputDpuMramHeapPointerName(
+						execution,
+						dpu_id,
+					)
+					output_offset, output_byte_stream = assemblable.OutputDpuMramHeapPointerName(
+						execution,
+						dpu_id,
+					)
+				}) {
+					t.Fatalf("%s: panics", label)
+				}
+
+				if input_dpu_host == nil || output_dpu_host == nil {
+					t.Fatalf("%s: DPU host symbols are nil", label)
+				} else if input_byte_stream == nil || output_byte_stream == nil {
+					t.Fatalf("%s: MRAM heap byte streams are nil", label)
+				}
+
+				if _, found := input_dpu_host["DPU_INPUT_ARGUMENTS"]; !found {
+					t.Errorf("%s: DPU_INPUT_ARGUMENTS is not transferred", label)
+				}
+
+				if dpu_id == 0 {
+					input_names = sortedNames(input_dpu_host)
+					output_names = sortedNames(output_dpu_host)
+				} else if fmt.Sprint(sortedNames(input_dpu_host)) != fmt.Sprint(input_names) {
+					t.Errorf("%s: input DPU host symbols differ from DPU 0", label)
+				} else if fmt.Sprint(sortedNames(output_dpu_host)) != fmt.Sprint(output_names) {
+					t.Errorf("%s: output DPU host symbols differ from DPU 0", label)
+				}
+
+				for name, _ := range input_dpu_host {
+					symbols[name] = true
+				}
+				for name, _ := range output_dpu_host {
+					symbols[name] = true
+				}
+
+				if input_byte_stream.Size() > 0 || output_byte_stream.Size() > 0 {
+					symbols["DPU_MRAM_HEAP_POINTER_NAME"] = true
+				}
+
+				if input_offset < 0 || input_offset%8 != 0 {
+					t.Errorf("%s: input MRAM heap offset (%d) is not 8-byte aligned", label, input_offset)
+				} else if input_byte_stream.Size()%8 != 0 {
+					t.Errorf(
+						"%s: input MRAM heap size (%d) is not 8-byte aligned",
+						label,
+						input_byte_stream.Size(),
+					)
+				}
+
+				if output_offset < 0 || output_offset%8 != 0 {
+					t.Errorf(
+						"%s: output MRAM heap offset (%d) is not 8-byte aligned",
+						label,
+						output_offset,
+					)
+				} else if output_byte_stream.Size()%8 != 0 {
+					t.Errorf(
+						"%s: output MRAM heap size (%d) is not 8-byte aligned",
+						label,
+						output_byte_stream.Size(),
+					)
+				}
+			}
+		}
+
+		if !isPanicking(func() { assemblable.InputDpuHost(num_executions, 0) }) {
+			t.Errorf("execution >= num executions is accepted")
+		}
+
+		if !isPanicking(func() { assemblable.InputDpuMramHeapPointerName(0, num_dpus) }) {
+			t.Errorf("DPU ID >= num DPUs is accepted")
+		}
+
+		if communicable, ok := assemblable.(assembler.Communicable); ok {
+			for execution := 0; execution < num_executions; execution++ {
+				for _, communication_ := range communicable.Communications(execution) {
+					dpu_ids := append(communication_.SrcDpuIds(), communication_.DstDpuIds()...)
+					for _, dpu_id := range dpu_ids {
+						if dpu_id >= num_dpus {
+							t.Errorf("execution %d: DPU ID (%d) >= num DPUs", execution, dpu_id)
+						}
+					}
+
+					line := new(communication.Communication)
+					line.InitLine(communication_.Stringify())
+					if line.Stringify() != communication_.Stringify() {
+						t.Errorf("execution %d: %s is not parsed back", execution, line.Stringify())
+					}
+				}
+			}
+		}
+
+		if element_typeable, ok := assemblable.(assembler.ElementTypeable); ok {
+			for name, _ := range element_typeable.ElementTypes() {
+				if !symbols[name] {
+					t.Errorf("element type of %s is given, but %s is never transferred", name, name)
+				}
+			}
+		}
+	})
+}
+
+func TestPrimReferences(t *testing.T) {
+	forEachConfig(t, func(t *testing.T, name string, config primConfig) {
+		prim_references[name](t, newAssemblable(name, config), config)
+	})
+}
+
+func TestMlpWeakScaling(t *testing.T) {
+	config := primConfig{4, 2, "32"}
+
+	command_line_parser := newCommandLineParser(config)
+	command_line_parser.Parse([]string{"prim.test", "--scaling", "weak"})
+
+	assemblable := assembler.NewAssemblable("MLP")
+	assemblable.Init(command_line_parser)
+
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		arguments := decodeAll(assemblable.InputDpuHost(0, dpu_id)["DPU_INPUT_ARGUMENTS"], 4, false)
+		n_size := arguments[0]
+		nr_rows := arguments[2]
+
+		if n_size != 32 {
+			t.Errorf("DPU %d: n size is %d, but weak scaling keeps 32 columns", dpu_id, n_size)
+		} else if nr_rows != 32 {
+			t.Errorf("DPU %d: %d rows, but weak scaling keeps 32 rows per DPU", dpu_id, nr_rows)
+		}
+	}
+
+	referenceMlp(t, assemblable, config)
+}
+
+// NOTE: the host buffers of every execution and DPU are concatenated in a fixed order, each one
+// prefixed by its name or MRAM heap offset, so that two data preparations compare byte for byte
+func hostBuffers(t *testing.T, name string, config primConfig, seed int) []byte {
+	t.Helper()
+
+	command_line_parser := newCommandLineParser(config)
+	command_line_parser.Parse([]string{"prim.test", "--seed", strconv.Itoa(seed)})
+
+	assemblable := assembler.NewAssemblable(name)
+	assemblable.Init(command_line_parser)
+
+	buffers := make([]byte, 0)
+	append_byte_stream := func(label string, byte_stream *encoding.ByteStream) {
+		buffers = append(buffers, label...)
+		for i := 0; i < int(byte_stream.Size()); i++ {
+			buffers = append(buffers, byte_stream.Get(i))
+		}
+	}
+
+	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
+		for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+			for _, dpu_host := range []map[string]*encoding.ByteStream{
+				assemblable.InputDpuHost(execution, dpu_id),
+				assemblable.OutputDpuHost(execution, dpu_id),
+			} {
+				for _, symbol := range sortedNames(dpu_host) {
+					append_byte_stream(symbol, dpu_host[symbol])
+				}
+			}
+
+			offset, input := assemblable.InputDpuMramHeapPointerName(execution, dpu_id)
+			if input != nil {
+				append_byte_stream(fmt.Sprintf("input+%d", offset), input)
+			}
+
+			offset, output := assemblable.OutputDpuMramHeapPointerName(execution, dpu_id)
+			if output != nil {
+				append_byte_stream(fmt.Sprintf("output+%d", offset), output)
+			}
+		}
+	}
+
+	return buffers
+}
+
+func TestPrimSeed(t *testing.T) {
+	for _, name := range assembler.Registered() {
+		t.Run(name, func(t *testing.T) {
+			config := prim_configs[name][len(prim_configs[name])-1]
+
+			buffers := hostBuffers(t, name, config, 1)
+
+			if !bytes.Equal(hostBuffers(t, name, config, 1), buffers) {
+				t.Errorf("the same seed prepares different host buffers")
+			}
+
+			if bytes.Equal(hostBuffers(t, name, config, 2), buffers) {
+				t.Errorf("another seed prepares the same host buffers")
+			}
+		})
+	}
+}
+
+func sortedNames(dpu_host map[string]*encoding.ByteStream) []string {
+	names := make([]string, 0)
+	for name, _ := range dpu_host {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func referenceVa(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		arguments := decodeAll(assemblable.InputDpuHost(0, dpu_id)["DPU_INPUT_ARGUMENTS"], 4, false)
+		transfer_size := int(arguments[1])
+
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+		num_elements := transfer_size / 4
+
+		a := decode(input, 0, 4, num_elements, true)
+		b := decode(input, transfer_size, 4, num_elements, true)
+
+		expected := make([]int64, 0)
+		for i := 0; i < num_elements; i++ {
+			expected = append(expected, int64(int32(a[i]+b[i])))
+		}
+
+		label := fmt.Sprintf("DPU %d C", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(0, dpu_id)
+		expectOffset(t, label, offset, int64(transfer_size))
+		expectElements(t, label, output, 4, true, expected)
+	}
+}
+
+func referenceRed(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	reduce := expectCommunication(t, assemblable, 0, communication.REDUCE, numDpus(config))
+	if reduce == nil {
+		return
+	} else if reduce.SrcName() != "DPU_RESULTS" || reduce.SrcOffset() != 8 || reduce.Size() != 8 {
+		t.Errorf("%s does not reduce the count of the first tasklet", reduce.Stringify())
+	}
+
+	total := int64(0)
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		results := assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"]
+		total += decode(results, int(reduce.SrcOffset()), 8, 1, true)[0]
+	}
+
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+
+		label := fmt.Sprintf("DPU %d total count", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(0, dpu_id)
+		expectOffset(t, label, offset, input.Size())
+		expectOffset(t, label, reduce.DstOffset(), input.Size())
+		expectElements(t, label, output, 8, true, []int64{total})
+	}
+
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		arguments := decodeAll(assemblable.InputDpuHost(0, dpu_id)["DPU_INPUT_ARGUMENTS"], 4, false)
+		dpu_arg_size := int(arguments[0])
+
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+
+		sum := int64(0)
+		for _, element := range decode(input, 0, 8, dpu_arg_size/8, true) {
+			sum += element
+		}
+
+		results := decodeAll(assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"], 8, true)
+		if len(results) != 2*config.num_tasklets {
+			t.Errorf("DPU %d DPU_RESULTS has %d elements", dpu_id, len(results))
+			continue
+		}
+
+		for tasklet_id := 0; tasklet_id < config.num_tasklets; tasklet_id++ {
+			expected := int64(0)
+			if tasklet_id == 0 {
+				expected = sum
+			}
+
+			if results[2*tasklet_id+1] != expected {
+				t.Errorf(
+					"DPU %d tasklet %d count: %d, but the reference is %d",
+					dpu_id,
+					tasklet_id,
+					results[2*tasklet_id+1],
+					expected,
+				)
+			}
+		}
+	}
+}
+
+func referenceBs(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		arguments := assemblable.InputDpuHost(0, dpu_id)["DPU_INPUT_ARGUMENTS"]
+		size := int(decode(arguments, 0, 8, 1, true)[0])
+		slice_per_dpu := int(decode(arguments, 8, 8, 1, true)[0])
+
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+		elements := decode(input, 0, 8, size, true)
+		queries := decode(input, 8*size, 8, slice_per_dpu, true)
+
+		query_per_tasklet := slice_per_dpu / config.num_tasklets
+
+		expected := make([]int64, 0)
+		for tasklet_id := 0; tasklet_id < config.num_tasklets; tasklet_id++ {
+			result := int64(-1)
+			query := queries[(tasklet_id+1)*query_per_tasklet-1]
+
+			for i, element := range elements {
+				if element == query {
+					result = int64(i)
+					break
+				}
+			}
+
+			expected = append(expected, result)
+		}
+
+		label := fmt.Sprintf("DPU %d DPU_RESULTS", dpu_id)
+		expectElements(t, label, assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"], 8, true, expected)
+	}
+}
+
+func matrixVector(
+	assemblable assembler.Assemblable,
+	execution int,
+	dpu_id int,
+) ([]int64, []int64, int64) {
+	arguments := decodeAll(assemblable.InputDpuHost(execution, dpu_id)["DPU_INPUT_ARGUMENTS"], 4, false)
+	n_size := int(arguments[0])
+	nr_rows := int(arguments[2])
+
+	_, input := assemblable.InputDpuMramHeapPointerName(execution, dpu_id)
+	a := decode(input, 0, 4, nr_rows*n_size, true)
+	b := decode(input, 4*nr_rows*n_size, 4, n_size, true)
+
+	c := make([]int64, 0)
+	for i := 0; i < nr_rows; i++ {
+		dot := int32(0)
+		for j := 0; j < n_size; j++ {
+			dot += int32(a[i*n_size+j]) * int32(b[j])
+		}
+
+		c = append(c, int64(dot))
+	}
+
+	return b, c, int64(4 * (nr_rows*n_size + n_size))
+}
+
+func referenceGemv(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		_, c, c_offset := matrixVector(assemblable, 0, dpu_id)
+
+		label := fmt.Sprintf("DPU %d C", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(0, dpu_id)
+		expectOffset(t, label, offset, c_offset)
+		expectElements(t, label, output, 4, true, c)
+	}
+}
+
+func referenceMlp(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	layer_outputs := make([]int64, 0)
+
+	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
+		outputs := make([]int64, 0)
+
+		for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+			b, c, c_offset := matrixVector(assemblable, execution, dpu_id)
+
+			// NOTE: the next layer takes as many outputs of the previous layer as it has columns
+			if execution > 0 && fmt.Sprint(b) != fmt.Sprint(layer_outputs[:len(b)]) {
+				t.Errorf(
+					"execution %d, DPU %d: B is not the output of the previous layer",
+					execution,
+					dpu_id,
+				)
+			}
+
+			for i, element := range c {
+				if element < 0 {
+					c[i] = 0
+				}
+			}
+
+			label := fmt.Sprintf("execution %d, DPU %d C", execution, dpu_id)
+			offset, output := assemblable.OutputDpuMramHeapPointerName(execution, dpu_id)
+			expectOffset(t, label, offset, c_offset)
+			expectElements(t, label, output, 4, true, c)
+
+			outputs = append(outputs, c...)
+		}
+
+		layer_outputs = outputs
+	}
+}
+
+func referenceHst(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	depth := 12
+
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		arguments := decodeAll(assemblable.InputDpuHost(0, dpu_id)["DPU_INPUT_ARGUMENTS"], 4, false)
+		dpu_arg_size := int(arguments[0])
+		transfer_size := int64(arguments[1])
+		num_bins := int(arguments[2])
+
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+
+		histogram := make([]int64, num_bins)
+		for _, element := range decode(input, 0, 4, dpu_arg_size/4, false) {
+			histogram[(int(element)*num_bins)>>depth]++
+		}
+
+		label := fmt.Sprintf("DPU %d histogram", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(0, dpu_id)
+		expectOffset(t, label, offset, transfer_size)
+		expectElements(t, label, output, 4, false, histogram)
+	}
+}
+
+func referenceScan(
+	t *testing.T,
+	assemblable assembler.Assemblable,
+	config primConfig,
+	is_reduce_then_scan bool,
+) {
+	scan := expectCommunication(t, assemblable, 0, communication.SCAN, numDpus(config))
+	if scan == nil {
+		return
+	} else if scan.SrcName() != "DPU_RESULTS" || scan.DstName() != "DPU_INPUT_ARGUMENTS" {
+		t.Errorf("%s does not scan DPU_RESULTS into DPU_INPUT_ARGUMENTS", scan.Stringify())
+	} else if scan.DstOffset() != 8 || scan.Size() != 8 {
+		t.Errorf("%s does not write the count of DPU_INPUT_ARGUMENTS", scan.Stringify())
+	}
+
+	prefix_sum := int64(0)
+	t_count := int64(0)
+
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+		elements := decodeAll(input, 8, true)
+
+		sum := int64(0)
+		for _, element := range elements {
+			sum += element
+		}
+
+		results := make([]int64, config.num_tasklets)
+		if is_reduce_then_scan {
+			results[0] = sum
+		} else {
+			results[config.num_tasklets-1] = sum
+		}
+
+		label := fmt.Sprintf("DPU %d DPU_RESULTS", dpu_id)
+		dpu_results := assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"]
+		expectElements(t, label, dpu_results, 8, true, results)
+
+		// NOTE: the second execution must not overwrite the count that the host communicates
+		arguments := assemblable.InputDpuHost(1, dpu_id)["DPU_INPUT_ARGUMENTS"]
+		if arguments.Size() > scan.DstOffset() {
+			t.Errorf("DPU %d count of the previous DPUs is baked into the input", dpu_id)
+		}
+
+		if t_count != prefix_sum {
+			t.Errorf(
+				"DPU %d count of the previous DPUs: %d, but the reference is %d",
+				dpu_id,
+				t_count,
+				prefix_sum,
+			)
+		}
+
+		if dpu_results.Size() >= scan.SrcOffset()+8 {
+			t_count += decode(dpu_results, int(scan.SrcOffset()), 8, 1, true)[0]
+		}
+
+		prefix_sums := make([]int64, 0)
+		for _, element := range elements {
+			prefix_sum += element
+			prefix_sums = append(prefix_sums, prefix_sum)
+		}
+
+		label = fmt.Sprintf("DPU %d scan", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(1, dpu_id)
+		expectOffset(t, label, offset, input.Size())
+		expectElements(t, label, output, 8, true, prefix_sums)
+	}
+}
+
+func referenceScanRss(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	referenceScan(t, assemblable, config, true)
+}
+
+func referenceScanSsa(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	referenceScan(t, assemblable, config, false)
+}
+
+func referenceSel(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+
+		selected := make([]int64, 0)
+		for _, element := range decodeAll(input, 8, true) {
+			if element%2 != 0 {
+				selected = append(selected, element)
+			}
+		}
+
+		results := make([]int64, config.num_tasklets)
+		results[config.num_tasklets-1] = int64(len(selected))
+
+		label := fmt.Sprintf("DPU %d DPU_RESULTS", dpu_id)
+		expectElements(t, label, assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"], 4, false, results)
+
+		label = fmt.Sprintf("DPU %d selected", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(0, dpu_id)
+		expectOffset(t, label, offset, input.Size())
+		expectElements(t, label, output, 8, true, selected)
+	}
+}
+
+func referenceUni(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+		elements := decodeAll(input, 8, true)
+
+		unique := []int64{elements[0]}
+		for i := 1; i < len(elements); i++ {
+			if elements[i] != elements[i-1] {
+				unique = append(unique, elements[i])
+			}
+		}
+
+		results := make([]int64, 3*config.num_tasklets)
+		if config.num_tasklets > 1 {
+			results[1] = elements[0]
+		}
+		results[3*(config.num_tasklets-1)] = int64(len(unique))
+		results[3*(config.num_tasklets-1)+2] = unique[len(unique)-1]
+
+		label := fmt.Sprintf("DPU %d DPU_RESULTS", dpu_id)
+		expectElements(t, label, assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"], 8, true, results)
+
+		for len(unique) < len(elements) {
+			unique = append(unique, 0)
+		}
+
+		label = fmt.Sprintf("DPU %d unique", dpu_id)
+		offset, output := assemblable.OutputDpuMramHeapPointerName(0, dpu_id)
+		expectOffset(t, label, offset, input.Size())
+		expectElements(t, label, output, 8, true, unique)
+	}
+}
+
+func referenceTrns(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	if assemblable.NumExecutions()%2 != 0 {
+		t.Fatalf("num executions (%d) is not even", assemblable.NumExecutions())
+	}
+
+	for execution := 1; execution < assemblable.NumExecutions(); execution += 2 {
+		for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+			arguments := decodeAll(
+				assemblable.InputDpuHost(execution-1, dpu_id)["DPU_INPUT_ARGUMENTS"],
+				4,
+				false,
+			)
+			m := int(arguments[0])
+			n := int(arguments[1])
+			M := int(arguments[2])
+
+			_, input := assemblable.InputDpuMramHeapPointerName(execution-1, dpu_id)
+			a := decode(input, 0, 8, M*m*n, true)
+
+			transpose := make([]int64, 0)
+			for i := 0; i < n; i++ {
+				for j := 0; j < M*m; j++ {
+					transpose = append(transpose, a[j*n+i])
+				}
+			}
+
+			label := fmt.Sprintf("execution %d, DPU %d transpose", execution, dpu_id)
+			offset, output := assemblable.OutputDpuMramHeapPointerName(execution, dpu_id)
+			expectOffset(t, label, offset, 0)
+			expectElements(t, label, output, 8, true, transpose)
+		}
+	}
+
+	// NOTE: the WRAM tile counter of step 3 starts from zero only if the program is reloaded
+	communicable, ok := assemblable.(assembler.Communicable)
+	if !ok {
+		t.Fatalf("TRNS does not declare its reloads")
+	}
+
+	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
+		communications := communicable.Communications(execution)
+
+		if execution+1 == assemblable.NumExecutions() {
+			if len(communications) != 0 {
+				t.Errorf("execution %d: communications after the last execution", execution)
+			}
+		} else if len(communications) != 1 {
+			t.Errorf("execution %d: %d communications", execution, len(communications))
+		} else if communications[0].CommunicationType() != communication.LOAD {
+			t.Errorf("execution %d: %s is not a reload", execution, communications[0].Stringify())
+		} else if len(communications[0].DstDpuIds()) != numDpus(config) {
+			t.Errorf("execution %d: not every DPU is reloaded", execution)
+		}
+	}
+}
+
+func referenceTs(t *testing.T, assemblable assembler.Assemblable, config primConfig) {
+	block_length := 64
+
+	for dpu_id := 0; dpu_id < numDpus(config); dpu_id++ {
+		arguments := decodeAll(assemblable.InputDpuHost(0, dpu_id)["DPU_INPUT_ARGUMENTS"], 4, true)
+		query_length := int(arguments[1])
+		query_mean := arguments[2]
+		query_std := arguments[3]
+		slice_per_dpu := int(arguments[4])
+
+		_, input := assemblable.InputDpuMramHeapPointerName(0, dpu_id)
+		length := slice_per_dpu + query_length
+
+		query := decode(input, 0, 4, query_length, true)
+		t_series := decode(input, 4*query_length, 4, length, true)
+		amean := decode(input, 4*(query_length+length), 4, length, true)
+		asigma := decode(input, 4*(query_length+2*length), 4, length, true)
+
+		results := decodeAll(assemblable.OutputDpuHost(0, dpu_id)["DPU_RESULTS"], 4, true)
+		if len(results) != 4*config.num_tasklets {
+			t.Errorf("DPU %d DPU_RESULTS has %d elements", dpu_id, len(results))
+			continue
+		}
+
+		slice_per_tasklet := slice_per_dpu / config.num_tasklets
+		for tasklet_id := 0; tasklet_id < config.num_tasklets; tasklet_id++ {
+			begin := tasklet_id * slice_per_tasklet
+			end := begin + slice_per_tasklet - 1
+			if end > slice_per_dpu-query_length {
+				end = slice_per_dpu - query_length
+			}
+
+			min_val := int64(0x7FFFFFFF)
+			min_idx := int64(0)
+			for block := begin; block < end; block += block_length {
+				for i := block; i < block+block_length; i++ {
+					dot := int64(0)
+					for j := 0; j < query_length; j++ {
+						dot += t_series[i+j] * query[j]
+					}
+
+					distance := 2 * (int64(query_length) -
+						(dot-int64(query_length)*amean[i]*query_mean)/(asigma[i]*query_std))
+
+					if distance < min_val {
+						min_val = distance
+						min_idx = int64(i)
+					}
+				}
+			}
+
+			if results[4*tasklet_id] != min_val || results[4*tasklet_id+1] != min_idx {
+				t.Errorf(
+					"DPU %d tasklet %d minimum: (%d, %d), but the reference is (%d, %d)",
+					dpu_id,
+					tasklet_id,
+					results[4*tasklet_id],
+					results[4*tasklet_id+1],
+					min_val,
+					min_idx,
+				)
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
+	"uPIMulator/src/assembler/communication"
 	"uPIMulator/src/misc"
 )
 
@@ -14,9 +15,12 @@ type Red struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	input_size_dpu_8bytes int64
 	buffer_a              []int64
 	counts                []int64
+	count                 int64
 	dpu_arg_sizes         []int64
 	kernels               []int64
 	input_t_counts        []int64
@@ -32,13 +36,15 @@ func (this *Red) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	size := int64(command_line_parser.DataPrepParams()[0])
 
 	elem_size := int64(8)
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 
 	var input_size int64
 	if is_strong_scaling {
@@ -64,7 +70,7 @@ func (this *Red) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.buffer_a = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_8bytes*int64(this.num_dpus); i++ {
-		a := int64(rand.Intn(this.Pow2(31)))
+		a := int64(this.random.Intn(this.Pow2(31)))
 
 		this.buffer_a = append(this.buffer_a, a)
 	}
@@ -98,6 +104,8 @@ func (this *Red) Init(command_line_parser *misc.CommandLineParser) {
 		}
 	}
 
+	this.count = this.Sum(this.counts)
+
 	this.kernels = make([]int64, 0)
 	for i := 0; i < this.num_dpus; i++ {
 		this.kernels = append(this.kernels, 0)
@@ -233,16 +241,52 @@ func (this *Red) OutputDpuMramHeapPointerName(
 		panic(err)
 	}
 
-	byte_stream := new(encoding.ByteStream)
-	byte_stream.Init()
+	count_word := new(word.Word)
+	count_word.Init(64)
+	count_word.SetValue(this.count)
 
-	return 0, byte_stream
+	return this.input_size_dpu_8bytes * 8, count_word.ToByteStream()
+}
+
+// NOTE: the host adds up the counts that the first tasklet of every DPU reports, and the total
+// count is copied back to every DPU right after its input so that it is verified with the outputs
+func (this *Red) Communications(execution int) []*communication.Communication {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	}
+
+	dpu_ids := make([]int, 0)
+	for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+		dpu_ids = append(dpu_ids, dpu_id)
+	}
+
+	reduce := new(communication.Communication)
+	reduce.InitReduce(
+		execution,
+		dpu_ids,
+		"DPU_RESULTS",
+		8,
+		dpu_ids,
+		"DPU_MRAM_HEAP_POINTER_NAME",
+		this.input_size_dpu_8bytes*8,
+		8,
+	)
+
+	return []*communication.Communication{reduce}
 }
 
 func (this *Red) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Red) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "int64",
+		"DPU_RESULTS":                "uint64",
+	}
+}
+
 func (this *Red) Sum(s []int64) int64 {
 	sum := int64(0)
 	for _, element := range s {
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
+	"uPIMulator/src/assembler/communication"
 	"uPIMulator/src/misc"
 )
 
@@ -14,6 +15,8 @@ type ScanSsa struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	input_size_dpu_round int64
 	buffer_a             []int64
 	buffer_c             []int64
@@ -21,7 +24,6 @@ type ScanSsa struct {
 	result_t_counts      [][]int64
 	dpu_arg_size         int64
 	kernels              []int64
-	t_counts             [][]int64
 }
 
 func (this *ScanSsa) Init(command_line_parser *misc.CommandLineParser) {
@@ -32,6 +34,8 @@ func (this *ScanSsa) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 2
 
 	size := int64(command_line_parser.DataPrepParams()[0])
@@ -40,7 +44,7 @@ func (this *ScanSsa) Init(command_line_parser *misc.CommandLineParser) {
 
 	regs := int64(128)
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 
 	var input_size int64
 	if is_strong_scaling {
@@ -61,7 +65,7 @@ func (this *ScanSsa) Init(command_line_parser *misc.CommandLineParser) {
 	for i := int64(0); i < this.input_size_dpu_round*int64(this.num_dpus); i++ {
 		var a int64
 		if i < input_size {
-			a = int64(rand.Intn(100))
+			a = int64(this.random.Intn(100))
 		} else {
 			a = 0
 		}
@@ -105,25 +109,6 @@ func (this *ScanSsa) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.dpu_arg_size = this.input_size_dpu_round * elem_size
 	this.kernels = []int64{0, 1}
-
-	this.t_counts = make([][]int64, 0)
-	for i := 0; i < this.num_dpus; i++ {
-		this.t_counts = append(this.t_counts, make([]int64, 0))
-
-		for j := 0; j < 2; j++ {
-			if i == 0 {
-				this.t_counts[i] = []int64{
-					0,
-					0,
-				}
-			} else {
-				this.t_counts[i] = []int64{
-					0,
-					this.Sum2D(this.result_t_counts[0:i]),
-				}
-			}
-		}
-	}
 }
 
 func (this *ScanSsa) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
@@ -148,10 +133,13 @@ func (this *ScanSsa) InputDpuHost(execution int, dpu_id int) map[string]*encodin
 	kernel_word.SetValue(this.kernels[execution])
 	dpu_input_arguments_byte_stream.Merge(kernel_word.ToByteStream())
 
-	t_count_word := new(word.Word)
-	t_count_word.Init(64)
-	t_count_word.SetValue(this.t_counts[dpu_id][execution])
-	dpu_input_arguments_byte_stream.Merge(t_count_word.ToByteStream())
+	// NOTE: the count of the previous DPUs is communicated by the host after the first execution
+	if execution == 0 {
+		t_count_word := new(word.Word)
+		t_count_word.Init(64)
+		t_count_word.SetValue(0)
+		dpu_input_arguments_byte_stream.Merge(t_count_word.ToByteStream())
+	}
 
 	dpu_host := make(map[string]*encoding.ByteStream, 0)
 	dpu_host["DPU_INPUT_ARGUMENTS"] = dpu_input_arguments_byte_stream
@@ -246,24 +234,55 @@ func (this *ScanSsa) OutputDpuMramHeapPointerName(
 	return int64(this.input_size_dpu_round * 8), byte_stream
 }
 
+// NOTE: the host adds up the counts that the last tasklet of every previous DPU reports into the
+// count that the second execution of a DPU starts from
+func (this *ScanSsa) Communications(execution int) []*communication.Communication {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	}
+
+	communications := make([]*communication.Communication, 0)
+
+	if execution == 0 {
+		dpu_ids := make([]int, 0)
+		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+			dpu_ids = append(dpu_ids, dpu_id)
+		}
+
+		scan := new(communication.Communication)
+		scan.InitScan(
+			execution,
+			dpu_ids,
+			"DPU_RESULTS",
+			8*int64(this.num_tasklets-1),
+			dpu_ids,
+			"DPU_INPUT_ARGUMENTS",
+			8,
+			8,
+		)
+
+		communications = append(communications, scan)
+	}
+
+	return communications
+}
+
 func (this *ScanSsa) NumExecutions() int {
 	return this.num_executions
 }
 
-func (this *ScanSsa) Sum1D(s []int64) int64 {
-	sum := int64(0)
-	for _, element := range s {
-		sum += element
+func (this *ScanSsa) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "int64",
+		"DPU_RESULTS":                "int64",
 	}
-	return sum
 }
 
-func (this *ScanSsa) Sum2D(s [][]int64) int64 {
+func (this *ScanSsa) Sum1D(s []int64) int64 {
 	sum := int64(0)
-	for _, elements := range s {
-		for _, element := range elements {
-			sum += element
-		}
+	for _, element := range s {
+		sum += element
 	}
 	return sum
 }
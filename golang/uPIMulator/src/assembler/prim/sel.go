@@ -3,6 +3,7 @@ package prim
 import (
 	"errors"
 	"math"
+	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -13,6 +14,8 @@ type Sel struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	buffer_a             []int64
 	buffer_c             [][]int64
 	input_size_dpu_round int64
@@ -30,6 +33,8 @@ func (this *Sel) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	size := int64(command_line_parser.DataPrepParams()[0])
@@ -38,7 +43,7 @@ func (this *Sel) Init(command_line_parser *misc.CommandLineParser) {
 
 	regs := int64(128)
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 
 	var input_size int64
 	if is_strong_scaling {
@@ -59,7 +64,7 @@ func (this *Sel) Init(command_line_parser *misc.CommandLineParser) {
 	for i := int64(0); i < this.input_size_dpu_round*int64(this.num_dpus); i++ {
 		var a int64
 		if i < input_size {
-			a = i + 1
+			a = this.random.Int63n(input_size) + 1
 		} else {
 			a = 0
 		}
@@ -224,3 +229,10 @@ func (this *Sel) OutputDpuMramHeapPointerName(
 func (this *Sel) NumExecutions() int {
 	return this.num_executions
 }
+
+func (this *Sel) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "uint64",
+		"DPU_RESULTS":                "uint32",
+	}
+}
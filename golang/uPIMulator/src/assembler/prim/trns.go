@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
+	"uPIMulator/src/assembler/communication"
 	"uPIMulator/src/misc"
 )
 
@@ -13,6 +14,8 @@ type Trns struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	num_active_dpus_at_begining int64
 	n                           int64
 	M                           int64
@@ -34,6 +37,8 @@ func (this *Trns) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	//NOTE(dongjae.lee@kaist.ac.kr): different parameter given if single dpu is simulated
 	if num_channels == 1 && num_ranks_per_channel == 1 && num_dpus_per_rank == 1 {
 		this.N = 1
@@ -47,7 +52,7 @@ func (this *Trns) Init(command_line_parser *misc.CommandLineParser) {
 		this.m = 4
 	}
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 	if !is_strong_scaling {
 		this.N *= int64(this.num_dpus)
 	}
@@ -72,7 +77,7 @@ func (this *Trns) Init(command_line_parser *misc.CommandLineParser) {
 			this.buffer_a[i] = append(this.buffer_a[i], make([]int64, 0))
 
 			for k := int64(0); k < this.n; k++ {
-				this.buffer_a[i][j] = append(this.buffer_a[i][j], int64(rand.Intn(100)))
+				this.buffer_a[i][j] = append(this.buffer_a[i][j], int64(this.random.Intn(100)))
 			}
 		}
 	}
@@ -211,10 +216,41 @@ func (this *Trns) OutputDpuMramHeapPointerName(
 	return 0, byte_stream
 }
 
+// NOTE: get_tile hands out tiles from the WRAM global curr_tile, which only a reload resets to
+// zero, so the program is loaded again in between every two executions
+func (this *Trns) Communications(execution int) []*communication.Communication {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	}
+
+	communications := make([]*communication.Communication, 0)
+
+	if execution+1 < this.num_executions {
+		dpu_ids := make([]int, 0)
+		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+			dpu_ids = append(dpu_ids, dpu_id)
+		}
+
+		load := new(communication.Communication)
+		load.InitLoad(execution, dpu_ids)
+
+		communications = append(communications, load)
+	}
+
+	return communications
+}
+
 func (this *Trns) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Trns) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "int64",
+	}
+}
+
 func (this *Trns) Transpose(s [][]int64) [][]int64 {
 	xl := len(s[0])
 	yl := len(s)
@@ -3,6 +3,7 @@ package prim
 import (
 	"errors"
 	"math"
+	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -13,6 +14,8 @@ type Ts struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	ts_size        int64
 	query_length   int64
 	query_mean     int64
@@ -44,23 +47,30 @@ func (this *Ts) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	this.ts_size = int64(command_line_parser.DataPrepParams()[0])
 	this.query_length = 64
 
+	is_strong_scaling := command_line_parser.IsStrongScaling()
+	if !is_strong_scaling {
+		this.ts_size *= int64(this.num_dpus)
+	}
+
 	if this.ts_size%(int64(this.num_dpus)*int64(this.num_tasklets)*this.query_length) != 0 {
 		this.ts_size += int64(this.num_dpus)*int64(this.num_tasklets)*this.query_length - this.ts_size%(int64(this.num_dpus)*int64(this.num_tasklets)*this.query_length)
 	}
 
 	this.t_series_buffer = make([]int64, 0)
 	for i := int64(0); i < this.ts_size; i++ {
-		this.t_series_buffer = append(this.t_series_buffer, i%127)
+		this.t_series_buffer = append(this.t_series_buffer, this.random.Int63n(127))
 	}
 
 	this.query_buffer = make([]int64, 0)
 	for i := int64(0); i < this.query_length; i++ {
-		this.query_buffer = append(this.query_buffer, i%127)
+		this.query_buffer = append(this.query_buffer, this.random.Int63n(127))
 	}
 
 	this.asigma_buffer = make([]int64, 0)
@@ -441,6 +451,12 @@ func (this *Ts) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Ts) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_RESULTS": "int32",
+	}
+}
+
 func (this *Ts) DotProduct(a []int64, a_aux []int64, query []int64, result []int64) []int64 {
 	for i := int64(0); i < this.block_size/this.elem_size; i++ {
 		for j := int64(0); j < this.dotpip; j++ {
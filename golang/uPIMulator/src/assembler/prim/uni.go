@@ -3,6 +3,7 @@ package prim
 import (
 	"errors"
 	"math"
+	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -13,10 +14,12 @@ type Uni struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	buffer_a             []int64
 	buffer_c             [][]int64
 	input_size_dpu_round int64
-	pos                  int64
+	pos                  []int64
 	input_sizes_dpu      []int64
 	kernels              []int64
 	t_counts             [][]int64
@@ -32,6 +35,8 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	size := int64(command_line_parser.DataPrepParams()[0])
@@ -40,7 +45,7 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 
 	regs := int64(128)
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 
 	var input_size int64
 	if is_strong_scaling {
@@ -61,10 +66,10 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 	for i := int64(0); i < this.input_size_dpu_round*int64(this.num_dpus); i++ {
 		var a int64
 		if i < input_size {
-			if i%2 == 0 {
-				a = i
+			if i > 0 && this.random.Intn(2) == 0 {
+				a = this.buffer_a[i-1]
 			} else {
-				a = i + 1
+				a = this.random.Int63n(input_size) + 1
 			}
 		} else {
 			a = this.buffer_a[input_size-1]
@@ -82,18 +87,21 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 		}
 	}
 
+	this.pos = make([]int64, 0)
 	for i := 0; i < this.num_dpus; i++ {
 		start_elem := this.input_size_dpu_round * int64(i)
 
 		this.buffer_c[i][0] = this.buffer_a[start_elem]
 
-		this.pos = 1
+		pos := int64(1)
 		for j := int64(1); j < this.input_size_dpu_round; j++ {
 			if this.buffer_a[start_elem+j] != this.buffer_a[start_elem+j-1] {
-				this.buffer_c[i][this.pos] = this.buffer_a[start_elem+j]
-				this.pos++
+				this.buffer_c[i][pos] = this.buffer_a[start_elem+j]
+				pos++
 			}
 		}
+
+		this.pos = append(this.pos, pos)
 	}
 
 	this.input_sizes_dpu = make([]int64, 0)
@@ -126,9 +134,9 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 				first = this.buffer_a[start_elem]
 				last = 0
 			} else if j == this.num_tasklets-1 {
-				t_count = this.pos
+				t_count = this.pos[i]
 				first = 0
-				last = this.buffer_c[i][this.pos-1]
+				last = this.buffer_c[i][this.pos[i]-1]
 			} else {
 				t_count = 0
 				first = 0
@@ -272,3 +280,10 @@ func (this *Uni) OutputDpuMramHeapPointerName(
 func (this *Uni) NumExecutions() int {
 	return this.num_executions
 }
+
+func (this *Uni) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "int64",
+		"DPU_RESULTS":                "int64",
+	}
+}
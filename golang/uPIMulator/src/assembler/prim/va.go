@@ -14,6 +14,8 @@ type Va struct {
 	num_tasklets   int
 	num_executions int
 
+	random *rand.Rand
+
 	input_size_dpu_8bytes int64
 	buffer_a              []int64
 	buffer_b              []int64
@@ -31,13 +33,15 @@ func (this *Va) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+
 	this.num_executions = 1
 
 	buffer_size := int64(command_line_parser.DataPrepParams()[0])
 
 	elem_size := int64(4)
 
-	is_strong_scaling := true
+	is_strong_scaling := command_line_parser.IsStrongScaling()
 
 	var input_size int64
 	if is_strong_scaling {
@@ -65,8 +69,8 @@ func (this *Va) Init(command_line_parser *misc.CommandLineParser) {
 	this.buffer_b = make([]int64, 0)
 	this.buffer_c = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_8bytes*int64(this.num_dpus); i++ {
-		a := int64(rand.Intn(this.Pow2(31)))
-		b := int64(rand.Intn(this.Pow2(31)))
+		a := int64(this.random.Intn(this.Pow2(31)))
+		b := int64(this.random.Intn(this.Pow2(31)))
 
 		c := a + b
 
@@ -203,6 +207,12 @@ func (this *Va) NumExecutions() int {
 	return this.num_executions
 }
 
+func (this *Va) ElementTypes() map[string]string {
+	return map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME": "int32",
+	}
+}
+
 func (this *Va) Pow2(exponent int) int {
 	if exponent < 0 {
 		err := errors.New("exponent < 0")
@@ -0,0 +1,62 @@
+package assembler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Assemblables register a constructor under their benchmark name, typically from an init function
+// of the package that implements them, so that new benchmarks do not require editing the assembler.
+var registry_mutex sync.Mutex
+var registry = make(map[string]func() Assemblable, 0)
+
+func Register(name string, constructor func() Assemblable) {
+	registry_mutex.Lock()
+	defer registry_mutex.Unlock()
+
+	if _, found := registry[name]; found {
+		err_msg := fmt.Sprintf("assemblable (%s) is already registered", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	registry[name] = constructor
+}
+
+func IsRegistered(name string) bool {
+	registry_mutex.Lock()
+	defer registry_mutex.Unlock()
+
+	_, found := registry[name]
+	return found
+}
+
+func Registered() []string {
+	registry_mutex.Lock()
+	defer registry_mutex.Unlock()
+
+	names := make([]string, 0)
+	for name, _ := range registry {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+func NewAssemblable(name string) Assemblable {
+	registry_mutex.Lock()
+	defer registry_mutex.Unlock()
+
+	constructor, found := registry[name]
+
+	if !found {
+		err_msg := fmt.Sprintf("assemblable (%s) is not registered", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return constructor()
+}
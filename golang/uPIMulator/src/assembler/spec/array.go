@@ -0,0 +1,165 @@
+package spec
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"uPIMulator/src/abi/encoding"
+)
+
+// Array holds the per-DPU elements of a symbol. Integer element types are kept as int64 (uint64
+// as its bits) and floating-point element types as float64. Float elements are rounded to float32
+// whenever they are stored, and derived outputs round them after each step, so that the expected
+// values match the float32 arithmetic of the DPUs.
+type Array struct {
+	element_type string
+
+	ints   []int64
+	floats []float64
+}
+
+func (this *Array) Init(element_type string) {
+	ElementSize(element_type)
+
+	this.element_type = element_type
+	this.ints = make([]int64, 0)
+	this.floats = make([]float64, 0)
+}
+
+func (this *Array) ElementType() string {
+	return this.element_type
+}
+
+func (this *Array) IsFloat() bool {
+	return this.element_type == "float" || this.element_type == "double"
+}
+
+func (this *Array) Size() int {
+	if this.IsFloat() {
+		return len(this.floats)
+	} else {
+		return len(this.ints)
+	}
+}
+
+func (this *Array) Int(pos int) int64 {
+	if this.IsFloat() {
+		return int64(this.floats[pos])
+	} else {
+		return this.ints[pos]
+	}
+}
+
+func (this *Array) Float(pos int) float64 {
+	if this.IsFloat() {
+		return this.floats[pos]
+	} else if this.element_type == "uint64" {
+		return float64(uint64(this.ints[pos]))
+	} else {
+		return float64(this.ints[pos])
+	}
+}
+
+func (this *Array) AppendInt(value int64) {
+	if this.IsFloat() {
+		this.floats = append(this.floats, float64(value))
+	} else {
+		this.ints = append(this.ints, this.Wrap(value))
+	}
+}
+
+func (this *Array) AppendFloat(value float64) {
+	if this.IsFloat() {
+		this.floats = append(this.floats, this.Round(value))
+	} else {
+		this.ints = append(this.ints, this.Wrap(int64(value)))
+	}
+}
+
+// NOTE: integers are parsed as integers first, so that values above 2^53 are not rounded
+func (this *Array) AppendNumber(number string) {
+	if number == "" {
+		this.AppendInt(0)
+	} else if int_value, int_err := strconv.ParseInt(number, 10, 64); int_err == nil {
+		this.AppendInt(int_value)
+	} else if uint_value, uint_err := strconv.ParseUint(number, 10, 64); uint_err == nil {
+		if this.IsFloat() {
+			this.AppendFloat(float64(uint_value))
+		} else {
+			this.AppendInt(int64(uint_value))
+		}
+	} else if float_value, float_err := strconv.ParseFloat(number, 64); float_err == nil {
+		this.AppendFloat(float_value)
+	} else {
+		panic(float_err)
+	}
+}
+
+func (this *Array) AppendElement(array *Array, pos int) {
+	if array.IsFloat() || this.IsFloat() {
+		this.AppendFloat(array.Float(pos))
+	} else {
+		this.AppendInt(array.Int(pos))
+	}
+}
+
+func (this *Array) Less(int_value_1 int64, int_value_2 int64) bool {
+	if this.element_type == "uint64" {
+		return uint64(int_value_1) < uint64(int_value_2)
+	} else {
+		return int_value_1 < int_value_2
+	}
+}
+
+func (this *Array) Round(value float64) float64 {
+	if this.element_type == "float" {
+		return float64(float32(value))
+	} else {
+		return value
+	}
+}
+
+func (this *Array) Wrap(value int64) int64 {
+	if this.element_type == "int32" {
+		return int64(int32(value))
+	} else if this.element_type == "uint32" {
+		return int64(uint32(value))
+	} else {
+		return value
+	}
+}
+
+func (this *Array) ToByteStream() *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	element_size := ElementSize(this.element_type)
+
+	for i := 0; i < this.Size(); i++ {
+		var bits uint64
+		if this.element_type == "float" {
+			bits = uint64(math.Float32bits(float32(this.floats[i])))
+		} else if this.element_type == "double" {
+			bits = math.Float64bits(this.floats[i])
+		} else {
+			bits = uint64(this.ints[i])
+		}
+
+		for j := int64(0); j < element_size; j++ {
+			byte_stream.Append(uint8(bits >> (8 * j)))
+		}
+	}
+
+	return byte_stream
+}
+
+func ElementSize(element_type string) int64 {
+	if element_type == "int32" || element_type == "uint32" || element_type == "float" {
+		return 4
+	} else if element_type == "int64" || element_type == "uint64" || element_type == "double" {
+		return 8
+	} else {
+		err := errors.New("element type is not int32, uint32, int64, uint64, float, or double")
+		panic(err)
+	}
+}
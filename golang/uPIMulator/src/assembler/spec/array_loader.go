@@ -0,0 +1,210 @@
+package spec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ArrayLoader reads a flat, row-major array of numbers from a NumPy .npy file or a CSV file.
+// Integer files are loaded as int64 (or uint64) arrays and the rest as double arrays.
+type ArrayLoader struct {
+	path string
+}
+
+func (this *ArrayLoader) Init(path string) {
+	this.path = path
+}
+
+func (this *ArrayLoader) Load() *Array {
+	extension := strings.ToLower(filepath.Ext(this.path))
+
+	if extension == ".npy" {
+		return this.LoadNpy()
+	} else if extension == ".csv" || extension == ".txt" {
+		return this.LoadCsv()
+	} else {
+		err_msg := fmt.Sprintf("array file (%s) is neither .npy nor .csv", this.path)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *ArrayLoader) LoadCsv() *Array {
+	bytes, read_err := os.ReadFile(this.path)
+
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	fields := make([]string, 0)
+	is_int := true
+	is_uint := true
+
+	for _, line := range strings.Split(string(bytes), "\n") {
+		for _, field := range strings.Split(line, ",") {
+			field = strings.TrimSpace(field)
+
+			if field == "" {
+				continue
+			}
+
+			if _, int_err := strconv.ParseInt(field, 10, 64); int_err != nil {
+				is_int = false
+			}
+
+			if _, uint_err := strconv.ParseUint(field, 10, 64); uint_err != nil {
+				is_uint = false
+			}
+
+			fields = append(fields, field)
+		}
+	}
+
+	array := new(Array)
+	if is_int {
+		array.Init("int64")
+	} else if is_uint {
+		array.Init("uint64")
+	} else {
+		array.Init("double")
+	}
+
+	for _, field := range fields {
+		array.AppendNumber(field)
+	}
+
+	return array
+}
+
+func (this *ArrayLoader) LoadNpy() *Array {
+	bytes, read_err := os.ReadFile(this.path)
+
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	if len(bytes) < 10 || string(bytes[0:6]) != "\x93NUMPY" {
+		err_msg := fmt.Sprintf("array file (%s) is not a .npy file", this.path)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	var header_length int
+	var header_begin int
+	if bytes[6] == 1 {
+		header_length = int(binary.LittleEndian.Uint16(bytes[8:10]))
+		header_begin = 10
+	} else if (bytes[6] == 2 || bytes[6] == 3) && len(bytes) >= 12 {
+		header_length = int(binary.LittleEndian.Uint32(bytes[8:12]))
+		header_begin = 12
+	} else {
+		err := errors.New(".npy version is not supported")
+		panic(err)
+	}
+
+	if header_begin+header_length > len(bytes) {
+		err := errors.New(".npy header is truncated")
+		panic(err)
+	}
+
+	header := string(bytes[header_begin : header_begin+header_length])
+	data := bytes[header_begin+header_length:]
+
+	if strings.Contains(strings.ReplaceAll(header, " ", ""), "'fortran_order':True") {
+		err := errors.New(".npy in Fortran order is not supported")
+		panic(err)
+	}
+
+	descr := this.NpyHeaderValue(header, "descr")
+	descr = strings.Trim(descr, "'\"")
+
+	if len(descr) < 3 {
+		err_msg := fmt.Sprintf(".npy descr (%s) is not valid", descr)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	if descr[0] == '>' {
+		err := errors.New("big-endian .npy is not supported")
+		panic(err)
+	}
+
+	kind := descr[1]
+	size, size_err := strconv.Atoi(descr[2:])
+
+	if size_err != nil {
+		panic(size_err)
+	}
+
+	if len(data)%size != 0 {
+		err := errors.New(".npy data size is not a multiple of the element size")
+		panic(err)
+	}
+
+	array := new(Array)
+	if kind == 'f' {
+		array.Init("double")
+	} else if kind == 'u' && size == 8 {
+		array.Init("uint64")
+	} else {
+		array.Init("int64")
+	}
+
+	for i := 0; i < len(data); i += size {
+		element := data[i : i+size]
+
+		if kind == 'i' && size == 1 {
+			array.AppendInt(int64(int8(element[0])))
+		} else if kind == 'i' && size == 2 {
+			array.AppendInt(int64(int16(binary.LittleEndian.Uint16(element))))
+		} else if kind == 'i' && size == 4 {
+			array.AppendInt(int64(int32(binary.LittleEndian.Uint32(element))))
+		} else if kind == 'i' && size == 8 {
+			array.AppendInt(int64(binary.LittleEndian.Uint64(element)))
+		} else if (kind == 'u' || kind == 'b') && size == 1 {
+			array.AppendInt(int64(element[0]))
+		} else if kind == 'u' && size == 2 {
+			array.AppendInt(int64(binary.LittleEndian.Uint16(element)))
+		} else if kind == 'u' && size == 4 {
+			array.AppendInt(int64(binary.LittleEndian.Uint32(element)))
+		} else if kind == 'u' && size == 8 {
+			array.AppendInt(int64(binary.LittleEndian.Uint64(element)))
+		} else if kind == 'f' && size == 4 {
+			array.AppendFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(element))))
+		} else if kind == 'f' && size == 8 {
+			array.AppendFloat(math.Float64frombits(binary.LittleEndian.Uint64(element)))
+		} else {
+			err_msg := fmt.Sprintf(".npy descr (%s) is not supported", descr)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+
+	return array
+}
+
+func (this *ArrayLoader) NpyHeaderValue(header string, key string) string {
+	pos := strings.Index(header, "'"+key+"'")
+
+	if pos == -1 {
+		err_msg := fmt.Sprintf(".npy header does not have %s", key)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	rest := header[pos+len(key)+2:]
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ":"))
+
+	end := strings.IndexAny(rest, ",}")
+	if end == -1 {
+		end = len(rest)
+	}
+
+	return strings.TrimSpace(rest[:end])
+}
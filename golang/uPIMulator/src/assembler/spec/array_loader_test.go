@@ -0,0 +1,182 @@
+package spec
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func isPanicking(function func()) (is_panicking bool) {
+	defer func() {
+		if recover() != nil {
+			is_panicking = true
+		}
+	}()
+
+	function()
+	return false
+}
+
+func writeFile(t *testing.T, name string, bytes []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, bytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// NOTE: version 1 has a 2-byte header length and versions 2 and 3 have a 4-byte one
+func npyBytes(version byte, descr string, data []byte) []byte {
+	return npyBytesInOrder(version, descr, "False", data)
+}
+
+func npyBytesInOrder(version byte, descr string, fortran_order string, data []byte) []byte {
+	header := "{'descr': '" + descr + "', 'fortran_order': " + fortran_order + ", 'shape': (4,), }"
+
+	header_begin := 10
+	if version != 1 {
+		header_begin = 12
+	}
+
+	for (header_begin+len(header)+1)%64 != 0 {
+		header += " "
+	}
+	header += "\n"
+
+	bytes := append([]byte("\x93NUMPY"), version, 0)
+	if version == 1 {
+		bytes = binary.LittleEndian.AppendUint16(bytes, uint16(len(header)))
+	} else {
+		bytes = binary.LittleEndian.AppendUint32(bytes, uint32(len(header)))
+	}
+
+	bytes = append(bytes, header...)
+	return append(bytes, data...)
+}
+
+func loadArray(path string) *Array {
+	array_loader := new(ArrayLoader)
+	array_loader.Init(path)
+	return array_loader.Load()
+}
+
+func TestLoadNpyVersions(t *testing.T) {
+	data := make([]byte, 0)
+	for _, value := range []int32{-1, 0, 7, math.MaxInt32} {
+		data = binary.LittleEndian.AppendUint32(data, uint32(value))
+	}
+
+	for _, version := range []byte{1, 2, 3} {
+		array := loadArray(writeFile(t, "a.npy", npyBytes(version, "<i4", data)))
+
+		if array.ElementType() != "int64" || array.Size() != 4 {
+			t.Errorf("version %d: %d %s elements", version, array.Size(), array.ElementType())
+			continue
+		}
+
+		for i, expected := range []int64{-1, 0, 7, math.MaxInt32} {
+			if array.Int(i) != expected {
+				t.Errorf("version %d: [%d] is %d, but %d is expected", version, i, array.Int(i), expected)
+			}
+		}
+	}
+}
+
+func TestLoadNpyKeepsIntegers(t *testing.T) {
+	int64s := []int64{math.MaxInt64, math.MinInt64, 1<<53 + 1, -(1<<53 + 1)}
+	uint64s := []uint64{math.MaxUint64, 1<<63 + 1, 1<<53 + 1, 0}
+
+	int_data := make([]byte, 0)
+	uint_data := make([]byte, 0)
+	for i := range int64s {
+		int_data = binary.LittleEndian.AppendUint64(int_data, uint64(int64s[i]))
+		uint_data = binary.LittleEndian.AppendUint64(uint_data, uint64s[i])
+	}
+
+	int_array := loadArray(writeFile(t, "i.npy", npyBytes(1, "<i8", int_data)))
+	uint_array := loadArray(writeFile(t, "u.npy", npyBytes(1, "<u8", uint_data)))
+
+	if uint_array.ElementType() != "uint64" {
+		t.Errorf("<u8 is loaded as %s", uint_array.ElementType())
+	}
+
+	for i := range int64s {
+		if int_array.Int(i) != int64s[i] {
+			t.Errorf("<i8[%d] is %d, but %d is expected", i, int_array.Int(i), int64s[i])
+		} else if uint64(uint_array.Int(i)) != uint64s[i] {
+			t.Errorf("<u8[%d] is %d, but %d is expected", i, uint64(uint_array.Int(i)), uint64s[i])
+		}
+	}
+}
+
+func TestLoadNpyFloats(t *testing.T) {
+	data := make([]byte, 0)
+	for _, value := range []float32{0.5, -2, 3.25, 1e10} {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(value))
+	}
+
+	array := loadArray(writeFile(t, "f.npy", npyBytes(2, "<f4", data)))
+
+	if !array.IsFloat() {
+		t.Fatalf("<f4 is loaded as %s", array.ElementType())
+	}
+
+	for i, expected := range []float64{0.5, -2, 3.25, 1e10} {
+		if array.Float(i) != expected {
+			t.Errorf("[%d] is %g, but %g is expected", i, array.Float(i), expected)
+		}
+	}
+}
+
+func TestLoadInvalidNpy(t *testing.T) {
+	data := make([]byte, 16)
+
+	invalids := map[string][]byte{
+		"bad magic":     append([]byte("\x93NUMPZ"), npyBytes(1, "<i4", data)[6:]...),
+		"version 4":     npyBytes(4, "<i4", data),
+		"big-endian":    npyBytes(1, ">i4", data),
+		"complex":       npyBytes(1, "<c8", data),
+		"truncated":     npyBytes(1, "<i4", data)[:20],
+		"partial data":  npyBytes(1, "<i8", data[:12]),
+		"fortran order": npyBytesInOrder(3, "<i4", "True", data),
+	}
+
+	for name, bytes := range invalids {
+		path := writeFile(t, "a.npy", bytes)
+
+		if !isPanicking(func() { loadArray(path) }) {
+			t.Errorf("%s is accepted", name)
+		}
+	}
+}
+
+func TestLoadCsv(t *testing.T) {
+	int_array := loadArray(writeFile(t, "i.csv", []byte("1, -2,3\n9007199254740993,\n\n4\n")))
+	uint_array := loadArray(writeFile(t, "u.csv", []byte("18446744073709551615,1\n")))
+	float_array := loadArray(writeFile(t, "f.txt", []byte("1,2.5\n-3e2\n")))
+
+	if int_array.ElementType() != "int64" {
+		t.Errorf("integer CSV is loaded as %s", int_array.ElementType())
+	} else if int_array.Size() != 5 || int_array.Int(3) != 9007199254740993 {
+		t.Errorf("integer CSV loses elements or precision")
+	}
+
+	if uint_array.ElementType() != "uint64" || uint64(uint_array.Int(0)) != math.MaxUint64 {
+		t.Errorf("unsigned CSV is not loaded as uint64")
+	}
+
+	if !float_array.IsFloat() || float_array.Size() != 3 || float_array.Float(2) != -300 {
+		t.Errorf("float CSV is not loaded as double")
+	}
+
+	if !isPanicking(func() { loadArray(writeFile(t, "a.csv", []byte("1,x\n"))) }) {
+		t.Errorf("a non-number field is accepted")
+	} else if !isPanicking(func() { loadArray(writeFile(t, "a.bin", []byte("1\n"))) }) {
+		t.Errorf("an unknown extension is accepted")
+	}
+}
@@ -0,0 +1,568 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// Spec is an Assemblable described by a JSON file instead of Go code. An example that adds two
+// int32 vectors placed back to back in the MRAM heap:
+//
+//	{
+//	  "num_executions": 1,
+//	  "symbols": [
+//	    {"name": "DPU_INPUT_ARGUMENTS", "direction": "input", "type": "uint32", "count": 2,
+//	     "generator": {"kind": "values", "values": [4096, 0]}},
+//	    {"id": "a", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 0, "direction": "input",
+//	     "type": "int32", "count": 1024, "generator": {"kind": "random", "min": 0, "max": 100}},
+//	    {"id": "b", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 4096, "direction": "input",
+//	     "type": "int32", "count": 1024, "generator": {"kind": "file", "path": "b.npy"}},
+//	    {"id": "c", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 4096, "direction": "output",
+//	     "type": "int32", "count": 1024, "generator": {"kind": "add", "operands": ["a", "b"]}}
+//	  ]
+//	}
+//
+// Input generators are constant, values, range, random, dpu_id and file (.npy or .csv, sliced
+// per DPU). Output generators may also be add, sub, mul, copy, sum, min and max over the ids of
+// the symbols of the same execution.
+type Spec struct {
+	num_dpus       int
+	num_executions int
+
+	dirpath string
+	symbols []*Symbol
+
+	random *rand.Rand
+	files  map[string]*Array
+
+	// NOTE: arrays[execution][dpu_id][symbol id]
+	arrays [][]map[string]*Array
+}
+
+type Symbol struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Offset     int64      `json:"offset"`
+	Direction  string     `json:"direction"`
+	Type       string     `json:"type"`
+	Count      int        `json:"count"`
+	Executions []int      `json:"executions"`
+	Generator  *Generator `json:"generator"`
+}
+
+// NOTE: numbers are kept as json.Number so that integers above 2^53 survive decoding
+type Generator struct {
+	Kind     string        `json:"kind"`
+	Value    json.Number   `json:"value"`
+	Values   []json.Number `json:"values"`
+	Start    json.Number   `json:"start"`
+	Step     json.Number   `json:"step"`
+	Min      int64         `json:"min"`
+	Max      int64         `json:"max"`
+	Path     string        `json:"path"`
+	Operands []string      `json:"operands"`
+}
+
+type specFile struct {
+	NumExecutions int       `json:"num_executions"`
+	Symbols       []*Symbol `json:"symbols"`
+}
+
+func (this *Spec) Init(command_line_parser *misc.CommandLineParser) {
+	num_channels := int(command_line_parser.IntParameter("num_channels"))
+	num_ranks_per_channel := int(command_line_parser.IntParameter("num_ranks_per_channel"))
+	num_dpus_per_rank := int(command_line_parser.IntParameter("num_dpus_per_rank"))
+
+	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
+
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+	this.files = make(map[string]*Array, 0)
+
+	this.InitSpecFile(command_line_parser.StringParameter("spec_filepath"))
+	this.InitArrays()
+}
+
+func (this *Spec) InitSpecFile(path string) {
+	bytes, read_err := os.ReadFile(path)
+
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	spec_file := new(specFile)
+
+	if unmarshal_err := json.Unmarshal(bytes, spec_file); unmarshal_err != nil {
+		panic(unmarshal_err)
+	}
+
+	if spec_file.NumExecutions <= 0 {
+		err := errors.New("num executions <= 0")
+		panic(err)
+	}
+
+	this.dirpath = filepath.Dir(path)
+	this.num_executions = spec_file.NumExecutions
+	this.symbols = spec_file.Symbols
+
+	ids := make(map[string]bool, 0)
+	for _, symbol := range this.symbols {
+		if symbol.Id == "" {
+			symbol.Id = symbol.Name
+		}
+
+		this.ValidateSymbol(symbol)
+
+		if _, found := ids[symbol.Id]; found {
+			err_msg := fmt.Sprintf("symbol ID (%s) is duplicated", symbol.Id)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		ids[symbol.Id] = true
+	}
+}
+
+func (this *Spec) ValidateSymbol(symbol *Symbol) {
+	if symbol.Name == "" {
+		err := errors.New("symbol name is empty")
+		panic(err)
+	} else if symbol.Direction != "input" && symbol.Direction != "output" {
+		err_msg := fmt.Sprintf("symbol (%s) direction is not input or output", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if symbol.Count <= 0 {
+		err_msg := fmt.Sprintf("symbol (%s) count <= 0", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if symbol.Generator == nil {
+		err_msg := fmt.Sprintf("symbol (%s) has no generator", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if symbol.Offset < 0 {
+		err_msg := fmt.Sprintf("symbol (%s) offset < 0", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	// NOTE: MRAM DMA transfers must be 8-byte aligned
+	if (int64(symbol.Count)*ElementSize(symbol.Type))%8 != 0 {
+		err_msg := fmt.Sprintf("symbol (%s) size is not a multiple of 8 bytes", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if symbol.Offset%8 != 0 {
+		err_msg := fmt.Sprintf("symbol (%s) offset is not a multiple of 8 bytes", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	for _, execution := range symbol.Executions {
+		if execution < 0 || execution >= this.num_executions {
+			err_msg := fmt.Sprintf("symbol (%s) execution is out of range", symbol.Id)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+}
+
+func (this *Spec) InitArrays() {
+	this.arrays = make([][]map[string]*Array, 0)
+
+	for execution := 0; execution < this.num_executions; execution++ {
+		this.arrays = append(this.arrays, make([]map[string]*Array, 0))
+
+		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+			this.arrays[execution] = append(this.arrays[execution], make(map[string]*Array, 0))
+		}
+
+		for _, symbol := range this.symbols {
+			if symbol.Direction == "input" && this.IsActive(symbol, execution) {
+				for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+					this.arrays[execution][dpu_id][symbol.Id] = this.Generate(symbol, execution, dpu_id)
+				}
+			}
+		}
+
+		for _, symbol := range this.symbols {
+			if symbol.Direction == "output" && this.IsActive(symbol, execution) {
+				for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+					this.arrays[execution][dpu_id][symbol.Id] = this.Generate(symbol, execution, dpu_id)
+				}
+			}
+		}
+	}
+}
+
+func (this *Spec) IsActive(symbol *Symbol, execution int) bool {
+	return len(symbol.Executions) == 0 || slices.Contains(symbol.Executions, execution)
+}
+
+func (this *Spec) Generate(symbol *Symbol, execution int, dpu_id int) *Array {
+	generator := symbol.Generator
+
+	array := new(Array)
+	array.Init(symbol.Type)
+
+	if generator.Kind == "constant" {
+		for i := 0; i < symbol.Count; i++ {
+			array.AppendNumber(generator.Value.String())
+		}
+	} else if generator.Kind == "values" {
+		if len(generator.Values) != symbol.Count {
+			err_msg := fmt.Sprintf("symbol (%s) values' length != count", symbol.Id)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		for _, value := range generator.Values {
+			array.AppendNumber(value.String())
+		}
+	} else if generator.Kind == "range" {
+		int_start, int_start_err := this.ParseInt(generator.Start)
+		int_step, int_step_err := this.ParseInt(generator.Step)
+		float_start := this.ParseFloat(generator.Start)
+		float_step := this.ParseFloat(generator.Step)
+
+		for i := 0; i < symbol.Count; i++ {
+			pos := dpu_id*symbol.Count + i
+
+			if int_start_err == nil && int_step_err == nil && !array.IsFloat() {
+				array.AppendInt(int_start + int_step*int64(pos))
+			} else {
+				array.AppendFloat(float_start + float_step*float64(pos))
+			}
+		}
+	} else if generator.Kind == "random" {
+		if generator.Max <= generator.Min {
+			err_msg := fmt.Sprintf("symbol (%s) random max <= min", symbol.Id)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		for i := 0; i < symbol.Count; i++ {
+			array.AppendInt(generator.Min + this.random.Int63n(generator.Max-generator.Min))
+		}
+	} else if generator.Kind == "dpu_id" {
+		for i := 0; i < symbol.Count; i++ {
+			array.AppendInt(int64(dpu_id))
+		}
+	} else if generator.Kind == "file" {
+		values := this.LoadFile(generator.Path)
+
+		begin := dpu_id * symbol.Count
+		end := begin + symbol.Count
+
+		if end > values.Size() {
+			err_msg := fmt.Sprintf(
+				"symbol (%s) file has %d elements but DPU %d needs elements up to %d",
+				symbol.Id,
+				values.Size(),
+				dpu_id,
+				end,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		for pos := begin; pos < end; pos++ {
+			array.AppendElement(values, pos)
+		}
+	} else if symbol.Direction == "output" {
+		this.GenerateDerived(symbol, array, this.Operands(symbol, execution, dpu_id))
+	} else {
+		err_msg := fmt.Sprintf("symbol (%s) generator (%s) is not valid", symbol.Id, generator.Kind)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return array
+}
+
+func (this *Spec) Operands(symbol *Symbol, execution int, dpu_id int) []*Array {
+	if len(symbol.Generator.Operands) == 0 {
+		err_msg := fmt.Sprintf("symbol (%s) has no operands", symbol.Id)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	operands := make([]*Array, 0)
+	for _, id := range symbol.Generator.Operands {
+		operand, found := this.arrays[execution][dpu_id][id]
+
+		if !found {
+			err_msg := fmt.Sprintf(
+				"symbol (%s) operand (%s) is not an input of execution %d",
+				symbol.Id,
+				id,
+				execution,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		operands = append(operands, operand)
+	}
+
+	return operands
+}
+
+func (this *Spec) GenerateDerived(symbol *Symbol, array *Array, operands []*Array) {
+	kind := symbol.Generator.Kind
+	is_float := array.IsFloat()
+
+	if kind == "add" || kind == "sub" || kind == "mul" || kind == "copy" {
+		for _, operand := range operands {
+			if operand.Size() != symbol.Count {
+				err_msg := fmt.Sprintf("symbol (%s) operand's count != count", symbol.Id)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+		}
+
+		for i := 0; i < symbol.Count; i++ {
+			int_value := operands[0].Int(i)
+			float_value := operands[0].Float(i)
+
+			for _, operand := range operands[1:] {
+				if kind == "add" {
+					int_value += operand.Int(i)
+					float_value += operand.Float(i)
+				} else if kind == "sub" {
+					int_value -= operand.Int(i)
+					float_value -= operand.Float(i)
+				} else if kind == "mul" {
+					int_value *= operand.Int(i)
+					float_value *= operand.Float(i)
+				}
+
+				float_value = array.Round(float_value)
+			}
+
+			if is_float {
+				array.AppendFloat(float_value)
+			} else {
+				array.AppendInt(int_value)
+			}
+		}
+	} else if kind == "sum" || kind == "min" || kind == "max" {
+		if len(operands) != 1 {
+			err_msg := fmt.Sprintf("symbol (%s) %s has more than one operand", symbol.Id, kind)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		operand := operands[0]
+
+		int_value := operand.Int(0)
+		float_value := operand.Float(0)
+
+		for i := 1; i < operand.Size(); i++ {
+			if kind == "sum" {
+				int_value += operand.Int(i)
+				float_value = array.Round(float_value + operand.Float(i))
+			} else if kind == "min" {
+				if operand.Less(operand.Int(i), int_value) {
+					int_value = operand.Int(i)
+				}
+				float_value = min(float_value, operand.Float(i))
+			} else {
+				if operand.Less(int_value, operand.Int(i)) {
+					int_value = operand.Int(i)
+				}
+				float_value = max(float_value, operand.Float(i))
+			}
+		}
+
+		// NOTE: the reduced value is stored in the first element and the rest are zero-padded
+		for i := 0; i < symbol.Count; i++ {
+			if i != 0 {
+				array.AppendInt(0)
+			} else if is_float {
+				array.AppendFloat(float_value)
+			} else {
+				array.AppendInt(int_value)
+			}
+		}
+	} else {
+		err_msg := fmt.Sprintf("symbol (%s) generator (%s) is not valid", symbol.Id, kind)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *Spec) ParseInt(number json.Number) (int64, error) {
+	if number == "" {
+		return 0, nil
+	}
+
+	return number.Int64()
+}
+
+func (this *Spec) ParseFloat(number json.Number) float64 {
+	if number == "" {
+		return 0
+	}
+
+	value, err := number.Float64()
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+func (this *Spec) LoadFile(path string) *Array {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(this.dirpath, path)
+	}
+
+	if values, found := this.files[path]; found {
+		return values
+	}
+
+	array_loader := new(ArrayLoader)
+	array_loader.Init(path)
+
+	this.files[path] = array_loader.Load()
+	return this.files[path]
+}
+
+func (this *Spec) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	return this.DpuHost("input", execution, dpu_id)
+}
+
+func (this *Spec) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	return this.DpuHost("output", execution, dpu_id)
+}
+
+func (this *Spec) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	return this.DpuMramHeapPointerName("input", execution, dpu_id)
+}
+
+func (this *Spec) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	return this.DpuMramHeapPointerName("output", execution, dpu_id)
+}
+
+func (this *Spec) NumExecutions() int {
+	return this.num_executions
+}
+
+// NOTE: element types are keyed by symbol name and offset, e.g. "DPU_MRAM_HEAP_POINTER_NAME+4096",
+// since the heap packs symbols of different types, and the outputs, which are the ones verified,
+// take precedence over the inputs at the same offset
+func (this *Spec) ElementTypes() map[string]string {
+	element_types := make(map[string]string, 0)
+
+	for _, direction := range []string{"output", "input"} {
+		for _, symbol := range this.symbols {
+			key := fmt.Sprintf("%s+%d", symbol.Name, symbol.Offset)
+
+			if _, found := element_types[key]; found || symbol.Direction != direction {
+				continue
+			}
+
+			element_types[key] = symbol.Type
+		}
+	}
+
+	return element_types
+}
+
+func (this *Spec) DpuHost(
+	direction string,
+	execution int,
+	dpu_id int,
+) map[string]*encoding.ByteStream {
+	this.ValidateExecutionDpuId(execution, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+
+	for _, symbol := range this.symbols {
+		if symbol.Direction == direction &&
+			symbol.Name != "DPU_MRAM_HEAP_POINTER_NAME" &&
+			this.IsActive(symbol, execution) {
+			if _, found := dpu_host[symbol.Name]; found {
+				err_msg := fmt.Sprintf(
+					"symbol (%s) is transferred twice in execution %d",
+					symbol.Name,
+					execution,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			dpu_host[symbol.Name] = this.arrays[execution][dpu_id][symbol.Id].ToByteStream()
+		}
+	}
+
+	return dpu_host
+}
+
+// NOTE: every heap symbol of the same direction is packed into a single region that starts at the
+// smallest offset, and the gaps between them are zero-filled
+func (this *Spec) DpuMramHeapPointerName(
+	direction string,
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.ValidateExecutionDpuId(execution, dpu_id)
+
+	symbols := make([]*Symbol, 0)
+	for _, symbol := range this.symbols {
+		if symbol.Direction == direction &&
+			symbol.Name == "DPU_MRAM_HEAP_POINTER_NAME" &&
+			this.IsActive(symbol, execution) {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	if len(symbols) == 0 {
+		return 0, byte_stream
+	}
+
+	slices.SortStableFunc(symbols, func(symbol_1 *Symbol, symbol_2 *Symbol) int {
+		return int(symbol_1.Offset - symbol_2.Offset)
+	})
+
+	offset := symbols[0].Offset
+	for _, symbol := range symbols {
+		if symbol.Offset < offset+byte_stream.Size() {
+			err_msg := fmt.Sprintf("heap symbol (%s) overlaps the previous heap symbol", symbol.Id)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		for offset+byte_stream.Size() < symbol.Offset {
+			byte_stream.Append(0)
+		}
+
+		byte_stream.Merge(this.arrays[execution][dpu_id][symbol.Id].ToByteStream())
+	}
+
+	return offset, byte_stream
+}
+
+func (this *Spec) ValidateExecutionDpuId(execution int, dpu_id int) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+}
@@ -0,0 +1,330 @@
+package spec
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+func newSpec(t *testing.T, num_dpus int, json string) *Spec {
+	t.Helper()
+
+	path := writeFile(t, "spec.json", []byte(json))
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+
+	command_line_parser.AddOption(misc.INT, "num_channels", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_ranks_per_channel", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_dpus_per_rank", strconv.Itoa(num_dpus), "")
+	command_line_parser.AddOption(misc.INT, "seed", "0", "")
+	command_line_parser.AddOption(misc.STRING, "spec_filepath", path, "")
+
+	spec := new(Spec)
+	spec.Init(command_line_parser)
+	return spec
+}
+
+func decode(byte_stream *encoding.ByteStream, element_size int) []int64 {
+	elements := make([]int64, 0)
+
+	for i := 0; i < int(byte_stream.Size()); i += element_size {
+		bits := uint64(0)
+		for j := 0; j < element_size; j++ {
+			bits |= uint64(byte_stream.Get(i+j)) << (8 * j)
+		}
+
+		elements = append(elements, int64(bits))
+	}
+
+	return elements
+}
+
+func expectElements(t *testing.T, label string, actual []int64, expected []int64) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Errorf("%s: %d elements, but %d are expected", label, len(actual), len(expected))
+		return
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("%s[%d]: %d, but %d is expected", label, i, actual[i], expected[i])
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	spec := newSpec(t, 2, `{
+	  "num_executions": 1,
+	  "symbols": [
+	    {"name": "constant", "direction": "input", "type": "int32", "count": 2,
+	     "generator": {"kind": "constant", "value": -3}},
+	    {"name": "values", "direction": "input", "type": "uint32", "count": 2,
+	     "generator": {"kind": "values", "values": [4294967295, 1]}},
+	    {"name": "range", "direction": "input", "type": "int64", "count": 2,
+	     "generator": {"kind": "range", "start": 10, "step": 5}},
+	    {"name": "dpu_id", "direction": "input", "type": "int32", "count": 2,
+	     "generator": {"kind": "dpu_id"}},
+	    {"name": "random", "direction": "input", "type": "int32", "count": 64,
+	     "generator": {"kind": "random", "min": -4, "max": 4}},
+	    {"name": "add", "direction": "output", "type": "int64", "count": 2,
+	     "generator": {"kind": "add", "operands": ["range", "dpu_id", "constant"]}},
+	    {"name": "sum", "direction": "output", "type": "int32", "count": 2,
+	     "generator": {"kind": "sum", "operands": ["range"]}},
+	    {"name": "min", "direction": "output", "type": "int32", "count": 2,
+	     "generator": {"kind": "min", "operands": ["constant"]}},
+	    {"name": "max", "direction": "output", "type": "uint32", "count": 2,
+	     "generator": {"kind": "max", "operands": ["values"]}}
+	  ]
+	}`)
+
+	inputs := [][]map[string][]int64{
+		{
+			{"constant": {-3, -3}, "values": {4294967295, 1}, "range": {10, 15}, "dpu_id": {0, 0}},
+			{"add": {7, 12}, "sum": {25, 0}, "min": {-3, 0}, "max": {4294967295, 0}},
+		},
+		{
+			{"constant": {-3, -3}, "values": {4294967295, 1}, "range": {20, 25}, "dpu_id": {1, 1}},
+			{"add": {18, 23}, "sum": {45, 0}, "min": {-3, 0}, "max": {4294967295, 0}},
+		},
+	}
+
+	for dpu_id, expectations := range inputs {
+		input_dpu_host := spec.InputDpuHost(0, dpu_id)
+		output_dpu_host := spec.OutputDpuHost(0, dpu_id)
+
+		for i, dpu_host := range []map[string]*encoding.ByteStream{input_dpu_host, output_dpu_host} {
+			for name, expected := range expectations[i] {
+				element_size := 4
+				if name == "range" || name == "add" {
+					element_size = 8
+				}
+
+				actual := decode(dpu_host[name], element_size)
+				if element_size == 4 && name != "values" && name != "max" {
+					for j := range actual {
+						actual[j] = int64(int32(actual[j]))
+					}
+				}
+
+				expectElements(t, "DPU "+strconv.Itoa(dpu_id)+" "+name, actual, expected)
+			}
+		}
+
+		for _, element := range decode(input_dpu_host["random"], 4) {
+			if value := int32(element); value < -4 || value >= 4 {
+				t.Errorf("DPU %d random element %d is out of [-4, 4)", dpu_id, value)
+			}
+		}
+	}
+
+	element_types := spec.ElementTypes()
+	if element_types["range+0"] != "int64" || element_types["values+0"] != "uint32" {
+		t.Errorf("element types are %v", element_types)
+	}
+}
+
+func TestGeneratorsKeepIntegers(t *testing.T) {
+	data := make([]byte, 0)
+	for _, value := range []int64{1<<53 + 1, -(1<<53 + 1), math.MaxInt64, 3} {
+		data = binary.LittleEndian.AppendUint64(data, uint64(value))
+	}
+
+	npy_path := writeFile(t, "a.npy", npyBytes(1, "<i8", data))
+
+	spec := newSpec(t, 2, `{
+	  "num_executions": 1,
+	  "symbols": [
+	    {"name": "values", "direction": "input", "type": "uint64", "count": 2,
+	     "generator": {"kind": "values", "values": [18446744073709551615, 9007199254740993]}},
+	    {"name": "range", "direction": "input", "type": "int64", "count": 1,
+	     "generator": {"kind": "range", "start": 9007199254740993, "step": 2}},
+	    {"name": "file", "direction": "input", "type": "int64", "count": 2,
+	     "generator": {"kind": "file", "path": "`+npy_path+`"}},
+	    {"name": "min", "direction": "output", "type": "uint64", "count": 2,
+	     "generator": {"kind": "min", "operands": ["values"]}}
+	  ]
+	}`)
+
+	expectations := []map[string][]int64{
+		{
+			"values": {-1, 1<<53 + 1},
+			"range":  {1<<53 + 1},
+			"file":   {1<<53 + 1, -(1<<53 + 1)},
+		},
+		{
+			"values": {-1, 1<<53 + 1},
+			"range":  {1<<53 + 3},
+			"file":   {math.MaxInt64, 3},
+		},
+	}
+
+	for dpu_id, expectation := range expectations {
+		input_dpu_host := spec.InputDpuHost(0, dpu_id)
+
+		for name, expected := range expectation {
+			label := "DPU " + strconv.Itoa(dpu_id) + " " + name
+			expectElements(t, label, decode(input_dpu_host[name], 8), expected)
+		}
+
+		label := "DPU " + strconv.Itoa(dpu_id) + " min"
+		expected := []int64{1<<53 + 1, 0}
+		expectElements(t, label, decode(spec.OutputDpuHost(0, dpu_id)["min"], 8), expected)
+	}
+}
+
+func TestHeapOffsets(t *testing.T) {
+	spec := newSpec(t, 1, `{
+	  "num_executions": 2,
+	  "symbols": [
+	    {"id": "a", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 24, "direction": "input",
+	     "type": "int32", "count": 2, "generator": {"kind": "values", "values": [1, 2]}},
+	    {"id": "b", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 8, "direction": "input",
+	     "type": "int32", "count": 2, "generator": {"kind": "values", "values": [3, 4]}},
+	    {"id": "c", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 40, "direction": "input",
+	     "type": "int64", "count": 1, "executions": [1],
+	     "generator": {"kind": "constant", "value": 5}},
+	    {"id": "d", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 64, "direction": "output",
+	     "type": "int32", "count": 2, "generator": {"kind": "add", "operands": ["a", "b"]}}
+	  ]
+	}`)
+
+	offset, byte_stream := spec.InputDpuMramHeapPointerName(0, 0)
+	if offset != 8 {
+		t.Errorf("execution 0 input offset is %d, but 8 is expected", offset)
+	}
+	expectElements(t, "execution 0 input", decode(byte_stream, 4), []int64{3, 4, 0, 0, 1, 2})
+
+	offset, byte_stream = spec.InputDpuMramHeapPointerName(1, 0)
+	if offset != 8 {
+		t.Errorf("execution 1 input offset is %d, but 8 is expected", offset)
+	}
+	expected := []int64{3, 4, 0, 0, 1, 2, 0, 0, 5, 0}
+	expectElements(t, "execution 1 input", decode(byte_stream, 4), expected)
+
+	offset, byte_stream = spec.OutputDpuMramHeapPointerName(0, 0)
+	if offset != 64 {
+		t.Errorf("output offset is %d, but 64 is expected", offset)
+	}
+	expectElements(t, "output", decode(byte_stream, 4), []int64{4, 6})
+
+	if len(spec.InputDpuHost(0, 0)) != 0 {
+		t.Errorf("heap symbols are transferred as DPU host symbols")
+	}
+
+	element_types := spec.ElementTypes()
+	expected_element_types := map[string]string{
+		"DPU_MRAM_HEAP_POINTER_NAME+8":  "int32",
+		"DPU_MRAM_HEAP_POINTER_NAME+24": "int32",
+		"DPU_MRAM_HEAP_POINTER_NAME+40": "int64",
+		"DPU_MRAM_HEAP_POINTER_NAME+64": "int32",
+	}
+	if len(element_types) != len(expected_element_types) {
+		t.Errorf("element types are %v", element_types)
+	}
+	for key, element_type := range expected_element_types {
+		if element_types[key] != element_type {
+			t.Errorf("element type of %s is %s", key, element_types[key])
+		}
+	}
+}
+
+// NOTE: an output at the offset of an input is the one verified, so its element type is kept
+func TestElementTypesPreferOutputs(t *testing.T) {
+	spec := newSpec(t, 1, `{
+	  "num_executions": 1,
+	  "symbols": [
+	    {"id": "a", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 0, "direction": "input",
+	     "type": "int64", "count": 2, "generator": {"kind": "values", "values": [1, 2]}},
+	    {"id": "b", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 0, "direction": "output",
+	     "type": "double", "count": 2, "generator": {"kind": "copy", "operands": ["a"]}}
+	  ]
+	}`)
+
+	if element_type := spec.ElementTypes()["DPU_MRAM_HEAP_POINTER_NAME+0"]; element_type != "double" {
+		t.Errorf("element type of the heap at offset 0 is %s", element_type)
+	}
+}
+
+// NOTE: 2^24 + 1 is not a float32, so that 2^24 + 1 + 1 is 2^24 when rounded after every step as
+// the DPUs do, but 2^24 + 2 when rounded once
+func TestFloatRounding(t *testing.T) {
+	spec := newSpec(t, 1, `{
+	  "num_executions": 1,
+	  "symbols": [
+	    {"name": "a", "direction": "input", "type": "float", "count": 4,
+	     "generator": {"kind": "values", "values": [16777216, 1, 1, 0.1]}},
+	    {"name": "b", "direction": "input", "type": "double", "count": 4,
+	     "generator": {"kind": "values", "values": [16777216, 1, 1, 0.1]}},
+	    {"name": "c", "direction": "input", "type": "int32", "count": 4,
+	     "generator": {"kind": "constant", "value": 1}},
+	    {"name": "a_sum", "direction": "output", "type": "float", "count": 2,
+	     "generator": {"kind": "sum", "operands": ["a"]}},
+	    {"name": "b_sum", "direction": "output", "type": "double", "count": 2,
+	     "generator": {"kind": "sum", "operands": ["b"]}},
+	    {"name": "bc_add", "direction": "output", "type": "float", "count": 4,
+	     "generator": {"kind": "add", "operands": ["b", "c", "c"]}}
+	  ]
+	}`)
+
+	input_dpu_host := spec.InputDpuHost(0, 0)
+	if bits := decode(input_dpu_host["a"], 4)[3]; uint32(bits) != math.Float32bits(0.1) {
+		t.Errorf("float input 0.1 is stored as %g", math.Float32frombits(uint32(bits)))
+	}
+
+	output_dpu_host := spec.OutputDpuHost(0, 0)
+
+	a_sum := math.Float32frombits(uint32(decode(output_dpu_host["a_sum"], 4)[0]))
+	if a_sum != 16777216 {
+		t.Errorf("float sum is %g", a_sum)
+	}
+
+	b_sum := math.Float64frombits(uint64(decode(output_dpu_host["b_sum"], 8)[0]))
+	if b_sum != 16777218.1 {
+		t.Errorf("double sum is %g", b_sum)
+	}
+
+	bc_add := math.Float32frombits(uint32(decode(output_dpu_host["bc_add"], 4)[0]))
+	if bc_add != 16777216 {
+		t.Errorf("float add is %g", bc_add)
+	}
+}
+
+func TestInvalidSpecs(t *testing.T) {
+	invalids := map[string]string{
+		"no executions": `{"num_executions": 0, "symbols": []}`,
+		"unaligned size": `{"num_executions": 1, "symbols": [{"name": "a", "direction": "input",
+		  "type": "int32", "count": 1, "generator": {"kind": "dpu_id"}}]}`,
+		"unaligned offset": `{"num_executions": 1, "symbols": [{"name": "DPU_MRAM_HEAP_POINTER_NAME",
+		  "offset": 4, "direction": "input", "type": "int32", "count": 2,
+		  "generator": {"kind": "dpu_id"}}]}`,
+		"overlap": `{"num_executions": 1, "symbols": [
+		  {"id": "a", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 0, "direction": "input",
+		   "type": "int64", "count": 2, "generator": {"kind": "dpu_id"}},
+		  {"id": "b", "name": "DPU_MRAM_HEAP_POINTER_NAME", "offset": 8, "direction": "input",
+		   "type": "int64", "count": 1, "generator": {"kind": "dpu_id"}}]}`,
+		"unknown operand": `{"num_executions": 1, "symbols": [{"name": "a", "direction": "output",
+		  "type": "int32", "count": 2, "generator": {"kind": "copy", "operands": ["b"]}}]}`,
+		"short values": `{"num_executions": 1, "symbols": [{"name": "a", "direction": "input",
+		  "type": "int32", "count": 2, "generator": {"kind": "values", "values": [1]}}]}`,
+		"duplicated ID": `{"num_executions": 1, "symbols": [
+		  {"name": "a", "direction": "input", "type": "int64", "count": 1,
+		   "generator": {"kind": "dpu_id"}},
+		  {"name": "a", "direction": "output", "type": "int64", "count": 1,
+		   "generator": {"kind": "copy", "operands": ["a"]}}]}`,
+	}
+
+	for name, json := range invalids {
+		if !isPanicking(func() {
+			spec := newSpec(t, 1, json)
+			spec.InputDpuMramHeapPointerName(0, 0)
+		}) {
+			t.Errorf("%s is accepted", name)
+		}
+	}
+}
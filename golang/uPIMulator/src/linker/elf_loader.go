@@ -0,0 +1,336 @@
+package linker
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/logic"
+	"uPIMulator/src/misc"
+)
+
+type ElfLoader struct {
+	elf_filepath string
+	bin_dirpath  string
+
+	linker_script *logic.LinkerScript
+	address_space *logic.AddressSpace
+
+	byte_streams map[logic.Memory]*encoding.ByteStream
+	addresses    map[string]int64
+	values       map[string]int64
+
+	source_line_table *kernel.SourceLineTable
+}
+
+func (this *ElfLoader) Init(command_line_parser *misc.CommandLineParser) {
+	this.elf_filepath = command_line_parser.StringParameter("elf_filepath")
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
+
+	this.linker_script = new(logic.LinkerScript)
+	this.linker_script.Init(command_line_parser)
+
+	this.address_space = new(logic.AddressSpace)
+	this.address_space.Init()
+
+	this.byte_streams = make(map[logic.Memory]*encoding.ByteStream, 0)
+	for _, memory := range this.address_space.Memories() {
+		this.byte_streams[memory] = new(encoding.ByteStream)
+		this.byte_streams[memory].Init()
+	}
+
+	this.addresses = make(map[string]int64, 0)
+	this.values = make(map[string]int64, 0)
+
+	this.source_line_table = new(kernel.SourceLineTable)
+	this.source_line_table.Init()
+}
+
+func (this *ElfLoader) Load() {
+	this.Read()
+	this.Dump()
+}
+
+func (this *ElfLoader) Read() {
+	fmt.Printf("Loading %s...\n", this.elf_filepath)
+
+	file, open_err := elf.Open(this.elf_filepath)
+
+	if open_err != nil {
+		panic(open_err)
+	}
+
+	defer file.Close()
+
+	if file.Type != elf.ET_EXEC {
+		err_msg := fmt.Sprintf("%s is not an executable", this.elf_filepath)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if file.Data != elf.ELFDATA2LSB {
+		err_msg := fmt.Sprintf("%s is not little-endian", this.elf_filepath)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.LoadSections(file)
+	this.LoadSymbols(file)
+	this.LoadSourceLines(file)
+
+	fmt.Println("Decoding IRAM...")
+	this.DecodeIram()
+}
+
+func (this *ElfLoader) ByteStream(memory logic.Memory) *encoding.ByteStream {
+	return this.byte_streams[memory]
+}
+
+func (this *ElfLoader) Addresses() map[string]int64 {
+	return this.addresses
+}
+
+func (this *ElfLoader) Values() map[string]int64 {
+	return this.values
+}
+
+func (this *ElfLoader) SourceLineTable() *kernel.SourceLineTable {
+	return this.source_line_table
+}
+
+// NOTE: executables built without debug information have no line table, which leaves the source
+// line table empty
+func (this *ElfLoader) LoadSourceLines(file *elf.File) {
+	data, dwarf_err := file.DWARF()
+	if dwarf_err != nil {
+		return
+	}
+
+	reader := data.Reader()
+	for {
+		entry, entry_err := reader.Next()
+		if entry_err != nil {
+			panic(entry_err)
+		} else if entry == nil {
+			break
+		}
+
+		if entry.Tag != dwarf.TagCompileUnit {
+			reader.SkipChildren()
+			continue
+		}
+
+		line_reader, line_reader_err := data.LineReader(entry)
+		if line_reader_err != nil {
+			panic(line_reader_err)
+		} else if line_reader == nil {
+			continue
+		}
+
+		var line_entry dwarf.LineEntry
+		for {
+			if read_err := line_reader.Next(&line_entry); read_err == io.EOF {
+				break
+			} else if read_err != nil {
+				panic(read_err)
+			}
+
+			address := int64(line_entry.Address)
+			iram_origin := this.address_space.Origin(logic.IRAM_MEMORY)
+			iram_size := this.address_space.Size(logic.IRAM_MEMORY)
+
+			if line_entry.EndSequence || line_entry.Line == 0 || line_entry.File == nil {
+				continue
+			} else if address < iram_origin || iram_origin+iram_size <= address {
+				continue
+			}
+
+			address = this.address_space.Translate(address)
+
+			if this.source_line_table.SourceLine(address) == nil {
+				source_line := new(kernel.SourceLine)
+				source_line.Init(line_entry.File.Name, int64(line_entry.Line), int64(line_entry.Column))
+
+				this.source_line_table.Add(address, source_line)
+			}
+		}
+	}
+}
+
+func (this *ElfLoader) LoadSections(file *elf.File) {
+	for _, section := range file.Sections {
+		if section.Flags&elf.SHF_ALLOC == 0 || section.Size == 0 {
+			continue
+		}
+
+		address := int64(section.Addr)
+		memory := this.address_space.Memory(address)
+
+		if this.address_space.Memory(address+int64(section.Size)-1) != memory {
+			err_msg := fmt.Sprintf("section (%s) crosses a memory", section.Name)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		var bytes []byte
+		if section.Type == elf.SHT_NOBITS {
+			bytes = make([]byte, section.Size)
+		} else {
+			var read_err error
+			bytes, read_err = section.Data()
+
+			if read_err != nil {
+				panic(read_err)
+			}
+		}
+
+		this.Write(memory, this.address_space.Translate(address)-this.address_space.Offset(memory), bytes)
+	}
+}
+
+func (this *ElfLoader) LoadSymbols(file *elf.File) {
+	symbols, symbols_err := file.Symbols()
+
+	if symbols_err != nil {
+		panic(symbols_err)
+	}
+
+	// NOTE: local symbols such as static variables of different files may share a name, and the
+	// host only ever looks up global ones, so that duplicates are only flagged among globals
+	for _, symbol := range symbols {
+		symbol_type := elf.ST_TYPE(symbol.Info)
+
+		if symbol.Name == "" ||
+			symbol.Section == elf.SHN_UNDEF ||
+			elf.ST_BIND(symbol.Info) == elf.STB_LOCAL ||
+			symbol_type == elf.STT_SECTION ||
+			symbol_type == elf.STT_FILE {
+			continue
+		}
+
+		// NOTE: absolute symbols without the reserved "__" prefix (e.g., NR_TASKLETS and
+		// STACK_SIZE_TASKLET_<n>) are plain values rather than addresses
+		var value int64
+		if symbol.Section == elf.SHN_ABS && !strings.HasPrefix(symbol.Name, "__") {
+			value = int64(symbol.Value)
+		} else {
+			value = this.address_space.Translate(int64(symbol.Value))
+		}
+
+		if this.linker_script.HasLinkerConstant(symbol.Name) {
+			this.values[symbol.Name] = value
+		} else {
+			if _, found := this.addresses[symbol.Name]; found {
+				err_msg := fmt.Sprintf("symbol (%s) is defined more than once", symbol.Name)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			this.addresses[symbol.Name] = value
+		}
+	}
+
+	if _, found := this.values["__sys_used_mram_end"]; !found {
+		err := errors.New("__sys_used_mram_end is not defined")
+		panic(err)
+	}
+}
+
+// NOTE: IRAM of an executable holds instructions in the simulator's encoding of IramDataWidth bits,
+// as the linker dumps them to iram.bin, so that its IRAM addresses count IramDataWidth/8 bytes per
+// instruction; the 64-bit encoding of the UPMEM toolchain does not round-trip and is rejected
+func (this *ElfLoader) DecodeIram() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+	iram_byte_stream := this.byte_streams[logic.IRAM_MEMORY]
+
+	if iram_byte_stream.Size()%iram_data_size != 0 {
+		err_msg := fmt.Sprintf(
+			"IRAM size (%d) is not a multiple of the IRAM data size (%d)",
+			iram_byte_stream.Size(),
+			iram_data_size,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	for i := int64(0); i < iram_byte_stream.Size(); i += iram_data_size {
+		byte_stream := new(encoding.ByteStream)
+		byte_stream.Init()
+		for j := int64(0); j < iram_data_size; j++ {
+			byte_stream.Append(iram_byte_stream.Get(int(i + j)))
+		}
+
+		instruction_ := new(instruction.Instruction)
+		instruction_.Decode(byte_stream)
+
+		encoded_byte_stream := instruction_.Encode()
+		for j := int64(0); j < iram_data_size; j++ {
+			if encoded_byte_stream.Get(int(j)) != byte_stream.Get(int(j)) {
+				err_msg := fmt.Sprintf(
+					"instruction at IRAM address %d is not in the simulator's IRAM encoding",
+					config_loader.IramOffset()+i,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+		}
+	}
+}
+
+func (this *ElfLoader) Write(memory logic.Memory, index int64, bytes []byte) {
+	byte_stream := this.byte_streams[memory]
+
+	for byte_stream.Size() < index+int64(len(bytes)) {
+		byte_stream.Append(0)
+	}
+
+	for i, value := range bytes {
+		byte_stream.Set(int(index)+i, value)
+	}
+}
+
+func (this *ElfLoader) Dump() {
+	this.DumpSymbols(filepath.Join(this.bin_dirpath, "values.txt"), this.values)
+	this.DumpSymbols(filepath.Join(this.bin_dirpath, "addresses.txt"), this.addresses)
+	this.source_line_table.Dump(filepath.Join(this.bin_dirpath, "source_lines.txt"))
+	this.DumpByteStream(filepath.Join(this.bin_dirpath, "atomic.bin"), this.byte_streams[logic.ATOMIC_MEMORY])
+	this.DumpByteStream(filepath.Join(this.bin_dirpath, "iram.bin"), this.byte_streams[logic.IRAM_MEMORY])
+	this.DumpByteStream(filepath.Join(this.bin_dirpath, "wram.bin"), this.byte_streams[logic.WRAM_MEMORY])
+	this.DumpByteStream(filepath.Join(this.bin_dirpath, "mram.bin"), this.byte_streams[logic.MRAM_MEMORY])
+}
+
+func (this *ElfLoader) DumpSymbols(path string, symbols map[string]int64) {
+	names := make([]string, 0)
+	for name, _ := range symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0)
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s: %d", name, symbols[name]))
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
+func (this *ElfLoader) DumpByteStream(path string, byte_stream *encoding.ByteStream) {
+	lines := make([]string, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		lines = append(lines, fmt.Sprintf("%d", byte_stream.Get(int(i))))
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
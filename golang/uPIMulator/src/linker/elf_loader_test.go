@@ -0,0 +1,426 @@
+package linker
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/cc"
+	"uPIMulator/src/linker/logic"
+	"uPIMulator/src/misc"
+)
+
+type elfSection struct {
+	name         string
+	section_type elf.SectionType
+	flags        elf.SectionFlag
+	address      uint32
+	data         []byte
+	size         uint32
+}
+
+type elfSymbol struct {
+	name        string
+	value       uint32
+	section     elf.SectionIndex
+	symbol_type elf.SymType
+	binding     elf.SymBind
+}
+
+func appendString(table *[]byte, name string) uint32 {
+	index := uint32(len(*table))
+	*table = append(*table, name...)
+	*table = append(*table, 0)
+	return index
+}
+
+// NOTE: a minimal ELF32 executable with the given sections followed by .symtab, .strtab and
+// .shstrtab, which is all that debug/elf needs to read sections and symbols
+func newElf(sections []*elfSection, symbols []*elfSymbol) []byte {
+	strtab := []byte{0}
+	symtab := make([]byte, 16)
+	for _, symbol := range symbols {
+		symtab = binary.LittleEndian.AppendUint32(symtab, appendString(&strtab, symbol.name))
+		symtab = binary.LittleEndian.AppendUint32(symtab, symbol.value)
+		symtab = binary.LittleEndian.AppendUint32(symtab, 0)
+		symtab = append(symtab, elf.ST_INFO(symbol.binding, symbol.symbol_type), 0)
+		symtab = binary.LittleEndian.AppendUint16(symtab, uint16(symbol.section))
+	}
+
+	num_sections := len(sections) + 4
+	sections = append(
+		sections,
+		&elfSection{name: ".symtab", section_type: elf.SHT_SYMTAB, data: symtab},
+		&elfSection{name: ".strtab", section_type: elf.SHT_STRTAB, data: strtab},
+		&elfSection{name: ".shstrtab", section_type: elf.SHT_STRTAB},
+	)
+
+	shstrtab := []byte{0}
+	names := make([]uint32, 0)
+	for _, section := range sections {
+		names = append(names, appendString(&shstrtab, section.name))
+	}
+	sections[len(sections)-1].data = shstrtab
+
+	file := make([]byte, 52)
+	offsets := make([]uint32, 0)
+	for _, section := range sections {
+		offsets = append(offsets, uint32(len(file)))
+		if section.section_type != elf.SHT_NOBITS {
+			file = append(file, section.data...)
+		}
+	}
+
+	for len(file)%4 != 0 {
+		file = append(file, 0)
+	}
+	section_header_offset := uint32(len(file))
+
+	file = append(file, make([]byte, 40)...)
+	for i, section := range sections {
+		size := uint32(len(section.data))
+		if section.section_type == elf.SHT_NOBITS {
+			size = section.size
+		}
+
+		link := uint32(0)
+		entry_size := uint32(0)
+		if section.section_type == elf.SHT_SYMTAB {
+			link = uint32(num_sections - 2)
+			entry_size = 16
+		}
+
+		for _, field := range []uint32{
+			names[i],
+			uint32(section.section_type),
+			uint32(section.flags),
+			section.address,
+			offsets[i],
+			size,
+			link,
+			0,
+			1,
+			entry_size,
+		} {
+			file = binary.LittleEndian.AppendUint32(file, field)
+		}
+	}
+
+	header := []byte{0x7f, 'E', 'L', 'F', byte(elf.ELFCLASS32), byte(elf.ELFDATA2LSB), 1}
+	header = append(header, make([]byte, 9)...)
+	header = binary.LittleEndian.AppendUint16(header, uint16(elf.ET_EXEC))
+	header = binary.LittleEndian.AppendUint16(header, 245)
+	header = binary.LittleEndian.AppendUint32(header, 1)
+	header = binary.LittleEndian.AppendUint32(header, uint32(logic.IRAM_ORIGIN))
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	header = binary.LittleEndian.AppendUint32(header, section_header_offset)
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	header = binary.LittleEndian.AppendUint16(header, 52)
+	header = binary.LittleEndian.AppendUint16(header, 0)
+	header = binary.LittleEndian.AppendUint16(header, 0)
+	header = binary.LittleEndian.AppendUint16(header, 40)
+	header = binary.LittleEndian.AppendUint16(header, uint16(num_sections))
+	header = binary.LittleEndian.AppendUint16(header, uint16(num_sections-1))
+	copy(file, header)
+
+	return file
+}
+
+func newIram() []byte {
+	nop := new(instruction.Instruction)
+	nop.InitZ(instruction.NOP)
+
+	stop := new(instruction.Instruction)
+	stop.InitCi(instruction.STOP, cc.TRUE, 0)
+
+	iram := new(encoding.ByteStream)
+	iram.Init()
+	iram.Merge(nop.Encode())
+	iram.Merge(stop.Encode())
+
+	bytes := make([]byte, 0)
+	for i := int64(0); i < iram.Size(); i++ {
+		bytes = append(bytes, iram.Get(int(i)))
+	}
+	return bytes
+}
+
+func newSections(iram []byte) []*elfSection {
+	return []*elfSection{
+		{
+			name:         ".text",
+			section_type: elf.SHT_PROGBITS,
+			flags:        elf.SHF_ALLOC | elf.SHF_EXECINSTR,
+			address:      uint32(logic.IRAM_ORIGIN),
+			data:         iram,
+		},
+		{
+			name:         ".data",
+			section_type: elf.SHT_PROGBITS,
+			flags:        elf.SHF_ALLOC | elf.SHF_WRITE,
+			address:      uint32(logic.WRAM_ORIGIN + 8),
+			data:         []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			name:         ".bss",
+			section_type: elf.SHT_NOBITS,
+			flags:        elf.SHF_ALLOC | elf.SHF_WRITE,
+			address:      uint32(logic.WRAM_ORIGIN + 16),
+			size:         8,
+		},
+		{
+			name:         ".mram",
+			section_type: elf.SHT_PROGBITS,
+			flags:        elf.SHF_ALLOC | elf.SHF_WRITE,
+			address:      uint32(logic.MRAM_ORIGIN + 8),
+			data:         []byte{9, 10, 11, 12, 13, 14, 15, 16},
+		},
+		{
+			name:         ".debug_info",
+			section_type: elf.SHT_PROGBITS,
+			data:         bytes.Repeat([]byte{0xff}, 16),
+		},
+	}
+}
+
+func newSymbols() []*elfSymbol {
+	return []*elfSymbol{
+		{"__bootstrap", uint32(logic.IRAM_ORIGIN), 1, elf.STT_FUNC, elf.STB_GLOBAL},
+		{"buffer", uint32(logic.WRAM_ORIGIN + 8), 2, elf.STT_OBJECT, elf.STB_GLOBAL},
+		{"counter", uint32(logic.WRAM_ORIGIN + 16), 3, elf.STT_OBJECT, elf.STB_GLOBAL},
+		{"table", uint32(logic.MRAM_ORIGIN + 8), 4, elf.STT_OBJECT, elf.STB_GLOBAL},
+		{".text", uint32(logic.IRAM_ORIGIN), 1, elf.STT_SECTION, elf.STB_LOCAL},
+		{"NR_TASKLETS", 16, elf.SHN_ABS, elf.STT_NOTYPE, elf.STB_GLOBAL},
+		{"STACK_SIZE_TASKLET_0", 1024, elf.SHN_ABS, elf.STT_NOTYPE, elf.STB_GLOBAL},
+		{
+			"__sys_used_mram_end",
+			uint32(logic.MRAM_ORIGIN + 16),
+			elf.SHN_ABS,
+			elf.STT_NOTYPE,
+			elf.STB_GLOBAL,
+		},
+		{"printf", 0, elf.SHN_UNDEF, elf.STT_FUNC, elf.STB_GLOBAL},
+	}
+}
+
+func newElfLoader(t *testing.T, file []byte) *ElfLoader {
+	t.Helper()
+
+	elf_filepath := filepath.Join(t.TempDir(), "main.elf")
+	if err := os.WriteFile(elf_filepath, file, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+
+	command_line_parser.AddOption(misc.STRING, "root_dirpath", "../..", "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.STRING, "elf_filepath", elf_filepath, "")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "", "")
+	command_line_parser.AddOption(misc.INT, "num_tasklets", "16", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+
+	elf_loader := new(ElfLoader)
+	elf_loader.Init(command_line_parser)
+	return elf_loader
+}
+
+func recoverMessage(function func()) (message string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			message = fmt.Sprint(recovered)
+		}
+	}()
+
+	function()
+	return ""
+}
+
+func TestElfLoaderTranslatesAddresses(t *testing.T) {
+	iram := newIram()
+
+	elf_loader := newElfLoader(t, newElf(newSections(iram), newSymbols()))
+	elf_loader.Read()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	addresses := map[string]int64{
+		"__bootstrap": config_loader.IramOffset(),
+		"buffer":      config_loader.WramOffset() + 8,
+		"counter":     config_loader.WramOffset() + 16,
+		"table":       config_loader.MramOffset() + 8,
+	}
+
+	for name, address := range addresses {
+		if elf_loader.Addresses()[name] != address {
+			t.Errorf("%s is at %d, but %d is expected", name, elf_loader.Addresses()[name], address)
+		}
+	}
+
+	if len(elf_loader.Addresses()) != len(addresses) {
+		t.Errorf("addresses are %v", elf_loader.Addresses())
+	}
+
+	// NOTE: absolute symbols are values as they are, unless they are reserved "__" addresses
+	values := map[string]int64{
+		"NR_TASKLETS":          16,
+		"STACK_SIZE_TASKLET_0": 1024,
+		"__sys_used_mram_end":  config_loader.MramOffset() + 16,
+	}
+
+	for name, value := range values {
+		if elf_loader.Values()[name] != value {
+			t.Errorf("%s is %d, but %d is expected", name, elf_loader.Values()[name], value)
+		}
+	}
+
+	byte_streams := map[logic.Memory][]byte{
+		logic.IRAM_MEMORY: iram,
+		logic.WRAM_MEMORY: {0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 0, 0, 0, 0, 0, 0, 0},
+		logic.MRAM_MEMORY: {0, 0, 0, 0, 0, 0, 0, 0, 9, 10, 11, 12, 13, 14, 15, 16},
+	}
+
+	for memory, expected := range byte_streams {
+		byte_stream := elf_loader.ByteStream(memory)
+
+		actual := make([]byte, 0)
+		for i := int64(0); i < byte_stream.Size(); i++ {
+			actual = append(actual, byte_stream.Get(int(i)))
+		}
+
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("memory %d is %v, but %v is expected", memory, actual, expected)
+		}
+	}
+}
+
+// NOTE: testdata/executable.elf is testdata/executable.S as an ELF executable, so that its IRAM and
+// addresses are the ones that the linker gives the kernel
+func TestElfLoaderLoadsLinkedExecutable(t *testing.T) {
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "executable.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	linker_ := new(Linker)
+	linker_.Init(newLinkerCommandLineParser(t, asm_filepath, t.TempDir(), t.TempDir()))
+	linker_.Link()
+
+	executable := linker_.Executable()
+
+	file, read_err := os.ReadFile(filepath.Join("testdata", "executable.elf"))
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	elf_loader := newElfLoader(t, file)
+	elf_loader.Read()
+
+	for _, name := range []string{"__bootstrap", "__sys_end", "value", "buffer"} {
+		if elf_loader.Addresses()[name] != executable.Addresses()[name] {
+			t.Errorf(
+				"%s is at %d, but the linker places it at %d",
+				name,
+				elf_loader.Addresses()[name],
+				executable.Addresses()[name],
+			)
+		}
+	}
+
+	iram := elf_loader.ByteStream(logic.IRAM_MEMORY)
+	linked_iram := executable.IramByteStream()
+
+	if iram.Size() != linked_iram.Size() {
+		t.Fatalf("IRAM is %d bytes, but the linker's is %d bytes", iram.Size(), linked_iram.Size())
+	}
+
+	for i := 0; i < int(iram.Size()); i++ {
+		if iram.Get(i) != linked_iram.Get(i) {
+			t.Fatalf("IRAM byte %d is %d, but the linker's is %d", i, iram.Get(i), linked_iram.Get(i))
+		}
+	}
+}
+
+func TestElfLoaderRejectsForeignIram(t *testing.T) {
+	iram := newIram()
+
+	// NOTE: a 64-bit UPMEM instruction word is not a multiple of the simulator's IRAM data size
+	message := recoverMessage(func() {
+		newElfLoader(t, newElf(newSections(iram[:8]), newSymbols())).Read()
+	})
+	if !strings.Contains(message, "not a multiple of the IRAM data size") {
+		t.Errorf("a truncated IRAM is loaded: %s", message)
+	}
+
+	// NOTE: bits that the simulator's encoding never sets are lost when re-encoding
+	foreign_iram := append([]byte{}, iram...)
+	foreign_iram[len(foreign_iram)-1] |= 0x80
+
+	message = recoverMessage(func() {
+		newElfLoader(t, newElf(newSections(foreign_iram), newSymbols())).Read()
+	})
+	if !strings.Contains(message, "not in the simulator's IRAM encoding") {
+		t.Errorf("a foreign IRAM encoding is loaded: %s", message)
+	}
+}
+
+func TestElfLoaderRejectsInvalidExecutables(t *testing.T) {
+	invalids := map[string][]byte{
+		"no __sys_used_mram_end": newElf(newSections(newIram()), newSymbols()[:7]),
+		"duplicate global": newElf(
+			newSections(newIram()),
+			append(
+				newSymbols(),
+				&elfSymbol{"buffer", uint32(logic.WRAM_ORIGIN), 2, elf.STT_OBJECT, elf.STB_GLOBAL},
+			),
+		),
+		"relocatable": func() []byte {
+			file := newElf(newSections(newIram()), newSymbols())
+			binary.LittleEndian.PutUint16(file[16:], uint16(elf.ET_REL))
+			return file
+		}(),
+	}
+
+	for name, file := range invalids {
+		if recoverMessage(func() { newElfLoader(t, file).Read() }) == "" {
+			t.Errorf("%s is accepted", name)
+		}
+	}
+}
+
+// NOTE: static variables of two files share a name as local symbols, each file comes with a local
+// STT_FILE symbol, and none of them is an address the host looks up
+func TestElfLoaderSkipsLocalSymbols(t *testing.T) {
+	symbols := append(
+		newSymbols(),
+		&elfSymbol{"a.c", 0, elf.SHN_ABS, elf.STT_FILE, elf.STB_LOCAL},
+		&elfSymbol{"count", uint32(logic.WRAM_ORIGIN), 2, elf.STT_OBJECT, elf.STB_LOCAL},
+		&elfSymbol{"b.c", 0, elf.SHN_ABS, elf.STT_FILE, elf.STB_LOCAL},
+		&elfSymbol{"count", uint32(logic.WRAM_ORIGIN + 4), 2, elf.STT_OBJECT, elf.STB_LOCAL},
+		&elfSymbol{".data", uint32(logic.WRAM_ORIGIN), 2, elf.STT_SECTION, elf.STB_LOCAL},
+	)
+
+	elf_loader := newElfLoader(t, newElf(newSections(newIram()), symbols))
+
+	if message := recoverMessage(elf_loader.Read); message != "" {
+		t.Fatalf("local symbols of the same name are rejected: %s", message)
+	}
+
+	for _, name := range []string{"a.c", "b.c", "count", ".data"} {
+		if _, found := elf_loader.Addresses()[name]; found {
+			t.Errorf("local symbol %s is loaded as an address", name)
+		}
+	}
+
+	if _, found := elf_loader.Addresses()["buffer"]; !found {
+		t.Errorf("global symbol buffer is not loaded along with local symbols")
+	}
+}
@@ -54,6 +54,15 @@ func (this *Executable) SetBenchmarkRelocatable(relocatable *Relocatable) {
 	this.UpdateUnresolvedSymbols(relocatable)
 }
 
+func (this *Executable) BenchmarkRelocatable() *Relocatable {
+	return this.benchmark_relocatable
+}
+
+func (this *Executable) HasSdkRelocatable(relocatable *Relocatable) bool {
+	_, found := this.sdk_relocatables[relocatable]
+	return found
+}
+
 func (this *Executable) AddSdkRelocatable(relocatable *Relocatable) {
 	this.sdk_relocatables[relocatable] = true
 
@@ -106,6 +115,10 @@ func (this *Executable) DumpAddresses(path string) {
 	file_dumper.WriteLines(lines)
 }
 
+func (this *Executable) DumpSourceLines(path string) {
+	this.SourceLineTable().Dump(path)
+}
+
 func (this *Executable) DumpAtomic(path string) {
 	atomic_byte_stream := this.AtomicByteStream()
 
@@ -185,6 +198,10 @@ func (this *Executable) Sections(section_name SectionName) map[*Section]bool {
 	return sections
 }
 
+func (this *Executable) AllSections() map[*Section]bool {
+	return this.sections
+}
+
 func (this *Executable) AddSection(
 	section_name SectionName,
 	name string,
@@ -198,6 +215,15 @@ func (this *Executable) AddSection(
 	}
 }
 
+func (this *Executable) RemoveSection(section *Section) {
+	if _, found := this.sections[section]; !found {
+		err := errors.New("section is not found")
+		panic(err)
+	}
+
+	delete(this.sections, section)
+}
+
 func (this *Executable) CurSection() *Section {
 	return this.cur_section
 }
@@ -231,6 +257,10 @@ func (this *Executable) Label(label_name string) *Label {
 func (this *Executable) Addresses() map[string]int64 {
 	addresses := make(map[string]int64, 0)
 	for section, _ := range this.sections {
+		if !section.IsLoaded() {
+			continue
+		}
+
 		for _, label := range section.Labels() {
 			addresses[label.Name()] = label.Address()
 		}
@@ -238,6 +268,35 @@ func (this *Executable) Addresses() map[string]int64 {
 	return addresses
 }
 
+func (this *Executable) SourceLineTable() *SourceLineTable {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_sections := this.Sort(
+		config_loader.IramOffset(),
+		config_loader.IramOffset()+config_loader.IramSize(),
+	)
+
+	source_line_table := new(SourceLineTable)
+	source_line_table.Init()
+
+	for _, iram_section := range iram_sections {
+		for _, label := range iram_section.Labels() {
+			address := label.Address()
+
+			for i, encodable := range label.Encodables() {
+				if source_line := label.SourceLines()[i]; source_line != nil {
+					source_line_table.Add(address, source_line)
+				}
+
+				address += encodable.Encode().Size()
+			}
+		}
+	}
+
+	return source_line_table
+}
+
 func (this *Executable) AtomicByteStream() *encoding.ByteStream {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -336,13 +395,60 @@ func (this *Executable) UpdateUnresolvedSymbols(relocatable *Relocatable) {
 	}
 }
 
+func (this *Executable) Relocatables() []*Relocatable {
+	sdk_relocatables := make([]*Relocatable, 0)
+	for sdk_relocatable, _ := range this.sdk_relocatables {
+		sdk_relocatables = append(sdk_relocatables, sdk_relocatable)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		return sdk_relocatables[i].Name() < sdk_relocatables[j].Name()
+	}
+
+	sort.Slice(sdk_relocatables, sort_fn)
+
+	return append([]*Relocatable{this.benchmark_relocatable}, sdk_relocatables...)
+}
+
+// NOTE: sections of the merged assembly do not remember where they came from, so the origins of
+// a section are the relocatables that define any of its labels
+func (this *Executable) Origins(section *Section) []string {
+	origins := make([]string, 0)
+	for _, relocatable := range this.Relocatables() {
+		for _, label := range section.Labels()[1:] {
+			if relocatable.Defines(label.Name()) {
+				origins = append(origins, relocatable.Name())
+				break
+			}
+		}
+	}
+	return origins
+}
+
+func (this *Executable) NonLoadedSections() []*Section {
+	sections := make([]*Section, 0)
+	for section, _ := range this.sections {
+		if !section.IsLoaded() {
+			sections = append(sections, section)
+		}
+	}
+
+	sort_fn := func(i int, j int) bool {
+		return sections[i].FullName() < sections[j].FullName()
+	}
+
+	sort.Slice(sections, sort_fn)
+
+	return sections
+}
+
 func (this *Executable) Sort(begin_address int64, end_address int64) []*Section {
 	sections := make([]*Section, 0)
 
 	for section, _ := range this.sections {
 		address := section.Address()
 
-		if begin_address <= address && address < end_address {
+		if section.IsLoaded() && begin_address <= address && address < end_address {
 			sections = append(sections, section)
 		}
 	}
@@ -10,6 +10,9 @@ type Label struct {
 	address     *int64
 	size        int64
 	byte_stream *encoding.ByteStream
+	encodables  []Encodable
+
+	source_lines []*SourceLine
 }
 
 func (this *Label) Init(name string) {
@@ -19,6 +22,10 @@ func (this *Label) Init(name string) {
 
 	this.byte_stream = new(encoding.ByteStream)
 	this.byte_stream.Init()
+
+	this.encodables = make([]Encodable, 0)
+
+	this.source_lines = make([]*SourceLine, 0)
 }
 
 func (this *Label) Name() string {
@@ -69,8 +76,23 @@ func (this *Label) ToByteStream() *encoding.ByteStream {
 	return this.byte_stream
 }
 
+func (this *Label) Encodables() []Encodable {
+	return this.encodables
+}
+
+// NOTE: source lines are parallel to encodables, and nil for encodables without a source line
+func (this *Label) SourceLines() []*SourceLine {
+	return this.source_lines
+}
+
 func (this *Label) Append(encodable Encodable) {
+	this.AppendWithSourceLine(encodable, nil)
+}
+
+func (this *Label) AppendWithSourceLine(encodable Encodable, source_line *SourceLine) {
 	this.byte_stream.Merge(encodable.Encode())
+	this.encodables = append(this.encodables, encodable)
+	this.source_lines = append(this.source_lines, source_line)
 
 	if this.byte_stream.Size() > this.size {
 		err := errors.New("byte stream's size > size")
@@ -2,14 +2,20 @@ package kernel
 
 type Liveness struct {
 	defs           map[string]bool
+	def_lines      map[string]int
 	uses           map[string]bool
+	use_lines      map[string]int
 	global_symbols map[string]bool
+	weak_symbols   map[string]bool
 }
 
 func (this *Liveness) Init() {
 	this.defs = make(map[string]bool, 0)
+	this.def_lines = make(map[string]int, 0)
 	this.uses = make(map[string]bool, 0)
+	this.use_lines = make(map[string]int, 0)
 	this.global_symbols = make(map[string]bool, 0)
+	this.weak_symbols = make(map[string]bool, 0)
 }
 
 func (this *Liveness) Defs() map[string]bool {
@@ -20,6 +26,21 @@ func (this *Liveness) AddDef(def string) {
 	this.defs[def] = true
 }
 
+func (this *Liveness) DefLines() map[string]int {
+	return this.def_lines
+}
+
+// NOTE: the line of a def is where the symbol is first defined, which diagnostics point at
+func (this *Liveness) DefLine(def string) int {
+	return this.def_lines[def]
+}
+
+func (this *Liveness) SetDefLine(def string, line int) {
+	if prev_line, found := this.def_lines[def]; !found || line < prev_line {
+		this.def_lines[def] = line
+	}
+}
+
 func (this *Liveness) Uses() map[string]bool {
 	return this.uses
 }
@@ -28,6 +49,21 @@ func (this *Liveness) AddUse(use string) {
 	this.uses[use] = true
 }
 
+func (this *Liveness) UseLines() map[string]int {
+	return this.use_lines
+}
+
+// NOTE: the line of a use is where the symbol is first referenced, which diagnostics point at
+func (this *Liveness) UseLine(use string) int {
+	return this.use_lines[use]
+}
+
+func (this *Liveness) SetUseLine(use string, line int) {
+	if prev_line, found := this.use_lines[use]; !found || line < prev_line {
+		this.use_lines[use] = line
+	}
+}
+
 func (this *Liveness) GlobalSymbols() map[string]bool {
 	return this.global_symbols
 }
@@ -36,16 +72,45 @@ func (this *Liveness) AddGlobalSymbol(global_symbol string) {
 	this.global_symbols[global_symbol] = true
 }
 
+func (this *Liveness) WeakSymbols() map[string]bool {
+	return this.weak_symbols
+}
+
+func (this *Liveness) AddWeakSymbol(weak_symbol string) {
+	this.weak_symbols[weak_symbol] = true
+}
+
+func (this *Liveness) IsWeak(symbol string) bool {
+	_, found := this.weak_symbols[symbol]
+	return found
+}
+
+func (this *Liveness) IsExported(symbol string) bool {
+	_, global_found := this.global_symbols[symbol]
+	_, weak_found := this.weak_symbols[symbol]
+	return global_found || weak_found
+}
+
 func (this *Liveness) LocalSymbols() map[string]bool {
 	local_symbols := make(map[string]bool, 0)
 	for def, _ := range this.defs {
-		if _, found := this.global_symbols[def]; !found {
+		if !this.IsExported(def) {
 			local_symbols[def] = true
 		}
 	}
 	return local_symbols
 }
 
+func (this *Liveness) ExportedSymbols() map[string]bool {
+	exported_symbols := make(map[string]bool, 0)
+	for def, _ := range this.defs {
+		if this.IsExported(def) {
+			exported_symbols[def] = true
+		}
+	}
+	return exported_symbols
+}
+
 func (this *Liveness) UnresolvedSymbols() map[string]bool {
 	unresolved_symbols := make(map[string]bool, 0)
 	for use, _ := range this.uses {
@@ -16,13 +16,15 @@ type Relocatable struct {
 	ast          *parser.Ast
 	liveness     *Liveness
 
-	renames map[string]string
+	renames  map[string]string
+	discards map[string]string
 }
 
 func (this *Relocatable) Init(name string) {
 	this.name = name
 
 	this.renames = make(map[string]string, 0)
+	this.discards = make(map[string]string, 0)
 }
 
 func (this *Relocatable) Name() string {
@@ -72,6 +74,21 @@ func (this *Relocatable) Lines() []string {
 	return lines
 }
 
+func (this *Relocatable) Defines(label_name string) bool {
+	for def, _ := range this.liveness.Defs() {
+		if rename, found := this.renames[def]; found {
+			def = rename
+		} else if discard, found := this.discards[def]; found {
+			def = discard
+		}
+
+		if def == label_name {
+			return true
+		}
+	}
+	return false
+}
+
 func (this *Relocatable) RenameLocalSymbol(old_name string, new_name string) {
 	if _, found := this.liveness.LocalSymbols()[old_name]; !found {
 		err := errors.New("local symbol is not found")
@@ -88,7 +105,29 @@ func (this *Relocatable) RenameLocalSymbol(old_name string, new_name string) {
 	this.renames[old_name] = new_name
 }
 
+// NOTE: a discarded definition loses the symbol to a definition in another relocatable, so only
+// its label is renamed while references keep pointing at the symbol
+func (this *Relocatable) DiscardSymbol(name string) {
+	if _, found := this.liveness.ExportedSymbols()[name]; !found {
+		err := errors.New("exported symbol is not found")
+		panic(err)
+	}
+
+	this.discards[name] = strings.ReplaceAll(this.name, "-", "_") + "." + name
+}
+
+func (this *Relocatable) IsDiscarded(name string) bool {
+	_, found := this.discards[name]
+	return found
+}
+
 func (this *Relocatable) RenameLine(line string) string {
+	for old_name, new_name := range this.discards {
+		if strings.HasPrefix(line, old_name+":") {
+			line = new_name + line[len(old_name):]
+		}
+	}
+
 	for old_name, new_name := range this.renames {
 		line = strings.ReplaceAll(line, old_name+",", new_name+",")
 		line = strings.ReplaceAll(line, old_name+" ", new_name+" ")
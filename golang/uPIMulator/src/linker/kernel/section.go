@@ -2,7 +2,9 @@ package kernel
 
 import (
 	"errors"
+	"strings"
 	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/linker/parser/stmt"
 )
 
 type SectionName int
@@ -48,9 +50,12 @@ type Section struct {
 	name          string
 	section_flags map[SectionFlag]bool
 	section_type  SectionType
+	alignment     int64
 
 	labels    []*Label
 	cur_label *Label
+
+	stmts []*stmt.Stmt
 }
 
 func (this *Section) Init(
@@ -63,6 +68,7 @@ func (this *Section) Init(
 	this.name = name
 	this.section_flags = section_flags
 	this.section_type = section_type
+	this.alignment = 1
 
 	default_label := new(Label)
 	default_label.Init(this.HiddenLabelName())
@@ -71,6 +77,8 @@ func (this *Section) Init(
 	this.labels = append(this.labels, default_label)
 
 	this.cur_label = default_label
+
+	this.stmts = make([]*stmt.Stmt, 0)
 }
 
 func (this *Section) SectionName() SectionName {
@@ -89,6 +97,29 @@ func (this *Section) SectionType() SectionType {
 	return this.section_type
 }
 
+// NOTE: debug information and stack sizes are not allocated in any memory of the DPU, as GNU ld
+// does for sections without the SHF_ALLOC flag; the name decides since .text sets no flags
+func (this *Section) IsLoaded() bool {
+	return this.section_name != DEBUG_ABBREV &&
+		this.section_name != DEBUG_FRAME &&
+		this.section_name != DEBUG_INFO &&
+		this.section_name != DEBUG_LINE &&
+		this.section_name != DEBUG_LOC &&
+		this.section_name != DEBUG_RANGES &&
+		this.section_name != DEBUG_STR &&
+		this.section_name != STACK_SIZES
+}
+
+func (this *Section) Alignment() int64 {
+	return this.alignment
+}
+
+func (this *Section) SetAlignment(alignment int64) {
+	if alignment > this.alignment {
+		this.alignment = alignment
+	}
+}
+
 func (this *Section) Address() int64 {
 	return this.labels[0].Address()
 }
@@ -143,6 +174,14 @@ func (this *Section) CurLabel() *Label {
 	return this.cur_label
 }
 
+func (this *Section) Stmts() []*stmt.Stmt {
+	return this.stmts
+}
+
+func (this *Section) AppendStmt(stmt_ *stmt.Stmt) {
+	this.stmts = append(this.stmts, stmt_)
+}
+
 func (this *Section) ToByteStream() *encoding.ByteStream {
 	byte_stream := new(encoding.ByteStream)
 	byte_stream.Init()
@@ -154,6 +193,10 @@ func (this *Section) ToByteStream() *encoding.ByteStream {
 	return byte_stream
 }
 
+func (this *Section) FullName() string {
+	return "." + strings.TrimSuffix(this.HiddenLabelName(), ".")
+}
+
 func (this *Section) HiddenLabelName() string {
 	if this.section_name == ATOMIC {
 		return "atomic." + this.name
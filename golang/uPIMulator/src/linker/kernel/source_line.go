@@ -0,0 +1,46 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+)
+
+type SourceLine struct {
+	path   string
+	line   int64
+	column int64
+}
+
+func (this *SourceLine) Init(path string, line int64, column int64) {
+	if line <= 0 {
+		err := errors.New("line <= 0")
+		panic(err)
+	} else if column < 0 {
+		err := errors.New("column < 0")
+		panic(err)
+	}
+
+	this.path = path
+	this.line = line
+	this.column = column
+}
+
+func (this *SourceLine) Path() string {
+	return this.path
+}
+
+func (this *SourceLine) Line() int64 {
+	return this.line
+}
+
+func (this *SourceLine) Column() int64 {
+	return this.column
+}
+
+func (this *SourceLine) Stringify() string {
+	if this.column == 0 {
+		return fmt.Sprintf("%s:%d", this.path, this.line)
+	} else {
+		return fmt.Sprintf("%s:%d:%d", this.path, this.line, this.column)
+	}
+}
@@ -0,0 +1,121 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+type SourceLineTable struct {
+	source_lines map[int64]*SourceLine
+}
+
+func (this *SourceLineTable) Init() {
+	this.source_lines = make(map[int64]*SourceLine, 0)
+}
+
+func (this *SourceLineTable) Add(address int64, source_line *SourceLine) {
+	if _, found := this.source_lines[address]; found {
+		err_msg := fmt.Sprintf("source line of address (%d) is already added", address)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.source_lines[address] = source_line
+}
+
+// NOTE: returns nil for addresses that the compiler has not attributed to a source line (e.g.,
+// hand-written assembly of the SDK)
+func (this *SourceLineTable) SourceLine(address int64) *SourceLine {
+	if source_line, found := this.source_lines[address]; found {
+		return source_line
+	} else {
+		return nil
+	}
+}
+
+func (this *SourceLineTable) Addresses() []int64 {
+	addresses := make([]int64, 0)
+	for address, _ := range this.source_lines {
+		addresses = append(addresses, address)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		return addresses[i] < addresses[j]
+	}
+
+	sort.Slice(addresses, sort_fn)
+
+	return addresses
+}
+
+func (this *SourceLineTable) Dump(path string) {
+	lines := make([]string, 0)
+
+	for _, address := range this.Addresses() {
+		source_line := this.source_lines[address]
+
+		line := fmt.Sprintf(
+			"%d: %s:%d:%d",
+			address,
+			source_line.Path(),
+			source_line.Line(),
+			source_line.Column(),
+		)
+		lines = append(lines, line)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
+// NOTE: the path is split from the right since it may contain colons
+func (this *SourceLineTable) Load(path string) {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	for _, line := range file_scanner.ReadLines() {
+		words := strings.SplitN(line, ": ", 2)
+		if len(words) != 2 {
+			err_msg := fmt.Sprintf("%s is not a source line", line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		address, address_err := strconv.ParseInt(words[0], 10, 64)
+		if address_err != nil {
+			panic(address_err)
+		}
+
+		column_index := strings.LastIndex(words[1], ":")
+		line_index := -1
+		if column_index >= 0 {
+			line_index = strings.LastIndex(words[1][:column_index], ":")
+		}
+
+		if line_index < 0 {
+			err_msg := fmt.Sprintf("%s is not a source line", line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		line_number, line_err := strconv.ParseInt(words[1][line_index+1:column_index], 10, 64)
+		if line_err != nil {
+			panic(line_err)
+		}
+
+		column, column_err := strconv.ParseInt(words[1][column_index+1:], 10, 64)
+		if column_err != nil {
+			panic(column_err)
+		}
+
+		source_line := new(SourceLine)
+		source_line.Init(words[1][:line_index], line_number, column)
+
+		this.Add(address, source_line)
+	}
+}
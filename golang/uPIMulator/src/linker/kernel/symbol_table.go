@@ -0,0 +1,129 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+type SymbolTable struct {
+	names     []string
+	addresses map[string]int64
+	is_sorted bool
+}
+
+func (this *SymbolTable) Init() {
+	this.names = make([]string, 0)
+	this.addresses = make(map[string]int64, 0)
+	this.is_sorted = true
+}
+
+// NOTE: local labels of the compiler (e.g., .LBB0_1) never name an address
+func (this *SymbolTable) Add(name string, address int64) {
+	if strings.HasPrefix(name, ".") {
+		return
+	} else if _, found := this.addresses[name]; found {
+		err_msg := fmt.Sprintf("symbol (%s) is already added", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.names = append(this.names, name)
+	this.addresses[name] = address
+	this.is_sorted = false
+}
+
+func (this *SymbolTable) Load(path string) {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	for _, line := range file_scanner.ReadLines() {
+		words := strings.Split(line, ": ")
+
+		if len(words) != 2 {
+			err_msg := fmt.Sprintf("line (%s) of %s is not valid", line, path)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		address, err := strconv.ParseInt(words[1], 10, 64)
+
+		if err != nil {
+			panic(err)
+		}
+
+		this.Add(words[0], address)
+	}
+
+	this.Sort()
+}
+
+func (this *SymbolTable) Address(name string) (int64, bool) {
+	address, found := this.addresses[name]
+	return address, found
+}
+
+// NOTE: the hidden labels that the linker gives to sections (e.g., data.buffer) share their
+// address with the first symbol of the section, which is preferred since it has no dot
+func (this *SymbolTable) Sort() {
+	if this.is_sorted {
+		return
+	}
+
+	sort_fn := func(i int, j int) bool {
+		address_i := this.addresses[this.names[i]]
+		address_j := this.addresses[this.names[j]]
+
+		if address_i != address_j {
+			return address_i < address_j
+		}
+
+		is_hidden_i := strings.Contains(this.names[i], ".")
+		is_hidden_j := strings.Contains(this.names[j], ".")
+
+		if is_hidden_i != is_hidden_j {
+			return !is_hidden_i
+		}
+		return this.names[i] < this.names[j]
+	}
+
+	sort.Slice(this.names, sort_fn)
+
+	this.is_sorted = true
+}
+
+// NOTE: returns the symbol at or below the address among the symbols in [begin_address,
+// end_address), or an empty string if there is none
+func (this *SymbolTable) Symbol(address int64, begin_address int64, end_address int64) string {
+	this.Sort()
+
+	search_fn := func(i int) bool {
+		return this.addresses[this.names[i]] > address
+	}
+
+	index := sort.Search(len(this.names), search_fn) - 1
+	if index < 0 || this.addresses[this.names[index]] < begin_address || address >= end_address {
+		return ""
+	}
+
+	for index > 0 && this.addresses[this.names[index-1]] == this.addresses[this.names[index]] {
+		index--
+	}
+
+	return this.names[index]
+}
+
+func (this *SymbolTable) Symbolize(address int64, begin_address int64, end_address int64) string {
+	symbol := this.Symbol(address, begin_address, end_address)
+
+	if symbol == "" {
+		return ""
+	} else if this.addresses[symbol] == address {
+		return symbol
+	} else {
+		return fmt.Sprintf("%s+%d", symbol, address-this.addresses[symbol])
+	}
+}
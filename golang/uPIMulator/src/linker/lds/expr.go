@@ -0,0 +1,74 @@
+package lds
+
+type ExprType int
+
+const (
+	NUMBER_EXPR ExprType = iota
+	SYMBOL_EXPR
+	UNARY_EXPR
+	BINARY_EXPR
+	TERNARY_EXPR
+	CALL_EXPR
+)
+
+type Expr struct {
+	expr_type ExprType
+
+	value    int64
+	name     string
+	operator TokenType
+	operands []*Expr
+}
+
+func (this *Expr) InitNumberExpr(value int64) {
+	this.expr_type = NUMBER_EXPR
+	this.value = value
+}
+
+func (this *Expr) InitSymbolExpr(name string) {
+	this.expr_type = SYMBOL_EXPR
+	this.name = name
+}
+
+func (this *Expr) InitUnaryExpr(operator TokenType, operand *Expr) {
+	this.expr_type = UNARY_EXPR
+	this.operator = operator
+	this.operands = []*Expr{operand}
+}
+
+func (this *Expr) InitBinaryExpr(operator TokenType, operand1 *Expr, operand2 *Expr) {
+	this.expr_type = BINARY_EXPR
+	this.operator = operator
+	this.operands = []*Expr{operand1, operand2}
+}
+
+func (this *Expr) InitTernaryExpr(condition *Expr, operand1 *Expr, operand2 *Expr) {
+	this.expr_type = TERNARY_EXPR
+	this.operands = []*Expr{condition, operand1, operand2}
+}
+
+func (this *Expr) InitCallExpr(name string, arguments []*Expr) {
+	this.expr_type = CALL_EXPR
+	this.name = name
+	this.operands = arguments
+}
+
+func (this *Expr) ExprType() ExprType {
+	return this.expr_type
+}
+
+func (this *Expr) Value() int64 {
+	return this.value
+}
+
+func (this *Expr) Name() string {
+	return this.name
+}
+
+func (this *Expr) Operator() TokenType {
+	return this.operator
+}
+
+func (this *Expr) Operands() []*Expr {
+	return this.operands
+}
@@ -0,0 +1,202 @@
+package lds
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Lexer struct {
+	path   string
+	source string
+	pos    int
+	line   int
+}
+
+func (this *Lexer) Init() {
+}
+
+func (this *Lexer) Lex(path string) []*Token {
+	source, read_err := os.ReadFile(path)
+
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	return this.LexString(path, string(source))
+}
+
+func (this *Lexer) LexString(path string, source string) []*Token {
+	this.path = path
+	this.source = source
+	this.pos = 0
+	this.line = 1
+
+	tokens := make([]*Token, 0)
+	for {
+		token := this.NextToken()
+		tokens = append(tokens, token)
+
+		if token.TokenType() == END_OF_FILE {
+			return tokens
+		}
+	}
+}
+
+func (this *Lexer) NextToken() *Token {
+	this.SkipWhiteSpacesAndComments()
+
+	token := new(Token)
+
+	if this.pos >= len(this.source) {
+		token.Init(END_OF_FILE, "", this.line)
+		return token
+	}
+
+	c := this.source[this.pos]
+
+	if this.IsDigit(c) {
+		begin := this.pos
+		for this.pos < len(this.source) && this.IsNameCharacter(this.source[this.pos]) {
+			this.pos++
+		}
+
+		token.Init(NUMBER, this.source[begin:this.pos], this.line)
+		return token
+	} else if this.IsNameBeginCharacter(c) {
+		if c == '*' && this.Peek(1) == '=' {
+			this.pos += 2
+			token.Init(MUL_ASSIGN, "*=", this.line)
+			return token
+		}
+
+		begin := this.pos
+		for this.pos < len(this.source) && this.IsNameCharacter(this.source[this.pos]) {
+			this.pos++
+		}
+
+		token.Init(NAME, this.source[begin:this.pos], this.line)
+		return token
+	} else if c == '"' {
+		end := strings.IndexByte(this.source[this.pos+1:], '"')
+
+		if end < 0 {
+			err := this.Error("string is not terminated")
+			panic(err)
+		}
+
+		attribute := this.source[this.pos+1 : this.pos+1+end]
+		this.line += strings.Count(attribute, "\n")
+		this.pos += end + 2
+
+		token.Init(STRING, attribute, this.line)
+		return token
+	}
+
+	operators := []struct {
+		attribute  string
+		token_type TokenType
+	}{
+		{"||", OR_OR},
+		{"&&", AND_AND},
+		{"==", EQ},
+		{"!=", NE},
+		{"<=", LE},
+		{">=", GE},
+		{"<<", SHL},
+		{">>", SHR},
+		{"+=", ADD_ASSIGN},
+		{"-=", SUB_ASSIGN},
+		{"/=", DIV_ASSIGN},
+		{"{", LEFT_BRACE},
+		{"}", RIGHT_BRACE},
+		{"(", LEFT_PAREN},
+		{")", RIGHT_PAREN},
+		{";", SEMICOLON},
+		{":", COLON},
+		{",", COMMA},
+		{"?", QUESTION},
+		{"=", ASSIGN},
+		{"|", OR},
+		{"&", AND},
+		{"<", LT},
+		{">", GT},
+		{"+", PLUS},
+		{"-", MINUS},
+		{"/", DIV},
+		{"%", MOD},
+		{"!", NOT},
+		{"~", TILDE},
+	}
+
+	for _, operator := range operators {
+		if strings.HasPrefix(this.source[this.pos:], operator.attribute) {
+			this.pos += len(operator.attribute)
+			token.Init(operator.token_type, operator.attribute, this.line)
+			return token
+		}
+	}
+
+	err := this.Error(fmt.Sprintf("character (%c) is not valid", c))
+	panic(err)
+}
+
+func (this *Lexer) SkipWhiteSpacesAndComments() {
+	for this.pos < len(this.source) {
+		c := this.source[this.pos]
+
+		if c == '\n' {
+			this.line++
+			this.pos++
+		} else if c == ' ' || c == '\t' || c == '\r' {
+			this.pos++
+		} else if c == '/' && this.Peek(1) == '*' {
+			end := strings.Index(this.source[this.pos+2:], "*/")
+
+			if end < 0 {
+				err := this.Error("comment is not terminated")
+				panic(err)
+			}
+
+			this.line += strings.Count(this.source[this.pos:this.pos+2+end], "\n")
+			this.pos += end + 4
+		} else {
+			return
+		}
+	}
+}
+
+func (this *Lexer) Peek(offset int) byte {
+	if this.pos+offset < len(this.source) {
+		return this.source[this.pos+offset]
+	} else {
+		return 0
+	}
+}
+
+func (this *Lexer) IsDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func (this *Lexer) IsNameBeginCharacter(c byte) bool {
+	return ('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z') ||
+		c == '_' ||
+		c == '.' ||
+		c == '$' ||
+		c == '*'
+}
+
+func (this *Lexer) IsNameCharacter(c byte) bool {
+	return this.IsNameBeginCharacter(c) ||
+		this.IsDigit(c) ||
+		c == '?' ||
+		c == '[' ||
+		c == ']'
+}
+
+func (this *Lexer) Error(msg string) error {
+	err_msg := fmt.Sprintf("%s:%d: %s", this.path, this.line, msg)
+	return errors.New(err_msg)
+}
@@ -0,0 +1,437 @@
+package lds
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NOTE: the parser accepts the subset of the GNU ld script syntax that UPMEM's linker scripts use:
+// ENTRY, MEMORY, SECTIONS, symbol assignments, PROVIDE, HIDDEN, ASSERT, KEEP and input section
+// descriptions with wildcards
+type Parser struct {
+	path   string
+	tokens []*Token
+	pos    int
+}
+
+func (this *Parser) Init() {
+}
+
+func (this *Parser) Parse(path string) *Script {
+	lexer := new(Lexer)
+	lexer.Init()
+
+	return this.ParseTokens(path, lexer.Lex(path))
+}
+
+func (this *Parser) ParseTokens(path string, tokens []*Token) *Script {
+	this.path = path
+	this.tokens = tokens
+	this.pos = 0
+
+	script := new(Script)
+	script.Init(path)
+
+	for this.Peek().TokenType() != END_OF_FILE {
+		token := this.Peek()
+
+		if token.IsName("ENTRY") {
+			this.Next()
+			this.Expect(LEFT_PAREN)
+			script.SetEntry(this.Expect(NAME).Attribute())
+			this.Expect(RIGHT_PAREN)
+		} else if token.IsName("MEMORY") {
+			this.Next()
+			this.ParseMemory(script)
+		} else if token.IsName("SECTIONS") {
+			this.Next()
+			this.ParseSections(script)
+		} else if token.TokenType() == SEMICOLON {
+			this.Next()
+		} else {
+			script.AppendCommand(this.ParseCommand())
+		}
+	}
+
+	return script
+}
+
+func (this *Parser) ParseMemory(script *Script) {
+	this.Expect(LEFT_BRACE)
+
+	for this.Peek().TokenType() != RIGHT_BRACE {
+		name := this.Expect(NAME).Attribute()
+
+		attributes := ""
+		if this.Peek().TokenType() == LEFT_PAREN {
+			this.Next()
+			for this.Peek().TokenType() != RIGHT_PAREN {
+				attributes += this.Next().Attribute()
+			}
+			this.Next()
+		}
+
+		this.Expect(COLON)
+		this.ExpectName("ORIGIN", "org", "o")
+		this.Expect(ASSIGN)
+		origin := this.ParseExpr()
+
+		this.Expect(COMMA)
+		this.ExpectName("LENGTH", "len", "l")
+		this.Expect(ASSIGN)
+		length := this.ParseExpr()
+
+		memory_region := new(MemoryRegion)
+		memory_region.Init(name, attributes, origin, length)
+		script.AppendMemoryRegion(memory_region)
+	}
+
+	this.Expect(RIGHT_BRACE)
+}
+
+func (this *Parser) ParseSections(script *Script) {
+	this.Expect(LEFT_BRACE)
+
+	for this.Peek().TokenType() != RIGHT_BRACE {
+		token := this.Peek()
+
+		if token.TokenType() == SEMICOLON {
+			this.Next()
+		} else if this.IsCommand() {
+			script.AppendCommand(this.ParseCommand())
+		} else {
+			script.AppendOutputSection(this.ParseOutputSection())
+		}
+	}
+
+	this.Expect(RIGHT_BRACE)
+}
+
+func (this *Parser) ParseOutputSection() *OutputSection {
+	token := this.Expect(NAME)
+
+	var address *Expr = nil
+	is_noload := false
+
+	if this.Peek().TokenType() == LEFT_PAREN && this.PeekAt(1).IsName("NOLOAD") {
+		this.Next()
+		this.Next()
+		this.Expect(RIGHT_PAREN)
+		is_noload = true
+	} else if this.Peek().TokenType() != COLON {
+		address = this.ParseExpr()
+
+		if this.Peek().TokenType() == LEFT_PAREN && this.PeekAt(1).IsName("NOLOAD") {
+			this.Next()
+			this.Next()
+			this.Expect(RIGHT_PAREN)
+			is_noload = true
+		}
+	}
+
+	this.Expect(COLON)
+	this.Expect(LEFT_BRACE)
+
+	commands := make([]*Command, 0)
+	for this.Peek().TokenType() != RIGHT_BRACE {
+		if this.Peek().TokenType() == SEMICOLON {
+			this.Next()
+		} else if this.IsCommand() {
+			commands = append(commands, this.ParseCommand())
+		} else {
+			commands = append(commands, this.ParseInputSectionCommand())
+		}
+	}
+
+	this.Expect(RIGHT_BRACE)
+
+	region := ""
+	if this.Peek().TokenType() == GT {
+		this.Next()
+		region = this.Expect(NAME).Attribute()
+	}
+
+	output_section := new(OutputSection)
+	output_section.Init(token.Attribute(), address, is_noload, commands, region, token.Line())
+	return output_section
+}
+
+func (this *Parser) IsCommand() bool {
+	token := this.Peek()
+
+	if token.IsName("ASSERT") ||
+		token.IsName("PROVIDE") ||
+		token.IsName("HIDDEN") ||
+		token.IsName("PROVIDE_HIDDEN") {
+		return true
+	}
+
+	next_token_type := this.PeekAt(1).TokenType()
+
+	return token.TokenType() == NAME &&
+		(next_token_type == ASSIGN ||
+			next_token_type == ADD_ASSIGN ||
+			next_token_type == SUB_ASSIGN ||
+			next_token_type == MUL_ASSIGN ||
+			next_token_type == DIV_ASSIGN)
+}
+
+func (this *Parser) ParseCommand() *Command {
+	token := this.Peek()
+
+	command := new(Command)
+
+	if token.IsName("ASSERT") {
+		this.Next()
+		this.Expect(LEFT_PAREN)
+		expr := this.ParseExpr()
+		this.Expect(COMMA)
+		message := this.Expect(STRING).Attribute()
+		this.Expect(RIGHT_PAREN)
+
+		command.InitAssertCommand(expr, message, token.Line())
+	} else if token.IsName("PROVIDE") || token.IsName("HIDDEN") || token.IsName("PROVIDE_HIDDEN") {
+		this.Next()
+		this.Expect(LEFT_PAREN)
+		name := this.Expect(NAME).Attribute()
+		this.Expect(ASSIGN)
+		expr := this.ParseExpr()
+		this.Expect(RIGHT_PAREN)
+
+		is_provided := token.Attribute() != "HIDDEN"
+		is_hidden := token.Attribute() != "PROVIDE"
+
+		command.InitAssignmentCommand(name, ASSIGN, expr, is_provided, is_hidden, token.Line())
+	} else {
+		name := this.Expect(NAME).Attribute()
+		operator := this.Next().TokenType()
+		expr := this.ParseExpr()
+
+		command.InitAssignmentCommand(name, operator, expr, false, false, token.Line())
+	}
+
+	if this.Peek().TokenType() == SEMICOLON {
+		this.Next()
+	}
+
+	return command
+}
+
+func (this *Parser) ParseInputSectionCommand() *Command {
+	token := this.Peek()
+
+	is_kept := false
+	if token.IsName("KEEP") {
+		this.Next()
+		this.Expect(LEFT_PAREN)
+		is_kept = true
+	}
+
+	file_pattern := this.Expect(NAME).Attribute()
+
+	section_patterns := make([]string, 0)
+	this.Expect(LEFT_PAREN)
+	for this.Peek().TokenType() != RIGHT_PAREN {
+		section_patterns = append(section_patterns, this.Expect(NAME).Attribute())
+	}
+	this.Expect(RIGHT_PAREN)
+
+	if is_kept {
+		this.Expect(RIGHT_PAREN)
+	}
+
+	command := new(Command)
+	command.InitInputSectionCommand(file_pattern, section_patterns, is_kept, token.Line())
+	return command
+}
+
+func (this *Parser) ParseExpr() *Expr {
+	condition := this.ParseBinaryExpr(0)
+
+	if this.Peek().TokenType() != QUESTION {
+		return condition
+	}
+
+	this.Next()
+	operand1 := this.ParseExpr()
+	this.Expect(COLON)
+	operand2 := this.ParseExpr()
+
+	expr := new(Expr)
+	expr.InitTernaryExpr(condition, operand1, operand2)
+	return expr
+}
+
+func (this *Parser) BinaryOperators() [][]TokenType {
+	return [][]TokenType{
+		{OR_OR},
+		{AND_AND},
+		{OR},
+		{AND},
+		{EQ, NE},
+		{LT, LE, GT, GE},
+		{SHL, SHR},
+		{PLUS, MINUS},
+		{MUL, DIV, MOD},
+	}
+}
+
+func (this *Parser) ParseBinaryExpr(precedence int) *Expr {
+	binary_operators := this.BinaryOperators()
+
+	if precedence == len(binary_operators) {
+		return this.ParseUnaryExpr()
+	}
+
+	expr := this.ParseBinaryExpr(precedence + 1)
+
+	for {
+		operator, found := this.PeekBinaryOperator(binary_operators[precedence])
+
+		if !found {
+			return expr
+		}
+
+		this.Next()
+
+		binary_expr := new(Expr)
+		binary_expr.InitBinaryExpr(operator, expr, this.ParseBinaryExpr(precedence+1))
+		expr = binary_expr
+	}
+}
+
+func (this *Parser) PeekBinaryOperator(operators []TokenType) (TokenType, bool) {
+	token := this.Peek()
+
+	for _, operator := range operators {
+		if operator == MUL && token.IsName("*") {
+			return MUL, true
+		} else if token.TokenType() == operator {
+			return operator, true
+		}
+	}
+
+	return END_OF_FILE, false
+}
+
+func (this *Parser) ParseUnaryExpr() *Expr {
+	token := this.Peek()
+
+	if token.TokenType() == MINUS || token.TokenType() == NOT || token.TokenType() == TILDE {
+		this.Next()
+
+		expr := new(Expr)
+		expr.InitUnaryExpr(token.TokenType(), this.ParseUnaryExpr())
+		return expr
+	}
+
+	return this.ParsePrimaryExpr()
+}
+
+func (this *Parser) ParsePrimaryExpr() *Expr {
+	token := this.Next()
+
+	expr := new(Expr)
+
+	if token.TokenType() == NUMBER {
+		expr.InitNumberExpr(this.ParseNumber(token))
+	} else if token.TokenType() == LEFT_PAREN {
+		expr = this.ParseExpr()
+		this.Expect(RIGHT_PAREN)
+	} else if token.TokenType() == NAME && this.Peek().TokenType() == LEFT_PAREN {
+		this.Next()
+
+		arguments := make([]*Expr, 0)
+		for this.Peek().TokenType() != RIGHT_PAREN {
+			arguments = append(arguments, this.ParseExpr())
+
+			if this.Peek().TokenType() == COMMA {
+				this.Next()
+			}
+		}
+		this.Expect(RIGHT_PAREN)
+
+		expr.InitCallExpr(token.Attribute(), arguments)
+	} else if token.TokenType() == NAME {
+		expr.InitSymbolExpr(token.Attribute())
+	} else {
+		err := this.Error(token, fmt.Sprintf("expression cannot start with (%s)", token.Attribute()))
+		panic(err)
+	}
+
+	return expr
+}
+
+func (this *Parser) ParseNumber(token *Token) int64 {
+	attribute := token.Attribute()
+
+	multiplier := int64(1)
+	if strings.HasSuffix(attribute, "K") || strings.HasSuffix(attribute, "k") {
+		multiplier = 1024
+		attribute = attribute[:len(attribute)-1]
+	} else if strings.HasSuffix(attribute, "M") || strings.HasSuffix(attribute, "m") {
+		multiplier = 1024 * 1024
+		attribute = attribute[:len(attribute)-1]
+	}
+
+	value, err := strconv.ParseInt(attribute, 0, 64)
+
+	if err != nil {
+		panic(this.Error(token, fmt.Sprintf("number (%s) is not valid", token.Attribute())))
+	}
+
+	return value * multiplier
+}
+
+func (this *Parser) Peek() *Token {
+	return this.PeekAt(0)
+}
+
+func (this *Parser) PeekAt(offset int) *Token {
+	if this.pos+offset < len(this.tokens) {
+		return this.tokens[this.pos+offset]
+	} else {
+		return this.tokens[len(this.tokens)-1]
+	}
+}
+
+func (this *Parser) Next() *Token {
+	token := this.Peek()
+
+	if token.TokenType() == END_OF_FILE {
+		panic(this.Error(token, "unexpected end of file"))
+	}
+
+	this.pos++
+	return token
+}
+
+func (this *Parser) Expect(token_type TokenType) *Token {
+	token := this.Peek()
+
+	if token.TokenType() != token_type {
+		panic(this.Error(token, fmt.Sprintf("unexpected (%s)", token.Attribute())))
+	}
+
+	return this.Next()
+}
+
+func (this *Parser) ExpectName(attributes ...string) *Token {
+	token := this.Peek()
+
+	for _, attribute := range attributes {
+		if token.IsName(attribute) {
+			return this.Next()
+		}
+	}
+
+	err_msg := fmt.Sprintf("expected (%s) but found (%s)", strings.Join(attributes, "|"), token.Attribute())
+	panic(this.Error(token, err_msg))
+}
+
+func (this *Parser) Error(token *Token, msg string) error {
+	err_msg := fmt.Sprintf("%s:%d: %s", this.path, token.Line(), msg)
+	return errors.New(err_msg)
+}
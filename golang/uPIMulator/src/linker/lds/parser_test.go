@@ -0,0 +1,238 @@
+package lds
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func isPanicking(function func()) (is_panicking bool) {
+	defer func() {
+		if recover() != nil {
+			is_panicking = true
+		}
+	}()
+
+	function()
+	return false
+}
+
+func parseString(source string) *Script {
+	lexer := new(Lexer)
+	lexer.Init()
+
+	parser := new(Parser)
+	parser.Init()
+
+	return parser.ParseTokens("test.lds", lexer.LexString("test.lds", source))
+}
+
+func TestParseScript(t *testing.T) {
+	script := parseString(`
+		ENTRY(__bootstrap)
+
+		MEMORY {
+			iram (rx) : ORIGIN = 0x80000000, LENGTH = 4K
+			wram (rw!x) : org = 0, len = 64K
+		}
+
+		/* a comment */
+		SECTIONS {
+			__stack_size = 1024;
+
+			.text : {
+				*(.text.__bootstrap)
+				*(.text .text.*)
+			} > iram
+
+			.data (NOLOAD) : {
+				KEEP(*(.data.__sys_zero))
+				. += MAX(8, .);
+				HIDDEN(__imm_mem_end = .);
+				ASSERT(__imm_mem_end < 2048, "too far")
+				PROVIDE(__sys_heap_pointer_reset = .);
+			} > wram
+		}
+	`)
+
+	if script.Entry() != "__bootstrap" {
+		t.Errorf("entry is %s", script.Entry())
+	}
+
+	memory_regions := script.MemoryRegions()
+	if len(memory_regions) != 2 {
+		t.Fatalf("%d memory regions are parsed", len(memory_regions))
+	}
+	if memory_regions[0].Name() != "iram" || memory_regions[0].Attributes() != "rx" ||
+		memory_regions[0].Origin().Value() != 0x80000000 || memory_regions[0].Length().Value() != 4096 {
+		t.Errorf("iram region is not parsed")
+	}
+	if memory_regions[1].Name() != "wram" || memory_regions[1].Attributes() != "rw!x" ||
+		memory_regions[1].Origin().Value() != 0 || memory_regions[1].Length().Value() != 65536 {
+		t.Errorf("wram region is not parsed")
+	}
+
+	if len(script.Commands()) != 1 || script.Commands()[0].Name() != "__stack_size" {
+		t.Errorf("top-level commands are not parsed")
+	}
+
+	output_sections := script.OutputSections()
+	if len(output_sections) != 2 {
+		t.Fatalf("%d output sections are parsed", len(output_sections))
+	}
+
+	text := output_sections[0]
+	if text.Name() != ".text" || text.IsNoload() || text.Region() != "iram" ||
+		len(text.Commands()) != 2 {
+		t.Errorf(".text is not parsed")
+	}
+	patterns := text.Commands()[1].SectionPatterns()
+	if !slices.Equal(patterns, []string{".text", ".text.*"}) {
+		t.Errorf("section patterns of .text are %v", patterns)
+	}
+
+	data := output_sections[1]
+	if data.Name() != ".data" || !data.IsNoload() || data.Region() != "wram" ||
+		len(data.Commands()) != 5 {
+		t.Fatalf(".data is not parsed")
+	}
+
+	keep := data.Commands()[0]
+	if keep.CommandType() != INPUT_SECTION_COMMAND || !keep.IsKept() || keep.FilePattern() != "*" {
+		t.Errorf("KEEP is not parsed")
+	}
+
+	dot := data.Commands()[1]
+	if dot.Name() != "." || dot.Operator() != ADD_ASSIGN || dot.Expr().ExprType() != CALL_EXPR ||
+		dot.Expr().Name() != "MAX" || len(dot.Expr().Operands()) != 2 {
+		t.Errorf(". += MAX(8, .) is not parsed")
+	}
+
+	hidden := data.Commands()[2]
+	if !hidden.IsHidden() || hidden.IsProvided() {
+		t.Errorf("HIDDEN is not parsed")
+	}
+
+	assert := data.Commands()[3]
+	if assert.CommandType() != ASSERT_COMMAND || assert.Message() != "too far" ||
+		assert.Expr().Operator() != LT {
+		t.Errorf("ASSERT is not parsed")
+	}
+
+	provide := data.Commands()[4]
+	if !provide.IsProvided() || provide.IsHidden() || provide.Line() != 23 {
+		t.Errorf("PROVIDE is not parsed")
+	}
+
+	symbols := script.Symbols()
+	if !slices.Equal(symbols, []string{"__stack_size", "__sys_heap_pointer_reset"}) {
+		t.Errorf("symbols are %v", symbols)
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	script := parseString(
+		"x = 1 + 2 * 3 - 4; y = a ? b : c; z = -(1 << 4) | ~0; w = 1 == 2 && 3 != 4;",
+	)
+
+	x := script.Commands()[0].Expr()
+	if x.Operator() != MINUS || x.Operands()[0].Operator() != PLUS ||
+		x.Operands()[0].Operands()[1].Operator() != MUL {
+		t.Errorf("precedence of + - * is not respected")
+	}
+
+	y := script.Commands()[1].Expr()
+	if y.ExprType() != TERNARY_EXPR || y.Operands()[2].Name() != "c" {
+		t.Errorf("ternary expression is not parsed")
+	}
+
+	z := script.Commands()[2].Expr()
+	if z.Operator() != OR || z.Operands()[0].ExprType() != UNARY_EXPR ||
+		z.Operands()[0].Operands()[0].Operator() != SHL || z.Operands()[1].Operator() != TILDE {
+		t.Errorf("unary expressions are not parsed")
+	}
+
+	w := script.Commands()[3].Expr()
+	if w.Operator() != AND_AND || w.Operands()[0].Operator() != EQ ||
+		w.Operands()[1].Operator() != NE {
+		t.Errorf("precedence of && == != is not respected")
+	}
+}
+
+func TestParseNumbers(t *testing.T) {
+	numbers := map[string]int64{
+		"42":     42,
+		"0x10":   16,
+		"0X1f":   31,
+		"010":    8,
+		"2K":     2048,
+		"1m":     1024 * 1024,
+		"0x10k":  16 * 1024,
+		"64M":    64 * 1024 * 1024,
+		"0":      0,
+		"0x8000": 32768,
+	}
+
+	for number, expected := range numbers {
+		value := parseString("x = " + number + ";").Commands()[0].Expr().Value()
+
+		if value != expected {
+			t.Errorf("%s is parsed as %d", number, value)
+		}
+	}
+}
+
+func TestParseDpuLds(t *testing.T) {
+	parser := new(Parser)
+	parser.Init()
+
+	script := parser.Parse(filepath.Join("..", "..", "..", "sdk", "misc", "dpu.lds"))
+
+	if script.Entry() != "__bootstrap" {
+		t.Errorf("entry is %s", script.Entry())
+	}
+
+	region_names := make([]string, 0)
+	for _, memory_region := range script.MemoryRegions() {
+		region_names = append(region_names, memory_region.Name())
+	}
+	for _, region_name := range []string{"iram", "wram", "mram", "atomic"} {
+		if !slices.Contains(region_names, region_name) {
+			t.Errorf("memory region (%s) is missing", region_name)
+		}
+	}
+
+	for _, symbol := range []string{
+		"__atomic_used_addr",
+		"__sys_heap_pointer_reset",
+		"__sys_used_mram_end",
+		"__sw_cache_buffer",
+	} {
+		if !slices.Contains(script.Symbols(), symbol) {
+			t.Errorf("symbol (%s) is missing", symbol)
+		}
+	}
+}
+
+func TestInvalidScripts(t *testing.T) {
+	scripts := map[string]string{
+		"unterminated ENTRY":       "ENTRY(__bootstrap",
+		"MEMORY without ORIGIN":    "MEMORY { iram : LENGTH = 4K }",
+		"MEMORY without LENGTH":    "MEMORY { iram : ORIGIN = 0 }",
+		"unterminated SECTIONS":    "SECTIONS { .text : { *(.text) }",
+		"output section without :": "SECTIONS { .text { *(.text) } }",
+		"ASSERT without message":   "SECTIONS { .data : { ASSERT(. < 8) } }",
+		"unterminated KEEP":        "SECTIONS { .data : { KEEP(*(.data) } }",
+		"invalid number":           "x = 0x;",
+		"missing operand":          "x = 1 + ;",
+		"unterminated expression":  "x = (1 + 2;",
+		"unterminated comment":     "/* x = 1;",
+		"unterminated string":      "SECTIONS { .data : { ASSERT(1, \"x) } }",
+	}
+
+	for name, source := range scripts {
+		if !isPanicking(func() { parseString(source) }) {
+			t.Errorf("%s is accepted", name)
+		}
+	}
+}
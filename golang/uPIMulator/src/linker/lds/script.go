@@ -0,0 +1,263 @@
+package lds
+
+type CommandType int
+
+const (
+	ASSIGNMENT_COMMAND CommandType = iota
+	INPUT_SECTION_COMMAND
+	ASSERT_COMMAND
+)
+
+type Command struct {
+	command_type CommandType
+	line         int
+
+	name        string
+	operator    TokenType
+	expr        *Expr
+	is_provided bool
+	is_hidden   bool
+
+	file_pattern     string
+	section_patterns []string
+	is_kept          bool
+
+	message string
+}
+
+func (this *Command) InitAssignmentCommand(
+	name string,
+	operator TokenType,
+	expr *Expr,
+	is_provided bool,
+	is_hidden bool,
+	line int,
+) {
+	this.command_type = ASSIGNMENT_COMMAND
+	this.name = name
+	this.operator = operator
+	this.expr = expr
+	this.is_provided = is_provided
+	this.is_hidden = is_hidden
+	this.line = line
+}
+
+func (this *Command) InitInputSectionCommand(
+	file_pattern string,
+	section_patterns []string,
+	is_kept bool,
+	line int,
+) {
+	this.command_type = INPUT_SECTION_COMMAND
+	this.file_pattern = file_pattern
+	this.section_patterns = section_patterns
+	this.is_kept = is_kept
+	this.line = line
+}
+
+func (this *Command) InitAssertCommand(expr *Expr, message string, line int) {
+	this.command_type = ASSERT_COMMAND
+	this.expr = expr
+	this.message = message
+	this.line = line
+}
+
+func (this *Command) CommandType() CommandType {
+	return this.command_type
+}
+
+func (this *Command) Line() int {
+	return this.line
+}
+
+func (this *Command) Name() string {
+	return this.name
+}
+
+func (this *Command) Operator() TokenType {
+	return this.operator
+}
+
+func (this *Command) Expr() *Expr {
+	return this.expr
+}
+
+func (this *Command) IsProvided() bool {
+	return this.is_provided
+}
+
+func (this *Command) IsHidden() bool {
+	return this.is_hidden
+}
+
+func (this *Command) FilePattern() string {
+	return this.file_pattern
+}
+
+func (this *Command) SectionPatterns() []string {
+	return this.section_patterns
+}
+
+func (this *Command) IsKept() bool {
+	return this.is_kept
+}
+
+func (this *Command) Message() string {
+	return this.message
+}
+
+type MemoryRegion struct {
+	name       string
+	attributes string
+	origin     *Expr
+	length     *Expr
+}
+
+func (this *MemoryRegion) Init(name string, attributes string, origin *Expr, length *Expr) {
+	this.name = name
+	this.attributes = attributes
+	this.origin = origin
+	this.length = length
+}
+
+func (this *MemoryRegion) Name() string {
+	return this.name
+}
+
+func (this *MemoryRegion) Attributes() string {
+	return this.attributes
+}
+
+func (this *MemoryRegion) Origin() *Expr {
+	return this.origin
+}
+
+func (this *MemoryRegion) Length() *Expr {
+	return this.length
+}
+
+type OutputSection struct {
+	name      string
+	address   *Expr
+	is_noload bool
+	commands  []*Command
+	region    string
+	line      int
+}
+
+func (this *OutputSection) Init(
+	name string,
+	address *Expr,
+	is_noload bool,
+	commands []*Command,
+	region string,
+	line int,
+) {
+	this.name = name
+	this.address = address
+	this.is_noload = is_noload
+	this.commands = commands
+	this.region = region
+	this.line = line
+}
+
+func (this *OutputSection) Name() string {
+	return this.name
+}
+
+func (this *OutputSection) Address() *Expr {
+	return this.address
+}
+
+func (this *OutputSection) IsNoload() bool {
+	return this.is_noload
+}
+
+func (this *OutputSection) Commands() []*Command {
+	return this.commands
+}
+
+func (this *OutputSection) Region() string {
+	return this.region
+}
+
+func (this *OutputSection) Line() int {
+	return this.line
+}
+
+// NOTE: top-level commands are evaluated before any output section, which is how dpu.lds
+// uses them (e.g., to override STACK_SIZE_TASKLET_<n>)
+type Script struct {
+	path            string
+	entry           string
+	memory_regions  []*MemoryRegion
+	commands        []*Command
+	output_sections []*OutputSection
+}
+
+func (this *Script) Init(path string) {
+	this.path = path
+	this.entry = ""
+	this.memory_regions = make([]*MemoryRegion, 0)
+	this.commands = make([]*Command, 0)
+	this.output_sections = make([]*OutputSection, 0)
+}
+
+func (this *Script) Path() string {
+	return this.path
+}
+
+func (this *Script) Entry() string {
+	return this.entry
+}
+
+func (this *Script) SetEntry(entry string) {
+	this.entry = entry
+}
+
+func (this *Script) MemoryRegions() []*MemoryRegion {
+	return this.memory_regions
+}
+
+func (this *Script) AppendMemoryRegion(memory_region *MemoryRegion) {
+	this.memory_regions = append(this.memory_regions, memory_region)
+}
+
+func (this *Script) Commands() []*Command {
+	return this.commands
+}
+
+func (this *Script) AppendCommand(command *Command) {
+	this.commands = append(this.commands, command)
+}
+
+func (this *Script) OutputSections() []*OutputSection {
+	return this.output_sections
+}
+
+func (this *Script) AppendOutputSection(output_section *OutputSection) {
+	this.output_sections = append(this.output_sections, output_section)
+}
+
+// NOTE: symbols assigned by the script, except the HIDDEN ones, are visible to relocatables
+func (this *Script) Symbols() []string {
+	symbols := make([]string, 0)
+
+	append_fn := func(command *Command) {
+		if command.CommandType() == ASSIGNMENT_COMMAND && command.Name() != "." && !command.IsHidden() {
+			symbols = append(symbols, command.Name())
+		}
+	}
+
+	for _, command := range this.commands {
+		append_fn(command)
+	}
+
+	for _, output_section := range this.output_sections {
+		for _, command := range output_section.Commands() {
+			append_fn(command)
+		}
+	}
+
+	return symbols
+}
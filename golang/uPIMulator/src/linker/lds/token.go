@@ -0,0 +1,76 @@
+package lds
+
+type TokenType int
+
+const (
+	NAME TokenType = iota
+	NUMBER
+	STRING
+
+	LEFT_BRACE
+	RIGHT_BRACE
+	LEFT_PAREN
+	RIGHT_PAREN
+	SEMICOLON
+	COLON
+	COMMA
+	QUESTION
+
+	ASSIGN
+	ADD_ASSIGN
+	SUB_ASSIGN
+	MUL_ASSIGN
+	DIV_ASSIGN
+
+	OR_OR
+	AND_AND
+	OR
+	AND
+	EQ
+	NE
+	LT
+	LE
+	GT
+	GE
+	SHL
+	SHR
+	PLUS
+	MINUS
+	MUL
+	DIV
+	MOD
+	NOT
+	TILDE
+
+	END_OF_FILE
+)
+
+type Token struct {
+	token_type TokenType
+	attribute  string
+	line       int
+}
+
+func (this *Token) Init(token_type TokenType, attribute string, line int) {
+	this.token_type = token_type
+	this.attribute = attribute
+	this.line = line
+}
+
+func (this *Token) TokenType() TokenType {
+	return this.token_type
+}
+
+func (this *Token) Attribute() string {
+	return this.attribute
+}
+
+func (this *Token) Line() int {
+	return this.line
+}
+
+// NOTE: "*" is lexed as a name since it is a wildcard in input section descriptions,
+// so the parser reads a lone "*" name as MUL inside expressions
+func (this *Token) IsName(attribute string) bool {
+	return this.token_type == NAME && this.attribute == attribute
+}
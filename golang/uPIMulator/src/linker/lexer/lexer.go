@@ -2,11 +2,15 @@ package lexer
 
 import (
 	"errors"
+	"fmt"
 	"uPIMulator/src/misc"
 )
 
 type Lexer struct {
 	tokenizer *Tokenizer
+
+	path        string
+	line_number int
 }
 
 func (this *Lexer) Init() {
@@ -18,14 +22,21 @@ func (this *Lexer) Lex(path string) *TokenStream {
 	file_scanner := new(misc.FileScanner)
 	file_scanner.Init(path)
 
+	this.path = path
+
 	token_stream := new(TokenStream)
 	token_stream.Init()
+	token_stream.SetPath(path)
+
+	for i, line := range file_scanner.ReadLines() {
+		this.line_number = i + 1
 
-	for _, line := range file_scanner.ReadLines() {
 		token_stream.Merge(this.Tokenize(line))
 
 		new_line := new(Token)
 		new_line.Init(NEW_LINE, "")
+		new_line.SetLine(this.line_number)
+		new_line.SetColumn(len(line) + 1)
 
 		token_stream.Append(new_line)
 	}
@@ -47,6 +58,10 @@ func (this *Lexer) Tokenize(line string) *TokenStream {
 		token, length := this.FindTokenWithMaxLength(line, prev_pos)
 
 		if token != nil {
+			token.SetText(line[prev_pos : prev_pos+length])
+			token.SetLine(this.line_number)
+			token.SetColumn(prev_pos + 1)
+
 			token_stream.Append(token)
 		}
 
@@ -93,6 +108,18 @@ func (this *Lexer) FindTokenWithMaxLength(line string, prev_pos int) (*Token, in
 				continue
 			}
 		} else {
+			if !this.tokenizer.IsTokenizable(word) {
+				err_msg := fmt.Sprintf(
+					"%s:%d:%d: %q is not a token",
+					this.path,
+					this.line_number,
+					prev_pos+1,
+					word,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
 			token := this.tokenizer.Tokenize(word)
 			return token, i - prev_pos
 		}
@@ -0,0 +1,50 @@
+package lexer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLexerRejectsUnknownToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad_token.S")
+	if err := os.WriteFile(path, []byte("\tmove r0, 42\n\tmove r0, 42 `\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	message := func() (message string) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				message = fmt.Sprint(recovered)
+			}
+		}()
+
+		lexer := new(Lexer)
+		lexer.Init()
+		lexer.Lex(path)
+		return ""
+	}()
+
+	if expected := fmt.Sprintf("%s:2:14: \"`\" is not a token", path); message != expected {
+		t.Errorf("error is %q, but %q is expected", message, expected)
+	}
+}
+
+func TestLexerTokenText(t *testing.T) {
+	lexer := new(Lexer)
+	lexer.Init()
+
+	token_stream := lexer.Tokenize("\tmove r0, 42")
+
+	texts := []string{"move", "r0", ",", "42"}
+	if token_stream.Size() != len(texts) {
+		t.Fatalf("%d tokens are lexed", token_stream.Size())
+	}
+
+	for i, text := range texts {
+		if token := token_stream.Get(i); token.Text() != text {
+			t.Errorf("token %d is %q, but %q is expected", i, token.Text(), text)
+		}
+	}
+}
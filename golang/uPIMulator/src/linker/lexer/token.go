@@ -252,6 +252,9 @@ const (
 type Token struct {
 	token_type TokenType
 	attribute  string
+	text       string
+	line       int
+	column     int
 }
 
 func (this *Token) Init(token_type TokenType, attribute string) {
@@ -266,3 +269,29 @@ func (this *Token) TokenType() TokenType {
 func (this *Token) Attribute() string {
 	return this.attribute
 }
+
+// NOTE: the text of a token is the word of the source line that it is lexed from, which keywords
+// have no attribute for
+func (this *Token) Text() string {
+	return this.text
+}
+
+func (this *Token) SetText(text string) {
+	this.text = text
+}
+
+func (this *Token) Line() int {
+	return this.line
+}
+
+func (this *Token) SetLine(line int) {
+	this.line = line
+}
+
+func (this *Token) Column() int {
+	return this.column
+}
+
+func (this *Token) SetColumn(column int) {
+	this.column = column
+}
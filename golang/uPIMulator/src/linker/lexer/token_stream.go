@@ -1,6 +1,7 @@
 package lexer
 
 type TokenStream struct {
+	path   string
 	tokens []*Token
 }
 
@@ -8,6 +9,14 @@ func (this *TokenStream) Init() {
 	this.tokens = make([]*Token, 0)
 }
 
+func (this *TokenStream) Path() string {
+	return this.path
+}
+
+func (this *TokenStream) SetPath(path string) {
+	this.path = path
+}
+
 func (this *TokenStream) Size() int {
 	return len(this.tokens)
 }
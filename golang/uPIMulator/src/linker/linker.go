@@ -1,9 +1,12 @@
 package linker
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"uPIMulator/src/core"
 	"uPIMulator/src/linker/kernel"
 	"uPIMulator/src/linker/lexer"
@@ -19,9 +22,16 @@ type Linker struct {
 	bin_dirpath            string
 	benchmark              string
 	num_simulation_threads int
+	gc_sections            bool
+	asm_filepaths          []string
 
 	benchmark_relocatable *kernel.Relocatable
+	asm_relocatables      []*kernel.Relocatable
 	sdk_relocatables      map[string]*kernel.Relocatable
+	required_relocatables []*kernel.Relocatable
+
+	relocatable_cache     *RelocatableCache
+	uncached_relocatables []*kernel.Relocatable
 
 	executable *kernel.Executable
 
@@ -35,17 +45,52 @@ func (this *Linker) Init(command_line_parser *misc.CommandLineParser) {
 	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
 	this.benchmark = command_line_parser.StringParameter("benchmark")
 	this.num_simulation_threads = int(command_line_parser.IntParameter("num_simulation_threads"))
+	this.gc_sections = command_line_parser.BoolParameter("gc_sections")
+
+	if asm_filepaths := command_line_parser.StringParameter("asm_filepaths"); asm_filepaths != "" {
+		this.asm_filepaths = strings.Split(asm_filepaths, ",")
+	}
+
+	if len(this.asm_filepaths) == 0 {
+		this.InitBenchmarkRelocatable()
+	} else {
+		this.InitAsmRelocatables()
+	}
 
-	this.InitBenchmarkRelocatable()
 	this.InitSdkRelocatables()
 
+	if sdk_objects := command_line_parser.StringParameter("sdk_objects"); sdk_objects == "" {
+		this.InitRequiredRelocatables([]string{})
+	} else {
+		this.InitRequiredRelocatables(strings.Split(sdk_objects, ","))
+	}
+
+	this.relocatable_cache = new(RelocatableCache)
+	this.relocatable_cache.Init(this.CacheDirpath())
+
 	this.executable = new(kernel.Executable)
-	this.executable.Init(this.benchmark)
+	this.executable.Init(this.benchmark_relocatable.Name())
 
 	this.linker_script = new(logic.LinkerScript)
 	this.linker_script.Init(command_line_parser)
 }
 
+// NOTE: the cache lives outside of the source tree so that it is never committed by accident, and
+// falls back to the bin directory where the user cache directory is not defined (e.g., no $HOME)
+func (this *Linker) CacheDirpath() string {
+	cache_dirpath := this.command_line_parser.StringParameter("cache_dirpath")
+	if cache_dirpath != "" {
+		return cache_dirpath
+	}
+
+	user_cache_dirpath, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(this.bin_dirpath, "cache")
+	}
+
+	return filepath.Join(user_cache_dirpath, "uPIMulator")
+}
+
 func (this *Linker) InitBenchmarkRelocatable() {
 	benchmark_build_dirpath := filepath.Join(this.root_dirpath, "benchmark", "build")
 
@@ -63,6 +108,55 @@ func (this *Linker) InitBenchmarkRelocatable() {
 	this.benchmark_relocatable.SetPath(assembly_path)
 }
 
+// NOTE: hand-written assembly is linked as is without the C preprocessor, and the first file
+// plays the role of the benchmark while the others are always loaded like required SDK objects.
+// Without misc.crt0, a kernel defines __bootstrap and the __sys_end label at which the host
+// expects every thread to stop (i.e., "__sys_end: stop true, __sys_end")
+func (this *Linker) InitAsmRelocatables() {
+	this.asm_relocatables = make([]*kernel.Relocatable, 0)
+
+	names := make(map[string]bool, 0)
+	for i, asm_filepath := range this.asm_filepaths {
+		name := this.AsmRelocatableName(asm_filepath)
+
+		if _, found := names[name]; found {
+			err_msg := fmt.Sprintf("assembly files share the name (%s)", name)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		names[name] = true
+
+		relocatable := new(kernel.Relocatable)
+		relocatable.Init(name)
+		relocatable.SetPath(asm_filepath)
+
+		if i == 0 {
+			this.benchmark_relocatable = relocatable
+		} else {
+			this.asm_relocatables = append(this.asm_relocatables, relocatable)
+		}
+	}
+}
+
+// NOTE: the name of a relocatable prefixes its local symbols, so it must lex as an identifier
+func (this *Linker) AsmRelocatableName(asm_filepath string) string {
+	base := filepath.Base(asm_filepath)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	name := ""
+	for _, character := range base {
+		if (character >= 'a' && character <= 'z') ||
+			(character >= 'A' && character <= 'Z') ||
+			(character >= '0' && character <= '9') {
+			name += string(character)
+		} else {
+			name += "_"
+		}
+	}
+	return name
+}
+
 func (this *Linker) InitSdkRelocatables() {
 	this.sdk_relocatables = make(map[string]*kernel.Relocatable, 0)
 
@@ -70,7 +164,9 @@ func (this *Linker) InitSdkRelocatables() {
 
 	sdk_build_dir_entries, sdk_build_dir_read_err := os.ReadDir(sdk_build_dirpath)
 
-	if sdk_build_dir_read_err != nil {
+	if os.IsNotExist(sdk_build_dir_read_err) && len(this.asm_filepaths) > 0 {
+		return
+	} else if sdk_build_dir_read_err != nil {
 		panic(sdk_build_dir_read_err)
 	}
 
@@ -105,29 +201,79 @@ func (this *Linker) InitSdkRelocatables() {
 	}
 }
 
+func (this *Linker) InitRequiredRelocatables(sdk_objects []string) {
+	this.required_relocatables = make([]*kernel.Relocatable, 0)
+	this.required_relocatables = append(this.required_relocatables, this.asm_relocatables...)
+
+	for _, sdk_object := range sdk_objects {
+		sdk_relocatable, found := this.sdk_relocatables[sdk_object]
+
+		if !found {
+			err_msg := fmt.Sprintf("SDK object (%s) is not found in %s", sdk_object, this.root_dirpath)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.required_relocatables = append(this.required_relocatables, sdk_relocatable)
+	}
+}
+
+func (this *Linker) Executable() *kernel.Executable {
+	return this.executable
+}
+
 func (this *Linker) Link() {
+	this.LoadCache()
 	this.Lex()
 	this.Parse()
 	this.AnalyzeLiveness()
+	this.StoreCache()
 	this.MakeExecutable()
 	this.LoadExecutable()
 	this.DumpExecutable()
 }
 
+func (this *Linker) Relocatables() []*kernel.Relocatable {
+	sdk_relocatable_names := make([]string, 0)
+	for sdk_relocatable_name, _ := range this.sdk_relocatables {
+		sdk_relocatable_names = append(sdk_relocatable_names, sdk_relocatable_name)
+	}
+
+	sort.Strings(sdk_relocatable_names)
+
+	relocatables := []*kernel.Relocatable{this.benchmark_relocatable}
+	relocatables = append(relocatables, this.asm_relocatables...)
+	for _, sdk_relocatable_name := range sdk_relocatable_names {
+		relocatables = append(relocatables, this.sdk_relocatables[sdk_relocatable_name])
+	}
+	return relocatables
+}
+
+func (this *Linker) LoadCache() {
+	this.uncached_relocatables = make([]*kernel.Relocatable, 0)
+
+	for _, relocatable := range this.Relocatables() {
+		if !this.relocatable_cache.Load(relocatable) {
+			this.uncached_relocatables = append(this.uncached_relocatables, relocatable)
+		}
+	}
+
+	fmt.Printf(
+		"Loaded %d of %d relocatables from the cache...\n",
+		len(this.Relocatables())-len(this.uncached_relocatables),
+		len(this.Relocatables()),
+	)
+}
+
 func (this *Linker) Lex() {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
-	benchmark_lex_job := new(LexJob)
-	benchmark_lex_job.Init(this.benchmark_relocatable)
-
-	thread_pool.Enque(benchmark_lex_job)
-
-	for _, sdk_relocatable := range this.sdk_relocatables {
-		sdk_lex_job := new(LexJob)
-		sdk_lex_job.Init(sdk_relocatable)
+	for _, relocatable := range this.uncached_relocatables {
+		lex_job := new(LexJob)
+		lex_job.Init(relocatable)
 
-		thread_pool.Enque(sdk_lex_job)
+		thread_pool.Enque(lex_job)
 	}
 
 	thread_pool.Start()
@@ -137,16 +283,11 @@ func (this *Linker) Parse() {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
-	benchmark_parse_job := new(ParseJob)
-	benchmark_parse_job.Init(this.benchmark_relocatable)
-
-	thread_pool.Enque(benchmark_parse_job)
-
-	for _, sdk_relocatable := range this.sdk_relocatables {
-		sdk_parse_job := new(ParseJob)
-		sdk_parse_job.Init(sdk_relocatable)
+	for _, relocatable := range this.uncached_relocatables {
+		parse_job := new(ParseJob)
+		parse_job.Init(relocatable)
 
-		thread_pool.Enque(sdk_parse_job)
+		thread_pool.Enque(parse_job)
 	}
 
 	thread_pool.Start()
@@ -156,21 +297,22 @@ func (this *Linker) AnalyzeLiveness() {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
-	benchmark_analyze_liveness_job := new(AnalyzeLivenessJob)
-	benchmark_analyze_liveness_job.Init(this.benchmark_relocatable)
+	for _, relocatable := range this.uncached_relocatables {
+		analyze_liveness_job := new(AnalyzeLivenessJob)
+		analyze_liveness_job.Init(relocatable)
 
-	thread_pool.Enque(benchmark_analyze_liveness_job)
-
-	for _, sdk_relocatable := range this.sdk_relocatables {
-		sdk_analyze_liveness_job := new(AnalyzeLivenessJob)
-		sdk_analyze_liveness_job.Init(sdk_relocatable)
-
-		thread_pool.Enque(sdk_analyze_liveness_job)
+		thread_pool.Enque(analyze_liveness_job)
 	}
 
 	thread_pool.Start()
 }
 
+func (this *Linker) StoreCache() {
+	for _, relocatable := range this.uncached_relocatables {
+		this.relocatable_cache.Store(relocatable)
+	}
+}
+
 func (this *Linker) MakeExecutable() {
 	fmt.Printf("Resolving symbols of %s...\n", this.executable.Name())
 
@@ -185,36 +327,22 @@ func (this *Linker) MakeExecutable() {
 	this.executable.DumpAssembly()
 }
 
-func (this *Linker) HasResolved() bool {
-	for unresolved_symbol, _ := range this.executable.Liveness().UnresolvedSymbols() {
-		if !this.linker_script.HasLinkerConstant(unresolved_symbol) {
-			return false
-		}
-	}
-	return true
-}
-
 func (this *Linker) ResolveSymbols() {
-	this.executable.AddSdkRelocatable(this.sdk_relocatables["misc.crt0"])
-
-	for !this.HasResolved() {
-		for unresolved_symbol, _ := range this.executable.Liveness().UnresolvedSymbols() {
-			if !this.linker_script.HasLinkerConstant(unresolved_symbol) {
-				for _, sdk_relocatable := range this.sdk_relocatables {
-					if _, found := sdk_relocatable.Liveness().GlobalSymbols()[unresolved_symbol]; found {
-						this.executable.AddSdkRelocatable(sdk_relocatable)
-					}
-				}
-			}
-		}
-	}
+	symbol_resolver := new(logic.SymbolResolver)
+	symbol_resolver.Init(this.linker_script, this.sdk_relocatables, this.required_relocatables)
+	symbol_resolver.Resolve(this.executable)
 }
 
 func (this *Linker) LoadExecutable() {
 	fmt.Println("Re-lexing executable")
-	lexer_ := new(lexer.Lexer)
-	lexer_.Init()
-	token_stream := lexer_.Lex(this.executable.Path())
+	token_stream := this.relocatable_cache.LoadTokenStream(this.executable.Path())
+	if token_stream == nil {
+		lexer_ := new(lexer.Lexer)
+		lexer_.Init()
+		token_stream = lexer_.Lex(this.executable.Path())
+
+		this.relocatable_cache.StoreTokenStream(this.executable.Path(), token_stream)
+	}
 	this.executable.SetTokenStream(token_stream)
 
 	fmt.Println("Re-parsing executable...")
@@ -228,6 +356,21 @@ func (this *Linker) LoadExecutable() {
 	label_assigner.Init()
 	label_assigner.Assign(this.executable)
 
+	if this.gc_sections {
+		fmt.Println("Collecting unused sections...")
+		garbage_collector := new(logic.GarbageCollector)
+		garbage_collector.Init(this.linker_script)
+
+		for _, section := range garbage_collector.Collect(this.executable) {
+			fmt.Printf(
+				"Removing unused section %s (%d bytes) in %s\n",
+				section.FullName(),
+				section.Size(),
+				strings.Join(this.executable.Origins(section), ", "),
+			)
+		}
+	}
+
 	fmt.Println("Assigning addresses..")
 	this.linker_script.Assign(this.executable)
 
@@ -245,8 +388,19 @@ func (this *Linker) LoadExecutable() {
 func (this *Linker) DumpExecutable() {
 	this.linker_script.DumpValues(filepath.Join(this.bin_dirpath, "values.txt"))
 	this.executable.DumpAddresses(filepath.Join(this.bin_dirpath, "addresses.txt"))
+	this.executable.DumpSourceLines(filepath.Join(this.bin_dirpath, "source_lines.txt"))
 	this.executable.DumpAtomic(filepath.Join(this.bin_dirpath, "atomic.bin"))
 	this.executable.DumpIram(filepath.Join(this.bin_dirpath, "iram.bin"))
 	this.executable.DumpWram(filepath.Join(this.bin_dirpath, "wram.bin"))
 	this.executable.DumpMram(filepath.Join(this.bin_dirpath, "mram.bin"))
+
+	linker_map := new(LinkerMap)
+	linker_map.Init(this.executable, this.linker_script)
+
+	fmt.Printf("Dumping the linker map to %s...\n", filepath.Join(this.bin_dirpath, "main.map"))
+	linker_map.Dump(filepath.Join(this.bin_dirpath, "main.map"))
+
+	for _, line := range linker_map.Summary() {
+		fmt.Println(line)
+	}
 }
@@ -0,0 +1,199 @@
+package linker
+
+import (
+	"fmt"
+	"strings"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/linker/logic"
+	"uPIMulator/src/misc"
+)
+
+type LinkerMap struct {
+	executable    *kernel.Executable
+	linker_script *logic.LinkerScript
+}
+
+func (this *LinkerMap) Init(executable *kernel.Executable, linker_script *logic.LinkerScript) {
+	this.executable = executable
+	this.linker_script = linker_script
+}
+
+func (this *LinkerMap) Memories() []string {
+	return []string{"atomic", "IRAM", "WRAM", "MRAM"}
+}
+
+func (this *LinkerMap) Memory(memory string) logic.Memory {
+	if memory == "atomic" {
+		return logic.ATOMIC_MEMORY
+	} else if memory == "IRAM" {
+		return logic.IRAM_MEMORY
+	} else if memory == "WRAM" {
+		return logic.WRAM_MEMORY
+	} else {
+		return logic.MRAM_MEMORY
+	}
+}
+
+// NOTE: a memory without a region in the linker script starts at its offset in the simulator
+func (this *LinkerMap) Origin(memory string) int64 {
+	regions := this.linker_script.Regions(this.Memory(memory))
+
+	if len(regions) == 0 {
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		if memory == "atomic" {
+			return config_loader.AtomicOffset()
+		} else if memory == "IRAM" {
+			return config_loader.IramOffset()
+		} else if memory == "WRAM" {
+			return config_loader.WramOffset()
+		} else {
+			return config_loader.MramOffset()
+		}
+	}
+
+	origin := regions[0].Origin()
+	for _, region := range regions[1:] {
+		if region.Origin() < origin {
+			origin = region.Origin()
+		}
+	}
+	return this.linker_script.Translate(this.Memory(memory), origin)
+}
+
+// NOTE: lengths are those of the regions that CheckOverflow of the linker script checks against,
+// so that a link that succeeds never reports more usage than the size of a memory
+func (this *LinkerMap) Length(memory string) int64 {
+	length := int64(0)
+	for _, region := range this.linker_script.Regions(this.Memory(memory)) {
+		length += region.Length()
+	}
+	return length
+}
+
+// NOTE: usage includes what the linker script reserves past the last section of a memory
+// (e.g., the atomic bits of the runtime, the tasklet stacks and the MRAM alignment padding)
+func (this *LinkerMap) Used(memory string) int64 {
+	end := this.Origin(memory)
+
+	for _, section := range this.Sections(memory) {
+		if section.Address()+section.Size() > end {
+			end = section.Address() + section.Size()
+		}
+	}
+
+	linker_constant_names := map[string]string{
+		"atomic": "__atomic_end_addr",
+		"WRAM":   "__sys_heap_pointer_reset",
+		"MRAM":   "__sys_used_mram_end",
+	}
+
+	if name, found := linker_constant_names[memory]; found && this.linker_script.HasLinkerConstant(name) {
+		if value := this.linker_script.LinkerConstant(name).Value(); value > end {
+			end = value
+		}
+	}
+
+	return end - this.Origin(memory)
+}
+
+func (this *LinkerMap) Sections(memory string) []*kernel.Section {
+	return this.executable.Sort(this.Origin(memory), this.Origin(memory)+this.Length(memory))
+}
+
+func (this *LinkerMap) Summary() []string {
+	lines := []string{
+		fmt.Sprintf("%-8s %12s %12s %12s %8s", "Memory", "Used", "Free", "Size", "Used%"),
+	}
+
+	for _, memory := range this.Memories() {
+		used := this.Used(memory)
+		length := this.Length(memory)
+
+		used_percent := float64(0)
+		if length > 0 {
+			used_percent = 100 * float64(used) / float64(length)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%-8s %12d %12d %12d %7.2f%%",
+			memory,
+			used,
+			length-used,
+			length,
+			used_percent,
+		))
+	}
+
+	return lines
+}
+
+func (this *LinkerMap) Lines() []string {
+	lines := []string{"Memory Configuration", ""}
+	lines = append(lines, fmt.Sprintf("%-8s %-18s %-18s", "Name", "Origin", "Length"))
+	for _, memory := range this.Memories() {
+		lines = append(lines, fmt.Sprintf(
+			"%-8s 0x%016x 0x%016x",
+			memory,
+			this.Origin(memory),
+			this.Length(memory),
+		))
+	}
+
+	lines = append(lines, "", "Memory Usage", "")
+	lines = append(lines, this.Summary()...)
+
+	lines = append(lines, "", "Linker script and memory map")
+	for _, memory := range this.Memories() {
+		lines = append(lines, "", fmt.Sprintf("%s:", memory))
+
+		for _, section := range this.Sections(memory) {
+			origins := this.executable.Origins(section)
+			if len(origins) == 0 {
+				origins = []string{"*"}
+			}
+
+			lines = append(lines, fmt.Sprintf(
+				" %-32s 0x%016x %10d  align %-4d %s",
+				section.FullName(),
+				section.Address(),
+				section.Size(),
+				section.Alignment(),
+				strings.Join(origins, ", "),
+			))
+
+			for _, label := range section.Labels()[1:] {
+				lines = append(lines, fmt.Sprintf(
+					" %-32s 0x%016x %10d        %s",
+					"",
+					label.Address(),
+					label.Size(),
+					label.Name(),
+				))
+			}
+		}
+	}
+
+	lines = append(lines, "", "Non-loaded sections:")
+	for _, section := range this.executable.NonLoadedSections() {
+		lines = append(lines, fmt.Sprintf(" %-32s %10d", section.FullName(), section.Size()))
+	}
+
+	lines = append(lines, "", "Linker constants", "")
+	for _, linker_constant := range this.linker_script.LinkerConstants() {
+		lines = append(lines, fmt.Sprintf(
+			" %-32s 0x%016x",
+			linker_constant.Name(),
+			linker_constant.Value(),
+		))
+	}
+
+	return lines
+}
+
+func (this *LinkerMap) Dump(path string) {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(this.Lines())
+}
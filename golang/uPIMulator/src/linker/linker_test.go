@@ -0,0 +1,414 @@
+package linker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"uPIMulator/src/misc"
+)
+
+func newLinkerCommandLineParser(
+	t *testing.T,
+	asm_filepath string,
+	bin_dirpath string,
+	cache_dirpath string,
+) *misc.CommandLineParser {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "root_dirpath", root_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", bin_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "")
+	command_line_parser.AddOption(misc.INT, "num_simulation_threads", "4", "")
+	command_line_parser.AddOption(misc.INT, "num_tasklets", "16", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	command_line_parser.AddOption(misc.STRING, "asm_filepaths", asm_filepath, "")
+	command_line_parser.AddOption(misc.STRING, "sdk_objects", "", "")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "cache_dirpath", cache_dirpath, "")
+	command_line_parser.AddOption(misc.BOOL, "gc_sections", "false", "")
+	return command_line_parser
+}
+
+// NOTE: testdata/kernel.map is the map of testdata/kernel.S under sdk/misc/dpu.lds, so any change
+// of the layout shows up as a diff of the golden map
+func TestLinkerMap(t *testing.T) {
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "kernel.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	bin_dirpath := t.TempDir()
+
+	linker_ := new(Linker)
+	linker_.Init(newLinkerCommandLineParser(t, asm_filepath, bin_dirpath, t.TempDir()))
+	linker_.Link()
+
+	linker_map, read_err := os.ReadFile(filepath.Join(bin_dirpath, "main.map"))
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	golden, read_err := os.ReadFile(filepath.Join("testdata", "kernel.map"))
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	if string(linker_map) != string(golden) {
+		t.Errorf("main.map =\n%s\nwant\n%s", linker_map, golden)
+	}
+}
+
+// NOTE: debug information and stack sizes must neither take MRAM nor shadow loaded labels
+func TestLinkerNonLoadedSections(t *testing.T) {
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "kernel.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	linker_ := new(Linker)
+	linker_.Init(newLinkerCommandLineParser(t, asm_filepath, t.TempDir(), t.TempDir()))
+	linker_.Link()
+
+	executable := linker_.Executable()
+
+	if size := executable.MramByteStream().Size(); size != 8 {
+		t.Errorf("MRAM image is %d bytes, want the 8 bytes of buffer", size)
+	}
+
+	non_loaded_sections := executable.NonLoadedSections()
+	if len(non_loaded_sections) != 4 {
+		t.Errorf("%d sections are not loaded, want 4", len(non_loaded_sections))
+	}
+
+	for _, section := range non_loaded_sections {
+		if section.Address() != 0 {
+			t.Errorf("%s is at %d", section.FullName(), section.Address())
+		}
+	}
+
+	for label_name, _ := range executable.Addresses() {
+		if strings.Contains(label_name, "debug") || strings.Contains(label_name, "info") {
+			t.Errorf("label (%s) of a non-loaded section is dumped", label_name)
+		}
+	}
+}
+
+func TestLinkerMinAccessGranularity(t *testing.T) {
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "kernel.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	command_line_parser := newLinkerCommandLineParser(t, asm_filepath, t.TempDir(), t.TempDir())
+	command_line_parser.Parse([]string{"linker.test", "--min_access_granularity", "64"})
+
+	linker_ := new(Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	for _, name := range []string{"__sys_heap_pointer_reset", "__sys_used_mram_end"} {
+		if value := linker_.linker_script.LinkerConstant(name).Value(); value%64 != 0 {
+			t.Errorf("%s (%d) is not aligned to min_access_granularity", name, value)
+		}
+	}
+}
+
+// NOTE: the cache is keyed by the content of the assembly, so an edited file is lexed again while
+// an untouched one is loaded from the cache
+func TestLinkerCacheInvalidation(t *testing.T) {
+	kernel, read_err := os.ReadFile(filepath.Join("testdata", "kernel.S"))
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	asm_filepath := filepath.Join(t.TempDir(), "kernel.S")
+	cache_dirpath := t.TempDir()
+
+	link_fn := func(source string) *Linker {
+		if err := os.WriteFile(asm_filepath, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		linker_ := new(Linker)
+		linker_.Init(newLinkerCommandLineParser(t, asm_filepath, t.TempDir(), cache_dirpath))
+		linker_.Link()
+		return linker_
+	}
+
+	if linker_ := link_fn(string(kernel)); len(linker_.uncached_relocatables) != 1 {
+		t.Errorf("%d relocatables are lexed on a cold cache", len(linker_.uncached_relocatables))
+	}
+
+	if linker_ := link_fn(string(kernel)); len(linker_.uncached_relocatables) != 0 {
+		t.Errorf("%d relocatables are lexed on a warm cache", len(linker_.uncached_relocatables))
+	}
+
+	edited := strings.Replace(string(kernel), ".quad\t11", ".quad\t13", 1)
+	if edited == string(kernel) {
+		t.Fatal("testdata/kernel.S does not define buffer as .quad 11")
+	}
+
+	linker_ := link_fn(edited)
+	if len(linker_.uncached_relocatables) != 1 {
+		t.Errorf("%d relocatables are lexed after an edit", len(linker_.uncached_relocatables))
+	}
+	if value := linker_.Executable().MramByteStream().Get(0); value != 13 {
+		t.Errorf("buffer is %d after the edit, want 13", value)
+	}
+}
+
+// NOTE: testdata/source_lines.S locates the instructions of __bootstrap only, so that line 0 and
+// the section of __sys_end, which has no .loc of its own, leave instructions unattributed
+func TestLinkerSourceLines(t *testing.T) {
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "source_lines.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	linker_ := new(Linker)
+	linker_.Init(newLinkerCommandLineParser(t, asm_filepath, t.TempDir(), t.TempDir()))
+	linker_.Link()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+	executable := linker_.Executable()
+	source_line_table := executable.SourceLineTable()
+	bootstrap := executable.Addresses()["__bootstrap"]
+
+	source_lines := map[int64]string{
+		bootstrap:                           "/src/task.c:10",
+		bootstrap + iram_data_size:          "/src/task.c:11:3",
+		bootstrap + 2*iram_data_size:        "",
+		bootstrap + 3*iram_data_size:        "/src/task.c:12:5",
+		executable.Addresses()["__sys_end"]: "",
+	}
+
+	for address, expected := range source_lines {
+		source_line := source_line_table.SourceLine(address)
+
+		if expected == "" && source_line != nil {
+			t.Errorf("address %d is located at %s", address, source_line.Stringify())
+		} else if expected != "" && source_line == nil {
+			t.Errorf("address %d is not located, want %s", address, expected)
+		} else if expected != "" && source_line.Stringify() != expected {
+			t.Errorf("address %d is located at %s, want %s", address, source_line.Stringify(), expected)
+		}
+	}
+}
+
+const BOOTSTRAP = `	.section	.text.__bootstrap,"ax",@progbits
+	.globl	__bootstrap
+	.type	__bootstrap,@function
+__bootstrap:
+	lw r0, zero, value
+	call r23, __sys_end
+	.section	.text.__sys_end,"ax",@progbits
+	.globl	__sys_end
+	.type	__sys_end,@function
+__sys_end:
+	stop true, __sys_end
+`
+
+// NOTE: the first source plays the role of the benchmark and the others are linked like required
+// SDK objects, while panics of the linker are returned as messages
+func linkSources(t *testing.T, gc_sections bool, sources ...string) (*Linker, []string, string) {
+	asm_dirpath := t.TempDir()
+
+	asm_filepaths := make([]string, 0)
+	for i, source := range sources {
+		asm_filepath := filepath.Join(asm_dirpath, fmt.Sprintf("kernel%d.S", i))
+		if err := os.WriteFile(asm_filepath, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		asm_filepaths = append(asm_filepaths, asm_filepath)
+	}
+
+	command_line_parser := newLinkerCommandLineParser(
+		t,
+		strings.Join(asm_filepaths, ","),
+		t.TempDir(),
+		t.TempDir(),
+	)
+	command_line_parser.Parse([]string{
+		"linker.test",
+		"--gc_sections",
+		strconv.FormatBool(gc_sections),
+	})
+
+	linker_ := new(Linker)
+	message := recoverMessage(func() {
+		linker_.Init(command_line_parser)
+		linker_.Link()
+	})
+
+	return linker_, asm_filepaths, message
+}
+
+func TestLinkerUndefinedReference(t *testing.T) {
+	_, asm_filepaths, message := linkSources(t, false, BOOTSTRAP)
+
+	expected := fmt.Sprintf("%s:5: undefined reference to `value'", asm_filepaths[0])
+	if message != expected {
+		t.Errorf("undefined reference is reported as %q, want %q", message, expected)
+	}
+}
+
+func TestLinkerMultipleDefinition(t *testing.T) {
+	value := `	.section	.data.value,"aw",@progbits
+	.globl	value
+	.p2align	2
+value:
+	.long	7
+`
+
+	_, asm_filepaths, message := linkSources(t, false, BOOTSTRAP+value, value)
+
+	expected := fmt.Sprintf(
+		"%s:4: multiple definition of `value'; %s:15: first defined here",
+		asm_filepaths[1],
+		asm_filepaths[0],
+	)
+	if message != expected {
+		t.Errorf("multiple definition is reported as %q, want %q", message, expected)
+	}
+}
+
+// NOTE: a strong definition overrides a weak one wherever it is linked, the first of several weak
+// definitions wins, and a weak reference that nothing defines resolves to zero
+func TestLinkerWeakDefinition(t *testing.T) {
+	definition_fn := func(binding string, name string, value int) string {
+		return fmt.Sprintf(`	.section	.data.%s%d,"aw",@progbits
+	%s	%s
+	.p2align	2
+%s:
+	.long	%d
+`, name, value, binding, name, name, value)
+	}
+
+	weak_reference := `	.section	.data.pointer,"aw",@progbits
+	.globl	pointer
+	.weak	missing
+	.p2align	2
+pointer:
+	.long	missing
+`
+
+	linker_, _, message := linkSources(
+		t,
+		false,
+		BOOTSTRAP+definition_fn(".weak", "value", 1)+weak_reference,
+		definition_fn(".globl", "value", 2),
+		definition_fn(".weak", "other", 3),
+		definition_fn(".weak", "other", 4),
+	)
+	if message != "" {
+		t.Fatal(message)
+	}
+
+	addresses := linker_.Executable().Addresses()
+
+	sections := map[string]string{"value": "data.value2", "other": "data.other3"}
+	for name, section := range sections {
+		if _, found := addresses[section]; !found {
+			t.Errorf(".%s is not linked", section)
+		} else if addresses[name] != addresses[section] {
+			t.Errorf("%s is not resolved to the definition in .%s", name, section)
+		}
+	}
+
+	if value := linker_.linker_script.LinkerConstant("missing").Value(); value != 0 {
+		t.Errorf("weak reference to an undefined symbol resolves to %d", value)
+	}
+}
+
+// NOTE: unused is only referenced by the unused function, so that both are collected together
+// while the debug information of the unused function goes with them
+func TestLinkerGcSections(t *testing.T) {
+	source := BOOTSTRAP + `	.section	.data.value,"aw",@progbits
+	.globl	value
+	.p2align	2
+value:
+	.long	7
+	.section	.text.unused_function,"ax",@progbits
+	.globl	unused_function
+	.type	unused_function,@function
+unused_function:
+	lw r0, zero, unused
+	jump r23
+	.section	.data.unused,"aw",@progbits
+	.globl	unused
+	.p2align	2
+unused:
+	.long	9
+	.section	.stack_sizes,"o",@progbits,.text.unused_function
+	.long	unused_function
+	.byte	0
+`
+
+	for _, gc_sections := range []bool{false, true} {
+		linker_, _, message := linkSources(t, gc_sections, source)
+		if message != "" {
+			t.Fatal(message)
+		}
+
+		executable := linker_.Executable()
+
+		for _, name := range []string{"__bootstrap", "__sys_end", "value"} {
+			if _, found := executable.Addresses()[name]; !found {
+				t.Errorf("%s is collected with gc_sections (%t)", name, gc_sections)
+			}
+		}
+
+		for _, name := range []string{"unused_function", "unused"} {
+			if _, found := executable.Addresses()[name]; found == gc_sections {
+				t.Errorf("%s is kept (%t) with gc_sections (%t)", name, found, gc_sections)
+			}
+		}
+
+		if num_non_loaded := len(executable.NonLoadedSections()); gc_sections && num_non_loaded != 0 {
+			t.Errorf("%d non-loaded sections of collected sections are kept", num_non_loaded)
+		}
+	}
+}
+
+// NOTE: the two buffers alone take more than the 64K of WRAM in sdk/misc/dpu.lds, so the report
+// lists them first
+func TestLinkerOverflow(t *testing.T) {
+	buffers := `	.section	.bss.table,"aw",@nobits
+	.globl	table
+	.p2align	3
+table:
+	.zero	40000
+	.section	.bss.buffer,"aw",@nobits
+	.globl	buffer
+	.p2align	3
+buffer:
+	.zero	30000
+	.section	.data.value,"aw",@progbits
+	.globl	value
+	.p2align	2
+value:
+	.long	7
+`
+
+	_, _, message := linkSources(t, false, BOOTSTRAP+buffers)
+
+	expected := "wram overflows by 4480 bytes: 70016 bytes used of 65536 bytes available; " +
+		"largest sections: .bss.table (40000 bytes from kernel0), " +
+		".bss.buffer (30000 bytes from kernel0), .data.value (4 bytes from kernel0)"
+	if message != expected {
+		t.Errorf("overflow is reported as %q, want %q", message, expected)
+	}
+}
@@ -0,0 +1,100 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"uPIMulator/src/misc"
+)
+
+type Memory int
+
+const (
+	ATOMIC_MEMORY Memory = iota
+	IRAM_MEMORY
+	WRAM_MEMORY
+	MRAM_MEMORY
+)
+
+// NOTE: linker scripts and ELF executables use the memory regions of the UPMEM toolchain's dpu.lds,
+// which are translated to the memories of the simulator given by misc.ConfigLoader
+const (
+	ATOMIC_ORIGIN int64 = 0xF0000000
+	IRAM_ORIGIN   int64 = 0x80000000
+	WRAM_ORIGIN   int64 = 0x00000000
+	MRAM_ORIGIN   int64 = 0x08000000
+)
+
+type AddressSpace struct {
+	config_loader *misc.ConfigLoader
+}
+
+func (this *AddressSpace) Init() {
+	this.config_loader = new(misc.ConfigLoader)
+	this.config_loader.Init()
+}
+
+func (this *AddressSpace) Memories() []Memory {
+	return []Memory{ATOMIC_MEMORY, IRAM_MEMORY, WRAM_MEMORY, MRAM_MEMORY}
+}
+
+func (this *AddressSpace) Memory(address int64) Memory {
+	for _, memory := range this.Memories() {
+		if this.Origin(memory) <= address && address < this.Origin(memory)+this.Size(memory) {
+			return memory
+		}
+	}
+
+	err_msg := fmt.Sprintf("address (0x%x) is not in any memory", address)
+	err := errors.New(err_msg)
+	panic(err)
+}
+
+func (this *AddressSpace) Origin(memory Memory) int64 {
+	if memory == ATOMIC_MEMORY {
+		return ATOMIC_ORIGIN
+	} else if memory == IRAM_MEMORY {
+		return IRAM_ORIGIN
+	} else if memory == WRAM_MEMORY {
+		return WRAM_ORIGIN
+	} else if memory == MRAM_MEMORY {
+		return MRAM_ORIGIN
+	} else {
+		err := errors.New("memory is not valid")
+		panic(err)
+	}
+}
+
+func (this *AddressSpace) Offset(memory Memory) int64 {
+	if memory == ATOMIC_MEMORY {
+		return this.config_loader.AtomicOffset()
+	} else if memory == IRAM_MEMORY {
+		return this.config_loader.IramOffset()
+	} else if memory == WRAM_MEMORY {
+		return this.config_loader.WramOffset()
+	} else if memory == MRAM_MEMORY {
+		return this.config_loader.MramOffset()
+	} else {
+		err := errors.New("memory is not valid")
+		panic(err)
+	}
+}
+
+func (this *AddressSpace) Size(memory Memory) int64 {
+	if memory == ATOMIC_MEMORY {
+		return this.config_loader.AtomicSize()
+	} else if memory == IRAM_MEMORY {
+		return this.config_loader.IramSize()
+	} else if memory == WRAM_MEMORY {
+		return this.config_loader.WramSize()
+	} else if memory == MRAM_MEMORY {
+		return this.config_loader.MramSize()
+	} else {
+		err := errors.New("memory is not valid")
+		panic(err)
+	}
+}
+
+func (this *AddressSpace) Translate(address int64) int64 {
+	memory := this.Memory(address)
+	return this.Offset(memory) + address - this.Origin(memory)
+}
@@ -0,0 +1,193 @@
+package logic
+
+import (
+	"errors"
+	"sort"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/linker/lexer"
+	"uPIMulator/src/linker/parser"
+	"uPIMulator/src/linker/parser/expr"
+	"uPIMulator/src/linker/parser/stmt"
+)
+
+type GarbageCollector struct {
+	linker_script *LinkerScript
+
+	executable *kernel.Executable
+	walker     *parser.Walker
+
+	cur_section *kernel.Section
+	defs        map[string]*kernel.Section
+	uses        map[*kernel.Section]map[string]bool
+	live        map[*kernel.Section]bool
+}
+
+func (this *GarbageCollector) Init(linker_script *LinkerScript) {
+	this.linker_script = linker_script
+
+	this.walker = new(parser.Walker)
+	this.walker.Init()
+
+	this.walker.RegisterExprCallback(expr.PRIMARY, this.WalkPrimaryExpr)
+	this.walker.RegisterStmtCallback(stmt.SET, this.WalkSetStmt)
+}
+
+// NOTE: a section is live if the entry symbol or a KEEP command of the linker script reaches it
+// through symbol references, and every other allocated section is removed from the executable
+func (this *GarbageCollector) Collect(executable *kernel.Executable) []*kernel.Section {
+	this.executable = executable
+
+	this.defs = make(map[string]*kernel.Section, 0)
+	this.uses = make(map[*kernel.Section]map[string]bool, 0)
+	this.live = make(map[*kernel.Section]bool, 0)
+
+	sections := this.Sort(executable.AllSections())
+
+	for _, section := range sections {
+		for _, label := range section.Labels()[1:] {
+			this.defs[label.Name()] = section
+		}
+	}
+
+	for _, section := range sections {
+		this.cur_section = section
+		this.uses[section] = make(map[string]bool, 0)
+
+		for _, stmt_ := range section.Stmts() {
+			if stmt_.StmtType() != stmt.GLOBAL && stmt_.StmtType() != stmt.WEAK {
+				this.walker.WalkStmt(stmt_)
+			}
+		}
+	}
+
+	if entry, found := this.defs[this.linker_script.Entry()]; found {
+		this.Mark(entry)
+	}
+
+	for _, section := range sections {
+		if this.linker_script.IsKept(executable, section) {
+			this.Mark(section)
+		}
+	}
+
+	for _, section := range sections {
+		if _, found := section.SectionFlags()[kernel.ALLOC]; !found && this.IsSatisfied(section) {
+			this.live[section] = true
+		}
+	}
+
+	collected_sections := make([]*kernel.Section, 0)
+	for _, section := range sections {
+		if _, found := this.live[section]; !found {
+			collected_sections = append(collected_sections, section)
+		}
+	}
+
+	this.Sweep(collected_sections)
+
+	return collected_sections
+}
+
+func (this *GarbageCollector) Mark(section *kernel.Section) {
+	worklist := []*kernel.Section{section}
+
+	for len(worklist) > 0 {
+		section_ := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+
+		if _, found := this.live[section_]; found {
+			continue
+		}
+
+		this.live[section_] = true
+
+		for use, _ := range this.uses[section_] {
+			if def, found := this.defs[use]; found {
+				worklist = append(worklist, def)
+			}
+		}
+	}
+}
+
+// NOTE: sections that are not allocated (e.g., debug information and stack sizes) only describe
+// other sections, so they are kept as long as whatever they refer to is kept
+func (this *GarbageCollector) IsSatisfied(section *kernel.Section) bool {
+	for use, _ := range this.uses[section] {
+		if def, found := this.defs[use]; found && def != section {
+			if _, found := this.live[def]; !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (this *GarbageCollector) Sweep(collected_sections []*kernel.Section) {
+	collected_stmts := make(map[*stmt.Stmt]bool, 0)
+	for _, collected_section := range collected_sections {
+		for _, stmt_ := range collected_section.Stmts() {
+			collected_stmts[stmt_] = true
+		}
+
+		this.executable.RemoveSection(collected_section)
+	}
+
+	ast := this.executable.Ast()
+
+	stmts := make([]*stmt.Stmt, 0)
+	for i := 0; i < ast.Size(); i++ {
+		if _, found := collected_stmts[ast.Get(i)]; !found {
+			stmts = append(stmts, ast.Get(i))
+		}
+	}
+
+	swept_ast := new(parser.Ast)
+	swept_ast.Init(stmts)
+
+	this.executable.SetAst(swept_ast)
+}
+
+func (this *GarbageCollector) Sort(sections map[*kernel.Section]bool) []*kernel.Section {
+	sorted_sections := make([]*kernel.Section, 0)
+	for section, _ := range sections {
+		sorted_sections = append(sorted_sections, section)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		return sorted_sections[i].FullName() < sorted_sections[j].FullName()
+	}
+
+	sort.Slice(sorted_sections, sort_fn)
+
+	return sorted_sections
+}
+
+func (this *GarbageCollector) WalkPrimaryExpr(expr_ *expr.Expr) {
+	if expr_.ExprType() != expr.PRIMARY {
+		err := errors.New("expr type is not primary")
+		panic(err)
+	}
+
+	token := expr_.PrimaryExpr().Token()
+
+	if token.TokenType() == lexer.IDENTIFIER {
+		this.uses[this.cur_section][token.Attribute()] = true
+	}
+}
+
+func (this *GarbageCollector) WalkSetStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.SET {
+		err := errors.New("stmt type is not set")
+		panic(err)
+	}
+
+	program_counter_expr := stmt_.SetStmt().Expr1().ProgramCounterExpr()
+	token := program_counter_expr.Expr().PrimaryExpr().Token()
+
+	if token.TokenType() != lexer.IDENTIFIER {
+		err := errors.New("token type is not identifier")
+		panic(err)
+	}
+
+	this.defs[token.Attribute()] = this.cur_section
+}
@@ -2,6 +2,8 @@ package logic
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 	"strconv"
 	"uPIMulator/src/linker/kernel"
 	"uPIMulator/src/linker/kernel/directive"
@@ -19,6 +21,9 @@ type InstructionAssigner struct {
 	executable    *kernel.Executable
 	walker        *parser.Walker
 	linker_script *LinkerScript
+
+	file_paths      map[int64]string
+	cur_source_line *kernel.SourceLine
 }
 
 func (this *InstructionAssigner) Init(linker_script *LinkerScript) {
@@ -30,6 +35,11 @@ func (this *InstructionAssigner) Init(linker_script *LinkerScript) {
 	this.walker.RegisterStmtCallback(stmt.ASCII, this.WalkAsciiStmt)
 	this.walker.RegisterStmtCallback(stmt.ASCIZ, this.WalkAscizStmt)
 	this.walker.RegisterStmtCallback(stmt.BYTE, this.WalkByteStmt)
+	this.walker.RegisterStmtCallback(stmt.FILE_NUMBER, this.WalkFileNumberStmt)
+	this.walker.RegisterStmtCallback(stmt.FILE_STRING, this.WalkFileStringStmt)
+	this.walker.RegisterStmtCallback(stmt.LOC_IS_STMT, this.WalkLocIsStmtStmt)
+	this.walker.RegisterStmtCallback(stmt.LOC_NUMBER, this.WalkLocNumberStmt)
+	this.walker.RegisterStmtCallback(stmt.LOC_PROLOGUE_END, this.WalkLocPrologueEndStmt)
 	this.walker.RegisterStmtCallback(stmt.LONG_PROGRAM_COUNTER, this.WalkLongProgramCounterStmt)
 	this.walker.RegisterStmtCallback(stmt.LONG_SECTION_NAME, this.WalkLongSectionNameStmt)
 	this.walker.RegisterStmtCallback(stmt.QUAD, this.WalkQuadStmt)
@@ -121,6 +131,10 @@ func (this *InstructionAssigner) Init(linker_script *LinkerScript) {
 
 func (this *InstructionAssigner) Assign(executable *kernel.Executable) {
 	this.executable = executable
+
+	this.file_paths = make(map[int64]string, 0)
+	this.cur_source_line = nil
+
 	this.walker.Walk(executable.Ast())
 }
 
@@ -164,6 +178,79 @@ func (this *InstructionAssigner) WalkByteStmt(stmt_ *stmt.Stmt) {
 	cur_label.Append(byte_directive)
 }
 
+func (this *InstructionAssigner) WalkFileNumberStmt(stmt_ *stmt.Stmt) {
+	file_number_stmt := stmt_.FileNumberStmt()
+
+	file_number := this.EvaluateProgramCounter(file_number_stmt.Expr())
+
+	dirpath := file_number_stmt.Token1().Attribute()
+	filename := file_number_stmt.Token2().Attribute()
+
+	dirpath = dirpath[1 : len(dirpath)-1]
+	filename = filename[1 : len(filename)-1]
+
+	if filepath.IsAbs(filename) {
+		this.file_paths[file_number] = filename
+	} else {
+		this.file_paths[file_number] = filepath.Join(dirpath, filename)
+	}
+}
+
+// NOTE: every relocatable begins with its own .file directive and numbers its files from scratch,
+// so the file table of the previous relocatable is dropped
+func (this *InstructionAssigner) WalkFileStringStmt(stmt_ *stmt.Stmt) {
+	this.file_paths = make(map[int64]string, 0)
+	this.cur_source_line = nil
+}
+
+func (this *InstructionAssigner) WalkLocIsStmtStmt(stmt_ *stmt.Stmt) {
+	loc_is_stmt_stmt := stmt_.LocIsStmtStmt()
+
+	this.Locate(loc_is_stmt_stmt.Expr1(), loc_is_stmt_stmt.Expr2(), loc_is_stmt_stmt.Expr3())
+}
+
+func (this *InstructionAssigner) WalkLocNumberStmt(stmt_ *stmt.Stmt) {
+	loc_number_stmt := stmt_.LocNumberStmt()
+
+	this.Locate(loc_number_stmt.Expr1(), loc_number_stmt.Expr2(), loc_number_stmt.Expr3())
+}
+
+func (this *InstructionAssigner) WalkLocPrologueEndStmt(stmt_ *stmt.Stmt) {
+	loc_prologue_end_stmt := stmt_.LocPrologueEndStmt()
+
+	this.Locate(
+		loc_prologue_end_stmt.Expr1(),
+		loc_prologue_end_stmt.Expr2(),
+		loc_prologue_end_stmt.Expr3(),
+	)
+}
+
+// NOTE: line 0 marks instructions that the compiler has not attributed to any source line
+func (this *InstructionAssigner) Locate(
+	file_expr *expr.Expr,
+	line_expr *expr.Expr,
+	column_expr *expr.Expr,
+) {
+	file_number := this.EvaluateProgramCounter(file_expr)
+	line := this.EvaluateProgramCounter(line_expr)
+	column := this.EvaluateProgramCounter(column_expr)
+
+	if line == 0 {
+		this.cur_source_line = nil
+		return
+	}
+
+	path, found := this.file_paths[file_number]
+	if !found {
+		err_msg := fmt.Sprintf("file number (%d) is not defined by a .file directive", file_number)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.cur_source_line = new(kernel.SourceLine)
+	this.cur_source_line.Init(path, line, column)
+}
+
 func (this *InstructionAssigner) WalkLongProgramCounterStmt(stmt_ *stmt.Stmt) {
 	long_program_counter_stmt := stmt_.LongProgramCounterStmt()
 
@@ -206,7 +293,7 @@ func (this *InstructionAssigner) WalkSectionIdentifierNumberStmt(stmt_ *stmt.Stm
 	section_name := this.ConvertSectionName(section_identifier_number_stmt.Expr1())
 	name := this.ConvertName(section_identifier_number_stmt.Expr2())
 
-	this.executable.CheckoutSection(section_name, name)
+	this.CheckoutSection(section_name, name)
 }
 
 func (this *InstructionAssigner) WalkSectionIdentifierStmt(stmt_ *stmt.Stmt) {
@@ -215,7 +302,7 @@ func (this *InstructionAssigner) WalkSectionIdentifierStmt(stmt_ *stmt.Stmt) {
 	section_name := this.ConvertSectionName(section_identifier_stmt.Expr1())
 	name := this.ConvertName(section_identifier_stmt.Expr2())
 
-	this.executable.CheckoutSection(section_name, name)
+	this.CheckoutSection(section_name, name)
 }
 
 func (this *InstructionAssigner) WalkSectionStackSizes(stmt_ *stmt.Stmt) {
@@ -265,7 +352,7 @@ func (this *InstructionAssigner) WalkSectionStackSizes(stmt_ *stmt.Stmt) {
 
 	name += this.ConvertName(section_stack_sizes_stmt.Expr3())
 
-	this.executable.CheckoutSection(section_name, name)
+	this.CheckoutSection(section_name, name)
 }
 
 func (this *InstructionAssigner) WalkSectionStringNumberStmt(stmt_ *stmt.Stmt) {
@@ -274,7 +361,7 @@ func (this *InstructionAssigner) WalkSectionStringNumberStmt(stmt_ *stmt.Stmt) {
 	section_name := this.ConvertSectionName(section_string_number_stmt.Expr1())
 	name := ""
 
-	this.executable.CheckoutSection(section_name, name)
+	this.CheckoutSection(section_name, name)
 }
 
 func (this *InstructionAssigner) WalkSectionStringStmt(stmt_ *stmt.Stmt) {
@@ -283,6 +370,14 @@ func (this *InstructionAssigner) WalkSectionStringStmt(stmt_ *stmt.Stmt) {
 	section_name := this.ConvertSectionName(section_string_stmt.Expr1())
 	name := ""
 
+	this.CheckoutSection(section_name, name)
+}
+
+// NOTE: a .loc applies to the section it is written in only, so that code of a later section
+// without .loc directives (e.g., __sys_end) is not attributed to the last line of the previous one
+func (this *InstructionAssigner) CheckoutSection(section_name kernel.SectionName, name string) {
+	this.cur_source_line = nil
+
 	this.executable.CheckoutSection(section_name, name)
 }
 
@@ -302,7 +397,7 @@ func (this *InstructionAssigner) WalkTextStmt(stmt_ *stmt.Stmt) {
 	section_name := kernel.TEXT
 	name := ""
 
-	this.executable.CheckoutSection(section_name, name)
+	this.CheckoutSection(section_name, name)
 }
 
 func (this *InstructionAssigner) WalkZeroDoubleNumberStmt(stmt_ *stmt.Stmt) {
@@ -341,7 +436,7 @@ func (this *InstructionAssigner) WalkCiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitCi(op_code, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkDdciStmt(stmt_ *stmt.Stmt) {
@@ -357,7 +452,7 @@ func (this *InstructionAssigner) WalkDdciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitDdci(op_code, dc, db, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkDmaRriStmt(stmt_ *stmt.Stmt) {
@@ -372,7 +467,7 @@ func (this *InstructionAssigner) WalkDmaRriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitDmaRri(op_code, ra, rb, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkDrdiciStmt(stmt_ *stmt.Stmt) {
@@ -390,7 +485,7 @@ func (this *InstructionAssigner) WalkDrdiciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitDrdici(op_code, dc, ra, db, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkEdriStmt(stmt_ *stmt.Stmt) {
@@ -406,7 +501,7 @@ func (this *InstructionAssigner) WalkEdriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitEdri(op_code, endian, dc, ra, off)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkEridStmt(stmt_ *stmt.Stmt) {
@@ -422,7 +517,7 @@ func (this *InstructionAssigner) WalkEridStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErid(op_code, endian, ra, off, db)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkEriiStmt(stmt_ *stmt.Stmt) {
@@ -438,7 +533,7 @@ func (this *InstructionAssigner) WalkEriiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErii(op_code, endian, ra, off, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkErirStmt(stmt_ *stmt.Stmt) {
@@ -454,7 +549,7 @@ func (this *InstructionAssigner) WalkErirStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErir(op_code, endian, ra, off, rb)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkErriStmt(stmt_ *stmt.Stmt) {
@@ -470,7 +565,7 @@ func (this *InstructionAssigner) WalkErriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErri(op_code, endian, rc, ra, off)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkIStmt(stmt_ *stmt.Stmt) {
@@ -483,7 +578,7 @@ func (this *InstructionAssigner) WalkIStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitI(op_code, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkNopStmt(stmt_ *stmt.Stmt) {
@@ -495,7 +590,7 @@ func (this *InstructionAssigner) WalkNopStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitZ(op_code)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRciStmt(stmt_ *stmt.Stmt) {
@@ -517,7 +612,7 @@ func (this *InstructionAssigner) WalkRciStmt(stmt_ *stmt.Stmt) {
 	}
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRiciStmt(stmt_ *stmt.Stmt) {
@@ -533,7 +628,7 @@ func (this *InstructionAssigner) WalkRiciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitRici(op_code, ra, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRirciStmt(stmt_ *stmt.Stmt) {
@@ -556,7 +651,7 @@ func (this *InstructionAssigner) WalkRirciStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZirci(op_code, imm, ra, condition, pc)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRircStmt(stmt_ *stmt.Stmt) {
@@ -578,7 +673,7 @@ func (this *InstructionAssigner) WalkRircStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZirc(op_code, imm, ra, condition)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRirStmt(stmt_ *stmt.Stmt) {
@@ -599,7 +694,7 @@ func (this *InstructionAssigner) WalkRirStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZir(op_code, imm, ra)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrciStmt(stmt_ *stmt.Stmt) {
@@ -646,7 +741,7 @@ func (this *InstructionAssigner) WalkRrciStmt(stmt_ *stmt.Stmt) {
 		}
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrcStmt(stmt_ *stmt.Stmt) {
@@ -668,7 +763,7 @@ func (this *InstructionAssigner) WalkRrcStmt(stmt_ *stmt.Stmt) {
 	}
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRriciStmt(stmt_ *stmt.Stmt) {
@@ -692,7 +787,7 @@ func (this *InstructionAssigner) WalkRriciStmt(stmt_ *stmt.Stmt) {
 	}
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRricStmt(stmt_ *stmt.Stmt) {
@@ -722,7 +817,7 @@ func (this *InstructionAssigner) WalkRricStmt(stmt_ *stmt.Stmt) {
 		}
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRriStmt(stmt_ *stmt.Stmt) {
@@ -750,7 +845,7 @@ func (this *InstructionAssigner) WalkRriStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZri(op_code, ra, imm)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrrciStmt(stmt_ *stmt.Stmt) {
@@ -773,7 +868,7 @@ func (this *InstructionAssigner) WalkRrrciStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZrrci(op_code, ra, rb, condition, pc)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrrcStmt(stmt_ *stmt.Stmt) {
@@ -795,7 +890,7 @@ func (this *InstructionAssigner) WalkRrrcStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZrrc(op_code, ra, rb, condition)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrriciStmt(stmt_ *stmt.Stmt) {
@@ -819,7 +914,7 @@ func (this *InstructionAssigner) WalkRrriciStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZrrici(op_code, ra, rb, imm, condition, pc)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrriStmt(stmt_ *stmt.Stmt) {
@@ -841,7 +936,7 @@ func (this *InstructionAssigner) WalkRrriStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZrri(op_code, ra, rb, imm)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrrStmt(stmt_ *stmt.Stmt) {
@@ -862,7 +957,7 @@ func (this *InstructionAssigner) WalkRrrStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitZrr(op_code, ra, rb)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRrStmt(stmt_ *stmt.Stmt) {
@@ -908,7 +1003,7 @@ func (this *InstructionAssigner) WalkRrStmt(stmt_ *stmt.Stmt) {
 		}
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkRStmt(stmt_ *stmt.Stmt) {
@@ -928,7 +1023,7 @@ func (this *InstructionAssigner) WalkRStmt(stmt_ *stmt.Stmt) {
 	}
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSErriStmt(stmt_ *stmt.Stmt) {
@@ -945,7 +1040,7 @@ func (this *InstructionAssigner) WalkSErriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSErri(op_code, suffix, endian, dc, ra, off)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRciStmt(stmt_ *stmt.Stmt) {
@@ -961,7 +1056,7 @@ func (this *InstructionAssigner) WalkSRciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRci(op_code, suffix, dc, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRirciStmt(stmt_ *stmt.Stmt) {
@@ -979,7 +1074,7 @@ func (this *InstructionAssigner) WalkSRirciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRirci(op_code, suffix, dc, imm, ra, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRircStmt(stmt_ *stmt.Stmt) {
@@ -996,7 +1091,7 @@ func (this *InstructionAssigner) WalkSRircStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRirc(op_code, suffix, dc, imm, ra, condition)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrciStmt(stmt_ *stmt.Stmt) {
@@ -1026,7 +1121,7 @@ func (this *InstructionAssigner) WalkSRrciStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitSRrci(op_code, suffix, dc, ra, condition, pc)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrcStmt(stmt_ *stmt.Stmt) {
@@ -1042,7 +1137,7 @@ func (this *InstructionAssigner) WalkSRrcStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrc(op_code, suffix, dc, ra, condition)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRriciStmt(stmt_ *stmt.Stmt) {
@@ -1060,7 +1155,7 @@ func (this *InstructionAssigner) WalkSRriciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrici(op_code, suffix, dc, ra, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRricStmt(stmt_ *stmt.Stmt) {
@@ -1081,7 +1176,7 @@ func (this *InstructionAssigner) WalkSRricStmt(stmt_ *stmt.Stmt) {
 	}
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRriStmt(stmt_ *stmt.Stmt) {
@@ -1097,7 +1192,7 @@ func (this *InstructionAssigner) WalkSRriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRri(op_code, suffix, dc, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrrciStmt(stmt_ *stmt.Stmt) {
@@ -1115,7 +1210,7 @@ func (this *InstructionAssigner) WalkSRrrciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrrci(op_code, suffix, dc, ra, rb, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrrcStmt(stmt_ *stmt.Stmt) {
@@ -1132,7 +1227,7 @@ func (this *InstructionAssigner) WalkSRrrcStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrrc(op_code, suffix, dc, ra, rb, condition)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrriciStmt(stmt_ *stmt.Stmt) {
@@ -1151,7 +1246,7 @@ func (this *InstructionAssigner) WalkSRrriciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrrici(op_code, suffix, dc, ra, rb, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrriStmt(stmt_ *stmt.Stmt) {
@@ -1168,7 +1263,7 @@ func (this *InstructionAssigner) WalkSRrriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrri(op_code, suffix, dc, ra, rb, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrrStmt(stmt_ *stmt.Stmt) {
@@ -1184,7 +1279,7 @@ func (this *InstructionAssigner) WalkSRrrStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrr(op_code, suffix, dc, ra, rb)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRrStmt(stmt_ *stmt.Stmt) {
@@ -1215,7 +1310,7 @@ func (this *InstructionAssigner) WalkSRrStmt(stmt_ *stmt.Stmt) {
 		instruction_.InitSRr(op_code, suffix, dc, ra)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSRStmt(stmt_ *stmt.Stmt) {
@@ -1229,7 +1324,7 @@ func (this *InstructionAssigner) WalkSRStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSR(op_code, suffix, dc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkBkpStmt(stmt_ *stmt.Stmt) {
@@ -1240,7 +1335,7 @@ func (this *InstructionAssigner) WalkBkpStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitI(op_code, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkBootRiStmt(stmt_ *stmt.Stmt) {
@@ -1256,7 +1351,7 @@ func (this *InstructionAssigner) WalkBootRiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitRici(op_code, ra, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkCallRiStmt(stmt_ *stmt.Stmt) {
@@ -1276,7 +1371,7 @@ func (this *InstructionAssigner) WalkCallRiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitRri(op_code, rc, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkCallRrStmt(stmt_ *stmt.Stmt) {
@@ -1291,7 +1386,7 @@ func (this *InstructionAssigner) WalkCallRrStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitRri(op_code, rc, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkDivStepDrdiStmt(stmt_ *stmt.Stmt) {
@@ -1309,7 +1404,7 @@ func (this *InstructionAssigner) WalkDivStepDrdiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitDrdici(op_code, dc, ra, db, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkJeqRiiStmt(stmt_ *stmt.Stmt) {
@@ -1325,7 +1420,7 @@ func (this *InstructionAssigner) WalkJeqRiiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitZrici(op_code, ra, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkJeqRriStmt(stmt_ *stmt.Stmt) {
@@ -1341,7 +1436,7 @@ func (this *InstructionAssigner) WalkJeqRriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitZrrci(op_code, ra, rb, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkJnzRiStmt(stmt_ *stmt.Stmt) {
@@ -1366,7 +1461,7 @@ func (this *InstructionAssigner) WalkJnzRiStmt(stmt_ *stmt.Stmt) {
 		panic(err)
 	}
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkJumpIStmt(stmt_ *stmt.Stmt) {
@@ -1385,7 +1480,7 @@ func (this *InstructionAssigner) WalkJumpIStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitZri(op_code, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkJumpRStmt(stmt_ *stmt.Stmt) {
@@ -1399,7 +1494,7 @@ func (this *InstructionAssigner) WalkJumpRStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitZri(op_code, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkLbsRriStmt(stmt_ *stmt.Stmt) {
@@ -1415,7 +1510,7 @@ func (this *InstructionAssigner) WalkLbsRriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErri(op_code, endian, rc, ra, off)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkLbsSRriStmt(stmt_ *stmt.Stmt) {
@@ -1432,7 +1527,7 @@ func (this *InstructionAssigner) WalkLbsSRriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSErri(op_code, suffix, endian, dc, ra, off)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkLdDriStmt(stmt_ *stmt.Stmt) {
@@ -1448,7 +1543,7 @@ func (this *InstructionAssigner) WalkLdDriStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitEdri(op_code, endian, dc, ra, off)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkMovdDdStmt(stmt_ *stmt.Stmt) {
@@ -1464,7 +1559,7 @@ func (this *InstructionAssigner) WalkMovdDdStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitDdci(op_code, dc, db, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkMoveRiciStmt(stmt_ *stmt.Stmt) {
@@ -1486,7 +1581,7 @@ func (this *InstructionAssigner) WalkMoveRiciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitRrici(op_code, rc, ra, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkMoveRiStmt(stmt_ *stmt.Stmt) {
@@ -1506,7 +1601,7 @@ func (this *InstructionAssigner) WalkMoveRiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitRri(op_code, rc, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkMoveSRiciStmt(stmt_ *stmt.Stmt) {
@@ -1529,7 +1624,7 @@ func (this *InstructionAssigner) WalkMoveSRiciStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRrici(op_code, suffix, dc, ra, imm, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkMoveSRiStmt(stmt_ *stmt.Stmt) {
@@ -1551,7 +1646,7 @@ func (this *InstructionAssigner) WalkMoveSRiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitSRri(op_code, suffix, dc, ra, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSbIdRiiStmt(stmt_ *stmt.Stmt) {
@@ -1567,7 +1662,7 @@ func (this *InstructionAssigner) WalkSbIdRiiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErii(op_code, endian, ra, off, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSbIdRiStmt(stmt_ *stmt.Stmt) {
@@ -1583,7 +1678,7 @@ func (this *InstructionAssigner) WalkSbIdRiStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErii(op_code, endian, ra, off, imm)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSbRirStmt(stmt_ *stmt.Stmt) {
@@ -1599,7 +1694,7 @@ func (this *InstructionAssigner) WalkSbRirStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErir(op_code, endian, ra, off, rb)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkSdRidStmt(stmt_ *stmt.Stmt) {
@@ -1615,7 +1710,7 @@ func (this *InstructionAssigner) WalkSdRidStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitErid(op_code, endian, ra, off, db)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkStopStmt(stmt_ *stmt.Stmt) {
@@ -1627,7 +1722,7 @@ func (this *InstructionAssigner) WalkStopStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitCi(op_code, condition, pc)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkTimeCfgRStmt(stmt_ *stmt.Stmt) {
@@ -1640,7 +1735,7 @@ func (this *InstructionAssigner) WalkTimeCfgRStmt(stmt_ *stmt.Stmt) {
 	instruction_.InitZr(op_code, ra)
 
 	cur_label := this.executable.CurSection().CurLabel()
-	cur_label.Append(instruction_)
+	cur_label.AppendWithSourceLine(instruction_, this.cur_source_line)
 }
 
 func (this *InstructionAssigner) WalkLabelStmt(stmt_ *stmt.Stmt) {
@@ -25,6 +25,7 @@ func (this *LabelAssigner) Init() {
 	this.walker.RegisterStmtCallback(stmt.BYTE, this.WalkByteStmt)
 	this.walker.RegisterStmtCallback(stmt.LONG_PROGRAM_COUNTER, this.WalkLongProgramCounterStmt)
 	this.walker.RegisterStmtCallback(stmt.LONG_SECTION_NAME, this.WalkLongSectionNameStmt)
+	this.walker.RegisterStmtCallback(stmt.P2_ALIGN, this.WalkP2AlignStmt)
 	this.walker.RegisterStmtCallback(stmt.QUAD, this.WalkQuadStmt)
 	this.walker.RegisterStmtCallback(
 		stmt.SECTION_IDENTIFIER_NUMBER,
@@ -115,7 +116,17 @@ func (this *LabelAssigner) Init() {
 
 func (this *LabelAssigner) Assign(executable *kernel.Executable) {
 	this.executable = executable
-	this.walker.Walk(executable.Ast())
+
+	ast := executable.Ast()
+	for i := 0; i < ast.Size(); i++ {
+		stmt_ := ast.Get(i)
+
+		this.walker.WalkStmt(stmt_)
+
+		if cur_section := this.executable.CurSection(); cur_section != nil {
+			cur_section.AppendStmt(stmt_)
+		}
+	}
 }
 
 func (this *LabelAssigner) WalkAsciiStmt(stmt_ *stmt.Stmt) {
@@ -153,6 +164,22 @@ func (this *LabelAssigner) WalkLongSectionNameStmt(stmt_ *stmt.Stmt) {
 	cur_label.SetSize(cur_label.Size() + 4)
 }
 
+func (this *LabelAssigner) WalkP2AlignStmt(stmt_ *stmt.Stmt) {
+	p2_align_stmt := stmt_.P2AlignStmt()
+
+	program_counter_expr := p2_align_stmt.Expr().ProgramCounterExpr()
+	primary_expr := program_counter_expr.Expr().PrimaryExpr()
+	token := primary_expr.Token()
+	attribute := token.Attribute()
+
+	exponent, err := strconv.ParseInt(attribute, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+
+	this.executable.CurSection().SetAlignment(int64(1) << exponent)
+}
+
 func (this *LabelAssigner) WalkQuadStmt(stmt_ *stmt.Stmt) {
 	cur_label := this.executable.CurSection().CurLabel()
 	cur_label.SetSize(cur_label.Size() + 8)
@@ -3,10 +3,12 @@ package logic
 import (
 	"errors"
 	"fmt"
-	"math"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/linker/lds"
 	"uPIMulator/src/misc"
 )
 
@@ -16,7 +18,22 @@ type LinkerScript struct {
 	num_tasklets           int
 	min_access_granularity int64
 
+	script        *lds.Script
+	address_space *AddressSpace
+
 	linker_constants map[string]*LinkerConstant
+
+	executable *kernel.Executable
+
+	regions       map[string]*Region
+	region_names  []string
+	dot           int64
+	symbols       map[string]int64
+	symbol_memory map[string]Memory
+	placed        map[*kernel.Section]bool
+
+	output_section_addresses map[string]int64
+	output_section_sizes     map[string]int64
 }
 
 func (this *LinkerScript) Init(command_line_parser *misc.CommandLineParser) {
@@ -25,16 +42,85 @@ func (this *LinkerScript) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 	this.min_access_granularity = command_line_parser.IntParameter("min_access_granularity")
 
+	linker_script_filepath := command_line_parser.StringParameter("linker_script_filepath")
+	if linker_script_filepath == "" {
+		linker_script_filepath = filepath.Join(
+			command_line_parser.StringParameter("root_dirpath"),
+			"sdk",
+			"misc",
+			"dpu.lds",
+		)
+	}
+
+	parser := new(lds.Parser)
+	parser.Init()
+	this.script = parser.Parse(linker_script_filepath)
+
+	this.address_space = new(AddressSpace)
+	this.address_space.Init()
+
 	this.linker_constants = make(map[string]*LinkerConstant, 0)
 
 	this.InitLinkerConstants()
+	this.InitRegions()
 }
 
+// NOTE: the layout follows the linker script (sdk/misc/dpu.lds by default) rather than the fixed
+// layout of earlier versions of the simulator, which changes the following addresses:
+//   - __atomic_used_addr starts 200 bytes into the atomic memory that the runtime reserves
+//   - `. += MAX(8, .)` after .data.__sys_zero shifts the rest of WRAM by at least 8 bytes
+//   - stacks and the software cache are reserved for NR_TASKLETS rather than every tasklet
+//   - debug information and stack sizes are not loaded into MRAM anymore
+//
+// __sys_heap_pointer_reset and __sys_used_mram_end are still aligned to min_access_granularity.
 func (this *LinkerScript) Assign(executable *kernel.Executable) {
-	this.AssignAtomic(executable)
-	this.AssignIram(executable)
-	this.AssignWram(executable)
-	this.AssignMram(executable)
+	this.executable = executable
+
+	this.InitRegions()
+
+	this.dot = 0
+	this.symbols = make(map[string]int64, 0)
+	this.symbol_memory = make(map[string]Memory, 0)
+	this.placed = make(map[*kernel.Section]bool, 0)
+
+	this.output_section_addresses = make(map[string]int64, 0)
+	this.output_section_sizes = make(map[string]int64, 0)
+
+	for _, command := range this.script.Commands() {
+		this.Execute(command, nil)
+	}
+
+	for _, output_section := range this.script.OutputSections() {
+		this.AssignOutputSection(output_section)
+	}
+
+	this.AssignOrphans()
+	this.AssignLinkerConstants()
+	this.CheckEntry()
+}
+
+func (this *LinkerScript) Entry() string {
+	return this.script.Entry()
+}
+
+// NOTE: sections that a KEEP command matches survive the garbage collection of unused sections
+func (this *LinkerScript) IsKept(executable *kernel.Executable, section *kernel.Section) bool {
+	this.executable = executable
+
+	commands := make([]*lds.Command, 0)
+	commands = append(commands, this.script.Commands()...)
+	for _, output_section := range this.script.OutputSections() {
+		commands = append(commands, output_section.Commands()...)
+	}
+
+	for _, command := range commands {
+		if command.CommandType() == lds.INPUT_SECTION_COMMAND &&
+			command.IsKept() &&
+			this.Matches(command, section) {
+			return true
+		}
+	}
+	return false
 }
 
 func (this *LinkerScript) HasLinkerConstant(name string) bool {
@@ -42,10 +128,38 @@ func (this *LinkerScript) HasLinkerConstant(name string) bool {
 	return found
 }
 
+func (this *LinkerScript) AddLinkerConstant(name string) {
+	if this.HasLinkerConstant(name) {
+		err_msg := fmt.Sprintf("linker constant (%s) is already added", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.linker_constants[name] = new(LinkerConstant)
+	this.linker_constants[name].Init(name)
+}
+
 func (this *LinkerScript) LinkerConstant(name string) *LinkerConstant {
 	return this.linker_constants[name]
 }
 
+func (this *LinkerScript) LinkerConstants() []*LinkerConstant {
+	linker_constants := make([]*LinkerConstant, 0)
+	for _, linker_constant := range this.linker_constants {
+		linker_constants = append(linker_constants, linker_constant)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		return linker_constants[i].Name() < linker_constants[j].Name()
+	}
+
+	sort.Slice(linker_constants, sort_fn)
+
+	return linker_constants
+}
+
+// NOTE: NR_TASKLETS and STACK_SIZE_TASKLET_<n> are inputs of the linker script while every other
+// linker constant is a symbol that the linker script assigns
 func (this *LinkerScript) InitLinkerConstants() {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -62,336 +176,550 @@ func (this *LinkerScript) InitLinkerConstants() {
 
 		this.linker_constants[stack_size_tasklet] = new(LinkerConstant)
 		this.linker_constants[stack_size_tasklet].Init(stack_size_tasklet)
-		this.linker_constants[stack_size_tasklet].SetValue(stack_size)
-	}
-
-	this.linker_constants["__atomic_start_addr"] = new(LinkerConstant)
-	this.linker_constants["__atomic_start_addr"].Init("__atomic_start_addr")
 
-	this.linker_constants["__atomic_used_addr"] = new(LinkerConstant)
-	this.linker_constants["__atomic_used_addr"].Init("__atomic_used_addr")
+		if i < this.num_tasklets {
+			this.linker_constants[stack_size_tasklet].SetValue(stack_size)
+		}
+	}
 
-	this.linker_constants["__atomic_end_addr"] = new(LinkerConstant)
-	this.linker_constants["__atomic_end_addr"].Init("__atomic_end_addr")
+	for _, symbol := range this.script.Symbols() {
+		if !this.HasLinkerConstant(symbol) {
+			this.linker_constants[symbol] = new(LinkerConstant)
+			this.linker_constants[symbol].Init(symbol)
+		}
+	}
+}
 
-	this.linker_constants["__rodata_start_addr"] = new(LinkerConstant)
-	this.linker_constants["__rodata_start_addr"].Init("__rodata_start_addr")
+// NOTE: a LENGTH of an executable memory region counts the 64-bit instructions of UPMEM DPUs,
+// which take IramDataWidth bits each in the simulator
+func (this *LinkerScript) InitRegions() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
 
-	this.linker_constants["__rodata_end_addr"] = new(LinkerConstant)
-	this.linker_constants["__rodata_end_addr"].Init("__rodata_end_addr")
+	this.regions = make(map[string]*Region, 0)
+	this.region_names = make([]string, 0)
 
-	for i := 0; i < max_num_tasklets; i++ {
-		sys_stack_thread := "__sys_stack_thread_" + strconv.Itoa(i)
+	for _, memory_region := range this.script.MemoryRegions() {
+		origin := this.Evaluate(memory_region.Origin())
+		length := this.Evaluate(memory_region.Length())
+		memory := this.address_space.Memory(origin)
 
-		this.linker_constants[sys_stack_thread] = new(LinkerConstant)
-		this.linker_constants[sys_stack_thread].Init(sys_stack_thread)
-	}
+		if memory == IRAM_MEMORY {
+			length = length * int64(config_loader.IramDataWidth()) / 64
+		}
 
-	this.linker_constants["__sw_cache_buffer"] = new(LinkerConstant)
-	this.linker_constants["__sw_cache_buffer"].Init("__sw_cache_buffer")
+		if origin+length > this.address_space.Origin(memory)+this.address_space.Size(memory) {
+			err_msg := fmt.Sprintf(
+				"memory region (%s) does not fit in the simulated memory of %d bytes",
+				memory_region.Name(),
+				this.address_space.Size(memory),
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
 
-	this.linker_constants["__sys_heap_pointer_reset"] = new(LinkerConstant)
-	this.linker_constants["__sys_heap_pointer_reset"].Init("__sys_heap_pointer_reset")
+		region := new(Region)
+		region.Init(memory_region.Name(), memory_region.Attributes(), memory, origin, length)
 
-	this.linker_constants["__sys_used_mram_end"] = new(LinkerConstant)
-	this.linker_constants["__sys_used_mram_end"].Init("__sys_used_mram_end")
+		this.regions[region.Name()] = region
+		this.region_names = append(this.region_names, region.Name())
+	}
 }
 
-func (this *LinkerScript) AssignAtomic(executable *kernel.Executable) {
-	config_loader := new(misc.ConfigLoader)
-	config_loader.Init()
+// NOTE: regions are known from Init on, e.g. to bound the heap of WRAM in the simulator, and are
+// returned in the order of the MEMORY command of the linker script
+func (this *LinkerScript) Regions(memory Memory) []*Region {
+	regions := make([]*Region, 0)
+	for _, region_name := range this.region_names {
+		if region := this.regions[region_name]; region.Memory() == memory {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
 
-	cur_address := config_loader.AtomicOffset()
+func (this *LinkerScript) AssignOutputSection(output_section *lds.OutputSection) {
+	region, found := this.regions[output_section.Region()]
+	if !found {
+		err_msg := fmt.Sprintf(
+			"%s:%d: memory region (%s) of output section (%s) is not defined",
+			this.script.Path(),
+			output_section.Line(),
+			output_section.Region(),
+			output_section.Name(),
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
 
-	this.linker_constants["__atomic_start_addr"].SetValue(cur_address)
+	if output_section.Address() != nil {
+		this.dot = this.Evaluate(output_section.Address())
+	} else {
+		this.dot = region.Cursor()
+	}
 
-	this.linker_constants["__atomic_used_addr"].SetValue(cur_address)
+	address := this.dot
 
-	for section, _ := range executable.Sections(kernel.ATOMIC) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+	for _, command := range output_section.Commands() {
+		this.Execute(command, region)
 	}
 
-	this.linker_constants["__atomic_end_addr"].SetValue(cur_address)
+	this.output_section_addresses[output_section.Name()] = address
+	this.output_section_sizes[output_section.Name()] = this.dot - address
 
-	if cur_address >= config_loader.AtomicOffset()+config_loader.AtomicSize() {
-		err := errors.New("address is larger than the atomic end address")
-		panic(err)
-	}
+	region.SetCursor(this.dot)
+	this.CheckOverflow(region)
 }
 
-func (this *LinkerScript) AssignIram(executable *kernel.Executable) {
-	config_loader := new(misc.ConfigLoader)
-	config_loader.Init()
+// NOTE: the location counter is an absolute address rather than an offset from the output section,
+// which is equivalent for the ". = . + <n>", ". += <n>" and ". = ALIGN(<n>)" forms that dpu.lds uses
+func (this *LinkerScript) Execute(command *lds.Command, region *Region) {
+	if command.CommandType() == lds.ASSIGNMENT_COMMAND {
+		name := command.Name()
 
-	cur_address := config_loader.IramOffset()
-
-	bootstrap := executable.Section(kernel.TEXT, "__bootstrap")
-	if bootstrap == nil {
-		err := errors.New("bootstrap is not found")
-		panic(err)
-	}
+		if command.IsProvided() && this.executable.Label(name) != nil {
+			delete(this.linker_constants, name)
+			return
+		}
 
-	bootstrap.SetAddress(cur_address)
-	cur_address += bootstrap.Size()
+		var value int64
+		if command.Operator() == lds.ASSIGN {
+			value = this.Evaluate(command.Expr())
+		} else {
+			value = this.Operate(command.Operator(), this.Symbol(name), this.Evaluate(command.Expr()))
+		}
 
-	text_default := executable.Section(kernel.TEXT, "")
-	if text_default != nil {
-		text_default.SetAddress(cur_address)
-		cur_address += text_default.Size()
-	}
+		if name == "." {
+			this.dot = value
+		} else {
+			this.symbols[name] = value
 
-	for section, _ := range executable.Sections(kernel.TEXT) {
-		if section.Name() != "__bootstrap" && section.Name() != "" {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+			if region != nil {
+				this.symbol_memory[name] = region.Memory()
+			}
+		}
+	} else if command.CommandType() == lds.INPUT_SECTION_COMMAND {
+		if region == nil {
+			err_msg := fmt.Sprintf(
+				"%s:%d: input sections must be in an output section",
+				this.script.Path(),
+				command.Line(),
+			)
+			err := errors.New(err_msg)
+			panic(err)
 		}
-	}
 
-	if cur_address >= config_loader.IramOffset()+config_loader.IramSize() {
-		err := errors.New("address is larger than the IRAM end address")
-		panic(err)
+		for _, section := range this.Match(command) {
+			this.Place(section, region)
+		}
+	} else if command.CommandType() == lds.ASSERT_COMMAND {
+		if this.Evaluate(command.Expr()) == 0 {
+			err_msg := fmt.Sprintf("%s:%d: %s", this.script.Path(), command.Line(), command.Message())
+			err := errors.New(err_msg)
+			panic(err)
+		}
 	}
 }
 
-func (this *LinkerScript) AssignWram(executable *kernel.Executable) {
-	config_loader := new(misc.ConfigLoader)
-	config_loader.Init()
+func (this *LinkerScript) Match(command *lds.Command) []*kernel.Section {
+	sections := make([]*kernel.Section, 0)
 
-	cur_address := config_loader.WramOffset()
+	for section, _ := range this.executable.AllSections() {
+		if _, found := this.placed[section]; found {
+			continue
+		}
 
-	sys_zero := executable.Section(kernel.DATA, "__sys_zero")
-	if sys_zero != nil {
-		sys_zero.SetAddress(cur_address)
-		cur_address += sys_zero.Size()
+		if this.Matches(command, section) {
+			sections = append(sections, section)
+		}
 	}
 
-	immediate_memory := executable.Section(kernel.DATA, "immediate_memory")
-	if immediate_memory != nil {
-		immediate_memory.SetAddress(cur_address)
-		cur_address += immediate_memory.Size()
+	sort_fn := func(i int, j int) bool {
+		return sections[i].FullName() < sections[j].FullName()
 	}
 
-	for section, _ := range executable.Sections(kernel.DATA) {
-		if strings.Contains(section.Name(), "immediate_memory.") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
-		}
-	}
+	sort.Slice(sections, sort_fn)
 
-	this.linker_constants["__rodata_start_addr"].SetValue(cur_address)
+	return sections
+}
 
-	rodata_default := executable.Section(kernel.RODATA, "")
-	if rodata_default != nil {
-		rodata_default.SetAddress(cur_address)
-		cur_address += rodata_default.Size()
+func (this *LinkerScript) Matches(command *lds.Command, section *kernel.Section) bool {
+	if !this.MatchFile(command.FilePattern(), section) {
+		return false
 	}
 
-	for section, _ := range executable.Sections(kernel.RODATA) {
-		if section.Name() != "" {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+	for _, section_pattern := range command.SectionPatterns() {
+		if matched, err := filepath.Match(section_pattern, section.FullName()); err != nil {
+			panic(err)
+		} else if matched {
+			return true
 		}
 	}
+	return false
+}
 
-	this.linker_constants["__rodata_end_addr"].SetValue(cur_address)
-
-	bss_default := executable.Section(kernel.BSS, "")
-	if bss_default != nil {
-		bss_default.SetAddress(cur_address)
-		cur_address += bss_default.Size()
+func (this *LinkerScript) MatchFile(file_pattern string, section *kernel.Section) bool {
+	if file_pattern == "*" {
+		return true
 	}
 
-	for section, _ := range executable.Sections(kernel.BSS) {
-		if section.Name() != "" {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+	for _, origin := range this.executable.Origins(section) {
+		if matched, err := filepath.Match(file_pattern, origin); err != nil {
+			panic(err)
+		} else if matched {
+			return true
 		}
 	}
 
-	sys_keep := executable.Section(kernel.DATA, "__sys_keep")
-	if sys_keep != nil {
-		sys_keep.SetAddress(cur_address)
-		cur_address += sys_keep.Size()
-	}
+	return false
+}
 
-	data_default := executable.Section(kernel.DATA, "")
-	if data_default != nil {
-		data_default.SetAddress(cur_address)
-		cur_address += data_default.Size()
+// NOTE: IRAM sections are never padded since every instruction is IramDataWidth bits wide
+// regardless of the alignment that the relocatable asks for
+func (this *LinkerScript) Place(section *kernel.Section, region *Region) {
+	if region.Memory() != IRAM_MEMORY {
+		this.dot = this.Align(this.dot, section.Alignment())
 	}
 
-	for section, _ := range executable.Sections(kernel.DATA) {
-		if section.Name() != "__sys_zero" &&
-			section.Name() != "__sys_keep" &&
-			!strings.Contains(section.Name(), "immediate_memory") &&
-			section.Name() != "" {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+	section.SetAddress(this.Translate(region.Memory(), this.dot))
+	this.dot += section.Size()
+
+	this.placed[section] = true
+	region.AppendSection(section)
+}
+
+// NOTE: sections that the linker script does not mention are placed after every output section
+// of the memory region they naturally belong to while non-loaded sections stay at address 0
+func (this *LinkerScript) AssignOrphans() {
+	orphans := make([]*kernel.Section, 0)
+	for section, _ := range this.executable.AllSections() {
+		if _, found := this.placed[section]; !found {
+			orphans = append(orphans, section)
 		}
 	}
 
-	// TODO(bongjoon.hyun@gmail.com): figure out ".data.__sys_host" section
-
-	dpu_host := executable.Section(kernel.DPU_HOST, "")
-	if dpu_host != nil {
-		dpu_host.SetAddress(cur_address)
-		cur_address += dpu_host.Size()
+	sort_fn := func(i int, j int) bool {
+		return orphans[i].FullName() < orphans[j].FullName()
 	}
 
-	// TODO(bongjoon.hyun@gmail.com): figure out ".data.__sys_profilng" section
+	sort.Slice(orphans, sort_fn)
 
-	// TODO(bongjoon.hyun@gmail.com): figure out ".data.stacks" section
+	for _, orphan := range orphans {
+		if !orphan.IsLoaded() {
+			orphan.SetAddress(0)
+			this.placed[orphan] = true
+			continue
+		}
 
-	for i := 0; i < config_loader.MaxNumTasklets(); i++ {
-		sys_stack_thread := "__sys_stack_thread_" + strconv.Itoa(i)
-		this.linker_constants[sys_stack_thread].SetValue(cur_address)
+		memory := this.OrphanMemory(orphan)
 
-		stack_size_tasklet := "STACK_SIZE_TASKLET_" + strconv.Itoa(i)
-		cur_address += this.linker_constants[stack_size_tasklet].Value()
-	}
+		var region *Region = nil
+		for _, region_name := range this.region_names {
+			candidate := this.regions[region_name]
 
-	// TODO(bongjoon.hyun@gmail.com): figure out ".data.sw_cache" section
+			if candidate.Memory() == memory &&
+				(region == nil || candidate.Cursor()-candidate.Origin() > region.Cursor()-region.Origin()) {
+				region = candidate
+			}
+		}
 
-	this.linker_constants["__sw_cache_buffer"].SetValue(cur_address)
-	cur_address += int64(8 * config_loader.MaxNumTasklets())
+		if region == nil {
+			err_msg := fmt.Sprintf("no memory region can hold orphan section (%s)", orphan.FullName())
+			err := errors.New(err_msg)
+			panic(err)
+		}
 
-	// TODO(bongjoon.hyun@gmail.com): figure out ".data.heap_pointer_reset" section
+		this.dot = region.Cursor()
+		this.Place(orphan, region)
 
-	cur_address = int64(
-		math.Ceil(float64(cur_address)/float64(this.min_access_granularity)),
-	) * int64(
-		this.min_access_granularity,
-	)
-	this.linker_constants["__sys_heap_pointer_reset"].SetValue(cur_address)
+		region.SetCursor(this.dot)
+		this.CheckOverflow(region)
+	}
+}
 
-	if cur_address >= config_loader.WramOffset()+config_loader.WramSize() {
-		err := errors.New("address is larger than the WRAM end address")
-		panic(err)
+func (this *LinkerScript) OrphanMemory(section *kernel.Section) Memory {
+	section_name := section.SectionName()
+
+	if section_name == kernel.TEXT {
+		return IRAM_MEMORY
+	} else if section_name == kernel.ATOMIC {
+		return ATOMIC_MEMORY
+	} else if section_name == kernel.BSS ||
+		section_name == kernel.DATA ||
+		section_name == kernel.DPU_HOST ||
+		section_name == kernel.RODATA {
+		return WRAM_MEMORY
+	} else {
+		return MRAM_MEMORY
 	}
 }
 
-func (this *LinkerScript) AssignMram(executable *kernel.Executable) {
-	config_loader := new(misc.ConfigLoader)
-	config_loader.Init()
+func (this *LinkerScript) AssignLinkerConstants() {
+	for name, value := range this.symbols {
+		linker_constant, found := this.linker_constants[name]
 
-	cur_address := config_loader.MramOffset()
+		if !found {
+			continue
+		}
 
-	for section, _ := range executable.Sections(kernel.DEBUG_ABBREV) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+		if memory, found := this.symbol_memory[name]; found {
+			linker_constant.SetValue(this.Translate(memory, value))
+		} else {
+			linker_constant.SetValue(value)
+		}
 	}
 
-	for section, _ := range executable.Sections(kernel.DEBUG_FRAME) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+	for _, name := range []string{"__sys_heap_pointer_reset", "__sys_used_mram_end"} {
+		if linker_constant, found := this.linker_constants[name]; found {
+			linker_constant.SetValue(this.Align(linker_constant.Value(), this.min_access_granularity))
+		}
 	}
+}
 
-	for section, _ := range executable.Sections(kernel.DEBUG_INFO) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
-	}
+// NOTE: the simulator starts every thread at the beginning of IRAM
+func (this *LinkerScript) CheckEntry() {
+	entry := this.script.Entry()
 
-	for section, _ := range executable.Sections(kernel.DEBUG_LINE) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+	if entry == "" {
+		return
 	}
 
-	for section, _ := range executable.Sections(kernel.DEBUG_LOC) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
-	}
+	label := this.executable.Label(entry)
 
-	for section, _ := range executable.Sections(kernel.DEBUG_RANGES) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+	if label == nil {
+		err_msg := fmt.Sprintf("entry symbol (%s) is not defined", entry)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if label.Address() != this.address_space.Offset(IRAM_MEMORY) {
+		err_msg := fmt.Sprintf("entry symbol (%s) is not at the beginning of IRAM", entry)
+		err := errors.New(err_msg)
+		panic(err)
 	}
+}
 
-	for section, _ := range executable.Sections(kernel.DEBUG_STR) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+func (this *LinkerScript) CheckOverflow(region *Region) {
+	used := region.Cursor() - region.Origin()
+
+	if used > region.Length() {
+		err := this.OverflowError(region.Name(), region.Sections(), used, region.Length())
+		panic(err)
 	}
+}
 
-	for section, _ := range executable.Sections(kernel.STACK_SIZES) {
-		section.SetAddress(cur_address)
-		cur_address += section.Size()
+func (this *LinkerScript) Translate(memory Memory, address int64) int64 {
+	return this.address_space.Offset(memory) + address - this.address_space.Origin(memory)
+}
+
+func (this *LinkerScript) Align(value int64, alignment int64) int64 {
+	if alignment <= 1 {
+		return value
 	}
 
-	noinit := executable.Section(kernel.MRAM, "noinit")
-	if noinit != nil {
-		noinit.SetAddress(cur_address)
-		cur_address += noinit.Size()
+	return (value + alignment - 1) / alignment * alignment
+}
+
+func (this *LinkerScript) Symbol(name string) int64 {
+	if name == "." {
+		return this.dot
+	} else if value, found := this.symbols[name]; found {
+		return value
+	} else if linker_constant, found := this.linker_constants[name]; found &&
+		(name == "NR_TASKLETS" || strings.HasPrefix(name, "STACK_SIZE_TASKLET_")) {
+		return linker_constant.Value()
+	} else {
+		err_msg := fmt.Sprintf("symbol (%s) is not defined in %s", name, this.script.Path())
+		err := errors.New(err_msg)
+		panic(err)
 	}
+}
 
-	for section, _ := range executable.Sections(kernel.MRAM) {
-		if strings.Contains(section.Name(), "noinit.") &&
-			!strings.Contains(section.Name(), "noinit.keep") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+func (this *LinkerScript) Evaluate(expr *lds.Expr) int64 {
+	operands := expr.Operands()
+
+	if expr.ExprType() == lds.NUMBER_EXPR {
+		return expr.Value()
+	} else if expr.ExprType() == lds.SYMBOL_EXPR {
+		return this.Symbol(expr.Name())
+	} else if expr.ExprType() == lds.UNARY_EXPR {
+		value := this.Evaluate(operands[0])
+
+		if expr.Operator() == lds.MINUS {
+			return -value
+		} else if expr.Operator() == lds.TILDE {
+			return ^value
+		} else if value == 0 {
+			return 1
+		} else {
+			return 0
+		}
+	} else if expr.ExprType() == lds.BINARY_EXPR {
+		return this.Operate(expr.Operator(), this.Evaluate(operands[0]), this.Evaluate(operands[1]))
+	} else if expr.ExprType() == lds.TERNARY_EXPR {
+		if this.Evaluate(operands[0]) != 0 {
+			return this.Evaluate(operands[1])
+		} else {
+			return this.Evaluate(operands[2])
 		}
+	} else {
+		return this.Call(expr.Name(), operands)
 	}
+}
 
-	noinit_keep := executable.Section(kernel.MRAM, "noinit.keep")
-	if noinit_keep != nil {
-		noinit_keep.SetAddress(cur_address)
-		cur_address += noinit_keep.Size()
+func (this *LinkerScript) Operate(operator lds.TokenType, value1 int64, value2 int64) int64 {
+	bool_to_int64 := func(value bool) int64 {
+		if value {
+			return 1
+		} else {
+			return 0
+		}
 	}
 
-	for section, _ := range executable.Sections(kernel.MRAM) {
-		if strings.Contains(section.Name(), "noinit.keep") &&
-			!strings.Contains(section.Name(), "noinit.keep.") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+	if operator == lds.OR_OR {
+		return bool_to_int64(value1 != 0 || value2 != 0)
+	} else if operator == lds.AND_AND {
+		return bool_to_int64(value1 != 0 && value2 != 0)
+	} else if operator == lds.OR {
+		return value1 | value2
+	} else if operator == lds.AND {
+		return value1 & value2
+	} else if operator == lds.EQ {
+		return bool_to_int64(value1 == value2)
+	} else if operator == lds.NE {
+		return bool_to_int64(value1 != value2)
+	} else if operator == lds.LT {
+		return bool_to_int64(value1 < value2)
+	} else if operator == lds.LE {
+		return bool_to_int64(value1 <= value2)
+	} else if operator == lds.GT {
+		return bool_to_int64(value1 > value2)
+	} else if operator == lds.GE {
+		return bool_to_int64(value1 >= value2)
+	} else if operator == lds.SHL {
+		return value1 << value2
+	} else if operator == lds.SHR {
+		return value1 >> value2
+	} else if operator == lds.PLUS || operator == lds.ADD_ASSIGN {
+		return value1 + value2
+	} else if operator == lds.MINUS || operator == lds.SUB_ASSIGN {
+		return value1 - value2
+	} else if operator == lds.MUL || operator == lds.MUL_ASSIGN {
+		return value1 * value2
+	} else if operator == lds.DIV || operator == lds.DIV_ASSIGN || operator == lds.MOD {
+		if value2 == 0 {
+			err_msg := fmt.Sprintf("division by zero in %s", this.script.Path())
+			err := errors.New(err_msg)
+			panic(err)
 		}
-	}
 
-	for section, _ := range executable.Sections(kernel.MRAM) {
-		if strings.Contains(section.Name(), "noinit.keep.") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+		if operator == lds.MOD {
+			return value1 % value2
+		} else {
+			return value1 / value2
 		}
+	} else {
+		err := errors.New("operator is not valid")
+		panic(err)
 	}
+}
 
-	mram_default := executable.Section(kernel.MRAM, "")
-	if mram_default != nil {
-		mram_default.SetAddress(cur_address)
-		cur_address += mram_default.Size()
-	}
+func (this *LinkerScript) Call(name string, arguments []*lds.Expr) int64 {
+	if name == "ALIGN" && len(arguments) == 1 {
+		return this.Align(this.dot, this.Evaluate(arguments[0]))
+	} else if name == "ALIGN" && len(arguments) == 2 {
+		return this.Align(this.Evaluate(arguments[0]), this.Evaluate(arguments[1]))
+	} else if name == "MAX" && len(arguments) == 2 {
+		return max(this.Evaluate(arguments[0]), this.Evaluate(arguments[1]))
+	} else if name == "MIN" && len(arguments) == 2 {
+		return min(this.Evaluate(arguments[0]), this.Evaluate(arguments[1]))
+	} else if name == "ABSOLUTE" && len(arguments) == 1 {
+		return this.Evaluate(arguments[0])
+	} else if (name == "ORIGIN" || name == "LENGTH") && len(arguments) == 1 {
+		region, found := this.regions[arguments[0].Name()]
+
+		if !found {
+			err_msg := fmt.Sprintf("memory region (%s) is not defined", arguments[0].Name())
+			err := errors.New(err_msg)
+			panic(err)
+		}
 
-	for section, _ := range executable.Sections(kernel.MRAM) {
-		if section.Name() != "" &&
-			!strings.Contains(section.Name(), "noinit") &&
-			!strings.Contains(section.Name(), "keep") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+		if name == "ORIGIN" {
+			return region.Origin()
+		} else {
+			return region.Length()
+		}
+	} else if (name == "ADDR" || name == "SIZEOF") && len(arguments) == 1 {
+		address, found := this.output_section_addresses[arguments[0].Name()]
+
+		if !found {
+			err_msg := fmt.Sprintf("output section (%s) is not yet assigned", arguments[0].Name())
+			err := errors.New(err_msg)
+			panic(err)
 		}
-	}
 
-	for section, _ := range executable.Sections(kernel.MRAM) {
-		if strings.Contains(section.Name(), "keep") &&
-			!strings.Contains(section.Name(), "keep.") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+		if name == "ADDR" {
+			return address
+		} else {
+			return this.output_section_sizes[arguments[0].Name()]
 		}
+	} else if name == "DEFINED" && len(arguments) == 1 {
+		_, found := this.symbols[arguments[0].Name()]
+
+		if found || this.executable.Label(arguments[0].Name()) != nil {
+			return 1
+		} else {
+			return 0
+		}
+	} else {
+		err_msg := fmt.Sprintf("function (%s) is not supported in %s", name, this.script.Path())
+		err := errors.New(err_msg)
+		panic(err)
 	}
+}
 
-	for section, _ := range executable.Sections(kernel.MRAM) {
-		if strings.Contains(section.Name(), "keep.") {
-			section.SetAddress(cur_address)
-			cur_address += section.Size()
+// NOTE: an overflow names the largest sections of the memory since they are the ones worth shrinking
+func (this *LinkerScript) OverflowError(
+	memory_name string,
+	sections []*kernel.Section,
+	used int64,
+	size int64,
+) error {
+	sorted_sections := make([]*kernel.Section, len(sections))
+	copy(sorted_sections, sections)
+
+	sort_fn := func(i int, j int) bool {
+		if sorted_sections[i].Size() != sorted_sections[j].Size() {
+			return sorted_sections[i].Size() > sorted_sections[j].Size()
+		} else {
+			return sorted_sections[i].FullName() < sorted_sections[j].FullName()
 		}
 	}
 
-	cur_address = int64(
-		math.Ceil(float64(cur_address)/float64(this.min_access_granularity)),
-	) * this.min_access_granularity
+	sort.Slice(sorted_sections, sort_fn)
 
-	this.linker_constants["__sys_used_mram_end"].SetValue(cur_address)
+	largest_sections := make([]string, 0)
+	for i := 0; i < len(sorted_sections) && i < 5; i++ {
+		largest_section := fmt.Sprintf(
+			"%s (%d bytes",
+			sorted_sections[i].FullName(),
+			sorted_sections[i].Size(),
+		)
 
-	if cur_address >= config_loader.MramOffset()+config_loader.MramSize() {
-		err := errors.New("address is larger than the MRAM end address")
-		panic(err)
+		if origins := this.executable.Origins(sorted_sections[i]); len(origins) > 0 {
+			largest_section += " from " + strings.Join(origins, ", ")
+		}
+
+		largest_sections = append(largest_sections, largest_section+")")
 	}
+
+	err_msg := fmt.Sprintf(
+		"%s overflows by %d bytes: %d bytes used of %d bytes available; largest sections: %s",
+		memory_name,
+		used-size,
+		used,
+		size,
+		strings.Join(largest_sections, ", "),
+	)
+	return errors.New(err_msg)
 }
 
 func (this *LinkerScript) DumpValues(path string) {
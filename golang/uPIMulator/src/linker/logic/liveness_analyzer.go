@@ -25,6 +25,7 @@ func (this *LivenessAnalyzer) Init() {
 	this.walker.RegisterStmtCallback(stmt.GLOBAL, this.WalkGlobalStmt)
 	this.walker.RegisterStmtCallback(stmt.SET, this.WalkSetStmt)
 	this.walker.RegisterStmtCallback(stmt.LABEL, this.WalkLabelStmt)
+	this.walker.RegisterStmtCallback(stmt.WEAK, this.WalkWeakStmt)
 }
 
 func (this *LivenessAnalyzer) Analyze(relocatable *kernel.Relocatable) *kernel.Liveness {
@@ -43,6 +44,7 @@ func (this *LivenessAnalyzer) WalkPrimaryExpr(expr_ *expr.Expr) {
 	token := primary_expr.Token()
 	if token.TokenType() == lexer.IDENTIFIER {
 		this.liveness.AddUse(token.Attribute())
+		this.liveness.SetUseLine(token.Attribute(), token.Line())
 	}
 }
 
@@ -98,6 +100,7 @@ func (this *LivenessAnalyzer) WalkSetStmt(stmt_ *stmt.Stmt) {
 	}
 
 	this.liveness.AddDef(token1.Attribute())
+	this.liveness.SetDefLine(token1.Attribute(), token1.Line())
 	this.liveness.AddUse(token2.Attribute())
 }
 
@@ -121,5 +124,27 @@ func (this *LivenessAnalyzer) WalkLabelStmt(stmt_ *stmt.Stmt) {
 	attribute := token.Attribute()
 	if attribute != "__sys_used_mram_end" {
 		this.liveness.AddDef(attribute)
+		this.liveness.SetDefLine(attribute, token.Line())
 	}
 }
+
+func (this *LivenessAnalyzer) WalkWeakStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.WEAK {
+		err := errors.New("stmt type is not weak")
+		panic(err)
+	}
+
+	weak_stmt := stmt_.WeakStmt()
+
+	program_counter_expr := weak_stmt.Expr().ProgramCounterExpr()
+	primary_expr := program_counter_expr.Expr().PrimaryExpr()
+
+	token := primary_expr.Token()
+
+	if token.TokenType() != lexer.IDENTIFIER {
+		err := errors.New("token type is not identifier")
+		panic(err)
+	}
+
+	this.liveness.AddWeakSymbol(token.Attribute())
+}
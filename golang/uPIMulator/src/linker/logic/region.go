@@ -0,0 +1,65 @@
+package logic
+
+import (
+	"uPIMulator/src/linker/kernel"
+)
+
+type Region struct {
+	name       string
+	attributes string
+	memory     Memory
+
+	origin int64
+	length int64
+	cursor int64
+
+	sections []*kernel.Section
+}
+
+func (this *Region) Init(name string, attributes string, memory Memory, origin int64, length int64) {
+	this.name = name
+	this.attributes = attributes
+	this.memory = memory
+
+	this.origin = origin
+	this.length = length
+	this.cursor = origin
+
+	this.sections = make([]*kernel.Section, 0)
+}
+
+func (this *Region) Name() string {
+	return this.name
+}
+
+func (this *Region) Attributes() string {
+	return this.attributes
+}
+
+func (this *Region) Memory() Memory {
+	return this.memory
+}
+
+func (this *Region) Origin() int64 {
+	return this.origin
+}
+
+func (this *Region) Length() int64 {
+	return this.length
+}
+
+func (this *Region) Cursor() int64 {
+	return this.cursor
+}
+
+func (this *Region) SetCursor(cursor int64) {
+	this.cursor = cursor
+}
+
+func (this *Region) Sections() []*kernel.Section {
+	return this.sections
+}
+
+func (this *Region) AppendSection(section *kernel.Section) {
+	this.sections = append(this.sections, section)
+}
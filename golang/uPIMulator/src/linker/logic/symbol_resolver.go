@@ -0,0 +1,205 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"uPIMulator/src/linker/kernel"
+)
+
+type SymbolResolver struct {
+	linker_script *LinkerScript
+
+	executable            *kernel.Executable
+	sdk_relocatables      []*kernel.Relocatable
+	required_relocatables []*kernel.Relocatable
+
+	definitions map[string]*kernel.Relocatable
+	diagnostics []string
+}
+
+func (this *SymbolResolver) Init(
+	linker_script *LinkerScript,
+	sdk_relocatables map[string]*kernel.Relocatable,
+	required_relocatables []*kernel.Relocatable,
+) {
+	this.linker_script = linker_script
+	this.required_relocatables = required_relocatables
+
+	this.sdk_relocatables = make([]*kernel.Relocatable, 0)
+	for _, sdk_relocatable := range sdk_relocatables {
+		this.sdk_relocatables = append(this.sdk_relocatables, sdk_relocatable)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		return this.sdk_relocatables[i].Name() < this.sdk_relocatables[j].Name()
+	}
+
+	sort.Slice(this.sdk_relocatables, sort_fn)
+
+	this.definitions = make(map[string]*kernel.Relocatable, 0)
+	this.diagnostics = make([]string, 0)
+}
+
+// NOTE: SDK relocatables behave like the members of an archive, which are loaded only when they
+// define a symbol that is still undefined, and the entry symbol of the linker script (i.e., the
+// bootstrap of misc.crt0) counts as undefined until something defines it
+func (this *SymbolResolver) Resolve(executable *kernel.Executable) {
+	this.executable = executable
+
+	this.Define(executable.BenchmarkRelocatable())
+	for _, required_relocatable := range this.required_relocatables {
+		this.Load(required_relocatable)
+	}
+
+	if entry := this.linker_script.Entry(); entry != "" && !this.IsDefined(entry) {
+		if sdk_relocatable := this.Lookup(entry); sdk_relocatable != nil {
+			this.Load(sdk_relocatable)
+		} else {
+			this.diagnostics = append(
+				this.diagnostics,
+				fmt.Sprintf("undefined entry symbol `%s'", entry),
+			)
+		}
+	}
+
+	for has_loaded := true; has_loaded; {
+		has_loaded = false
+
+		for _, symbol := range this.UndefinedSymbols() {
+			if this.IsWeaklyReferenced(symbol) {
+				continue
+			}
+
+			if sdk_relocatable := this.Lookup(symbol); sdk_relocatable != nil {
+				this.Load(sdk_relocatable)
+				has_loaded = true
+			}
+		}
+	}
+
+	for _, symbol := range this.UndefinedSymbols() {
+		if this.IsWeaklyReferenced(symbol) {
+			this.linker_script.AddLinkerConstant(symbol)
+		} else {
+			for _, relocatable := range this.executable.Relocatables() {
+				if _, found := relocatable.Liveness().UnresolvedSymbols()[symbol]; found {
+					this.diagnostics = append(this.diagnostics, fmt.Sprintf(
+						"%s:%d: undefined reference to `%s'",
+						relocatable.Path(),
+						relocatable.Liveness().UseLine(symbol),
+						symbol,
+					))
+				}
+			}
+		}
+	}
+
+	if len(this.diagnostics) > 0 {
+		err := errors.New(strings.Join(this.diagnostics, "\n"))
+		panic(err)
+	}
+}
+
+func (this *SymbolResolver) Load(sdk_relocatable *kernel.Relocatable) {
+	if this.executable.HasSdkRelocatable(sdk_relocatable) {
+		return
+	}
+
+	this.executable.AddSdkRelocatable(sdk_relocatable)
+	this.Define(sdk_relocatable)
+}
+
+// NOTE: a strong definition overrides a weak one and the first of several weak definitions wins,
+// while two strong definitions of a symbol are an error
+func (this *SymbolResolver) Define(relocatable *kernel.Relocatable) {
+	liveness := relocatable.Liveness()
+
+	for _, symbol := range this.Sort(liveness.ExportedSymbols()) {
+		definition, found := this.definitions[symbol]
+
+		if !found {
+			this.definitions[symbol] = relocatable
+		} else if liveness.IsWeak(symbol) {
+			relocatable.DiscardSymbol(symbol)
+		} else if definition.Liveness().IsWeak(symbol) {
+			definition.DiscardSymbol(symbol)
+			this.definitions[symbol] = relocatable
+		} else {
+			this.diagnostics = append(this.diagnostics, fmt.Sprintf(
+				"%s:%d: multiple definition of `%s'; %s:%d: first defined here",
+				relocatable.Path(),
+				liveness.DefLine(symbol),
+				symbol,
+				definition.Path(),
+				definition.Liveness().DefLine(symbol),
+			))
+		}
+	}
+}
+
+func (this *SymbolResolver) IsDefined(symbol string) bool {
+	_, found := this.definitions[symbol]
+	return found || this.linker_script.HasLinkerConstant(symbol)
+}
+
+func (this *SymbolResolver) UndefinedSymbols() []string {
+	undefined_symbols := make(map[string]bool, 0)
+	for _, relocatable := range this.executable.Relocatables() {
+		for symbol, _ := range relocatable.Liveness().UnresolvedSymbols() {
+			if !this.IsDefined(symbol) {
+				undefined_symbols[symbol] = true
+			}
+		}
+	}
+	return this.Sort(undefined_symbols)
+}
+
+// NOTE: a symbol that every loaded relocatable references weakly does not load an SDK relocatable
+// and resolves to zero if nothing else defines it
+func (this *SymbolResolver) IsWeaklyReferenced(symbol string) bool {
+	for _, relocatable := range this.executable.Relocatables() {
+		liveness := relocatable.Liveness()
+
+		if _, found := liveness.UnresolvedSymbols()[symbol]; found && !liveness.IsWeak(symbol) {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *SymbolResolver) Lookup(symbol string) *kernel.Relocatable {
+	var weak_definition *kernel.Relocatable = nil
+
+	for _, sdk_relocatable := range this.sdk_relocatables {
+		if this.executable.HasSdkRelocatable(sdk_relocatable) {
+			continue
+		}
+
+		liveness := sdk_relocatable.Liveness()
+
+		if _, found := liveness.ExportedSymbols()[symbol]; !found {
+			continue
+		}
+
+		if !liveness.IsWeak(symbol) {
+			return sdk_relocatable
+		} else if weak_definition == nil {
+			weak_definition = sdk_relocatable
+		}
+	}
+
+	return weak_definition
+}
+
+func (this *SymbolResolver) Sort(symbols map[string]bool) []string {
+	sorted_symbols := make([]string, 0)
+	for symbol, _ := range symbols {
+		sorted_symbols = append(sorted_symbols, symbol)
+	}
+
+	sort.Strings(sorted_symbols)
+
+	return sorted_symbols
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 	"uPIMulator/src/linker/lexer"
 	"uPIMulator/src/linker/parser/expr"
 	"uPIMulator/src/linker/parser/stmt"
@@ -154,12 +155,18 @@ func (this *Parser) Init() {
 }
 
 func (this *Parser) Parse(token_stream *lexer.TokenStream) *Ast {
+	var line_token *lexer.Token = nil
+
 	for pos := 0; pos < token_stream.Size(); pos++ {
 		token := token_stream.Get(pos)
 
 		this.ReduceExpr(token)
 
 		if token.TokenType() != lexer.NEW_LINE {
+			if line_token == nil {
+				line_token = token
+			}
+
 			stack_item := new(StackItem)
 			stack_item.InitToken(token)
 
@@ -168,9 +175,14 @@ func (this *Parser) Parse(token_stream *lexer.TokenStream) *Ast {
 			this.ReduceStmt(token)
 
 			if !this.stack.AreStmts() {
-				err := errors.New("stack are not stmts")
-				panic(err)
+				if line_token == nil {
+					line_token = token
+				}
+
+				panic(this.SyntaxError(token_stream.Path(), line_token))
 			}
+
+			line_token = nil
 		}
 	}
 
@@ -182,6 +194,34 @@ func (this *Parser) Parse(token_stream *lexer.TokenStream) *Ast {
 	return this.stack.Accept()
 }
 
+// NOTE: a line that does not reduce to a stmt is reported at its first token that is left on the
+// stack, or at its first token if an expr is left instead
+func (this *Parser) SyntaxError(path string, line_token *lexer.Token) error {
+	token := line_token
+	if stack_item := this.stack.FirstNonStmt(); stack_item.StackItemType() == TOKEN {
+		token = stack_item.Token()
+	}
+
+	if token.TokenType() == lexer.NEW_LINE {
+		err_msg := fmt.Sprintf(
+			"%s:%d:%d: syntax error at the end of the line",
+			path,
+			token.Line(),
+			token.Column(),
+		)
+		return errors.New(err_msg)
+	}
+
+	err_msg := fmt.Sprintf(
+		"%s:%d:%d: syntax error at %q",
+		path,
+		token.Line(),
+		token.Column(),
+		token.Text(),
+	)
+	return errors.New(err_msg)
+}
+
 func (this *Parser) ReduceExpr(token *lexer.Token) {
 	for {
 		reducible_expr_rule, stack_items := this.table.FindReducibleExprRule(token)
@@ -557,7 +597,6 @@ func (this *Parser) RegisterConditionExpr() {
 					token_type == lexer.SO ||
 					token_type == lexer.SE ||
 					token_type == lexer.NC5 ||
-					token_type == lexer.NC5 ||
 					token_type == lexer.NC6 ||
 					token_type == lexer.NC7 ||
 					token_type == lexer.NC8 ||
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/linker/lexer"
+)
+
+func TestParserReportsSyntaxError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad_stmt.S")
+	source := "\tmove r0, 42\n\tadd r0, r0,\n\tstop true, __sys_end\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lexer_ := new(lexer.Lexer)
+	lexer_.Init()
+	token_stream := lexer_.Lex(path)
+
+	message := func() (message string) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				message = fmt.Sprint(recovered)
+			}
+		}()
+
+		parser := new(Parser)
+		parser.Init()
+		parser.Parse(token_stream)
+		return ""
+	}()
+
+	if expected := fmt.Sprintf("%s:2:2: syntax error at \"add\"", path); message != expected {
+		t.Errorf("error is %q, but %q is expected", message, expected)
+	}
+}
@@ -45,6 +45,17 @@ func (this *Stack) NonStmtSize() int {
 	return non_stmt_size
 }
 
+func (this *Stack) FirstNonStmt() *StackItem {
+	for _, stack_item := range this.stack_items {
+		if stack_item.StackItemType() != STMT {
+			return stack_item
+		}
+	}
+
+	err := errors.New("stack are stmts")
+	panic(err)
+}
+
 func (this *Stack) AreStmts() bool {
 	for _, stack_item := range this.stack_items {
 		if stack_item.StackItemType() == STMT {
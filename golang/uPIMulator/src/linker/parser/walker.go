@@ -39,188 +39,200 @@ func (this *Walker) RegisterStmtCallback(stmt_type stmt.StmtType, stmt_callback
 
 func (this *Walker) Walk(ast *Ast) {
 	for i := 0; i < ast.Size(); i++ {
-		stmt_ := ast.Get(i)
-
-		stmt_type := stmt_.StmtType()
-		if stmt_type == stmt.ASCII {
-			this.WalkAsciiStmt(stmt_)
-		} else if stmt_type == stmt.ASCIZ {
-			this.WalkAscizStmt(stmt_)
-		} else if stmt_type == stmt.BYTE {
-			this.WalkByteStmt(stmt_)
-		} else if stmt_type == stmt.GLOBAL {
-			this.WalkGlobalStmt(stmt_)
-		} else if stmt_type == stmt.LONG_PROGRAM_COUNTER {
-			this.WalkLongProgramCounterStmt(stmt_)
-		} else if stmt_type == stmt.LONG_SECTION_NAME {
-			this.WalkLongSectionNameStmt(stmt_)
-		} else if stmt_type == stmt.P2_ALIGN {
-			this.WalkP2AlignStmt(stmt_)
-		} else if stmt_type == stmt.QUAD {
-			this.WalkQuadStmt(stmt_)
-		} else if stmt_type == stmt.SECTION_IDENTIFIER_NUMBER {
-			this.WalkSectionIdentifierNumberStmt(stmt_)
-		} else if stmt_type == stmt.SECTION_IDENTIFIER {
-			this.WalkSectionIdentifierStmt(stmt_)
-		} else if stmt_type == stmt.SECTION_STACK_SIZES {
-			this.WalkSectionStackSizesStmt(stmt_)
-		} else if stmt_type == stmt.SECTION_STRING_NUMBER {
-			this.WalkSectionStringNumberStmt(stmt_)
-		} else if stmt_type == stmt.SECTION_STRING {
-			this.WalkSectionStringStmt(stmt_)
-		} else if stmt_type == stmt.SET {
-			this.WalkSetStmt(stmt_)
-		} else if stmt_type == stmt.SHORT {
-			this.WalkShortStmt(stmt_)
-		} else if stmt_type == stmt.SIZE {
-			this.WalkSizeStmt(stmt_)
-		} else if stmt_type == stmt.TEXT {
-			this.WalkTextStmt(stmt_)
-		} else if stmt_type == stmt.ZERO_DOUBLE_NUMBER {
-			this.WalkZeroDoubleNumberStmt(stmt_)
-		} else if stmt_type == stmt.ZERO_SINGLE_NUMBER {
-			this.WalkZeroSingleNumberStmt(stmt_)
-		} else if stmt_type == stmt.CI {
-			this.WalkCiStmt(stmt_)
-		} else if stmt_type == stmt.DDCI {
-			this.WalkDdciStmt(stmt_)
-		} else if stmt_type == stmt.DMA_RRI {
-			this.WalkDmaRriStmt(stmt_)
-		} else if stmt_type == stmt.DRDICI {
-			this.WalkDrdiciStmt(stmt_)
-		} else if stmt_type == stmt.EDRI {
-			this.WalkEdriStmt(stmt_)
-		} else if stmt_type == stmt.ERID {
-			this.WalkEridStmt(stmt_)
-		} else if stmt_type == stmt.ERII {
-			this.WalkEriiStmt(stmt_)
-		} else if stmt_type == stmt.ERIR {
-			this.WalkErirStmt(stmt_)
-		} else if stmt_type == stmt.ERRI {
-			this.WalkErriStmt(stmt_)
-		} else if stmt_type == stmt.I {
-			this.WalkIStmt(stmt_)
-		} else if stmt_type == stmt.NOP {
-			this.WalkNopStmt(stmt_)
-		} else if stmt_type == stmt.RCI {
-			this.WalkRciStmt(stmt_)
-		} else if stmt_type == stmt.RICI {
-			this.WalkRiciStmt(stmt_)
-		} else if stmt_type == stmt.RIRCI {
-			this.WalkRirciStmt(stmt_)
-		} else if stmt_type == stmt.RIRC {
-			this.WalkRircStmt(stmt_)
-		} else if stmt_type == stmt.RIR {
-			this.WalkRirStmt(stmt_)
-		} else if stmt_type == stmt.RRCI {
-			this.WalkRrciStmt(stmt_)
-		} else if stmt_type == stmt.RRC {
-			this.WalkRrcStmt(stmt_)
-		} else if stmt_type == stmt.RRICI {
-			this.WalkRriciStmt(stmt_)
-		} else if stmt_type == stmt.RRIC {
-			this.WalkRricStmt(stmt_)
-		} else if stmt_type == stmt.RRI {
-			this.WalkRriStmt(stmt_)
-		} else if stmt_type == stmt.RRRCI {
-			this.WalkRrrciStmt(stmt_)
-		} else if stmt_type == stmt.RRRC {
-			this.WalkRrrcStmt(stmt_)
-		} else if stmt_type == stmt.RRRICI {
-			this.WalkRrriciStmt(stmt_)
-		} else if stmt_type == stmt.RRRI {
-			this.WalkRrriStmt(stmt_)
-		} else if stmt_type == stmt.RRR {
-			this.WalkRrrStmt(stmt_)
-		} else if stmt_type == stmt.RR {
-			this.WalkRrStmt(stmt_)
-		} else if stmt_type == stmt.R {
-			this.WalkRStmt(stmt_)
-		} else if stmt_type == stmt.S_ERRI {
-			this.WalkSErriStmt(stmt_)
-		} else if stmt_type == stmt.S_RCI {
-			this.WalkSRciStmt(stmt_)
-		} else if stmt_type == stmt.S_RIRCI {
-			this.WalkSRirciStmt(stmt_)
-		} else if stmt_type == stmt.S_RIRC {
-			this.WalkSRircStmt(stmt_)
-		} else if stmt_type == stmt.S_RRCI {
-			this.WalkSRrciStmt(stmt_)
-		} else if stmt_type == stmt.S_RRC {
-			this.WalkSRrcStmt(stmt_)
-		} else if stmt_type == stmt.S_RRICI {
-			this.WalkSRriciStmt(stmt_)
-		} else if stmt_type == stmt.S_RRIC {
-			this.WalkSRricStmt(stmt_)
-		} else if stmt_type == stmt.S_RRI {
-			this.WalkSRriStmt(stmt_)
-		} else if stmt_type == stmt.S_RRRCI {
-			this.WalkSRrrciStmt(stmt_)
-		} else if stmt_type == stmt.S_RRRC {
-			this.WalkSRrrcStmt(stmt_)
-		} else if stmt_type == stmt.S_RRRICI {
-			this.WalkSRrriciStmt(stmt_)
-		} else if stmt_type == stmt.S_RRRI {
-			this.WalkSRrriStmt(stmt_)
-		} else if stmt_type == stmt.S_RRR {
-			this.WalkSRrrStmt(stmt_)
-		} else if stmt_type == stmt.S_RR {
-			this.WalkSRrStmt(stmt_)
-		} else if stmt_type == stmt.S_R {
-			this.WalkSRStmt(stmt_)
-		} else if stmt_type == stmt.BKP {
-			this.WalkBkpStmt(stmt_)
-		} else if stmt_type == stmt.BOOT_RI {
-			this.WalkBootRiStmt(stmt_)
-		} else if stmt_type == stmt.CALL_RI {
-			this.WalkCallRiStmt(stmt_)
-		} else if stmt_type == stmt.CALL_RR {
-			this.WalkCallRrStmt(stmt_)
-		} else if stmt_type == stmt.DIV_STEP_DRDI {
-			this.WalkDivStepDrdiStmt(stmt_)
-		} else if stmt_type == stmt.JEQ_RII {
-			this.WalkJeqRiiStmt(stmt_)
-		} else if stmt_type == stmt.JEQ_RRI {
-			this.WalkJeqRriStmt(stmt_)
-		} else if stmt_type == stmt.JNZ_RI {
-			this.WalkJnzRiStmt(stmt_)
-		} else if stmt_type == stmt.JUMP_I {
-			this.WalkJumpIStmt(stmt_)
-		} else if stmt_type == stmt.JUMP_R {
-			this.WalkJumpRStmt(stmt_)
-		} else if stmt_type == stmt.LBS_RRI {
-			this.WalkLbsRriStmt(stmt_)
-		} else if stmt_type == stmt.LBS_S_RRI {
-			this.WalkLbsSRriStmt(stmt_)
-		} else if stmt_type == stmt.LD_DRI {
-			this.WalkLdDriStmt(stmt_)
-		} else if stmt_type == stmt.MOVD_DD {
-			this.WalkMovdDdStmt(stmt_)
-		} else if stmt_type == stmt.MOVE_RICI {
-			this.WalkMoveRiciStmt(stmt_)
-		} else if stmt_type == stmt.MOVE_RI {
-			this.WalkMoveRiStmt(stmt_)
-		} else if stmt_type == stmt.MOVE_S_RICI {
-			this.WalkMoveSRiciStmt(stmt_)
-		} else if stmt_type == stmt.MOVE_S_RI {
-			this.WalkMoveSRiStmt(stmt_)
-		} else if stmt_type == stmt.SB_ID_RII {
-			this.WalkSbIdRiiStmt(stmt_)
-		} else if stmt_type == stmt.SB_ID_RI {
-			this.WalkSbIdRiStmt(stmt_)
-		} else if stmt_type == stmt.SB_RIR {
-			this.WalkSbRirStmt(stmt_)
-		} else if stmt_type == stmt.SD_RID {
-			this.WalkSdRidStmt(stmt_)
-		} else if stmt_type == stmt.STOP {
-			this.WalkStopStmt(stmt_)
-		} else if stmt_type == stmt.TIME_CFG_R {
-			this.WalkTimeCfgRStmt(stmt_)
-		} else if stmt_type == stmt.LABEL {
-			this.WalkLabelStmt(stmt_)
-		} else {
-			continue
-		}
+		this.WalkStmt(ast.Get(i))
+	}
+}
+
+func (this *Walker) WalkStmt(stmt_ *stmt.Stmt) {
+	stmt_type := stmt_.StmtType()
+	if stmt_type == stmt.ASCII {
+		this.WalkAsciiStmt(stmt_)
+	} else if stmt_type == stmt.ASCIZ {
+		this.WalkAscizStmt(stmt_)
+	} else if stmt_type == stmt.BYTE {
+		this.WalkByteStmt(stmt_)
+	} else if stmt_type == stmt.FILE_NUMBER {
+		this.WalkFileNumberStmt(stmt_)
+	} else if stmt_type == stmt.FILE_STRING {
+		this.WalkFileStringStmt(stmt_)
+	} else if stmt_type == stmt.GLOBAL {
+		this.WalkGlobalStmt(stmt_)
+	} else if stmt_type == stmt.LOC_IS_STMT {
+		this.WalkLocIsStmtStmt(stmt_)
+	} else if stmt_type == stmt.LOC_NUMBER {
+		this.WalkLocNumberStmt(stmt_)
+	} else if stmt_type == stmt.LOC_PROLOGUE_END {
+		this.WalkLocPrologueEndStmt(stmt_)
+	} else if stmt_type == stmt.LONG_PROGRAM_COUNTER {
+		this.WalkLongProgramCounterStmt(stmt_)
+	} else if stmt_type == stmt.LONG_SECTION_NAME {
+		this.WalkLongSectionNameStmt(stmt_)
+	} else if stmt_type == stmt.P2_ALIGN {
+		this.WalkP2AlignStmt(stmt_)
+	} else if stmt_type == stmt.QUAD {
+		this.WalkQuadStmt(stmt_)
+	} else if stmt_type == stmt.SECTION_IDENTIFIER_NUMBER {
+		this.WalkSectionIdentifierNumberStmt(stmt_)
+	} else if stmt_type == stmt.SECTION_IDENTIFIER {
+		this.WalkSectionIdentifierStmt(stmt_)
+	} else if stmt_type == stmt.SECTION_STACK_SIZES {
+		this.WalkSectionStackSizesStmt(stmt_)
+	} else if stmt_type == stmt.SECTION_STRING_NUMBER {
+		this.WalkSectionStringNumberStmt(stmt_)
+	} else if stmt_type == stmt.SECTION_STRING {
+		this.WalkSectionStringStmt(stmt_)
+	} else if stmt_type == stmt.SET {
+		this.WalkSetStmt(stmt_)
+	} else if stmt_type == stmt.SHORT {
+		this.WalkShortStmt(stmt_)
+	} else if stmt_type == stmt.SIZE {
+		this.WalkSizeStmt(stmt_)
+	} else if stmt_type == stmt.TEXT {
+		this.WalkTextStmt(stmt_)
+	} else if stmt_type == stmt.WEAK {
+		this.WalkWeakStmt(stmt_)
+	} else if stmt_type == stmt.ZERO_DOUBLE_NUMBER {
+		this.WalkZeroDoubleNumberStmt(stmt_)
+	} else if stmt_type == stmt.ZERO_SINGLE_NUMBER {
+		this.WalkZeroSingleNumberStmt(stmt_)
+	} else if stmt_type == stmt.CI {
+		this.WalkCiStmt(stmt_)
+	} else if stmt_type == stmt.DDCI {
+		this.WalkDdciStmt(stmt_)
+	} else if stmt_type == stmt.DMA_RRI {
+		this.WalkDmaRriStmt(stmt_)
+	} else if stmt_type == stmt.DRDICI {
+		this.WalkDrdiciStmt(stmt_)
+	} else if stmt_type == stmt.EDRI {
+		this.WalkEdriStmt(stmt_)
+	} else if stmt_type == stmt.ERID {
+		this.WalkEridStmt(stmt_)
+	} else if stmt_type == stmt.ERII {
+		this.WalkEriiStmt(stmt_)
+	} else if stmt_type == stmt.ERIR {
+		this.WalkErirStmt(stmt_)
+	} else if stmt_type == stmt.ERRI {
+		this.WalkErriStmt(stmt_)
+	} else if stmt_type == stmt.I {
+		this.WalkIStmt(stmt_)
+	} else if stmt_type == stmt.NOP {
+		this.WalkNopStmt(stmt_)
+	} else if stmt_type == stmt.RCI {
+		this.WalkRciStmt(stmt_)
+	} else if stmt_type == stmt.RICI {
+		this.WalkRiciStmt(stmt_)
+	} else if stmt_type == stmt.RIRCI {
+		this.WalkRirciStmt(stmt_)
+	} else if stmt_type == stmt.RIRC {
+		this.WalkRircStmt(stmt_)
+	} else if stmt_type == stmt.RIR {
+		this.WalkRirStmt(stmt_)
+	} else if stmt_type == stmt.RRCI {
+		this.WalkRrciStmt(stmt_)
+	} else if stmt_type == stmt.RRC {
+		this.WalkRrcStmt(stmt_)
+	} else if stmt_type == stmt.RRICI {
+		this.WalkRriciStmt(stmt_)
+	} else if stmt_type == stmt.RRIC {
+		this.WalkRricStmt(stmt_)
+	} else if stmt_type == stmt.RRI {
+		this.WalkRriStmt(stmt_)
+	} else if stmt_type == stmt.RRRCI {
+		this.WalkRrrciStmt(stmt_)
+	} else if stmt_type == stmt.RRRC {
+		this.WalkRrrcStmt(stmt_)
+	} else if stmt_type == stmt.RRRICI {
+		this.WalkRrriciStmt(stmt_)
+	} else if stmt_type == stmt.RRRI {
+		this.WalkRrriStmt(stmt_)
+	} else if stmt_type == stmt.RRR {
+		this.WalkRrrStmt(stmt_)
+	} else if stmt_type == stmt.RR {
+		this.WalkRrStmt(stmt_)
+	} else if stmt_type == stmt.R {
+		this.WalkRStmt(stmt_)
+	} else if stmt_type == stmt.S_ERRI {
+		this.WalkSErriStmt(stmt_)
+	} else if stmt_type == stmt.S_RCI {
+		this.WalkSRciStmt(stmt_)
+	} else if stmt_type == stmt.S_RIRCI {
+		this.WalkSRirciStmt(stmt_)
+	} else if stmt_type == stmt.S_RIRC {
+		this.WalkSRircStmt(stmt_)
+	} else if stmt_type == stmt.S_RRCI {
+		this.WalkSRrciStmt(stmt_)
+	} else if stmt_type == stmt.S_RRC {
+		this.WalkSRrcStmt(stmt_)
+	} else if stmt_type == stmt.S_RRICI {
+		this.WalkSRriciStmt(stmt_)
+	} else if stmt_type == stmt.S_RRIC {
+		this.WalkSRricStmt(stmt_)
+	} else if stmt_type == stmt.S_RRI {
+		this.WalkSRriStmt(stmt_)
+	} else if stmt_type == stmt.S_RRRCI {
+		this.WalkSRrrciStmt(stmt_)
+	} else if stmt_type == stmt.S_RRRC {
+		this.WalkSRrrcStmt(stmt_)
+	} else if stmt_type == stmt.S_RRRICI {
+		this.WalkSRrriciStmt(stmt_)
+	} else if stmt_type == stmt.S_RRRI {
+		this.WalkSRrriStmt(stmt_)
+	} else if stmt_type == stmt.S_RRR {
+		this.WalkSRrrStmt(stmt_)
+	} else if stmt_type == stmt.S_RR {
+		this.WalkSRrStmt(stmt_)
+	} else if stmt_type == stmt.S_R {
+		this.WalkSRStmt(stmt_)
+	} else if stmt_type == stmt.BKP {
+		this.WalkBkpStmt(stmt_)
+	} else if stmt_type == stmt.BOOT_RI {
+		this.WalkBootRiStmt(stmt_)
+	} else if stmt_type == stmt.CALL_RI {
+		this.WalkCallRiStmt(stmt_)
+	} else if stmt_type == stmt.CALL_RR {
+		this.WalkCallRrStmt(stmt_)
+	} else if stmt_type == stmt.DIV_STEP_DRDI {
+		this.WalkDivStepDrdiStmt(stmt_)
+	} else if stmt_type == stmt.JEQ_RII {
+		this.WalkJeqRiiStmt(stmt_)
+	} else if stmt_type == stmt.JEQ_RRI {
+		this.WalkJeqRriStmt(stmt_)
+	} else if stmt_type == stmt.JNZ_RI {
+		this.WalkJnzRiStmt(stmt_)
+	} else if stmt_type == stmt.JUMP_I {
+		this.WalkJumpIStmt(stmt_)
+	} else if stmt_type == stmt.JUMP_R {
+		this.WalkJumpRStmt(stmt_)
+	} else if stmt_type == stmt.LBS_RRI {
+		this.WalkLbsRriStmt(stmt_)
+	} else if stmt_type == stmt.LBS_S_RRI {
+		this.WalkLbsSRriStmt(stmt_)
+	} else if stmt_type == stmt.LD_DRI {
+		this.WalkLdDriStmt(stmt_)
+	} else if stmt_type == stmt.MOVD_DD {
+		this.WalkMovdDdStmt(stmt_)
+	} else if stmt_type == stmt.MOVE_RICI {
+		this.WalkMoveRiciStmt(stmt_)
+	} else if stmt_type == stmt.MOVE_RI {
+		this.WalkMoveRiStmt(stmt_)
+	} else if stmt_type == stmt.MOVE_S_RICI {
+		this.WalkMoveSRiciStmt(stmt_)
+	} else if stmt_type == stmt.MOVE_S_RI {
+		this.WalkMoveSRiStmt(stmt_)
+	} else if stmt_type == stmt.SB_ID_RII {
+		this.WalkSbIdRiiStmt(stmt_)
+	} else if stmt_type == stmt.SB_ID_RI {
+		this.WalkSbIdRiStmt(stmt_)
+	} else if stmt_type == stmt.SB_RIR {
+		this.WalkSbRirStmt(stmt_)
+	} else if stmt_type == stmt.SD_RID {
+		this.WalkSdRidStmt(stmt_)
+	} else if stmt_type == stmt.STOP {
+		this.WalkStopStmt(stmt_)
+	} else if stmt_type == stmt.TIME_CFG_R {
+		this.WalkTimeCfgRStmt(stmt_)
+	} else if stmt_type == stmt.LABEL {
+		this.WalkLabelStmt(stmt_)
 	}
 }
 
@@ -352,6 +364,34 @@ func (this *Walker) WalkByteStmt(stmt_ *stmt.Stmt) {
 	this.WalkProgramCounterExpr(expr_)
 }
 
+func (this *Walker) WalkFileNumberStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.FILE_NUMBER {
+		err := errors.New("stmt type is not a file number stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.FILE_NUMBER]; found {
+		stmt_callback(stmt_)
+	}
+
+	file_number_stmt := stmt_.FileNumberStmt()
+
+	expr_ := file_number_stmt.Expr()
+
+	this.WalkProgramCounterExpr(expr_)
+}
+
+func (this *Walker) WalkFileStringStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.FILE_STRING {
+		err := errors.New("stmt type is not a file string stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.FILE_STRING]; found {
+		stmt_callback(stmt_)
+	}
+}
+
 func (this *Walker) WalkGlobalStmt(stmt_ *stmt.Stmt) {
 	if stmt_.StmtType() != stmt.GLOBAL {
 		err := errors.New("stmt type is not a global stmt")
@@ -369,6 +409,71 @@ func (this *Walker) WalkGlobalStmt(stmt_ *stmt.Stmt) {
 	this.WalkProgramCounterExpr(expr_)
 }
 
+func (this *Walker) WalkLocIsStmtStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.LOC_IS_STMT {
+		err := errors.New("stmt type is not a loc is_stmt stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.LOC_IS_STMT]; found {
+		stmt_callback(stmt_)
+	}
+
+	loc_is_stmt_stmt := stmt_.LocIsStmtStmt()
+
+	expr1 := loc_is_stmt_stmt.Expr1()
+	expr2 := loc_is_stmt_stmt.Expr2()
+	expr3 := loc_is_stmt_stmt.Expr3()
+	expr4 := loc_is_stmt_stmt.Expr4()
+
+	this.WalkProgramCounterExpr(expr1)
+	this.WalkProgramCounterExpr(expr2)
+	this.WalkProgramCounterExpr(expr3)
+	this.WalkProgramCounterExpr(expr4)
+}
+
+func (this *Walker) WalkLocNumberStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.LOC_NUMBER {
+		err := errors.New("stmt type is not a loc number stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.LOC_NUMBER]; found {
+		stmt_callback(stmt_)
+	}
+
+	loc_number_stmt := stmt_.LocNumberStmt()
+
+	expr1 := loc_number_stmt.Expr1()
+	expr2 := loc_number_stmt.Expr2()
+	expr3 := loc_number_stmt.Expr3()
+
+	this.WalkProgramCounterExpr(expr1)
+	this.WalkProgramCounterExpr(expr2)
+	this.WalkProgramCounterExpr(expr3)
+}
+
+func (this *Walker) WalkLocPrologueEndStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.LOC_PROLOGUE_END {
+		err := errors.New("stmt type is not a loc prologue_end stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.LOC_PROLOGUE_END]; found {
+		stmt_callback(stmt_)
+	}
+
+	loc_prologue_end_stmt := stmt_.LocPrologueEndStmt()
+
+	expr1 := loc_prologue_end_stmt.Expr1()
+	expr2 := loc_prologue_end_stmt.Expr2()
+	expr3 := loc_prologue_end_stmt.Expr3()
+
+	this.WalkProgramCounterExpr(expr1)
+	this.WalkProgramCounterExpr(expr2)
+	this.WalkProgramCounterExpr(expr3)
+}
+
 func (this *Walker) WalkLongProgramCounterStmt(stmt_ *stmt.Stmt) {
 	if stmt_.StmtType() != stmt.LONG_PROGRAM_COUNTER {
 		err := errors.New("stmt type is not a long program counter stmt")
@@ -545,6 +650,23 @@ func (this *Walker) WalkTextStmt(stmt_ *stmt.Stmt) {
 	}
 }
 
+func (this *Walker) WalkWeakStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.WEAK {
+		err := errors.New("stmt type is not a weak stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.WEAK]; found {
+		stmt_callback(stmt_)
+	}
+
+	weak_stmt := stmt_.WeakStmt()
+
+	expr_ := weak_stmt.Expr()
+
+	this.WalkProgramCounterExpr(expr_)
+}
+
 func (this *Walker) WalkZeroDoubleNumberStmt(stmt_ *stmt.Stmt) {
 	if stmt_.StmtType() != stmt.ZERO_DOUBLE_NUMBER {
 		err := errors.New("stmt type is not a zero double number stmt")
@@ -0,0 +1,238 @@
+package linker
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/linker/lexer"
+)
+
+// NOTE: bump the version whenever the lexer or the liveness analyzer changes what they produce so
+// that entries of older simulators are no longer hit
+const RELOCATABLE_CACHE_VERSION = 3
+
+type RelocatableCache struct {
+	dirpath string
+}
+
+type cachedToken struct {
+	TokenType int
+	Attribute string
+	Line      int
+	Column    int
+}
+
+type cachedLiveness struct {
+	Defs          []string
+	DefLines      map[string]int
+	Uses          []string
+	UseLines      map[string]int
+	GlobalSymbols []string
+	WeakSymbols   []string
+}
+
+type cacheEntry struct {
+	Version  int
+	Tokens   []cachedToken
+	Liveness *cachedLiveness
+}
+
+func (this *RelocatableCache) Init(dirpath string) {
+	this.dirpath = dirpath
+
+	if err := os.MkdirAll(dirpath, 0755); err != nil {
+		panic(err)
+	}
+}
+
+// NOTE: entries are keyed by the content of the assembly, so editing or rebuilding the SDK or a
+// benchmark invalidates their entries without any bookkeeping
+func (this *RelocatableCache) Key(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(fmt.Sprintf("v%d\n", RELOCATABLE_CACHE_VERSION)))
+	hash.Write(content)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (this *RelocatableCache) Load(relocatable *kernel.Relocatable) bool {
+	cache_entry := this.Read(this.Key(relocatable.Path()))
+
+	if cache_entry == nil || cache_entry.Liveness == nil {
+		return false
+	}
+
+	relocatable.SetTokenStream(this.DecodeTokenStream(relocatable.Path(), cache_entry.Tokens))
+	relocatable.SetLiveness(this.DecodeLiveness(cache_entry.Liveness))
+	return true
+}
+
+func (this *RelocatableCache) Store(relocatable *kernel.Relocatable) {
+	cache_entry := new(cacheEntry)
+	cache_entry.Version = RELOCATABLE_CACHE_VERSION
+	cache_entry.Tokens = this.EncodeTokenStream(relocatable.TokenStream())
+	cache_entry.Liveness = this.EncodeLiveness(relocatable.Liveness())
+
+	this.Write(this.Key(relocatable.Path()), cache_entry)
+}
+
+func (this *RelocatableCache) LoadTokenStream(path string) *lexer.TokenStream {
+	cache_entry := this.Read(this.Key(path))
+
+	if cache_entry == nil {
+		return nil
+	}
+
+	return this.DecodeTokenStream(path, cache_entry.Tokens)
+}
+
+func (this *RelocatableCache) StoreTokenStream(path string, token_stream *lexer.TokenStream) {
+	cache_entry := new(cacheEntry)
+	cache_entry.Version = RELOCATABLE_CACHE_VERSION
+	cache_entry.Tokens = this.EncodeTokenStream(token_stream)
+
+	this.Write(this.Key(path), cache_entry)
+}
+
+func (this *RelocatableCache) Read(key string) *cacheEntry {
+	file, open_err := os.Open(filepath.Join(this.dirpath, key+".gob"))
+	if open_err != nil {
+		return nil
+	}
+
+	cache_entry := new(cacheEntry)
+	decode_err := gob.NewDecoder(file).Decode(cache_entry)
+	file.Close()
+
+	if decode_err != nil {
+		return nil
+	}
+
+	if cache_entry.Version != RELOCATABLE_CACHE_VERSION {
+		return nil
+	}
+
+	return cache_entry
+}
+
+// NOTE: an entry is written to a temporary file and renamed so that simulators that run
+// concurrently never read a partially written entry
+func (this *RelocatableCache) Write(key string, cache_entry *cacheEntry) {
+	file, create_err := os.CreateTemp(this.dirpath, key+".*.tmp")
+	if create_err != nil {
+		panic(create_err)
+	}
+
+	if err := gob.NewEncoder(file).Encode(cache_entry); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		panic(err)
+	}
+
+	if err := file.Close(); err != nil {
+		panic(err)
+	}
+
+	if err := os.Rename(file.Name(), filepath.Join(this.dirpath, key+".gob")); err != nil {
+		panic(err)
+	}
+}
+
+func (this *RelocatableCache) EncodeTokenStream(token_stream *lexer.TokenStream) []cachedToken {
+	if token_stream == nil {
+		err := errors.New("token stream is not set")
+		panic(err)
+	}
+
+	cached_tokens := make([]cachedToken, token_stream.Size())
+	for i := 0; i < token_stream.Size(); i++ {
+		token := token_stream.Get(i)
+
+		cached_tokens[i] = cachedToken{
+			TokenType: int(token.TokenType()),
+			Attribute: token.Attribute(),
+			Line:      token.Line(),
+			Column:    token.Column(),
+		}
+	}
+	return cached_tokens
+}
+
+func (this *RelocatableCache) DecodeTokenStream(
+	path string,
+	cached_tokens []cachedToken,
+) *lexer.TokenStream {
+	token_stream := new(lexer.TokenStream)
+	token_stream.Init()
+	token_stream.SetPath(path)
+
+	for _, cached_token := range cached_tokens {
+		token := new(lexer.Token)
+		token.Init(lexer.TokenType(cached_token.TokenType), cached_token.Attribute)
+		token.SetLine(cached_token.Line)
+		token.SetColumn(cached_token.Column)
+
+		token_stream.Append(token)
+	}
+	return token_stream
+}
+
+func (this *RelocatableCache) EncodeLiveness(liveness *kernel.Liveness) *cachedLiveness {
+	cached_liveness := new(cachedLiveness)
+	cached_liveness.Defs = this.Keys(liveness.Defs())
+	cached_liveness.DefLines = liveness.DefLines()
+	cached_liveness.Uses = this.Keys(liveness.Uses())
+	cached_liveness.UseLines = liveness.UseLines()
+	cached_liveness.GlobalSymbols = this.Keys(liveness.GlobalSymbols())
+	cached_liveness.WeakSymbols = this.Keys(liveness.WeakSymbols())
+	return cached_liveness
+}
+
+func (this *RelocatableCache) DecodeLiveness(cached_liveness *cachedLiveness) *kernel.Liveness {
+	liveness := new(kernel.Liveness)
+	liveness.Init()
+
+	for _, def := range cached_liveness.Defs {
+		liveness.AddDef(def)
+	}
+
+	for def, line := range cached_liveness.DefLines {
+		liveness.SetDefLine(def, line)
+	}
+
+	for _, use := range cached_liveness.Uses {
+		liveness.AddUse(use)
+	}
+
+	for use, line := range cached_liveness.UseLines {
+		liveness.SetUseLine(use, line)
+	}
+
+	for _, global_symbol := range cached_liveness.GlobalSymbols {
+		liveness.AddGlobalSymbol(global_symbol)
+	}
+
+	for _, weak_symbol := range cached_liveness.WeakSymbols {
+		liveness.AddWeakSymbol(weak_symbol)
+	}
+
+	return liveness
+}
+
+func (this *RelocatableCache) Keys(symbols map[string]bool) []string {
+	keys := make([]string, 0)
+	for symbol, _ := range symbols {
+		keys = append(keys, symbol)
+	}
+	return keys
+}
@@ -5,14 +5,46 @@ import (
 	"os"
 	"path/filepath"
 	"uPIMulator/src/assembler"
+	_ "uPIMulator/src/assembler/prim"
 	"uPIMulator/src/compiler"
 	"uPIMulator/src/linker"
 	"uPIMulator/src/misc"
+	"uPIMulator/src/objdump"
 	"uPIMulator/src/simulator"
 )
 
 func main() {
 	command_line_parser := InitCommandLineParser()
+
+	// NOTE: "objdump" as the first argument disassembles the images in bin_dirpath (or the
+	// executable in elf_filepath) instead of running a simulation
+	if len(os.Args) > 1 && os.Args[1] == "objdump" {
+		command_line_parser.Parse(os.Args[1:])
+
+		if command_line_parser.IsArgSet("help") {
+			fmt.Printf("%s", objdump.Usage())
+			return
+		}
+
+		objdump_ := new(objdump.Objdump)
+		objdump_.Init(command_line_parser)
+		objdump_.Dump()
+
+		return
+	}
+
+	// NOTE: "as" as the first argument links the assembly files in asm_filepaths (and the SDK
+	// objects they need) into the images in bin_dirpath instead of running a simulation
+	if len(os.Args) > 1 && os.Args[1] == "as" {
+		command_line_parser.Parse(os.Args[1:])
+
+		linker_ := new(linker.Linker)
+		linker_.Init(command_line_parser)
+		linker_.Link()
+
+		return
+	}
+
 	command_line_parser.Parse(os.Args)
 
 	if command_line_parser.IsArgSet("help") {
@@ -41,13 +73,21 @@ func main() {
 		options_file_dumper.Init(options_filepath)
 		options_file_dumper.WriteLines([]string{command_line_parser.StringifyOptions()})
 
-		compiler_ := new(compiler.Compiler)
-		compiler_.Init(command_line_parser)
-		compiler_.Compile()
-
-		linker_ := new(linker.Linker)
-		linker_.Init(command_line_parser)
-		linker_.Link()
+		if command_line_parser.StringParameter("elf_filepath") == "" {
+			if command_line_parser.StringParameter("asm_filepaths") == "" {
+				compiler_ := new(compiler.Compiler)
+				compiler_.Init(command_line_parser)
+				compiler_.Compile()
+			}
+
+			linker_ := new(linker.Linker)
+			linker_.Init(command_line_parser)
+			linker_.Link()
+		} else {
+			elf_loader := new(linker.ElfLoader)
+			elf_loader.Init(command_line_parser)
+			elf_loader.Load()
+		}
 
 		assembler_ := new(assembler.Assembler)
 		assembler_.Init(command_line_parser)
@@ -80,6 +120,20 @@ func InitCommandLineParser() *misc.CommandLineParser {
 
 	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "benchmark name")
 
+	// NOTE: Explanation of verify policy
+	// strict: stops the simulation when an execution's outputs mismatch the expected outputs
+	// report: reports every mismatch in verification.txt and continues the simulation
+	// off: does not compare the outputs
+	command_line_parser.AddOption(misc.STRING, "verify", "strict", "output verification policy")
+
+	// NOTE: Explanation of memory check policy
+	// strict: stops the simulation at the first out-of-bounds or uninitialized WRAM/MRAM access
+	// report: reports every violation in memory_check.txt and continues the simulation
+	// off: does not check memory accesses
+	command_line_parser.AddOption(misc.STRING, "memory_check", "off", "memory safety check policy")
+	command_line_parser.AddOption(misc.BOOL, "detect_races", "false",
+		"report WRAM accesses of tasklets that are not ordered by acquire/release or boot/resume")
+
 	command_line_parser.AddOption(misc.INT, "num_channels", "1", "number of PIM memory channels")
 	command_line_parser.AddOption(
 		misc.INT,
@@ -92,6 +146,26 @@ func InitCommandLineParser() *misc.CommandLineParser {
 	command_line_parser.AddOption(misc.INT, "num_tasklets", "1", "number of tasklets")
 	command_line_parser.AddOption(misc.STRING, "data_prep_params", "8192",
 		"data preparation parameter")
+	command_line_parser.AddOption(misc.STRING, "spec_filepath", "",
+		"path to a JSON benchmark spec used instead of a registered assemblable")
+	command_line_parser.AddOption(misc.STRING, "elf_filepath", "",
+		"path to a DPU ELF executable, whose IRAM is in the simulator's instruction encoding, "+
+			"loaded instead of compiling and linking the benchmark")
+	command_line_parser.AddOption(misc.STRING, "asm_filepaths", "",
+		"comma-separated assembly files linked instead of compiling the benchmark")
+	command_line_parser.AddOption(misc.STRING, "sdk_objects", "",
+		"comma-separated SDK objects (e.g., stdlib.stdio) always linked with the assembly files")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "",
+		"path to the linker script (defaults to sdk/misc/dpu.lds under the root directory)")
+	command_line_parser.AddOption(misc.STRING, "cache_dirpath", "",
+		"path to the cache of lexed relocatables (defaults to uPIMulator under the user cache directory)")
+	command_line_parser.AddOption(misc.BOOL, "gc_sections", "false",
+		"remove sections that are not reachable from the entry symbol or kept by the linker script")
+	command_line_parser.AddOption(misc.BOOL, "profile", "false",
+		"attribute cycles and DMA bytes to PCs and dump profile.txt, profile.folded and profile.pb.gz")
+	command_line_parser.AddOption(misc.INT, "seed", "0", "random seed for data preparation")
+	command_line_parser.AddOption(misc.STRING, "scaling", "strong",
+		"data preparation scaling (strong or weak)")
 
 	command_line_parser.AddOption(
 		misc.STRING,
@@ -25,7 +25,7 @@ func (this *CommandLineParser) AddOption(
 	help_msg string,
 ) {
 	if _, found := this.command_line_options[option]; found {
-		err_msg := fmt.Sprintf("option (%s) is already added to the parser")
+		err_msg := fmt.Sprintf("option (%s) is already added to the parser", option)
 		err := errors.New(err_msg)
 		panic(err)
 	}
@@ -111,6 +111,20 @@ func (this *CommandLineParser) DataPrepParams() []int {
 	return data_prep_params
 }
 
+func (this *CommandLineParser) IsStrongScaling() bool {
+	scaling := this.StringParameter("scaling")
+
+	if scaling == "strong" {
+		return true
+	} else if scaling == "weak" {
+		return false
+	} else {
+		err_msg := fmt.Sprintf("scaling (%s) is not strong or weak", scaling)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
 func (this *CommandLineParser) IsArgSet(arg string) bool {
 	if _, found := this.args[arg]; found {
 		return true
@@ -142,8 +156,8 @@ func (this *CommandLineParser) Args() []string {
 func (this *CommandLineParser) StringifyOptions() string {
 	str := "OPTIONS\n"
 
-	for option, command_line_option := range this.command_line_options {
-		str += option + "  -->  " + command_line_option.Parameter() + "\n"
+	for _, option := range this.Options() {
+		str += option + "  -->  " + this.command_line_options[option].Parameter() + "\n"
 	}
 
 	return str
@@ -20,6 +20,25 @@ func (this *CommandLineValidator) Validate() {
 		panic(err)
 	}
 
+	if verify := this.command_line_parser.StringParameter("verify"); verify != "strict" &&
+		verify != "report" &&
+		verify != "off" {
+		err := errors.New("verify is not strict, report, or off")
+		panic(err)
+	}
+
+	if scaling := this.command_line_parser.StringParameter("scaling"); scaling != "strong" &&
+		scaling != "weak" {
+		err := errors.New("scaling is not strong or weak")
+		panic(err)
+	}
+
+	memory_check := this.command_line_parser.StringParameter("memory_check")
+	if memory_check != "strict" && memory_check != "report" && memory_check != "off" {
+		err := errors.New("memory_check is not strict, report, or off")
+		panic(err)
+	}
+
 	if this.command_line_parser.IntParameter("num_channels") <= 0 {
 		err := errors.New("num_channels <= 0")
 		panic(err)
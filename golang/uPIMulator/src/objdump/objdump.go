@@ -0,0 +1,335 @@
+package objdump
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/linker"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/logic"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: the usage of the objdump subcommand, printed for -help
+func Usage() string {
+	str := "usage: objdump [--bin_dirpath <dirpath> | --elf_filepath <filepath>]\n"
+	str += "disassembles IRAM and dumps the atomic, WRAM and MRAM images of a linked DPU kernel\n"
+	str += "each instruction is printed as its address, its raw IRAM word and its assembly, where\n"
+	str += "the raw IRAM word is the whole 96-bit word of the simulator's own encoding, most\n"
+	str += "significant byte first, rather than the 48-bit encoding of the UPMEM ISA\n"
+	return str
+}
+
+type Objdump struct {
+	atomic *encoding.ByteStream
+	iram   *encoding.ByteStream
+	wram   *encoding.ByteStream
+	mram   *encoding.ByteStream
+
+	addresses   map[string]int64
+	label_names []string
+	labels      map[int64][]string
+}
+
+func (this *Objdump) Init(command_line_parser *misc.CommandLineParser) {
+	if elf_filepath := command_line_parser.StringParameter("elf_filepath"); elf_filepath != "" {
+		elf_loader := new(linker.ElfLoader)
+		elf_loader.Init(command_line_parser)
+		elf_loader.Read()
+
+		this.InitImages(
+			elf_loader.ByteStream(logic.ATOMIC_MEMORY),
+			elf_loader.ByteStream(logic.IRAM_MEMORY),
+			elf_loader.ByteStream(logic.WRAM_MEMORY),
+			elf_loader.ByteStream(logic.MRAM_MEMORY),
+			elf_loader.Addresses(),
+		)
+	} else {
+		bin_dirpath := command_line_parser.StringParameter("bin_dirpath")
+
+		this.InitImages(
+			this.ReadByteStream(filepath.Join(bin_dirpath, "atomic.bin")),
+			this.ReadByteStream(filepath.Join(bin_dirpath, "iram.bin")),
+			this.ReadByteStream(filepath.Join(bin_dirpath, "wram.bin")),
+			this.ReadByteStream(filepath.Join(bin_dirpath, "mram.bin")),
+			this.ReadAddresses(filepath.Join(bin_dirpath, "addresses.txt")),
+		)
+	}
+}
+
+func (this *Objdump) InitImages(
+	atomic *encoding.ByteStream,
+	iram *encoding.ByteStream,
+	wram *encoding.ByteStream,
+	mram *encoding.ByteStream,
+	addresses map[string]int64,
+) {
+	this.atomic = atomic
+	this.iram = iram
+	this.wram = wram
+	this.mram = mram
+
+	this.addresses = addresses
+
+	this.label_names = make([]string, 0)
+	for label_name, _ := range this.addresses {
+		this.label_names = append(this.label_names, label_name)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		address_i := this.addresses[this.label_names[i]]
+		address_j := this.addresses[this.label_names[j]]
+
+		if address_i != address_j {
+			return address_i < address_j
+		} else {
+			return this.label_names[i] < this.label_names[j]
+		}
+	}
+
+	sort.Slice(this.label_names, sort_fn)
+
+	this.labels = make(map[int64][]string, 0)
+	for _, label_name := range this.label_names {
+		address := this.addresses[label_name]
+		this.labels[address] = append(this.labels[address], label_name)
+	}
+}
+
+func (this *Objdump) ReadByteStream(path string) *encoding.ByteStream {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, line := range file_scanner.ReadLines() {
+		value, err := strconv.Atoi(line)
+
+		if err != nil {
+			panic(err)
+		}
+
+		byte_stream.Append(uint8(value))
+	}
+
+	return byte_stream
+}
+
+func (this *Objdump) ReadAddresses(path string) map[string]int64 {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	addresses := make(map[string]int64, 0)
+
+	for _, line := range file_scanner.ReadLines() {
+		words := strings.Split(line, ": ")
+
+		if len(words) != 2 {
+			err_msg := fmt.Sprintf("line (%s) of %s is not valid", line, path)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		address, err := strconv.ParseInt(words[1], 10, 64)
+
+		if err != nil {
+			panic(err)
+		}
+
+		addresses[words[0]] = address
+	}
+
+	return addresses
+}
+
+func (this *Objdump) NumInstructions() int {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return int(this.iram.Size() / int64(config_loader.IramDataWidth()/8))
+}
+
+func (this *Objdump) InstructionAddress(index int) int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return config_loader.IramOffset() + int64(index*config_loader.IramDataWidth()/8)
+}
+
+func (this *Objdump) RawInstruction(index int) *encoding.ByteStream {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := config_loader.IramDataWidth() / 8
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for i := 0; i < iram_data_size; i++ {
+		byte_stream.Append(this.iram.Get(index*iram_data_size + i))
+	}
+
+	return byte_stream
+}
+
+func (this *Objdump) Instruction(index int) *instruction.Instruction {
+	instruction_ := new(instruction.Instruction)
+	instruction_.Decode(this.RawInstruction(index))
+	return instruction_
+}
+
+func (this *Objdump) LabelNames(address int64) []string {
+	return this.labels[address]
+}
+
+// NOTE: a symbolic address is the closest label at or below the address within the same memory
+func (this *Objdump) Symbolize(address int64) string {
+	begin, end := this.Memory(address)
+
+	symbol := ""
+	for _, label_name := range this.label_names {
+		label_address := this.addresses[label_name]
+
+		if label_address > address {
+			break
+		} else if begin <= label_address && label_address < end {
+			if label_address == address {
+				symbol = label_name
+			} else {
+				symbol = fmt.Sprintf("%s+%d", label_name, address-label_address)
+			}
+		}
+	}
+
+	return symbol
+}
+
+func (this *Objdump) Memory(address int64) (int64, int64) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	offsets := []int64{
+		config_loader.AtomicOffset(),
+		config_loader.IramOffset(),
+		config_loader.WramOffset(),
+		config_loader.MramOffset(),
+	}
+	sizes := []int64{
+		config_loader.AtomicSize(),
+		config_loader.IramSize(),
+		config_loader.WramSize(),
+		config_loader.MramSize(),
+	}
+
+	for i, offset := range offsets {
+		if offset <= address && address < offset+sizes[i] {
+			return offset, offset + sizes[i]
+		}
+	}
+
+	return address, address + 1
+}
+
+// NOTE: the raw instruction is the whole 96-bit IRAM word, most significant byte first, since the
+// linker packs the fields of an instruction in a layout of its own rather than the 48-bit one of
+// the UPMEM ISA, where the fields do not fit in 48 bits, e.g. a RRICI instruction with a 24-bit
+// immediate and a 32-bit pc spans 86 bits
+func (this *Objdump) StringifyRaw(byte_stream *encoding.ByteStream) string {
+	str := ""
+	for i := int(byte_stream.Size()) - 1; i >= 0; i-- {
+		str += fmt.Sprintf("%02x", byte_stream.Get(i))
+	}
+	return str
+}
+
+func (this *Objdump) StringifyInstruction(index int) string {
+	instruction_ := this.Instruction(index)
+
+	str := fmt.Sprintf(
+		"%8d:  %s  %s",
+		this.InstructionAddress(index),
+		this.StringifyRaw(this.RawInstruction(index)),
+		instruction_.Stringify(),
+	)
+
+	if instruction_.Pc() != nil {
+		if symbol := this.Symbolize(instruction_.Pc().Value()); symbol != "" {
+			str += fmt.Sprintf("  ; <%s>", symbol)
+		}
+	}
+
+	return str
+}
+
+func (this *Objdump) Disassemble() []string {
+	lines := []string{"Disassembly of IRAM:"}
+
+	for i := 0; i < this.NumInstructions(); i++ {
+		for _, label_name := range this.LabelNames(this.InstructionAddress(i)) {
+			lines = append(lines, "", fmt.Sprintf("<%s>:", label_name))
+		}
+
+		lines = append(lines, this.StringifyInstruction(i))
+	}
+
+	return lines
+}
+
+func (this *Objdump) DumpData(name string, offset int64, byte_stream *encoding.ByteStream) []string {
+	lines := []string{fmt.Sprintf("Contents of %s:", name)}
+
+	row := ""
+	row_address := offset
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		address := offset + i
+		label_names := this.LabelNames(address)
+
+		if row != "" && (len(label_names) > 0 || address%8 == 0) {
+			lines = append(lines, fmt.Sprintf("%8d: %s", row_address, row))
+			row = ""
+		}
+
+		for _, label_name := range label_names {
+			lines = append(lines, "", fmt.Sprintf("<%s>:", label_name))
+		}
+
+		if row == "" {
+			row_address = address
+		}
+		row += fmt.Sprintf(" %02x", byte_stream.Get(int(i)))
+	}
+
+	if row != "" {
+		lines = append(lines, fmt.Sprintf("%8d: %s", row_address, row))
+	}
+
+	return lines
+}
+
+func (this *Objdump) Lines() []string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	lines := this.Disassemble()
+
+	lines = append(lines, "")
+	lines = append(lines, this.DumpData("atomic", config_loader.AtomicOffset(), this.atomic)...)
+
+	lines = append(lines, "")
+	lines = append(lines, this.DumpData("WRAM", config_loader.WramOffset(), this.wram)...)
+
+	lines = append(lines, "")
+	lines = append(lines, this.DumpData("MRAM", config_loader.MramOffset(), this.mram)...)
+
+	return lines
+}
+
+func (this *Objdump) Dump() {
+	for _, line := range this.Lines() {
+		fmt.Println(line)
+	}
+}
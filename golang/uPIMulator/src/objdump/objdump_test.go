@@ -0,0 +1,326 @@
+package objdump
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/linker"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/cc"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
+)
+
+var prim_benchmarks = []string{
+	"BS",
+	"GEMV",
+	"HST-L",
+	"HST-S",
+	"MLP",
+	"RED",
+	"SCAN-RSS",
+	"SCAN-SSA",
+	"SEL",
+	"TRNS",
+	"TS",
+	"UNI",
+	"VA",
+}
+
+func newByteStream(size int64) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for i := int64(0); i < size; i++ {
+		byte_stream.Append(uint8(i))
+	}
+	return byte_stream
+}
+
+func newInstructions(stop_pc int64) []*instruction.Instruction {
+	nop := new(instruction.Instruction)
+	nop.InitZ(instruction.NOP)
+
+	rc := new(reg_descriptor.GpRegDescriptor)
+	rc.Init(1)
+
+	gp_ra := new(reg_descriptor.GpRegDescriptor)
+	gp_ra.Init(2)
+
+	ra := new(reg_descriptor.SrcRegDescriptor)
+	ra.InitGpRegDescriptor(gp_ra)
+
+	add := new(instruction.Instruction)
+	add.InitRri(instruction.ADD, rc, ra, 42)
+
+	stop := new(instruction.Instruction)
+	stop.InitCi(instruction.STOP, cc.TRUE, stop_pc)
+
+	return []*instruction.Instruction{nop, add, stop}
+}
+
+func TestObjdumpSynthetic(t *testing.T) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+	loop_address := config_loader.IramOffset() + iram_data_size
+
+	instructions := newInstructions(loop_address)
+
+	iram := new(encoding.ByteStream)
+	iram.Init()
+	for _, instruction_ := range instructions {
+		iram.Merge(instruction_.Encode())
+	}
+
+	addresses := map[string]int64{
+		"__bootstrap": config_loader.IramOffset(),
+		"loop":        loop_address,
+		"buffer":      config_loader.WramOffset() + 4,
+	}
+
+	objdump_ := new(Objdump)
+	objdump_.InitImages(newByteStream(0), iram, newByteStream(16), newByteStream(0), addresses)
+
+	if objdump_.NumInstructions() != len(instructions) {
+		t.Fatalf("NumInstructions() = %d, want %d", objdump_.NumInstructions(), len(instructions))
+	}
+
+	for i, instruction_ := range instructions {
+		decoded := objdump_.Instruction(i)
+
+		if decoded.Stringify() != instruction_.Stringify() {
+			t.Errorf("instruction %d: %q, want %q", i, decoded.Stringify(), instruction_.Stringify())
+		}
+
+		if objdump_.StringifyRaw(decoded.Encode()) != objdump_.StringifyRaw(objdump_.RawInstruction(i)) {
+			t.Errorf("instruction %d does not survive a decode-encode round trip", i)
+		}
+	}
+
+	disassembly := strings.Join(objdump_.Disassemble(), "\n")
+	for _, want := range []string{"<__bootstrap>:", "<loop>:", "; <loop>"} {
+		if !strings.Contains(disassembly, want) {
+			t.Errorf("disassembly does not contain %q:\n%s", want, disassembly)
+		}
+	}
+
+	if symbol := objdump_.Symbolize(loop_address + 2); symbol != "loop+2" {
+		t.Errorf("Symbolize(loop+2) = %q", symbol)
+	}
+
+	if symbol := objdump_.Symbolize(config_loader.WramOffset()); symbol != "" {
+		t.Errorf("Symbolize(WRAM offset) = %q, want no symbol from another memory", symbol)
+	}
+
+	wram_lines := objdump_.DumpData("WRAM", config_loader.WramOffset(), newByteStream(16))
+	want_lines := []string{
+		"Contents of WRAM:",
+		fmt.Sprintf("%8d:  00 01 02 03", config_loader.WramOffset()),
+		"",
+		"<buffer>:",
+		fmt.Sprintf("%8d:  04 05 06 07", config_loader.WramOffset()+4),
+		fmt.Sprintf("%8d:  08 09 0a 0b 0c 0d 0e 0f", config_loader.WramOffset()+8),
+	}
+
+	if strings.Join(wram_lines, "\n") != strings.Join(want_lines, "\n") {
+		t.Errorf("DumpData() =\n%s\nwant\n%s", strings.Join(wram_lines, "\n"), strings.Join(want_lines, "\n"))
+	}
+}
+
+func newCommandLineParser(
+	t *testing.T,
+	root_dirpath string,
+	benchmark string,
+	asm_filepaths string,
+) *misc.CommandLineParser {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "root_dirpath", root_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.STRING, "benchmark", benchmark, "")
+	command_line_parser.AddOption(misc.INT, "num_simulation_threads", "4", "")
+	command_line_parser.AddOption(misc.INT, "num_tasklets", "16", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	command_line_parser.AddOption(misc.STRING, "elf_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "asm_filepaths", asm_filepaths, "")
+	command_line_parser.AddOption(misc.STRING, "sdk_objects", "", "")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "cache_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.BOOL, "gc_sections", "false", "")
+	return command_line_parser
+}
+
+// NOTE: links and dumps the executable, and checks that every instruction the linker emitted is
+// decoded back by objdump from the dumped IRAM image
+func checkRoundTrip(t *testing.T, command_line_parser *misc.CommandLineParser) int {
+	t.Helper()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+	linker_ := new(linker.Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	objdump_ := new(Objdump)
+	objdump_.Init(command_line_parser)
+
+	iram_sections := linker_.Executable().Sort(
+		config_loader.IramOffset(),
+		config_loader.IramOffset()+config_loader.IramSize(),
+	)
+
+	num_instructions := 0
+	for _, iram_section := range iram_sections {
+		for _, label := range iram_section.Labels() {
+			address := label.Address()
+
+			for _, encodable := range label.Encodables() {
+				instruction_, ok := encodable.(*instruction.Instruction)
+				if !ok {
+					t.Fatalf("label (%s) in IRAM holds a non-instruction", label.Name())
+				}
+
+				index := int((address - config_loader.IramOffset()) / iram_data_size)
+
+				if objdump_.Instruction(index).Stringify() != instruction_.Stringify() {
+					t.Errorf(
+						"instruction at %d: %q, want %q",
+						address,
+						objdump_.Instruction(index).Stringify(),
+						instruction_.Stringify(),
+					)
+				}
+
+				raw := objdump_.StringifyRaw(objdump_.RawInstruction(index))
+				if objdump_.StringifyRaw(instruction_.Encode()) != raw {
+					t.Errorf("instruction at %d does not match the dumped IRAM image", address)
+				}
+
+				address += iram_data_size
+				num_instructions++
+			}
+		}
+	}
+
+	if num_instructions != objdump_.NumInstructions() {
+		t.Errorf(
+			"linker emitted %d instructions, objdump decoded %d",
+			num_instructions,
+			objdump_.NumInstructions(),
+		)
+	}
+
+	return num_instructions
+}
+
+// NOTE: testdata/kernel.S is hand-written assembly that links without the SDK, so that the round
+// trip is checked even where neither the SDK nor the PrIM benchmarks are compiled
+func TestObjdumpAsRoundTrip(t *testing.T) {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "kernel.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	command_line_parser := newCommandLineParser(t, root_dirpath, "BS", asm_filepath)
+
+	if num_instructions := checkRoundTrip(t, command_line_parser); num_instructions != 8 {
+		t.Errorf("linker emitted %d instructions, want 8", num_instructions)
+	}
+
+	objdump_ := new(Objdump)
+	objdump_.Init(command_line_parser)
+
+	disassembly := strings.Join(objdump_.Disassemble(), "\n")
+	for _, want := range []string{"<__bootstrap>:", "<__sys_end>:", "stop, ci, true"} {
+		if !strings.Contains(disassembly, want) {
+			t.Errorf("disassembly does not contain %q:\n%s", want, disassembly)
+		}
+	}
+}
+
+// NOTE: linking needs the PrIM benchmarks and the SDK compiled to assembly, so this test is
+// skipped unless both build directories exist
+func TestObjdumpPrimRoundTrip(t *testing.T) {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root_dirpath, "sdk", "build")); err != nil {
+		t.Skip("SDK is not built")
+	}
+
+	for _, benchmark := range prim_benchmarks {
+		t.Run(benchmark, func(t *testing.T) {
+			relocatable_path := filepath.Join(
+				root_dirpath,
+				"benchmark",
+				"build",
+				benchmark,
+				"dpu",
+				"CMakeFiles",
+				fmt.Sprintf("%s_device.dir", benchmark),
+				"task.c.o",
+			)
+
+			if _, err := os.Stat(relocatable_path); err != nil {
+				t.Skipf("%s is not compiled", benchmark)
+			}
+
+			checkRoundTrip(t, newCommandLineParser(t, root_dirpath, benchmark, ""))
+		})
+	}
+}
+
+// NOTE: the round trip is also checked on the kernels that the linker and the simulator check in for
+// their own tests, which need neither the SDK nor the PrIM builds
+func TestObjdumpTestdataRoundTrip(t *testing.T) {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	asm_filepaths := []string{
+		filepath.Join("..", "linker", "testdata", "kernel.S"),
+		filepath.Join("..", "linker", "testdata", "source_lines.S"),
+		filepath.Join("..", "simulator", "dpu", "logic", "testdata", "memory_check.S"),
+		filepath.Join("..", "simulator", "profiler", "testdata", "profile.S"),
+	}
+
+	for _, asm_filepath := range asm_filepaths {
+		t.Run(filepath.Base(asm_filepath), func(t *testing.T) {
+			abs_asm_filepath, abs_err := filepath.Abs(asm_filepath)
+			if abs_err != nil {
+				t.Fatal(abs_err)
+			}
+
+			command_line_parser := newCommandLineParser(t, root_dirpath, "BS", abs_asm_filepath)
+
+			if num_instructions := checkRoundTrip(t, command_line_parser); num_instructions == 0 {
+				t.Errorf("linker emitted no instruction")
+			}
+		})
+	}
+}
+
+func TestObjdumpUsage(t *testing.T) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	raw_word := fmt.Sprintf("%d-bit word", config_loader.IramDataWidth())
+	if usage := Usage(); !strings.Contains(usage, raw_word) || !strings.Contains(usage, "48-bit") {
+		t.Errorf("usage does not state the raw %s:\n%s", raw_word, usage)
+	}
+}
@@ -2,6 +2,7 @@ package channel
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/misc"
@@ -21,6 +22,8 @@ type Channel struct {
 	input_q         *ChannelMessageQ
 	communication_q *ChannelMessageQ
 	ready_q         *ChannelMessageQ
+
+	stat_factory *misc.StatFactory
 }
 
 func (this *Channel) Init(channel_id int, command_line_parser *misc.CommandLineParser) {
@@ -50,6 +53,10 @@ func (this *Channel) Init(channel_id int, command_line_parser *misc.CommandLineP
 
 	this.ready_q = new(ChannelMessageQ)
 	this.ready_q.Init(-1, 0)
+
+	name := fmt.Sprintf("Channel[%d]", channel_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
 }
 
 func (this *Channel) Fini() {
@@ -84,6 +91,10 @@ func (this *Channel) Dpus() []*dpu.Dpu {
 	return dpus
 }
 
+func (this *Channel) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
 func (this *Channel) Lock() {
 	this.mutex.Lock()
 }
@@ -152,6 +163,8 @@ func (this *Channel) Cycle() {
 	this.input_q.Cycle()
 	this.communication_q.Cycle()
 	this.ready_q.Cycle()
+
+	this.stat_factory.Increment("channel_cycle", 1)
 }
 
 func (this *Channel) ServiceInputQ() {
@@ -200,6 +213,9 @@ func (this *Channel) ServiceCommunicationQ() {
 			}
 
 			channel_messaage.SetByteStreams(byte_streams)
+
+			this.stat_factory.Increment("num_reads", 1)
+			this.stat_factory.Increment("read_bytes", size*int64(len(dpu_ids)))
 		} else if channel_operation == WRITE {
 			for i, _ := range dpu_ids {
 				dpu_id := dpu_ids[i]
@@ -207,6 +223,9 @@ func (this *Channel) ServiceCommunicationQ() {
 
 				rank_.Write(dpu_id, address, byte_stream)
 			}
+
+			this.stat_factory.Increment("num_writes", 1)
+			this.stat_factory.Increment("write_bytes", size*int64(len(dpu_ids)))
 		} else {
 			err := errors.New("channel operation is not valid")
 			panic(err)
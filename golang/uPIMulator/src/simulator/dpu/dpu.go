@@ -26,6 +26,8 @@ type Dpu struct {
 	wram              *sram.Wram
 	mram              *dram.Mram
 	operand_collector *logic.OperandCollector
+	memory_checker    *logic.MemoryChecker
+	race_detector     *logic.RaceDetector
 	memory_controller *dram.MemoryController
 	dma               *logic.Dma
 	logic             *logic.Logic
@@ -82,9 +84,17 @@ func (this *Dpu) Init(
 	this.mram = new(dram.Mram)
 	this.mram.Init(command_line_parser)
 
+	this.memory_checker = new(logic.MemoryChecker)
+	this.memory_checker.Init(channel_id, rank_id, dpu_id, command_line_parser)
+
+	this.race_detector = new(logic.RaceDetector)
+	this.race_detector.Init(channel_id, rank_id, dpu_id, command_line_parser)
+
 	this.operand_collector = new(logic.OperandCollector)
 	this.operand_collector.Init()
 	this.operand_collector.ConnectWram(this.wram)
+	this.operand_collector.ConnectMemoryChecker(this.memory_checker)
+	this.operand_collector.ConnectRaceDetector(this.race_detector)
 
 	this.memory_controller = new(dram.MemoryController)
 	this.memory_controller.Init(channel_id, rank_id, dpu_id, command_line_parser)
@@ -96,6 +106,8 @@ func (this *Dpu) Init(
 	this.dma.ConnectIram(this.iram)
 	this.dma.ConnectOperandCollector(this.operand_collector)
 	this.dma.ConnectMemoryController(this.memory_controller)
+	this.dma.ConnectMemoryChecker(this.memory_checker)
+	this.dma.ConnectRaceDetector(this.race_detector)
 
 	this.logic = new(logic.Logic)
 	this.logic.Init(channel_id, rank_id, dpu_id, command_line_parser)
@@ -104,6 +116,8 @@ func (this *Dpu) Init(
 	this.logic.ConnectIram(this.iram)
 	this.logic.ConnectOperandCollector(this.operand_collector)
 	this.logic.ConnectDma(this.dma)
+	this.logic.ConnectMemoryChecker(this.memory_checker)
+	this.logic.ConnectRaceDetector(this.race_detector)
 
 	name := fmt.Sprintf("DPU%d-%d-%d", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
@@ -147,6 +161,14 @@ func (this *Dpu) Logic() *logic.Logic {
 	return this.logic
 }
 
+func (this *Dpu) MemoryChecker() *logic.MemoryChecker {
+	return this.memory_checker
+}
+
+func (this *Dpu) RaceDetector() *logic.RaceDetector {
+	return this.race_detector
+}
+
 func (this *Dpu) MemoryController() *dram.MemoryController {
 	return this.memory_controller
 }
@@ -164,7 +186,12 @@ func (this *Dpu) StatFactory() *misc.StatFactory {
 }
 
 func (this *Dpu) Boot() {
+	this.race_detector.Reset()
 	this.thread_scheduler.Boot(0)
+
+	if this.logic.Profile() != nil {
+		this.logic.Profile().Boot(0)
+	}
 }
 
 func (this *Dpu) IsZombie() bool {
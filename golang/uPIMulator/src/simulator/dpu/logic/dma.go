@@ -14,6 +14,8 @@ type Dma struct {
 	iram              *sram.Iram
 	operand_collector *OperandCollector
 	memory_controller *dram.MemoryController
+	memory_checker    *MemoryChecker
+	race_detector     *RaceDetector
 
 	input_q *dram.DmaCommandQ
 	ready_q *dram.DmaCommandQ
@@ -24,6 +26,8 @@ func (this *Dma) Init() {
 	this.iram = nil
 	this.operand_collector = nil
 	this.memory_controller = nil
+	this.memory_checker = nil
+	this.race_detector = nil
 
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -78,6 +82,24 @@ func (this *Dma) ConnectMemoryController(memory_controller *dram.MemoryControlle
 	this.memory_controller = memory_controller
 }
 
+func (this *Dma) ConnectMemoryChecker(memory_checker *MemoryChecker) {
+	if this.memory_checker != nil {
+		err := errors.New("memory checker is already set")
+		panic(err)
+	}
+
+	this.memory_checker = memory_checker
+}
+
+func (this *Dma) ConnectRaceDetector(race_detector *RaceDetector) {
+	if this.race_detector != nil {
+		err := errors.New("race detector is already set")
+		panic(err)
+	}
+
+	this.race_detector = race_detector
+}
+
 func (this *Dma) IsEmpty() bool {
 	return this.input_q.IsEmpty() && this.ready_q.IsEmpty()
 }
@@ -114,7 +136,7 @@ func (this *Dma) TransferFromWram(address int64, size int64) *encoding.ByteStrea
 	byte_stream.Init()
 
 	for i := int64(0); i < size; i++ {
-		value := this.operand_collector.Lbu(address + i)
+		value := this.operand_collector.LoadByte(address + i)
 		byte_stream.Append(uint8(value))
 	}
 
@@ -124,7 +146,7 @@ func (this *Dma) TransferFromWram(address int64, size int64) *encoding.ByteStrea
 func (this *Dma) TransferToWram(address int64, byte_stream *encoding.ByteStream) {
 	for i := int64(0); i < byte_stream.Size(); i++ {
 		value := byte_stream.Get(int(i))
-		this.operand_collector.Sb(address+i, int64(value))
+		this.operand_collector.StoreByte(address+i, int64(value))
 	}
 }
 
@@ -148,6 +170,14 @@ func (this *Dma) TransferFromWramToMram(
 		panic(err)
 	}
 
+	if this.memory_checker != nil {
+		this.memory_checker.CheckDma(wram_address, mram_address, size, false)
+	}
+
+	if this.race_detector != nil {
+		this.race_detector.Load(wram_address, size)
+	}
+
 	byte_stream := this.TransferFromWram(wram_address, size)
 
 	dma_command := new(dram.DmaCommand)
@@ -167,6 +197,16 @@ func (this *Dma) TransferFromMramToWram(
 		panic(err)
 	}
 
+	if this.memory_checker != nil {
+		this.memory_checker.CheckDma(wram_address, mram_address, size, true)
+	}
+
+	// NOTE: the WRAM write of ldma completes in the DMA engine, but the tasklet is blocked until
+	// then, so it is attributed to the tasklet when the command is issued
+	if this.race_detector != nil {
+		this.race_detector.Store(wram_address, size)
+	}
+
 	dma_command := new(dram.DmaCommand)
 	dma_command.InitReadFromMramToWram(wram_address, mram_address, size, instruction_)
 
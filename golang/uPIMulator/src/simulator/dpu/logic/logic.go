@@ -4,12 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"uPIMulator/src/abi/word"
+	"uPIMulator/src/linker/kernel"
 	"uPIMulator/src/linker/kernel/instruction"
 	"uPIMulator/src/linker/kernel/instruction/cc"
 	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/dpu/sram"
+	"uPIMulator/src/simulator/profiler"
 )
 
 type Logic struct {
@@ -21,7 +25,8 @@ type Logic struct {
 	num_ranks_per_channel int
 	num_dpus_per_rank     int
 
-	verbose int
+	verbose     int
+	bin_dirpath string
 
 	min_access_granularity int64
 
@@ -30,8 +35,16 @@ type Logic struct {
 	iram              *sram.Iram
 	operand_collector *OperandCollector
 	dma               *Dma
+	memory_checker    *MemoryChecker
+	race_detector     *RaceDetector
 
 	scoreboard map[*instruction.Instruction]*Thread
+	pcs        map[*instruction.Instruction]int64
+
+	source_line_table *kernel.SourceLineTable
+
+	profile   *profiler.Profile
+	block_pcs map[int]int64
 
 	pipeline   *Pipeline
 	cycle_rule *CycleRule
@@ -70,14 +83,29 @@ func (this *Logic) Init(
 	this.min_access_granularity = command_line_parser.IntParameter("min_access_granularity")
 
 	this.verbose = int(command_line_parser.IntParameter("verbose"))
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
 
 	this.thread_scheduler = nil
 	this.atomic = nil
 	this.iram = nil
 	this.operand_collector = nil
 	this.dma = nil
+	this.memory_checker = nil
+	this.race_detector = nil
 
 	this.scoreboard = make(map[*instruction.Instruction]*Thread, 0)
+	this.pcs = make(map[*instruction.Instruction]int64, 0)
+
+	this.source_line_table = nil
+
+	if command_line_parser.BoolParameter("profile") {
+		this.profile = new(profiler.Profile)
+		this.profile.Init()
+	} else {
+		this.profile = nil
+	}
+
+	this.block_pcs = make(map[int]int64, 0)
 
 	this.pipeline = new(Pipeline)
 	this.pipeline.Init(command_line_parser)
@@ -152,6 +180,24 @@ func (this *Logic) ConnectDma(dma *Dma) {
 	this.dma = dma
 }
 
+func (this *Logic) ConnectMemoryChecker(memory_checker *MemoryChecker) {
+	if this.memory_checker != nil {
+		err := errors.New("memory checker is already set")
+		panic(err)
+	}
+
+	this.memory_checker = memory_checker
+}
+
+func (this *Logic) ConnectRaceDetector(race_detector *RaceDetector) {
+	if this.race_detector != nil {
+		err := errors.New("race detector is already set")
+		panic(err)
+	}
+
+	this.race_detector = race_detector
+}
+
 func (this *Logic) CycleRule() *CycleRule {
 	return this.cycle_rule
 }
@@ -160,6 +206,10 @@ func (this *Logic) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
 
+func (this *Logic) Profile() *profiler.Profile {
+	return this.profile
+}
+
 func (this *Logic) IsEmpty() bool {
 	return this.pipeline.IsEmpty() && this.cycle_rule.IsEmpty() && this.wait_q.IsEmpty()
 }
@@ -183,18 +233,23 @@ func (this *Logic) ServiceThreadScheduler() {
 	if this.pipeline.CanPush() && this.cycle_rule.CanPush() && this.wait_q.CanPush(1) {
 		thread := this.thread_scheduler.Schedule()
 
+		this.ProfileCycle(thread, false)
+
 		if thread != nil {
 			pc := thread.RegFile().ReadPcReg()
 			instruction_ := this.iram.Read(pc)
 
 			this.scoreboard[instruction_] = thread
+			this.pcs[instruction_] = pc
 
 			this.pipeline.Push(instruction_)
 
 			if instruction_.Suffix() != instruction.DMA_RRI {
 				this.ExecuteInstruction(instruction_)
+				this.ProfileBranch(thread, pc, instruction_)
 			} else {
 				this.thread_scheduler.Block(thread.ThreadId())
+				this.block_pcs[thread.ThreadId()] = pc
 				thread.RegFile().IncrementPcReg()
 				this.wait_q.Push(instruction_)
 			}
@@ -205,11 +260,60 @@ func (this *Logic) ServiceThreadScheduler() {
 		active_tasklets := fmt.Sprintf("active_tasklets_%d", this.thread_scheduler.NumIssuableThreads())
 		this.stat_factory.Increment(active_tasklets, 1)
 	} else {
+		this.ProfileCycle(nil, true)
+
 		this.stat_factory.Increment("backpressure", 1)
 		this.stat_factory.Increment("active_tasklets_0", 1)
 	}
 }
 
+// NOTE: every cycle is attributed to the PC of each tasklet that is either issuing, blocked by a
+// DMA (at the PC of the DMA instruction) or runnable but waiting for the revolver scheduler or
+// behind the backpressure of the pipeline, while sleeping tasklets are not attributed
+func (this *Logic) ProfileCycle(issued_thread *Thread, has_backpressure bool) {
+	if this.profile == nil {
+		return
+	}
+
+	for _, thread := range this.thread_scheduler.Threads() {
+		thread_id := thread.ThreadId()
+		pc := thread.RegFile().ReadPcReg()
+
+		if thread == issued_thread {
+			this.profile.Issue(pc, thread_id)
+		} else if thread.ThreadState() == BLOCK {
+			this.profile.Stall(this.block_pcs[thread_id], thread_id, profiler.DMA_STALL)
+		} else if thread.ThreadState() == RUNNABLE && has_backpressure {
+			this.profile.Stall(pc, thread_id, profiler.BACKPRESSURE_STALL)
+		} else if thread.ThreadState() == RUNNABLE {
+			this.profile.Stall(pc, thread_id, profiler.REVOLVER_STALL)
+		}
+	}
+}
+
+// NOTE: calls link their return address, and a tasklet that lands on the return address of a call
+// in its chain has returned from it, so that samples are attributed to the calls they are made in
+func (this *Logic) ProfileBranch(thread *Thread, pc int64, instruction_ *instruction.Instruction) {
+	if this.profile == nil {
+		return
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+	thread_id := thread.ThreadId()
+	next_pc := thread.RegFile().ReadPcReg()
+
+	if instruction_.OpCode() == instruction.CALL &&
+		(instruction_.Suffix() == instruction.RRI || instruction_.Suffix() == instruction.RRR) {
+		this.profile.Call(thread_id, pc)
+	} else if next_pc != pc+iram_data_size {
+		this.profile.Jump(thread_id, next_pc, iram_data_size)
+	}
+}
+
 func (this *Logic) ServicePipeline() {
 	if this.pipeline.CanPop() && this.cycle_rule.CanPush() {
 		instruction_ := this.pipeline.Pop()
@@ -227,6 +331,7 @@ func (this *Logic) ServiceCycleRule() {
 
 		if instruction_.Suffix() != instruction.DMA_RRI {
 			delete(this.scoreboard, instruction_)
+			delete(this.pcs, instruction_)
 		} else {
 			this.ExecuteInstruction(instruction_)
 		}
@@ -251,6 +356,7 @@ func (this *Logic) ServiceDma() {
 
 				this.wait_q.Remove(i)
 				delete(this.scoreboard, instruction_)
+				delete(this.pcs, instruction_)
 
 				has_waked_up = true
 				break
@@ -269,13 +375,31 @@ func (this *Logic) ExecuteInstruction(instruction_ *instruction.Instruction) {
 
 	unique_dpu_id := this.channel_id*this.num_ranks_per_channel*this.num_dpus_per_rank + this.rank_id*this.num_dpus_per_rank + this.dpu_id
 
+	defer this.Report(instruction_)
+
+	this.memory_checker.Checkout(thread.ThreadId(), this.pcs[instruction_])
+	defer this.memory_checker.Checkin()
+
+	this.race_detector.Checkout(thread.ThreadId(), this.pcs[instruction_])
+	defer this.race_detector.Checkin()
+
 	if this.verbose >= 1 {
-		fmt.Printf(
-			"{%d}[%d] %s\n",
-			unique_dpu_id,
-			thread.ThreadId(),
-			instruction_.Stringify(),
-		)
+		if source_line := this.SourceLine(this.pcs[instruction_]); source_line != nil {
+			fmt.Printf(
+				"{%d}[%d] %s ; %s\n",
+				unique_dpu_id,
+				thread.ThreadId(),
+				instruction_.Stringify(),
+				source_line.Stringify(),
+			)
+		} else {
+			fmt.Printf(
+				"{%d}[%d] %s\n",
+				unique_dpu_id,
+				thread.ThreadId(),
+				instruction_.Stringify(),
+			)
+		}
 	}
 
 	suffix := instruction_.Suffix()
@@ -414,6 +538,48 @@ func (this *Logic) ExecuteInstruction(instruction_ *instruction.Instruction) {
 	}
 }
 
+// NOTE: an error raised while executing an instruction is reported with the PC of the instruction
+// and, if the linker has emitted source_lines.txt, the source line that it was compiled from
+func (this *Logic) Report(instruction_ *instruction.Instruction) {
+	if recovered := recover(); recovered != nil {
+		thread := this.scoreboard[instruction_]
+		pc := this.pcs[instruction_]
+
+		location := fmt.Sprintf("PC (%d)", pc)
+		if source_line := this.SourceLine(pc); source_line != nil {
+			location = fmt.Sprintf("PC (%d) at %s", pc, source_line.Stringify())
+		}
+
+		err_msg := fmt.Sprintf(
+			"DPU (%d, %d, %d) thread (%d) failed to execute %s in %s: %v",
+			this.channel_id,
+			this.rank_id,
+			this.dpu_id,
+			thread.ThreadId(),
+			instruction_.Stringify(),
+			location,
+			recovered,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// NOTE: the table is loaded on first use since only verbose output and error reports need it
+func (this *Logic) SourceLine(pc int64) *kernel.SourceLine {
+	if this.source_line_table == nil {
+		this.source_line_table = new(kernel.SourceLineTable)
+		this.source_line_table.Init()
+
+		path := filepath.Join(this.bin_dirpath, "source_lines.txt")
+		if _, err := os.Stat(path); err == nil {
+			this.source_line_table.Load(path)
+		}
+	}
+
+	return this.source_line_table.SourceLine(pc)
+}
+
 func (this *Logic) ExecuteRici(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RiciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RICI op code")
@@ -455,6 +621,7 @@ func (this *Logic) ExecuteAcquireRici(instruction_ *instruction.Instruction) {
 	can_acquire := this.atomic.CanAcquire(atomic_address)
 	if can_acquire {
 		this.atomic.Acquire(atomic_address, thread.ThreadId())
+		this.race_detector.Acquire(atomic_address)
 	}
 
 	thread.RegFile().ClearConditions()
@@ -496,6 +663,7 @@ func (this *Logic) ExecuteReleaseRici(instruction_ *instruction.Instruction) {
 	can_release := this.atomic.CanRelease(atomic_address, thread.ThreadId())
 	if can_release {
 		this.atomic.Release(atomic_address, thread.ThreadId())
+		this.race_detector.Release(atomic_address)
 	}
 
 	thread.RegFile().ClearConditions()
@@ -540,6 +708,11 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 	if op_code == instruction.BOOT {
 		can_boot := this.thread_scheduler.Boot(thread_id)
 		if can_boot {
+			if this.profile != nil {
+				this.profile.Boot(thread_id)
+			}
+
+			this.race_detector.Signal(thread_id)
 			this.SetBootCc(instruction_, ra, 0)
 			this.SetFlags(instruction_, 0, false)
 		} else {
@@ -549,6 +722,7 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.RESUME {
 		can_resume := this.thread_scheduler.Awake(thread_id)
 		if can_resume {
+			this.race_detector.Signal(thread_id)
 			this.SetBootCc(instruction_, ra, 0)
 			this.SetFlags(instruction_, 0, false)
 		} else {
@@ -5041,6 +5215,10 @@ func (this *Logic) ExecuteLdmaDmaRri(instruction_ *instruction.Instruction) {
 
 	this.dma.TransferFromMramToWram(wram_address, mram_address, size, instruction_)
 
+	if this.profile != nil {
+		this.profile.Transfer(this.pcs[instruction_], thread.ThreadId(), size)
+	}
+
 	thread.RegFile().ClearConditions()
 }
 
@@ -5081,6 +5259,10 @@ func (this *Logic) ExecuteSdmaDmaRri(instruction_ *instruction.Instruction) {
 
 	this.dma.TransferFromWramToMram(wram_address, mram_address, size, instruction_)
 
+	if this.profile != nil {
+		this.profile.Transfer(this.pcs[instruction_], thread.ThreadId(), size)
+	}
+
 	thread.RegFile().ClearConditions()
 }
 
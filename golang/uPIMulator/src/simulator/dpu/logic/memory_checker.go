@@ -0,0 +1,419 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	linker_logic "uPIMulator/src/linker/logic"
+	"uPIMulator/src/misc"
+)
+
+type MemoryCheckPolicy int
+
+// Explanation of memory check policies
+// MEMORY_CHECK_STRICT: the simulation stops at the first violation
+// MEMORY_CHECK_REPORT: violations are reported in memory_check.txt and the simulation continues
+// MEMORY_CHECK_OFF: memory accesses are not checked
+const (
+	MEMORY_CHECK_STRICT MemoryCheckPolicy = iota
+	MEMORY_CHECK_REPORT
+	MEMORY_CHECK_OFF
+)
+
+type ViolationType int
+
+const (
+	STACK_OVERFLOW ViolationType = iota
+	RODATA_WRITE
+	UNINITIALIZED_READ
+	DMA_MISALIGNMENT
+	DMA_OVERSIZE
+)
+
+type Violation struct {
+	violation_type ViolationType
+	thread_id      int
+	pc             int64
+	message        string
+	count          int64
+}
+
+type MemoryRegion struct {
+	name          string
+	begin_address int64
+	end_address   int64
+}
+
+func (this *MemoryRegion) Contains(address int64) bool {
+	return this.begin_address <= address && address < this.end_address
+}
+
+type MemoryChecker struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	memory_check_policy MemoryCheckPolicy
+	bin_dirpath         string
+
+	linker_script *linker_logic.LinkerScript
+
+	symbolizer *Symbolizer
+
+	stacks []*MemoryRegion
+	rodata *MemoryRegion
+	heap   *MemoryRegion
+
+	written []bool
+
+	thread_id int
+	pc        int64
+
+	violations map[string]*Violation
+}
+
+func (this *MemoryChecker) Init(
+	channel_id int,
+	rank_id int,
+	dpu_id int,
+	command_line_parser *misc.CommandLineParser,
+) {
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	memory_check := command_line_parser.StringParameter("memory_check")
+	if memory_check == "strict" {
+		this.memory_check_policy = MEMORY_CHECK_STRICT
+	} else if memory_check == "report" {
+		this.memory_check_policy = MEMORY_CHECK_REPORT
+	} else if memory_check == "off" {
+		this.memory_check_policy = MEMORY_CHECK_OFF
+	} else {
+		err := errors.New("memory check policy is not valid")
+		panic(err)
+	}
+
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
+
+	this.linker_script = nil
+	if this.IsEnabled() {
+		this.linker_script = new(linker_logic.LinkerScript)
+		this.linker_script.Init(command_line_parser)
+	}
+
+	this.symbolizer = nil
+
+	this.stacks = make([]*MemoryRegion, 0)
+	this.rodata = nil
+	this.heap = nil
+
+	this.written = nil
+	if this.IsEnabled() {
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		this.written = make([]bool, config_loader.WramSize())
+	}
+
+	this.Checkin()
+
+	this.violations = make(map[string]*Violation, 0)
+
+	if this.IsEnabled() {
+		this.Load()
+	}
+}
+
+func (this *MemoryChecker) MemoryCheckPolicy() MemoryCheckPolicy {
+	return this.memory_check_policy
+}
+
+func (this *MemoryChecker) IsEnabled() bool {
+	return this.memory_check_policy != MEMORY_CHECK_OFF
+}
+
+func (this *MemoryChecker) Load() {
+	this.symbolizer = new(Symbolizer)
+	this.symbolizer.Init(this.bin_dirpath)
+
+	values := this.ReadValues(filepath.Join(this.bin_dirpath, "values.txt"))
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for i := 0; i < config_loader.MaxNumTasklets(); i++ {
+		stack, stack_found := values[fmt.Sprintf("__sys_stack_thread_%d", i)]
+		stack_size, stack_size_found := values[fmt.Sprintf("STACK_SIZE_TASKLET_%d", i)]
+
+		if stack_found && stack_size_found && stack_size > 0 {
+			region := new(MemoryRegion)
+			region.name = fmt.Sprintf("stack of tasklet %d", i)
+			region.begin_address = stack
+			region.end_address = stack + stack_size
+
+			this.stacks = append(this.stacks, region)
+		} else {
+			this.stacks = append(this.stacks, nil)
+		}
+	}
+
+	rodata_start, rodata_start_found := values["__rodata_start_addr"]
+	rodata_end, rodata_end_found := values["__rodata_end_addr"]
+	if rodata_start_found && rodata_end_found {
+		this.rodata = new(MemoryRegion)
+		this.rodata.name = "rodata"
+		this.rodata.begin_address = rodata_start
+		this.rodata.end_address = rodata_end
+	}
+
+	if heap, found := values["__sys_heap_pointer_reset"]; found {
+		this.heap = new(MemoryRegion)
+		this.heap.name = "heap"
+		this.heap.begin_address = heap
+		this.heap.end_address = this.WramEnd()
+	}
+}
+
+// NOTE: the heap ends where the WRAM regions of the linker script end rather than at the end of
+// the simulated WRAM, which may be larger
+func (this *MemoryChecker) WramEnd() int64 {
+	wram_end := int64(0)
+	for _, region := range this.linker_script.Regions(linker_logic.WRAM_MEMORY) {
+		region_end := this.linker_script.Translate(
+			linker_logic.WRAM_MEMORY,
+			region.Origin()+region.Length(),
+		)
+
+		if region_end > wram_end {
+			wram_end = region_end
+		}
+	}
+	return wram_end
+}
+
+func (this *MemoryChecker) ReadValues(path string) map[string]int64 {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	values := make(map[string]int64, 0)
+
+	for _, line := range file_scanner.ReadLines() {
+		words := strings.Split(line, ": ")
+
+		if len(words) != 2 {
+			err_msg := fmt.Sprintf("line (%s) of %s is not valid", line, path)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		value, err := strconv.ParseInt(words[1], 10, 64)
+
+		if err != nil {
+			panic(err)
+		}
+
+		values[words[0]] = value
+	}
+
+	return values
+}
+
+// NOTE: accesses are checked only while a tasklet executes an instruction, while the host and the
+// completion of DMA commands only initialize WRAM
+func (this *MemoryChecker) Checkout(thread_id int, pc int64) {
+	this.thread_id = thread_id
+	this.pc = pc
+}
+
+func (this *MemoryChecker) Checkin() {
+	this.thread_id = -1
+	this.pc = -1
+}
+
+func (this *MemoryChecker) IsCheckedOut() bool {
+	return this.thread_id >= 0
+}
+
+func (this *MemoryChecker) MarkWritten(address int64, size int64) {
+	if !this.IsEnabled() {
+		return
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for i := int64(0); i < size; i++ {
+		index := address + i - config_loader.WramOffset()
+
+		if 0 <= index && index < int64(len(this.written)) {
+			this.written[index] = true
+		}
+	}
+}
+
+func (this *MemoryChecker) CheckLoad(address int64, size int64) {
+	if !this.IsEnabled() || !this.IsCheckedOut() {
+		return
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for i := int64(0); i < size; i++ {
+		index := address + i - config_loader.WramOffset()
+
+		if 0 <= index && index < int64(len(this.written)) && !this.written[index] {
+			this.Report(UNINITIALIZED_READ, fmt.Sprintf(
+				"reads %d bytes at %s that have never been written",
+				size,
+				this.Describe(address),
+			))
+			return
+		}
+	}
+}
+
+func (this *MemoryChecker) CheckStore(address int64, size int64) {
+	if !this.IsEnabled() || !this.IsCheckedOut() {
+		return
+	}
+
+	for i := int64(0); i < size; i++ {
+		if this.rodata != nil && this.rodata.Contains(address+i) {
+			this.Report(RODATA_WRITE, fmt.Sprintf(
+				"writes %d bytes at %s in rodata",
+				size,
+				this.Describe(address),
+			))
+			return
+		}
+
+		for thread_id, stack := range this.stacks {
+			if stack != nil && thread_id != this.thread_id && stack.Contains(address+i) {
+				this.Report(STACK_OVERFLOW, fmt.Sprintf(
+					"writes %d bytes at %s outside its own stack",
+					size,
+					this.Describe(address),
+				))
+				return
+			}
+		}
+	}
+}
+
+// NOTE: the DMA engine moves 8-byte aligned blocks of at most 2048 bytes, and the WRAM side of a
+// DMA command is checked as a store for ldma and as a load for sdma
+func (this *MemoryChecker) CheckDma(
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_mram_read bool,
+) {
+	if !this.IsEnabled() || !this.IsCheckedOut() {
+		return
+	}
+
+	if wram_address%8 != 0 || mram_address%8 != 0 || size%8 != 0 {
+		this.Report(DMA_MISALIGNMENT, fmt.Sprintf(
+			"transfers %d bytes between WRAM %s and MRAM %s, which are not 8-byte aligned",
+			size,
+			this.Describe(wram_address),
+			this.Describe(mram_address),
+		))
+	}
+
+	if size <= 0 || size > 2048 {
+		this.Report(DMA_OVERSIZE, fmt.Sprintf(
+			"transfers %d bytes between WRAM %s and MRAM %s, which exceeds 2048 bytes",
+			size,
+			this.Describe(wram_address),
+			this.Describe(mram_address),
+		))
+	}
+
+	if is_mram_read {
+		this.CheckStore(wram_address, size)
+	} else {
+		this.CheckLoad(wram_address, size)
+	}
+}
+
+func (this *MemoryChecker) Describe(address int64) string {
+	for _, stack := range this.stacks {
+		if stack != nil && stack.Contains(address) {
+			return fmt.Sprintf("%d <%s+%d>", address, stack.name, address-stack.begin_address)
+		}
+	}
+
+	if this.heap != nil && this.heap.Contains(address) {
+		return fmt.Sprintf("%d <%s+%d>", address, this.heap.name, address-this.heap.begin_address)
+	}
+
+	return this.symbolizer.Describe(address)
+}
+
+// NOTE: in strict mode the violation is raised as is since Logic reports the failing instruction
+// with its PC and source line
+func (this *MemoryChecker) Report(violation_type ViolationType, message string) {
+	if this.memory_check_policy == MEMORY_CHECK_STRICT {
+		err := errors.New(message)
+		panic(err)
+	}
+
+	message = fmt.Sprintf(
+		"DPU (%d, %d, %d) tasklet %d, %s: %s",
+		this.channel_id,
+		this.rank_id,
+		this.dpu_id,
+		this.thread_id,
+		this.symbolizer.Locate(this.pc),
+		message,
+	)
+
+	key := fmt.Sprintf("%d %d %d", violation_type, this.thread_id, this.pc)
+	if violation, found := this.violations[key]; found {
+		violation.count++
+	} else {
+		violation := new(Violation)
+		violation.violation_type = violation_type
+		violation.thread_id = this.thread_id
+		violation.pc = this.pc
+		violation.message = message
+		violation.count = 1
+
+		this.violations[key] = violation
+	}
+}
+
+func (this *MemoryChecker) NumViolations() int {
+	return len(this.violations)
+}
+
+// NOTE: a violation is reported once per tasklet and PC along with the number of its occurrences
+func (this *MemoryChecker) ToLines() []string {
+	violations := make([]*Violation, 0)
+	for _, violation := range this.violations {
+		violations = append(violations, violation)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if violations[i].pc != violations[j].pc {
+			return violations[i].pc < violations[j].pc
+		} else if violations[i].thread_id != violations[j].thread_id {
+			return violations[i].thread_id < violations[j].thread_id
+		}
+		return violations[i].violation_type < violations[j].violation_type
+	}
+
+	sort.Slice(violations, sort_fn)
+
+	lines := make([]string, 0)
+	for _, violation := range violations {
+		lines = append(lines, fmt.Sprintf("%s (%d times)", violation.message, violation.count))
+	}
+	return lines
+}
@@ -0,0 +1,173 @@
+package logic
+
+import (
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/linker"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: the checker runs on testdata/memory_check.S linked for two tasklets, whose rodata holds
+// table and whose WRAM data holds value
+func newMemoryChecker(t *testing.T) (*MemoryChecker, map[string]int64) {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", "..", "..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "memory_check.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	bin_dirpath := t.TempDir()
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "root_dirpath", root_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", bin_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "")
+	command_line_parser.AddOption(misc.INT, "num_simulation_threads", "4", "")
+	command_line_parser.AddOption(misc.INT, "num_tasklets", "2", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	command_line_parser.AddOption(misc.STRING, "asm_filepaths", asm_filepath, "")
+	command_line_parser.AddOption(misc.STRING, "sdk_objects", "", "")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "cache_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.BOOL, "gc_sections", "false", "")
+	command_line_parser.AddOption(misc.STRING, "memory_check", "report", "")
+
+	linker_ := new(linker.Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	memory_checker := new(MemoryChecker)
+	memory_checker.Init(0, 0, 0, command_line_parser)
+
+	addresses := memory_checker.ReadValues(filepath.Join(bin_dirpath, "addresses.txt"))
+	for name, value := range memory_checker.ReadValues(filepath.Join(bin_dirpath, "values.txt")) {
+		addresses[name] = value
+	}
+
+	return memory_checker, addresses
+}
+
+func expectViolation(t *testing.T, memory_checker *MemoryChecker, violation_type ViolationType) {
+	t.Helper()
+
+	if memory_checker.NumViolations() != 1 {
+		t.Fatalf(
+			"%d violations are reported: %v",
+			memory_checker.NumViolations(),
+			memory_checker.ToLines(),
+		)
+	}
+
+	for _, violation := range memory_checker.violations {
+		if violation.violation_type != violation_type {
+			t.Errorf("violation (%s) is of type %d", violation.message, violation.violation_type)
+		}
+	}
+}
+
+func TestMemoryCheckerStackOverflow(t *testing.T) {
+	memory_checker, addresses := newMemoryChecker(t)
+
+	memory_checker.Checkout(0, addresses["__bootstrap"])
+	memory_checker.CheckStore(addresses["__sys_stack_thread_0"], 8)
+	memory_checker.Checkin()
+
+	if memory_checker.NumViolations() != 0 {
+		t.Fatalf("a store to the own stack is reported: %v", memory_checker.ToLines())
+	}
+
+	memory_checker.Checkout(0, addresses["__bootstrap"])
+	memory_checker.CheckStore(addresses["__sys_stack_thread_1"]-4, 8)
+	memory_checker.Checkin()
+
+	expectViolation(t, memory_checker, STACK_OVERFLOW)
+}
+
+func TestMemoryCheckerRodataWrite(t *testing.T) {
+	memory_checker, addresses := newMemoryChecker(t)
+
+	memory_checker.Checkout(1, addresses["__bootstrap"])
+	memory_checker.CheckStore(addresses["value"], 8)
+	memory_checker.CheckStore(addresses["table"], 4)
+	memory_checker.Checkin()
+
+	expectViolation(t, memory_checker, RODATA_WRITE)
+}
+
+// NOTE: the host initializes WRAM data such as value when it loads the kernel, while the heap is
+// only initialized by the tasklets, up to the 64 KB of WRAM that dpu.lds gives to the kernel
+func TestMemoryCheckerUninitializedRead(t *testing.T) {
+	memory_checker, addresses := newMemoryChecker(t)
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if wram_end := config_loader.WramOffset() + 64*1024; memory_checker.heap.end_address != wram_end {
+		t.Errorf("heap ends at %d rather than %d", memory_checker.heap.end_address, wram_end)
+	}
+
+	heap := addresses["__sys_heap_pointer_reset"]
+	memory_checker.MarkWritten(addresses["value"], 8)
+
+	memory_checker.Checkout(0, addresses["__bootstrap"])
+	memory_checker.CheckLoad(addresses["value"], 8)
+	memory_checker.CheckStore(heap, 8)
+	memory_checker.MarkWritten(heap, 8)
+	memory_checker.CheckLoad(heap, 8)
+	memory_checker.CheckLoad(heap+8, 4)
+	memory_checker.Checkin()
+
+	expectViolation(t, memory_checker, UNINITIALIZED_READ)
+}
+
+func TestMemoryCheckerDmaMisalignment(t *testing.T) {
+	memory_checker, addresses := newMemoryChecker(t)
+
+	memory_checker.MarkWritten(addresses["value"], 8)
+
+	memory_checker.Checkout(0, addresses["__bootstrap"])
+	memory_checker.CheckDma(addresses["value"], addresses["buffer"], 8, false)
+	memory_checker.CheckDma(addresses["value"]+4, addresses["buffer"], 8, true)
+	memory_checker.Checkin()
+
+	expectViolation(t, memory_checker, DMA_MISALIGNMENT)
+}
+
+func TestMemoryCheckerDmaOversize(t *testing.T) {
+	memory_checker, addresses := newMemoryChecker(t)
+
+	memory_checker.Checkout(0, addresses["__bootstrap"])
+	memory_checker.CheckDma(addresses["__sys_heap_pointer_reset"], addresses["buffer"], 4096, true)
+	memory_checker.Checkin()
+
+	expectViolation(t, memory_checker, DMA_OVERSIZE)
+}
+
+// NOTE: a disabled checker neither links the kernel nor keeps track of the written WRAM
+func TestMemoryCheckerOff(t *testing.T) {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.STRING, "memory_check", "off", "")
+
+	memory_checker := new(MemoryChecker)
+	memory_checker.Init(0, 0, 0, command_line_parser)
+
+	if memory_checker.written != nil {
+		t.Errorf("%d bytes of WRAM are tracked", len(memory_checker.written))
+	}
+
+	memory_checker.Checkout(0, 0)
+	memory_checker.MarkWritten(0, 4)
+	memory_checker.CheckLoad(8, 4)
+	memory_checker.Checkin()
+
+	if memory_checker.NumViolations() != 0 {
+		t.Errorf("%d violations are reported", memory_checker.NumViolations())
+	}
+}
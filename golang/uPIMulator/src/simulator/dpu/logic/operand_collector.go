@@ -8,11 +8,15 @@ import (
 )
 
 type OperandCollector struct {
-	wram *sram.Wram
+	wram           *sram.Wram
+	memory_checker *MemoryChecker
+	race_detector  *RaceDetector
 }
 
 func (this *OperandCollector) Init() {
 	this.wram = nil
+	this.memory_checker = nil
+	this.race_detector = nil
 }
 
 func (this *OperandCollector) Fini() {
@@ -27,10 +31,71 @@ func (this *OperandCollector) ConnectWram(wram *sram.Wram) {
 	this.wram = wram
 }
 
-func (this *OperandCollector) Lbs(address int64) int64 {
+func (this *OperandCollector) ConnectMemoryChecker(memory_checker *MemoryChecker) {
+	if this.memory_checker != nil {
+		err := errors.New("memory checker is already set")
+		panic(err)
+	}
+
+	this.memory_checker = memory_checker
+}
+
+func (this *OperandCollector) ConnectRaceDetector(race_detector *RaceDetector) {
+	if this.race_detector != nil {
+		err := errors.New("race detector is already set")
+		panic(err)
+	}
+
+	this.race_detector = race_detector
+}
+
+// NOTE: LoadByte and StoreByte bypass the memory checker and the race detector, which check each
+// load and store once with its access size
+func (this *OperandCollector) LoadByte(address int64) int64 {
 	byte_stream := this.wram.Read(address, 1)
+	return int64(byte_stream.Get(0))
+}
+
+func (this *OperandCollector) StoreByte(address int64, value int64) {
+	word_ := new(word.Word)
+	word_.Init(8)
+	word_.SetValue(value)
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	byte_stream.Append(uint8(word_.Value(word.UNSIGNED)))
+
+	this.wram.Write(address, 1, byte_stream)
+
+	if this.memory_checker != nil {
+		this.memory_checker.MarkWritten(address, 1)
+	}
+}
+
+func (this *OperandCollector) CheckLoad(address int64, size int64) {
+	if this.memory_checker != nil {
+		this.memory_checker.CheckLoad(address, size)
+	}
+
+	if this.race_detector != nil {
+		this.race_detector.Load(address, size)
+	}
+}
+
+func (this *OperandCollector) CheckStore(address int64, size int64) {
+	if this.memory_checker != nil {
+		this.memory_checker.CheckStore(address, size)
+	}
 
-	value := int64(byte_stream.Get(0))
+	if this.race_detector != nil {
+		this.race_detector.Store(address, size)
+	}
+}
+
+func (this *OperandCollector) Lbs(address int64) int64 {
+	this.CheckLoad(address, 1)
+
+	value := this.LoadByte(address)
 
 	word_ := new(word.Word)
 	word_.Init(8)
@@ -40,9 +105,9 @@ func (this *OperandCollector) Lbs(address int64) int64 {
 }
 
 func (this *OperandCollector) Lbu(address int64) int64 {
-	byte_stream := this.wram.Read(address, 1)
+	this.CheckLoad(address, 1)
 
-	value := int64(byte_stream.Get(0))
+	value := this.LoadByte(address)
 
 	word_ := new(word.Word)
 	word_.Init(8)
@@ -52,68 +117,79 @@ func (this *OperandCollector) Lbu(address int64) int64 {
 }
 
 func (this *OperandCollector) Lhs(address int64) int64 {
+	this.CheckLoad(address, 2)
+
 	word_ := new(word.Word)
 	word_.Init(16)
-	word_.SetBitSlice(0, 8, this.Lbs(address))
-	word_.SetBitSlice(8, 16, this.Lbs(address+1))
+	word_.SetBitSlice(0, 8, this.LoadByte(address))
+	word_.SetBitSlice(8, 16, this.LoadByte(address+1))
 	return word_.Value(word.SIGNED)
 }
 
 func (this *OperandCollector) Lhu(address int64) int64 {
+	this.CheckLoad(address, 2)
+
 	word_ := new(word.Word)
 	word_.Init(16)
-	word_.SetBitSlice(0, 8, this.Lbu(address))
-	word_.SetBitSlice(8, 16, this.Lbu(address+1))
+	word_.SetBitSlice(0, 8, this.LoadByte(address))
+	word_.SetBitSlice(8, 16, this.LoadByte(address+1))
 	return word_.Value(word.UNSIGNED)
 }
 
 func (this *OperandCollector) Lw(address int64) int64 {
-	word_ := new(word.Word)
-	word_.Init(32)
-	word_.SetBitSlice(0, 8, this.Lbu(address))
-	word_.SetBitSlice(8, 16, this.Lbu(address+1))
-	word_.SetBitSlice(16, 24, this.Lbu(address+2))
-	word_.SetBitSlice(24, 32, this.Lbu(address+3))
-	return word_.Value(word.UNSIGNED)
+	this.CheckLoad(address, 4)
+	return this.ReadWord(address)
 }
 
 func (this *OperandCollector) Ld(address int64) (int64, int64) {
-	return this.Lw(address + 4), this.Lw(address)
+	this.CheckLoad(address, 8)
+	return this.ReadWord(address + 4), this.ReadWord(address)
 }
 
-func (this *OperandCollector) Sb(address int64, value int64) {
+func (this *OperandCollector) ReadWord(address int64) int64 {
 	word_ := new(word.Word)
-	word_.Init(8)
-	word_.SetValue(value)
-
-	byte_stream := new(encoding.ByteStream)
-	byte_stream.Init()
-	byte_stream.Append(uint8(word_.Value(word.UNSIGNED)))
+	word_.Init(32)
+	word_.SetBitSlice(0, 8, this.LoadByte(address))
+	word_.SetBitSlice(8, 16, this.LoadByte(address+1))
+	word_.SetBitSlice(16, 24, this.LoadByte(address+2))
+	word_.SetBitSlice(24, 32, this.LoadByte(address+3))
+	return word_.Value(word.UNSIGNED)
+}
 
-	this.wram.Write(address, 1, byte_stream)
+func (this *OperandCollector) Sb(address int64, value int64) {
+	this.CheckStore(address, 1)
+	this.StoreByte(address, value)
 }
 
 func (this *OperandCollector) Sh(address int64, value int64) {
+	this.CheckStore(address, 2)
+
 	word_ := new(word.Word)
 	word_.Init(16)
 	word_.SetValue(value)
 
-	this.Sb(address, word_.BitSlice(word.UNSIGNED, 0, 8))
-	this.Sb(address+1, word_.BitSlice(word.UNSIGNED, 8, 16))
+	this.StoreByte(address, word_.BitSlice(word.UNSIGNED, 0, 8))
+	this.StoreByte(address+1, word_.BitSlice(word.UNSIGNED, 8, 16))
 }
 
 func (this *OperandCollector) Sw(address int64, value int64) {
+	this.CheckStore(address, 4)
+	this.WriteWord(address, value)
+}
+
+func (this *OperandCollector) Sd(address int64, even int64, odd int64) {
+	this.CheckStore(address, 8)
+	this.WriteWord(address+4, even)
+	this.WriteWord(address, odd)
+}
+
+func (this *OperandCollector) WriteWord(address int64, value int64) {
 	word_ := new(word.Word)
 	word_.Init(32)
 	word_.SetValue(value)
 
-	this.Sb(address, word_.BitSlice(word.UNSIGNED, 0, 8))
-	this.Sb(address+1, word_.BitSlice(word.UNSIGNED, 8, 16))
-	this.Sb(address+2, word_.BitSlice(word.UNSIGNED, 16, 24))
-	this.Sb(address+3, word_.BitSlice(word.UNSIGNED, 24, 32))
-}
-
-func (this *OperandCollector) Sd(address int64, even int64, odd int64) {
-	this.Sw(address+4, even)
-	this.Sw(address, odd)
+	this.StoreByte(address, word_.BitSlice(word.UNSIGNED, 0, 8))
+	this.StoreByte(address+1, word_.BitSlice(word.UNSIGNED, 8, 16))
+	this.StoreByte(address+2, word_.BitSlice(word.UNSIGNED, 16, 24))
+	this.StoreByte(address+3, word_.BitSlice(word.UNSIGNED, 24, 32))
 }
@@ -0,0 +1,346 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"uPIMulator/src/misc"
+)
+
+type AccessType int
+
+const (
+	LOAD_ACCESS AccessType = iota
+	STORE_ACCESS
+)
+
+type VectorClock struct {
+	clocks []int64
+}
+
+func (this *VectorClock) Init(num_threads int) {
+	this.clocks = make([]int64, num_threads)
+}
+
+func (this *VectorClock) Clock(thread_id int) int64 {
+	return this.clocks[thread_id]
+}
+
+func (this *VectorClock) Tick(thread_id int) {
+	this.clocks[thread_id]++
+}
+
+func (this *VectorClock) Join(vector_clock *VectorClock) {
+	for i, clock := range vector_clock.clocks {
+		if clock > this.clocks[i] {
+			this.clocks[i] = clock
+		}
+	}
+}
+
+func (this *VectorClock) Copy() *VectorClock {
+	vector_clock := new(VectorClock)
+	vector_clock.Init(len(this.clocks))
+	copy(vector_clock.clocks, this.clocks)
+	return vector_clock
+}
+
+type Access struct {
+	thread_id int
+	clock     int64
+	pc        int64
+}
+
+// NOTE: a WRAM byte remembers its last store and the last load of each tasklet since that store,
+// which is all that is needed to find an access that does not happen before the next one
+type Shadow struct {
+	store *Access
+	loads map[int]*Access
+}
+
+type Race struct {
+	address int64
+
+	first_access_type AccessType
+	first_access      *Access
+
+	second_access_type AccessType
+	second_access      *Access
+
+	count int64
+}
+
+type RaceDetector struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	is_enabled  bool
+	bin_dirpath string
+
+	symbolizer *Symbolizer
+
+	vector_clocks      []*VectorClock
+	lock_vector_clocks map[int64]*VectorClock
+	shadows            map[int64]*Shadow
+
+	thread_id int
+	pc        int64
+
+	races map[string]*Race
+}
+
+func (this *RaceDetector) Init(
+	channel_id int,
+	rank_id int,
+	dpu_id int,
+	command_line_parser *misc.CommandLineParser,
+) {
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.is_enabled = command_line_parser.BoolParameter("detect_races")
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
+
+	this.symbolizer = nil
+	if this.is_enabled {
+		this.symbolizer = new(Symbolizer)
+		this.symbolizer.Init(this.bin_dirpath)
+	}
+
+	this.Reset()
+	this.Checkin()
+
+	this.races = make(map[string]*Race, 0)
+}
+
+func (this *RaceDetector) IsEnabled() bool {
+	return this.is_enabled
+}
+
+// NOTE: the host launches a kernel only after the previous one has finished and every tasklet
+// starts with its own epoch, so nothing of the previous launch can race with the next one
+func (this *RaceDetector) Reset() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	max_num_tasklets := config_loader.MaxNumTasklets()
+
+	this.vector_clocks = make([]*VectorClock, 0)
+	for i := 0; i < max_num_tasklets; i++ {
+		vector_clock := new(VectorClock)
+		vector_clock.Init(max_num_tasklets)
+		vector_clock.Tick(i)
+
+		this.vector_clocks = append(this.vector_clocks, vector_clock)
+	}
+
+	this.lock_vector_clocks = make(map[int64]*VectorClock, 0)
+	this.shadows = make(map[int64]*Shadow, 0)
+}
+
+func (this *RaceDetector) Checkout(thread_id int, pc int64) {
+	this.thread_id = thread_id
+	this.pc = pc
+}
+
+func (this *RaceDetector) Checkin() {
+	this.thread_id = -1
+	this.pc = -1
+}
+
+func (this *RaceDetector) IsCheckedOut() bool {
+	return this.thread_id >= 0
+}
+
+// NOTE: a successful acquire happens after the release that last freed the same atomic bit
+func (this *RaceDetector) Acquire(atomic_address int64) {
+	if !this.is_enabled || !this.IsCheckedOut() {
+		return
+	}
+
+	if lock_vector_clock, found := this.lock_vector_clocks[atomic_address]; found {
+		this.vector_clocks[this.thread_id].Join(lock_vector_clock)
+	}
+}
+
+func (this *RaceDetector) Release(atomic_address int64) {
+	if !this.is_enabled || !this.IsCheckedOut() {
+		return
+	}
+
+	this.lock_vector_clocks[atomic_address] = this.vector_clocks[this.thread_id].Copy()
+	this.vector_clocks[this.thread_id].Tick(this.thread_id)
+}
+
+// NOTE: everything a tasklet has done before booting or resuming another tasklet happens before
+// what the other tasklet does next
+func (this *RaceDetector) Signal(thread_id int) {
+	if !this.is_enabled || !this.IsCheckedOut() {
+		return
+	}
+
+	this.vector_clocks[thread_id].Join(this.vector_clocks[this.thread_id])
+	this.vector_clocks[this.thread_id].Tick(this.thread_id)
+}
+
+// NOTE: every byte of an access shares the same Access, so the bytes that an earlier access
+// overlaps are reported as a single conflict
+func (this *RaceDetector) Load(address int64, size int64) {
+	if !this.is_enabled || !this.IsCheckedOut() {
+		return
+	}
+
+	access := this.Access()
+	vector_clock := this.vector_clocks[this.thread_id]
+	reported := make(map[*Access]bool, 0)
+
+	for i := int64(0); i < size; i++ {
+		shadow := this.Shadow(address + i)
+
+		if store := shadow.store; store != nil && !reported[store] &&
+			this.IsConcurrent(store, vector_clock) {
+			this.Report(address+i, STORE_ACCESS, store, LOAD_ACCESS, access)
+			reported[store] = true
+		}
+
+		shadow.loads[this.thread_id] = access
+	}
+}
+
+func (this *RaceDetector) Store(address int64, size int64) {
+	if !this.is_enabled || !this.IsCheckedOut() {
+		return
+	}
+
+	access := this.Access()
+	vector_clock := this.vector_clocks[this.thread_id]
+	reported := make(map[*Access]bool, 0)
+
+	for i := int64(0); i < size; i++ {
+		shadow := this.Shadow(address + i)
+
+		if store := shadow.store; store != nil && !reported[store] &&
+			this.IsConcurrent(store, vector_clock) {
+			this.Report(address+i, STORE_ACCESS, store, STORE_ACCESS, access)
+			reported[store] = true
+		}
+
+		for _, load := range shadow.loads {
+			if !reported[load] && this.IsConcurrent(load, vector_clock) {
+				this.Report(address+i, LOAD_ACCESS, load, STORE_ACCESS, access)
+				reported[load] = true
+			}
+		}
+
+		shadow.store = access
+		shadow.loads = make(map[int]*Access, 0)
+	}
+}
+
+func (this *RaceDetector) Access() *Access {
+	access := new(Access)
+	access.thread_id = this.thread_id
+	access.clock = this.vector_clocks[this.thread_id].Clock(this.thread_id)
+	access.pc = this.pc
+	return access
+}
+
+func (this *RaceDetector) Shadow(address int64) *Shadow {
+	if _, found := this.shadows[address]; !found {
+		shadow := new(Shadow)
+		shadow.store = nil
+		shadow.loads = make(map[int]*Access, 0)
+
+		this.shadows[address] = shadow
+	}
+
+	return this.shadows[address]
+}
+
+func (this *RaceDetector) IsConcurrent(access *Access, vector_clock *VectorClock) bool {
+	return access.thread_id != this.thread_id && access.clock > vector_clock.Clock(access.thread_id)
+}
+
+func (this *RaceDetector) Report(
+	address int64,
+	first_access_type AccessType,
+	first_access *Access,
+	second_access_type AccessType,
+	second_access *Access,
+) {
+	key := fmt.Sprintf(
+		"%d %d %d %d %d %d",
+		first_access_type,
+		first_access.thread_id,
+		first_access.pc,
+		second_access_type,
+		second_access.thread_id,
+		second_access.pc,
+	)
+
+	if race, found := this.races[key]; found {
+		race.count++
+	} else {
+		race := new(Race)
+		race.address = address
+		race.first_access_type = first_access_type
+		race.first_access = first_access
+		race.second_access_type = second_access_type
+		race.second_access = second_access
+		race.count = 1
+
+		this.races[key] = race
+	}
+}
+
+func (this *RaceDetector) NumRaces() int {
+	return len(this.races)
+}
+
+func (this *RaceDetector) StringifyAccess(access_type AccessType, access *Access) string {
+	if access_type == LOAD_ACCESS {
+		return fmt.Sprintf("tasklet %d loads at %s", access.thread_id, this.symbolizer.Locate(access.pc))
+	} else {
+		return fmt.Sprintf("tasklet %d stores at %s", access.thread_id, this.symbolizer.Locate(access.pc))
+	}
+}
+
+// NOTE: a race is reported once per pair of PCs and tasklets with the first conflicting address
+// and the number of conflicting accesses, however many bytes each pair of accesses overlaps
+func (this *RaceDetector) ToLines() []string {
+	races := make([]*Race, 0)
+	for _, race := range this.races {
+		races = append(races, race)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if races[i].address != races[j].address {
+			return races[i].address < races[j].address
+		} else if races[i].first_access.pc != races[j].first_access.pc {
+			return races[i].first_access.pc < races[j].first_access.pc
+		} else if races[i].second_access.pc != races[j].second_access.pc {
+			return races[i].second_access.pc < races[j].second_access.pc
+		} else if races[i].first_access.thread_id != races[j].first_access.thread_id {
+			return races[i].first_access.thread_id < races[j].first_access.thread_id
+		}
+		return races[i].second_access.thread_id < races[j].second_access.thread_id
+	}
+
+	sort.Slice(races, sort_fn)
+
+	lines := make([]string, 0)
+	for _, race := range races {
+		lines = append(lines, fmt.Sprintf(
+			"DPU (%d, %d, %d) race on WRAM %s: %s and %s (%d times)",
+			this.channel_id,
+			this.rank_id,
+			this.dpu_id,
+			this.symbolizer.Describe(race.address),
+			this.StringifyAccess(race.first_access_type, race.first_access),
+			this.StringifyAccess(race.second_access_type, race.second_access),
+			race.count,
+		))
+	}
+	return lines
+}
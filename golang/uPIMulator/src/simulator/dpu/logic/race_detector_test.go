@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"testing"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: races are checked without a symbolizer, which only matters to ToLines
+func newRaceDetector(t *testing.T) *RaceDetector {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.BOOL, "detect_races", "false", "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", t.TempDir(), "")
+
+	race_detector := new(RaceDetector)
+	race_detector.Init(0, 0, 0, command_line_parser)
+	race_detector.is_enabled = true
+	return race_detector
+}
+
+func count(race_detector *RaceDetector) int64 {
+	count := int64(0)
+	for _, race := range race_detector.races {
+		count += race.count
+	}
+	return count
+}
+
+func TestRaceDetectorReportsOncePerAccess(t *testing.T) {
+	race_detector := newRaceDetector(t)
+
+	race_detector.Checkout(0, 100)
+	race_detector.Store(64, 4)
+	race_detector.Checkout(1, 200)
+	race_detector.Store(64, 4)
+	race_detector.Checkin()
+
+	if race_detector.NumRaces() != 1 || count(race_detector) != 1 {
+		t.Errorf("a 4-byte store pair is reported %d times", count(race_detector))
+	}
+
+	race_detector.Checkout(0, 300)
+	race_detector.Load(62, 8)
+	race_detector.Checkin()
+
+	if race_detector.NumRaces() != 2 || count(race_detector) != 2 {
+		t.Errorf("a load overlapping a store is reported %d times", count(race_detector)-1)
+	}
+
+	race_detector.Checkout(1, 200)
+	race_detector.Store(64, 4)
+	race_detector.Checkin()
+
+	if race_detector.NumRaces() != 3 || count(race_detector) != 3 {
+		t.Errorf("a store after a load is reported %d times", count(race_detector)-2)
+	}
+}
+
+func TestRaceDetectorSynchronization(t *testing.T) {
+	race_detector := newRaceDetector(t)
+
+	race_detector.Checkout(0, 100)
+	race_detector.Acquire(8)
+	race_detector.Store(64, 8)
+	race_detector.Release(8)
+	race_detector.Checkout(1, 200)
+	race_detector.Acquire(8)
+	race_detector.Store(64, 8)
+	race_detector.Release(8)
+	race_detector.Checkin()
+
+	if race_detector.NumRaces() != 0 {
+		t.Errorf("stores under the same lock are reported as %d races", race_detector.NumRaces())
+	}
+}
@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/misc"
+)
+
+type Symbolizer struct {
+	symbol_table      *kernel.SymbolTable
+	source_line_table *kernel.SourceLineTable
+}
+
+func (this *Symbolizer) Init(bin_dirpath string) {
+	this.symbol_table = new(kernel.SymbolTable)
+	this.symbol_table.Init()
+	this.symbol_table.Load(filepath.Join(bin_dirpath, "addresses.txt"))
+
+	this.source_line_table = new(kernel.SourceLineTable)
+	this.source_line_table.Init()
+
+	source_lines_path := filepath.Join(bin_dirpath, "source_lines.txt")
+	if _, err := os.Stat(source_lines_path); err == nil {
+		this.source_line_table.Load(source_lines_path)
+	}
+}
+
+func (this *Symbolizer) Locate(pc int64) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	location := fmt.Sprintf("PC %d", pc)
+
+	iram_begin := config_loader.IramOffset()
+	iram_end := config_loader.IramOffset() + config_loader.IramSize()
+	if symbol := this.symbol_table.Symbolize(pc, iram_begin, iram_end); symbol != "" {
+		location = fmt.Sprintf("%s <%s>", location, symbol)
+	}
+
+	if source_line := this.source_line_table.SourceLine(pc); source_line != nil {
+		location = fmt.Sprintf("%s at %s", location, source_line.Stringify())
+	}
+
+	return location
+}
+
+// NOTE: WRAM and MRAM addresses are symbolized within their own memory so that a WRAM address is
+// never attributed to an MRAM symbol
+func (this *Symbolizer) Describe(address int64) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	begin_address := config_loader.WramOffset()
+	end_address := config_loader.WramOffset() + config_loader.WramSize()
+	if config_loader.MramOffset() <= address {
+		begin_address = config_loader.MramOffset()
+		end_address = config_loader.MramOffset() + config_loader.MramSize()
+	}
+
+	if symbol := this.symbol_table.Symbolize(address, begin_address, end_address); symbol != "" {
+		return fmt.Sprintf("%d <%s>", address, symbol)
+	} else {
+		return fmt.Sprintf("%d", address)
+	}
+}
@@ -70,6 +70,10 @@ func (this *ThreadScheduler) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
 
+func (this *ThreadScheduler) Threads() []*Thread {
+	return this.threads
+}
+
 func (this *ThreadScheduler) NumIssuableThreads() int {
 	num_issuable_threads := 0
 
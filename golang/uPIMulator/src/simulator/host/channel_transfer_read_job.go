@@ -9,15 +9,25 @@ import (
 type ChannelTransferReadJob struct {
 	channel_message *channel.ChannelMessage
 
-	byte_streams []*encoding.ByteStream
-
-	channel *channel.Channel
+	execution      int
+	unique_dpu_ids []int
+	name           string
+	offset         int64
+	byte_streams   []*encoding.ByteStream
+
+	channel  *channel.Channel
+	verifier *Verifier
 }
 
 func (this *ChannelTransferReadJob) Init(
 	channel_message *channel.ChannelMessage,
+	execution int,
+	unique_dpu_ids []int,
+	name string,
+	offset int64,
 	byte_streams []*encoding.ByteStream,
 	channel_ *channel.Channel,
+	verifier *Verifier,
 ) {
 	if channel_message.ChannelOperation() != channel.READ {
 		err := errors.New("channel operation is not read")
@@ -25,8 +35,13 @@ func (this *ChannelTransferReadJob) Init(
 	}
 
 	this.channel_message = channel_message
+	this.execution = execution
+	this.unique_dpu_ids = unique_dpu_ids
+	this.name = name
+	this.offset = offset
 	this.byte_streams = byte_streams
 	this.channel = channel_
+	this.verifier = verifier
 }
 
 func (this *ChannelTransferReadJob) Execute() {
@@ -40,34 +55,19 @@ func (this *ChannelTransferReadJob) Execute() {
 
 	this.channel.PopChannelMessage(this.channel_message)
 
-	this.CompareByteStreams(this.byte_streams, this.channel_message.ByteStreams())
-
 	this.channel.Unlock()
-}
 
-func (this *ChannelTransferReadJob) CompareByteStreams(
-	byte_streams_1 []*encoding.ByteStream,
-	byte_streams_2 []*encoding.ByteStream,
-) {
-	if len(byte_streams_1) != len(byte_streams_2) {
-		err := errors.New("byte streams 1's length != byte streams 2's length")
-		panic(err)
+	// NOTE: communication steps read DPU memory without any expected outputs to verify against
+	if this.verifier == nil {
+		return
 	}
 
-	for i := 0; i < len(byte_streams_1); i++ {
-		byte_stream_1 := byte_streams_1[i]
-		byte_stream_2 := byte_streams_2[i]
-
-		if byte_stream_1.Size() != byte_stream_2.Size() {
-			err := errors.New("byte stream 1's size != byte stream 2's size")
-			panic(err)
-		}
-
-		for j := int64(0); j < byte_stream_1.Size(); j++ {
-			if byte_stream_1.Get(int(j)) != byte_stream_2.Get(int(j)) {
-				err := errors.New("bytes are different")
-				panic(err)
-			}
-		}
-	}
+	this.verifier.Verify(
+		this.execution,
+		this.unique_dpu_ids,
+		this.name,
+		this.offset,
+		this.byte_streams,
+		this.channel_message.ByteStreams(),
+	)
 }
@@ -0,0 +1,384 @@
+package host
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/assembler/communication"
+	"uPIMulator/src/core"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+	"uPIMulator/src/simulator/dpu"
+)
+
+func (this *Host) InitCommunications() {
+	this.communications = make([]*communication.Communication, 0)
+
+	path := filepath.Join(this.bin_dirpath, "communications.txt")
+
+	if _, stat_err := os.Stat(path); stat_err != nil {
+		if os.IsNotExist(stat_err) {
+			return
+		}
+
+		panic(stat_err)
+	}
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	for _, line := range file_scanner.ReadLines() {
+		if line == "" {
+			continue
+		}
+
+		communication_ := new(communication.Communication)
+		communication_.InitLine(line)
+
+		this.communications = append(this.communications, communication_)
+	}
+}
+
+// NOTE: data steps run before the outputs of the execution are checked so that the results of the
+// host-side reductions are verified as well, whereas loads run after them since a load resets WRAM
+func (this *Host) Communicate(execution int) {
+	for _, communication_ := range this.communications {
+		if communication_.Execution() == execution &&
+			communication_.CommunicationType() != communication.LOAD {
+			this.CommunicateStep(communication_)
+		}
+	}
+}
+
+func (this *Host) Reload(execution int) {
+	for _, communication_ := range this.communications {
+		if communication_.Execution() == execution &&
+			communication_.CommunicationType() == communication.LOAD {
+			this.LoadDpus(this.SelectDpus(communication_.DstDpuIds()))
+
+			this.stat_factory.Increment("num_loads", 1)
+		}
+	}
+}
+
+func (this *Host) CommunicateStep(communication_ *communication.Communication) {
+	channel_cycles := this.ChannelCycles()
+
+	src_address := this.CommunicationAddress(communication_.SrcName(), communication_.SrcOffset())
+	dst_address := this.CommunicationAddress(communication_.DstName(), communication_.DstOffset())
+
+	src_dpu_ids := communication_.SrcDpuIds()
+	dst_dpu_ids := communication_.DstDpuIds()
+	size := communication_.Size()
+
+	src_byte_streams := this.ChannelTransferFromDpus(src_dpu_ids, src_address, size)
+	dst_byte_streams := make(map[int]*encoding.ByteStream, 0)
+
+	communication_type := communication_.CommunicationType()
+	if communication_type == communication.BROADCAST {
+		for _, dst_dpu_id := range dst_dpu_ids {
+			dst_byte_streams[dst_dpu_id] = src_byte_streams[src_dpu_ids[0]]
+		}
+	} else if communication_type == communication.SCATTER {
+		slice_size := size / int64(len(dst_dpu_ids))
+
+		for i, dst_dpu_id := range dst_dpu_ids {
+			byte_stream := new(encoding.ByteStream)
+			byte_stream.Init()
+
+			for j := int64(i) * slice_size; j < int64(i+1)*slice_size; j++ {
+				byte_stream.Append(src_byte_streams[src_dpu_ids[0]].Get(int(j)))
+			}
+
+			dst_byte_streams[dst_dpu_id] = byte_stream
+		}
+	} else if communication_type == communication.GATHER {
+		byte_stream := new(encoding.ByteStream)
+		byte_stream.Init()
+
+		for _, src_dpu_id := range src_dpu_ids {
+			byte_stream.Merge(src_byte_streams[src_dpu_id])
+		}
+
+		for _, dst_dpu_id := range dst_dpu_ids {
+			dst_byte_streams[dst_dpu_id] = byte_stream
+		}
+	} else if communication_type == communication.COPY {
+		for i, dst_dpu_id := range dst_dpu_ids {
+			dst_byte_streams[dst_dpu_id] = src_byte_streams[src_dpu_ids[i]]
+		}
+	} else if communication_type == communication.REDUCE {
+		sums := make([]int64, size/8)
+		for _, src_dpu_id := range src_dpu_ids {
+			this.Accumulate(sums, src_byte_streams[src_dpu_id])
+		}
+
+		byte_stream := this.ToByteStream(sums)
+		for _, dst_dpu_id := range dst_dpu_ids {
+			dst_byte_streams[dst_dpu_id] = byte_stream
+		}
+	} else if communication_type == communication.SCAN {
+		sums := make([]int64, size/8)
+		for i, dst_dpu_id := range dst_dpu_ids {
+			dst_byte_streams[dst_dpu_id] = this.ToByteStream(sums)
+
+			this.Accumulate(sums, src_byte_streams[src_dpu_ids[i]])
+		}
+	} else {
+		err := errors.New("communication type is not valid")
+		panic(err)
+	}
+
+	this.ChannelTransferToDpus(dst_byte_streams, dst_address)
+
+	communication_cycle := int64(0)
+	for channel_id, channel_cycle := range this.ChannelCycles() {
+		if channel_cycle-channel_cycles[channel_id] > communication_cycle {
+			communication_cycle = channel_cycle - channel_cycles[channel_id]
+		}
+	}
+
+	communication_bytes := size * int64(len(src_byte_streams))
+	for _, byte_stream := range dst_byte_streams {
+		communication_bytes += byte_stream.Size()
+	}
+
+	this.stat_factory.Increment("num_communications", 1)
+	this.stat_factory.Increment("communication_cycle", communication_cycle)
+	this.stat_factory.Increment("communication_bytes", communication_bytes)
+
+	// NOTE: the DPUs idle while the host communicates, so the slowest channel of the step is added
+	// to the timeline of the run
+	this.stat_factory.Increment("total_cycle", communication_cycle)
+}
+
+func (this *Host) Accumulate(sums []int64, byte_stream *encoding.ByteStream) {
+	for i, _ := range sums {
+		element_byte_stream := new(encoding.ByteStream)
+		element_byte_stream.Init()
+		for j := 0; j < 8; j++ {
+			element_byte_stream.Append(byte_stream.Get(8*i + j))
+		}
+
+		element_word := new(word.Word)
+		element_word.Init(64)
+		element_word.FromByteStream(element_byte_stream)
+
+		sums[i] += element_word.Value(word.SIGNED)
+	}
+}
+
+func (this *Host) ToByteStream(elements []int64) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, element := range elements {
+		element_word := new(word.Word)
+		element_word.Init(64)
+		element_word.SetValue(element)
+		byte_stream.Merge(element_word.ToByteStream())
+	}
+
+	return byte_stream
+}
+
+func (this *Host) CommunicationAddress(name string, offset int64) int64 {
+	if name == "DPU_MRAM_HEAP_POINTER_NAME" {
+		if _, found := this.values["__sys_used_mram_end"]; !found {
+			err := errors.New("__sys_used_mram_end is not found")
+			panic(err)
+		}
+
+		return this.values["__sys_used_mram_end"] + offset
+	}
+
+	if _, found := this.addresses[name]; !found {
+		err := errors.New("pointer is not found")
+		panic(err)
+	}
+
+	return this.addresses[name] + offset
+}
+
+func (this *Host) ChannelCycles() []int64 {
+	channel_cycles := make([]int64, 0)
+
+	for _, channel_ := range this.channels {
+		channel_cycles = append(channel_cycles, channel_.StatFactory().Value("channel_cycle"))
+	}
+
+	return channel_cycles
+}
+
+func (this *Host) ChannelTransferFromDpus(
+	unique_dpu_ids []int,
+	address int64,
+	size int64,
+) map[int]*encoding.ByteStream {
+	is_selected := make(map[int]bool, 0)
+	for _, unique_dpu_id := range unique_dpu_ids {
+		this.ValidateUniqueDpuId(unique_dpu_id)
+
+		is_selected[unique_dpu_id] = true
+	}
+
+	thread_pool := new(core.ThreadPool)
+	thread_pool.Init(this.num_simulation_threads)
+
+	channel_messages := make([]*channel.ChannelMessage, 0)
+	channel_message_unique_dpu_ids := make([][]int, 0)
+
+	for _, channel_ := range this.channels {
+		channel_id := channel_.ChannelId()
+		ranks := channel_.Ranks()
+
+		for _, rank_ := range ranks {
+			rank_id := rank_.RankId()
+			dpus := rank_.Dpus()
+
+			for i := 0; i < 8; i++ {
+				dpu_ids := make([]int, 0)
+				selected_unique_dpu_ids := make([]int, 0)
+
+				for _, dpu_ := range dpus {
+					dpu_id := dpu_.DpuId()
+					unique_dpu_id := channel_id*this.num_ranks_per_channel*this.num_dpus_per_rank + rank_id*this.num_dpus_per_rank + dpu_id
+
+					if dpu_id%8 == i && is_selected[unique_dpu_id] {
+						dpu_ids = append(dpu_ids, dpu_id)
+						selected_unique_dpu_ids = append(selected_unique_dpu_ids, unique_dpu_id)
+					}
+				}
+
+				if len(dpu_ids) != 0 {
+					channel_message := new(channel.ChannelMessage)
+					channel_message.InitRead(channel_id, rank_id, dpu_ids, address, size)
+
+					channel_transfer_read_job := new(ChannelTransferReadJob)
+					channel_transfer_read_job.Init(
+						channel_message,
+						-1,
+						selected_unique_dpu_ids,
+						"",
+						0,
+						nil,
+						channel_,
+						nil,
+					)
+
+					thread_pool.Enque(channel_transfer_read_job)
+
+					channel_messages = append(channel_messages, channel_message)
+					channel_message_unique_dpu_ids = append(
+						channel_message_unique_dpu_ids,
+						selected_unique_dpu_ids,
+					)
+				}
+			}
+		}
+	}
+
+	thread_pool.Start()
+
+	byte_streams := make(map[int]*encoding.ByteStream, 0)
+	for i, channel_message := range channel_messages {
+		for j, unique_dpu_id := range channel_message_unique_dpu_ids[i] {
+			byte_streams[unique_dpu_id] = channel_message.ByteStreams()[j]
+		}
+	}
+
+	return byte_streams
+}
+
+func (this *Host) ChannelTransferToDpus(byte_streams map[int]*encoding.ByteStream, address int64) {
+	for unique_dpu_id, _ := range byte_streams {
+		this.ValidateUniqueDpuId(unique_dpu_id)
+	}
+
+	thread_pool := new(core.ThreadPool)
+	thread_pool.Init(this.num_simulation_threads)
+
+	for _, channel_ := range this.channels {
+		channel_id := channel_.ChannelId()
+		ranks := channel_.Ranks()
+
+		for _, rank_ := range ranks {
+			rank_id := rank_.RankId()
+			dpus := rank_.Dpus()
+
+			for i := 0; i < 8; i++ {
+				dpu_ids := make([]int, 0)
+				selected_byte_streams := make([]*encoding.ByteStream, 0)
+
+				for _, dpu_ := range dpus {
+					dpu_id := dpu_.DpuId()
+					unique_dpu_id := channel_id*this.num_ranks_per_channel*this.num_dpus_per_rank + rank_id*this.num_dpus_per_rank + dpu_id
+
+					if byte_stream, found := byte_streams[unique_dpu_id]; dpu_id%8 == i && found {
+						dpu_ids = append(dpu_ids, dpu_id)
+						selected_byte_streams = append(selected_byte_streams, byte_stream)
+					}
+				}
+
+				if len(dpu_ids) != 0 {
+					channel_message := new(channel.ChannelMessage)
+					channel_message.InitWrite(
+						channel_id,
+						rank_id,
+						dpu_ids,
+						address,
+						selected_byte_streams[0].Size(),
+						selected_byte_streams,
+					)
+
+					channel_transfer_write_job := new(ChannelTransferWriteJob)
+					channel_transfer_write_job.Init(channel_message, channel_)
+
+					thread_pool.Enque(channel_transfer_write_job)
+				}
+			}
+		}
+	}
+
+	thread_pool.Start()
+}
+
+func (this *Host) SelectDpus(unique_dpu_ids []int) []*dpu.Dpu {
+	is_selected := make(map[int]bool, 0)
+	for _, unique_dpu_id := range unique_dpu_ids {
+		this.ValidateUniqueDpuId(unique_dpu_id)
+
+		is_selected[unique_dpu_id] = true
+	}
+
+	dpus := make([]*dpu.Dpu, 0)
+	for _, channel_ := range this.channels {
+		channel_id := channel_.ChannelId()
+
+		for _, rank_ := range channel_.Ranks() {
+			rank_id := rank_.RankId()
+
+			for _, dpu_ := range rank_.Dpus() {
+				unique_dpu_id := channel_id*this.num_ranks_per_channel*this.num_dpus_per_rank + rank_id*this.num_dpus_per_rank + dpu_.DpuId()
+
+				if is_selected[unique_dpu_id] {
+					dpus = append(dpus, dpu_)
+				}
+			}
+		}
+	}
+
+	return dpus
+}
+
+func (this *Host) ValidateUniqueDpuId(unique_dpu_id int) {
+	if unique_dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	} else if unique_dpu_id >= this.num_channels*this.num_ranks_per_channel*this.num_dpus_per_rank {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+}
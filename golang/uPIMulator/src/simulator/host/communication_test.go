@@ -0,0 +1,221 @@
+package host
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/assembler/communication"
+	"uPIMulator/src/linker"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+)
+
+func newCommandLineParser(t *testing.T, num_dpus int) *misc.CommandLineParser {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", "..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	bin_dirpath := t.TempDir()
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "root_dirpath", root_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", bin_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "")
+	command_line_parser.AddOption(misc.INT, "verbose", "0", "")
+	command_line_parser.AddOption(misc.INT, "num_simulation_threads", "4", "")
+	command_line_parser.AddOption(misc.INT, "num_channels", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_ranks_per_channel", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_dpus_per_rank", "4", "")
+	command_line_parser.AddOption(misc.INT, "num_tasklets", "1", "")
+	command_line_parser.AddOption(misc.STRING, "verify", "strict", "")
+	command_line_parser.AddOption(misc.STRING, "memory_check", "off", "")
+	command_line_parser.AddOption(misc.BOOL, "detect_races", "false", "")
+	command_line_parser.AddOption(misc.BOOL, "profile", "false", "")
+	command_line_parser.AddOption(misc.STRING, "asm_filepaths", "", "")
+	command_line_parser.AddOption(misc.STRING, "elf_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "sdk_objects", "", "")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "cache_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.BOOL, "gc_sections", "false", "")
+	command_line_parser.AddOption(misc.INT, "logic_frequency", "350", "")
+	command_line_parser.AddOption(misc.INT, "memory_frequency", "2400", "")
+	command_line_parser.AddOption(misc.INT, "num_pipeline_stages", "14", "")
+	command_line_parser.AddOption(misc.INT, "num_revolver_scheduling_cycles", "11", "")
+	command_line_parser.AddOption(misc.INT, "wordline_size", "1024", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	command_line_parser.AddOption(misc.INT, "t_rcd", "32", "")
+	command_line_parser.AddOption(misc.INT, "t_ras", "78", "")
+	command_line_parser.AddOption(misc.INT, "t_rp", "32", "")
+	command_line_parser.AddOption(misc.INT, "t_cl", "32", "")
+	command_line_parser.AddOption(misc.INT, "t_bl", "8", "")
+	command_line_parser.AddOption(misc.INT, "read_bandwidth", "1", "")
+	command_line_parser.AddOption(misc.INT, "write_bandwidth", "3", "")
+	command_line_parser.Parse([]string{
+		"host.test",
+		"--asm_filepaths",
+		filepath.Join(root_dirpath, "src", "linker", "testdata", "kernel.S"),
+		"--num_dpus_per_rank",
+		strconv.Itoa(num_dpus),
+	})
+
+	return command_line_parser
+}
+
+// NOTE: the host runs on linker/testdata/kernel.S, whose MRAM variable buffer is 8 bytes, with no
+// execution launched, so that only the channel transfers of the communication steps are timed
+func newHost(t *testing.T, num_dpus int) *Host {
+	command_line_parser := newCommandLineParser(t, num_dpus)
+
+	linker_ := new(linker.Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	return newLoadedHost(t, command_line_parser)
+}
+
+func newLoadedHost(t *testing.T, command_line_parser *misc.CommandLineParser) *Host {
+	bin_dirpath := command_line_parser.StringParameter("bin_dirpath")
+
+	num_executions_path := filepath.Join(bin_dirpath, "num_executions.txt")
+	if err := os.WriteFile(num_executions_path, []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	host := new(Host)
+	host.Init(command_line_parser)
+
+	channel_ := new(channel.Channel)
+	channel_.Init(0, command_line_parser)
+
+	host.ConnectChannels([]*channel.Channel{channel_})
+	host.Load()
+
+	return host
+}
+
+func toByteStream(elements ...int64) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, element := range elements {
+		element_word := new(word.Word)
+		element_word.Init(64)
+		element_word.SetValue(element)
+		byte_stream.Merge(element_word.ToByteStream())
+	}
+
+	return byte_stream
+}
+
+func expectByteStreams(
+	t *testing.T,
+	label string,
+	byte_streams map[int]*encoding.ByteStream,
+	expected *encoding.ByteStream,
+) {
+	t.Helper()
+
+	for dpu_id, byte_stream := range byte_streams {
+		if byte_stream.Size() != expected.Size() {
+			t.Errorf("%s: DPU %d has %d bytes", label, dpu_id, byte_stream.Size())
+			continue
+		}
+
+		for i := 0; i < int(expected.Size()); i++ {
+			if byte_stream.Get(i) != expected.Get(i) {
+				t.Errorf("%s: DPU %d byte %d is %d", label, dpu_id, i, byte_stream.Get(i))
+				break
+			}
+		}
+	}
+}
+
+func TestCommunicationGatherBroadcast(t *testing.T) {
+	host := newHost(t, 4)
+	dpu_ids := []int{0, 1, 2, 3}
+
+	buffers := make(map[int]*encoding.ByteStream, 0)
+	for _, dpu_id := range dpu_ids {
+		buffers[dpu_id] = toByteStream(int64(100 + dpu_id))
+	}
+	host.ChannelTransferToDpus(buffers, host.CommunicationAddress("buffer", 0))
+
+	gather := new(communication.Communication)
+	gather.InitGather(0, dpu_ids, "buffer", 0, []int{0}, "DPU_MRAM_HEAP_POINTER_NAME", 0, 8)
+	host.CommunicateStep(gather)
+
+	broadcast := new(communication.Communication)
+	broadcast.InitBroadcast(
+		0,
+		0,
+		"DPU_MRAM_HEAP_POINTER_NAME",
+		0,
+		[]int{1, 2, 3},
+		"DPU_MRAM_HEAP_POINTER_NAME",
+		0,
+		32,
+	)
+	host.CommunicateStep(broadcast)
+
+	heap_address := host.CommunicationAddress("DPU_MRAM_HEAP_POINTER_NAME", 0)
+	expectByteStreams(
+		t,
+		"gathered and broadcast buffers",
+		host.ChannelTransferFromDpus(dpu_ids, heap_address, 32),
+		toByteStream(100, 101, 102, 103),
+	)
+
+	stat_factory := host.StatFactory()
+	if num_communications := stat_factory.Value("num_communications"); num_communications != 2 {
+		t.Errorf("%d communications are counted", num_communications)
+	}
+
+	communication_cycle := stat_factory.Value("communication_cycle")
+	if communication_cycle <= 0 {
+		t.Errorf("communications take %d cycles", communication_cycle)
+	} else if total_cycle := stat_factory.Value("total_cycle"); total_cycle != communication_cycle {
+		t.Errorf("%d of %d cycles of communication are on the timeline", total_cycle, communication_cycle)
+	}
+}
+
+func TestCommunicationReduceScan(t *testing.T) {
+	host := newHost(t, 3)
+	dpu_ids := []int{0, 1, 2}
+
+	buffers := make(map[int]*encoding.ByteStream, 0)
+	for _, dpu_id := range dpu_ids {
+		buffers[dpu_id] = toByteStream(int64(dpu_id*dpu_id - 2))
+	}
+	host.ChannelTransferToDpus(buffers, host.CommunicationAddress("buffer", 0))
+
+	reduce := new(communication.Communication)
+	reduce.InitReduce(0, dpu_ids, "buffer", 0, dpu_ids, "DPU_MRAM_HEAP_POINTER_NAME", 0, 8)
+	host.CommunicateStep(reduce)
+
+	scan := new(communication.Communication)
+	scan.InitScan(0, dpu_ids, "buffer", 0, dpu_ids, "DPU_MRAM_HEAP_POINTER_NAME", 8, 8)
+	host.CommunicateStep(scan)
+
+	heap_address := host.CommunicationAddress("DPU_MRAM_HEAP_POINTER_NAME", 0)
+	expectByteStreams(
+		t,
+		"reduced buffers",
+		host.ChannelTransferFromDpus(dpu_ids, heap_address, 8),
+		toByteStream(-1),
+	)
+
+	prefix_sums := []int64{0, -2, -3}
+	for i, dpu_id := range dpu_ids {
+		expectByteStreams(
+			t,
+			"scanned buffers",
+			host.ChannelTransferFromDpus([]int{dpu_id}, heap_address+8, 8),
+			toByteStream(prefix_sums[i]),
+		)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/assembler/communication"
 	"uPIMulator/src/core"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
@@ -40,7 +41,12 @@ type Host struct {
 	input_dpu_mram_heap_pointer_name  []*Chunk
 	output_dpu_mram_heap_pointer_name []*Chunk
 
+	communications []*communication.Communication
+
 	channels []*channel.Channel
+
+	verifier     *Verifier
+	stat_factory *misc.StatFactory
 }
 
 func (this *Host) Init(command_line_parser *misc.CommandLineParser) {
@@ -64,6 +70,13 @@ func (this *Host) Init(command_line_parser *misc.CommandLineParser) {
 	this.InitMram()
 	this.InitNumExecutions()
 	this.InitChunks()
+	this.InitCommunications()
+
+	this.verifier = new(Verifier)
+	this.verifier.Init(command_line_parser.StringParameter("verify"), this.bin_dirpath)
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("Host")
 }
 
 func (this *Host) InitAddresses() {
@@ -226,6 +239,14 @@ func (this *Host) ConnectChannels(channels []*channel.Channel) {
 	this.channels = channels
 }
 
+func (this *Host) Verifier() *Verifier {
+	return this.verifier
+}
+
+func (this *Host) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
 func (this *Host) NumExecutions() int {
 	return this.num_executions
 }
@@ -253,18 +274,17 @@ func (this *Host) IsZombie() bool {
 }
 
 func (this *Host) Load() {
-	this.DmaTransferToAtomic()
-	this.DmaTransferToIram()
-	this.DmaTransferToWram()
-	this.DmaTransferToMram()
+	this.LoadDpus(this.Dpus())
 }
 
-func (this *Host) Schedule(execution int) {
-	// TODO(bongjoon.hyun@gmail.com): fix this
-	if this.benchmark == "TRNS" {
-		this.Load()
-	}
+func (this *Host) LoadDpus(dpus []*dpu.Dpu) {
+	this.DmaTransferToAtomic(dpus)
+	this.DmaTransferToIram(dpus)
+	this.DmaTransferToWram(dpus)
+	this.DmaTransferToMram(dpus)
+}
 
+func (this *Host) Schedule(execution int) {
 	this.ChannelTransferInputDpuHost(execution)
 	this.ChannelTransferInputDpuMramHeapPointerName(execution)
 }
@@ -272,6 +292,8 @@ func (this *Host) Schedule(execution int) {
 func (this *Host) Check(execution int) {
 	this.ChannelTransferOutputDpuHost(execution)
 	this.ChannelTransferOutputDpuMramHeapPointerName(execution)
+
+	this.verifier.Conclude(execution)
 }
 
 func (this *Host) Launch() {
@@ -292,9 +314,7 @@ func (this *Host) Launch() {
 	}
 }
 
-func (this *Host) DmaTransferToAtomic() {
-	dpus := this.Dpus()
-
+func (this *Host) DmaTransferToAtomic(dpus []*dpu.Dpu) {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
@@ -308,9 +328,7 @@ func (this *Host) DmaTransferToAtomic() {
 	thread_pool.Start()
 }
 
-func (this *Host) DmaTransferToIram() {
-	dpus := this.Dpus()
-
+func (this *Host) DmaTransferToIram(dpus []*dpu.Dpu) {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
@@ -324,9 +342,7 @@ func (this *Host) DmaTransferToIram() {
 	thread_pool.Start()
 }
 
-func (this *Host) DmaTransferToWram() {
-	dpus := this.Dpus()
-
+func (this *Host) DmaTransferToWram(dpus []*dpu.Dpu) {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
@@ -340,9 +356,7 @@ func (this *Host) DmaTransferToWram() {
 	thread_pool.Start()
 }
 
-func (this *Host) DmaTransferToMram() {
-	dpus := this.Dpus()
-
+func (this *Host) DmaTransferToMram(dpus []*dpu.Dpu) {
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(this.num_simulation_threads)
 
@@ -442,6 +456,7 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 
 				for i := 0; i < 8; i++ {
 					dpu_ids := make([]int, 0)
+					unique_dpu_ids := make([]int, 0)
 					byte_streams := make([]*encoding.ByteStream, 0)
 
 					for _, dpu_ := range dpus {
@@ -452,6 +467,7 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 							chunk := this.FindOutputDpuHostChunk(pointer, execution, unique_dpu_id)
 
 							dpu_ids = append(dpu_ids, dpu_id)
+							unique_dpu_ids = append(unique_dpu_ids, unique_dpu_id)
 							byte_streams = append(byte_streams, chunk.ByteStream())
 						}
 					}
@@ -467,7 +483,16 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 						)
 
 						channel_transfer_read_job := new(ChannelTransferReadJob)
-						channel_transfer_read_job.Init(channel_message, byte_streams, channel_)
+						channel_transfer_read_job.Init(
+							channel_message,
+							execution,
+							unique_dpu_ids,
+							pointer,
+							0,
+							byte_streams,
+							channel_,
+							this.verifier,
+						)
 
 						thread_pool.Enque(channel_transfer_read_job)
 					}
@@ -573,6 +598,7 @@ func (this *Host) ChannelTransferOutputDpuMramHeapPointerName(execution int) {
 
 				for i := 0; i < 8; i++ {
 					dpu_ids := make([]int, 0)
+					unique_dpu_ids := make([]int, 0)
 					byte_streams := make([]*encoding.ByteStream, 0)
 
 					for _, dpu_ := range dpus {
@@ -587,6 +613,7 @@ func (this *Host) ChannelTransferOutputDpuMramHeapPointerName(execution int) {
 							)
 
 							dpu_ids = append(dpu_ids, dpu_id)
+							unique_dpu_ids = append(unique_dpu_ids, unique_dpu_id)
 							byte_streams = append(byte_streams, chunk.ByteStream())
 						}
 					}
@@ -602,16 +629,25 @@ func (this *Host) ChannelTransferOutputDpuMramHeapPointerName(execution int) {
 						)
 
 						channel_transfer_read_job := new(ChannelTransferReadJob)
-						channel_transfer_read_job.Init(channel_message, byte_streams, channel_)
+						channel_transfer_read_job.Init(
+							channel_message,
+							execution,
+							unique_dpu_ids,
+							"DPU_MRAM_HEAP_POINTER_NAME",
+							offset,
+							byte_streams,
+							channel_,
+							this.verifier,
+						)
 
 						thread_pool.Enque(channel_transfer_read_job)
 					}
 				}
 			}
 		}
-
-		thread_pool.Start()
 	}
+
+	thread_pool.Start()
 }
 
 func (this *Host) FindInputDpuHostPointers(execution int) map[string]bool {
@@ -735,4 +771,6 @@ func (this *Host) Cycle() {
 	}
 
 	thread_pool.Start()
+
+	this.stat_factory.Increment("total_cycle", 1)
 }
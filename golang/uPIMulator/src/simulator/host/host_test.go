@@ -0,0 +1,51 @@
+package host
+
+import (
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/linker"
+)
+
+// NOTE: linker/testdata/executable.elf is linker/testdata/executable.S as an ELF executable, whose
+// kernel adds 35 to the 7 of value in WRAM before it stops
+func TestHostRunsElfExecutable(t *testing.T) {
+	elf_filepath, abs_err := filepath.Abs(
+		filepath.Join("..", "..", "linker", "testdata", "executable.elf"),
+	)
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	command_line_parser := newCommandLineParser(t, 2)
+	command_line_parser.Parse([]string{"host.test", "--elf_filepath", elf_filepath})
+
+	elf_loader := new(linker.ElfLoader)
+	elf_loader.Init(command_line_parser)
+	elf_loader.Load()
+
+	host := newLoadedHost(t, command_line_parser)
+	host.Schedule(0)
+	host.Launch()
+
+	for cycle := 0; !host.IsZombie(); cycle++ {
+		if cycle == 10000 {
+			t.Fatalf("the kernel does not stop in %d cycles", cycle)
+		}
+
+		host.Cycle()
+		for _, dpu_ := range host.Dpus() {
+			dpu_.Cycle()
+		}
+	}
+
+	for _, dpu_ := range host.Dpus() {
+		value := new(word.Word)
+		value.Init(32)
+		value.FromByteStream(dpu_.Dma().TransferFromWram(host.addresses["value"], 4))
+
+		if value.Value(word.UNSIGNED) != 42 {
+			t.Errorf("value of DPU %d is %d", dpu_.DpuId(), value.Value(word.UNSIGNED))
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/assembler/spec"
+	"uPIMulator/src/misc"
+)
+
+type VerifyPolicy int
+
+// Explanation of verify policies
+// STRICT: mismatches are reported and the simulation stops once the execution is checked
+// REPORT: mismatches are reported and the simulation continues
+// OFF: outputs are not compared
+const (
+	STRICT VerifyPolicy = iota
+	REPORT
+	OFF
+)
+
+type Mismatch struct {
+	execution int
+	dpu_id    int
+	name      string
+	offset    int64
+	expected  string
+	actual    string
+}
+
+func (this *Mismatch) Stringify() string {
+	return fmt.Sprintf(
+		"execution %d, DPU %d, %s + %d: expected %s, actual %s",
+		this.execution,
+		this.dpu_id,
+		this.name,
+		this.offset,
+		this.expected,
+		this.actual,
+	)
+}
+
+type Verifier struct {
+	mutex sync.Mutex
+
+	verify_policy VerifyPolicy
+
+	// NOTE: element_types[name][offset]
+	element_types map[string]map[int64]string
+
+	mismatches []*Mismatch
+
+	num_elements       map[string]int64
+	num_mismatches     map[string]int64
+	num_dpu_mismatches map[int]int64
+}
+
+func (this *Verifier) Init(verify string, bin_dirpath string) {
+	if verify == "strict" {
+		this.verify_policy = STRICT
+	} else if verify == "report" {
+		this.verify_policy = REPORT
+	} else if verify == "off" {
+		this.verify_policy = OFF
+	} else {
+		err := errors.New("verify policy is not valid")
+		panic(err)
+	}
+
+	this.InitElementTypes(filepath.Join(bin_dirpath, "element_types.txt"))
+
+	this.mismatches = make([]*Mismatch, 0)
+
+	this.num_elements = make(map[string]int64, 0)
+	this.num_mismatches = make(map[string]int64, 0)
+	this.num_dpu_mismatches = make(map[int]int64, 0)
+}
+
+func (this *Verifier) InitElementTypes(path string) {
+	this.element_types = make(map[string]map[int64]string, 0)
+
+	if _, stat_err := os.Stat(path); stat_err != nil {
+		if os.IsNotExist(stat_err) {
+			return
+		}
+
+		panic(stat_err)
+	}
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	for _, line := range file_scanner.ReadLines() {
+		words := strings.Split(line, ": ")
+
+		if len(words) != 2 {
+			err := errors.New("element type line cannot be parsed")
+			panic(err)
+		}
+
+		this.ElementSize(words[1])
+
+		name := words[0]
+		offset := int64(0)
+		if pos := strings.LastIndex(words[0], "+"); pos != -1 {
+			name = words[0][:pos]
+
+			var parse_err error
+			offset, parse_err = strconv.ParseInt(words[0][pos+1:], 10, 64)
+			if parse_err != nil {
+				panic(parse_err)
+			}
+		}
+
+		if _, found := this.element_types[name]; !found {
+			this.element_types[name] = make(map[int64]string, 0)
+		}
+
+		this.element_types[name][offset] = words[1]
+	}
+}
+
+func (this *Verifier) VerifyPolicy() VerifyPolicy {
+	return this.verify_policy
+}
+
+func (this *Verifier) Verify(
+	execution int,
+	dpu_ids []int,
+	name string,
+	offset int64,
+	expected_byte_streams []*encoding.ByteStream,
+	actual_byte_streams []*encoding.ByteStream,
+) {
+	if this.verify_policy == OFF {
+		return
+	}
+
+	if len(dpu_ids) != len(expected_byte_streams) {
+		err := errors.New("DPU IDs' length != expected byte streams' length")
+		panic(err)
+	} else if len(expected_byte_streams) != len(actual_byte_streams) {
+		err := errors.New("expected byte streams' length != actual byte streams' length")
+		panic(err)
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i, dpu_id := range dpu_ids {
+		expected_byte_stream := expected_byte_streams[i]
+		actual_byte_stream := actual_byte_streams[i]
+
+		if expected_byte_stream.Size() != actual_byte_stream.Size() {
+			err := errors.New("expected byte stream's size != actual byte stream's size")
+			panic(err)
+		}
+
+		for begin := int64(0); begin < expected_byte_stream.Size(); {
+			type_offset, element_type := this.ElementType(name, offset+begin)
+
+			end := begin + this.ElementSize(element_type)
+			if end > expected_byte_stream.Size() {
+				end = expected_byte_stream.Size()
+			}
+
+			summary_key := this.SummaryKey(execution, name, type_offset)
+
+			this.num_elements[summary_key]++
+
+			is_different := false
+			for j := begin; j < end; j++ {
+				if expected_byte_stream.Get(int(j)) != actual_byte_stream.Get(int(j)) {
+					is_different = true
+					break
+				}
+			}
+
+			if is_different {
+				mismatch := new(Mismatch)
+				mismatch.execution = execution
+				mismatch.dpu_id = dpu_id
+				mismatch.name = name
+				mismatch.offset = offset + begin
+				mismatch.expected = this.Decode(expected_byte_stream, begin, end, element_type)
+				mismatch.actual = this.Decode(actual_byte_stream, begin, end, element_type)
+
+				this.mismatches = append(this.mismatches, mismatch)
+				this.num_mismatches[summary_key]++
+				this.num_dpu_mismatches[dpu_id]++
+			}
+
+			begin = end
+		}
+	}
+}
+
+func (this *Verifier) Conclude(execution int) {
+	num_mismatches := int64(0)
+	for _, mismatch := range this.mismatches {
+		if mismatch.execution == execution {
+			num_mismatches++
+		}
+	}
+
+	if num_mismatches == 0 {
+		return
+	}
+
+	fmt.Printf("execution (%d) has %d mismatches...\n", execution, num_mismatches)
+
+	if this.verify_policy == STRICT {
+		for _, line := range this.ToLines() {
+			fmt.Println(line)
+		}
+
+		err_msg := fmt.Sprintf("execution (%d) failed verification", execution)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// NOTE: an element type applies from its offset in the symbol up to the next one, and the bytes
+// before the first one are compared as bytes
+func (this *Verifier) ElementType(name string, offset int64) (int64, string) {
+	type_offset := int64(0)
+	element_type := "byte"
+
+	is_found := false
+	for element_type_offset, element_type_ := range this.element_types[name] {
+		if element_type_offset <= offset && (!is_found || element_type_offset > type_offset) {
+			type_offset = element_type_offset
+			element_type = element_type_
+			is_found = true
+		}
+	}
+
+	return type_offset, element_type
+}
+
+// NOTE: bytes are the element type of the symbols without one, which spec arrays do not hold
+func (this *Verifier) ElementSize(element_type string) int64 {
+	if element_type == "byte" {
+		return 1
+	} else {
+		return spec.ElementSize(element_type)
+	}
+}
+
+func (this *Verifier) Decode(
+	byte_stream *encoding.ByteStream,
+	begin int64,
+	end int64,
+	element_type string,
+) string {
+	if end-begin != this.ElementSize(element_type) {
+		words := make([]string, 0)
+		for i := begin; i < end; i++ {
+			words = append(words, fmt.Sprintf("0x%02x", byte_stream.Get(int(i))))
+		}
+		return strings.Join(words, " ")
+	}
+
+	value := uint64(0)
+	for i := end - 1; i >= begin; i-- {
+		value = value<<8 | uint64(byte_stream.Get(int(i)))
+	}
+
+	if element_type == "byte" {
+		return fmt.Sprintf("0x%02x", value)
+	} else if element_type == "int32" {
+		return fmt.Sprintf("%d", int32(uint32(value)))
+	} else if element_type == "uint32" {
+		return fmt.Sprintf("%d", uint32(value))
+	} else if element_type == "float" {
+		return fmt.Sprintf("%g", math.Float32frombits(uint32(value)))
+	} else if element_type == "int64" {
+		return fmt.Sprintf("%d", int64(value))
+	} else if element_type == "uint64" {
+		return fmt.Sprintf("%d", value)
+	} else if element_type == "double" {
+		return fmt.Sprintf("%g", math.Float64frombits(value))
+	} else {
+		err := errors.New("element type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Verifier) SummaryKey(execution int, name string, type_offset int64) string {
+	return fmt.Sprintf("%08d %s %016d", execution, name, type_offset)
+}
+
+func (this *Verifier) NumMismatches() int {
+	return len(this.mismatches)
+}
+
+func (this *Verifier) ToLines() []string {
+	lines := make([]string, 0)
+
+	summary_keys := make([]string, 0)
+	for summary_key, _ := range this.num_elements {
+		summary_keys = append(summary_keys, summary_key)
+	}
+	slices.Sort(summary_keys)
+
+	total_num_elements := int64(0)
+	for _, summary_key := range summary_keys {
+		words := strings.SplitN(summary_key, " ", 3)
+
+		execution, err := strconv.Atoi(words[0])
+		if err != nil {
+			panic(err)
+		}
+
+		type_offset, parse_err := strconv.ParseInt(words[2], 10, 64)
+		if parse_err != nil {
+			panic(parse_err)
+		}
+
+		_, element_type := this.ElementType(words[1], type_offset)
+
+		lines = append(
+			lines,
+			fmt.Sprintf(
+				"execution %d, %s + %d (%s): %d / %d elements mismatched",
+				execution,
+				words[1],
+				type_offset,
+				element_type,
+				this.num_mismatches[summary_key],
+				this.num_elements[summary_key],
+			),
+		)
+
+		total_num_elements += this.num_elements[summary_key]
+	}
+
+	dpu_ids := make([]int, 0)
+	for dpu_id, _ := range this.num_dpu_mismatches {
+		dpu_ids = append(dpu_ids, dpu_id)
+	}
+	slices.Sort(dpu_ids)
+
+	for _, dpu_id := range dpu_ids {
+		lines = append(
+			lines,
+			fmt.Sprintf("DPU %d: %d elements mismatched", dpu_id, this.num_dpu_mismatches[dpu_id]),
+		)
+	}
+
+	lines = append(
+		lines,
+		fmt.Sprintf("total: %d / %d elements mismatched", len(this.mismatches), total_num_elements),
+	)
+
+	mismatches := slices.Clone(this.mismatches)
+	slices.SortStableFunc(mismatches, func(mismatch_1 *Mismatch, mismatch_2 *Mismatch) int {
+		if mismatch_1.execution != mismatch_2.execution {
+			return mismatch_1.execution - mismatch_2.execution
+		} else if mismatch_1.dpu_id != mismatch_2.dpu_id {
+			return mismatch_1.dpu_id - mismatch_2.dpu_id
+		} else if mismatch_1.name != mismatch_2.name {
+			return strings.Compare(mismatch_1.name, mismatch_2.name)
+		} else {
+			return int(mismatch_1.offset - mismatch_2.offset)
+		}
+	})
+
+	for _, mismatch := range mismatches {
+		lines = append(lines, mismatch.Stringify())
+	}
+
+	return lines
+}
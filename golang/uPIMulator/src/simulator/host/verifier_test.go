@@ -0,0 +1,265 @@
+package host
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+)
+
+func newVerifier(t *testing.T, verify string) *Verifier {
+	bin_dirpath := t.TempDir()
+
+	// NOTE: a name without an offset is typed from offset 0, as the PrIM benchmarks give them
+	element_types := strings.Join([]string{
+		"DPU_MRAM_HEAP_POINTER_NAME+16: float",
+		"DPU_MRAM_HEAP_POINTER_NAME+8: int32",
+		"counts+0: uint64",
+		"sums+0: double",
+		"values: int32",
+		"weights+0: float",
+	}, "\n")
+	element_types_path := filepath.Join(bin_dirpath, "element_types.txt")
+	if err := os.WriteFile(element_types_path, []byte(element_types), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := new(Verifier)
+	verifier.Init(verify, bin_dirpath)
+	return verifier
+}
+
+// NOTE: elements are given as their bits and laid out in little endian, as the DPUs store them
+func toElementByteStream(element_size int, elements ...uint64) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, element := range elements {
+		for i := 0; i < element_size; i++ {
+			byte_stream.Append(uint8(element >> (8 * i)))
+		}
+	}
+
+	return byte_stream
+}
+
+func expectLines(t *testing.T, verifier *Verifier, expected_lines ...string) {
+	t.Helper()
+
+	lines := verifier.ToLines()
+	for _, expected_line := range expected_lines {
+		if !slices.Contains(lines, expected_line) {
+			t.Errorf("line (%s) is not reported in %q", expected_line, lines)
+		}
+	}
+}
+
+func concludeMessage(verifier *Verifier, execution int) (message string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			message = fmt.Sprint(recovered)
+		}
+	}()
+
+	verifier.Conclude(execution)
+	return ""
+}
+
+func TestVerifierStrict(t *testing.T) {
+	verifier := newVerifier(t, "strict")
+
+	minus_two := uint64(0xfffffffe)
+	minus_three := uint64(0xfffffffd)
+
+	verifier.Verify(
+		0,
+		[]int{0, 1},
+		"values",
+		8,
+		[]*encoding.ByteStream{
+			toElementByteStream(4, 1, minus_two),
+			toElementByteStream(4, 1, minus_two),
+		},
+		[]*encoding.ByteStream{
+			toElementByteStream(4, 1, minus_three),
+			toElementByteStream(4, 1, minus_two),
+		},
+	)
+
+	verifier.Verify(
+		1,
+		[]int{0},
+		"counts",
+		0,
+		[]*encoding.ByteStream{toElementByteStream(8, math.MaxUint64)},
+		[]*encoding.ByteStream{toElementByteStream(8, math.MaxUint64)},
+	)
+
+	if verifier.NumMismatches() != 1 {
+		t.Fatalf("%d mismatches are found", verifier.NumMismatches())
+	}
+
+	expectLines(
+		t,
+		verifier,
+		"execution 0, values + 0 (int32): 1 / 4 elements mismatched",
+		"execution 1, counts + 0 (uint64): 0 / 1 elements mismatched",
+		"DPU 0: 1 elements mismatched",
+		"total: 1 / 5 elements mismatched",
+		"execution 0, DPU 0, values + 12: expected -2, actual -3",
+	)
+
+	if message := concludeMessage(verifier, 1); message != "" {
+		t.Fatalf("execution 1 without mismatches fails with (%s)", message)
+	}
+
+	if message := concludeMessage(verifier, 0); message != "execution (0) failed verification" {
+		t.Fatalf("execution 0 with a mismatch concludes with (%s)", message)
+	}
+}
+
+func TestVerifierReport(t *testing.T) {
+	verifier := newVerifier(t, "report")
+
+	verifier.Verify(
+		0,
+		[]int{2},
+		"weights",
+		0,
+		[]*encoding.ByteStream{
+			toElementByteStream(4, uint64(math.Float32bits(1.5)), uint64(math.Float32bits(-0.25))),
+		},
+		[]*encoding.ByteStream{
+			toElementByteStream(4, uint64(math.Float32bits(2.25)), uint64(math.Float32bits(-0.25))),
+		},
+	)
+
+	verifier.Verify(
+		0,
+		[]int{2},
+		"sums",
+		16,
+		[]*encoding.ByteStream{toElementByteStream(8, math.Float64bits(1e-3))},
+		[]*encoding.ByteStream{toElementByteStream(8, math.Float64bits(3.5e10))},
+	)
+
+	// NOTE: a symbol without an element type is compared byte by byte
+	verifier.Verify(
+		0,
+		[]int{3},
+		"flags",
+		0,
+		[]*encoding.ByteStream{toElementByteStream(1, 1, 0)},
+		[]*encoding.ByteStream{toElementByteStream(1, 1, 0xff)},
+	)
+
+	// NOTE: a trailing partial element is shown as its raw bytes
+	verifier.Verify(
+		0,
+		[]int{3},
+		"values",
+		0,
+		[]*encoding.ByteStream{toElementByteStream(1, 7, 0, 0, 0, 5, 6)},
+		[]*encoding.ByteStream{toElementByteStream(1, 7, 0, 0, 0, 5, 7)},
+	)
+
+	if verifier.NumMismatches() != 4 {
+		t.Fatalf("%d mismatches are found", verifier.NumMismatches())
+	}
+
+	expectLines(
+		t,
+		verifier,
+		"execution 0, weights + 0 (float): 1 / 2 elements mismatched",
+		"execution 0, sums + 0 (double): 1 / 1 elements mismatched",
+		"execution 0, flags + 0 (byte): 1 / 2 elements mismatched",
+		"execution 0, values + 0 (int32): 1 / 2 elements mismatched",
+		"DPU 2: 2 elements mismatched",
+		"DPU 3: 2 elements mismatched",
+		"total: 4 / 7 elements mismatched",
+		"execution 0, DPU 2, weights + 0: expected 1.5, actual 2.25",
+		"execution 0, DPU 2, sums + 16: expected 0.001, actual 3.5e+10",
+		"execution 0, DPU 3, flags + 1: expected 0x00, actual 0xff",
+		"execution 0, DPU 3, values + 4: expected 0x05 0x06, actual 0x05 0x07",
+	)
+
+	if message := concludeMessage(verifier, 0); message != "" {
+		t.Fatalf("execution 0 fails with (%s) under the report policy", message)
+	}
+}
+
+func TestVerifierHeapOffsets(t *testing.T) {
+	verifier := newVerifier(t, "report")
+
+	// NOTE: the heap is read from offset 4, so that its first 4 bytes precede every element type
+	expected := toElementByteStream(4, 0, 7, 0xfffffffe, uint64(math.Float32bits(0.1)), 0)
+	actual := toElementByteStream(4, 0, 7, 0xfffffffd, uint64(math.Float32bits(0.2)), 0)
+	actual.Set(1, 0xff)
+
+	verifier.Verify(
+		0,
+		[]int{1},
+		"DPU_MRAM_HEAP_POINTER_NAME",
+		4,
+		[]*encoding.ByteStream{expected},
+		[]*encoding.ByteStream{actual},
+	)
+
+	if verifier.NumMismatches() != 3 {
+		t.Fatalf("%d mismatches are found", verifier.NumMismatches())
+	}
+
+	expectLines(
+		t,
+		verifier,
+		"execution 0, DPU_MRAM_HEAP_POINTER_NAME + 0 (byte): 1 / 4 elements mismatched",
+		"execution 0, DPU_MRAM_HEAP_POINTER_NAME + 8 (int32): 1 / 2 elements mismatched",
+		"execution 0, DPU_MRAM_HEAP_POINTER_NAME + 16 (float): 1 / 2 elements mismatched",
+		"execution 0, DPU 1, DPU_MRAM_HEAP_POINTER_NAME + 5: expected 0x00, actual 0xff",
+		"execution 0, DPU 1, DPU_MRAM_HEAP_POINTER_NAME + 12: expected -2, actual -3",
+		"execution 0, DPU 1, DPU_MRAM_HEAP_POINTER_NAME + 16: expected 0.1, actual 0.2",
+	)
+}
+
+func TestVerifierOff(t *testing.T) {
+	verifier := newVerifier(t, "off")
+
+	verifier.Verify(
+		0,
+		[]int{0},
+		"weights",
+		0,
+		[]*encoding.ByteStream{toElementByteStream(4, uint64(math.Float32bits(1.5)))},
+		[]*encoding.ByteStream{toElementByteStream(4, uint64(math.Float32bits(2.25)))},
+	)
+
+	if verifier.NumMismatches() != 0 {
+		t.Fatalf("%d mismatches are found with verification off", verifier.NumMismatches())
+	}
+
+	if message := concludeMessage(verifier, 0); message != "" {
+		t.Fatalf("execution 0 fails with (%s) with verification off", message)
+	}
+}
+
+func TestVerifierRejectsInvalidElementType(t *testing.T) {
+	bin_dirpath := t.TempDir()
+
+	element_types_path := filepath.Join(bin_dirpath, "element_types.txt")
+	if err := os.WriteFile(element_types_path, []byte("values: int16\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("element type int16 is accepted")
+		}
+	}()
+
+	verifier := new(Verifier)
+	verifier.Init("strict", bin_dirpath)
+}
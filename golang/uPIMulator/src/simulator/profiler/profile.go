@@ -0,0 +1,212 @@
+package profiler
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type StallType int
+
+const (
+	DMA_STALL StallType = iota
+	REVOLVER_STALL
+	BACKPRESSURE_STALL
+)
+
+// NOTE: call_sites are the PCs of the calls that the tasklet is in, outermost first and joined by
+// commas, so that the key stays comparable
+type SampleKey struct {
+	pc         int64
+	thread_id  int
+	call_sites string
+}
+
+func (this SampleKey) Pc() int64 {
+	return this.pc
+}
+
+func (this SampleKey) ThreadId() int {
+	return this.thread_id
+}
+
+func (this SampleKey) CallSites() []int64 {
+	call_sites := make([]int64, 0)
+
+	if this.call_sites == "" {
+		return call_sites
+	}
+
+	for _, word := range strings.Split(this.call_sites, ",") {
+		call_site, err := strconv.ParseInt(word, 10, 64)
+		if err != nil {
+			panic(err)
+		}
+
+		call_sites = append(call_sites, call_site)
+	}
+
+	return call_sites
+}
+
+type Sample struct {
+	num_instructions    int64
+	dma_cycles          int64
+	revolver_cycles     int64
+	backpressure_cycles int64
+	dma_bytes           int64
+}
+
+func (this *Sample) NumInstructions() int64 {
+	return this.num_instructions
+}
+
+func (this *Sample) DmaCycles() int64 {
+	return this.dma_cycles
+}
+
+func (this *Sample) RevolverCycles() int64 {
+	return this.revolver_cycles
+}
+
+func (this *Sample) BackpressureCycles() int64 {
+	return this.backpressure_cycles
+}
+
+func (this *Sample) DmaBytes() int64 {
+	return this.dma_bytes
+}
+
+// NOTE: an issued instruction occupies its tasklet for one cycle, so the cycles of a sample are the
+// issued instructions and every cycle that the tasklet has stalled at the PC
+func (this *Sample) Cycles() int64 {
+	return this.num_instructions + this.dma_cycles + this.revolver_cycles + this.backpressure_cycles
+}
+
+func (this *Sample) Merge(sample *Sample) {
+	this.num_instructions += sample.num_instructions
+	this.dma_cycles += sample.dma_cycles
+	this.revolver_cycles += sample.revolver_cycles
+	this.backpressure_cycles += sample.backpressure_cycles
+	this.dma_bytes += sample.dma_bytes
+}
+
+type Profile struct {
+	samples map[SampleKey]*Sample
+
+	// NOTE: call_stacks[thread_id] is the return-address chain of the tasklet kept as the PCs of
+	// its calls, and call_site_keys[thread_id] is the same chain encoded as in a sample key
+	call_stacks    map[int][]int64
+	call_site_keys map[int]string
+}
+
+func (this *Profile) Init() {
+	this.samples = make(map[SampleKey]*Sample, 0)
+
+	this.call_stacks = make(map[int][]int64, 0)
+	this.call_site_keys = make(map[int]string, 0)
+}
+
+func (this *Profile) Sample(pc int64, thread_id int) *Sample {
+	key := SampleKey{pc: pc, thread_id: thread_id, call_sites: this.call_site_keys[thread_id]}
+
+	sample, found := this.samples[key]
+	if !found {
+		sample = new(Sample)
+		this.samples[key] = sample
+	}
+
+	return sample
+}
+
+func (this *Profile) Issue(pc int64, thread_id int) {
+	this.Sample(pc, thread_id).num_instructions++
+}
+
+func (this *Profile) Stall(pc int64, thread_id int, stall_type StallType) {
+	sample := this.Sample(pc, thread_id)
+
+	if stall_type == DMA_STALL {
+		sample.dma_cycles++
+	} else if stall_type == REVOLVER_STALL {
+		sample.revolver_cycles++
+	} else if stall_type == BACKPRESSURE_STALL {
+		sample.backpressure_cycles++
+	} else {
+		err := errors.New("stall type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Profile) Transfer(pc int64, thread_id int, size int64) {
+	this.Sample(pc, thread_id).dma_bytes += size
+}
+
+// NOTE: a booted tasklet starts at the entry of the kernel outside of any call
+func (this *Profile) Boot(thread_id int) {
+	this.SetCallStack(thread_id, make([]int64, 0))
+}
+
+// NOTE: a call pushes its PC on the return-address chain of the tasklet
+func (this *Profile) Call(thread_id int, pc int64) {
+	call_stack := append(this.call_stacks[thread_id], pc)
+	this.SetCallStack(thread_id, call_stack)
+}
+
+// NOTE: a jump to the return address of a call in the chain returns from that call and from every
+// call made after it, which other jumps leave as is
+func (this *Profile) Jump(thread_id int, target int64, iram_data_size int64) {
+	call_stack := this.call_stacks[thread_id]
+
+	for i := len(call_stack) - 1; i >= 0; i-- {
+		if call_stack[i]+iram_data_size == target {
+			this.SetCallStack(thread_id, call_stack[:i])
+			return
+		}
+	}
+}
+
+func (this *Profile) CallStack(thread_id int) []int64 {
+	return this.call_stacks[thread_id]
+}
+
+func (this *Profile) SetCallStack(thread_id int, call_stack []int64) {
+	words := make([]string, 0)
+	for _, call_site := range call_stack {
+		words = append(words, strconv.FormatInt(call_site, 10))
+	}
+
+	this.call_stacks[thread_id] = call_stack
+	this.call_site_keys[thread_id] = strings.Join(words, ",")
+}
+
+func (this *Profile) Merge(profile *Profile) {
+	for key, sample := range profile.samples {
+		if _, found := this.samples[key]; !found {
+			this.samples[key] = new(Sample)
+		}
+
+		this.samples[key].Merge(sample)
+	}
+}
+
+func (this *Profile) Keys() []SampleKey {
+	keys := make([]SampleKey, 0)
+	for key, _ := range this.samples {
+		keys = append(keys, key)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if keys[i].pc != keys[j].pc {
+			return keys[i].pc < keys[j].pc
+		} else if keys[i].thread_id != keys[j].thread_id {
+			return keys[i].thread_id < keys[j].thread_id
+		}
+		return keys[i].call_sites < keys[j].call_sites
+	}
+
+	sort.Slice(keys, sort_fn)
+
+	return keys
+}
@@ -0,0 +1,428 @@
+package profiler
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/misc"
+)
+
+type Profiler struct {
+	bin_dirpath string
+
+	profile *Profile
+
+	symbol_table      *kernel.SymbolTable
+	source_line_table *kernel.SourceLineTable
+}
+
+func (this *Profiler) Init(command_line_parser *misc.CommandLineParser) {
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
+
+	this.profile = new(Profile)
+	this.profile.Init()
+
+	this.InitFunctions()
+	this.InitSourceLines()
+}
+
+func (this *Profiler) InitFunctions() {
+	this.symbol_table = new(kernel.SymbolTable)
+	this.symbol_table.Init()
+	this.symbol_table.Load(filepath.Join(this.bin_dirpath, "addresses.txt"))
+}
+
+func (this *Profiler) InitSourceLines() {
+	this.source_line_table = new(kernel.SourceLineTable)
+	this.source_line_table.Init()
+
+	path := filepath.Join(this.bin_dirpath, "source_lines.txt")
+	if _, err := os.Stat(path); err == nil {
+		this.source_line_table.Load(path)
+	}
+}
+
+func (this *Profiler) Merge(profile *Profile) {
+	this.profile.Merge(profile)
+}
+
+func (this *Profiler) Function(pc int64) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_begin := config_loader.IramOffset()
+	iram_end := config_loader.IramOffset() + config_loader.IramSize()
+
+	if function := this.symbol_table.Symbol(pc, iram_begin, iram_end); function != "" {
+		return function
+	} else {
+		return "??"
+	}
+}
+
+func (this *Profiler) Symbolize(pc int64) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_begin := config_loader.IramOffset()
+	iram_end := config_loader.IramOffset() + config_loader.IramSize()
+
+	if symbol := this.symbol_table.Symbolize(pc, iram_begin, iram_end); symbol != "" {
+		return symbol
+	} else {
+		return fmt.Sprintf("%d", pc)
+	}
+}
+
+// NOTE: columns are dropped so that the instructions of a source line are reported together
+func (this *Profiler) SourceLine(pc int64) string {
+	if source_line := this.source_line_table.SourceLine(pc); source_line != nil {
+		return fmt.Sprintf("%s:%d", source_line.Path(), source_line.Line())
+	} else {
+		return ""
+	}
+}
+
+func (this *Profiler) Aggregate(key_fn func(key SampleKey) string) map[string]*Sample {
+	samples := make(map[string]*Sample, 0)
+
+	for _, key := range this.profile.Keys() {
+		name := key_fn(key)
+
+		if _, found := samples[name]; !found {
+			samples[name] = new(Sample)
+		}
+
+		samples[name].Merge(this.profile.samples[key])
+	}
+
+	return samples
+}
+
+func (this *Profiler) Sort(samples map[string]*Sample) []string {
+	names := make([]string, 0)
+	for name, _ := range samples {
+		names = append(names, name)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if samples[names[i]].Cycles() != samples[names[j]].Cycles() {
+			return samples[names[i]].Cycles() > samples[names[j]].Cycles()
+		}
+		return names[i] < names[j]
+	}
+
+	sort.Slice(names, sort_fn)
+
+	return names
+}
+
+func (this *Profiler) Table(title string, samples map[string]*Sample) []string {
+	total_cycles := int64(0)
+	for _, sample := range samples {
+		total_cycles += sample.Cycles()
+	}
+
+	lines := []string{
+		title,
+		"",
+		fmt.Sprintf(
+			"%14s %8s %14s %14s %14s %14s %14s  %s",
+			"cycles",
+			"cycles%",
+			"instructions",
+			"dma_stall",
+			"revolver_stall",
+			"backpressure",
+			"dma_bytes",
+			"name",
+		),
+	}
+
+	for _, name := range this.Sort(samples) {
+		sample := samples[name]
+
+		percentage := float64(0)
+		if total_cycles > 0 {
+			percentage = 100 * float64(sample.Cycles()) / float64(total_cycles)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%14d %7.2f%% %14d %14d %14d %14d %14d  %s",
+			sample.Cycles(),
+			percentage,
+			sample.NumInstructions(),
+			sample.DmaCycles(),
+			sample.RevolverCycles(),
+			sample.BackpressureCycles(),
+			sample.DmaBytes(),
+			name,
+		))
+	}
+
+	return append(lines, "")
+}
+
+func (this *Profiler) Lines() []string {
+	lines := make([]string, 0)
+
+	lines = append(lines, this.Table("Functions", this.Aggregate(func(key SampleKey) string {
+		return this.Function(key.Pc())
+	}))...)
+
+	if len(this.source_line_table.Addresses()) > 0 {
+		lines = append(lines, this.Table("Source lines", this.Aggregate(func(key SampleKey) string {
+			if source_line := this.SourceLine(key.Pc()); source_line != "" {
+				return source_line
+			} else {
+				return "??"
+			}
+		}))...)
+	}
+
+	lines = append(lines, this.Table("PCs", this.Aggregate(func(key SampleKey) string {
+		if source_line := this.SourceLine(key.Pc()); source_line != "" {
+			return fmt.Sprintf("%d <%s> %s", key.Pc(), this.Symbolize(key.Pc()), source_line)
+		} else {
+			return fmt.Sprintf("%d <%s>", key.Pc(), this.Symbolize(key.Pc()))
+		}
+	}))...)
+
+	lines = append(lines, this.Table("Tasklets", this.Aggregate(func(key SampleKey) string {
+		return fmt.Sprintf("tasklet_%d", key.ThreadId())
+	}))...)
+
+	return lines
+}
+
+// NOTE: each stack is rooted at the tasklet, goes through the functions of its return-address
+// chain and ends with what the tasklet has spent its cycles on, which flamegraph.pl and speedscope
+// read as is
+func (this *Profiler) FoldedLines() []string {
+	folded_stacks := make(map[string]int64, 0)
+
+	for _, key := range this.profile.Keys() {
+		sample := this.profile.samples[key]
+
+		frames := []string{fmt.Sprintf("tasklet_%d", key.ThreadId())}
+		for _, call_site := range key.CallSites() {
+			frames = append(frames, this.Function(call_site))
+		}
+		frames = append(frames, this.Function(key.Pc()))
+
+		if source_line := this.SourceLine(key.Pc()); source_line != "" {
+			frames = append(frames, source_line)
+		} else {
+			frames = append(frames, this.Symbolize(key.Pc()))
+		}
+
+		stack := strings.Join(frames, ";")
+
+		folded_stacks[stack+";issue"] += sample.NumInstructions()
+		folded_stacks[stack+";dma_stall"] += sample.DmaCycles()
+		folded_stacks[stack+";revolver_stall"] += sample.RevolverCycles()
+		folded_stacks[stack+";backpressure"] += sample.BackpressureCycles()
+	}
+
+	stacks := make([]string, 0)
+	for stack, cycles := range folded_stacks {
+		if cycles > 0 {
+			stacks = append(stacks, stack)
+		}
+	}
+
+	sort.Strings(stacks)
+
+	lines := make([]string, 0)
+	for _, stack := range stacks {
+		lines = append(lines, fmt.Sprintf("%s %d", stack, folded_stacks[stack]))
+	}
+	return lines
+}
+
+// NOTE: encodes the profile.proto of pprof with one location per PC and one sample per PC, call
+// stack and tasklet, so that go tool pprof can break cycles down by function, caller, source line
+// and tasklet
+func (this *Profiler) Pprof() []byte {
+	strings_ := []string{""}
+	string_indices := map[string]int64{"": 0}
+	string_index := func(value string) int64 {
+		if index, found := string_indices[value]; found {
+			return index
+		}
+
+		string_indices[value] = int64(len(strings_))
+		strings_ = append(strings_, value)
+		return string_indices[value]
+	}
+
+	profile := new(ProtoBuffer)
+	profile.Init()
+
+	sample_types := [][]string{
+		{"cycles", "cycles"},
+		{"instructions", "count"},
+		{"dma_stall", "cycles"},
+		{"revolver_stall", "cycles"},
+		{"backpressure", "cycles"},
+		{"dma_bytes", "bytes"},
+	}
+
+	for _, sample_type := range sample_types {
+		value_type := new(ProtoBuffer)
+		value_type.Init()
+		value_type.EncodeInt64(1, string_index(sample_type[0]))
+		value_type.EncodeInt64(2, string_index(sample_type[1]))
+
+		profile.EncodeMessage(1, value_type)
+	}
+
+	location_ids := make(map[int64]uint64, 0)
+	function_ids := make(map[string]uint64, 0)
+
+	location_id := func(pc int64) uint64 {
+		if location_id_, found := location_ids[pc]; found {
+			return location_id_
+		}
+
+		location_ids[pc] = uint64(len(location_ids) + 1)
+
+		source_line := this.source_line_table.SourceLine(pc)
+
+		function_name := this.Function(pc)
+		filename := ""
+		line_number := int64(0)
+		if source_line != nil {
+			filename = source_line.Path()
+			line_number = source_line.Line()
+		}
+
+		function_key := function_name + "\x00" + filename
+		if _, found := function_ids[function_key]; !found {
+			function_ids[function_key] = uint64(len(function_ids) + 1)
+
+			function := new(ProtoBuffer)
+			function.Init()
+			function.EncodeUint64(1, function_ids[function_key])
+			function.EncodeInt64(2, string_index(function_name))
+			function.EncodeInt64(3, string_index(function_name))
+			function.EncodeInt64(4, string_index(filename))
+
+			profile.EncodeMessage(5, function)
+		}
+
+		line := new(ProtoBuffer)
+		line.Init()
+		line.EncodeUint64(1, function_ids[function_key])
+		line.EncodeInt64(2, line_number)
+
+		location := new(ProtoBuffer)
+		location.Init()
+		location.EncodeUint64(1, location_ids[pc])
+		location.EncodeUint64(2, 1)
+		location.EncodeUint64(3, uint64(pc))
+		location.EncodeMessage(4, line)
+
+		profile.EncodeMessage(4, location)
+
+		return location_ids[pc]
+	}
+
+	for _, key := range this.profile.Keys() {
+		// NOTE: pprof lists the locations of a sample from the leaf to the root
+		sample_location_ids := []int64{int64(location_id(key.Pc()))}
+
+		call_sites := key.CallSites()
+		for i := len(call_sites) - 1; i >= 0; i-- {
+			sample_location_ids = append(sample_location_ids, int64(location_id(call_sites[i])))
+		}
+
+		sample := this.profile.samples[key]
+
+		label := new(ProtoBuffer)
+		label.Init()
+		label.EncodeInt64(1, string_index("tasklet"))
+		label.EncodeInt64(2, string_index(fmt.Sprintf("%d", key.ThreadId())))
+
+		sample_ := new(ProtoBuffer)
+		sample_.Init()
+		sample_.EncodePackedInt64s(1, sample_location_ids)
+		sample_.EncodePackedInt64s(2, []int64{
+			sample.Cycles(),
+			sample.NumInstructions(),
+			sample.DmaCycles(),
+			sample.RevolverCycles(),
+			sample.BackpressureCycles(),
+			sample.DmaBytes(),
+		})
+		sample_.EncodeMessage(3, label)
+
+		profile.EncodeMessage(2, sample_)
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	mapping := new(ProtoBuffer)
+	mapping.Init()
+	mapping.EncodeUint64(1, 1)
+	mapping.EncodeUint64(2, uint64(config_loader.IramOffset()))
+	mapping.EncodeUint64(3, uint64(config_loader.IramOffset()+config_loader.IramSize()))
+	mapping.EncodeInt64(5, string_index("iram.bin"))
+	mapping.EncodeBool(7, true)
+	mapping.EncodeBool(8, len(this.source_line_table.Addresses()) > 0)
+	mapping.EncodeBool(9, len(this.source_line_table.Addresses()) > 0)
+
+	profile.EncodeMessage(3, mapping)
+
+	period_type := new(ProtoBuffer)
+	period_type.Init()
+	period_type.EncodeInt64(1, string_index("cycles"))
+	period_type.EncodeInt64(2, string_index("cycles"))
+
+	profile.EncodeMessage(11, period_type)
+	profile.EncodeInt64(12, 1)
+	profile.EncodeInt64(14, string_index("cycles"))
+
+	for _, value := range strings_ {
+		profile.EncodeString(6, value)
+	}
+
+	return profile.Bytes()
+}
+
+func (this *Profiler) Dump() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(this.bin_dirpath, "profile.txt"))
+	file_dumper.WriteLines(this.Lines())
+
+	folded_file_dumper := new(misc.FileDumper)
+	folded_file_dumper.Init(filepath.Join(this.bin_dirpath, "profile.folded"))
+	folded_file_dumper.WriteLines(this.FoldedLines())
+
+	this.DumpPprof(filepath.Join(this.bin_dirpath, "profile.pb.gz"))
+}
+
+func (this *Profiler) DumpPprof(path string) {
+	file, create_err := os.Create(path)
+
+	if create_err != nil {
+		panic(create_err)
+	}
+
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+
+	if _, write_err := writer.Write(this.Pprof()); write_err != nil {
+		panic(write_err)
+	}
+
+	if close_err := writer.Close(); close_err != nil {
+		panic(close_err)
+	}
+}
@@ -0,0 +1,286 @@
+package profiler
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"uPIMulator/src/linker"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: the profiler runs on testdata/profile.S, where __bootstrap calls main, which calls leaf,
+// and returns the addresses of their functions along with the size of an instruction
+func newProfiler(t *testing.T) (*Profiler, map[string]int64, int64) {
+	root_dirpath, abs_err := filepath.Abs(filepath.Join("..", "..", ".."))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	asm_filepath, abs_err := filepath.Abs(filepath.Join("testdata", "profile.S"))
+	if abs_err != nil {
+		t.Fatal(abs_err)
+	}
+
+	bin_dirpath := t.TempDir()
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "root_dirpath", root_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "bin_dirpath", bin_dirpath, "")
+	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "")
+	command_line_parser.AddOption(misc.INT, "num_simulation_threads", "4", "")
+	command_line_parser.AddOption(misc.INT, "num_tasklets", "2", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	command_line_parser.AddOption(misc.STRING, "asm_filepaths", asm_filepath, "")
+	command_line_parser.AddOption(misc.STRING, "sdk_objects", "", "")
+	command_line_parser.AddOption(misc.STRING, "linker_script_filepath", "", "")
+	command_line_parser.AddOption(misc.STRING, "cache_dirpath", t.TempDir(), "")
+	command_line_parser.AddOption(misc.BOOL, "gc_sections", "false", "")
+
+	linker_ := new(linker.Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	profiler := new(Profiler)
+	profiler.Init(command_line_parser)
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return profiler, linker_.Executable().Addresses(), int64(config_loader.IramDataWidth() / 8)
+}
+
+// NOTE: tasklet 0 runs through the calls of the kernel, one instruction per cycle except for a DMA
+// stall in leaf, while tasklet 1 waits for the revolver scheduler at the entry of main
+func newProfile(addresses map[string]int64, iram_data_size int64) *Profile {
+	bootstrap := addresses["__bootstrap"]
+	main := addresses["main"]
+	leaf := addresses["leaf"]
+
+	profile := new(Profile)
+	profile.Init()
+
+	profile.Boot(0)
+	profile.Issue(bootstrap, 0)
+	profile.Call(0, bootstrap)
+	profile.Issue(main, 0)
+	profile.Issue(main+iram_data_size, 0)
+	profile.Call(0, main+iram_data_size)
+	profile.Issue(leaf, 0)
+	profile.Stall(leaf, 0, DMA_STALL)
+	profile.Transfer(leaf, 0, 64)
+	profile.Issue(leaf, 0)
+	profile.Issue(leaf+iram_data_size, 0)
+	profile.Jump(0, main+2*iram_data_size, iram_data_size)
+	profile.Issue(main+2*iram_data_size, 0)
+	profile.Jump(0, bootstrap+iram_data_size, iram_data_size)
+	profile.Issue(bootstrap+iram_data_size, 0)
+
+	profile.Boot(1)
+	profile.Call(1, bootstrap)
+	profile.Stall(main, 1, REVOLVER_STALL)
+	profile.Stall(main, 1, REVOLVER_STALL)
+	profile.Issue(main, 1)
+
+	return profile
+}
+
+func TestProfileCallStack(t *testing.T) {
+	profile := new(Profile)
+	profile.Init()
+
+	profile.Boot(0)
+	profile.Call(0, 100)
+	profile.Call(0, 200)
+	profile.Call(0, 300)
+
+	// NOTE: a jump that is not to a return address, e.g. a loop, stays in the call
+	profile.Jump(0, 500, 12)
+	if call_stack := profile.CallStack(0); !slices.Equal(call_stack, []int64{100, 200, 300}) {
+		t.Fatalf("call stack is %v after a jump", call_stack)
+	}
+
+	// NOTE: landing on the return address of 200 also unwinds 300, as a longjmp would
+	profile.Jump(0, 212, 12)
+	if call_stack := profile.CallStack(0); !slices.Equal(call_stack, []int64{100}) {
+		t.Fatalf("call stack is %v after a return", call_stack)
+	}
+
+	profile.Issue(400, 0)
+	profile.Boot(0)
+	profile.Issue(400, 0)
+
+	keys := profile.Keys()
+	if len(keys) != 2 || len(keys[0].CallSites()) != 0 ||
+		!slices.Equal(keys[1].CallSites(), []int64{100}) {
+		t.Fatalf("samples are keyed by %v", keys)
+	}
+
+	merged_profile := new(Profile)
+	merged_profile.Init()
+	merged_profile.Merge(profile)
+	merged_profile.Merge(profile)
+
+	for _, key := range keys {
+		if num_instructions := merged_profile.samples[key].NumInstructions(); num_instructions != 2 {
+			t.Errorf("sample %v has %d instructions after merging", key, num_instructions)
+		}
+	}
+}
+
+func TestProfilerAggregateTable(t *testing.T) {
+	profiler, addresses, iram_data_size := newProfiler(t)
+	profiler.Merge(newProfile(addresses, iram_data_size))
+
+	functions := profiler.Aggregate(func(key SampleKey) string {
+		return profiler.Function(key.Pc())
+	})
+
+	cycles := map[string]int64{"__bootstrap": 2, "main": 6, "leaf": 4}
+	for function, expected := range cycles {
+		if sample, found := functions[function]; !found || sample.Cycles() != expected {
+			t.Errorf("function %s is not aggregated to %d cycles", function, expected)
+		}
+	}
+
+	if dma_bytes := functions["leaf"].DmaBytes(); dma_bytes != 64 {
+		t.Errorf("leaf transfers %d bytes", dma_bytes)
+	}
+
+	lines := profiler.Table("Functions", functions)
+	if len(lines) != 7 || lines[0] != "Functions" {
+		t.Fatalf("table is %q", lines)
+	}
+
+	// NOTE: rows are sorted by cycles, out of the 12 cycles of the profile
+	rows := []string{"  50.00%", "  33.33%", "  16.67%"}
+	names := []string{"  main", "  leaf", "  __bootstrap"}
+	for i := range rows {
+		if line := lines[3+i]; !strings.Contains(line, rows[i]) || !strings.HasSuffix(line, names[i]) {
+			t.Errorf("row %d is (%s)", i, line)
+		}
+	}
+
+	if lines := profiler.Lines(); !slices.Contains(lines, "Source lines") ||
+		!slices.Contains(lines, "Tasklets") {
+		t.Errorf("report is %q", lines)
+	}
+}
+
+func TestProfilerFoldedLines(t *testing.T) {
+	profiler, addresses, iram_data_size := newProfiler(t)
+	profiler.Merge(newProfile(addresses, iram_data_size))
+
+	expected := []string{
+		"tasklet_0;__bootstrap;/src/task.c:20;issue 2",
+		"tasklet_0;__bootstrap;main;/src/task.c:10;issue 3",
+		"tasklet_0;__bootstrap;main;leaf;/src/task.c:5;dma_stall 1",
+		"tasklet_0;__bootstrap;main;leaf;/src/task.c:5;issue 3",
+		"tasklet_1;__bootstrap;main;/src/task.c:10;issue 1",
+		"tasklet_1;__bootstrap;main;/src/task.c:10;revolver_stall 2",
+	}
+
+	if lines := profiler.FoldedLines(); !slices.Equal(lines, expected) {
+		t.Errorf("folded stacks are %q", lines)
+	}
+}
+
+type field struct {
+	number int
+	value  uint64
+	bytes  []byte
+}
+
+// NOTE: decodes the varint and length-delimited fields that the profile encoder writes
+func decodeFields(t *testing.T, bytes []byte) []field {
+	fields := make([]field, 0)
+
+	for len(bytes) > 0 {
+		tag, n := binary.Uvarint(bytes)
+		bytes = bytes[n:]
+
+		value, n := binary.Uvarint(bytes)
+		bytes = bytes[n:]
+
+		field_ := field{number: int(tag >> 3), value: value}
+		if WireType(tag&7) == BYTES {
+			field_.bytes = bytes[:value]
+			bytes = bytes[value:]
+		} else if WireType(tag&7) != VARINT {
+			t.Fatalf("wire type %d is not encoded by the profiler", tag&7)
+		}
+
+		fields = append(fields, field_)
+	}
+
+	return fields
+}
+
+func TestProfilerPprof(t *testing.T) {
+	profiler, addresses, iram_data_size := newProfiler(t)
+	profiler.Merge(newProfile(addresses, iram_data_size))
+
+	location_addresses := make(map[uint64]int64, 0)
+	location_stacks := make([][]int64, 0)
+	num_samples := 0
+
+	for _, field_ := range decodeFields(t, profiler.Pprof()) {
+		if field_.number == 4 {
+			location_id := uint64(0)
+			address := int64(0)
+			for _, location_field := range decodeFields(t, field_.bytes) {
+				if location_field.number == 1 {
+					location_id = location_field.value
+				} else if location_field.number == 3 {
+					address = int64(location_field.value)
+				}
+			}
+
+			location_addresses[location_id] = address
+		} else if field_.number == 2 {
+			num_samples++
+
+			for _, sample_field := range decodeFields(t, field_.bytes) {
+				if sample_field.number == 1 {
+					location_ids := make([]int64, 0)
+					for packed := sample_field.bytes; len(packed) > 0; {
+						location_id, n := binary.Uvarint(packed)
+						packed = packed[n:]
+						location_ids = append(location_ids, int64(location_id))
+					}
+
+					location_stacks = append(location_stacks, location_ids)
+				}
+			}
+		}
+	}
+
+	if num_samples != 8 {
+		t.Fatalf("%d samples are encoded", num_samples)
+	}
+
+	// NOTE: the samples of leaf list leaf, then the call in main, then the call in __bootstrap
+	leaf_stack := []int64{
+		addresses["leaf"],
+		addresses["main"] + iram_data_size,
+		addresses["__bootstrap"],
+	}
+
+	num_leaf_stacks := 0
+	for _, location_stack := range location_stacks {
+		stack := make([]int64, 0)
+		for _, location_id := range location_stack {
+			stack = append(stack, location_addresses[uint64(location_id)])
+		}
+
+		if slices.Equal(stack, leaf_stack) {
+			num_leaf_stacks++
+		}
+	}
+
+	if num_leaf_stacks != 1 {
+		t.Errorf("%d samples are at leaf under main and __bootstrap", num_leaf_stacks)
+	}
+}
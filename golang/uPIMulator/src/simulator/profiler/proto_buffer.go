@@ -0,0 +1,70 @@
+package profiler
+
+import (
+	"encoding/binary"
+)
+
+type WireType int
+
+const (
+	VARINT WireType = 0
+	BYTES  WireType = 2
+)
+
+// NOTE: a minimal protocol buffer encoder that covers what the pprof profile.proto needs (i.e.,
+// varints, packed varints, strings and nested messages)
+type ProtoBuffer struct {
+	bytes []byte
+}
+
+func (this *ProtoBuffer) Init() {
+	this.bytes = make([]byte, 0)
+}
+
+func (this *ProtoBuffer) Bytes() []byte {
+	return this.bytes
+}
+
+func (this *ProtoBuffer) EncodeTag(field int, wire_type WireType) {
+	this.bytes = binary.AppendUvarint(this.bytes, uint64(field)<<3|uint64(wire_type))
+}
+
+func (this *ProtoBuffer) EncodeUint64(field int, value uint64) {
+	this.EncodeTag(field, VARINT)
+	this.bytes = binary.AppendUvarint(this.bytes, value)
+}
+
+func (this *ProtoBuffer) EncodeInt64(field int, value int64) {
+	this.EncodeUint64(field, uint64(value))
+}
+
+func (this *ProtoBuffer) EncodeBool(field int, value bool) {
+	if value {
+		this.EncodeUint64(field, 1)
+	} else {
+		this.EncodeUint64(field, 0)
+	}
+}
+
+func (this *ProtoBuffer) EncodeBytes(field int, bytes []byte) {
+	this.EncodeTag(field, BYTES)
+	this.bytes = binary.AppendUvarint(this.bytes, uint64(len(bytes)))
+	this.bytes = append(this.bytes, bytes...)
+}
+
+func (this *ProtoBuffer) EncodeString(field int, value string) {
+	this.EncodeBytes(field, []byte(value))
+}
+
+func (this *ProtoBuffer) EncodePackedInt64s(field int, values []int64) {
+	packed := make([]byte, 0)
+	for _, value := range values {
+		packed = binary.AppendUvarint(packed, uint64(value))
+	}
+
+	this.EncodeBytes(field, packed)
+}
+
+func (this *ProtoBuffer) EncodeMessage(field int, message *ProtoBuffer) {
+	this.EncodeBytes(field, message.Bytes())
+}
@@ -6,7 +6,9 @@ import (
 	"uPIMulator/src/core"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
+	"uPIMulator/src/simulator/dpu/logic"
 	"uPIMulator/src/simulator/host"
+	"uPIMulator/src/simulator/profiler"
 )
 
 type Simulator struct {
@@ -18,6 +20,8 @@ type Simulator struct {
 	execution              int
 
 	verbose int
+
+	profiler *profiler.Profiler
 }
 
 func (this *Simulator) Init(command_line_parser *misc.CommandLineParser) {
@@ -26,6 +30,13 @@ func (this *Simulator) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.verbose = int(command_line_parser.IntParameter("verbose"))
 
+	if command_line_parser.BoolParameter("profile") {
+		this.profiler = new(profiler.Profiler)
+		this.profiler.Init(command_line_parser)
+	} else {
+		this.profiler = nil
+	}
+
 	num_channels := int(command_line_parser.IntParameter("num_channels"))
 	this.channels = make([]*channel.Channel, 0)
 	for i := 0; i < num_channels; i++ {
@@ -77,7 +88,9 @@ func (this *Simulator) Cycle() {
 	if this.host.IsZombie() {
 		fmt.Printf("execution (%d) is finished...\n", this.execution)
 
+		this.host.Communicate(this.execution)
 		this.host.Check(this.execution)
+		this.host.Reload(this.execution)
 		this.execution++
 
 		if !this.IsFinished() {
@@ -97,6 +110,11 @@ func (this *Simulator) Dump() {
 
 	lines := make([]string, 0)
 
+	lines = append(lines, this.host.StatFactory().ToLines()...)
+	for _, channel_ := range this.channels {
+		lines = append(lines, channel_.StatFactory().ToLines()...)
+	}
+
 	dpus := this.host.Dpus()
 	for _, dpu_ := range dpus {
 		lines = append(lines, dpu_.StatFactory().ToLines()...)
@@ -109,4 +127,51 @@ func (this *Simulator) Dump() {
 	}
 
 	file_dumper.WriteLines(lines)
+
+	if this.profiler != nil {
+		for _, dpu_ := range dpus {
+			this.profiler.Merge(dpu_.Logic().Profile())
+		}
+
+		fmt.Printf("Dumping the profile to %s...\n", filepath.Join(this.bin_dirpath, "profile.txt"))
+		this.profiler.Dump()
+	}
+
+	if len(dpus) > 0 && dpus[0].MemoryChecker().MemoryCheckPolicy() == logic.MEMORY_CHECK_REPORT {
+		memory_check_lines := make([]string, 0)
+		num_violations := 0
+		for _, dpu_ := range dpus {
+			memory_check_lines = append(memory_check_lines, dpu_.MemoryChecker().ToLines()...)
+			num_violations += dpu_.MemoryChecker().NumViolations()
+		}
+
+		memory_check_file_dumper := new(misc.FileDumper)
+		memory_check_file_dumper.Init(filepath.Join(this.bin_dirpath, "memory_check.txt"))
+		memory_check_file_dumper.WriteLines(memory_check_lines)
+
+		fmt.Printf("memory check: %d violations\n", num_violations)
+	}
+
+	if len(dpus) > 0 && dpus[0].RaceDetector().IsEnabled() {
+		race_lines := make([]string, 0)
+		num_races := 0
+		for _, dpu_ := range dpus {
+			race_lines = append(race_lines, dpu_.RaceDetector().ToLines()...)
+			num_races += dpu_.RaceDetector().NumRaces()
+		}
+
+		race_file_dumper := new(misc.FileDumper)
+		race_file_dumper.Init(filepath.Join(this.bin_dirpath, "races.txt"))
+		race_file_dumper.WriteLines(race_lines)
+
+		fmt.Printf("race detection: %d races\n", num_races)
+	}
+
+	if this.host.Verifier().VerifyPolicy() != host.OFF {
+		verification_file_dumper := new(misc.FileDumper)
+		verification_file_dumper.Init(filepath.Join(this.bin_dirpath, "verification.txt"))
+		verification_file_dumper.WriteLines(this.host.Verifier().ToLines())
+
+		fmt.Printf("verification: %d mismatches\n", this.host.Verifier().NumMismatches())
+	}
 }
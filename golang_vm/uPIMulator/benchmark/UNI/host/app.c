@@ -154,7 +154,6 @@ int main() {
     int status = 1;
     if(accum != total_count) {
         status = 0;
-        break;
     }
 
     for (i = 0; i < accum; i++) {
@@ -557,7 +557,6 @@ func (this *Parser) RegisterConditionExpr() {
 					token_type == lexer.SO ||
 					token_type == lexer.SE ||
 					token_type == lexer.NC5 ||
-					token_type == lexer.NC5 ||
 					token_type == lexer.NC6 ||
 					token_type == lexer.NC7 ||
 					token_type == lexer.NC8 ||
@@ -168,6 +168,10 @@ func (this *Dpu) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
 
+func (this *Dpu) Cycles() int64 {
+	return this.cycles
+}
+
 func (this *Dpu) Boot() {
 	this.control_interface.SetBoot()
 	this.thread_scheduler.Boot(0)
@@ -210,6 +210,16 @@ func (this *Dma) CanPop() bool {
 	return this.ready_q.CanPop(1)
 }
 
+func (this *Dma) Front() *dram.DmaCommand {
+	if !this.CanPop() {
+		err := errors.New("DMA cannot be popped")
+		panic(err)
+	}
+
+	dma_command, _ := this.ready_q.Front(0)
+	return dma_command
+}
+
 func (this *Dma) Pop() *dram.DmaCommand {
 	if !this.CanPop() {
 		err := errors.New("DMA cannot be popped")
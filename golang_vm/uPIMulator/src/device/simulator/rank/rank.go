@@ -123,14 +123,18 @@ func (this *Rank) ServiceInputQ() {
 	}
 }
 
+// NOTE: the DMA of a DPU also completes the DMA commands of its own tasklets, which are left to the
+// logic of the DPU
 func (this *Rank) ServiceReadyQ() {
 	for _, dpu_ := range this.dpus {
 		if dpu_.Dma().CanPop() && this.ready_q.CanPush(1) {
-			dma_command := dpu_.Dma().Pop()
-			rank_command := this.scoreboard[dma_command]
+			if _, found := this.scoreboard[dpu_.Dma().Front()]; found {
+				dma_command := dpu_.Dma().Pop()
+				rank_command := this.scoreboard[dma_command]
 
-			this.ready_q.Push(rank_command)
-			delete(this.scoreboard, dma_command)
+				this.ready_q.Push(rank_command)
+				delete(this.scoreboard, dma_command)
+			}
 		}
 	}
 }
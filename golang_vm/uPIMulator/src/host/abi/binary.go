@@ -112,3 +112,12 @@ func (this *Binary) Dump(path string) {
 	file_dumper.Init(path)
 	file_dumper.WriteLines(lines)
 }
+
+func (this *Binary) DumpBinary(path string) {
+	bytecode_encoder := new(BytecodeEncoder)
+	bytecode_encoder.Init()
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteBytes(bytecode_encoder.Encode(this.relocatable.Labels()))
+}
@@ -11,6 +11,11 @@ type Bytecode struct {
 	arg2    *int64
 	str1    *string
 	str2    *string
+
+	target *Label
+
+	path string
+	line int
 }
 
 func (this *Bytecode) Init(op_code OpCode, args []int64, strs []string) {
@@ -53,6 +58,11 @@ func (this *Bytecode) Init(op_code OpCode, args []int64, strs []string) {
 		err := errors.New("len(strs) > 2")
 		panic(err)
 	}
+
+	this.target = nil
+
+	this.path = ""
+	this.line = 0
 }
 
 func (this *Bytecode) OpCode() OpCode {
@@ -77,6 +87,10 @@ func (this *Bytecode) Arg2() int64 {
 	return *this.arg2
 }
 
+func (this *Bytecode) HasArg2() bool {
+	return this.arg2 != nil
+}
+
 func (this *Bytecode) Str1() string {
 	if this.str1 == nil {
 		err := errors.New("str1 == nil")
@@ -95,6 +109,55 @@ func (this *Bytecode) Str2() string {
 	return *this.str2
 }
 
+func (this *Bytecode) IsBranch() bool {
+	return this.op_code == JUMP ||
+		this.op_code == JUMP_IF_ZERO ||
+		this.op_code == JUMP_IF_NONZERO ||
+		this.op_code == CALL
+}
+
+// NOTE: the label of a branch is resolved when the bytecode is loaded, so that the VM does not look
+// it up by its name
+func (this *Bytecode) Target() *Label {
+	if this.target == nil {
+		err := errors.New("target == nil")
+		panic(err)
+	}
+
+	return this.target
+}
+
+func (this *Bytecode) SetTarget(target *Label) {
+	if !this.IsBranch() {
+		err := errors.New("bytecode is not a branch")
+		panic(err)
+	} else if target.Name() != this.Str1() {
+		err := errors.New("target's name is not str1")
+		panic(err)
+	}
+
+	this.target = target
+}
+
+// NOTE: the position of a bytecode is the host source line of the stmt it is generated from, while
+// the bytecodes that no stmt of the source stands for have no position
+func (this *Bytecode) HasPosition() bool {
+	return this.line > 0
+}
+
+func (this *Bytecode) Path() string {
+	return this.path
+}
+
+func (this *Bytecode) Line() int {
+	return this.line
+}
+
+func (this *Bytecode) SetPosition(path string, line int) {
+	this.path = path
+	this.line = line
+}
+
 func (this *Bytecode) Stringify() string {
 	if this.op_code == NEW_SCOPE {
 		return "NEW_SCOPE"
@@ -108,70 +171,140 @@ func (this *Bytecode) Stringify() string {
 		return fmt.Sprintf("PUSH_INT %d", *this.arg1)
 	} else if this.op_code == PUSH_LONG {
 		return fmt.Sprintf("PUSH_LONG %d", *this.arg1)
+	} else if this.op_code == PUSH_FLOAT {
+		return fmt.Sprintf("PUSH_FLOAT %d", *this.arg1)
+	} else if this.op_code == PUSH_DOUBLE {
+		return fmt.Sprintf("PUSH_DOUBLE %d", *this.arg1)
 	} else if this.op_code == PUSH_STRING {
 		return fmt.Sprintf("PUSH_STRING %s", *this.str1)
 	} else if this.op_code == POP {
 		return "POP"
+	} else if this.op_code == DUPLICATE {
+		return "DUPLICATE"
 	} else if this.op_code == BEGIN_STRUCT {
 		return fmt.Sprintf("BEGIN_STRUCT %s", *this.str1)
 	} else if this.op_code == APPEND_VOID {
 		return fmt.Sprintf("APPEND_VOID %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == APPEND_CHAR {
-		return fmt.Sprintf("APPEND_VOID %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("APPEND_CHAR %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == APPEND_SHORT {
-		return fmt.Sprintf("APPEND_SHORT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("APPEND_SHORT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == APPEND_INT {
-		return fmt.Sprintf("APPEND_INT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("APPEND_INT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == APPEND_LONG {
-		return fmt.Sprintf("APPEND_LONG %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("APPEND_LONG %d %s", *this.arg1, *this.str1),
+		)
+	} else if this.op_code == APPEND_FLOAT {
+		return fmt.Sprintf("APPEND_FLOAT %d %s", *this.arg1, *this.str1)
+	} else if this.op_code == APPEND_DOUBLE {
+		return fmt.Sprintf("APPEND_DOUBLE %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == APPEND_STRUCT {
 		return fmt.Sprintf("APPEND_STRUCT %d %s %s", *this.arg1, *this.str1, *this.str2)
+	} else if this.op_code == APPEND_ARRAY {
+		return "APPEND_ARRAY"
 	} else if this.op_code == END_STRUCT {
 		return "END_STRUCT"
 	} else if this.op_code == NEW_GLOBAL_VOID {
 		return fmt.Sprintf("NEW_GLOBAL_VOID %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == NEW_GLOBAL_CHAR {
-		return fmt.Sprintf("NEW_GLOBAL_CHAR %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_GLOBAL_CHAR %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_GLOBAL_SHORT {
-		return fmt.Sprintf("NEW_GLOBAL_SHORT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_GLOBAL_SHORT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_GLOBAL_INT {
-		return fmt.Sprintf("NEW_GLOBAL_INT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_GLOBAL_INT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_GLOBAL_LONG {
-		return fmt.Sprintf("NEW_GLOBAL_LONG %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_GLOBAL_LONG %d %s", *this.arg1, *this.str1),
+		)
+	} else if this.op_code == NEW_GLOBAL_FLOAT {
+		return fmt.Sprintf("NEW_GLOBAL_FLOAT %d %s", *this.arg1, *this.str1)
+	} else if this.op_code == NEW_GLOBAL_DOUBLE {
+		return fmt.Sprintf("NEW_GLOBAL_DOUBLE %d %s", *this.arg1, *this.str1)
+	} else if this.op_code == NEW_GLOBAL_STRUCT {
+		return fmt.Sprintf("NEW_GLOBAL_STRUCT %d %s %s", *this.arg1, *this.str1, *this.str2)
 	} else if this.op_code == NEW_FAST_VOID {
 		return fmt.Sprintf("NEW_FAST_VOID %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == NEW_FAST_CHAR {
-		return fmt.Sprintf("NEW_FAST_CHAR %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_FAST_CHAR %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_FAST_SHORT {
-		return fmt.Sprintf("NEW_FAST_SHORT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_FAST_SHORT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_FAST_INT {
-		return fmt.Sprintf("NEW_FAST_INT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_FAST_INT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_FAST_LONG {
-		return fmt.Sprintf("NEW_FAST_LONG %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_FAST_LONG %d %s", *this.arg1, *this.str1),
+		)
+	} else if this.op_code == NEW_FAST_FLOAT {
+		return fmt.Sprintf("NEW_FAST_FLOAT %d %s", *this.arg1, *this.str1)
+	} else if this.op_code == NEW_FAST_DOUBLE {
+		return fmt.Sprintf("NEW_FAST_DOUBLE %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == NEW_FAST_STRUCT {
 		return fmt.Sprintf("NEW_FAST_STRUCT %d %s %s", *this.arg1, *this.str1, *this.str2)
 	} else if this.op_code == NEW_ARG_VOID {
 		return fmt.Sprintf("NEW_ARG_VOID %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == NEW_ARG_CHAR {
-		return fmt.Sprintf("NEW_ARG_CHAR %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_ARG_CHAR %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_ARG_SHORT {
-		return fmt.Sprintf("NEW_ARG_SHORT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_ARG_SHORT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_ARG_INT {
-		return fmt.Sprintf("NEW_ARG_INT %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_ARG_INT %d %s", *this.arg1, *this.str1),
+		)
 	} else if this.op_code == NEW_ARG_LONG {
-		return fmt.Sprintf("NEW_ARG_LONG %d %s", *this.arg1, *this.str1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_ARG_LONG %d %s", *this.arg1, *this.str1),
+		)
+	} else if this.op_code == NEW_ARG_FLOAT {
+		return fmt.Sprintf("NEW_ARG_FLOAT %d %s", *this.arg1, *this.str1)
+	} else if this.op_code == NEW_ARG_DOUBLE {
+		return fmt.Sprintf("NEW_ARG_DOUBLE %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == NEW_ARG_STRUCT {
 		return fmt.Sprintf("NEW_ARG_STRUCT %d %s %s", *this.arg1, *this.str1, *this.str2)
 	} else if this.op_code == NEW_RETURN_VOID {
 		return fmt.Sprintf("NEW_RETURN_VOID %d", *this.arg1)
 	} else if this.op_code == NEW_RETURN_CHAR {
-		return fmt.Sprintf("NEW_RETURN_CHAR %d", *this.arg1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_RETURN_CHAR %d", *this.arg1),
+		)
 	} else if this.op_code == NEW_RETURN_SHORT {
-		return fmt.Sprintf("NEW_RETURN_SHORT %d", *this.arg1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_RETURN_SHORT %d", *this.arg1),
+		)
 	} else if this.op_code == NEW_RETURN_INT {
-		return fmt.Sprintf("NEW_RETURN_INT %d", *this.arg1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_RETURN_INT %d", *this.arg1),
+		)
 	} else if this.op_code == NEW_RETURN_LONG {
-		return fmt.Sprintf("NEW_RETURN_LONG %d", *this.arg1)
+		return this.StringifyUnsigned(
+			fmt.Sprintf("NEW_RETURN_LONG %d", *this.arg1),
+		)
+	} else if this.op_code == NEW_RETURN_FLOAT {
+		return fmt.Sprintf("NEW_RETURN_FLOAT %d", *this.arg1)
+	} else if this.op_code == NEW_RETURN_DOUBLE {
+		return fmt.Sprintf("NEW_RETURN_DOUBLE %d", *this.arg1)
 	} else if this.op_code == NEW_RETURN_STRUCT {
 		return fmt.Sprintf("NEW_RETURN_STRUCT %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == SIZEOF_VOID {
@@ -184,8 +317,28 @@ func (this *Bytecode) Stringify() string {
 		return fmt.Sprintf("SIZEOF_INT %d", *this.arg1)
 	} else if this.op_code == SIZEOF_LONG {
 		return fmt.Sprintf("SIZEOF_LONG %d", *this.arg1)
+	} else if this.op_code == SIZEOF_FLOAT {
+		return fmt.Sprintf("SIZEOF_FLOAT %d", *this.arg1)
+	} else if this.op_code == SIZEOF_DOUBLE {
+		return fmt.Sprintf("SIZEOF_DOUBLE %d", *this.arg1)
 	} else if this.op_code == SIZEOF_STRUCT {
 		return fmt.Sprintf("SIZEOF_STRUCT %d %s", *this.arg1, *this.str1)
+	} else if this.op_code == CAST_VOID {
+		return fmt.Sprintf("CAST_VOID %d", *this.arg1)
+	} else if this.op_code == CAST_CHAR {
+		return this.StringifyUnsigned(fmt.Sprintf("CAST_CHAR %d", *this.arg1))
+	} else if this.op_code == CAST_SHORT {
+		return this.StringifyUnsigned(fmt.Sprintf("CAST_SHORT %d", *this.arg1))
+	} else if this.op_code == CAST_INT {
+		return this.StringifyUnsigned(fmt.Sprintf("CAST_INT %d", *this.arg1))
+	} else if this.op_code == CAST_LONG {
+		return this.StringifyUnsigned(fmt.Sprintf("CAST_LONG %d", *this.arg1))
+	} else if this.op_code == CAST_FLOAT {
+		return fmt.Sprintf("CAST_FLOAT %d", *this.arg1)
+	} else if this.op_code == CAST_DOUBLE {
+		return fmt.Sprintf("CAST_DOUBLE %d", *this.arg1)
+	} else if this.op_code == CAST_STRUCT {
+		return fmt.Sprintf("CAST_STRUCT %d %s", *this.arg1, *this.str1)
 	} else if this.op_code == GET_IDENTIFIER {
 		return fmt.Sprintf("GET_IDENTIFIER %s", *this.str1)
 	} else if this.op_code == GET_ARG_IDENTIFIER {
@@ -206,6 +359,24 @@ func (this *Bytecode) Stringify() string {
 		return "FREE"
 	} else if this.op_code == ASSERT {
 		return "ASSERT"
+	} else if this.op_code == PRINTF {
+		return "PRINTF"
+	} else if this.op_code == EXIT {
+		return "EXIT"
+	} else if this.op_code == ATOI {
+		return "ATOI"
+	} else if this.op_code == RAND {
+		return "RAND"
+	} else if this.op_code == SRAND {
+		return "SRAND"
+	} else if this.op_code == GETTIMEOFDAY {
+		return "GETTIMEOFDAY"
+	} else if this.op_code == GETOPT {
+		return "GETOPT"
+	} else if this.op_code == GET_ARGC {
+		return "GET_ARGC"
+	} else if this.op_code == GET_ARGV {
+		return "GET_ARGV"
 	} else if this.op_code == ADD {
 		return "ADD"
 	} else if this.op_code == SUB {
@@ -293,23 +464,53 @@ func (this *Bytecode) Stringify() string {
 	} else if this.op_code == NOP {
 		return "NOP"
 	} else if this.op_code == DPU_ALLOC {
-		return fmt.Sprintf("DPU_ALLOC %d", *this.arg1)
+		return "DPU_ALLOC"
+	} else if this.op_code == DPU_ALLOC_RANKS {
+		return "DPU_ALLOC_RANKS"
 	} else if this.op_code == DPU_LOAD {
-		return fmt.Sprintf("DPU_LOAD %d %s", *this.arg1, *this.str1)
+		return "DPU_LOAD"
 	} else if this.op_code == DPU_PREPARE {
 		return "DPU_PREPARE"
 	} else if this.op_code == DPU_TRANSFER {
 		return "DPU_TRANSFER"
+	} else if this.op_code == DPU_BROADCAST {
+		return "DPU_BROADCAST"
 	} else if this.op_code == DPU_COPY_TO {
 		return "DPU_COPY_TO"
 	} else if this.op_code == DPU_COPY_FROM {
 		return "DPU_COPY_FROM"
 	} else if this.op_code == DPU_LAUNCH {
 		return "DPU_LAUNCH"
+	} else if this.op_code == DPU_SYNC {
+		return "DPU_SYNC"
 	} else if this.op_code == DPU_FREE {
 		return "DPU_FREE"
+	} else if this.op_code == DPU_NR_DPUS {
+		return "DPU_NR_DPUS"
+	} else if this.op_code == DPU_NR_RANKS {
+		return "DPU_NR_RANKS"
+	} else if this.op_code == DPU_GET_DPU {
+		return "DPU_GET_DPU"
+	} else if this.op_code == DPU_GET_RANK {
+		return "DPU_GET_RANK"
+	} else if this.op_code == DPU_NR_CALLBACKS {
+		return "DPU_NR_CALLBACKS"
+	} else if this.op_code == DPU_GET_CALLBACK {
+		return "DPU_GET_CALLBACK"
+	} else if this.op_code == DPU_LOG_READ {
+		return "DPU_LOG_READ"
 	} else {
 		err := errors.New("op code is not valid")
 		panic(err)
 	}
 }
+
+// NOTE: the integer declarations and casts carry an optional second argument that is 1 for
+// unsigned types
+func (this *Bytecode) StringifyUnsigned(str string) string {
+	if this.arg2 != nil {
+		return fmt.Sprintf("%s %d", str, *this.arg2)
+	} else {
+		return str
+	}
+}
@@ -0,0 +1,167 @@
+package abi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type BytecodeDecoder struct {
+	bytes []byte
+	pos   int
+
+	strs []string
+}
+
+func (this *BytecodeDecoder) Init(bytes []byte) {
+	this.bytes = bytes
+	this.pos = 0
+
+	this.strs = make([]string, 0)
+}
+
+func (this *BytecodeDecoder) Decode() []*Label {
+	if len(this.bytes) < len(BYTECODE_MAGIC) ||
+		string(this.bytes[:len(BYTECODE_MAGIC)]) != BYTECODE_MAGIC {
+		err := errors.New("bytecode file does not start with the magic word")
+		panic(err)
+	}
+
+	this.pos = len(BYTECODE_MAGIC)
+
+	if version := this.ReadUvarint(); version != BYTECODE_VERSION {
+		err_msg := fmt.Sprintf("bytecode version (%d) is not supported", version)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	num_strs := this.ReadUvarint()
+	for i := int64(0); i < num_strs; i++ {
+		length := int(this.ReadUvarint())
+
+		if this.pos+length > len(this.bytes) {
+			err := errors.New("bytecode file ends in the middle of a string")
+			panic(err)
+		}
+
+		this.strs = append(this.strs, string(this.bytes[this.pos:this.pos+length]))
+		this.pos += length
+	}
+
+	num_labels := this.ReadUvarint()
+
+	labels := make([]*Label, 0)
+	lengths := make([]int64, 0)
+	for i := int64(0); i < num_labels; i++ {
+		label := new(Label)
+		label.Init(this.ReadStr())
+
+		labels = append(labels, label)
+		lengths = append(lengths, this.ReadUvarint())
+	}
+
+	for i, label := range labels {
+		for j := int64(0); j < lengths[i]; j++ {
+			label.Append(this.DecodeBytecode(labels))
+		}
+	}
+
+	if this.pos != len(this.bytes) {
+		err := errors.New("bytecode file has trailing bytes")
+		panic(err)
+	}
+
+	return labels
+}
+
+func (this *BytecodeDecoder) DecodeBytecode(labels []*Label) *Bytecode {
+	op_code := OpCode(this.ReadUvarint())
+	layout := this.ReadUvarint()
+
+	if op_code < 0 || op_code >= NUM_OP_CODES {
+		err_msg := fmt.Sprintf("op code (%d) is not valid", op_code)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	args := make([]int64, 0)
+	if layout&HAS_ARG1 != 0 {
+		args = append(args, this.ReadVarint())
+	}
+	if layout&HAS_ARG2 != 0 {
+		args = append(args, this.ReadVarint())
+	}
+
+	var target *Label
+	strs := make([]string, 0)
+	if layout&HAS_STR1 != 0 {
+		if op_code == JUMP || op_code == JUMP_IF_ZERO || op_code == JUMP_IF_NONZERO ||
+			op_code == CALL {
+			label_index := this.ReadUvarint()
+
+			if label_index >= int64(len(labels)) {
+				err := errors.New("label index is out of the label table")
+				panic(err)
+			}
+
+			target = labels[label_index]
+			strs = append(strs, target.Name())
+		} else {
+			strs = append(strs, this.ReadStr())
+		}
+	}
+	if layout&HAS_STR2 != 0 {
+		strs = append(strs, this.ReadStr())
+	}
+
+	bytecode := new(Bytecode)
+	bytecode.Init(op_code, args, strs)
+
+	if target != nil {
+		bytecode.SetTarget(target)
+	}
+
+	if layout&HAS_POSITION != 0 {
+		path := this.ReadStr()
+		bytecode.SetPosition(path, int(this.ReadUvarint()))
+	}
+
+	return bytecode
+}
+
+func (this *BytecodeDecoder) ReadStr() string {
+	index := this.ReadUvarint()
+
+	if index >= int64(len(this.strs)) {
+		err := errors.New("string index is out of the string table")
+		panic(err)
+	}
+
+	return this.strs[index]
+}
+
+func (this *BytecodeDecoder) ReadUvarint() int64 {
+	value, n := binary.Uvarint(this.bytes[this.pos:])
+
+	if n <= 0 {
+		err := errors.New("bytecode file has a corrupted varint")
+		panic(err)
+	}
+
+	this.pos += n
+
+	return int64(value)
+}
+
+func (this *BytecodeDecoder) ReadVarint() int64 {
+	value, n := binary.Varint(this.bytes[this.pos:])
+
+	if n <= 0 {
+		err := errors.New("bytecode file has a corrupted varint")
+		panic(err)
+	}
+
+	this.pos += n
+
+	return value
+}
@@ -0,0 +1,164 @@
+package abi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	BYTECODE_MAGIC   = "UPMB"
+	BYTECODE_VERSION = 3
+)
+
+const (
+	HAS_ARG1 = 1 << iota
+	HAS_ARG2
+	HAS_STR1
+	HAS_STR2
+	HAS_POSITION
+)
+
+// NOTE: a binary bytecode file starts with a magic word and a version, followed by a table of
+// interned strings, a table of labels and the bytecodes of every label in order, where integers
+// are varints, strings are indices to the string table and the label of a branch is an index to the
+// label table. A bytecode with a position carries the string index of its path and its line as well
+type BytecodeEncoder struct {
+	bytes []byte
+
+	strs          []string
+	str_indices   map[string]int
+	label_indices map[string]int
+}
+
+func (this *BytecodeEncoder) Init() {
+	this.bytes = make([]byte, 0)
+
+	this.strs = make([]string, 0)
+	this.str_indices = make(map[string]int)
+	this.label_indices = make(map[string]int)
+}
+
+func (this *BytecodeEncoder) Encode(labels []*Label) []byte {
+	for i, label := range labels {
+		if _, found := this.label_indices[label.Name()]; found {
+			err_msg := fmt.Sprintf("label (%s) is duplicated", label.Name())
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.label_indices[label.Name()] = i
+		this.Intern(label.Name())
+	}
+
+	for _, label := range labels {
+		for i := 0; i < label.Length(); i++ {
+			bytecode := label.Get(i)
+
+			if bytecode.str1 != nil && !bytecode.IsBranch() {
+				this.Intern(*bytecode.str1)
+			}
+
+			if bytecode.str2 != nil {
+				this.Intern(*bytecode.str2)
+			}
+
+			if bytecode.HasPosition() {
+				this.Intern(bytecode.path)
+			}
+		}
+	}
+
+	this.bytes = append(this.bytes, BYTECODE_MAGIC...)
+	this.WriteUvarint(BYTECODE_VERSION)
+
+	this.WriteUvarint(int64(len(this.strs)))
+	for _, str := range this.strs {
+		this.WriteUvarint(int64(len(str)))
+		this.bytes = append(this.bytes, str...)
+	}
+
+	this.WriteUvarint(int64(len(labels)))
+	for _, label := range labels {
+		this.WriteUvarint(int64(this.str_indices[label.Name()]))
+		this.WriteUvarint(int64(label.Length()))
+	}
+
+	for _, label := range labels {
+		for i := 0; i < label.Length(); i++ {
+			this.EncodeBytecode(label.Get(i))
+		}
+	}
+
+	return this.bytes
+}
+
+func (this *BytecodeEncoder) EncodeBytecode(bytecode *Bytecode) {
+	layout := int64(0)
+	if bytecode.arg1 != nil {
+		layout |= HAS_ARG1
+	}
+	if bytecode.arg2 != nil {
+		layout |= HAS_ARG2
+	}
+	if bytecode.str1 != nil {
+		layout |= HAS_STR1
+	}
+	if bytecode.str2 != nil {
+		layout |= HAS_STR2
+	}
+	if bytecode.HasPosition() {
+		layout |= HAS_POSITION
+	}
+
+	this.WriteUvarint(int64(bytecode.op_code))
+	this.WriteUvarint(layout)
+
+	if bytecode.arg1 != nil {
+		this.WriteVarint(*bytecode.arg1)
+	}
+
+	if bytecode.arg2 != nil {
+		this.WriteVarint(*bytecode.arg2)
+	}
+
+	if bytecode.str1 != nil {
+		if bytecode.IsBranch() {
+			label_index, found := this.label_indices[*bytecode.str1]
+
+			if !found {
+				err_msg := fmt.Sprintf("label (%s) is not found", *bytecode.str1)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			this.WriteUvarint(int64(label_index))
+		} else {
+			this.WriteUvarint(int64(this.str_indices[*bytecode.str1]))
+		}
+	}
+
+	if bytecode.str2 != nil {
+		this.WriteUvarint(int64(this.str_indices[*bytecode.str2]))
+	}
+
+	if bytecode.HasPosition() {
+		this.WriteUvarint(int64(this.str_indices[bytecode.path]))
+		this.WriteUvarint(int64(bytecode.line))
+	}
+}
+
+func (this *BytecodeEncoder) Intern(str string) {
+	if _, found := this.str_indices[str]; !found {
+		this.str_indices[str] = len(this.strs)
+		this.strs = append(this.strs, str)
+	}
+}
+
+func (this *BytecodeEncoder) WriteUvarint(value int64) {
+	this.bytes = binary.AppendUvarint(this.bytes, uint64(value))
+}
+
+func (this *BytecodeEncoder) WriteVarint(value int64) {
+	this.bytes = binary.AppendVarint(this.bytes, value)
+}
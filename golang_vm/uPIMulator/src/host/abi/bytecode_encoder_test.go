@@ -0,0 +1,196 @@
+package abi
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// NOTE: the relocatable holds a label of every kind that the codegen makes, i.e., functions, the
+// condition, body and end of a loop, the end of a switch, an unnamed label and an empty one, and
+// every op code is generated once per label with arguments, strings and positions that vary with it
+func newRoundTripRelocatable() *Relocatable {
+	relocatable := new(Relocatable)
+	relocatable.Init()
+
+	relocatable.NewFunc("main")
+	relocatable.NewFunc("helper")
+	relocatable.NewLoop()
+	relocatable.NewSwitch()
+	relocatable.EndLoop()
+	relocatable.EndLoop()
+	relocatable.NewUnnamedLabel()
+	relocatable.NewNamedLabel("empty")
+
+	labels := relocatable.Labels()
+	args := []int64{0, -1, 1, math.MaxInt64, math.MinInt64, 1 << 40}
+
+	for i, label := range labels[:len(labels)-1] {
+		relocatable.SwitchLabel(label.Name())
+
+		for op_code := OpCode(0); op_code < NUM_OP_CODES; op_code++ {
+			seed := i + int(op_code)
+
+			if seed%3 == 0 {
+				relocatable.SetPosition("", 0)
+			} else {
+				relocatable.SetPosition("/src/app_"+label.Name()+".c", seed)
+			}
+
+			bytecode := new(Bytecode)
+
+			// NOTE: the strings of a bytecode may be label names too, which are interned once
+			strs := []string{"x", "main", "", "struct point"}[:seed%3]
+			if bytecode.op_code = op_code; bytecode.IsBranch() {
+				strs = []string{labels[seed%len(labels)].Name()}
+			}
+
+			// NOTE: the arguments wrap around to cover the edge values in either position
+			bytecode_args := make([]int64, 0)
+			for j := 0; j < seed%3; j++ {
+				bytecode_args = append(bytecode_args, args[(seed+j)%len(args)])
+			}
+
+			relocatable.NewBytecode(op_code, bytecode_args, strs)
+		}
+	}
+
+	return relocatable
+}
+
+func expectBytecode(t *testing.T, label string, actual *Bytecode, expected *Bytecode) {
+	t.Helper()
+
+	if actual.OpCode() != expected.OpCode() {
+		t.Fatalf("%s: op code %d is decoded as %d", label, expected.OpCode(), actual.OpCode())
+	}
+
+	for j, pair := range [][]*int64{{actual.arg1, expected.arg1}, {actual.arg2, expected.arg2}} {
+		if (pair[0] == nil) != (pair[1] == nil) || pair[0] != nil && *pair[0] != *pair[1] {
+			t.Errorf("%s: arg%d of %s is not kept", label, j+1, expected.Stringify())
+		}
+	}
+
+	for j, pair := range [][]*string{{actual.str1, expected.str1}, {actual.str2, expected.str2}} {
+		if (pair[0] == nil) != (pair[1] == nil) || pair[0] != nil && *pair[0] != *pair[1] {
+			t.Errorf("%s: str%d of op code %d is not kept", label, j+1, expected.OpCode())
+		}
+	}
+
+	if actual.Path() != expected.Path() || actual.Line() != expected.Line() {
+		t.Errorf(
+			"%s: position %s:%d is decoded as %s:%d",
+			label,
+			expected.Path(),
+			expected.Line(),
+			actual.Path(),
+			actual.Line(),
+		)
+	}
+}
+
+func TestBytecodeRoundTrip(t *testing.T) {
+	labels := newRoundTripRelocatable().Labels()
+
+	bytecode_encoder := new(BytecodeEncoder)
+	bytecode_encoder.Init()
+	encoded := bytecode_encoder.Encode(labels)
+
+	bytecode_decoder := new(BytecodeDecoder)
+	bytecode_decoder.Init(encoded)
+	decoded_labels := bytecode_decoder.Decode()
+
+	if len(decoded_labels) != len(labels) {
+		t.Fatalf("%d labels are decoded out of %d", len(decoded_labels), len(labels))
+	}
+
+	for i, label := range labels {
+		decoded_label := decoded_labels[i]
+
+		if decoded_label.Name() != label.Name() || decoded_label.Length() != label.Length() {
+			t.Fatalf(
+				"label %s of %d bytecodes is decoded as %s of %d bytecodes",
+				label.Name(),
+				label.Length(),
+				decoded_label.Name(),
+				decoded_label.Length(),
+			)
+		}
+
+		for j := 0; j < label.Length(); j++ {
+			bytecode := label.Get(j)
+			decoded_bytecode := decoded_label.Get(j)
+
+			expectBytecode(t, label.Name(), decoded_bytecode, bytecode)
+
+			// NOTE: a branch is resolved to the decoded label it names rather than a copy of it
+			if bytecode.IsBranch() {
+				target := decoded_bytecode.Target()
+
+				found := false
+				for _, decoded_target := range decoded_labels {
+					found = found || decoded_target == target
+				}
+
+				if !found || target.Name() != bytecode.Str1() {
+					t.Errorf("%s: branch to %s is not resolved", label.Name(), bytecode.Str1())
+				}
+			}
+		}
+	}
+
+	bytecode_encoder = new(BytecodeEncoder)
+	bytecode_encoder.Init()
+	if !bytes.Equal(bytecode_encoder.Encode(decoded_labels), encoded) {
+		t.Errorf("decoded labels are not encoded to the same bytes")
+	}
+}
+
+func TestBytecodeDecoderRejectsCorruptFiles(t *testing.T) {
+	bytecode_encoder := new(BytecodeEncoder)
+	bytecode_encoder.Init()
+	encoded := bytecode_encoder.Encode(newRoundTripRelocatable().Labels())
+
+	version := append([]byte(BYTECODE_MAGIC), BYTECODE_VERSION+1)
+	version = append(version, encoded[len(BYTECODE_MAGIC)+1:]...)
+
+	corrupts := map[string][]byte{
+		"magic":     append([]byte("UPMX"), encoded[len(BYTECODE_MAGIC):]...),
+		"version":   version,
+		"truncated": encoded[:len(encoded)-1],
+		"trailing":  append(append([]byte{}, encoded...), 0),
+	}
+
+	for name, corrupt := range corrupts {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s bytecode file is decoded", name)
+				}
+			}()
+
+			bytecode_decoder := new(BytecodeDecoder)
+			bytecode_decoder.Init(corrupt)
+			bytecode_decoder.Decode()
+		}()
+	}
+}
+
+func TestBytecodeEncoderRejectsUnknownLabels(t *testing.T) {
+	label := new(Label)
+	label.Init("main")
+
+	bytecode := new(Bytecode)
+	bytecode.Init(JUMP, []int64{}, []string{"missing"})
+	label.Append(bytecode)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("a branch to a missing label is encoded")
+		}
+	}()
+
+	bytecode_encoder := new(BytecodeEncoder)
+	bytecode_encoder.Init()
+	bytecode_encoder.Encode([]*Label{label})
+}
@@ -10,9 +10,12 @@ const (
 	PUSH_SHORT
 	PUSH_INT
 	PUSH_LONG
+	PUSH_FLOAT
+	PUSH_DOUBLE
 	PUSH_STRING
 
 	POP
+	DUPLICATE
 
 	BEGIN_STRUCT
 	APPEND_VOID
@@ -20,7 +23,10 @@ const (
 	APPEND_SHORT
 	APPEND_INT
 	APPEND_LONG
+	APPEND_FLOAT
+	APPEND_DOUBLE
 	APPEND_STRUCT
+	APPEND_ARRAY
 	END_STRUCT
 
 	NEW_GLOBAL_VOID
@@ -28,12 +34,17 @@ const (
 	NEW_GLOBAL_SHORT
 	NEW_GLOBAL_INT
 	NEW_GLOBAL_LONG
+	NEW_GLOBAL_FLOAT
+	NEW_GLOBAL_DOUBLE
+	NEW_GLOBAL_STRUCT
 
 	NEW_FAST_VOID
 	NEW_FAST_CHAR
 	NEW_FAST_SHORT
 	NEW_FAST_INT
 	NEW_FAST_LONG
+	NEW_FAST_FLOAT
+	NEW_FAST_DOUBLE
 	NEW_FAST_STRUCT
 
 	NEW_ARG_VOID
@@ -41,6 +52,8 @@ const (
 	NEW_ARG_SHORT
 	NEW_ARG_INT
 	NEW_ARG_LONG
+	NEW_ARG_FLOAT
+	NEW_ARG_DOUBLE
 	NEW_ARG_STRUCT
 
 	NEW_RETURN_VOID
@@ -48,6 +61,8 @@ const (
 	NEW_RETURN_SHORT
 	NEW_RETURN_INT
 	NEW_RETURN_LONG
+	NEW_RETURN_FLOAT
+	NEW_RETURN_DOUBLE
 	NEW_RETURN_STRUCT
 
 	SIZEOF_VOID
@@ -55,8 +70,19 @@ const (
 	SIZEOF_SHORT
 	SIZEOF_INT
 	SIZEOF_LONG
+	SIZEOF_FLOAT
+	SIZEOF_DOUBLE
 	SIZEOF_STRUCT
 
+	CAST_VOID
+	CAST_CHAR
+	CAST_SHORT
+	CAST_INT
+	CAST_LONG
+	CAST_FLOAT
+	CAST_DOUBLE
+	CAST_STRUCT
+
 	GET_IDENTIFIER
 	GET_ARG_IDENTIFIER
 
@@ -71,6 +97,16 @@ const (
 
 	ASSERT
 
+	PRINTF
+	EXIT
+	ATOI
+	RAND
+	SRAND
+	GETTIMEOFDAY
+	GETOPT
+	GET_ARGC
+	GET_ARGV
+
 	ADD
 	SUB
 	MUL
@@ -127,11 +163,23 @@ const (
 	NOP
 
 	DPU_ALLOC
+	DPU_ALLOC_RANKS
 	DPU_LOAD
 	DPU_PREPARE
 	DPU_TRANSFER
+	DPU_BROADCAST
 	DPU_COPY_TO
 	DPU_COPY_FROM
 	DPU_LAUNCH
+	DPU_SYNC
 	DPU_FREE
+	DPU_NR_DPUS
+	DPU_NR_RANKS
+	DPU_GET_DPU
+	DPU_GET_RANK
+	DPU_NR_CALLBACKS
+	DPU_GET_CALLBACK
+	DPU_LOG_READ
+
+	NUM_OP_CODES
 )
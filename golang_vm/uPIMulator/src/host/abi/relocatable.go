@@ -14,9 +14,16 @@ type Relocatable struct {
 	cur_loop_body      *Label
 	cur_loop_end       *Label
 
+	prev_loop_conditions []*Label
+	prev_loop_bodies     []*Label
+	prev_loop_ends       []*Label
+
 	cur_label *Label
 
 	label_brk int
+
+	cur_path string
+	cur_line int
 }
 
 func (this *Relocatable) Init() {
@@ -28,9 +35,16 @@ func (this *Relocatable) Init() {
 	this.cur_loop_body = nil
 	this.cur_loop_end = nil
 
+	this.prev_loop_conditions = make([]*Label, 0)
+	this.prev_loop_bodies = make([]*Label, 0)
+	this.prev_loop_ends = make([]*Label, 0)
+
 	this.cur_label = nil
 
 	this.label_brk = 0
+
+	this.cur_path = ""
+	this.cur_line = 0
 }
 
 func (this *Relocatable) HasFunc(func_name string) bool {
@@ -84,6 +98,8 @@ func (this *Relocatable) CurLoopEnd() *Label {
 }
 
 func (this *Relocatable) NewLoop() (*Label, *Label, *Label) {
+	this.SaveLoop()
+
 	this.cur_loop_condition = this.NewUnnamedLabel()
 	this.cur_loop_body = this.NewUnnamedLabel()
 	this.cur_loop_end = this.NewUnnamedLabel()
@@ -91,6 +107,39 @@ func (this *Relocatable) NewLoop() (*Label, *Label, *Label) {
 	return this.cur_loop_condition, this.cur_loop_body, this.cur_loop_end
 }
 
+// NOTE: a switch only redirects break, so that continue still jumps to the enclosing loop
+func (this *Relocatable) NewSwitch() *Label {
+	this.SaveLoop()
+
+	this.cur_loop_end = this.NewUnnamedLabel()
+
+	return this.cur_loop_end
+}
+
+// NOTE: EndLoop restores the labels of the enclosing loop after a loop or a switch is generated
+func (this *Relocatable) EndLoop() {
+	if len(this.prev_loop_ends) == 0 {
+		err := errors.New("loop is not begun")
+		panic(err)
+	}
+
+	last := len(this.prev_loop_ends) - 1
+
+	this.cur_loop_condition = this.prev_loop_conditions[last]
+	this.cur_loop_body = this.prev_loop_bodies[last]
+	this.cur_loop_end = this.prev_loop_ends[last]
+
+	this.prev_loop_conditions = this.prev_loop_conditions[:last]
+	this.prev_loop_bodies = this.prev_loop_bodies[:last]
+	this.prev_loop_ends = this.prev_loop_ends[:last]
+}
+
+func (this *Relocatable) SaveLoop() {
+	this.prev_loop_conditions = append(this.prev_loop_conditions, this.cur_loop_condition)
+	this.prev_loop_bodies = append(this.prev_loop_bodies, this.cur_loop_body)
+	this.prev_loop_ends = append(this.prev_loop_ends, this.cur_loop_end)
+}
+
 func (this *Relocatable) HasLabel(label_name string) bool {
 	for _, label := range this.labels {
 		if label.Name() == label_name {
@@ -156,9 +205,24 @@ func (this *Relocatable) CurLabel() *Label {
 	return this.cur_label
 }
 
+func (this *Relocatable) CurPath() string {
+	return this.cur_path
+}
+
+func (this *Relocatable) CurLine() int {
+	return this.cur_line
+}
+
+// NOTE: the bytecodes that are generated from now on are given the position until it changes again
+func (this *Relocatable) SetPosition(path string, line int) {
+	this.cur_path = path
+	this.cur_line = line
+}
+
 func (this *Relocatable) NewBytecode(op_code OpCode, args []int64, strs []string) {
 	bytecode := new(Bytecode)
 	bytecode.Init(op_code, args, strs)
+	bytecode.SetPosition(this.cur_path, this.cur_line)
 
 	this.cur_label.Append(bytecode)
 }
@@ -0,0 +1,1784 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"uPIMulator/src/host/interpreter/codegen/type_system"
+	"uPIMulator/src/host/interpreter/lexer"
+	"uPIMulator/src/host/interpreter/parser"
+	"uPIMulator/src/host/interpreter/parser/decl"
+	"uPIMulator/src/host/interpreter/parser/directive"
+	"uPIMulator/src/host/interpreter/parser/expr"
+	"uPIMulator/src/host/interpreter/parser/param_list"
+	"uPIMulator/src/host/interpreter/parser/stmt"
+	"uPIMulator/src/host/interpreter/parser/type_specifier"
+)
+
+// NOTE: the checker is a semantic pass between the parser and the codegen, which reports the
+// errors that the codegen would otherwise hit as a panic without a position or not at all. A type
+// that the checker does not know, e.g. the type of an undeclared identifier, is nil, so that an
+// error is reported once and not for every expression that it is part of.
+type Checker struct {
+	type_system *type_system.TypeSystem
+	builtins    *type_system.TypeSystem
+	constants   map[string]bool
+
+	global_scope *Scope
+	scope        *Scope
+
+	func_defs  map[string]bool
+	method     *type_system.Method
+	undeclared map[string]bool
+
+	loop_depth   int
+	switch_depth int
+
+	diagnostics []*lexer.Diagnostic
+}
+
+func (this *Checker) Init() {
+	this.type_system = new(type_system.TypeSystem)
+	this.type_system.Init()
+
+	this.InitBuiltins()
+	this.InitConstants()
+
+	this.global_scope = new(Scope)
+	this.global_scope.Init(nil)
+	this.scope = this.global_scope
+
+	this.func_defs = make(map[string]bool)
+	this.method = nil
+	this.undeclared = make(map[string]bool)
+
+	this.loop_depth = 0
+	this.switch_depth = 0
+
+	this.diagnostics = make([]*lexer.Diagnostic, 0)
+}
+
+// NOTE: the builtins are the functions that the codegen translates into bytecodes of their own,
+// with the signatures of the C library and of the UPMEM SDK, except that the calls of the C library
+// whose results the host programs ignore, e.g. printf and gettimeofday, are void
+func (this *Checker) InitBuiltins() {
+	this.builtins = new(type_system.TypeSystem)
+	this.builtins.Init()
+
+	dpu_set := this.NewStruct("dpu_set_t", 0)
+	dpu_set_pointer := this.NewStruct("dpu_set_t", 1)
+	void_pointer := this.NewPrimitive(type_system.VOID, 1)
+	char_pointer := this.NewPrimitive(type_system.CHAR, 1)
+	int_pointer := this.NewPrimitive(type_system.INT, 1)
+	int_ := this.NewPrimitive(type_system.INT, 0)
+	long_ := this.NewPrimitive(type_system.LONG, 0)
+	double_ := this.NewPrimitive(type_system.DOUBLE, 0)
+	void_ := this.NewPrimitive(type_system.VOID, 0)
+
+	this.AddBuiltin("malloc", void_pointer, long_)
+	this.AddBuiltin("free", void_, void_pointer)
+	this.AddBuiltin("assert", void_, int_)
+	this.AddBuiltin("sqrt", double_, double_)
+
+	this.AddBuiltin("printf", void_, char_pointer).SetVariadic()
+	this.AddBuiltin("fprintf", void_, int_, char_pointer).SetVariadic()
+	this.AddBuiltin("exit", void_, int_)
+	this.AddBuiltin("atoi", int_, char_pointer)
+	this.AddBuiltin("rand", int_)
+	this.AddBuiltin("srand", void_, int_)
+	this.AddBuiltin("gettimeofday", void_, this.NewStruct("timeval", 1), void_pointer)
+	this.AddBuiltin("getopt", int_, int_, this.NewPrimitive(type_system.CHAR, 2), char_pointer)
+
+	this.AddBuiltin("dpu_alloc", int_, int_, char_pointer, dpu_set_pointer)
+	this.AddBuiltin("dpu_alloc_ranks", int_, int_, char_pointer, dpu_set_pointer)
+	this.AddBuiltin("dpu_load", int_, dpu_set, char_pointer, void_pointer)
+	this.AddBuiltin("dpu_prepare_xfer", int_, dpu_set, void_pointer)
+	this.AddBuiltin("dpu_push_xfer", int_, dpu_set, int_, char_pointer, int_, long_, int_)
+	this.AddBuiltin("dpu_broadcast_to", int_, dpu_set, char_pointer, int_, void_pointer, long_, int_)
+	this.AddBuiltin("dpu_copy_to", int_, dpu_set, char_pointer, int_, void_pointer, long_)
+	this.AddBuiltin("dpu_copy_from", int_, dpu_set, char_pointer, int_, void_pointer, long_)
+	this.AddBuiltin("dpu_launch", int_, dpu_set, int_)
+	this.AddBuiltin("dpu_sync", int_, dpu_set)
+	this.AddBuiltin("dpu_free", int_, dpu_set)
+	this.AddBuiltin("dpu_get_nr_dpus", int_, dpu_set, int_pointer)
+	this.AddBuiltin("dpu_get_nr_ranks", int_, dpu_set, int_pointer)
+	this.AddBuiltin("dpu_log_read", int_, dpu_set, void_pointer)
+	this.AddBuiltin("dpu_callback", int_, dpu_set, void_pointer, void_pointer, int_)
+}
+
+func (this *Checker) AddBuiltin(
+	method_name string,
+	return_type *type_system.Symbol,
+	param_types ...*type_system.Symbol,
+) *type_system.Method {
+	method := new(type_system.Method)
+	method.Init(this.Rename(return_type, method_name))
+
+	for i, param_type := range param_types {
+		method.AppendParam(this.Rename(param_type, fmt.Sprintf("__arg%d", i)))
+	}
+
+	this.builtins.AddMethod(method)
+
+	return method
+}
+
+func (this *Checker) InitConstants() {
+	this.constants = make(map[string]bool)
+
+	constant_names := []string{
+		"DPU_XFER_TO_DPU", "DPU_XFER_FROM_DPU", "DPU_MRAM_HEAP_POINTER_NAME", "DPU_XFER_DEFAULT",
+		"DPU_SYNCHRONOUS", "DPU_ASYNCHRONOUS", "DPU_XFER_NO_RESET", "DPU_ALLOCATE_ALL",
+		"DPU_CALLBACK_DEFAULT", "DPU_CALLBACK_ASYNC", "DPU_CALLBACK_NONBLOCKING",
+		"DPU_CALLBACK_SINGLE_CALL", "DPU_OK", "stdout", "stderr", "RAND_MAX",
+	}
+	for _, constant_name := range constant_names {
+		this.constants[constant_name] = true
+	}
+}
+
+func (this *Checker) Check(ast *parser.Ast) {
+	this.CheckBootstrap()
+
+	for i := 0; i < ast.Length(); i++ {
+		stack_item := ast.Get(i)
+
+		if stack_item.StackItemType() == parser.DIRECTIVE {
+			this.CheckDirective(stack_item.Directive())
+		} else if stack_item.StackItemType() == parser.DECL {
+			this.CheckDecl(stack_item.Decl())
+		} else if stack_item.StackItemType() == parser.STMT {
+			this.CheckGlobalStmt(stack_item.Stmt())
+		} else {
+			err := errors.New("stack item is not directive, decl nor stmt")
+			panic(err)
+		}
+	}
+
+	if !this.func_defs["main"] {
+		this.Report(nil, "undefined reference to 'main'")
+	}
+
+	if len(this.diagnostics) > 0 {
+		messages := make([]string, 0)
+		for _, diagnostic := range this.diagnostics {
+			messages = append(messages, diagnostic.Stringify())
+		}
+
+		err := errors.New(strings.Join(messages, "\n"))
+		panic(err)
+	}
+}
+
+func (this *Checker) CheckBootstrap() {
+	struct_ := new(type_system.Struct)
+	struct_.Init("dpu_set_t")
+	struct_.AppendField(this.Rename(this.NewPrimitive(type_system.INT, 0), "handle"))
+
+	this.type_system.AddStruct(struct_)
+
+	timeval := new(type_system.Struct)
+	timeval.Init("timeval")
+	timeval.AppendField(this.Rename(this.NewPrimitive(type_system.LONG, 0), "tv_sec"))
+	timeval.AppendField(this.Rename(this.NewPrimitive(type_system.LONG, 0), "tv_usec"))
+
+	this.type_system.AddStruct(timeval)
+
+	this.global_scope.AddSymbol(this.Rename(this.NewPrimitive(type_system.CHAR, 1), "optarg"))
+}
+
+func (this *Checker) Report(token *lexer.Token, message string) {
+	diagnostic := new(lexer.Diagnostic)
+	diagnostic.InitToken(token, message)
+
+	this.diagnostics = append(this.diagnostics, diagnostic)
+}
+
+func (this *Checker) CheckDirective(directive_ *directive.Directive) {
+	if directive_.DirectiveType() == directive.INCLUDE {
+	} else if directive_.DirectiveType() == directive.DEFINE {
+		this.CheckDefineDirective(directive_.DefineDirective())
+	} else {
+		err := errors.New("directive type is not valid")
+		panic(err)
+	}
+}
+
+// NOTE: a #define of a number is a global int of the codegen and a #define of a string is only
+// expanded by the preprocessor, so that the latter is declared as a string for its uses that are
+// not expanded
+func (this *Checker) CheckDefineDirective(define_directive *directive.DefineDirective) {
+	lvalue := define_directive.Lvalue()
+	rvalue := define_directive.Rvalue()
+
+	if rvalue.ExprType() == expr.PRIMARY &&
+		rvalue.PrimaryExpr().PrimaryExprType() == expr.NUMBER {
+		this.global_scope.AddSymbol(
+			this.Rename(this.NewPrimitive(type_system.INT, 0), lvalue.Attribute()),
+		)
+	} else if rvalue.ExprType() == expr.PRIMARY &&
+		rvalue.PrimaryExpr().PrimaryExprType() == expr.STRING {
+		this.global_scope.AddSymbol(
+			this.Rename(this.NewPrimitive(type_system.CHAR, 1), lvalue.Attribute()),
+		)
+	} else {
+		err_msg := fmt.Sprintf("'#define %s' is neither a number nor a string", lvalue.Attribute())
+		this.Report(lvalue, err_msg)
+	}
+}
+
+func (this *Checker) CheckDecl(decl_ *decl.Decl) {
+	if decl_.DeclType() == decl.STRUCT_DEF {
+		this.CheckStructDef(decl_.StructDef())
+	} else if decl_.DeclType() == decl.FUNC_DECL {
+		func_decl := decl_.FuncDecl()
+		this.CheckFuncDecl(func_decl.TypeSpecifier(), func_decl.Identifier(), func_decl.ParamList())
+	} else if decl_.DeclType() == decl.FUNC_DEF {
+		this.CheckFuncDef(decl_.FuncDef())
+	} else {
+		err := errors.New("decl type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Checker) CheckStructDef(struct_def *decl.StructDef) {
+	struct_name := struct_def.Identifier().Attribute()
+
+	if this.type_system.HasStruct(struct_name) {
+		this.Report(struct_def.Identifier(), fmt.Sprintf("redefinition of 'struct %s'", struct_name))
+	}
+
+	struct_ := new(type_system.Struct)
+	struct_.Init(struct_name)
+
+	field_names := make(map[string]bool)
+	for i := 0; i < struct_def.Body().BlockStmt().Length(); i++ {
+		stmt_ := struct_def.Body().BlockStmt().Get(i)
+
+		var identifier *lexer.Token
+		var field *type_system.Symbol
+		if stmt_.StmtType() == stmt.VAR_DECL {
+			identifier = stmt_.VarDeclStmt().Identifier()
+			field = this.NewSymbol(stmt_.VarDeclStmt().TypeSpecifier(), identifier.Attribute())
+		} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+			identifier = stmt_.ArrayDeclStmt().Identifier()
+			field = this.NewSymbol(stmt_.ArrayDeclStmt().TypeSpecifier(), identifier.Attribute())
+
+			size := this.CheckValue(stmt_.ArrayDeclStmt().Size())
+			if size != nil && !this.IsInteger(size) {
+				err_msg := fmt.Sprintf("size of array '%s' has non-integer type", identifier.Attribute())
+				this.Report(stmt_.ArrayDeclStmt().Size().FirstToken(), err_msg)
+			}
+		} else {
+			this.Report(
+				stmt_.FirstToken(),
+				"field of a struct is declared with an initializer",
+			)
+			continue
+		}
+
+		field_name := identifier.Attribute()
+
+		if field_names[field_name] {
+			this.Report(identifier, fmt.Sprintf("duplicate member '%s'", field_name))
+		} else if this.IsVoidValue(field) {
+			this.Report(identifier, fmt.Sprintf("variable or field '%s' declared void", field_name))
+		} else if !this.IsComplete(field) {
+			this.Report(identifier, fmt.Sprintf("field '%s' has incomplete type", field_name))
+		}
+		field_names[field_name] = true
+
+		// NOTE: an array field is typed as a pointer to its elements, which it decays to
+		if stmt_.StmtType() == stmt.ARRAY_DECL {
+			field = this.Rename(this.Reference(field, field.NumStars()+1), field_name)
+		}
+
+		struct_.AppendField(field)
+	}
+
+	this.type_system.AddStruct(struct_)
+}
+
+func (this *Checker) CheckFuncDecl(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	identifier *lexer.Token,
+	param_list_ *param_list.ParamList,
+) *type_system.Method {
+	func_name := identifier.Attribute()
+
+	method := new(type_system.Method)
+	method.Init(this.NewSymbol(type_specifier_, func_name))
+
+	if !this.IsComplete(method.Symbol()) {
+		this.Report(identifier, "return type is an incomplete type")
+	}
+
+	for i := 0; i < param_list_.Length(); i++ {
+		param := param_list_.Get(i)
+		param_name := param.Identifier().Attribute()
+		symbol := this.NewSymbol(param.TypeSpecifier(), param_name)
+
+		if method.HasParam(param_name) {
+			this.Report(param.Identifier(), fmt.Sprintf("redefinition of parameter '%s'", param_name))
+		} else if this.IsVoidValue(symbol) {
+			err_msg := fmt.Sprintf("parameter %d ('%s') has void type", i+1, param_name)
+			this.Report(param.Identifier(), err_msg)
+		} else if !this.IsComplete(symbol) {
+			err_msg := fmt.Sprintf("parameter %d ('%s') has incomplete type", i+1, param_name)
+			this.Report(param.Identifier(), err_msg)
+		}
+
+		method.AppendParam(symbol)
+	}
+
+	if this.builtins.HasMethod(func_name) {
+		this.Report(identifier, fmt.Sprintf("conflicting types for builtin function '%s'", func_name))
+	} else if this.type_system.HasMethod(func_name) &&
+		!this.IsSameMethod(this.type_system.Method(func_name), method) {
+		this.Report(identifier, fmt.Sprintf("conflicting types for '%s'", func_name))
+	}
+
+	this.type_system.AddMethod(method)
+
+	return method
+}
+
+func (this *Checker) IsSameMethod(method *type_system.Method, other *type_system.Method) bool {
+	if !this.IsSameType(method.Symbol(), other.Symbol()) ||
+		len(method.Params()) != len(other.Params()) {
+		return false
+	}
+
+	for i := range method.Params() {
+		if !this.IsSameType(method.Params()[i], other.Params()[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *Checker) CheckFuncDef(func_def *decl.FuncDef) {
+	func_name := func_def.Identifier().Attribute()
+
+	if this.func_defs[func_name] {
+		this.Report(func_def.Identifier(), fmt.Sprintf("redefinition of '%s'", func_name))
+	}
+	this.func_defs[func_name] = true
+
+	this.method = this.CheckFuncDecl(
+		func_def.TypeSpecifier(),
+		func_def.Identifier(),
+		func_def.ParamList(),
+	)
+	this.undeclared = make(map[string]bool)
+
+	if func_name == "main" {
+		this.CheckMain(func_def.Identifier())
+	}
+
+	// NOTE: the params are declared in the scope of the body, so that a local of the body cannot
+	// redeclare them
+	this.EnterScope()
+	for _, param := range this.method.Params() {
+		this.scope.AddSymbol(param)
+	}
+
+	body := func_def.Body().BlockStmt()
+	for i := 0; i < body.Length(); i++ {
+		this.CheckStmt(body.Get(i))
+	}
+	this.ExitScope()
+
+	this.method = nil
+}
+
+// NOTE: main takes either no params or the argc and argv of the host args
+func (this *Checker) CheckMain(token *lexer.Token) {
+	params := this.method.Params()
+
+	if len(params) == 0 {
+		return
+	} else if len(params) != 2 {
+		this.Report(token, "'main' takes only zero or two arguments")
+		return
+	}
+
+	if !this.IsInteger(params[0]) {
+		this.Report(token, "first argument of 'main' should be 'int'")
+	}
+	if !this.IsSameType(params[1], this.NewPrimitive(type_system.CHAR, 2)) {
+		this.Report(token, "second argument of 'main' should be 'char **'")
+	}
+}
+
+func (this *Checker) EnterScope() {
+	scope := new(Scope)
+	scope.Init(this.scope)
+
+	this.scope = scope
+}
+
+func (this *Checker) ExitScope() {
+	this.scope = this.scope.Parent()
+}
+
+func (this *Checker) CheckGlobalStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() == stmt.VAR_DECL {
+		this.CheckVarDeclStmt(stmt_.VarDeclStmt())
+	} else if stmt_.StmtType() == stmt.VAR_DECL_INIT {
+		this.CheckVarDeclInitStmt(stmt_.VarDeclInitStmt())
+	} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+		this.CheckArrayDeclStmt(stmt_.ArrayDeclStmt())
+	} else if stmt_.StmtType() == stmt.DECL_LIST {
+		for i := 0; i < stmt_.DeclListStmt().Length(); i++ {
+			this.CheckGlobalStmt(stmt_.DeclListStmt().Get(i))
+		}
+	} else {
+		this.Report(stmt_.FirstToken(), "statement is not in a function")
+	}
+}
+
+func (this *Checker) CheckStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() == stmt.EMPTY {
+	} else if stmt_.StmtType() == stmt.VAR_DECL {
+		this.CheckVarDeclStmt(stmt_.VarDeclStmt())
+	} else if stmt_.StmtType() == stmt.VAR_DECL_INIT {
+		this.CheckVarDeclInitStmt(stmt_.VarDeclInitStmt())
+	} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+		this.CheckArrayDeclStmt(stmt_.ArrayDeclStmt())
+	} else if stmt_.StmtType() == stmt.FOR {
+		this.CheckForStmt(stmt_.ForStmt())
+	} else if stmt_.StmtType() == stmt.DPU_FOREACH {
+		this.CheckDpuForeachStmt(stmt_, stmt_.DpuForeachStmt())
+	} else if stmt_.StmtType() == stmt.WHILE {
+		this.CheckCondition(stmt_.WhileStmt().Condition())
+		this.CheckLoopBody(stmt_.WhileStmt().Body())
+	} else if stmt_.StmtType() == stmt.DO_WHILE {
+		this.CheckLoopBody(stmt_.DoWhileStmt().Body())
+		this.CheckCondition(stmt_.DoWhileStmt().Condition())
+	} else if stmt_.StmtType() == stmt.SWITCH {
+		this.CheckSwitchStmt(stmt_.SwitchStmt())
+	} else if stmt_.StmtType() == stmt.CASE {
+		this.Report(stmt_.FirstToken(), "case label not within a switch statement")
+	} else if stmt_.StmtType() == stmt.DEFAULT {
+		this.Report(stmt_.FirstToken(), "'default' label not within a switch statement")
+	} else if stmt_.StmtType() == stmt.CONTINUE {
+		if this.loop_depth == 0 {
+			this.Report(stmt_.FirstToken(), "continue statement not within a loop")
+		}
+	} else if stmt_.StmtType() == stmt.BREAK {
+		if this.loop_depth == 0 && this.switch_depth == 0 {
+			this.Report(stmt_.FirstToken(), "break statement not within loop or switch")
+		}
+	} else if stmt_.StmtType() == stmt.IF {
+		this.CheckIfStmt(stmt_.IfStmt())
+	} else if stmt_.StmtType() == stmt.RETURN {
+		this.CheckReturnStmt(stmt_, stmt_.ReturnStmt())
+	} else if stmt_.StmtType() == stmt.EXPR {
+		this.CheckExpr(stmt_.ExprStmt().Expr())
+	} else if stmt_.StmtType() == stmt.BLOCK {
+		this.CheckBlockStmt(stmt_.BlockStmt())
+	} else if stmt_.StmtType() == stmt.DECL_LIST {
+		for i := 0; i < stmt_.DeclListStmt().Length(); i++ {
+			this.CheckStmt(stmt_.DeclListStmt().Get(i))
+		}
+	} else {
+		err := errors.New("stmt type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Checker) CheckVarDeclStmt(var_decl_stmt *stmt.VarDeclStmt) {
+	type_specifier_ := var_decl_stmt.TypeSpecifier()
+
+	this.Declare(type_specifier_, type_specifier_.NumStars(), var_decl_stmt.Identifier())
+}
+
+func (this *Checker) CheckVarDeclInitStmt(var_decl_init_stmt *stmt.VarDeclInitStmt) {
+	type_specifier_ := var_decl_init_stmt.TypeSpecifier()
+
+	symbol := this.Declare(
+		type_specifier_,
+		type_specifier_.NumStars(),
+		var_decl_init_stmt.Identifier(),
+	)
+	this.CheckInitializer(symbol, var_decl_init_stmt.Expr())
+}
+
+func (this *Checker) CheckArrayDeclStmt(array_decl_stmt *stmt.ArrayDeclStmt) {
+	type_specifier_ := array_decl_stmt.TypeSpecifier()
+	identifier := array_decl_stmt.Identifier()
+
+	if array_decl_stmt.HasSize() {
+		size := this.CheckValue(array_decl_stmt.Size())
+
+		if size != nil && !this.IsInteger(size) {
+			err_msg := fmt.Sprintf("size of array '%s' has non-integer type", identifier.Attribute())
+			this.Report(array_decl_stmt.Size().FirstToken(), err_msg)
+		}
+	}
+
+	this.Declare(type_specifier_, type_specifier_.NumStars()+1, identifier)
+
+	if !array_decl_stmt.HasInitializer() {
+		return
+	}
+
+	element := this.NewSymbol(type_specifier_, "")
+	if !this.IsComplete(element) || this.IsVoidValue(element) {
+		element = nil
+	}
+
+	initializer := array_decl_stmt.Initializer()
+	if initializer.ExprType() == expr.PRIMARY &&
+		initializer.PrimaryExpr().PrimaryExprType() == expr.STRING {
+		if element != nil && !this.IsInteger(element) {
+			this.Report(
+				initializer.FirstToken(),
+				"array of inappropriate type initialized from string constant",
+			)
+		}
+	} else if initializer.ExprType() == expr.PRIMARY &&
+		initializer.PrimaryExpr().PrimaryExprType() == expr.INITIALIZER_LIST {
+		arg_list := initializer.PrimaryExpr().ArgList()
+
+		for i := 0; i < arg_list.Length(); i++ {
+			this.CheckInitializer(element, arg_list.Get(i))
+		}
+	} else {
+		this.Report(
+			initializer.FirstToken(),
+			"array must be initialized with a brace-enclosed initializer",
+		)
+		this.CheckValue(initializer)
+	}
+}
+
+// NOTE: Declare returns nil for a symbol of an invalid type, so that neither its initializer nor
+// its uses are checked against it
+func (this *Checker) Declare(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	num_stars int,
+	identifier *lexer.Token,
+) *type_system.Symbol {
+	symbol_name := identifier.Attribute()
+	symbol := this.NewSymbol(type_specifier_, symbol_name)
+	symbol = this.Rename(this.Reference(symbol, num_stars), symbol_name)
+
+	if this.scope.HasLocalSymbol(symbol_name) {
+		if this.scope == this.global_scope {
+			this.Report(identifier, fmt.Sprintf("redefinition of '%s'", symbol_name))
+		} else {
+			this.Report(identifier, fmt.Sprintf("redeclaration of '%s'", symbol_name))
+		}
+	}
+
+	this.scope.AddSymbol(symbol)
+
+	if this.IsVoidValue(symbol) {
+		this.Report(identifier, fmt.Sprintf("variable or field '%s' declared void", symbol_name))
+		return nil
+	} else if !this.IsComplete(symbol) {
+		this.Report(identifier, fmt.Sprintf("storage size of '%s' isn't known", symbol_name))
+		return nil
+	}
+	return symbol
+}
+
+// NOTE: CheckInitializer checks an initializer of a symbol or of an element of an array, where an
+// initializer list of a struct is checked field by field
+func (this *Checker) CheckInitializer(symbol *type_system.Symbol, initializer *expr.Expr) {
+	if initializer.ExprType() != expr.PRIMARY ||
+		initializer.PrimaryExpr().PrimaryExprType() != expr.INITIALIZER_LIST {
+		value := this.CheckValue(initializer)
+
+		if !this.IsAssignable(symbol, value) {
+			err_msg := fmt.Sprintf(
+				"incompatible types when initializing type '%s' using type '%s'",
+				this.Spell(symbol),
+				this.Spell(value),
+			)
+			this.Report(initializer.FirstToken(), err_msg)
+		}
+		return
+	}
+
+	arg_list := initializer.PrimaryExpr().ArgList()
+
+	if symbol == nil || !this.IsComplete(symbol) {
+		for i := 0; i < arg_list.Length(); i++ {
+			this.CheckInitializer(nil, arg_list.Get(i))
+		}
+	} else if this.IsStructValue(symbol) {
+		struct_ := this.type_system.Struct(symbol.StructName())
+
+		for i := 0; i < arg_list.Length(); i++ {
+			if i < struct_.Length() {
+				this.CheckInitializer(struct_.Get(i), arg_list.Get(i))
+			} else {
+				this.Report(arg_list.Get(i).FirstToken(), "excess elements in struct initializer")
+				break
+			}
+		}
+	} else if arg_list.Length() == 0 {
+		this.Report(initializer.FirstToken(), "empty scalar initializer")
+	} else {
+		this.CheckInitializer(symbol, arg_list.Get(0))
+
+		if arg_list.Length() > 1 {
+			this.Report(arg_list.Get(1).FirstToken(), "excess elements in scalar initializer")
+		}
+	}
+}
+
+func (this *Checker) CheckForStmt(for_stmt *stmt.ForStmt) {
+	this.EnterScope()
+
+	this.CheckStmt(for_stmt.Initialization())
+	this.CheckCondition(for_stmt.Condition())
+	this.CheckStmt(for_stmt.Update())
+	this.CheckLoopBody(for_stmt.Body())
+
+	this.ExitScope()
+}
+
+// NOTE: the DPU (or the rank) and the index of DPU_FOREACH are declared in the scope of the loop,
+// where they shadow the variables of the host program
+func (this *Checker) CheckDpuForeachStmt(stmt_ *stmt.Stmt, dpu_foreach_stmt *stmt.DpuForeachStmt) {
+	foreach := dpu_foreach_stmt.Foreach()
+
+	macro_name := "DPU_FOREACH"
+	if dpu_foreach_stmt.IsRank() {
+		macro_name = "DPU_RANK_FOREACH"
+	}
+
+	if foreach.Length() != 2 && foreach.Length() != 3 {
+		err_msg := fmt.Sprintf(
+			"%s takes 2 or 3 arguments, but %d are given",
+			macro_name,
+			foreach.Length(),
+		)
+		this.Report(stmt_.FirstToken(), err_msg)
+		return
+	}
+
+	this.CheckArg(macro_name, 0, this.NewStruct("dpu_set_t", 0), foreach.Get(0))
+
+	this.EnterScope()
+
+	for i := 1; i < foreach.Length(); i++ {
+		arg := foreach.Get(i)
+
+		if arg.ExprType() != expr.PRIMARY || arg.PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
+			err_msg := fmt.Sprintf("argument %d of '%s' is not an identifier", i+1, macro_name)
+			this.Report(arg.FirstToken(), err_msg)
+			continue
+		}
+
+		symbol_name := arg.PrimaryExpr().Token().Attribute()
+		if i == 1 {
+			this.scope.AddSymbol(this.Rename(this.NewStruct("dpu_set_t", 0), symbol_name))
+		} else {
+			this.scope.AddSymbol(this.Rename(this.NewPrimitive(type_system.INT, 0), symbol_name))
+		}
+	}
+
+	this.CheckLoopBody(dpu_foreach_stmt.Body())
+
+	this.ExitScope()
+}
+
+func (this *Checker) CheckLoopBody(body *stmt.Stmt) {
+	this.loop_depth++
+	this.CheckStmt(body)
+	this.loop_depth--
+}
+
+func (this *Checker) CheckSwitchStmt(switch_stmt *stmt.SwitchStmt) {
+	condition := this.CheckValue(switch_stmt.Condition())
+	if condition != nil && !this.IsInteger(condition) {
+		this.Report(switch_stmt.Condition().FirstToken(), "switch quantity not an integer")
+	}
+
+	this.switch_depth++
+	this.EnterScope()
+
+	body := switch_stmt.Body().BlockStmt()
+	for i := 0; i < body.Length(); i++ {
+		stmt_ := body.Get(i)
+
+		if stmt_.StmtType() == stmt.CASE {
+			value := this.CheckValue(stmt_.CaseStmt().Value())
+
+			if value != nil && !this.IsInteger(value) {
+				this.Report(
+					stmt_.CaseStmt().Value().FirstToken(),
+					"case label does not reduce to an integer constant",
+				)
+			}
+		} else if stmt_.StmtType() == stmt.DEFAULT {
+		} else {
+			this.CheckStmt(stmt_)
+		}
+	}
+
+	this.ExitScope()
+	this.switch_depth--
+}
+
+func (this *Checker) CheckIfStmt(if_stmt *stmt.IfStmt) {
+	this.CheckCondition(if_stmt.IfCondition())
+	this.CheckStmt(if_stmt.IfBody())
+
+	for i := 0; i < if_stmt.NumElseIfs(); i++ {
+		this.CheckCondition(if_stmt.ElseIfCondition(i))
+		this.CheckStmt(if_stmt.ElseIfBody(i))
+	}
+
+	if if_stmt.HasElseBody() {
+		this.CheckStmt(if_stmt.ElseBody())
+	}
+}
+
+func (this *Checker) CheckReturnStmt(stmt_ *stmt.Stmt, return_stmt *stmt.ReturnStmt) {
+	if !return_stmt.HasValue() {
+		return
+	}
+
+	return_type := this.method.Symbol()
+
+	if this.IsVoidValue(return_type) {
+		this.Report(stmt_.FirstToken(), "'return' with a value, in function returning void")
+		this.CheckExpr(return_stmt.Value())
+		return
+	}
+
+	value := this.CheckValue(return_stmt.Value())
+	if !this.IsAssignable(return_type, value) {
+		err_msg := fmt.Sprintf(
+			"incompatible types when returning type '%s' but '%s' was expected",
+			this.Spell(value),
+			this.Spell(return_type),
+		)
+		this.Report(return_stmt.Value().FirstToken(), err_msg)
+	}
+}
+
+func (this *Checker) CheckBlockStmt(block_stmt *stmt.BlockStmt) {
+	this.EnterScope()
+
+	for i := 0; i < block_stmt.Length(); i++ {
+		this.CheckStmt(block_stmt.Get(i))
+	}
+
+	this.ExitScope()
+}
+
+func (this *Checker) CheckCondition(condition *expr.Expr) {
+	value := this.CheckValue(condition)
+
+	if value != nil && !this.IsScalar(value) {
+		this.Report(condition.FirstToken(), "used struct type value where scalar is required")
+	}
+}
+
+// NOTE: CheckValue checks an expr whose value is used, which cannot be void unlike the value of an
+// expr stmt
+func (this *Checker) CheckValue(expr_ *expr.Expr) *type_system.Symbol {
+	value := this.CheckExpr(expr_)
+
+	if value != nil && this.IsVoidValue(value) {
+		this.Report(expr_.FirstToken(), "void value not ignored as it ought to be")
+		return nil
+	}
+	return value
+}
+
+func (this *Checker) CheckExpr(expr_ *expr.Expr) *type_system.Symbol {
+	if expr_.ExprType() == expr.PRIMARY {
+		return this.CheckPrimaryExpr(expr_)
+	} else if expr_.ExprType() == expr.POSTFIX {
+		return this.CheckPostfixExpr(expr_)
+	} else if expr_.ExprType() == expr.UNARY {
+		return this.CheckUnaryExpr(expr_)
+	} else if expr_.ExprType() == expr.MULTIPLICATIVE {
+		return this.CheckMultiplicativeExpr(expr_)
+	} else if expr_.ExprType() == expr.ADDITIVE {
+		return this.CheckAdditiveExpr(expr_)
+	} else if expr_.ExprType() == expr.SHIFT {
+		shift_expr := expr_.ShiftExpr()
+
+		op := "<<"
+		if shift_expr.ShiftExprType() == expr.RSHIFT {
+			op = ">>"
+		}
+
+		return this.CheckIntegerOperands(expr_, op, shift_expr.Loperand(), shift_expr.Roperand())
+	} else if expr_.ExprType() == expr.RELATIONAL {
+		relational_expr := expr_.RelationalExpr()
+
+		op := ""
+		if relational_expr.RelationalExprType() == expr.LESS {
+			op = "<"
+		} else if relational_expr.RelationalExprType() == expr.LESS_EQ {
+			op = "<="
+		} else if relational_expr.RelationalExprType() == expr.GREATER {
+			op = ">"
+		} else if relational_expr.RelationalExprType() == expr.GREATER_EQ {
+			op = ">="
+		} else {
+			err := errors.New("relational expr type is not valid")
+			panic(err)
+		}
+
+		return this.CheckComparison(expr_, op, relational_expr.Loperand(), relational_expr.Roperand())
+	} else if expr_.ExprType() == expr.EQUALITY {
+		equality_expr := expr_.EqualityExpr()
+
+		op := "=="
+		if equality_expr.EqualityExprType() == expr.NOT_EQ {
+			op = "!="
+		}
+
+		return this.CheckComparison(expr_, op, equality_expr.Loperand(), equality_expr.Roperand())
+	} else if expr_.ExprType() == expr.BITWISE_AND {
+		bitwise_and_expr := expr_.BitwiseAndExpr()
+		return this.CheckIntegerOperands(
+			expr_,
+			"&",
+			bitwise_and_expr.Loperand(),
+			bitwise_and_expr.Roperand(),
+		)
+	} else if expr_.ExprType() == expr.BITWISE_XOR {
+		bitwise_xor_expr := expr_.BitwiseXorExpr()
+		return this.CheckIntegerOperands(
+			expr_,
+			"^",
+			bitwise_xor_expr.Loperand(),
+			bitwise_xor_expr.Roperand(),
+		)
+	} else if expr_.ExprType() == expr.BITWISE_OR {
+		bitwise_or_expr := expr_.BitwiseOrExpr()
+		return this.CheckIntegerOperands(
+			expr_,
+			"|",
+			bitwise_or_expr.Loperand(),
+			bitwise_or_expr.Roperand(),
+		)
+	} else if expr_.ExprType() == expr.LOGICAL_AND {
+		this.CheckCondition(expr_.LogicalAndExpr().Loperand())
+		this.CheckCondition(expr_.LogicalAndExpr().Roperand())
+		return this.NewPrimitive(type_system.INT, 0)
+	} else if expr_.ExprType() == expr.LOGICAL_OR {
+		this.CheckCondition(expr_.LogicalOrExpr().Loperand())
+		this.CheckCondition(expr_.LogicalOrExpr().Roperand())
+		return this.NewPrimitive(type_system.INT, 0)
+	} else if expr_.ExprType() == expr.CONDITIONAL {
+		return this.CheckConditionalExpr(expr_)
+	} else if expr_.ExprType() == expr.ASSIGNMENT {
+		return this.CheckAssignmentExpr(expr_)
+	} else {
+		err := errors.New("expr type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Checker) CheckPrimaryExpr(expr_ *expr.Expr) *type_system.Symbol {
+	primary_expr := expr_.PrimaryExpr()
+
+	if primary_expr.PrimaryExprType() == expr.IDENTIFIER {
+		return this.CheckIdentifier(primary_expr.Token())
+	} else if primary_expr.PrimaryExprType() == expr.NUMBER {
+		token := primary_expr.Token()
+
+		var value *type_system.Symbol
+		if token.IsLong() || token.Integer() > math.MaxInt32 {
+			value = this.NewPrimitive(type_system.LONG, 0)
+		} else {
+			value = this.NewPrimitive(type_system.INT, 0)
+		}
+
+		if token.IsUnsigned() {
+			value.SetUnsigned()
+		}
+		return value
+	} else if primary_expr.PrimaryExprType() == expr.FLOATING {
+		if primary_expr.Token().IsFloat() {
+			return this.NewPrimitive(type_system.FLOAT, 0)
+		} else {
+			return this.NewPrimitive(type_system.DOUBLE, 0)
+		}
+	} else if primary_expr.PrimaryExprType() == expr.CHARACTER {
+		return this.NewPrimitive(type_system.INT, 0)
+	} else if primary_expr.PrimaryExprType() == expr.STRING {
+		return this.NewPrimitive(type_system.CHAR, 1)
+	} else if primary_expr.PrimaryExprType() == expr.NULLPTR {
+		return this.NewPrimitive(type_system.VOID, 1)
+	} else if primary_expr.PrimaryExprType() == expr.PAREN {
+		return this.CheckExpr(primary_expr.Expr())
+	} else if primary_expr.PrimaryExprType() == expr.INITIALIZER_LIST {
+		this.Report(expr_.FirstToken(), "initializer list is not in a declaration")
+		return nil
+	} else {
+		err := errors.New("primary expr type is not valid")
+		panic(err)
+	}
+}
+
+// NOTE: an undeclared identifier is reported once for each function that it appears in
+func (this *Checker) CheckIdentifier(token *lexer.Token) *type_system.Symbol {
+	symbol_name := token.Attribute()
+
+	if this.scope.HasSymbol(symbol_name) {
+		symbol := this.scope.Symbol(symbol_name)
+
+		if this.IsVoidValue(symbol) || !this.IsComplete(symbol) {
+			return nil
+		}
+		return symbol
+	} else if this.constants[symbol_name] {
+		return this.NewPrimitive(type_system.INT, 0)
+	} else if this.type_system.HasMethod(symbol_name) || this.builtins.HasMethod(symbol_name) {
+		this.Report(token, fmt.Sprintf("function '%s' is used as a value", symbol_name))
+		return nil
+	}
+
+	if !this.undeclared[symbol_name] {
+		this.Report(token, fmt.Sprintf("'%s' undeclared", symbol_name))
+		this.undeclared[symbol_name] = true
+	}
+	return nil
+}
+
+func (this *Checker) CheckPostfixExpr(expr_ *expr.Expr) *type_system.Symbol {
+	postfix_expr := expr_.PostfixExpr()
+
+	if postfix_expr.PostfixExprType() == expr.BRACKET {
+		base := this.CheckValue(postfix_expr.Base())
+		offset := this.CheckValue(postfix_expr.OffsetExpr())
+
+		if offset != nil && !this.IsInteger(offset) {
+			this.Report(postfix_expr.OffsetExpr().FirstToken(), "array subscript is not an integer")
+		}
+
+		if base == nil {
+			return nil
+		} else if !this.IsPointer(base) {
+			this.Report(expr_.FirstToken(), "subscripted value is neither array nor pointer")
+			return nil
+		}
+		return this.Reference(base, base.NumStars()-1)
+	} else if postfix_expr.PostfixExprType() == expr.CALL {
+		return this.CheckCall(expr_)
+	} else if postfix_expr.PostfixExprType() == expr.DOT {
+		base := this.CheckValue(postfix_expr.Base())
+		field_token := postfix_expr.OffsetToken()
+
+		if base == nil {
+			return nil
+		} else if base.SymbolType() == type_system.STRUCT && base.NumStars() == 1 {
+			err_msg := fmt.Sprintf("'%s' is a pointer; did you mean to use '->'?", this.Spell(base))
+			this.Report(field_token, err_msg)
+			return nil
+		} else if !this.IsStructValue(base) {
+			err_msg := fmt.Sprintf(
+				"request for member '%s' in something not a structure",
+				field_token.Attribute(),
+			)
+			this.Report(field_token, err_msg)
+			return nil
+		}
+		return this.Field(base.StructName(), field_token)
+	} else if postfix_expr.PostfixExprType() == expr.ARROW {
+		base := this.CheckValue(postfix_expr.Base())
+		field_token := postfix_expr.OffsetToken()
+
+		if base == nil {
+			return nil
+		} else if base.SymbolType() != type_system.STRUCT || base.NumStars() != 1 {
+			err_msg := fmt.Sprintf("invalid type argument of '->' (have '%s')", this.Spell(base))
+			this.Report(field_token, err_msg)
+			return nil
+		}
+		return this.Field(base.StructName(), field_token)
+	} else if postfix_expr.PostfixExprType() == expr.POSTFIX_PLUS_PLUS {
+		return this.CheckIncrement(postfix_expr.Base(), "increment")
+	} else if postfix_expr.PostfixExprType() == expr.POSTFIX_MINUS_MINUS {
+		return this.CheckIncrement(postfix_expr.Base(), "decrement")
+	} else {
+		err := errors.New("postfix expr type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Checker) Field(struct_name string, field_token *lexer.Token) *type_system.Symbol {
+	if !this.type_system.HasStruct(struct_name) {
+		err_msg := fmt.Sprintf("invalid use of undefined type 'struct %s'", struct_name)
+		this.Report(field_token, err_msg)
+		return nil
+	}
+
+	struct_ := this.type_system.Struct(struct_name)
+	for i := 0; i < struct_.Length(); i++ {
+		if struct_.Get(i).Name() == field_token.Attribute() {
+			return struct_.Get(i)
+		}
+	}
+
+	err_msg := fmt.Sprintf(
+		"'struct %s' has no member named '%s'",
+		struct_name,
+		field_token.Attribute(),
+	)
+	this.Report(field_token, err_msg)
+	return nil
+}
+
+func (this *Checker) CheckCall(expr_ *expr.Expr) *type_system.Symbol {
+	postfix_expr := expr_.PostfixExpr()
+	base := postfix_expr.Base()
+	arg_list := postfix_expr.ArgList()
+
+	if base.ExprType() != expr.PRIMARY || base.PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
+		this.Report(expr_.FirstToken(), "called object is not a function")
+		this.CheckArgs(arg_list)
+		return nil
+	}
+
+	func_token := base.PrimaryExpr().Token()
+	func_name := func_token.Attribute()
+
+	var method *type_system.Method
+	if this.builtins.HasMethod(func_name) {
+		method = this.builtins.Method(func_name)
+	} else if this.type_system.HasMethod(func_name) {
+		method = this.type_system.Method(func_name)
+	} else if this.scope.HasSymbol(func_name) || this.constants[func_name] {
+		this.Report(func_token, fmt.Sprintf("called object '%s' is not a function", func_name))
+		this.CheckArgs(arg_list)
+		return nil
+	} else {
+		this.Report(func_token, fmt.Sprintf("implicit declaration of function '%s'", func_name))
+		this.CheckArgs(arg_list)
+		return nil
+	}
+
+	params := method.Params()
+	if arg_list.Length() < len(params) {
+		this.Report(func_token, fmt.Sprintf("too few arguments to function '%s'", func_name))
+	} else if arg_list.Length() > len(params) && !method.IsVariadic() {
+		this.Report(func_token, fmt.Sprintf("too many arguments to function '%s'", func_name))
+	}
+
+	for i := 0; i < arg_list.Length(); i++ {
+		arg := arg_list.Get(i)
+
+		// NOTE: the codegen only evaluates the DPU set of dpu_load and dpu_log_read, so that e.g.
+		// DPU_BINARY need not be declared
+		if (func_name == "dpu_load" || func_name == "dpu_log_read") && i > 0 {
+			continue
+		} else if func_name == "dpu_callback" && i == 1 {
+			this.CheckCallback(arg)
+			continue
+		}
+
+		if i < len(params) {
+			this.CheckArg(func_name, i, params[i], arg)
+		} else {
+			this.CheckValue(arg)
+		}
+	}
+
+	return method.Symbol()
+}
+
+func (this *Checker) CheckArg(
+	func_name string,
+	pos int,
+	param *type_system.Symbol,
+	arg *expr.Expr,
+) {
+	value := this.CheckValue(arg)
+
+	if !this.IsAssignable(param, value) {
+		err_msg := fmt.Sprintf(
+			"incompatible type for argument %d of '%s' (expected '%s' but argument is of type '%s')",
+			pos+1,
+			func_name,
+			this.Spell(param),
+			this.Spell(value),
+		)
+		this.Report(arg.FirstToken(), err_msg)
+	}
+}
+
+func (this *Checker) CheckArgs(arg_list *expr.ArgList) {
+	for i := 0; i < arg_list.Length(); i++ {
+		this.CheckValue(arg_list.Get(i))
+	}
+}
+
+// NOTE: the callback of dpu_callback is a function of the host program that takes the DPU set,
+// the index of the DPU (or of the rank) and the args
+func (this *Checker) CheckCallback(arg *expr.Expr) {
+	if arg.ExprType() != expr.PRIMARY || arg.PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
+		this.Report(arg.FirstToken(), "argument 2 of 'dpu_callback' is not a function name")
+		return
+	}
+
+	func_token := arg.PrimaryExpr().Token()
+	func_name := func_token.Attribute()
+
+	if !this.type_system.HasMethod(func_name) {
+		if this.scope.HasSymbol(func_name) || this.constants[func_name] {
+			this.Report(func_token, "argument 2 of 'dpu_callback' is not a function name")
+		} else {
+			this.Report(func_token, fmt.Sprintf("'%s' undeclared", func_name))
+		}
+	} else if len(this.type_system.Method(func_name).Params()) != 3 {
+		err_msg := fmt.Sprintf("callback '%s' of 'dpu_callback' does not take 3 arguments", func_name)
+		this.Report(func_token, err_msg)
+	}
+}
+
+func (this *Checker) CheckIncrement(base *expr.Expr, op string) *type_system.Symbol {
+	value := this.CheckValue(base)
+
+	if !this.IsLvalue(base) {
+		this.Report(base.FirstToken(), fmt.Sprintf("lvalue required as %s operand", op))
+		return value
+	} else if value != nil && !this.IsScalar(value) {
+		this.Report(base.FirstToken(), fmt.Sprintf("wrong type argument to %s", op))
+		return nil
+	}
+	return value
+}
+
+func (this *Checker) CheckUnaryExpr(expr_ *expr.Expr) *type_system.Symbol {
+	unary_expr := expr_.UnaryExpr()
+
+	if unary_expr.UnaryExprType() == expr.UNARY_PLUS_PLUS {
+		return this.CheckIncrement(unary_expr.Base(), "increment")
+	} else if unary_expr.UnaryExprType() == expr.UNARY_MINUS_MINUS {
+		return this.CheckIncrement(unary_expr.Base(), "decrement")
+	} else if unary_expr.UnaryExprType() == expr.AND {
+		value := this.CheckValue(unary_expr.Base())
+
+		if !this.IsLvalue(unary_expr.Base()) {
+			this.Report(expr_.FirstToken(), "lvalue required as unary '&' operand")
+			return nil
+		} else if value == nil {
+			return nil
+		}
+		return this.Reference(value, value.NumStars()+1)
+	} else if unary_expr.UnaryExprType() == expr.STAR {
+		value := this.CheckValue(unary_expr.Base())
+
+		if value == nil {
+			return nil
+		} else if !this.IsPointer(value) {
+			err_msg := fmt.Sprintf("invalid type argument of unary '*' (have '%s')", this.Spell(value))
+			this.Report(expr_.FirstToken(), err_msg)
+			return nil
+		}
+		return this.Reference(value, value.NumStars()-1)
+	} else if unary_expr.UnaryExprType() == expr.PLUS {
+		return this.CheckUnaryOperand(expr_, "unary plus", this.IsArithmetic)
+	} else if unary_expr.UnaryExprType() == expr.MINUS {
+		return this.CheckUnaryOperand(expr_, "unary minus", this.IsArithmetic)
+	} else if unary_expr.UnaryExprType() == expr.TILDE {
+		return this.CheckUnaryOperand(expr_, "bit-complement", this.IsInteger)
+	} else if unary_expr.UnaryExprType() == expr.NOT {
+		if this.CheckUnaryOperand(expr_, "unary exclamation mark", this.IsScalar) == nil {
+			return nil
+		}
+		return this.NewPrimitive(type_system.INT, 0)
+	} else if unary_expr.UnaryExprType() == expr.SIZEOF {
+		// NOTE: an operand that cannot be typed is sized as an int after its error is reported
+		if !unary_expr.HasTypeSpecifier() {
+			operand := this.CheckExpr(unary_expr.Base())
+
+			if operand == nil {
+				operand = this.NewPrimitive(type_system.INT, 0)
+			} else if this.IsVoidValue(operand) {
+				this.Report(expr_.FirstToken(), "invalid application of 'sizeof' to a void type")
+				operand = this.NewPrimitive(type_system.INT, 0)
+			}
+
+			unary_expr.SetTypeSpecifier(this.Specify(operand))
+		}
+
+		type_specifier_ := unary_expr.TypeSpecifier()
+
+		if symbol := this.NewSymbol(type_specifier_, ""); !this.IsComplete(symbol) {
+			err_msg := fmt.Sprintf(
+				"invalid application of 'sizeof' to incomplete type '%s'",
+				this.Spell(symbol),
+			)
+			this.Report(expr_.FirstToken(), err_msg)
+		}
+
+		value := this.NewPrimitive(type_system.LONG, 0)
+		value.SetUnsigned()
+		return value
+	} else if unary_expr.UnaryExprType() == expr.CAST {
+		return this.CheckCast(expr_)
+	} else {
+		err := errors.New("unary expr type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Checker) CheckUnaryOperand(
+	expr_ *expr.Expr,
+	op string,
+	is_valid func(*type_system.Symbol) bool,
+) *type_system.Symbol {
+	value := this.CheckValue(expr_.UnaryExpr().Base())
+
+	if value == nil {
+		return nil
+	} else if !is_valid(value) {
+		this.Report(expr_.FirstToken(), fmt.Sprintf("wrong type argument to %s", op))
+		return nil
+	} else if this.IsArithmetic(value) {
+		return this.Convert(value, value)
+	}
+	return value
+}
+
+func (this *Checker) CheckCast(expr_ *expr.Expr) *type_system.Symbol {
+	unary_expr := expr_.UnaryExpr()
+	target := this.NewSymbol(unary_expr.TypeSpecifier(), "")
+	value := this.CheckExpr(unary_expr.Base())
+
+	if this.IsVoidValue(target) {
+		return target
+	} else if value == nil {
+		return target
+	} else if this.IsVoidValue(value) {
+		this.Report(unary_expr.Base().FirstToken(), "void value not ignored as it ought to be")
+	} else if this.IsStructValue(target) {
+		this.Report(expr_.FirstToken(), "conversion to non-scalar type requested")
+	} else if this.IsStructValue(value) {
+		this.Report(expr_.FirstToken(), "aggregate value used where a scalar was expected")
+	} else if this.IsPointer(target) && this.IsFloating(value) {
+		this.Report(expr_.FirstToken(), "cannot convert to a pointer type")
+	} else if this.IsFloating(target) && this.IsPointer(value) {
+		this.Report(expr_.FirstToken(), "pointer value used where a floating-point was expected")
+	}
+	return target
+}
+
+func (this *Checker) CheckMultiplicativeExpr(expr_ *expr.Expr) *type_system.Symbol {
+	multiplicative_expr := expr_.MultiplicativeExpr()
+	loperand := multiplicative_expr.Loperand()
+	roperand := multiplicative_expr.Roperand()
+
+	if multiplicative_expr.MultiplicativeExprType() == expr.MUL {
+		return this.CheckArithmeticOperands(expr_, "*", loperand, roperand)
+	} else if multiplicative_expr.MultiplicativeExprType() == expr.DIV {
+		return this.CheckArithmeticOperands(expr_, "/", loperand, roperand)
+	} else if multiplicative_expr.MultiplicativeExprType() == expr.MOD {
+		return this.CheckIntegerOperands(expr_, "%", loperand, roperand)
+	} else {
+		err := errors.New("multiplicative expr type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Checker) CheckAdditiveExpr(expr_ *expr.Expr) *type_system.Symbol {
+	additive_expr := expr_.AdditiveExpr()
+	loperand := this.CheckValue(additive_expr.Loperand())
+	roperand := this.CheckValue(additive_expr.Roperand())
+
+	op := ""
+	if additive_expr.AdditiveExprType() == expr.ADD {
+		op = "+"
+	} else if additive_expr.AdditiveExprType() == expr.SUB {
+		op = "-"
+	} else {
+		err := errors.New("additive expr type is not valid")
+		panic(err)
+	}
+
+	if loperand == nil || roperand == nil {
+		return nil
+	} else if this.IsArithmetic(loperand) && this.IsArithmetic(roperand) {
+		return this.Convert(loperand, roperand)
+	} else if this.IsPointer(loperand) && this.IsInteger(roperand) {
+		return loperand
+	} else if op == "+" && this.IsInteger(loperand) && this.IsPointer(roperand) {
+		return roperand
+	} else if op == "-" && this.IsPointer(loperand) && this.IsPointer(roperand) {
+		return this.NewPrimitive(type_system.LONG, 0)
+	}
+
+	this.ReportOperands(expr_, op, loperand, roperand)
+	return nil
+}
+
+func (this *Checker) CheckArithmeticOperands(
+	expr_ *expr.Expr,
+	op string,
+	loperand_expr *expr.Expr,
+	roperand_expr *expr.Expr,
+) *type_system.Symbol {
+	loperand := this.CheckValue(loperand_expr)
+	roperand := this.CheckValue(roperand_expr)
+
+	if loperand == nil || roperand == nil {
+		return nil
+	} else if !this.IsArithmetic(loperand) || !this.IsArithmetic(roperand) {
+		this.ReportOperands(expr_, op, loperand, roperand)
+		return nil
+	}
+	return this.Convert(loperand, roperand)
+}
+
+func (this *Checker) CheckIntegerOperands(
+	expr_ *expr.Expr,
+	op string,
+	loperand_expr *expr.Expr,
+	roperand_expr *expr.Expr,
+) *type_system.Symbol {
+	loperand := this.CheckValue(loperand_expr)
+	roperand := this.CheckValue(roperand_expr)
+
+	if loperand == nil || roperand == nil {
+		return nil
+	} else if !this.IsInteger(loperand) || !this.IsInteger(roperand) {
+		this.ReportOperands(expr_, op, loperand, roperand)
+		return nil
+	}
+	return this.Convert(loperand, roperand)
+}
+
+func (this *Checker) CheckComparison(
+	expr_ *expr.Expr,
+	op string,
+	loperand_expr *expr.Expr,
+	roperand_expr *expr.Expr,
+) *type_system.Symbol {
+	loperand := this.CheckValue(loperand_expr)
+	roperand := this.CheckValue(roperand_expr)
+
+	if loperand != nil && roperand != nil && !this.IsComparable(loperand, roperand) {
+		this.ReportOperands(expr_, op, loperand, roperand)
+	}
+	return this.NewPrimitive(type_system.INT, 0)
+}
+
+func (this *Checker) IsComparable(loperand *type_system.Symbol, roperand *type_system.Symbol) bool {
+	if !this.IsScalar(loperand) || !this.IsScalar(roperand) {
+		return false
+	} else if this.IsPointer(loperand) && this.IsFloating(roperand) {
+		return false
+	} else if this.IsFloating(loperand) && this.IsPointer(roperand) {
+		return false
+	}
+	return true
+}
+
+func (this *Checker) ReportOperands(
+	expr_ *expr.Expr,
+	op string,
+	loperand *type_system.Symbol,
+	roperand *type_system.Symbol,
+) {
+	err_msg := fmt.Sprintf(
+		"invalid operands to binary %s (have '%s' and '%s')",
+		op,
+		this.Spell(loperand),
+		this.Spell(roperand),
+	)
+	this.Report(expr_.FirstToken(), err_msg)
+}
+
+func (this *Checker) CheckConditionalExpr(expr_ *expr.Expr) *type_system.Symbol {
+	conditional_expr := expr_.ConditionalExpr()
+
+	this.CheckCondition(conditional_expr.ConditionalExpr())
+	true_value := this.CheckValue(conditional_expr.TrueExpr())
+	false_value := this.CheckValue(conditional_expr.FalseExpr())
+
+	if true_value == nil || false_value == nil {
+		return nil
+	} else if this.IsArithmetic(true_value) && this.IsArithmetic(false_value) {
+		return this.Convert(true_value, false_value)
+	} else if !this.IsAssignable(true_value, false_value) {
+		this.Report(expr_.FirstToken(), "type mismatch in conditional expression")
+		return nil
+	}
+	return true_value
+}
+
+func (this *Checker) CheckAssignmentExpr(expr_ *expr.Expr) *type_system.Symbol {
+	assignment_expr := expr_.AssignmentExpr()
+	assignment_expr_type := assignment_expr.AssignmentExprType()
+
+	lvalue := this.CheckValue(assignment_expr.Lvalue())
+	rvalue := this.CheckValue(assignment_expr.Rvalue())
+
+	if !this.IsLvalue(assignment_expr.Lvalue()) {
+		this.Report(expr_.FirstToken(), "lvalue required as left operand of assignment")
+		return lvalue
+	} else if lvalue == nil || rvalue == nil {
+		return lvalue
+	}
+
+	if assignment_expr_type == expr.ASSIGN {
+		if !this.IsAssignable(lvalue, rvalue) {
+			err_msg := fmt.Sprintf(
+				"incompatible types when assigning to type '%s' from type '%s'",
+				this.Spell(lvalue),
+				this.Spell(rvalue),
+			)
+			this.Report(assignment_expr.Rvalue().FirstToken(), err_msg)
+		}
+	} else if assignment_expr_type == expr.PLUS_ASSIGN ||
+		assignment_expr_type == expr.MINUS_ASSIGN {
+		if !(this.IsArithmetic(lvalue) && this.IsArithmetic(rvalue)) &&
+			!(this.IsPointer(lvalue) && this.IsInteger(rvalue)) {
+			this.ReportOperands(expr_, this.CompoundOp(assignment_expr_type), lvalue, rvalue)
+		}
+	} else if assignment_expr_type == expr.STAR_ASSIGN || assignment_expr_type == expr.DIV_ASSIGN {
+		if !this.IsArithmetic(lvalue) || !this.IsArithmetic(rvalue) {
+			this.ReportOperands(expr_, this.CompoundOp(assignment_expr_type), lvalue, rvalue)
+		}
+	} else if assignment_expr_type == expr.MOD_ASSIGN ||
+		assignment_expr_type == expr.LSHIFT_ASSIGN ||
+		assignment_expr_type == expr.RSHIFT_ASSIGN ||
+		assignment_expr_type == expr.AND_ASSIGN ||
+		assignment_expr_type == expr.CARET_ASSIGN ||
+		assignment_expr_type == expr.OR_ASSIGN {
+		if !this.IsInteger(lvalue) || !this.IsInteger(rvalue) {
+			this.ReportOperands(expr_, this.CompoundOp(assignment_expr_type), lvalue, rvalue)
+		}
+	} else {
+		err := errors.New("assignment expr type is not valid")
+		panic(err)
+	}
+
+	return lvalue
+}
+
+func (this *Checker) CompoundOp(assignment_expr_type expr.AssignmentExprType) string {
+	if assignment_expr_type == expr.PLUS_ASSIGN {
+		return "+"
+	} else if assignment_expr_type == expr.MINUS_ASSIGN {
+		return "-"
+	} else if assignment_expr_type == expr.STAR_ASSIGN {
+		return "*"
+	} else if assignment_expr_type == expr.DIV_ASSIGN {
+		return "/"
+	} else if assignment_expr_type == expr.MOD_ASSIGN {
+		return "%"
+	} else if assignment_expr_type == expr.LSHIFT_ASSIGN {
+		return "<<"
+	} else if assignment_expr_type == expr.RSHIFT_ASSIGN {
+		return ">>"
+	} else if assignment_expr_type == expr.AND_ASSIGN {
+		return "&"
+	} else if assignment_expr_type == expr.CARET_ASSIGN {
+		return "^"
+	} else if assignment_expr_type == expr.OR_ASSIGN {
+		return "|"
+	} else {
+		err := errors.New("assignment expr type is not a compound assignment")
+		panic(err)
+	}
+}
+
+func (this *Checker) IsLvalue(expr_ *expr.Expr) bool {
+	if expr_.ExprType() == expr.PRIMARY {
+		primary_expr := expr_.PrimaryExpr()
+
+		if primary_expr.PrimaryExprType() == expr.IDENTIFIER {
+			return !this.constants[primary_expr.Token().Attribute()] ||
+				this.scope.HasSymbol(primary_expr.Token().Attribute())
+		} else if primary_expr.PrimaryExprType() == expr.PAREN {
+			return this.IsLvalue(primary_expr.Expr())
+		}
+		return false
+	} else if expr_.ExprType() == expr.POSTFIX {
+		postfix_expr_type := expr_.PostfixExpr().PostfixExprType()
+		return postfix_expr_type == expr.BRACKET || postfix_expr_type == expr.DOT ||
+			postfix_expr_type == expr.ARROW
+	} else if expr_.ExprType() == expr.UNARY {
+		return expr_.UnaryExpr().UnaryExprType() == expr.STAR
+	}
+	return false
+}
+
+// NOTE: IsAssignable only rejects what the VM cannot convert, i.e. a struct from anything but the
+// same struct, a pointer from a floating-point or the other way around, and leaves the rest of the
+// conversions of C, e.g. an int to a pointer, to the VM
+func (this *Checker) IsAssignable(target *type_system.Symbol, value *type_system.Symbol) bool {
+	if target == nil || value == nil {
+		return true
+	} else if this.IsStructValue(target) || this.IsStructValue(value) {
+		return this.IsStructValue(target) && this.IsStructValue(value) &&
+			target.StructName() == value.StructName()
+	} else if this.IsPointer(target) && this.IsFloating(value) {
+		return false
+	} else if this.IsFloating(target) && this.IsPointer(value) {
+		return false
+	}
+	return true
+}
+
+func (this *Checker) IsSameType(symbol *type_system.Symbol, other *type_system.Symbol) bool {
+	if symbol.SymbolType() != other.SymbolType() || symbol.NumStars() != other.NumStars() ||
+		symbol.IsUnsigned() != other.IsUnsigned() {
+		return false
+	} else if symbol.SymbolType() == type_system.STRUCT {
+		return symbol.StructName() == other.StructName()
+	}
+	return true
+}
+
+// NOTE: a struct is complete once it is defined, while a pointer to a struct is always complete
+func (this *Checker) IsComplete(symbol *type_system.Symbol) bool {
+	if !this.IsStructValue(symbol) {
+		return true
+	}
+	return this.type_system.HasStruct(symbol.StructName())
+}
+
+func (this *Checker) IsVoidValue(symbol *type_system.Symbol) bool {
+	return symbol.SymbolType() == type_system.VOID && symbol.NumStars() == 0
+}
+
+func (this *Checker) IsStructValue(symbol *type_system.Symbol) bool {
+	return symbol.SymbolType() == type_system.STRUCT && symbol.NumStars() == 0
+}
+
+func (this *Checker) IsPointer(symbol *type_system.Symbol) bool {
+	return symbol.NumStars() > 0
+}
+
+func (this *Checker) IsInteger(symbol *type_system.Symbol) bool {
+	symbol_type := symbol.SymbolType()
+
+	return symbol.NumStars() == 0 &&
+		(symbol_type == type_system.CHAR || symbol_type == type_system.SHORT ||
+			symbol_type == type_system.INT || symbol_type == type_system.LONG)
+}
+
+func (this *Checker) IsFloating(symbol *type_system.Symbol) bool {
+	symbol_type := symbol.SymbolType()
+
+	return symbol.NumStars() == 0 &&
+		(symbol_type == type_system.FLOAT || symbol_type == type_system.DOUBLE)
+}
+
+func (this *Checker) IsArithmetic(symbol *type_system.Symbol) bool {
+	return this.IsInteger(symbol) || this.IsFloating(symbol)
+}
+
+func (this *Checker) IsScalar(symbol *type_system.Symbol) bool {
+	return this.IsArithmetic(symbol) || this.IsPointer(symbol)
+}
+
+// NOTE: Convert gives the type of an arithmetic operation by the usual arithmetic conversions of
+// C, where a char or a short is promoted to an int
+func (this *Checker) Convert(
+	loperand *type_system.Symbol,
+	roperand *type_system.Symbol,
+) *type_system.Symbol {
+	rank := func(symbol *type_system.Symbol) int {
+		if symbol.SymbolType() == type_system.DOUBLE {
+			return 3
+		} else if symbol.SymbolType() == type_system.FLOAT {
+			return 2
+		} else if symbol.SymbolType() == type_system.LONG {
+			return 1
+		} else {
+			return 0
+		}
+	}
+
+	symbol_types := []type_system.SymbolType{
+		type_system.INT,
+		type_system.LONG,
+		type_system.FLOAT,
+		type_system.DOUBLE,
+	}
+
+	max_rank := rank(loperand)
+	if rank(roperand) > max_rank {
+		max_rank = rank(roperand)
+	}
+
+	value := this.NewPrimitive(symbol_types[max_rank], 0)
+
+	if this.IsInteger(value) &&
+		((rank(loperand) == max_rank && loperand.IsUnsigned()) ||
+			(rank(roperand) == max_rank && roperand.IsUnsigned())) {
+		value.SetUnsigned()
+	}
+	return value
+}
+
+func (this *Checker) NewSymbol(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	name string,
+) *type_system.Symbol {
+	symbol := new(type_system.Symbol)
+
+	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
+		symbol.InitPrimitive(type_system.VOID, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
+		symbol.InitPrimitive(type_system.CHAR, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
+		symbol.InitPrimitive(type_system.SHORT, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
+		symbol.InitPrimitive(type_system.INT, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
+		symbol.InitPrimitive(type_system.LONG, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+		symbol.InitPrimitive(type_system.FLOAT, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+		symbol.InitPrimitive(type_system.DOUBLE, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
+		symbol.InitStruct(
+			type_system.STRUCT,
+			type_specifier_.StructIdentifier().Attribute(),
+			type_specifier_.NumStars(),
+			name,
+		)
+	} else {
+		err := errors.New("type specifier type is not valid")
+		panic(err)
+	}
+
+	if type_specifier_.IsUnsigned() {
+		symbol.SetUnsigned()
+	}
+
+	return symbol
+}
+
+// NOTE: Specify gives the type specifier of a symbol, where a string is a pointer to its chars
+func (this *Checker) Specify(symbol *type_system.Symbol) *type_specifier.TypeSpecifier {
+	type_specifier_ := new(type_specifier.TypeSpecifier)
+	num_stars := symbol.NumStars()
+
+	if symbol.SymbolType() == type_system.STRUCT {
+		struct_identifier := new(lexer.Token)
+		struct_identifier.Init(lexer.IDENTIFIER, symbol.StructName())
+		type_specifier_.InitStruct(type_specifier.STRUCT, struct_identifier)
+	} else if symbol.SymbolType() == type_system.STRING {
+		type_specifier_.InitPrimitive(type_specifier.CHAR)
+		num_stars++
+	} else {
+		type_specifier_types := map[type_system.SymbolType]type_specifier.TypeSpecifierType{
+			type_system.VOID:   type_specifier.VOID,
+			type_system.CHAR:   type_specifier.CHAR,
+			type_system.SHORT:  type_specifier.SHORT,
+			type_system.INT:    type_specifier.INT,
+			type_system.LONG:   type_specifier.LONG,
+			type_system.FLOAT:  type_specifier.FLOAT,
+			type_system.DOUBLE: type_specifier.DOUBLE,
+		}
+		type_specifier_.InitPrimitive(type_specifier_types[symbol.SymbolType()])
+	}
+
+	for i := 0; i < num_stars; i++ {
+		type_specifier_.AddStar()
+	}
+
+	if symbol.IsUnsigned() {
+		type_specifier_.SetUnsigned()
+	}
+
+	return type_specifier_
+}
+
+func (this *Checker) NewPrimitive(
+	symbol_type type_system.SymbolType,
+	num_stars int,
+) *type_system.Symbol {
+	symbol := new(type_system.Symbol)
+	symbol.InitPrimitive(symbol_type, num_stars, "")
+	return symbol
+}
+
+func (this *Checker) NewStruct(struct_name string, num_stars int) *type_system.Symbol {
+	symbol := new(type_system.Symbol)
+	symbol.InitStruct(type_system.STRUCT, struct_name, num_stars, "")
+	return symbol
+}
+
+// NOTE: Reference gives the type of a symbol with another number of stars, e.g. the type of an
+// element of an array or of the address of a variable
+func (this *Checker) Reference(symbol *type_system.Symbol, num_stars int) *type_system.Symbol {
+	return this.Copy(symbol, num_stars, "")
+}
+
+func (this *Checker) Rename(symbol *type_system.Symbol, name string) *type_system.Symbol {
+	return this.Copy(symbol, symbol.NumStars(), name)
+}
+
+func (this *Checker) Copy(
+	symbol *type_system.Symbol,
+	num_stars int,
+	name string,
+) *type_system.Symbol {
+	copy_ := new(type_system.Symbol)
+
+	if symbol.SymbolType() == type_system.STRUCT {
+		copy_.InitStruct(type_system.STRUCT, symbol.StructName(), num_stars, name)
+	} else {
+		copy_.InitPrimitive(symbol.SymbolType(), num_stars, name)
+	}
+
+	if symbol.IsUnsigned() {
+		copy_.SetUnsigned()
+	}
+
+	return copy_
+}
+
+// NOTE: Spell names a type as the C compilers do, e.g. "unsigned int" or "struct dpu_set_t *"
+func (this *Checker) Spell(symbol *type_system.Symbol) string {
+	if symbol == nil {
+		return "unknown"
+	}
+
+	var name string
+	if symbol.SymbolType() == type_system.VOID {
+		name = "void"
+	} else if symbol.SymbolType() == type_system.CHAR {
+		name = "char"
+	} else if symbol.SymbolType() == type_system.SHORT {
+		name = "short"
+	} else if symbol.SymbolType() == type_system.INT {
+		name = "int"
+	} else if symbol.SymbolType() == type_system.LONG {
+		name = "long"
+	} else if symbol.SymbolType() == type_system.STRING {
+		name = "char *"
+	} else if symbol.SymbolType() == type_system.STRUCT {
+		name = "struct " + symbol.StructName()
+	} else if symbol.SymbolType() == type_system.FLOAT {
+		name = "float"
+	} else if symbol.SymbolType() == type_system.DOUBLE {
+		name = "double"
+	} else {
+		err := errors.New("symbol type is not valid")
+		panic(err)
+	}
+
+	if symbol.IsUnsigned() {
+		name = "unsigned " + name
+	}
+
+	if symbol.NumStars() > 0 {
+		name += " " + strings.Repeat("*", symbol.NumStars())
+	}
+
+	return name
+}
@@ -0,0 +1,177 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"uPIMulator/src/host/interpreter/lexer"
+	"uPIMulator/src/host/interpreter/parser"
+)
+
+func checkSource(t *testing.T, source string) (message string) {
+	path := filepath.Join(t.TempDir(), "app.c")
+	if err := os.WriteFile(path, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	lexer_ := new(lexer.Lexer)
+	lexer_.Init()
+
+	parser_ := new(parser.Parser)
+	parser_.Init()
+
+	ast := parser_.Parse(lexer_.Lex(path))
+
+	defer func() {
+		if err := recover(); err != nil {
+			message = strings.ReplaceAll(err.(error).Error(), path+":", "")
+		}
+	}()
+
+	checker_ := new(Checker)
+	checker_.Init()
+	checker_.Check(ast)
+	return ""
+}
+
+func TestCheckValidProgram(t *testing.T) {
+	message := checkSource(t, `
+		struct point {
+			int x, y;
+		};
+
+		int norm(struct point *p) {
+			return p->x * p->x + p->y * p->y;
+		}
+
+		int main() {
+			struct dpu_set_t set, dpu;
+			uint32_t i;
+			struct point p;
+			p.x = 3;
+			p.y = 4;
+			dpu_alloc(1, NULL, &set);
+			DPU_FOREACH(set, dpu, i) {
+				dpu_prepare_xfer(dpu, &p);
+			}
+			dpu_launch(set, DPU_SYNCHRONOUS);
+			dpu_free(set);
+			assert(norm(&p) == 25);
+			return 0;
+		}
+	`)
+
+	if message != "" {
+		t.Errorf("valid program is reported as\n%s", message)
+	}
+}
+
+func TestCheckDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			"undeclared identifier",
+			"int main() {\n\tint x = y;\n\treturn 0;\n}",
+			"2:10: error: 'y' undeclared",
+		},
+		{
+			"undeclared identifier out of its scope",
+			"int main() {\n\tif (1) { int x; }\n\treturn x;\n}",
+			"3:9: error: 'x' undeclared",
+		},
+		{
+			"implicit declaration",
+			"int main() {\n\treturn f(1);\n}",
+			"2:9: error: implicit declaration of function 'f'",
+		},
+		{
+			"too few arguments to dpu_launch",
+			"int main() {\n\tstruct dpu_set_t set;\n\tdpu_launch(set);\n\treturn 0;\n}",
+			"3:2: error: too few arguments to function 'dpu_launch'",
+		},
+		{
+			"too many arguments to dpu_sync",
+			"int main() {\n\tstruct dpu_set_t set;\n\tdpu_sync(set, 0);\n\treturn 0;\n}",
+			"3:2: error: too many arguments to function 'dpu_sync'",
+		},
+		{
+			"too few arguments to dpu_push_xfer",
+			"int main() {\n\tstruct dpu_set_t set;\n" +
+				"\tdpu_push_xfer(set, DPU_XFER_TO_DPU, \"buffer\", 0, 8);\n\treturn 0;\n}",
+			"3:2: error: too few arguments to function 'dpu_push_xfer'",
+		},
+		{
+			"unknown field",
+			"struct point { int x; };\nint main() {\n\tstruct point p;\n\tp.z = 1;\n\treturn 0;\n}",
+			"4:4: error: 'struct point' has no member named 'z'",
+		},
+		{
+			"unknown field through a pointer",
+			"struct point { int x; };\nint main() {\n\tstruct point p;\n" +
+				"\tstruct point *q = &p;\n\treturn q->y;\n}",
+			"5:12: error: 'struct point' has no member named 'y'",
+		},
+		{
+			"dot on a pointer",
+			"struct point { int x; };\nint main() {\n\tstruct point p;\n" +
+				"\tstruct point *q = &p;\n\treturn q.x;\n}",
+			"5:11: error: 'struct point *' is a pointer; did you mean to use '->'?",
+		},
+		{
+			"struct initialized with an int",
+			"struct point { int x; };\nint main() {\n\tstruct point p = 1;\n\treturn 0;\n}",
+			"3:19: error: incompatible types when initializing type 'struct point' using type 'int'",
+		},
+		{
+			"struct assigned to an int",
+			"struct point { int x; };\nint main() {\n\tstruct point p;\n\tint x;\n" +
+				"\tx = p;\n\treturn 0;\n}",
+			"5:6: error: incompatible types when assigning to type 'int' from type 'struct point'",
+		},
+		{
+			"struct operands",
+			"struct point { int x; };\nint main() {\n\tstruct point p;\n\treturn p + 1;\n}",
+			"4:9: error: invalid operands to binary + (have 'struct point' and 'int')",
+		},
+		{
+			"dpu set argument of the wrong type",
+			"int main() {\n\tdpu_launch(1, DPU_SYNCHRONOUS);\n\treturn 0;\n}",
+			"2:13: error: incompatible type for argument 1 of 'dpu_launch'",
+		},
+		{
+			"sizeof a void value",
+			"void f() {\n}\nint main() {\n\treturn sizeof(f());\n}",
+			"4:9: error: invalid application of 'sizeof' to a void type",
+		},
+		{
+			"sizeof an undeclared identifier",
+			"int main() {\n\treturn sizeof y;\n}",
+			"2:16: error: 'y' undeclared",
+		},
+		{
+			"main with one param",
+			"int main(int argc) {\n\treturn 0;\n}",
+			"1:5: error: 'main' takes only zero or two arguments",
+		},
+		{
+			"main with an argv of the wrong type",
+			"int main(int argc, char *argv) {\n\treturn 0;\n}",
+			"1:5: error: second argument of 'main' should be 'char **'",
+		},
+		{
+			"missing main",
+			"int f() {\n\treturn 0;\n}",
+			"error: undefined reference to 'main'",
+		},
+	}
+
+	for _, test := range tests {
+		if message := checkSource(t, test.source); !strings.Contains(message, test.expected) {
+			t.Errorf("%s is reported as\n%s\nwant %s", test.name, message, test.expected)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"uPIMulator/src/host/interpreter/codegen/type_system"
+)
+
+type Scope struct {
+	parent  *Scope
+	symbols map[string]*type_system.Symbol
+}
+
+func (this *Scope) Init(parent *Scope) {
+	this.parent = parent
+	this.symbols = make(map[string]*type_system.Symbol)
+}
+
+func (this *Scope) Parent() *Scope {
+	return this.parent
+}
+
+func (this *Scope) HasLocalSymbol(symbol_name string) bool {
+	_, found := this.symbols[symbol_name]
+	return found
+}
+
+func (this *Scope) HasSymbol(symbol_name string) bool {
+	for scope := this; scope != nil; scope = scope.parent {
+		if scope.HasLocalSymbol(symbol_name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *Scope) Symbol(symbol_name string) *type_system.Symbol {
+	for scope := this; scope != nil; scope = scope.parent {
+		if scope.HasLocalSymbol(symbol_name) {
+			return scope.symbols[symbol_name]
+		}
+	}
+
+	err_msg := fmt.Sprintf("symbol (%s) is not found", symbol_name)
+	err := errors.New(err_msg)
+	panic(err)
+}
+
+func (this *Scope) AddSymbol(symbol *type_system.Symbol) {
+	this.symbols[symbol.Name()] = symbol
+}
@@ -2,9 +2,10 @@ package codegen
 
 import (
 	"errors"
-	"strconv"
+	"math"
 	"uPIMulator/src/host/abi"
 	"uPIMulator/src/host/interpreter/codegen/type_system"
+	"uPIMulator/src/host/interpreter/lexer"
 	"uPIMulator/src/host/interpreter/parser"
 	"uPIMulator/src/host/interpreter/parser/decl"
 	"uPIMulator/src/host/interpreter/parser/directive"
@@ -62,8 +63,10 @@ func (this *Codegen) Codegen(ast *parser.Ast) *abi.Relocatable {
 			this.CodegenDirective(stack_item.Directive())
 		} else if stack_item.StackItemType() == parser.DECL {
 			this.CodegenDecl(stack_item.Decl())
+		} else if stack_item.StackItemType() == parser.STMT {
+			this.CodegenGlobalStmt(stack_item.Stmt())
 		} else {
-			err := errors.New("stack item is not directive nor decl")
+			err := errors.New("stack item is not directive, decl nor stmt")
 			panic(err)
 		}
 	}
@@ -80,13 +83,33 @@ func (this *Codegen) CodegenInitBootstrap() {
 	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
 
 	this.relocatable.NewBytecode(abi.BEGIN_STRUCT, []int64{}, []string{"dpu_set_t"})
-	this.relocatable.NewBytecode(abi.APPEND_INT, []int64{0}, []string{"foo"})
+	this.relocatable.NewBytecode(abi.APPEND_INT, []int64{0}, []string{"handle"})
 	this.relocatable.NewBytecode(abi.END_STRUCT, []int64{}, []string{})
+
+	this.relocatable.NewBytecode(abi.BEGIN_STRUCT, []int64{}, []string{"timeval"})
+	this.relocatable.NewBytecode(abi.APPEND_LONG, []int64{0}, []string{"tv_sec"})
+	this.relocatable.NewBytecode(abi.APPEND_LONG, []int64{0}, []string{"tv_usec"})
+	this.relocatable.NewBytecode(abi.END_STRUCT, []int64{}, []string{})
+
+	this.relocatable.NewBytecode(abi.NEW_GLOBAL_CHAR, []int64{1}, []string{"optarg"})
 }
 
+// NOTE: main is called with the argc and argv of the host args if it takes them
 func (this *Codegen) CodegenFiniBootstrap() {
 	this.relocatable.SwitchLabel("__bootstrap")
 
+	for i, param := range this.type_system.Method("main").Params() {
+		this.CodegenNewArg(param)
+
+		this.relocatable.NewBytecode(abi.GET_ARG_IDENTIFIER, []int64{0}, []string{param.Name()})
+		if i == 0 {
+			this.relocatable.NewBytecode(abi.GET_ARGC, []int64{}, []string{})
+		} else {
+			this.relocatable.NewBytecode(abi.GET_ARGV, []int64{}, []string{})
+		}
+		this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+	}
+
 	this.relocatable.NewBytecode(abi.CALL, []int64{}, []string{"main"})
 	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
 }
@@ -132,10 +155,7 @@ func (this *Codegen) CodegenDefineDirective(define_directive *directive.DefineDi
 				this.relocatable.NewBytecode(abi.PUSH_INT, []int64{int64(this.data_prep_params)}, []string{})
 				this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
 			} else {
-				rvalue_value, err := strconv.ParseInt(rvalue.PrimaryExpr().Token().Attribute(), 10, 64)
-				if err != nil {
-					panic(err)
-				}
+				rvalue_value := rvalue.PrimaryExpr().Token().Integer()
 
 				this.relocatable.NewBytecode(abi.NEW_GLOBAL_INT, []int64{0}, []string{lvalue.Attribute()})
 				this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{0}, []string{lvalue.Attribute()})
@@ -177,13 +197,23 @@ func (this *Codegen) CodegenStructDef(struct_def *decl.StructDef) {
 
 	struct_name := struct_def.Identifier().Attribute()
 
+	struct_ := new(type_system.Struct)
+	struct_.Init(struct_name)
+
 	this.relocatable.NewBytecode(abi.BEGIN_STRUCT, []int64{}, []string{struct_name})
 
 	for i := 0; i < struct_def.Body().BlockStmt().Length(); i++ {
 		stmt_ := struct_def.Body().BlockStmt().Get(i)
 
-		type_specifier_ := stmt_.VarDeclStmt().TypeSpecifier()
-		field_name := stmt_.VarDeclStmt().Identifier().Attribute()
+		var type_specifier_ *type_specifier.TypeSpecifier
+		var field_name string
+		if stmt_.StmtType() == stmt.ARRAY_DECL {
+			type_specifier_ = stmt_.ArrayDeclStmt().TypeSpecifier()
+			field_name = stmt_.ArrayDeclStmt().Identifier().Attribute()
+		} else {
+			type_specifier_ = stmt_.VarDeclStmt().TypeSpecifier()
+			field_name = stmt_.VarDeclStmt().Identifier().Attribute()
+		}
 
 		if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
 			if type_specifier_.NumStars() == 0 {
@@ -197,111 +227,52 @@ func (this *Codegen) CodegenStructDef(struct_def *decl.StructDef) {
 				[]string{field_name},
 			)
 		} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
-			this.relocatable.NewBytecode(abi.APPEND_CHAR, []int64{int64(type_specifier_.NumStars())}, []string{field_name})
+			this.relocatable.NewBytecode(abi.APPEND_CHAR, this.TypeArgs(type_specifier_, type_specifier_.NumStars()), []string{field_name})
 		} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
-			this.relocatable.NewBytecode(abi.APPEND_SHORT, []int64{int64(type_specifier_.NumStars())}, []string{field_name})
+			this.relocatable.NewBytecode(abi.APPEND_SHORT, this.TypeArgs(type_specifier_, type_specifier_.NumStars()), []string{field_name})
 		} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
-			this.relocatable.NewBytecode(abi.APPEND_INT, []int64{int64(type_specifier_.NumStars())}, []string{field_name})
+			this.relocatable.NewBytecode(abi.APPEND_INT, this.TypeArgs(type_specifier_, type_specifier_.NumStars()), []string{field_name})
 		} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
-			this.relocatable.NewBytecode(abi.APPEND_LONG, []int64{int64(type_specifier_.NumStars())}, []string{field_name})
+			this.relocatable.NewBytecode(abi.APPEND_LONG, this.TypeArgs(type_specifier_, type_specifier_.NumStars()), []string{field_name})
+		} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+			this.relocatable.NewBytecode(abi.APPEND_FLOAT, []int64{int64(type_specifier_.NumStars())}, []string{field_name})
+		} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+			this.relocatable.NewBytecode(abi.APPEND_DOUBLE, []int64{int64(type_specifier_.NumStars())}, []string{field_name})
 		} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
 			this.relocatable.NewBytecode(abi.APPEND_STRUCT, []int64{int64(type_specifier_.NumStars())}, []string{type_specifier_.StructIdentifier().Attribute(), field_name})
 		} else {
 			err := errors.New("type specifier type is not valid")
 			panic(err)
 		}
-	}
-
-	this.relocatable.NewBytecode(abi.END_STRUCT, []int64{}, []string{})
-}
-
-func (this *Codegen) CodegenFuncDecl(func_decl *decl.FuncDecl) {
-	type_specifier_ := func_decl.TypeSpecifier()
 
-	method := new(type_system.Method)
-
-	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
-		symbol := new(type_system.Symbol)
-		symbol.InitPrimitive(
-			type_system.VOID,
-			type_specifier_.NumStars(),
-			func_decl.Identifier().Attribute(),
-		)
+		field := this.NewSymbol(type_specifier_, field_name)
 
-		method.Init(symbol)
-	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
-		symbol := new(type_system.Symbol)
-		symbol.InitPrimitive(type_system.CHAR, type_specifier_.NumStars(), func_decl.Identifier().Attribute())
+		// NOTE: an array field is appended as its element type before its length is given
+		if stmt_.StmtType() == stmt.ARRAY_DECL {
+			this.CodegenExpr(stmt_.ArrayDeclStmt().Size())
+			this.relocatable.NewBytecode(abi.APPEND_ARRAY, []int64{}, []string{})
 
-		method.Init(symbol)
-	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
-		symbol := new(type_system.Symbol)
-		symbol.InitPrimitive(type_system.SHORT, type_specifier_.NumStars(), func_decl.Identifier().Attribute())
+			field = this.Reference(field)
+		}
 
-		method.Init(symbol)
-	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
-		symbol := new(type_system.Symbol)
-		symbol.InitPrimitive(type_system.INT, type_specifier_.NumStars(), func_decl.Identifier().Attribute())
+		struct_.AppendField(field)
+	}
 
-		method.Init(symbol)
-	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
-		symbol := new(type_system.Symbol)
-		symbol.InitPrimitive(type_system.LONG, type_specifier_.NumStars(), func_decl.Identifier().Attribute())
+	this.relocatable.NewBytecode(abi.END_STRUCT, []int64{}, []string{})
 
-		method.Init(symbol)
-	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
-		symbol := new(type_system.Symbol)
-		symbol.InitStruct(type_system.STRUCT, type_specifier_.StructIdentifier().Attribute(), type_specifier_.NumStars(), func_decl.Identifier().Attribute())
+	this.type_system.AddStruct(struct_)
+}
 
-		method.Init(symbol)
-	} else {
-		err := errors.New("type specifier type is not valid")
-		panic(err)
-	}
+func (this *Codegen) CodegenFuncDecl(func_decl *decl.FuncDecl) {
+	method := new(type_system.Method)
+	method.Init(this.NewSymbol(func_decl.TypeSpecifier(), func_decl.Identifier().Attribute()))
 
 	this.type_system.AddMethod(method)
 
 	for i := 0; i < func_decl.ParamList().Length(); i++ {
 		param := func_decl.ParamList().Get(i)
 
-		if param.TypeSpecifier().TypeSpecifierType() == type_specifier.VOID {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(
-				type_system.VOID,
-				param.TypeSpecifier().NumStars(),
-				param.Identifier().Attribute(),
-			)
-
-			method.AppendParam(symbol)
-		} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.CHAR {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.CHAR, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-			method.AppendParam(symbol)
-		} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.SHORT {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.SHORT, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-			method.AppendParam(symbol)
-		} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.INT {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.INT, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-			method.AppendParam(symbol)
-		} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.LONG {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.LONG, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-			method.AppendParam(symbol)
-		} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.STRUCT {
-			symbol := new(type_system.Symbol)
-			symbol.InitStruct(type_system.STRUCT, param.TypeSpecifier().StructIdentifier().Attribute(), param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-			method.AppendParam(symbol)
-		} else {
-			err := errors.New("type specifier type is not valid")
-			panic(err)
-		}
+		method.AppendParam(this.NewSymbol(param.TypeSpecifier(), param.Identifier().Attribute()))
 	}
 
 	this.relocatable.NewFunc(func_decl.Identifier().Attribute())
@@ -310,95 +281,18 @@ func (this *Codegen) CodegenFuncDecl(func_decl *decl.FuncDecl) {
 func (this *Codegen) CodegenFuncDef(func_def *decl.FuncDef) {
 	this.cur_block_depth = 0
 
-	type_specifier_ := func_def.TypeSpecifier()
-
 	func_name := func_def.Identifier().Attribute()
 
 	if !this.type_system.HasMethod(func_name) {
 		method := new(type_system.Method)
-
-		if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(
-				type_system.VOID,
-				type_specifier_.NumStars(),
-				func_def.Identifier().Attribute(),
-			)
-
-			method.Init(symbol)
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.CHAR, type_specifier_.NumStars(), func_def.Identifier().Attribute())
-
-			method.Init(symbol)
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.SHORT, type_specifier_.NumStars(), func_def.Identifier().Attribute())
-
-			method.Init(symbol)
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.INT, type_specifier_.NumStars(), func_def.Identifier().Attribute())
-
-			method.Init(symbol)
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
-			symbol := new(type_system.Symbol)
-			symbol.InitPrimitive(type_system.LONG, type_specifier_.NumStars(), func_def.Identifier().Attribute())
-
-			method.Init(symbol)
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
-			symbol := new(type_system.Symbol)
-			symbol.InitStruct(type_system.CHAR, type_specifier_.StructIdentifier().Attribute(), type_specifier_.NumStars(), func_def.Identifier().Attribute())
-
-			method.Init(symbol)
-		} else {
-			err := errors.New("type specifier type is not valid")
-			panic(err)
-		}
+		method.Init(this.NewSymbol(func_def.TypeSpecifier(), func_name))
 
 		this.type_system.AddMethod(method)
 
 		for i := 0; i < func_def.ParamList().Length(); i++ {
 			param := func_def.ParamList().Get(i)
 
-			if param.TypeSpecifier().TypeSpecifierType() == type_specifier.VOID {
-				symbol := new(type_system.Symbol)
-				symbol.InitPrimitive(
-					type_system.VOID,
-					param.TypeSpecifier().NumStars(),
-					param.Identifier().Attribute(),
-				)
-
-				method.AppendParam(symbol)
-			} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.CHAR {
-				symbol := new(type_system.Symbol)
-				symbol.InitPrimitive(type_system.CHAR, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-				method.AppendParam(symbol)
-			} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.SHORT {
-				symbol := new(type_system.Symbol)
-				symbol.InitPrimitive(type_system.SHORT, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-				method.AppendParam(symbol)
-			} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.INT {
-				symbol := new(type_system.Symbol)
-				symbol.InitPrimitive(type_system.INT, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-				method.AppendParam(symbol)
-			} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.LONG {
-				symbol := new(type_system.Symbol)
-				symbol.InitPrimitive(type_system.LONG, param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-				method.AppendParam(symbol)
-			} else if param.TypeSpecifier().TypeSpecifierType() == type_specifier.STRUCT {
-				symbol := new(type_system.Symbol)
-				symbol.InitStruct(type_system.STRUCT, param.TypeSpecifier().StructIdentifier().Attribute(), param.TypeSpecifier().NumStars(), param.Identifier().Attribute())
-
-				method.AppendParam(symbol)
-			} else {
-				err := errors.New("type specifier type is not valid")
-				panic(err)
-			}
+			method.AppendParam(this.NewSymbol(param.TypeSpecifier(), param.Identifier().Attribute()))
 		}
 
 		this.relocatable.NewFunc(func_def.Identifier().Attribute())
@@ -407,22 +301,168 @@ func (this *Codegen) CodegenFuncDef(func_def *decl.FuncDef) {
 	this.relocatable.SwitchFunc(func_name)
 	this.relocatable.SwitchLabel(func_name)
 
+	this.Locate(func_def.Identifier())
 	this.CodegenStmt(func_def.Body())
+	this.relocatable.SetPosition("", 0)
 }
 
+// NOTE: Locate gives the bytecodes generated from now on the position of the token if it has one
+func (this *Codegen) Locate(token *lexer.Token) {
+	if token != nil && token.HasPosition() {
+		this.relocatable.SetPosition(token.Path(), token.Line())
+	}
+}
+
+func (this *Codegen) NewSymbol(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	name string,
+) *type_system.Symbol {
+	symbol := new(type_system.Symbol)
+
+	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
+		symbol.InitPrimitive(type_system.VOID, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
+		symbol.InitPrimitive(type_system.CHAR, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
+		symbol.InitPrimitive(type_system.SHORT, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
+		symbol.InitPrimitive(type_system.INT, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
+		symbol.InitPrimitive(type_system.LONG, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+		symbol.InitPrimitive(type_system.FLOAT, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+		symbol.InitPrimitive(type_system.DOUBLE, type_specifier_.NumStars(), name)
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
+		symbol.InitStruct(
+			type_system.STRUCT,
+			type_specifier_.StructIdentifier().Attribute(),
+			type_specifier_.NumStars(),
+			name,
+		)
+	} else {
+		err := errors.New("type specifier type is not valid")
+		panic(err)
+	}
+
+	if type_specifier_.IsUnsigned() {
+		symbol.SetUnsigned()
+	}
+
+	return symbol
+}
+
+// NOTE: Reference gives the type of a pointer to a symbol, e.g. of an array field that decays to it
+func (this *Codegen) Reference(symbol *type_system.Symbol) *type_system.Symbol {
+	reference := new(type_system.Symbol)
+
+	if symbol.SymbolType() == type_system.STRUCT {
+		reference.InitStruct(type_system.STRUCT, symbol.StructName(), symbol.NumStars()+1, symbol.Name())
+	} else {
+		reference.InitPrimitive(symbol.SymbolType(), symbol.NumStars()+1, symbol.Name())
+	}
+
+	if symbol.IsUnsigned() {
+		reference.SetUnsigned()
+	}
+
+	return reference
+}
+
+// NOTE: integer declarations and casts carry an optional second arg that marks them unsigned
+func (this *Codegen) TypeArgs(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	num_stars int,
+) []int64 {
+	if type_specifier_.IsUnsigned() {
+		return []int64{int64(num_stars), 1}
+	} else {
+		return []int64{int64(num_stars)}
+	}
+}
+
+func (this *Codegen) SymbolArgs(symbol *type_system.Symbol) []int64 {
+	if symbol.IsUnsigned() {
+		return []int64{int64(symbol.NumStars()), 1}
+	} else {
+		return []int64{int64(symbol.NumStars())}
+	}
+}
+
+func (this *Codegen) CodegenGlobalStmt(stmt_ *stmt.Stmt) {
+	this.relocatable.SwitchLabel("__bootstrap")
+
+	this.Locate(stmt_.FirstToken())
+
+	if stmt_.StmtType() == stmt.VAR_DECL {
+		var_decl_stmt := stmt_.VarDeclStmt()
+		type_specifier_ := var_decl_stmt.TypeSpecifier()
+		symbol_name := var_decl_stmt.Identifier().Attribute()
+
+		this.CodegenNewGlobal(type_specifier_, type_specifier_.NumStars(), symbol_name)
+	} else if stmt_.StmtType() == stmt.VAR_DECL_INIT {
+		var_decl_init_stmt := stmt_.VarDeclInitStmt()
+		type_specifier_ := var_decl_init_stmt.TypeSpecifier()
+		symbol_name := var_decl_init_stmt.Identifier().Attribute()
+
+		this.CodegenNewGlobal(type_specifier_, type_specifier_.NumStars(), symbol_name)
+		this.CodegenInitializer(
+			symbol_name,
+			[]int64{},
+			[]string{},
+			this.StructName(type_specifier_),
+			var_decl_init_stmt.Expr(),
+		)
+	} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+		array_decl_stmt := stmt_.ArrayDeclStmt()
+		type_specifier_ := array_decl_stmt.TypeSpecifier()
+		symbol_name := array_decl_stmt.Identifier().Attribute()
+
+		this.CodegenNewGlobal(type_specifier_, type_specifier_.NumStars()+1, symbol_name)
+		this.CodegenArray(array_decl_stmt)
+	} else if stmt_.StmtType() == stmt.DECL_LIST {
+		for i := 0; i < stmt_.DeclListStmt().Length(); i++ {
+			this.CodegenGlobalStmt(stmt_.DeclListStmt().Get(i))
+		}
+	} else {
+		err := errors.New("global stmt type is not a var decl")
+		panic(err)
+	}
+
+	this.relocatable.SetPosition("", 0)
+}
+
+// NOTE: a block keeps the position of the stmt that it is the body of, so that the debugger does
+// not stop at a brace
 func (this *Codegen) CodegenStmt(stmt_ *stmt.Stmt) {
+	prev_path := this.relocatable.CurPath()
+	prev_line := this.relocatable.CurLine()
+
+	if stmt_.StmtType() != stmt.BLOCK {
+		this.Locate(stmt_.FirstToken())
+	}
+
 	if stmt_.StmtType() == stmt.EMPTY {
 		this.CodegenEmptyStmt(stmt_.EmptyStmt())
 	} else if stmt_.StmtType() == stmt.VAR_DECL {
 		this.CodegenVarDeclStmt(stmt_.VarDeclStmt())
 	} else if stmt_.StmtType() == stmt.VAR_DECL_INIT {
 		this.CodegenVarDeclInitStmt(stmt_.VarDeclInitStmt())
+	} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+		this.CodegenArrayDeclStmt(stmt_.ArrayDeclStmt())
 	} else if stmt_.StmtType() == stmt.FOR {
 		this.CodegenForStmt(stmt_.ForStmt())
 	} else if stmt_.StmtType() == stmt.DPU_FOREACH {
 		this.CodegenDpuForeachStmt(stmt_.DpuForeachStmt())
 	} else if stmt_.StmtType() == stmt.WHILE {
 		this.CodegenWhileStmt(stmt_.WhileStmt())
+	} else if stmt_.StmtType() == stmt.DO_WHILE {
+		this.CodegenDoWhileStmt(stmt_.DoWhileStmt())
+	} else if stmt_.StmtType() == stmt.SWITCH {
+		this.CodegenSwitchStmt(stmt_.SwitchStmt())
+	} else if stmt_.StmtType() == stmt.CASE || stmt_.StmtType() == stmt.DEFAULT {
+		err := errors.New("case is not in the body of a switch")
+		panic(err)
 	} else if stmt_.StmtType() == stmt.CONTINUE {
 		this.CodegenContinueStmt(stmt_.ContinueStmt())
 	} else if stmt_.StmtType() == stmt.BREAK {
@@ -435,10 +475,14 @@ func (this *Codegen) CodegenStmt(stmt_ *stmt.Stmt) {
 		this.CodegenExprStmt(stmt_.ExprStmt())
 	} else if stmt_.StmtType() == stmt.BLOCK {
 		this.CodegenBlockStmt(stmt_.BlockStmt())
+	} else if stmt_.StmtType() == stmt.DECL_LIST {
+		this.CodegenDeclListStmt(stmt_.DeclListStmt())
 	} else {
 		err := errors.New("stmt type is not valid")
 		panic(err)
 	}
+
+	this.relocatable.SetPosition(prev_path, prev_line)
 }
 
 func (this *Codegen) CodegenEmptyStmt(empty_stmt *stmt.EmptyStmt) {
@@ -449,66 +493,220 @@ func (this *Codegen) CodegenVarDeclStmt(var_decl_stmt *stmt.VarDeclStmt) {
 	type_specifier_ := var_decl_stmt.TypeSpecifier()
 	symbol_name := var_decl_stmt.Identifier().Attribute()
 
+	this.CodegenNewFast(type_specifier_, type_specifier_.NumStars(), symbol_name)
+}
+
+func (this *Codegen) CodegenVarDeclInitStmt(var_decl_init_stmt *stmt.VarDeclInitStmt) {
+	type_specifier_ := var_decl_init_stmt.TypeSpecifier()
+	symbol_name := var_decl_init_stmt.Identifier().Attribute()
+
+	this.CodegenNewFast(type_specifier_, type_specifier_.NumStars(), symbol_name)
+	this.CodegenInitializer(
+		symbol_name,
+		[]int64{},
+		[]string{},
+		this.StructName(type_specifier_),
+		var_decl_init_stmt.Expr(),
+	)
+}
+
+func (this *Codegen) CodegenArrayDeclStmt(array_decl_stmt *stmt.ArrayDeclStmt) {
+	type_specifier_ := array_decl_stmt.TypeSpecifier()
+	symbol_name := array_decl_stmt.Identifier().Attribute()
+
+	this.CodegenNewFast(type_specifier_, type_specifier_.NumStars()+1, symbol_name)
+	this.CodegenArray(array_decl_stmt)
+}
+
+// NOTE: an array is a pointer to its elements, which are allocated in the heap of the VM
+func (this *Codegen) CodegenArray(array_decl_stmt *stmt.ArrayDeclStmt) {
+	type_specifier_ := array_decl_stmt.TypeSpecifier()
+	symbol_name := array_decl_stmt.Identifier().Attribute()
+
+	if array_decl_stmt.HasInitializer() && !this.IsArrayInitializer(array_decl_stmt.Initializer()) {
+		err := errors.New("initializer of an array is neither an initializer list nor a string")
+		panic(err)
+	}
+
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{symbol_name})
+	if array_decl_stmt.HasSize() {
+		this.CodegenExpr(array_decl_stmt.Size())
+	} else if array_decl_stmt.Initializer().PrimaryExpr().PrimaryExprType() == expr.STRING {
+		characters := array_decl_stmt.Initializer().PrimaryExpr().Token().Characters()
+		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{int64(len(characters))}, []string{})
+	} else {
+		num_elements := int64(array_decl_stmt.Initializer().PrimaryExpr().ArgList().Length())
+		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{num_elements}, []string{})
+	}
+	this.CodegenSizeof(type_specifier_, type_specifier_.NumStars())
+	this.relocatable.NewBytecode(abi.MUL, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.ALLOC, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+
+	if !array_decl_stmt.HasInitializer() {
+		return
+	}
+
+	initializer := array_decl_stmt.Initializer().PrimaryExpr()
+
+	if initializer.PrimaryExprType() == expr.STRING {
+		for i, character := range initializer.Token().Characters() {
+			this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{symbol_name})
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{int64(i)}, []string{})
+			this.relocatable.NewBytecode(abi.GET_SUBSCRIPT, []int64{}, []string{})
+			this.relocatable.NewBytecode(abi.PUSH_CHAR, []int64{character}, []string{})
+			this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+		}
+		return
+	}
+
+	arg_list := initializer.ArgList()
+	for i := 0; i < arg_list.Length(); i++ {
+		this.CodegenInitializer(
+			symbol_name,
+			[]int64{int64(i)},
+			[]string{},
+			this.StructName(type_specifier_),
+			arg_list.Get(i),
+		)
+	}
+}
+
+func (this *Codegen) IsArrayInitializer(initializer *expr.Expr) bool {
+	if initializer.ExprType() != expr.PRIMARY {
+		return false
+	}
+
+	primary_expr_type := initializer.PrimaryExpr().PrimaryExprType()
+	return primary_expr_type == expr.INITIALIZER_LIST || primary_expr_type == expr.STRING
+}
+
+// NOTE: CodegenInitializer assigns an initializer to a symbol or to an element of an array,
+// where an initializer list of a struct is assigned field by field
+func (this *Codegen) CodegenInitializer(
+	symbol_name string,
+	subscripts []int64,
+	field_names []string,
+	struct_name string,
+	initializer *expr.Expr,
+) {
+	if initializer.ExprType() == expr.PRIMARY &&
+		initializer.PrimaryExpr().PrimaryExprType() == expr.INITIALIZER_LIST {
+		arg_list := initializer.PrimaryExpr().ArgList()
+
+		if struct_name == "" {
+			if arg_list.Length() != 1 {
+				err := errors.New("initializer list of a scalar does not have one element")
+				panic(err)
+			}
+
+			this.CodegenInitializer(symbol_name, subscripts, field_names, "", arg_list.Get(0))
+			return
+		}
+
+		struct_ := this.type_system.Struct(struct_name)
+		for i := 0; i < arg_list.Length(); i++ {
+			field := struct_.Get(i)
+
+			field_struct_name := ""
+			if field.SymbolType() == type_system.STRUCT && field.NumStars() == 0 {
+				field_struct_name = field.StructName()
+			}
+
+			this.CodegenInitializer(
+				symbol_name,
+				subscripts,
+				append(append([]string{}, field_names...), field.Name()),
+				field_struct_name,
+				arg_list.Get(i),
+			)
+		}
+	} else {
+		this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{symbol_name})
+		for _, subscript := range subscripts {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{subscript}, []string{})
+			this.relocatable.NewBytecode(abi.GET_SUBSCRIPT, []int64{}, []string{})
+		}
+		for _, field_name := range field_names {
+			this.relocatable.NewBytecode(abi.GET_ACCESS, []int64{}, []string{field_name})
+		}
+
+		this.CodegenExpr(initializer)
+		this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+	}
+}
+
+func (this *Codegen) StructName(type_specifier_ *type_specifier.TypeSpecifier) string {
+	if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT &&
+		type_specifier_.NumStars() == 0 {
+		return type_specifier_.StructIdentifier().Attribute()
+	} else {
+		return ""
+	}
+}
+
+func (this *Codegen) CodegenNewFast(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	num_stars int,
+	symbol_name string,
+) {
 	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
-		if type_specifier_.NumStars() == 0 {
+		if num_stars == 0 {
 			err := errors.New("num stars == 0")
 			panic(err)
 		}
 
-		this.relocatable.NewBytecode(
-			abi.NEW_FAST_VOID,
-			[]int64{int64(type_specifier_.NumStars())},
-			[]string{symbol_name},
-		)
+		this.relocatable.NewBytecode(abi.NEW_FAST_VOID, []int64{int64(num_stars)}, []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
-		this.relocatable.NewBytecode(abi.NEW_FAST_CHAR, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_FAST_CHAR, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
-		this.relocatable.NewBytecode(abi.NEW_FAST_SHORT, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_FAST_SHORT, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
-		this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_FAST_INT, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
-		this.relocatable.NewBytecode(abi.NEW_FAST_LONG, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_FAST_LONG, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+		this.relocatable.NewBytecode(abi.NEW_FAST_FLOAT, []int64{int64(num_stars)}, []string{symbol_name})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+		this.relocatable.NewBytecode(abi.NEW_FAST_DOUBLE, []int64{int64(num_stars)}, []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
-		this.relocatable.NewBytecode(abi.NEW_FAST_STRUCT, []int64{int64(type_specifier_.NumStars())}, []string{type_specifier_.StructIdentifier().Attribute(), symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_FAST_STRUCT, []int64{int64(num_stars)}, []string{type_specifier_.StructIdentifier().Attribute(), symbol_name})
 	} else {
 		err := errors.New("type specifier type is not valid")
 		panic(err)
 	}
 }
 
-func (this *Codegen) CodegenVarDeclInitStmt(var_decl_init_stmt *stmt.VarDeclInitStmt) {
-	type_specifier_ := var_decl_init_stmt.TypeSpecifier()
-	symbol_name := var_decl_init_stmt.Identifier().Attribute()
-
+func (this *Codegen) CodegenNewGlobal(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	num_stars int,
+	symbol_name string,
+) {
 	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
-		if type_specifier_.NumStars() == 0 {
+		if num_stars == 0 {
 			err := errors.New("num stars == 0")
 			panic(err)
 		}
 
-		this.relocatable.NewBytecode(
-			abi.NEW_FAST_VOID,
-			[]int64{int64(type_specifier_.NumStars())},
-			[]string{symbol_name},
-		)
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_VOID, []int64{int64(num_stars)}, []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
-		this.relocatable.NewBytecode(abi.NEW_FAST_CHAR, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_CHAR, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
-		this.relocatable.NewBytecode(abi.NEW_FAST_SHORT, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_SHORT, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
-		this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_INT, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
-		this.relocatable.NewBytecode(abi.NEW_FAST_LONG, []int64{int64(type_specifier_.NumStars())}, []string{symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_LONG, this.TypeArgs(type_specifier_, num_stars), []string{symbol_name})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_FLOAT, []int64{int64(num_stars)}, []string{symbol_name})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_DOUBLE, []int64{int64(num_stars)}, []string{symbol_name})
 	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
-		this.relocatable.NewBytecode(abi.NEW_FAST_STRUCT, []int64{int64(type_specifier_.NumStars())}, []string{type_specifier_.StructIdentifier().Attribute(), symbol_name})
+		this.relocatable.NewBytecode(abi.NEW_GLOBAL_STRUCT, []int64{int64(num_stars)}, []string{type_specifier_.StructIdentifier().Attribute(), symbol_name})
 	} else {
 		err := errors.New("type specifier type is not valid")
 		panic(err)
 	}
-
-	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{symbol_name})
-	this.CodegenExpr(var_decl_init_stmt.Expr())
-	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
 }
 
 func (this *Codegen) CodegenForStmt(for_stmt *stmt.ForStmt) {
@@ -516,83 +714,230 @@ func (this *Codegen) CodegenForStmt(for_stmt *stmt.ForStmt) {
 
 	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
 	this.block_depths[condition_label.Name()] = this.cur_block_depth
+	this.block_depths[end_label.Name()] = this.cur_block_depth
 	this.cur_block_depth++
 	this.CodegenStmt(for_stmt.Initialization())
-	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
+
+	// NOTE: continue jumps to the condition label, so that the update is generated there and the
+	// condition is checked in a label of its own
+	check_label := this.relocatable.NewUnnamedLabel()
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{check_label.Name()})
 
 	this.relocatable.SwitchLabel(condition_label.Name())
+	this.CodegenStmt(for_stmt.Update())
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{check_label.Name()})
+
+	this.relocatable.SwitchLabel(check_label.Name())
 	this.CodegenExpr(for_stmt.Condition())
 	this.relocatable.NewBytecode(abi.JUMP_IF_NONZERO, []int64{}, []string{body_label.Name()})
-	this.CodegenExpr(for_stmt.Condition())
-	this.relocatable.NewBytecode(abi.JUMP_IF_ZERO, []int64{}, []string{end_label.Name()})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{end_label.Name()})
 
 	this.relocatable.SwitchLabel(body_label.Name())
 	this.CodegenStmt(for_stmt.Body())
-	this.CodegenStmt(for_stmt.Update())
 	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
 
 	this.relocatable.SwitchLabel(end_label.Name())
 	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
 	this.cur_block_depth--
+
+	this.relocatable.EndLoop()
 }
 
+// NOTE: DPU_FOREACH and DPU_RANK_FOREACH iterate over the DPU set at runtime with a hidden index,
+// where the DPU (or the rank) of each iteration is a DPU set of its own that shadows the variable
+// of the host program in the scope of the loop
 func (this *Codegen) CodegenDpuForeachStmt(dpu_foreach_stmt *stmt.DpuForeachStmt) {
-	if dpu_foreach_stmt.Foreach().Get(1).ExprType() != expr.PRIMARY {
+	foreach := dpu_foreach_stmt.Foreach()
+
+	if foreach.Get(1).ExprType() != expr.PRIMARY {
 		err := errors.New("second argument's expr type is not primary")
 		panic(err)
-	} else if dpu_foreach_stmt.Foreach().Get(1).PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
+	} else if foreach.Get(1).PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
 		err := errors.New("second argument's primary expr type is not identifier")
 		panic(err)
 	}
 
-	dpu_symbol_name := dpu_foreach_stmt.Foreach().Get(1).PrimaryExpr().Token().Attribute()
+	dpu_symbol_name := foreach.Get(1).PrimaryExpr().Token().Attribute()
 
-	if dpu_foreach_stmt.Foreach().Get(2).ExprType() != expr.PRIMARY {
-		err := errors.New("third argument's expr type is not primary")
-		panic(err)
-	} else if dpu_foreach_stmt.Foreach().Get(2).PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
-		err := errors.New("third argument's primary expr type is not identifier")
-		panic(err)
+	i_symbol_name := ""
+	if foreach.Length() == 3 {
+		if foreach.Get(2).ExprType() != expr.PRIMARY {
+			err := errors.New("third argument's expr type is not primary")
+			panic(err)
+		} else if foreach.Get(2).PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
+			err := errors.New("third argument's primary expr type is not identifier")
+			panic(err)
+		}
+
+		i_symbol_name = foreach.Get(2).PrimaryExpr().Token().Attribute()
+	}
+
+	var nr_op_code abi.OpCode
+	var get_op_code abi.OpCode
+	if dpu_foreach_stmt.IsRank() {
+		nr_op_code = abi.DPU_NR_RANKS
+		get_op_code = abi.DPU_GET_RANK
+	} else {
+		nr_op_code = abi.DPU_NR_DPUS
+		get_op_code = abi.DPU_GET_DPU
 	}
 
-	i_symbol_name := dpu_foreach_stmt.Foreach().Get(2).PrimaryExpr().Token().Attribute()
+	condition_label, body_label, end_label := this.relocatable.NewLoop()
 
 	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
+	this.block_depths[condition_label.Name()] = this.cur_block_depth
+	this.block_depths[end_label.Name()] = this.cur_block_depth
+	this.cur_block_depth++
 
-	this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{0}, []string{dpu_symbol_name})
-	this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{0}, []string{i_symbol_name})
+	index_name := "__dpu_foreach_" + end_label.Name()
+	this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{0}, []string{index_name})
+	this.relocatable.NewBytecode(abi.NEW_FAST_STRUCT, []int64{0}, []string{"dpu_set_t", dpu_symbol_name})
+	if i_symbol_name != "" {
+		this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{0}, []string{i_symbol_name})
+	}
 
-	for i := 0; i < this.num_dpus; i++ {
-		this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{dpu_symbol_name})
-		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{int64(i)}, []string{})
-		this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+	check_label := this.relocatable.NewUnnamedLabel()
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{check_label.Name()})
+
+	this.relocatable.SwitchLabel(condition_label.Name())
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.relocatable.NewBytecode(abi.ASSIGN_PLUS_PLUS, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{check_label.Name()})
+
+	this.relocatable.SwitchLabel(check_label.Name())
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.CodegenExpr(foreach.Get(0))
+	this.relocatable.NewBytecode(nr_op_code, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.LESS, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.JUMP_IF_NONZERO, []int64{}, []string{body_label.Name()})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{end_label.Name()})
+
+	this.relocatable.SwitchLabel(body_label.Name())
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{dpu_symbol_name})
+	this.relocatable.NewBytecode(abi.GET_ACCESS, []int64{}, []string{"handle"})
+	this.CodegenExpr(foreach.Get(0))
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.relocatable.NewBytecode(get_op_code, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
 
+	if i_symbol_name != "" {
 		this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{i_symbol_name})
-		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{int64(i)}, []string{})
+		this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
 		this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
-
-		this.CodegenStmt(dpu_foreach_stmt.Body())
 	}
 
+	this.CodegenStmt(dpu_foreach_stmt.Body())
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
+
+	this.relocatable.SwitchLabel(end_label.Name())
 	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
+	this.cur_block_depth--
+
+	this.relocatable.EndLoop()
 }
 
 func (this *Codegen) CodegenWhileStmt(while_stmt *stmt.WhileStmt) {
 	condition_label, body_label, end_label := this.relocatable.NewLoop()
 
+	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
+	this.block_depths[condition_label.Name()] = this.cur_block_depth
+	this.block_depths[end_label.Name()] = this.cur_block_depth
+	this.cur_block_depth++
 	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
 
 	this.relocatable.SwitchLabel(condition_label.Name())
 	this.CodegenExpr(while_stmt.Condition())
 	this.relocatable.NewBytecode(abi.JUMP_IF_NONZERO, []int64{}, []string{body_label.Name()})
-	this.CodegenExpr(while_stmt.Condition())
-	this.relocatable.NewBytecode(abi.JUMP_IF_ZERO, []int64{}, []string{end_label.Name()})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{end_label.Name()})
 
 	this.relocatable.SwitchLabel(body_label.Name())
 	this.CodegenStmt(while_stmt.Body())
 	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
 
 	this.relocatable.SwitchLabel(end_label.Name())
+	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
+	this.cur_block_depth--
+
+	this.relocatable.EndLoop()
+}
+
+func (this *Codegen) CodegenDoWhileStmt(do_while_stmt *stmt.DoWhileStmt) {
+	condition_label, body_label, end_label := this.relocatable.NewLoop()
+
+	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
+	this.block_depths[condition_label.Name()] = this.cur_block_depth
+	this.block_depths[end_label.Name()] = this.cur_block_depth
+	this.cur_block_depth++
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{body_label.Name()})
+
+	this.relocatable.SwitchLabel(condition_label.Name())
+	this.CodegenExpr(do_while_stmt.Condition())
+	this.relocatable.NewBytecode(abi.JUMP_IF_NONZERO, []int64{}, []string{body_label.Name()})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{end_label.Name()})
+
+	this.relocatable.SwitchLabel(body_label.Name())
+	this.CodegenStmt(do_while_stmt.Body())
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
+
+	this.relocatable.SwitchLabel(end_label.Name())
+	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
+	this.cur_block_depth--
+
+	this.relocatable.EndLoop()
+}
+
+// NOTE: the condition of a switch is kept in a hidden long, which is compared with each case in
+// turn, and the body is generated in the scope of the switch, so that jumping to a case does not
+// skip a NEW_SCOPE
+func (this *Codegen) CodegenSwitchStmt(switch_stmt *stmt.SwitchStmt) {
+	end_label := this.relocatable.NewSwitch()
+
+	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
+	this.block_depths[end_label.Name()] = this.cur_block_depth
+	this.cur_block_depth++
+
+	condition_name := "__switch_" + end_label.Name()
+	this.relocatable.NewBytecode(abi.NEW_FAST_LONG, []int64{0}, []string{condition_name})
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{condition_name})
+	this.CodegenExpr(switch_stmt.Condition())
+	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+
+	body := switch_stmt.Body().BlockStmt()
+
+	case_labels := make(map[int]*abi.Label)
+	default_label := end_label
+	for i := 0; i < body.Length(); i++ {
+		stmt_ := body.Get(i)
+
+		if stmt_.StmtType() == stmt.CASE {
+			case_labels[i] = this.relocatable.NewUnnamedLabel()
+
+			this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{condition_name})
+			this.CodegenExpr(stmt_.CaseStmt().Value())
+			this.relocatable.NewBytecode(abi.EQ, []int64{}, []string{})
+			this.relocatable.NewBytecode(abi.JUMP_IF_NONZERO, []int64{}, []string{case_labels[i].Name()})
+		} else if stmt_.StmtType() == stmt.DEFAULT {
+			case_labels[i] = this.relocatable.NewUnnamedLabel()
+			default_label = case_labels[i]
+		}
+	}
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{default_label.Name()})
+
+	for i := 0; i < body.Length(); i++ {
+		if case_label, found := case_labels[i]; found {
+			this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{case_label.Name()})
+			this.relocatable.SwitchLabel(case_label.Name())
+		} else {
+			this.CodegenStmt(body.Get(i))
+		}
+	}
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{end_label.Name()})
+
+	this.relocatable.SwitchLabel(end_label.Name())
+	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
+	this.cur_block_depth--
+
+	this.relocatable.EndLoop()
 }
 
 func (this *Codegen) CodegenContinueStmt(continue_stmt *stmt.ContinueStmt) {
@@ -600,7 +945,6 @@ func (this *Codegen) CodegenContinueStmt(continue_stmt *stmt.ContinueStmt) {
 	for i := 0; i < this.cur_block_depth-loop_depth-1; i++ {
 		this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
 	}
-	this.cur_block_depth = loop_depth
 
 	this.relocatable.NewBytecode(
 		abi.JUMP,
@@ -610,11 +954,10 @@ func (this *Codegen) CodegenContinueStmt(continue_stmt *stmt.ContinueStmt) {
 }
 
 func (this *Codegen) CodegenBreakStmt(break_stmt *stmt.BreakStmt) {
-	loop_depth := this.block_depths[this.relocatable.CurLoopCondition().Name()]
+	loop_depth := this.block_depths[this.relocatable.CurLoopEnd().Name()]
 	for i := 0; i < this.cur_block_depth-loop_depth-1; i++ {
 		this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
 	}
-	this.cur_block_depth = loop_depth
 
 	this.relocatable.NewBytecode(
 		abi.JUMP,
@@ -693,13 +1036,17 @@ func (this *Codegen) CodegenReturnStmt(return_stmt *stmt.ReturnStmt) {
 				[]string{},
 			)
 		} else if method.Symbol().SymbolType() == type_system.CHAR {
-			this.relocatable.NewBytecode(abi.NEW_RETURN_CHAR, []int64{int64(method.Symbol().NumStars())}, []string{})
+			this.relocatable.NewBytecode(abi.NEW_RETURN_CHAR, this.SymbolArgs(method.Symbol()), []string{})
 		} else if method.Symbol().SymbolType() == type_system.SHORT {
-			this.relocatable.NewBytecode(abi.NEW_RETURN_SHORT, []int64{int64(method.Symbol().NumStars())}, []string{})
+			this.relocatable.NewBytecode(abi.NEW_RETURN_SHORT, this.SymbolArgs(method.Symbol()), []string{})
 		} else if method.Symbol().SymbolType() == type_system.INT {
-			this.relocatable.NewBytecode(abi.NEW_RETURN_INT, []int64{int64(method.Symbol().NumStars())}, []string{})
+			this.relocatable.NewBytecode(abi.NEW_RETURN_INT, this.SymbolArgs(method.Symbol()), []string{})
 		} else if method.Symbol().SymbolType() == type_system.LONG {
-			this.relocatable.NewBytecode(abi.NEW_RETURN_LONG, []int64{int64(method.Symbol().NumStars())}, []string{})
+			this.relocatable.NewBytecode(abi.NEW_RETURN_LONG, this.SymbolArgs(method.Symbol()), []string{})
+		} else if method.Symbol().SymbolType() == type_system.FLOAT {
+			this.relocatable.NewBytecode(abi.NEW_RETURN_FLOAT, []int64{int64(method.Symbol().NumStars())}, []string{})
+		} else if method.Symbol().SymbolType() == type_system.DOUBLE {
+			this.relocatable.NewBytecode(abi.NEW_RETURN_DOUBLE, []int64{int64(method.Symbol().NumStars())}, []string{})
 		} else if method.Symbol().SymbolType() == type_system.STRUCT {
 			this.relocatable.NewBytecode(abi.NEW_RETURN_STRUCT, []int64{int64(method.Symbol().NumStars())}, []string{method.Symbol().StructName()})
 		} else {
@@ -714,10 +1061,16 @@ func (this *Codegen) CodegenReturnStmt(return_stmt *stmt.ReturnStmt) {
 	this.relocatable.NewBytecode(abi.RETURN, []int64{}, []string{})
 }
 
+// NOTE: an assignment of an expr stmt is the only one whose value is not used
 func (this *Codegen) CodegenExprStmt(expr_stmt *stmt.ExprStmt) {
-	this.CodegenExpr(expr_stmt.Expr())
+	if expr_stmt.Expr().ExprType() == expr.ASSIGNMENT {
+		this.CodegenAssignmentExpr(expr_stmt.Expr().AssignmentExpr(), false)
+	} else {
+		this.CodegenExpr(expr_stmt.Expr())
+	}
 }
 
+// NOTE: the end of a block has no position since its closing brace is not located
 func (this *Codegen) CodegenBlockStmt(block_stmt *stmt.BlockStmt) {
 	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
 	this.block_depths[this.relocatable.CurLabel().Name()] = this.cur_block_depth
@@ -728,10 +1081,22 @@ func (this *Codegen) CodegenBlockStmt(block_stmt *stmt.BlockStmt) {
 		this.CodegenStmt(stmt_)
 	}
 
+	prev_path := this.relocatable.CurPath()
+	prev_line := this.relocatable.CurLine()
+
+	this.relocatable.SetPosition("", 0)
 	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
+	this.relocatable.SetPosition(prev_path, prev_line)
 	this.cur_block_depth--
 }
 
+// NOTE: the declarators of a decl list are declared in the scope that the decl list is in
+func (this *Codegen) CodegenDeclListStmt(decl_list_stmt *stmt.DeclListStmt) {
+	for i := 0; i < decl_list_stmt.Length(); i++ {
+		this.CodegenStmt(decl_list_stmt.Get(i))
+	}
+}
+
 func (this *Codegen) CodegenExpr(expr_ *expr.Expr) {
 	if expr_.ExprType() == expr.PRIMARY {
 		this.CodegenPrimaryExpr(expr_.PrimaryExpr())
@@ -762,7 +1127,7 @@ func (this *Codegen) CodegenExpr(expr_ *expr.Expr) {
 	} else if expr_.ExprType() == expr.CONDITIONAL {
 		this.CodegenConditionalExpr(expr_.ConditionalExpr())
 	} else if expr_.ExprType() == expr.ASSIGNMENT {
-		this.CodegenAssignmentExpr(expr_.AssignmentExpr())
+		this.CodegenAssignmentExpr(expr_.AssignmentExpr(), true)
 	} else {
 		err := errors.New("expr type is not valid")
 		panic(err)
@@ -783,16 +1148,44 @@ func (this *Codegen) CodegenPrimaryExpr(primary_expr *expr.PrimaryExpr) {
 			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{0}, []string{})
 		} else if symbol_name == "DPU_SYNCHRONOUS" {
 			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{0}, []string{})
+		} else if symbol_name == "DPU_ASYNCHRONOUS" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{1}, []string{})
+		} else if symbol_name == "DPU_XFER_NO_RESET" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{1}, []string{})
+		} else if symbol_name == "DPU_ALLOCATE_ALL" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{-1}, []string{})
+		} else if symbol_name == "DPU_CALLBACK_DEFAULT" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{0}, []string{})
+		} else if symbol_name == "DPU_CALLBACK_ASYNC" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{1}, []string{})
+		} else if symbol_name == "DPU_CALLBACK_NONBLOCKING" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{2}, []string{})
+		} else if symbol_name == "DPU_CALLBACK_SINGLE_CALL" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{4}, []string{})
+		} else if symbol_name == "DPU_OK" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{0}, []string{})
+		} else if symbol_name == "stdout" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{1}, []string{})
+		} else if symbol_name == "stderr" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{2}, []string{})
+		} else if symbol_name == "RAND_MAX" {
+			this.relocatable.NewBytecode(abi.PUSH_INT, []int64{32767}, []string{})
 		} else {
 			this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{symbol_name})
 		}
 	} else if primary_expr.PrimaryExprType() == expr.NUMBER {
-		number, err := strconv.ParseInt(primary_expr.Token().Attribute(), 10, 64)
-		if err != nil {
-			panic(err)
-		}
+		this.CodegenInteger(primary_expr.Token())
+	} else if primary_expr.PrimaryExprType() == expr.FLOATING {
+		token := primary_expr.Token()
+		bits := int64(math.Float64bits(token.Floating()))
 
-		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{number}, []string{})
+		if token.IsFloat() {
+			this.relocatable.NewBytecode(abi.PUSH_FLOAT, []int64{bits}, []string{})
+		} else {
+			this.relocatable.NewBytecode(abi.PUSH_DOUBLE, []int64{bits}, []string{})
+		}
+	} else if primary_expr.PrimaryExprType() == expr.CHARACTER {
+		this.CodegenInteger(primary_expr.Token())
 	} else if primary_expr.PrimaryExprType() == expr.STRING {
 		str := primary_expr.Token().Attribute()
 
@@ -801,12 +1194,25 @@ func (this *Codegen) CodegenPrimaryExpr(primary_expr *expr.PrimaryExpr) {
 		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{0}, []string{})
 	} else if primary_expr.PrimaryExprType() == expr.PAREN {
 		this.CodegenExpr(primary_expr.Expr())
+	} else if primary_expr.PrimaryExprType() == expr.INITIALIZER_LIST {
+		err := errors.New("initializer list is not in a declaration")
+		panic(err)
 	} else {
 		err := errors.New("primary expr type is not valid")
 		panic(err)
 	}
 }
 
+func (this *Codegen) CodegenInteger(token *lexer.Token) {
+	value := token.Integer()
+
+	if token.IsLong() || value > math.MaxInt32 {
+		this.relocatable.NewBytecode(abi.PUSH_LONG, []int64{value}, []string{})
+	} else {
+		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{value}, []string{})
+	}
+}
+
 func (this *Codegen) CodegenPostfixExpr(postfix_expr *expr.PostfixExpr) {
 	if postfix_expr.PostfixExprType() == expr.BRACKET {
 		this.CodegenExpr(postfix_expr.Base())
@@ -848,14 +1254,54 @@ func (this *Codegen) CodegenPostfixExpr(postfix_expr *expr.PostfixExpr) {
 			}
 
 			this.relocatable.NewBytecode(abi.SQRT, []int64{}, []string{})
+		} else if func_name == "printf" || func_name == "fprintf" {
+			this.CodegenPrintf(func_name, postfix_expr)
+		} else if func_name == "exit" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
+			}
+
+			this.relocatable.NewBytecode(abi.EXIT, []int64{}, []string{})
+		} else if func_name == "atoi" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
+			}
+
+			this.relocatable.NewBytecode(abi.ATOI, []int64{}, []string{})
+		} else if func_name == "rand" {
+			this.relocatable.NewBytecode(abi.RAND, []int64{}, []string{})
+		} else if func_name == "srand" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
+			}
+
+			this.relocatable.NewBytecode(abi.SRAND, []int64{}, []string{})
+		} else if func_name == "gettimeofday" {
+			this.CodegenExpr(postfix_expr.ArgList().Get(0))
+
+			this.relocatable.NewBytecode(abi.GETTIMEOFDAY, []int64{}, []string{})
+		} else if func_name == "getopt" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
+			}
+
+			this.relocatable.NewBytecode(abi.GETOPT, []int64{}, []string{})
 		} else if func_name == "dpu_alloc" {
-			for i := 0; i < this.num_dpus; i++ {
-				this.relocatable.NewBytecode(abi.DPU_ALLOC, []int64{int64(i)}, []string{})
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
 			}
-		} else if func_name == "dpu_load" {
-			for i := 0; i < this.num_dpus; i++ {
-				this.relocatable.NewBytecode(abi.DPU_LOAD, []int64{int64(i)}, []string{this.benchmark})
+
+			this.relocatable.NewBytecode(abi.DPU_ALLOC, []int64{}, []string{})
+		} else if func_name == "dpu_alloc_ranks" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
 			}
+
+			this.relocatable.NewBytecode(abi.DPU_ALLOC_RANKS, []int64{}, []string{})
+		} else if func_name == "dpu_load" {
+			this.CodegenExpr(postfix_expr.ArgList().Get(0))
+
+			this.relocatable.NewBytecode(abi.DPU_LOAD, []int64{}, []string{})
 		} else if func_name == "dpu_prepare_xfer" {
 			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
 				this.CodegenExpr(postfix_expr.ArgList().Get(i))
@@ -868,6 +1314,12 @@ func (this *Codegen) CodegenPostfixExpr(postfix_expr *expr.PostfixExpr) {
 			}
 
 			this.relocatable.NewBytecode(abi.DPU_TRANSFER, []int64{}, []string{})
+		} else if func_name == "dpu_broadcast_to" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
+			}
+
+			this.relocatable.NewBytecode(abi.DPU_BROADCAST, []int64{}, []string{})
 		} else if func_name == "dpu_copy_to" {
 			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
 				this.CodegenExpr(postfix_expr.ArgList().Get(i))
@@ -886,8 +1338,34 @@ func (this *Codegen) CodegenPostfixExpr(postfix_expr *expr.PostfixExpr) {
 			}
 
 			this.relocatable.NewBytecode(abi.DPU_LAUNCH, []int64{}, []string{})
+		} else if func_name == "dpu_sync" {
+			for i := 0; i < postfix_expr.ArgList().Length(); i++ {
+				this.CodegenExpr(postfix_expr.ArgList().Get(i))
+			}
+
+			this.relocatable.NewBytecode(abi.DPU_SYNC, []int64{}, []string{})
 		} else if func_name == "dpu_free" {
+			this.CodegenExpr(postfix_expr.ArgList().Get(0))
+
 			this.relocatable.NewBytecode(abi.DPU_FREE, []int64{}, []string{})
+		} else if func_name == "dpu_get_nr_dpus" || func_name == "dpu_get_nr_ranks" {
+			this.CodegenExpr(postfix_expr.ArgList().Get(1))
+			this.relocatable.NewBytecode(abi.GET_VALUE, []int64{}, []string{})
+			this.CodegenExpr(postfix_expr.ArgList().Get(0))
+
+			if func_name == "dpu_get_nr_dpus" {
+				this.relocatable.NewBytecode(abi.DPU_NR_DPUS, []int64{}, []string{})
+			} else {
+				this.relocatable.NewBytecode(abi.DPU_NR_RANKS, []int64{}, []string{})
+			}
+
+			this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+		} else if func_name == "dpu_log_read" {
+			this.CodegenExpr(postfix_expr.ArgList().Get(0))
+
+			this.relocatable.NewBytecode(abi.DPU_LOG_READ, []int64{}, []string{})
+		} else if func_name == "dpu_callback" {
+			this.CodegenDpuCallback(postfix_expr)
 		} else {
 			method := this.type_system.Method(func_name)
 			params := method.Params()
@@ -895,22 +1373,7 @@ func (this *Codegen) CodegenPostfixExpr(postfix_expr *expr.PostfixExpr) {
 			for i, param := range params {
 				arg := postfix_expr.ArgList().Get(i)
 
-				if param.SymbolType() == type_system.VOID {
-					this.relocatable.NewBytecode(abi.NEW_ARG_VOID, []int64{int64(param.NumStars())}, []string{param.Name()})
-				} else if param.SymbolType() == type_system.CHAR {
-					this.relocatable.NewBytecode(abi.NEW_ARG_CHAR, []int64{int64(param.NumStars())}, []string{param.Name()})
-				} else if param.SymbolType() == type_system.SHORT {
-					this.relocatable.NewBytecode(abi.NEW_ARG_SHORT, []int64{int64(param.NumStars())}, []string{param.Name()})
-				} else if param.SymbolType() == type_system.INT {
-					this.relocatable.NewBytecode(abi.NEW_ARG_INT, []int64{int64(param.NumStars())}, []string{param.Name()})
-				} else if param.SymbolType() == type_system.LONG {
-					this.relocatable.NewBytecode(abi.NEW_ARG_LONG, []int64{int64(param.NumStars())}, []string{param.Name()})
-				} else if param.SymbolType() == type_system.STRUCT {
-					this.relocatable.NewBytecode(abi.NEW_ARG_STRUCT, []int64{int64(param.NumStars())}, []string{param.StructName(), param.Name()})
-				} else {
-					err := errors.New("symbol type is not valid")
-					panic(err)
-				}
+				this.CodegenNewArg(param)
 
 				this.relocatable.NewBytecode(abi.GET_ARG_IDENTIFIER, []int64{0}, []string{param.Name()})
 				this.CodegenExpr(arg)
@@ -943,6 +1406,130 @@ func (this *Codegen) CodegenPostfixExpr(postfix_expr *expr.PostfixExpr) {
 	}
 }
 
+// NOTE: printf prints to stdout, i.e. the stream 1 of fprintf, and the number of values that
+// follow the format tells the VM where the stream and the format are on the stack
+func (this *Codegen) CodegenPrintf(func_name string, postfix_expr *expr.PostfixExpr) {
+	arg_list := postfix_expr.ArgList()
+
+	format_index := 0
+	if func_name == "printf" {
+		this.relocatable.NewBytecode(abi.PUSH_INT, []int64{1}, []string{})
+	} else {
+		this.CodegenExpr(arg_list.Get(0))
+		format_index = 1
+	}
+
+	for i := format_index; i < arg_list.Length(); i++ {
+		this.CodegenExpr(arg_list.Get(i))
+	}
+
+	num_values := int64(arg_list.Length() - format_index - 1)
+	this.relocatable.NewBytecode(abi.PRINTF, []int64{num_values}, []string{})
+}
+
+// NOTE: dpu_callback is called on the host core once for the whole set or once for every rank of
+// the set, in order and after the DPUs of the set are synchronized, so that an asynchronous
+// callback is serialized with the rest of the host program
+func (this *Codegen) CodegenDpuCallback(postfix_expr *expr.PostfixExpr) {
+	if postfix_expr.ArgList().Get(1).ExprType() != expr.PRIMARY {
+		err := errors.New("second argument's expr type is not primary")
+		panic(err)
+	} else if postfix_expr.ArgList().Get(1).PrimaryExpr().PrimaryExprType() != expr.IDENTIFIER {
+		err := errors.New("second argument's primary expr type is not identifier")
+		panic(err)
+	}
+
+	dpu_set := postfix_expr.ArgList().Get(0)
+	func_name := postfix_expr.ArgList().Get(1).PrimaryExpr().Token().Attribute()
+	args := postfix_expr.ArgList().Get(2)
+	flags := postfix_expr.ArgList().Get(3)
+
+	method := this.type_system.Method(func_name)
+	params := method.Params()
+
+	if len(params) != 3 {
+		err := errors.New("callback does not have 3 params")
+		panic(err)
+	}
+
+	this.CodegenExpr(dpu_set)
+	this.relocatable.NewBytecode(abi.DPU_SYNC, []int64{}, []string{})
+
+	condition_label := this.relocatable.NewUnnamedLabel()
+	body_label := this.relocatable.NewUnnamedLabel()
+	end_label := this.relocatable.NewUnnamedLabel()
+
+	this.relocatable.NewBytecode(abi.NEW_SCOPE, []int64{}, []string{})
+	this.cur_block_depth++
+
+	index_name := "__dpu_callback_" + end_label.Name()
+	this.relocatable.NewBytecode(abi.NEW_FAST_INT, []int64{0}, []string{index_name})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
+
+	this.relocatable.SwitchLabel(condition_label.Name())
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.CodegenExpr(dpu_set)
+	this.CodegenExpr(flags)
+	this.relocatable.NewBytecode(abi.DPU_NR_CALLBACKS, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.LESS, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.JUMP_IF_NONZERO, []int64{}, []string{body_label.Name()})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{end_label.Name()})
+
+	this.relocatable.SwitchLabel(body_label.Name())
+
+	this.CodegenNewArg(params[0])
+	this.relocatable.NewBytecode(abi.GET_ARG_IDENTIFIER, []int64{0}, []string{params[0].Name()})
+	this.relocatable.NewBytecode(abi.GET_ACCESS, []int64{}, []string{"handle"})
+	this.CodegenExpr(dpu_set)
+	this.CodegenExpr(flags)
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.relocatable.NewBytecode(abi.DPU_GET_CALLBACK, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+
+	this.CodegenNewArg(params[1])
+	this.relocatable.NewBytecode(abi.GET_ARG_IDENTIFIER, []int64{0}, []string{params[1].Name()})
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+
+	this.CodegenNewArg(params[2])
+	this.relocatable.NewBytecode(abi.GET_ARG_IDENTIFIER, []int64{0}, []string{params[2].Name()})
+	this.CodegenExpr(args)
+	this.relocatable.NewBytecode(abi.ASSIGN, []int64{}, []string{})
+
+	this.relocatable.NewBytecode(abi.CALL, []int64{}, []string{func_name})
+
+	this.relocatable.NewBytecode(abi.GET_IDENTIFIER, []int64{}, []string{index_name})
+	this.relocatable.NewBytecode(abi.ASSIGN_PLUS_PLUS, []int64{}, []string{})
+	this.relocatable.NewBytecode(abi.JUMP, []int64{}, []string{condition_label.Name()})
+
+	this.relocatable.SwitchLabel(end_label.Name())
+	this.relocatable.NewBytecode(abi.DELETE_SCOPE, []int64{}, []string{})
+	this.cur_block_depth--
+}
+
+func (this *Codegen) CodegenNewArg(param *type_system.Symbol) {
+	if param.SymbolType() == type_system.VOID {
+		this.relocatable.NewBytecode(abi.NEW_ARG_VOID, []int64{int64(param.NumStars())}, []string{param.Name()})
+	} else if param.SymbolType() == type_system.CHAR {
+		this.relocatable.NewBytecode(abi.NEW_ARG_CHAR, this.SymbolArgs(param), []string{param.Name()})
+	} else if param.SymbolType() == type_system.SHORT {
+		this.relocatable.NewBytecode(abi.NEW_ARG_SHORT, this.SymbolArgs(param), []string{param.Name()})
+	} else if param.SymbolType() == type_system.INT {
+		this.relocatable.NewBytecode(abi.NEW_ARG_INT, this.SymbolArgs(param), []string{param.Name()})
+	} else if param.SymbolType() == type_system.LONG {
+		this.relocatable.NewBytecode(abi.NEW_ARG_LONG, this.SymbolArgs(param), []string{param.Name()})
+	} else if param.SymbolType() == type_system.FLOAT {
+		this.relocatable.NewBytecode(abi.NEW_ARG_FLOAT, []int64{int64(param.NumStars())}, []string{param.Name()})
+	} else if param.SymbolType() == type_system.DOUBLE {
+		this.relocatable.NewBytecode(abi.NEW_ARG_DOUBLE, []int64{int64(param.NumStars())}, []string{param.Name()})
+	} else if param.SymbolType() == type_system.STRUCT {
+		this.relocatable.NewBytecode(abi.NEW_ARG_STRUCT, []int64{int64(param.NumStars())}, []string{param.StructName(), param.Name()})
+	} else {
+		err := errors.New("symbol type is not valid")
+		panic(err)
+	}
+}
+
 func (this *Codegen) CodegenUnaryExpr(unary_expr *expr.UnaryExpr) {
 	if unary_expr.UnaryExprType() == expr.UNARY_PLUS_PLUS {
 		this.CodegenExpr(unary_expr.Base())
@@ -977,29 +1564,65 @@ func (this *Codegen) CodegenUnaryExpr(unary_expr *expr.UnaryExpr) {
 	} else if unary_expr.UnaryExprType() == expr.SIZEOF {
 		type_specifier_ := unary_expr.TypeSpecifier()
 
-		if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
-			if type_specifier_.NumStars() == 0 {
-				err := errors.New("num stars == 0")
-				panic(err)
-			}
+		this.CodegenSizeof(type_specifier_, type_specifier_.NumStars())
+	} else if unary_expr.UnaryExprType() == expr.CAST {
+		this.CodegenExpr(unary_expr.Base())
+		this.CodegenCast(unary_expr.TypeSpecifier())
+	} else {
+		err := errors.New("unary expr type is not valid")
+		panic(err)
+	}
+}
 
-			this.relocatable.NewBytecode(abi.SIZEOF_VOID, []int64{int64(type_specifier_.NumStars())}, []string{})
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
-			this.relocatable.NewBytecode(abi.SIZEOF_CHAR, []int64{int64(type_specifier_.NumStars())}, []string{})
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
-			this.relocatable.NewBytecode(abi.SIZEOF_SHORT, []int64{int64(type_specifier_.NumStars())}, []string{})
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
-			this.relocatable.NewBytecode(abi.SIZEOF_INT, []int64{int64(type_specifier_.NumStars())}, []string{})
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
-			this.relocatable.NewBytecode(abi.SIZEOF_LONG, []int64{int64(type_specifier_.NumStars())}, []string{})
-		} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
-			this.relocatable.NewBytecode(abi.SIZEOF_STRUCT, []int64{int64(type_specifier_.NumStars())}, []string{type_specifier_.StructIdentifier().Attribute()})
-		} else {
-			err := errors.New("type specifier type is not valid")
+func (this *Codegen) CodegenSizeof(type_specifier_ *type_specifier.TypeSpecifier, num_stars int) {
+	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
+		if num_stars == 0 {
+			err := errors.New("num stars == 0")
 			panic(err)
 		}
+
+		this.relocatable.NewBytecode(abi.SIZEOF_VOID, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
+		this.relocatable.NewBytecode(abi.SIZEOF_CHAR, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
+		this.relocatable.NewBytecode(abi.SIZEOF_SHORT, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
+		this.relocatable.NewBytecode(abi.SIZEOF_INT, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
+		this.relocatable.NewBytecode(abi.SIZEOF_LONG, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+		this.relocatable.NewBytecode(abi.SIZEOF_FLOAT, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+		this.relocatable.NewBytecode(abi.SIZEOF_DOUBLE, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
+		this.relocatable.NewBytecode(abi.SIZEOF_STRUCT, []int64{int64(num_stars)}, []string{type_specifier_.StructIdentifier().Attribute()})
 	} else {
-		err := errors.New("unary expr type is not valid")
+		err := errors.New("type specifier type is not valid")
+		panic(err)
+	}
+}
+
+func (this *Codegen) CodegenCast(type_specifier_ *type_specifier.TypeSpecifier) {
+	num_stars := type_specifier_.NumStars()
+
+	if type_specifier_.TypeSpecifierType() == type_specifier.VOID {
+		this.relocatable.NewBytecode(abi.CAST_VOID, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.CHAR {
+		this.relocatable.NewBytecode(abi.CAST_CHAR, this.TypeArgs(type_specifier_, num_stars), []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.SHORT {
+		this.relocatable.NewBytecode(abi.CAST_SHORT, this.TypeArgs(type_specifier_, num_stars), []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.INT {
+		this.relocatable.NewBytecode(abi.CAST_INT, this.TypeArgs(type_specifier_, num_stars), []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.LONG {
+		this.relocatable.NewBytecode(abi.CAST_LONG, this.TypeArgs(type_specifier_, num_stars), []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.FLOAT {
+		this.relocatable.NewBytecode(abi.CAST_FLOAT, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.DOUBLE {
+		this.relocatable.NewBytecode(abi.CAST_DOUBLE, []int64{int64(num_stars)}, []string{})
+	} else if type_specifier_.TypeSpecifierType() == type_specifier.STRUCT {
+		this.relocatable.NewBytecode(abi.CAST_STRUCT, []int64{int64(num_stars)}, []string{type_specifier_.StructIdentifier().Attribute()})
+	} else {
+		err := errors.New("type specifier type is not valid")
 		panic(err)
 	}
 }
@@ -1123,8 +1746,13 @@ func (this *Codegen) CodegenConditionalExpr(conditional_expr *expr.ConditionalEx
 	this.relocatable.NewBytecode(abi.CONDITIONAL, []int64{}, []string{})
 }
 
-func (this *Codegen) CodegenAssignmentExpr(assignment_expr *expr.AssignmentExpr) {
+// NOTE: the value of an assignment is its lvalue once it is assigned, which is left on the stack
+// below the operands of the assignment
+func (this *Codegen) CodegenAssignmentExpr(assignment_expr *expr.AssignmentExpr, is_value bool) {
 	this.CodegenExpr(assignment_expr.Lvalue())
+	if is_value {
+		this.relocatable.NewBytecode(abi.DUPLICATE, []int64{}, []string{})
+	}
 	this.CodegenExpr(assignment_expr.Rvalue())
 
 	if assignment_expr.AssignmentExprType() == expr.ASSIGN {
@@ -5,14 +5,17 @@ import (
 	"fmt"
 )
 
+// NOTE: a variadic method takes any number of args after its params, e.g. printf
 type Method struct {
-	symbol *Symbol
-	params []*Symbol
+	symbol      *Symbol
+	params      []*Symbol
+	is_variadic bool
 }
 
 func (this *Method) Init(symbol *Symbol) {
 	this.symbol = symbol
 	this.params = make([]*Symbol, 0)
+	this.is_variadic = false
 }
 
 func (this *Method) Symbol() *Symbol {
@@ -50,3 +53,11 @@ func (this *Method) Params() []*Symbol {
 func (this *Method) AppendParam(param *Symbol) {
 	this.params = append(this.params, param)
 }
+
+func (this *Method) IsVariadic() bool {
+	return this.is_variadic
+}
+
+func (this *Method) SetVariadic() {
+	this.is_variadic = true
+}
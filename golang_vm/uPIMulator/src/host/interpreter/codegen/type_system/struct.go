@@ -0,0 +1,38 @@
+package type_system
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Struct struct {
+	name   string
+	fields []*Symbol
+}
+
+func (this *Struct) Init(name string) {
+	this.name = name
+	this.fields = make([]*Symbol, 0)
+}
+
+func (this *Struct) Name() string {
+	return this.name
+}
+
+func (this *Struct) Length() int {
+	return len(this.fields)
+}
+
+func (this *Struct) Get(pos int) *Symbol {
+	if pos >= len(this.fields) {
+		err_msg := fmt.Sprintf("struct (%s) does not have %d fields", this.name, pos+1)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return this.fields[pos]
+}
+
+func (this *Struct) AppendField(field *Symbol) {
+	this.fields = append(this.fields, field)
+}
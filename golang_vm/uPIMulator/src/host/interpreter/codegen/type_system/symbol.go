@@ -14,12 +14,15 @@ const (
 	LONG
 	STRING
 	STRUCT
+	FLOAT
+	DOUBLE
 )
 
 type Symbol struct {
 	symbol_type SymbolType
 	struct_name *string
 	num_stars   int
+	is_unsigned bool
 
 	name string
 }
@@ -33,6 +36,7 @@ func (this *Symbol) InitPrimitive(symbol_type SymbolType, num_stars int, name st
 	this.symbol_type = symbol_type
 	this.struct_name = nil
 	this.num_stars = num_stars
+	this.is_unsigned = false
 	this.name = name
 }
 
@@ -53,6 +57,7 @@ func (this *Symbol) InitStruct(
 	*this.struct_name = struct_name
 
 	this.num_stars = num_stars
+	this.is_unsigned = false
 	this.name = name
 }
 
@@ -73,6 +78,20 @@ func (this *Symbol) NumStars() int {
 	return this.num_stars
 }
 
+func (this *Symbol) IsUnsigned() bool {
+	return this.is_unsigned
+}
+
+func (this *Symbol) SetUnsigned() {
+	if this.symbol_type != CHAR && this.symbol_type != SHORT && this.symbol_type != INT &&
+		this.symbol_type != LONG {
+		err := errors.New("symbol type is not an integer")
+		panic(err)
+	}
+
+	this.is_unsigned = true
+}
+
 func (this *Symbol) Name() string {
 	return this.name
 }
@@ -7,10 +7,12 @@ import (
 
 type TypeSystem struct {
 	methods map[string]*Method
+	structs map[string]*Struct
 }
 
 func (this *TypeSystem) Init() {
 	this.methods = make(map[string]*Method)
+	this.structs = make(map[string]*Struct)
 }
 
 func (this *TypeSystem) HasMethod(method_name string) bool {
@@ -31,3 +33,22 @@ func (this *TypeSystem) Method(method_name string) *Method {
 func (this *TypeSystem) AddMethod(method *Method) {
 	this.methods[method.Symbol().Name()] = method
 }
+
+func (this *TypeSystem) HasStruct(struct_name string) bool {
+	_, found := this.structs[struct_name]
+	return found
+}
+
+func (this *TypeSystem) Struct(struct_name string) *Struct {
+	if !this.HasStruct(struct_name) {
+		err_msg := fmt.Sprintf("struct (%s) is not found", struct_name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return this.structs[struct_name]
+}
+
+func (this *TypeSystem) AddStruct(struct_ *Struct) {
+	this.structs[struct_.Name()] = struct_
+}
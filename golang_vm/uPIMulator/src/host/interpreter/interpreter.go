@@ -2,7 +2,10 @@ package interpreter
 
 import (
 	"path/filepath"
+	"strconv"
+	"strings"
 	"uPIMulator/src/host/abi"
+	"uPIMulator/src/host/interpreter/checker"
 	"uPIMulator/src/host/interpreter/codegen"
 	"uPIMulator/src/host/interpreter/lexer"
 	"uPIMulator/src/host/interpreter/parser"
@@ -16,6 +19,7 @@ type Interpreter struct {
 	num_dpus         int
 	num_tasklets     int
 	data_prep_params int
+	host_defines     string
 
 	dpu_mram_heap_pointer_name int64
 
@@ -38,6 +42,7 @@ func (this *Interpreter) Init(
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
 	this.data_prep_params = int(command_line_parser.IntParameter("data_prep_params"))
+	this.host_defines = command_line_parser.StringParameter("host_defines")
 
 	this.dpu_mram_heap_pointer_name = dpu_mram_heap_pointer_name
 
@@ -48,10 +53,15 @@ func (this *Interpreter) Init(
 func (this *Interpreter) Interpret() {
 	this.Lex()
 	this.Parse()
+	this.Check()
 	this.Codegen()
 
-	binary_path := filepath.Join(this.bin_dirpath, "bytecode.txt")
-	this.binary.Dump(binary_path)
+	// NOTE: the text bytecode is only dumped for reading, while the VM loads the binary bytecode
+	text_path := filepath.Join(this.bin_dirpath, "bytecode.txt")
+	this.binary.Dump(text_path)
+
+	binary_path := filepath.Join(this.bin_dirpath, "bytecode.bin")
+	this.binary.DumpBinary(binary_path)
 }
 
 func (this *Interpreter) Lex() {
@@ -59,6 +69,23 @@ func (this *Interpreter) Lex() {
 
 	lexer_ := new(lexer.Lexer)
 	lexer_.Init()
+	lexer_.Predefine("NR_DPUS", strconv.Itoa(this.num_dpus))
+	lexer_.Predefine("NR_TASKLETS", strconv.Itoa(this.num_tasklets))
+
+	// NOTE: the host defines are the -D flags of the Makefile of a benchmark, e.g. "BL=10,INT32",
+	// where a macro without a value is defined as 1
+	for _, host_define := range strings.Split(this.host_defines, ",") {
+		if host_define == "" {
+			continue
+		}
+
+		name, value, has_value := strings.Cut(host_define, "=")
+		if !has_value {
+			value = "1"
+		}
+
+		lexer_.Predefine(name, value)
+	}
 
 	token_stream := lexer_.Lex(source_code)
 	this.binary.SetTokenStream(token_stream)
@@ -72,6 +99,13 @@ func (this *Interpreter) Parse() {
 	this.binary.SetAst(ast)
 }
 
+func (this *Interpreter) Check() {
+	checker_ := new(checker.Checker)
+	checker_.Init()
+
+	checker_.Check(this.binary.Ast())
+}
+
 func (this *Interpreter) Codegen() {
 	codegen_ := new(codegen.Codegen)
 	codegen_.Init(
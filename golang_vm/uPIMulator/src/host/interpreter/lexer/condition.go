@@ -0,0 +1,42 @@
+package lexer
+
+type Condition struct {
+	is_parent_active bool
+	is_active        bool
+	is_taken         bool
+	has_else         bool
+}
+
+func (this *Condition) Init(is_parent_active bool, is_true bool) {
+	this.is_parent_active = is_parent_active
+	this.is_active = is_parent_active && is_true
+	this.is_taken = this.is_active
+	this.has_else = false
+}
+
+func (this *Condition) IsParentActive() bool {
+	return this.is_parent_active
+}
+
+func (this *Condition) IsActive() bool {
+	return this.is_active
+}
+
+func (this *Condition) IsTaken() bool {
+	return this.is_taken
+}
+
+func (this *Condition) HasElse() bool {
+	return this.has_else
+}
+
+func (this *Condition) Elif(is_true bool) {
+	this.is_active = this.is_parent_active && !this.is_taken && is_true
+	this.is_taken = this.is_taken || this.is_active
+}
+
+func (this *Condition) Else() {
+	this.is_active = this.is_parent_active && !this.is_taken
+	this.is_taken = true
+	this.has_else = true
+}
@@ -0,0 +1,218 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NOTE: the constant evaluator folds the integer constant expressions of #if directives and enum
+// values, where identifiers that are not given as constants evaluate to 0
+type ConstantEvaluator struct {
+	token_stream *TokenStream
+	pos          int
+	constants    map[string]int64
+}
+
+func (this *ConstantEvaluator) Evaluate(
+	token_stream *TokenStream,
+	constants map[string]int64,
+) int64 {
+	this.token_stream = token_stream
+	this.pos = 0
+	this.constants = constants
+
+	value := this.EvaluateConditional()
+
+	if this.pos != this.token_stream.Length() {
+		err := errors.New("constant expression has trailing tokens")
+		panic(err)
+	}
+
+	return value
+}
+
+func (this *ConstantEvaluator) Peek() *Token {
+	if this.pos < this.token_stream.Length() {
+		return this.token_stream.Get(this.pos)
+	} else {
+		return nil
+	}
+}
+
+func (this *ConstantEvaluator) Accept(token_type TokenType) bool {
+	if token := this.Peek(); token != nil && token.TokenType() == token_type {
+		this.pos++
+		return true
+	} else {
+		return false
+	}
+}
+
+func (this *ConstantEvaluator) Expect(token_type TokenType) {
+	if !this.Accept(token_type) {
+		err := errors.New("constant expression is not valid")
+		panic(err)
+	}
+}
+
+func (this *ConstantEvaluator) EvaluateConditional() int64 {
+	condition := this.EvaluateBinary(0)
+
+	if this.Accept(QUESTION) {
+		true_value := this.EvaluateConditional()
+		this.Expect(COLON)
+		false_value := this.EvaluateConditional()
+
+		if condition != 0 {
+			return true_value
+		} else {
+			return false_value
+		}
+	}
+
+	return condition
+}
+
+func (this *ConstantEvaluator) BinaryOperators() [][]TokenType {
+	return [][]TokenType{
+		{OR_OR},
+		{AND_AND},
+		{OR},
+		{CARET},
+		{AND},
+		{EQ, NOT_EQ},
+		{LESS, LESS_EQ, GREATER, GREATER_EQ},
+		{LSHIFT, RSHIFT},
+		{PLUS, MINUS},
+		{STAR, DIV, MOD},
+	}
+}
+
+// NOTE: binary operators are evaluated by precedence climbing over the levels of the C grammar
+func (this *ConstantEvaluator) EvaluateBinary(level int) int64 {
+	binary_operators := this.BinaryOperators()
+
+	if level == len(binary_operators) {
+		return this.EvaluateUnary()
+	}
+
+	value := this.EvaluateBinary(level + 1)
+
+	for {
+		token := this.Peek()
+
+		is_operator := false
+		if token != nil {
+			for _, token_type := range binary_operators[level] {
+				if token.TokenType() == token_type {
+					is_operator = true
+				}
+			}
+		}
+
+		if !is_operator {
+			return value
+		}
+
+		this.pos++
+
+		value = this.Apply(token.TokenType(), value, this.EvaluateBinary(level+1))
+	}
+}
+
+func (this *ConstantEvaluator) Apply(token_type TokenType, lvalue int64, rvalue int64) int64 {
+	if token_type == OR_OR {
+		return this.BoolValue(lvalue != 0 || rvalue != 0)
+	} else if token_type == AND_AND {
+		return this.BoolValue(lvalue != 0 && rvalue != 0)
+	} else if token_type == OR {
+		return lvalue | rvalue
+	} else if token_type == CARET {
+		return lvalue ^ rvalue
+	} else if token_type == AND {
+		return lvalue & rvalue
+	} else if token_type == EQ {
+		return this.BoolValue(lvalue == rvalue)
+	} else if token_type == NOT_EQ {
+		return this.BoolValue(lvalue != rvalue)
+	} else if token_type == LESS {
+		return this.BoolValue(lvalue < rvalue)
+	} else if token_type == LESS_EQ {
+		return this.BoolValue(lvalue <= rvalue)
+	} else if token_type == GREATER {
+		return this.BoolValue(lvalue > rvalue)
+	} else if token_type == GREATER_EQ {
+		return this.BoolValue(lvalue >= rvalue)
+	} else if token_type == LSHIFT {
+		return lvalue << rvalue
+	} else if token_type == RSHIFT {
+		return lvalue >> rvalue
+	} else if token_type == PLUS {
+		return lvalue + rvalue
+	} else if token_type == MINUS {
+		return lvalue - rvalue
+	} else if token_type == STAR {
+		return lvalue * rvalue
+	} else if token_type == DIV || token_type == MOD {
+		if rvalue == 0 {
+			err := errors.New("constant expression is divided by zero")
+			panic(err)
+		}
+
+		if token_type == DIV {
+			return lvalue / rvalue
+		} else {
+			return lvalue % rvalue
+		}
+	} else {
+		err := errors.New("token type is not a binary operator")
+		panic(err)
+	}
+}
+
+func (this *ConstantEvaluator) EvaluateUnary() int64 {
+	if this.Accept(NOT) {
+		return this.BoolValue(this.EvaluateUnary() == 0)
+	} else if this.Accept(TILDE) {
+		return ^this.EvaluateUnary()
+	} else if this.Accept(MINUS) {
+		return -this.EvaluateUnary()
+	} else if this.Accept(PLUS) {
+		return this.EvaluateUnary()
+	} else {
+		return this.EvaluatePrimary()
+	}
+}
+
+func (this *ConstantEvaluator) EvaluatePrimary() int64 {
+	token := this.Peek()
+
+	if token == nil {
+		err := errors.New("constant expression ends unexpectedly")
+		panic(err)
+	}
+
+	this.pos++
+
+	if token.TokenType() == NUMBER || token.TokenType() == CHARACTER {
+		return token.Integer()
+	} else if token.TokenType() == IDENTIFIER {
+		return this.constants[token.Attribute()]
+	} else if token.TokenType() == LPAREN {
+		value := this.EvaluateConditional()
+		this.Expect(RPAREN)
+		return value
+	} else {
+		err_msg := fmt.Sprintf("token (%s) is not valid in a constant expression", token.Attribute())
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *ConstantEvaluator) BoolValue(condition bool) int64 {
+	if condition {
+		return 1
+	} else {
+		return 0
+	}
+}
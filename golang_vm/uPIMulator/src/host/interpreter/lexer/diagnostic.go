@@ -0,0 +1,106 @@
+package lexer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: a diagnostic is an error of the host program at a position of its source code, which is
+// reported as the C compilers do with the source line and a caret under the column, where the
+// path, the line or the column is left out if it is not known
+type Diagnostic struct {
+	path   string
+	line   int
+	column int
+
+	message string
+}
+
+func (this *Diagnostic) Init(path string, line int, column int, message string) {
+	this.path = path
+	this.line = line
+	this.column = column
+
+	this.message = message
+}
+
+func (this *Diagnostic) InitToken(token *Token, message string) {
+	if token != nil && token.HasPosition() {
+		this.Init(token.Path(), token.Line(), token.Column(), message)
+	} else {
+		this.Init("", 0, 0, message)
+	}
+}
+
+func (this *Diagnostic) Path() string {
+	return this.path
+}
+
+func (this *Diagnostic) Line() int {
+	return this.line
+}
+
+func (this *Diagnostic) Column() int {
+	return this.column
+}
+
+func (this *Diagnostic) Message() string {
+	return this.message
+}
+
+func (this *Diagnostic) Stringify() string {
+	if this.path == "" || this.line <= 0 {
+		return fmt.Sprintf("error: %s", this.message)
+	}
+
+	var builder strings.Builder
+
+	if this.column > 0 {
+		builder.WriteString(
+			fmt.Sprintf("%s:%d:%d: error: %s", this.path, this.line, this.column, this.message),
+		)
+	} else {
+		builder.WriteString(fmt.Sprintf("%s:%d: error: %s", this.path, this.line, this.message))
+	}
+
+	source_line, found := this.SourceLine()
+	if !found {
+		return builder.String()
+	}
+
+	builder.WriteString("\n    " + source_line)
+
+	if this.column > 0 && this.column <= len(source_line)+1 {
+		builder.WriteString("\n    ")
+
+		// NOTE: the tabs of the source line are kept, so that the caret is aligned with the column
+		for i := 0; i < this.column-1; i++ {
+			if source_line[i] == '\t' {
+				builder.WriteByte('\t')
+			} else {
+				builder.WriteByte(' ')
+			}
+		}
+		builder.WriteString("^")
+	}
+
+	return builder.String()
+}
+
+func (this *Diagnostic) SourceLine() (string, bool) {
+	if _, stat_err := os.Stat(this.path); stat_err != nil {
+		return "", false
+	}
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(this.path)
+
+	lines := file_scanner.ReadLines()
+	if this.line > len(lines) {
+		return "", false
+	}
+
+	return strings.TrimRight(lines[this.line-1], "\r"), true
+}
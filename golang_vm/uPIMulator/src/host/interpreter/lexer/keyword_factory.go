@@ -21,6 +21,18 @@ func (this *KeywordFactory) IsTokenizable(word string) bool {
 	return found
 }
 
+// NOTE: Keyword finds the keyword of a token type by its spelling, which is only done to report
+// diagnostics
+func (this *KeywordFactory) Keyword(token_type TokenType) (string, bool) {
+	for keyword, token_type_ := range this.keywords {
+		if token_type_ == token_type {
+			return keyword, true
+		}
+	}
+
+	return "", false
+}
+
 func (this *KeywordFactory) Tokenize(word string) *Token {
 	if _, found := this.keywords[word]; !found {
 		err := errors.New("word is not tokenizable")
@@ -2,39 +2,103 @@ package lexer
 
 import (
 	"errors"
+	"fmt"
 	"strings"
-	"uPIMulator/src/misc"
 )
 
 type Lexer struct {
-	tokenizer *Tokenizer
+	tokenizer        *Tokenizer
+	preprocessor     *Preprocessor
+	typedef_resolver *TypedefResolver
+
+	path string
+	line int
 }
 
 func (this *Lexer) Init() {
 	this.tokenizer = new(Tokenizer)
 	this.tokenizer.Init()
+
+	this.preprocessor = new(Preprocessor)
+	this.preprocessor.Init(this)
+
+	this.typedef_resolver = new(TypedefResolver)
+	this.typedef_resolver.Init()
+
+	this.path = ""
+	this.line = 0
 }
 
-func (this *Lexer) Lex(path string) *TokenStream {
-	file_scanner := new(misc.FileScanner)
-	file_scanner.Init(path)
+// NOTE: predefined macros are expanded in the host program as if they were given with -D
+func (this *Lexer) Predefine(name string, value string) {
+	this.preprocessor.Define(name, make([]string, 0), false, value)
+}
 
-	token_stream := new(TokenStream)
-	token_stream.Init()
+func (this *Lexer) Lex(path string) *TokenStream {
+	token_stream := this.preprocessor.Preprocess(path)
 
-	for _, line := range file_scanner.ReadLines() {
-		line = strings.Replace(line, "\n", "", -1)
-		token_stream.Merge(this.Tokenize(line))
-	}
+	token_stream = this.ConcatenateStrings(token_stream)
+	token_stream = this.typedef_resolver.Resolve(token_stream)
 
+	// NOTE: the end of file is located at the last token, where a diagnostic of an input that ends
+	// too early points at
 	end_of_file := new(Token)
 	end_of_file.Init(END_OF_FILE, "")
+	if token_stream.Length() > 0 {
+		last_token := token_stream.Get(token_stream.Length() - 1)
+		end_of_file.SetPosition(last_token.Path(), last_token.Line(), last_token.Column())
+	}
 
 	token_stream.Append(end_of_file)
 
 	return token_stream
 }
 
+// NOTE: adjacent string literals are concatenated into the first of them once the macros are
+// expanded, e.g., "[" ANSI_COLOR_GREEN "OK" makes a single string
+func (this *Lexer) ConcatenateStrings(token_stream *TokenStream) *TokenStream {
+	concatenated_token_stream := new(TokenStream)
+	concatenated_token_stream.Init()
+
+	for i := 0; i < token_stream.Length(); i++ {
+		token := token_stream.Get(i)
+
+		if token.TokenType() == STRING {
+			attribute := token.Attribute()
+			for i+1 < token_stream.Length() && token_stream.Get(i+1).TokenType() == STRING {
+				i++
+				attribute = attribute[:len(attribute)-1] + token_stream.Get(i).Attribute()[1:]
+			}
+
+			if attribute != token.Attribute() {
+				string_token := new(Token)
+				string_token.Init(STRING, attribute)
+				string_token.SetPosition(token.Path(), token.Line(), token.Column())
+				token = string_token
+			}
+		}
+
+		concatenated_token_stream.Append(token)
+	}
+
+	return concatenated_token_stream
+}
+
+func (this *Lexer) Path() string {
+	return this.path
+}
+
+func (this *Lexer) Line() int {
+	return this.line
+}
+
+// NOTE: Locate gives the tokens of the lines tokenized from now on the position of a source line,
+// where the column of a token is its offset in the line
+func (this *Lexer) Locate(path string, line int) {
+	this.path = path
+	this.line = line
+}
+
 func (this *Lexer) Tokenize(line string) *TokenStream {
 	token_stream := new(TokenStream)
 	token_stream.Init()
@@ -55,6 +119,7 @@ func (this *Lexer) Tokenize(line string) *TokenStream {
 		token, length := this.FindTokenWithMaxLength(line, prev_pos)
 
 		if token != nil {
+			token.SetPosition(this.path, this.line, prev_pos+1)
 			token_stream.Append(token)
 		}
 
@@ -65,12 +130,18 @@ func (this *Lexer) Tokenize(line string) *TokenStream {
 }
 
 func (this *Lexer) FindHeader(line string, prev_pos int) *Token {
-	for this.IsWhiteSpace(string(line[prev_pos])) {
+	for prev_pos < len(line)-1 && this.IsWhiteSpace(string(line[prev_pos])) {
 		prev_pos++
 	}
 
 	word := line[prev_pos:]
+
+	if !this.tokenizer.IsTokenizable(word) {
+		this.Report(prev_pos, fmt.Sprintf("header (%s) is not valid", word))
+	}
+
 	token := this.tokenizer.Tokenize(word)
+	token.SetPosition(this.path, this.line, prev_pos+1)
 	return token
 }
 
@@ -87,6 +158,10 @@ func (this *Lexer) FindTokenWithMaxLength(line string, prev_pos int) (*Token, in
 	if this.IsQuote(string(line[prev_pos])) {
 		next_quote_pos := this.FindNextQuote(line, prev_pos+1)
 
+		if next_quote_pos < 0 {
+			this.Report(prev_pos, "missing terminating \" character")
+		}
+
 		word := line[prev_pos : next_quote_pos+1]
 
 		token := this.tokenizer.Tokenize(word)
@@ -94,6 +169,38 @@ func (this *Lexer) FindTokenWithMaxLength(line string, prev_pos int) (*Token, in
 		return token, next_quote_pos - prev_pos + 1
 	}
 
+	if this.IsApostrophe(string(line[prev_pos])) {
+		next_apostrophe_pos := this.FindNextApostrophe(line, prev_pos+1)
+
+		if next_apostrophe_pos < 0 {
+			this.Report(prev_pos, "missing terminating ' character")
+		}
+
+		word := line[prev_pos : next_apostrophe_pos+1]
+
+		if !this.tokenizer.IsTokenizable(word) {
+			this.Report(prev_pos, fmt.Sprintf("character constant (%s) is not valid", word))
+		}
+
+		token := this.tokenizer.Tokenize(word)
+
+		return token, next_apostrophe_pos - prev_pos + 1
+	}
+
+	if this.IsNumberBegin(line, prev_pos) {
+		number_end_pos := this.FindNumberEnd(line, prev_pos)
+
+		word := line[prev_pos:number_end_pos]
+
+		if !this.tokenizer.IsTokenizable(word) {
+			this.Report(prev_pos, fmt.Sprintf("number (%s) is not valid", word))
+		}
+
+		token := this.tokenizer.Tokenize(word)
+
+		return token, number_end_pos - prev_pos
+	}
+
 	for i := prev_pos + 1; i <= len(line); i++ {
 		word := line[prev_pos:i]
 
@@ -105,6 +212,12 @@ func (this *Lexer) FindTokenWithMaxLength(line string, prev_pos int) (*Token, in
 				return token, i - prev_pos
 			}
 		} else {
+			// NOTE: any prefix of an identifier or a number is tokenizable, so that a word that
+			// is not tokenizable up to the end of the line begins with a stray character
+			if !this.tokenizer.IsTokenizable(word) {
+				this.Report(prev_pos, fmt.Sprintf("stray '%c' in program", line[prev_pos]))
+			}
+
 			token := this.tokenizer.Tokenize(word)
 			return token, i - prev_pos
 		}
@@ -114,13 +227,22 @@ func (this *Lexer) FindTokenWithMaxLength(line string, prev_pos int) (*Token, in
 	panic(err)
 }
 
+// NOTE: Report raises a diagnostic at a position of the line being tokenized
+func (this *Lexer) Report(pos int, message string) {
+	diagnostic := new(Diagnostic)
+	diagnostic.Init(this.path, this.line, pos+1, message)
+
+	err := errors.New(diagnostic.Stringify())
+	panic(err)
+}
+
 func (this *Lexer) IsWhiteSpace(word string) bool {
 	if len(word) != 1 {
 		err := errors.New("word size != 1")
 		panic(err)
 	}
 
-	return word == " " || word == "\t" || word == "\n"
+	return word == " " || word == "\t" || word == "\n" || word == "\r"
 }
 
 func (this *Lexer) IsQuote(word string) bool {
@@ -132,13 +254,75 @@ func (this *Lexer) IsQuote(word string) bool {
 	return word == "\""
 }
 
+// NOTE: FindNextQuote returns -1 if the string literal is not terminated in the line
 func (this *Lexer) FindNextQuote(line string, pos int) int {
 	for i := pos; i < len(line); i++ {
-		if this.IsQuote(string(line[i])) {
+		if line[i] == '\\' {
+			i++
+		} else if this.IsQuote(string(line[i])) {
 			return i
 		}
 	}
 
-	err := errors.New("line does not have the next quote")
-	panic(err)
+	return -1
+}
+
+func (this *Lexer) IsApostrophe(word string) bool {
+	if len(word) != 1 {
+		err := errors.New("word size != 1")
+		panic(err)
+	}
+
+	return word == "'"
+}
+
+func (this *Lexer) FindNextApostrophe(line string, pos int) int {
+	for i := pos; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if this.IsApostrophe(string(line[i])) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (this *Lexer) IsNumberBegin(line string, pos int) bool {
+	if this.IsDigit(line[pos]) {
+		return true
+	}
+
+	return line[pos] == '.' && pos+1 < len(line) && this.IsDigit(line[pos+1])
+}
+
+// NOTE: a number is scanned as a preprocessing number, so that hexadecimal digits, exponents and
+// suffixes are kept together before the tokenizer validates it
+func (this *Lexer) FindNumberEnd(line string, pos int) int {
+	for i := pos + 1; i < len(line); i++ {
+		char := line[i]
+
+		if this.IsDigit(char) || this.IsLetter(char) || char == '.' || char == '_' {
+			continue
+		} else if (char == '+' || char == '-') && strings.ContainsRune("eEpP", rune(line[i-1])) &&
+			!this.IsHex(line[pos:i]) {
+			continue
+		} else {
+			return i
+		}
+	}
+
+	return len(line)
+}
+
+func (this *Lexer) IsDigit(char byte) bool {
+	return '0' <= char && char <= '9'
+}
+
+func (this *Lexer) IsLetter(char byte) bool {
+	return ('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z')
+}
+
+func (this *Lexer) IsHex(word string) bool {
+	return strings.HasPrefix(word, "0x") || strings.HasPrefix(word, "0X")
 }
@@ -0,0 +1,102 @@
+package lexer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func lexSource(t *testing.T, source string) []TokenType {
+	path := filepath.Join(t.TempDir(), "app.c")
+	if err := os.WriteFile(path, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	lexer := new(Lexer)
+	lexer.Init()
+
+	token_stream := lexer.Lex(path)
+
+	token_types := make([]TokenType, 0)
+	for i := 0; i < token_stream.Length(); i++ {
+		token_types = append(token_types, token_stream.Get(i).TokenType())
+	}
+	return token_types
+}
+
+func TestLexTypedefOfBuiltinType(t *testing.T) {
+	sources := map[string]string{
+		"primitive": "typedef unsigned int uint32_t; uint32_t x;",
+		"struct":    "typedef struct { int a; } size_t; size_t x;",
+		"twice":     "typedef unsigned int uint32_t; typedef unsigned int uint32_t; uint32_t x;",
+	}
+
+	expected := map[string][]TokenType{
+		"primitive": {UNSIGNED, INT, IDENTIFIER, SEMI, END_OF_FILE},
+		"struct": {
+			STRUCT, IDENTIFIER, LBRACE, INT, IDENTIFIER, SEMI, RBRACE, SEMI,
+			STRUCT, IDENTIFIER, IDENTIFIER, SEMI, END_OF_FILE,
+		},
+		"twice": {UNSIGNED, INT, IDENTIFIER, SEMI, END_OF_FILE},
+	}
+
+	for name, source := range sources {
+		if token_types := lexSource(t, source); !slices.Equal(token_types, expected[name]) {
+			t.Errorf("%s typedef is lexed as %v", name, token_types)
+		}
+	}
+}
+
+func TestLexBuiltinTypes(t *testing.T) {
+	token_types := lexSource(t, "uint32_t x; int64_t y; bool z = true;")
+
+	expected := []TokenType{
+		UNSIGNED, INT, IDENTIFIER, SEMI,
+		LONG, IDENTIFIER, SEMI,
+		CHAR, IDENTIFIER, ASSIGN, NUMBER, SEMI,
+		END_OF_FILE,
+	}
+	if !slices.Equal(token_types, expected) {
+		t.Errorf("builtin types are lexed as %v", token_types)
+	}
+}
+
+func TestLexNegativeEnumConstants(t *testing.T) {
+	token_types := lexSource(t, "enum { LOW = -1, HIGH }; int x = LOW + HIGH;")
+
+	// NOTE: LOW is -1 and HIGH is 0, where only the negative one is wrapped in parentheses
+	expected := []TokenType{
+		INT, IDENTIFIER, ASSIGN, LPAREN, MINUS, NUMBER, RPAREN, PLUS, NUMBER, SEMI,
+		END_OF_FILE,
+	}
+	if !slices.Equal(token_types, expected) {
+		t.Errorf("negative enum constants are lexed as %v", token_types)
+	}
+}
+
+func TestLexStringConcatenation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.c")
+	source := "#define GREEN \"\\x1b[32m\"\nchar *s = \"[\" GREEN \"OK\"\n\t\"]\"; char *t = GREEN;"
+	if err := os.WriteFile(path, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	lexer := new(Lexer)
+	lexer.Init()
+	token_stream := lexer.Lex(path)
+
+	// NOTE: GREEN is only substituted where it is concatenated, and is kept as a value otherwise
+	attributes := make([]string, 0)
+	for i := 0; i < token_stream.Length(); i++ {
+		if token := token_stream.Get(i); token.TokenType() == STRING ||
+			token.TokenType() == IDENTIFIER {
+			attributes = append(attributes, token.Attribute())
+		}
+	}
+
+	expected := []string{"GREEN", "\"\\x1b[32m\"", "s", "\"[\\x1b[32mOK]\"", "t", "GREEN"}
+	if !slices.Equal(attributes, expected) {
+		t.Errorf("strings are lexed as %q", attributes)
+	}
+}
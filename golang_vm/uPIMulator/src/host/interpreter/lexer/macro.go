@@ -0,0 +1,35 @@
+package lexer
+
+type Macro struct {
+	name        string
+	params      []string
+	is_function bool
+	body        *TokenStream
+}
+
+func (this *Macro) Init(name string, params []string, is_function bool, body *TokenStream) {
+	this.name = name
+	this.params = params
+	this.is_function = is_function
+	this.body = body
+}
+
+func (this *Macro) Name() string {
+	return this.name
+}
+
+func (this *Macro) Params() []string {
+	return this.params
+}
+
+func (this *Macro) IsFunction() bool {
+	return this.is_function
+}
+
+func (this *Macro) IsVariadic() bool {
+	return len(this.params) > 0 && this.params[len(this.params)-1] == "..."
+}
+
+func (this *Macro) Body() *TokenStream {
+	return this.body
+}
@@ -0,0 +1,676 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: the preprocessor strips comments, evaluates conditional directives, inlines local headers
+// and expands macros at the token level, while a #define of a single number or string is kept as a
+// DEFINE directive so that the codegen still declares it as a global that can be overridden
+type Preprocessor struct {
+	lexer *Lexer
+
+	macros map[string]*Macro
+	values map[string]*Token
+
+	conditions []*Condition
+
+	token_stream *TokenStream
+	pending      *TokenStream
+
+	constant_evaluator *ConstantEvaluator
+}
+
+func (this *Preprocessor) Init(lexer *Lexer) {
+	this.lexer = lexer
+
+	this.macros = make(map[string]*Macro)
+	this.values = make(map[string]*Token)
+
+	this.conditions = make([]*Condition, 0)
+
+	this.token_stream = nil
+	this.pending = nil
+
+	this.constant_evaluator = new(ConstantEvaluator)
+
+	this.InitBuiltinMacros()
+}
+
+func (this *Preprocessor) InitBuiltinMacros() {
+	// NOTE: qualifiers do not change how the VM executes a program, so that they are dropped
+	qualifiers := []string{
+		"static", "const", "volatile", "extern", "inline", "register", "restrict", "__restrict",
+	}
+	for _, qualifier := range qualifiers {
+		this.Define(qualifier, make([]string, 0), false, "")
+	}
+
+	// NOTE: pointers of the VM are 4 bytes wide, so that size_t and uintptr_t are 32-bit
+	builtin_types := [][]string{
+		{"bool", "char"},
+		{"int8_t", "signed char"},
+		{"uint8_t", "unsigned char"},
+		{"int16_t", "short"},
+		{"uint16_t", "unsigned short"},
+		{"int32_t", "int"},
+		{"uint32_t", "unsigned int"},
+		{"int64_t", "long"},
+		{"uint64_t", "unsigned long"},
+		{"size_t", "unsigned int"},
+		{"ssize_t", "int"},
+		{"uintptr_t", "unsigned int"},
+		{"dpu_error_t", "int"},
+		{"true", "1"},
+		{"false", "0"},
+		{"nullptr", "NULL"},
+	}
+	for _, builtin_type := range builtin_types {
+		this.Define(builtin_type[0], make([]string, 0), false, builtin_type[1])
+	}
+
+	// NOTE: the DPU API of the VM does not fail, so that DPU_ASSERT only evaluates its argument
+	this.Define("DPU_ASSERT", []string{"x"}, true, "(x)")
+}
+
+func (this *Preprocessor) Define(name string, params []string, is_function bool, body string) {
+	macro := new(Macro)
+	macro.Init(name, params, is_function, this.lexer.Tokenize(body))
+
+	delete(this.values, name)
+	this.macros[name] = macro
+}
+
+func (this *Preprocessor) Undefine(name string) {
+	delete(this.macros, name)
+	delete(this.values, name)
+}
+
+func (this *Preprocessor) IsDefined(name string) bool {
+	_, is_macro := this.macros[name]
+	_, is_value := this.values[name]
+	return is_macro || is_value
+}
+
+func (this *Preprocessor) Preprocess(path string) *TokenStream {
+	this.token_stream = new(TokenStream)
+	this.token_stream.Init()
+
+	this.pending = new(TokenStream)
+	this.pending.Init()
+
+	this.PreprocessFile(path)
+
+	if len(this.conditions) != 0 {
+		err := errors.New("#if is not terminated by #endif")
+		panic(err)
+	}
+
+	this.Flush()
+
+	return this.token_stream
+}
+
+func (this *Preprocessor) PreprocessFile(path string) {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	lines := this.StripComments(path, this.JoinLines(file_scanner.ReadLines()))
+
+	prev_path := this.lexer.Path()
+	prev_line := this.lexer.Line()
+
+	for i, line := range lines {
+		this.lexer.Locate(path, i+1)
+
+		trimmed_line := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed_line, "#") {
+			this.PreprocessDirective(path, strings.TrimSpace(trimmed_line[1:]))
+		} else if this.IsActive() {
+			this.pending.Merge(this.lexer.Tokenize(line))
+		}
+	}
+
+	this.lexer.Locate(prev_path, prev_line)
+}
+
+// NOTE: a continued line is replaced by an empty line, so that the lines keep their numbers
+func (this *Preprocessor) JoinLines(lines []string) []string {
+	joined_lines := make([]string, 0)
+
+	joined_line := ""
+	num_continued_lines := 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.HasSuffix(line, "\\") {
+			joined_line += line[:len(line)-1]
+			num_continued_lines++
+			continue
+		}
+
+		joined_lines = append(joined_lines, joined_line+line)
+		for i := 0; i < num_continued_lines; i++ {
+			joined_lines = append(joined_lines, "")
+		}
+
+		joined_line = ""
+		num_continued_lines = 0
+	}
+
+	if num_continued_lines > 0 {
+		joined_lines = append(joined_lines, joined_line)
+	}
+
+	return joined_lines
+}
+
+// NOTE: a block comment is replaced by spaces, so that the tokens after it keep their columns
+func (this *Preprocessor) StripComments(path string, lines []string) []string {
+	stripped_lines := make([]string, 0)
+
+	is_block_comment := false
+	comment_line := 0
+	comment_column := 0
+	for line_number, line := range lines {
+		var builder strings.Builder
+
+		for i := 0; i < len(line); i++ {
+			if is_block_comment {
+				if strings.HasPrefix(line[i:], "*/") {
+					is_block_comment = false
+					builder.WriteString("  ")
+					i++
+				} else {
+					builder.WriteByte(' ')
+				}
+			} else if line[i] == '"' || line[i] == '\'' {
+				end := this.FindLiteralEnd(line, i)
+				builder.WriteString(line[i:end])
+				i = end - 1
+			} else if strings.HasPrefix(line[i:], "//") {
+				break
+			} else if strings.HasPrefix(line[i:], "/*") {
+				is_block_comment = true
+				comment_line = line_number + 1
+				comment_column = i + 1
+				builder.WriteString("  ")
+				i++
+			} else {
+				builder.WriteByte(line[i])
+			}
+		}
+
+		stripped_lines = append(stripped_lines, builder.String())
+	}
+
+	if is_block_comment {
+		diagnostic := new(Diagnostic)
+		diagnostic.Init(path, comment_line, comment_column, "unterminated comment")
+
+		err := errors.New(diagnostic.Stringify())
+		panic(err)
+	}
+
+	return stripped_lines
+}
+
+// NOTE: a literal that is not terminated is kept up to the end of the line, so that the lexer
+// reports it at its position
+func (this *Preprocessor) FindLiteralEnd(line string, pos int) int {
+	for i := pos + 1; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if line[i] == line[pos] {
+			return i + 1
+		}
+	}
+
+	return len(line)
+}
+
+func (this *Preprocessor) PreprocessDirective(path string, directive string) {
+	name, rest := this.SplitWord(directive)
+
+	if name == "ifdef" {
+		identifier, _ := this.SplitWord(strings.TrimSpace(rest))
+		this.PushCondition(this.IsActive() && this.IsDefined(identifier))
+	} else if name == "ifndef" {
+		identifier, _ := this.SplitWord(strings.TrimSpace(rest))
+		this.PushCondition(this.IsActive() && !this.IsDefined(identifier))
+	} else if name == "if" {
+		this.PushCondition(this.IsActive() && this.Evaluate(rest))
+	} else if name == "elif" {
+		condition := this.LastCondition("#elif")
+
+		if condition.HasElse() {
+			err := errors.New("#elif follows #else")
+			panic(err)
+		}
+
+		condition.Elif(condition.IsParentActive() && !condition.IsTaken() && this.Evaluate(rest))
+	} else if name == "else" {
+		condition := this.LastCondition("#else")
+
+		if condition.HasElse() {
+			err := errors.New("#else follows #else")
+			panic(err)
+		}
+
+		condition.Else()
+	} else if name == "endif" {
+		this.LastCondition("#endif")
+		this.conditions = this.conditions[:len(this.conditions)-1]
+	} else if !this.IsActive() {
+		return
+	} else if name == "include" {
+		this.Include(path, strings.TrimSpace(rest))
+	} else if name == "define" {
+		this.PreprocessDefine(strings.TrimSpace(rest))
+	} else if name == "undef" {
+		identifier, _ := this.SplitWord(strings.TrimSpace(rest))
+
+		this.Flush()
+		this.Undefine(identifier)
+	} else if name == "error" {
+		err_msg := fmt.Sprintf("#error %s", strings.TrimSpace(rest))
+		err := errors.New(err_msg)
+		panic(err)
+	} else if name == "pragma" || name == "line" || name == "warning" || name == "" {
+		// NOTE: these directives do not change the program
+		return
+	} else {
+		err_msg := fmt.Sprintf("directive (#%s) is not supported", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *Preprocessor) SplitWord(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		char := line[i]
+
+		if !this.lexer.IsLetter(char) && !this.lexer.IsDigit(char) && char != '_' {
+			return line[:i], line[i:]
+		}
+	}
+
+	return line, ""
+}
+
+func (this *Preprocessor) IsActive() bool {
+	return len(this.conditions) == 0 || this.conditions[len(this.conditions)-1].IsActive()
+}
+
+func (this *Preprocessor) PushCondition(is_true bool) {
+	condition := new(Condition)
+	condition.Init(this.IsActive(), is_true)
+
+	this.conditions = append(this.conditions, condition)
+}
+
+func (this *Preprocessor) LastCondition(directive string) *Condition {
+	if len(this.conditions) == 0 {
+		err_msg := fmt.Sprintf("%s does not have a matching #if", directive)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return this.conditions[len(this.conditions)-1]
+}
+
+// NOTE: local headers are inlined, while system headers are kept as INCLUDE directives
+func (this *Preprocessor) Include(path string, header string) {
+	if strings.HasPrefix(header, "\"") {
+		end := strings.Index(header[1:], "\"")
+
+		if end < 0 {
+			err_msg := fmt.Sprintf("header (%s) is not terminated", header)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.PreprocessFile(filepath.Join(filepath.Dir(path), header[1:end+1]))
+	} else if strings.HasPrefix(header, "<") {
+		this.pending.Merge(this.lexer.Tokenize("#include " + header))
+	} else {
+		err_msg := fmt.Sprintf("header (%s) is not valid", header)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *Preprocessor) PreprocessDefine(definition string) {
+	name, rest := this.SplitWord(definition)
+
+	if name == "" {
+		err_msg := fmt.Sprintf("#define %s does not have a name", definition)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	params := make([]string, 0)
+	is_function := false
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+
+		if end < 0 {
+			err_msg := fmt.Sprintf("params of macro (%s) are not terminated", name)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		for _, param := range strings.Split(rest[1:end], ",") {
+			param = strings.TrimSpace(param)
+
+			if param != "" {
+				params = append(params, param)
+			}
+		}
+
+		is_function = true
+		rest = rest[end+1:]
+	}
+
+	body := strings.TrimSpace(rest)
+
+	this.Flush()
+
+	if !is_function && this.IsValue(body) {
+		this.Undefine(name)
+		this.values[name] = this.lexer.Tokenize(body).Get(0)
+
+		this.token_stream.Merge(this.lexer.Tokenize("#define " + name + " " + body))
+	} else {
+		this.Define(name, params, is_function, body)
+	}
+}
+
+func (this *Preprocessor) IsValue(body string) bool {
+	token_stream := this.lexer.Tokenize(body)
+
+	if token_stream.Length() != 1 {
+		return false
+	}
+
+	token_type := token_stream.Get(0).TokenType()
+	return token_type == NUMBER || token_type == STRING
+}
+
+func (this *Preprocessor) Flush() {
+	this.token_stream.Merge(this.Expand(this.pending, make(map[string]bool)))
+
+	this.pending = new(TokenStream)
+	this.pending.Init()
+}
+
+// NOTE: the hidden macros are the ones being expanded, which stops a macro from expanding itself
+func (this *Preprocessor) Expand(token_stream *TokenStream, hidden map[string]bool) *TokenStream {
+	expanded_token_stream := new(TokenStream)
+	expanded_token_stream.Init()
+
+	for i := 0; i < token_stream.Length(); i++ {
+		token := token_stream.Get(i)
+
+		macro, found := this.macros[token.Attribute()]
+
+		if this.IsConcatenatedValue(token_stream, expanded_token_stream, i) {
+			expanded_token_stream.Append(this.values[token.Attribute()].Relocate(token))
+		} else if token.TokenType() != IDENTIFIER || !found || hidden[macro.Name()] {
+			expanded_token_stream.Append(token)
+		} else if !macro.IsFunction() && this.IsTypedefName(token_stream, i) {
+			// NOTE: a program that typedefs a builtin type, e.g. typedef unsigned int uint32_t;,
+			// defines the type itself, so that the name is kept and the builtin macro is dropped
+			this.Undefine(macro.Name())
+			expanded_token_stream.Append(token)
+		} else if !macro.IsFunction() {
+			expanded_token_stream.Merge(
+				this.Expand(macro.Body(), this.Hide(hidden, macro.Name())).Relocate(token),
+			)
+		} else if i+1 < token_stream.Length() && token_stream.Get(i+1).TokenType() == LPAREN {
+			args, end := this.FindArgs(macro, token_stream, i+1)
+
+			substituted_token_stream := this.Substitute(macro, args, hidden)
+			expanded_token_stream.Merge(
+				this.Expand(substituted_token_stream, this.Hide(hidden, macro.Name())).
+					Relocate(token),
+			)
+
+			i = end
+		} else {
+			expanded_token_stream.Append(token)
+		}
+	}
+
+	return expanded_token_stream
+}
+
+// NOTE: a string value is kept as its name like any other value unless it is next to a string
+// literal, e.g. "[" ANSI_COLOR_GREEN "OK", where it is substituted for the literals to be
+// concatenated
+func (this *Preprocessor) IsConcatenatedValue(
+	token_stream *TokenStream,
+	expanded_token_stream *TokenStream,
+	pos int,
+) bool {
+	is_string := func(token *Token) bool {
+		if token.TokenType() == STRING {
+			return true
+		}
+
+		value, found := this.values[token.Attribute()]
+		return token.TokenType() == IDENTIFIER && found && value.TokenType() == STRING
+	}
+
+	if !is_string(token_stream.Get(pos)) || token_stream.Get(pos).TokenType() == STRING {
+		return false
+	}
+
+	if expanded_token_stream.Length() > 0 &&
+		expanded_token_stream.Get(expanded_token_stream.Length()-1).TokenType() == STRING {
+		return true
+	}
+
+	return pos+1 < token_stream.Length() && is_string(token_stream.Get(pos+1))
+}
+
+// NOTE: the name of a typedef is the identifier before the semicolon that ends the typedef, where
+// the braces of a struct or an enum are skipped
+func (this *Preprocessor) IsTypedefName(token_stream *TokenStream, pos int) bool {
+	if pos+1 >= token_stream.Length() || token_stream.Get(pos+1).TokenType() != SEMI {
+		return false
+	}
+
+	depth := 0
+	for i := pos - 1; i >= 0; i-- {
+		token_type := token_stream.Get(i).TokenType()
+
+		if token_type == RBRACE {
+			depth++
+		} else if token_type == LBRACE {
+			if depth == 0 {
+				return false
+			}
+
+			depth--
+		} else if depth == 0 && token_type == SEMI {
+			return false
+		} else if depth == 0 && token_type == TYPEDEF {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (this *Preprocessor) Hide(hidden map[string]bool, name string) map[string]bool {
+	new_hidden := make(map[string]bool)
+	for hidden_name := range hidden {
+		new_hidden[hidden_name] = true
+	}
+	new_hidden[name] = true
+	return new_hidden
+}
+
+func (this *Preprocessor) FindArgs(
+	macro *Macro,
+	token_stream *TokenStream,
+	lparen_pos int,
+) ([]*TokenStream, int) {
+	args := make([]*TokenStream, 0)
+
+	arg := new(TokenStream)
+	arg.Init()
+
+	depth := 0
+	for i := lparen_pos; i < token_stream.Length(); i++ {
+		token := token_stream.Get(i)
+
+		if token.TokenType() == LPAREN {
+			depth++
+
+			if depth == 1 {
+				continue
+			}
+		} else if token.TokenType() == RPAREN {
+			depth--
+
+			if depth == 0 {
+				args = append(args, arg)
+				return args, i
+			}
+		} else if token.TokenType() == COMMA && depth == 1 {
+			args = append(args, arg)
+
+			arg = new(TokenStream)
+			arg.Init()
+			continue
+		}
+
+		arg.Append(token)
+	}
+
+	err_msg := fmt.Sprintf("invocation of macro (%s) is not terminated", macro.Name())
+	err := errors.New(err_msg)
+	panic(err)
+}
+
+func (this *Preprocessor) Substitute(
+	macro *Macro,
+	args []*TokenStream,
+	hidden map[string]bool,
+) *TokenStream {
+	params := macro.Params()
+
+	if len(params) == 0 && len(args) == 1 && args[0].Length() == 0 {
+		args = make([]*TokenStream, 0)
+	}
+
+	if macro.IsVariadic() && len(args) >= len(params)-1 {
+		variadic_arg := new(TokenStream)
+		variadic_arg.Init()
+
+		for i := len(params) - 1; i < len(args); i++ {
+			if i > len(params)-1 {
+				comma := new(Token)
+				comma.Init(COMMA, "")
+				variadic_arg.Append(comma)
+			}
+
+			variadic_arg.Merge(args[i])
+		}
+
+		args = append(args[:len(params)-1], variadic_arg)
+	}
+
+	if len(args) != len(params) {
+		err_msg := fmt.Sprintf(
+			"macro (%s) expects %d arguments but is given %d",
+			macro.Name(),
+			len(params),
+			len(args),
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	expanded_args := make(map[string]*TokenStream)
+	for i, param := range params {
+		if param == "..." {
+			param = "__VA_ARGS__"
+		}
+
+		expanded_args[param] = this.Expand(args[i], hidden)
+	}
+
+	substituted_token_stream := new(TokenStream)
+	substituted_token_stream.Init()
+
+	for i := 0; i < macro.Body().Length(); i++ {
+		token := macro.Body().Get(i)
+
+		if expanded_arg, found := expanded_args[token.Attribute()]; found &&
+			token.TokenType() == IDENTIFIER {
+			substituted_token_stream.Merge(expanded_arg)
+		} else {
+			substituted_token_stream.Append(token)
+		}
+	}
+
+	return substituted_token_stream
+}
+
+// NOTE: defined is resolved before the macro expansion, and identifiers that are left after the
+// expansion evaluate to 0 as in C
+func (this *Preprocessor) Evaluate(expr string) bool {
+	token_stream := this.lexer.Tokenize(expr)
+
+	resolved_token_stream := new(TokenStream)
+	resolved_token_stream.Init()
+
+	for i := 0; i < token_stream.Length(); i++ {
+		token := token_stream.Get(i)
+
+		if token.TokenType() == IDENTIFIER && token.Attribute() == "defined" {
+			var identifier *Token
+			if i+3 < token_stream.Length() && token_stream.Get(i+1).TokenType() == LPAREN &&
+				token_stream.Get(i+3).TokenType() == RPAREN {
+				identifier = token_stream.Get(i + 2)
+				i += 3
+			} else if i+1 < token_stream.Length() {
+				identifier = token_stream.Get(i + 1)
+				i += 1
+			} else {
+				err := errors.New("defined does not have an identifier")
+				panic(err)
+			}
+
+			is_defined := new(Token)
+			if this.IsDefined(identifier.Attribute()) {
+				is_defined.Init(NUMBER, "1")
+			} else {
+				is_defined.Init(NUMBER, "0")
+			}
+
+			resolved_token_stream.Append(is_defined)
+		} else {
+			resolved_token_stream.Append(token)
+		}
+	}
+
+	constants := make(map[string]int64)
+	for name, value := range this.values {
+		if value.TokenType() == NUMBER {
+			constants[name] = value.Integer()
+		}
+	}
+
+	expanded_token_stream := this.Expand(resolved_token_stream, make(map[string]bool))
+
+	return this.constant_evaluator.Evaluate(expanded_token_stream, constants) != 0
+}
@@ -1,5 +1,12 @@
 package lexer
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type TokenType int
 
 const (
@@ -8,6 +15,8 @@ const (
 	IDENTIFIER
 	NUMBER
 	STRING
+	CHARACTER
+	FLOATING
 	HEADER
 
 	INCLUDE
@@ -18,9 +27,15 @@ const (
 	ENDIF
 
 	BREAK
+	CASE
 	CHAR
 	CONTINUE
+	DEFAULT
+	DO
+	DOUBLE
 	ELSE
+	ENUM
+	FLOAT
 	FOR
 	IF
 	INT
@@ -28,8 +43,12 @@ const (
 	NULL
 	RETURN
 	SHORT
+	SIGNED
 	SIZEOF
 	STRUCT
+	SWITCH
+	TYPEDEF
+	UNSIGNED
 	VOID
 	WHILE
 
@@ -90,11 +109,19 @@ const (
 type Token struct {
 	token_type TokenType
 	attribute  string
+
+	path   string
+	line   int
+	column int
 }
 
 func (this *Token) Init(token_type TokenType, attribute string) {
 	this.token_type = token_type
 	this.attribute = attribute
+
+	this.path = ""
+	this.line = 0
+	this.column = 0
 }
 
 func (this *Token) TokenType() TokenType {
@@ -104,3 +131,154 @@ func (this *Token) TokenType() TokenType {
 func (this *Token) Attribute() string {
 	return this.attribute
 }
+
+// NOTE: the position of a token is the source line and column it is read from, or the position of
+// the macro invocation or the typedef name it is expanded from, while a token that is made up by
+// the lexer itself may not have a position
+func (this *Token) HasPosition() bool {
+	return this.line > 0
+}
+
+func (this *Token) Path() string {
+	return this.path
+}
+
+func (this *Token) Line() int {
+	return this.line
+}
+
+func (this *Token) Column() int {
+	return this.column
+}
+
+func (this *Token) SetPosition(path string, line int, column int) {
+	this.path = path
+	this.line = line
+	this.column = column
+}
+
+func (this *Token) Relocate(token *Token) *Token {
+	relocated_token := new(Token)
+	relocated_token.Init(this.token_type, this.attribute)
+	relocated_token.SetPosition(token.Path(), token.Line(), token.Column())
+	return relocated_token
+}
+
+// NOTE: an integer literal keeps its base prefix and suffixes in its attribute, and a character
+// literal evaluates to its code
+func (this *Token) Integer() int64 {
+	if this.token_type == CHARACTER {
+		return this.Character()
+	} else if this.token_type != NUMBER {
+		err := errors.New("token is not an integer literal")
+		panic(err)
+	}
+
+	literal := strings.TrimRight(this.attribute, "uUlL")
+
+	value, parse_err := strconv.ParseUint(literal, 0, 64)
+	if parse_err != nil {
+		panic(parse_err)
+	}
+
+	return int64(value)
+}
+
+func (this *Token) IsUnsigned() bool {
+	return this.token_type == NUMBER && strings.ContainsAny(this.attribute, "uU")
+}
+
+func (this *Token) IsLong() bool {
+	return this.token_type == NUMBER && strings.ContainsAny(this.attribute, "lL")
+}
+
+func (this *Token) Floating() float64 {
+	if this.token_type != FLOATING {
+		err := errors.New("token is not a floating point literal")
+		panic(err)
+	}
+
+	literal := strings.TrimRight(this.attribute, "fFlL")
+
+	value, parse_err := strconv.ParseFloat(literal, 64)
+	if parse_err != nil {
+		panic(parse_err)
+	}
+
+	return value
+}
+
+func (this *Token) IsFloat() bool {
+	return this.token_type == FLOATING && strings.ContainsAny(this.attribute, "fF")
+}
+
+func (this *Token) Character() int64 {
+	if this.token_type != CHARACTER {
+		err := errors.New("token is not a character literal")
+		panic(err)
+	}
+
+	literal := this.attribute[1 : len(this.attribute)-1]
+
+	if len(literal) == 1 {
+		return int64(literal[0])
+	}
+
+	escapes := map[string]int64{
+		"\\n":  '\n',
+		"\\t":  '\t',
+		"\\r":  '\r',
+		"\\0":  0,
+		"\\\\": '\\',
+		"\\'":  '\'',
+		"\\\"": '"',
+		"\\a":  '\a',
+		"\\b":  '\b',
+		"\\f":  '\f',
+		"\\v":  '\v',
+	}
+
+	if value, found := escapes[literal]; found {
+		return value
+	} else if strings.HasPrefix(literal, "\\x") {
+		value, parse_err := strconv.ParseInt(literal[2:], 16, 64)
+		if parse_err != nil {
+			panic(parse_err)
+		}
+
+		return value
+	} else if strings.HasPrefix(literal, "\\") {
+		value, parse_err := strconv.ParseInt(literal[1:], 8, 64)
+		if parse_err != nil {
+			panic(parse_err)
+		}
+
+		return value
+	} else {
+		err_msg := fmt.Sprintf("character literal (%s) is not valid", this.attribute)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// NOTE: a string literal evaluates to the codes of its characters and the terminating null
+// character when it initializes an array
+func (this *Token) Characters() []int64 {
+	if this.token_type != STRING {
+		err := errors.New("token is not a string literal")
+		panic(err)
+	}
+
+	literal, unquote_err := strconv.Unquote(this.attribute)
+	if unquote_err != nil {
+		panic(unquote_err)
+	}
+
+	characters := make([]int64, 0)
+	for i := 0; i < len(literal); i++ {
+		characters = append(characters, int64(literal[i]))
+	}
+	characters = append(characters, 0)
+
+	return characters
+}
@@ -25,3 +25,14 @@ func (this *TokenStream) Merge(token_stream *TokenStream) {
 		this.Append(token_stream.Get(i))
 	}
 }
+
+func (this *TokenStream) Relocate(token *Token) *TokenStream {
+	relocated_token_stream := new(TokenStream)
+	relocated_token_stream.Init()
+
+	for _, token_ := range this.tokens {
+		relocated_token_stream.Append(token_.Relocate(token))
+	}
+
+	return relocated_token_stream
+}
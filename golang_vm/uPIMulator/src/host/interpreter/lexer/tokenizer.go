@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"errors"
+	"fmt"
 )
 
 type Tokenizer struct {
@@ -26,9 +27,15 @@ func (this *Tokenizer) InitKeywordFactory() {
 	this.keyword_factory.AddKeyword("#endif", ENDIF)
 
 	this.keyword_factory.AddKeyword("break", BREAK)
+	this.keyword_factory.AddKeyword("case", CASE)
 	this.keyword_factory.AddKeyword("char", CHAR)
 	this.keyword_factory.AddKeyword("continue", CONTINUE)
+	this.keyword_factory.AddKeyword("default", DEFAULT)
+	this.keyword_factory.AddKeyword("do", DO)
+	this.keyword_factory.AddKeyword("double", DOUBLE)
 	this.keyword_factory.AddKeyword("else", ELSE)
+	this.keyword_factory.AddKeyword("enum", ENUM)
+	this.keyword_factory.AddKeyword("float", FLOAT)
 	this.keyword_factory.AddKeyword("for", FOR)
 	this.keyword_factory.AddKeyword("if", IF)
 	this.keyword_factory.AddKeyword("int", INT)
@@ -36,8 +43,12 @@ func (this *Tokenizer) InitKeywordFactory() {
 	this.keyword_factory.AddKeyword("NULL", NULL)
 	this.keyword_factory.AddKeyword("return", RETURN)
 	this.keyword_factory.AddKeyword("short", SHORT)
+	this.keyword_factory.AddKeyword("signed", SIGNED)
 	this.keyword_factory.AddKeyword("sizeof", SIZEOF)
 	this.keyword_factory.AddKeyword("struct", STRUCT)
+	this.keyword_factory.AddKeyword("switch", SWITCH)
+	this.keyword_factory.AddKeyword("typedef", TYPEDEF)
+	this.keyword_factory.AddKeyword("unsigned", UNSIGNED)
 	this.keyword_factory.AddKeyword("void", VOID)
 	this.keyword_factory.AddKeyword("while", WHILE)
 
@@ -100,8 +111,13 @@ func (this *Tokenizer) InitRegexFactory() {
 	this.regex_factory.Init()
 
 	this.regex_factory.AddRegex("^([A-Za-z_])([A-Za-z0-9_]*)$", IDENTIFIER)
-	this.regex_factory.AddRegex("^([0-9]+)$", NUMBER)
-	this.regex_factory.AddRegex("^\"([A-Za-z0-9/_.])*\"$", STRING)
+	this.regex_factory.AddRegex("^(0[xX][0-9A-Fa-f]+|[0-9]+)([uUlL]*)$", NUMBER)
+	this.regex_factory.AddRegex(
+		"^(([0-9]+\\.[0-9]*|\\.[0-9]+)([eE][+-]?[0-9]+)?|[0-9]+[eE][+-]?[0-9]+)[fFlL]?$",
+		FLOATING,
+	)
+	this.regex_factory.AddRegex("^\"([^\"\\\\]|\\\\.)*\"$", STRING)
+	this.regex_factory.AddRegex("^'([^'\\\\]|\\\\.)+'$", CHARACTER)
 	this.regex_factory.AddRegex("^<([A-Za-z0-9/_.]*)>$", HEADER)
 }
 
@@ -115,7 +131,42 @@ func (this *Tokenizer) Tokenize(word string) *Token {
 	} else if this.regex_factory.IsTokenizable(word) {
 		return this.regex_factory.Tokenize(word)
 	} else {
-		err := errors.New("word is not tokenizable")
+		err_msg := fmt.Sprintf("word (%s) is not tokenizable", word)
+		err := errors.New(err_msg)
 		panic(err)
 	}
 }
+
+// NOTE: Spell gives a token type as a diagnostic shows it, which is the keyword of a keyword and
+// the kind of a literal or an identifier
+func (this *Tokenizer) Spell(token_type TokenType) string {
+	if keyword, found := this.keyword_factory.Keyword(token_type); found {
+		return "'" + keyword + "'"
+	} else if token_type == IDENTIFIER {
+		return "identifier"
+	} else if token_type == NUMBER {
+		return "number"
+	} else if token_type == STRING {
+		return "string"
+	} else if token_type == CHARACTER {
+		return "character constant"
+	} else if token_type == FLOATING {
+		return "floating constant"
+	} else if token_type == HEADER {
+		return "header name"
+	} else if token_type == END_OF_FILE {
+		return "end of input"
+	} else {
+		err := errors.New("token type is not valid")
+		panic(err)
+	}
+}
+
+// NOTE: SpellToken gives a token as a diagnostic shows it, which is its own text if it has one
+func (this *Tokenizer) SpellToken(token *Token) string {
+	if token.Attribute() != "" && token.TokenType() != END_OF_FILE {
+		return "'" + token.Attribute() + "'"
+	} else {
+		return this.Spell(token.TokenType())
+	}
+}
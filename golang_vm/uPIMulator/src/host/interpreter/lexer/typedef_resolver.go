@@ -0,0 +1,292 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// NOTE: the typedef resolver lowers enums into int constants and typedefs into the types they
+// name, so that the parser only sees the primitive types and structs that the VM supports
+type TypedefResolver struct {
+	enum_constants map[string]int64
+	typedefs       map[string]*TokenStream
+
+	constant_evaluator *ConstantEvaluator
+}
+
+func (this *TypedefResolver) Init() {
+	this.enum_constants = make(map[string]int64)
+	this.typedefs = make(map[string]*TokenStream)
+
+	this.constant_evaluator = new(ConstantEvaluator)
+}
+
+func (this *TypedefResolver) Resolve(token_stream *TokenStream) *TokenStream {
+	return this.ResolveTypedefs(this.ResolveEnums(token_stream))
+}
+
+func (this *TypedefResolver) ResolveEnums(token_stream *TokenStream) *TokenStream {
+	resolved_token_stream := new(TokenStream)
+	resolved_token_stream.Init()
+
+	for i := 0; i < token_stream.Length(); i++ {
+		token := token_stream.Get(i)
+
+		if token.TokenType() == ENUM {
+			end := this.ResolveEnum(token_stream, i+1)
+
+			// NOTE: an enum that only declares its constants does not leave any token behind
+			if end < token_stream.Length() && token_stream.Get(end).TokenType() == SEMI {
+				i = end
+				continue
+			}
+
+			int_token := new(Token)
+			int_token.Init(INT, "")
+			int_token.SetPosition(token.Path(), token.Line(), token.Column())
+			resolved_token_stream.Append(int_token)
+
+			i = end - 1
+		} else if value, found := this.enum_constants[token.Attribute()]; found &&
+			token.TokenType() == IDENTIFIER && !this.IsMember(resolved_token_stream) {
+			this.AppendEnumConstant(resolved_token_stream, token, value)
+		} else {
+			resolved_token_stream.Append(token)
+		}
+	}
+
+	return resolved_token_stream
+}
+
+// NOTE: a number literal has no sign, so that a negative constant is appended as (-N), where N
+// is formatted unsigned for the most negative value to keep its magnitude
+func (this *TypedefResolver) AppendEnumConstant(
+	token_stream *TokenStream,
+	token *Token,
+	value int64,
+) {
+	tokens := make([]*Token, 0)
+
+	if value < 0 {
+		for _, token_type := range []TokenType{LPAREN, MINUS} {
+			symbol := new(Token)
+			symbol.Init(token_type, "")
+			tokens = append(tokens, symbol)
+		}
+	}
+
+	number := new(Token)
+	if value < 0 {
+		number.Init(NUMBER, strconv.FormatUint(uint64(-value), 10))
+	} else {
+		number.Init(NUMBER, strconv.FormatInt(value, 10))
+	}
+	tokens = append(tokens, number)
+
+	if value < 0 {
+		rparen := new(Token)
+		rparen.Init(RPAREN, "")
+		tokens = append(tokens, rparen)
+	}
+
+	for _, token_ := range tokens {
+		token_.SetPosition(token.Path(), token.Line(), token.Column())
+		token_stream.Append(token_)
+	}
+}
+
+// NOTE: ResolveEnum defines the constants of an enum and returns the position after it
+func (this *TypedefResolver) ResolveEnum(token_stream *TokenStream, pos int) int {
+	if pos < token_stream.Length() && token_stream.Get(pos).TokenType() == IDENTIFIER {
+		pos++
+	}
+
+	if pos >= token_stream.Length() || token_stream.Get(pos).TokenType() != LBRACE {
+		return pos
+	}
+	pos++
+
+	value := int64(0)
+	for pos < token_stream.Length() && token_stream.Get(pos).TokenType() != RBRACE {
+		name := token_stream.Get(pos)
+
+		if name.TokenType() != IDENTIFIER {
+			err := errors.New("enumerator is not an identifier")
+			panic(err)
+		}
+		pos++
+
+		if pos < token_stream.Length() && token_stream.Get(pos).TokenType() == ASSIGN {
+			pos++
+
+			expr := new(TokenStream)
+			expr.Init()
+
+			for pos < token_stream.Length() && token_stream.Get(pos).TokenType() != COMMA &&
+				token_stream.Get(pos).TokenType() != RBRACE {
+				expr.Append(token_stream.Get(pos))
+				pos++
+			}
+
+			value = this.constant_evaluator.Evaluate(expr, this.enum_constants)
+		}
+
+		this.enum_constants[name.Attribute()] = value
+		value++
+
+		if pos < token_stream.Length() && token_stream.Get(pos).TokenType() == COMMA {
+			pos++
+		}
+	}
+
+	if pos >= token_stream.Length() {
+		err := errors.New("enum is not terminated")
+		panic(err)
+	}
+
+	return pos + 1
+}
+
+func (this *TypedefResolver) ResolveTypedefs(token_stream *TokenStream) *TokenStream {
+	resolved_token_stream := new(TokenStream)
+	resolved_token_stream.Init()
+
+	typedef_begin := -1
+	depth := 0
+	for i := 0; i < token_stream.Length(); i++ {
+		token := token_stream.Get(i)
+
+		if token.TokenType() == TYPEDEF {
+			typedef_begin = resolved_token_stream.Length()
+			depth = 0
+			continue
+		} else if token.TokenType() == LBRACE {
+			depth++
+		} else if token.TokenType() == RBRACE {
+			depth--
+		}
+
+		if typedef_begin >= 0 && depth == 0 && token.TokenType() == SEMI {
+			decl := new(TokenStream)
+			decl.Init()
+			for j := typedef_begin; j < resolved_token_stream.Length(); j++ {
+				decl.Append(resolved_token_stream.Get(j))
+			}
+
+			truncated_token_stream := new(TokenStream)
+			truncated_token_stream.Init()
+			for j := 0; j < typedef_begin; j++ {
+				truncated_token_stream.Append(resolved_token_stream.Get(j))
+			}
+
+			resolved_token_stream = truncated_token_stream
+			resolved_token_stream.Merge(this.ResolveTypedef(decl))
+
+			typedef_begin = -1
+		} else if typedef, found := this.typedefs[token.Attribute()]; found &&
+			token.TokenType() == IDENTIFIER && !this.IsMember(resolved_token_stream) &&
+			!this.IsStructTag(resolved_token_stream) &&
+			!this.IsRedefinition(token_stream, i, typedef_begin, depth) {
+			resolved_token_stream.Merge(typedef.Relocate(token))
+		} else {
+			resolved_token_stream.Append(token)
+		}
+	}
+
+	if typedef_begin >= 0 {
+		err := errors.New("typedef is not terminated")
+		panic(err)
+	}
+
+	return resolved_token_stream
+}
+
+// NOTE: a typedef may be repeated with the same type, in which case its name is kept rather than
+// replaced by the previous definition
+func (this *TypedefResolver) IsRedefinition(
+	token_stream *TokenStream,
+	pos int,
+	typedef_begin int,
+	depth int,
+) bool {
+	return typedef_begin >= 0 && depth == 0 && pos+1 < token_stream.Length() &&
+		token_stream.Get(pos+1).TokenType() == SEMI
+}
+
+// NOTE: ResolveTypedef records the name of a typedef and returns the tokens that replace it, which
+// is the definition of the struct for a typedef of an anonymous or tagged struct
+func (this *TypedefResolver) ResolveTypedef(decl *TokenStream) *TokenStream {
+	replaced_token_stream := new(TokenStream)
+	replaced_token_stream.Init()
+
+	if decl.Length() < 2 || decl.Get(decl.Length()-1).TokenType() != IDENTIFIER {
+		err := errors.New("typedef does not end with a name")
+		panic(err)
+	}
+
+	name := decl.Get(decl.Length() - 1)
+
+	has_body := false
+	for i := 0; i < decl.Length(); i++ {
+		if decl.Get(i).TokenType() == LBRACE {
+			has_body = true
+		}
+	}
+
+	typedef := new(TokenStream)
+	typedef.Init()
+
+	if has_body {
+		if decl.Get(0).TokenType() != STRUCT {
+			err_msg := fmt.Sprintf("typedef (%s) of a non-struct body is not supported", name.Attribute())
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		tag := name
+		body_begin := 1
+		if decl.Get(1).TokenType() == IDENTIFIER {
+			tag = decl.Get(1)
+			body_begin = 2
+		}
+
+		typedef.Append(decl.Get(0))
+		typedef.Append(tag)
+
+		replaced_token_stream.Merge(typedef)
+		for i := body_begin; i < decl.Length()-1; i++ {
+			replaced_token_stream.Append(decl.Get(i))
+		}
+
+		semi := new(Token)
+		semi.Init(SEMI, "")
+		semi.SetPosition(name.Path(), name.Line(), name.Column())
+		replaced_token_stream.Append(semi)
+	} else {
+		for i := 0; i < decl.Length()-1; i++ {
+			typedef.Append(decl.Get(i))
+		}
+	}
+
+	this.typedefs[name.Attribute()] = typedef
+
+	return replaced_token_stream
+}
+
+func (this *TypedefResolver) IsMember(token_stream *TokenStream) bool {
+	if token_stream.Length() == 0 {
+		return false
+	}
+
+	last_token := token_stream.Get(token_stream.Length() - 1)
+	return last_token.TokenType() == DOT || last_token.TokenType() == ARROW
+}
+
+func (this *TypedefResolver) IsStructTag(token_stream *TokenStream) bool {
+	if token_stream.Length() == 0 {
+		return false
+	}
+
+	return token_stream.Get(token_stream.Length()-1).TokenType() == STRUCT
+}
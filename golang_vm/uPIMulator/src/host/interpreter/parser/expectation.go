@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"uPIMulator/src/host/interpreter/lexer"
+)
+
+// NOTE: an expectation is a set of token types that a diagnostic names as a whole, e.g. the tokens
+// that can begin an expression, or a single token type that is named by its spelling
+type Expectation struct {
+	name        string
+	token_types map[lexer.TokenType]bool
+}
+
+func (this *Expectation) Init(name string, token_types []lexer.TokenType) {
+	this.name = name
+
+	this.token_types = make(map[lexer.TokenType]bool)
+	for _, token_type := range token_types {
+		this.token_types[token_type] = true
+	}
+}
+
+func (this *Expectation) Name() string {
+	return this.name
+}
+
+func (this *Expectation) IsExpected(token_type lexer.TokenType) bool {
+	_, found := this.token_types[token_type]
+	return found
+}
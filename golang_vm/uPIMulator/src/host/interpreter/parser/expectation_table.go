@@ -0,0 +1,455 @@
+package parser
+
+import (
+	"strings"
+	"uPIMulator/src/host/interpreter/lexer"
+	"uPIMulator/src/host/interpreter/parser/expr"
+)
+
+// NOTE: the expectation table tells which tokens can follow the top of the stack once it is
+// reduced. The parser does not need it to parse, but a stack that is not accepted only shows where
+// the parse gave up, so that the parser consults the table to find the first token that does not
+// fit. The table is loose, i.e. it may expect a token that the grammar rejects, but it never
+// rejects a token that the grammar expects, and the top of the stack that it does not know expects
+// any token. The grammar is the one of C rather than the rules of the parser, which also reduce two
+// adjacent expressions to a sum since a binary minus may be reduced as a unary one.
+type ExpectationTable struct {
+	tokenizer *lexer.Tokenizer
+
+	expression  *Expectation
+	type_       *Expectation
+	statement   *Expectation
+	declaration *Expectation
+	operator    *Expectation
+
+	token_expectations      map[lexer.TokenType][]*Expectation
+	stack_item_expectations map[StackItemType][]*Expectation
+}
+
+func (this *ExpectationTable) Init() {
+	this.tokenizer = new(lexer.Tokenizer)
+	this.tokenizer.Init()
+
+	this.InitExpectations()
+	this.InitTokenExpectations()
+	this.InitStackItemExpectations()
+}
+
+func (this *ExpectationTable) InitExpectations() {
+	expression_begins := []lexer.TokenType{
+		lexer.IDENTIFIER, lexer.NUMBER, lexer.STRING, lexer.CHARACTER, lexer.FLOATING, lexer.NULL,
+		lexer.SIZEOF, lexer.LPAREN, lexer.LBRACE, lexer.PLUS_PLUS, lexer.MINUS_MINUS, lexer.AND,
+		lexer.STAR, lexer.PLUS, lexer.MINUS, lexer.TILDE, lexer.NOT,
+	}
+
+	type_begins := []lexer.TokenType{
+		lexer.CHAR, lexer.DOUBLE, lexer.FLOAT, lexer.INT, lexer.LONG, lexer.SHORT, lexer.SIGNED,
+		lexer.UNSIGNED, lexer.VOID, lexer.STRUCT,
+	}
+
+	statement_begins := []lexer.TokenType{
+		lexer.IF, lexer.FOR, lexer.WHILE, lexer.DO, lexer.SWITCH, lexer.CASE, lexer.DEFAULT,
+		lexer.BREAK, lexer.CONTINUE, lexer.RETURN, lexer.SEMI,
+	}
+	statement_begins = append(statement_begins, expression_begins...)
+	statement_begins = append(statement_begins, type_begins...)
+
+	declaration_begins := []lexer.TokenType{lexer.INCLUDE, lexer.DEFINE, lexer.END_OF_FILE}
+	declaration_begins = append(declaration_begins, type_begins...)
+
+	operators := []lexer.TokenType{
+		lexer.LESS, lexer.LESS_EQ, lexer.GREATER, lexer.GREATER_EQ, lexer.EQ, lexer.NOT_EQ,
+		lexer.PLUS, lexer.PLUS_PLUS, lexer.MINUS, lexer.MINUS_MINUS, lexer.STAR, lexer.DIV,
+		lexer.MOD, lexer.LSHIFT, lexer.RSHIFT, lexer.AND, lexer.AND_AND, lexer.OR, lexer.OR_OR,
+		lexer.CARET, lexer.QUESTION, lexer.ASSIGN, lexer.PLUS_ASSIGN, lexer.MINUS_ASSIGN,
+		lexer.STAR_ASSIGN, lexer.DIV_ASSIGN, lexer.MOD_ASSIGN, lexer.LSHIFT_ASSIGN,
+		lexer.RSHIFT_ASSIGN, lexer.AND_ASSIGN, lexer.OR_ASSIGN, lexer.CARET_ASSIGN, lexer.LPAREN,
+		lexer.LBRACKET, lexer.DOT, lexer.ARROW,
+	}
+
+	this.expression = new(Expectation)
+	this.expression.Init("expression", expression_begins)
+
+	this.type_ = new(Expectation)
+	this.type_.Init("type", type_begins)
+
+	this.statement = new(Expectation)
+	this.statement.Init("statement", statement_begins)
+
+	this.declaration = new(Expectation)
+	this.declaration.Init("declaration", declaration_begins)
+
+	this.operator = new(Expectation)
+	this.operator.Init("operator", operators)
+}
+
+func (this *ExpectationTable) InitTokenExpectations() {
+	this.token_expectations = make(map[lexer.TokenType][]*Expectation)
+
+	this.token_expectations[lexer.INCLUDE] = []*Expectation{this.Token(lexer.HEADER)}
+	this.token_expectations[lexer.DEFINE] = []*Expectation{this.Token(lexer.IDENTIFIER)}
+
+	this.token_expectations[lexer.BREAK] = []*Expectation{this.Token(lexer.SEMI)}
+	this.token_expectations[lexer.CONTINUE] = []*Expectation{this.Token(lexer.SEMI)}
+	this.token_expectations[lexer.DEFAULT] = []*Expectation{this.Token(lexer.COLON)}
+	this.token_expectations[lexer.CASE] = []*Expectation{this.expression}
+	this.token_expectations[lexer.RETURN] = []*Expectation{
+		this.expression,
+		this.Token(lexer.SEMI),
+	}
+
+	this.token_expectations[lexer.ELSE] = []*Expectation{this.statement}
+	this.token_expectations[lexer.DO] = []*Expectation{this.statement}
+
+	this.token_expectations[lexer.IF] = []*Expectation{this.Token(lexer.LPAREN)}
+	this.token_expectations[lexer.FOR] = []*Expectation{this.Token(lexer.LPAREN)}
+	this.token_expectations[lexer.WHILE] = []*Expectation{this.Token(lexer.LPAREN)}
+	this.token_expectations[lexer.SWITCH] = []*Expectation{this.Token(lexer.LPAREN)}
+	this.token_expectations[lexer.SIZEOF] = []*Expectation{this.expression}
+
+	this.token_expectations[lexer.STRUCT] = []*Expectation{this.Token(lexer.IDENTIFIER)}
+	this.token_expectations[lexer.DOT] = []*Expectation{this.Token(lexer.IDENTIFIER)}
+	this.token_expectations[lexer.ARROW] = []*Expectation{this.Token(lexer.IDENTIFIER)}
+
+	this.token_expectations[lexer.LPAREN] = []*Expectation{
+		this.expression,
+		this.type_,
+		this.Token(lexer.RPAREN),
+		this.Token(lexer.SEMI),
+	}
+	this.token_expectations[lexer.RPAREN] = []*Expectation{this.statement}
+	this.token_expectations[lexer.LBRACKET] = []*Expectation{
+		this.expression,
+		this.Token(lexer.RBRACKET),
+	}
+	this.token_expectations[lexer.LBRACE] = []*Expectation{
+		this.statement,
+		this.Token(lexer.RBRACE),
+	}
+
+	this.token_expectations[lexer.SEMI] = []*Expectation{
+		this.expression,
+		this.Token(lexer.SEMI),
+		this.Token(lexer.RPAREN),
+	}
+	this.token_expectations[lexer.COLON] = []*Expectation{
+		this.statement,
+		this.Token(lexer.RBRACE),
+	}
+	this.token_expectations[lexer.COMMA] = []*Expectation{
+		this.expression,
+		this.type_,
+		this.Token(lexer.RBRACE),
+	}
+
+	// NOTE: a star also follows a type, which can be followed by another star or be closed by a
+	// parenthesis in a cast
+	this.token_expectations[lexer.STAR] = []*Expectation{
+		this.expression,
+		this.Token(lexer.RPAREN),
+	}
+
+	operands := []lexer.TokenType{
+		lexer.LESS, lexer.LESS_EQ, lexer.GREATER, lexer.GREATER_EQ, lexer.EQ, lexer.NOT_EQ,
+		lexer.PLUS, lexer.PLUS_PLUS, lexer.MINUS, lexer.MINUS_MINUS, lexer.DIV, lexer.MOD,
+		lexer.LSHIFT, lexer.RSHIFT, lexer.AND, lexer.AND_AND, lexer.OR, lexer.OR_OR, lexer.CARET,
+		lexer.NOT, lexer.TILDE, lexer.QUESTION, lexer.ASSIGN, lexer.PLUS_ASSIGN,
+		lexer.MINUS_ASSIGN, lexer.STAR_ASSIGN, lexer.DIV_ASSIGN, lexer.MOD_ASSIGN,
+		lexer.LSHIFT_ASSIGN, lexer.RSHIFT_ASSIGN, lexer.AND_ASSIGN, lexer.OR_ASSIGN,
+		lexer.CARET_ASSIGN,
+	}
+	for _, token_type := range operands {
+		this.token_expectations[token_type] = []*Expectation{this.expression}
+	}
+}
+
+func (this *ExpectationTable) InitStackItemExpectations() {
+	this.stack_item_expectations = make(map[StackItemType][]*Expectation)
+
+	this.stack_item_expectations[TYPE_SPECIFIER] = []*Expectation{
+		this.Token(lexer.IDENTIFIER),
+		this.Token(lexer.STAR),
+		this.type_,
+		this.Token(lexer.RPAREN),
+		this.Token(lexer.LBRACE),
+	}
+	this.stack_item_expectations[PARAM_LIST] = []*Expectation{
+		this.Token(lexer.RPAREN),
+		this.Token(lexer.COMMA),
+		this.Token(lexer.SEMI),
+		this.Token(lexer.ASSIGN),
+		this.Token(lexer.LPAREN),
+		this.Token(lexer.LBRACKET),
+	}
+	this.stack_item_expectations[ARG_LIST] = []*Expectation{
+		this.Token(lexer.RPAREN),
+		this.Token(lexer.COMMA),
+		this.Token(lexer.RBRACE),
+	}
+	this.stack_item_expectations[STMT] = []*Expectation{
+		this.statement,
+		this.declaration,
+		this.Token(lexer.RBRACE),
+		this.Token(lexer.ELSE),
+		this.Token(lexer.WHILE),
+	}
+	this.stack_item_expectations[CONDITION] = []*Expectation{this.statement}
+	this.stack_item_expectations[DECLARATOR_LIST] = []*Expectation{
+		this.Token(lexer.COMMA),
+		this.Token(lexer.SEMI),
+	}
+	this.stack_item_expectations[DECL] = []*Expectation{this.declaration}
+	this.stack_item_expectations[DIRECTIVE] = []*Expectation{this.declaration}
+}
+
+func (this *ExpectationTable) Token(token_type lexer.TokenType) *Expectation {
+	expectation := new(Expectation)
+	expectation.Init(this.tokenizer.Spell(token_type), []lexer.TokenType{token_type})
+	return expectation
+}
+
+func (this *ExpectationTable) Expectations(stack *Stack) []*Expectation {
+	if stack.Length() == 0 {
+		return []*Expectation{this.declaration}
+	}
+
+	top := stack.Front(1)[0]
+
+	if top.StackItemType() == TOKEN {
+		return this.token_expectations[top.Token().TokenType()]
+	} else if top.StackItemType() == EXPR {
+		return this.ExprExpectations(stack)
+	} else if top.StackItemType() == ARG_LIST {
+		return this.ArgListExpectations(stack)
+	}
+
+	return this.stack_item_expectations[top.StackItemType()]
+}
+
+// NOTE: an expression is followed by an operator or by what closes the construct that it is part
+// of, which is found by walking down the stack past the operands and operators of the expression
+// and the brackets that are closed already. The value of a #define is followed by anything.
+func (this *ExpectationTable) ExprExpectations(stack *Stack) []*Expectation {
+	top := stack.Front(1)[0]
+
+	closers := make(map[lexer.TokenType]bool)
+
+	// NOTE: adjacent string literals are concatenated
+	if top.Expr().ExprType() == expr.PRIMARY &&
+		top.Expr().PrimaryExpr().PrimaryExprType() == expr.STRING {
+		closers[lexer.STRING] = true
+	}
+
+	depth := 0
+	num_colons := 0
+	is_closed := false
+	for i := stack.Length() - 2; i >= 0 && !is_closed; i-- {
+		stack_item := stack.Get(i)
+
+		token_type := lexer.END_OF_FILE
+		if stack_item.StackItemType() == TOKEN {
+			token_type = stack_item.Token().TokenType()
+		}
+
+		is_closed = true
+
+		if token_type == lexer.RPAREN || token_type == lexer.RBRACKET ||
+			token_type == lexer.RBRACE {
+			depth++
+			is_closed = false
+		} else if depth > 0 {
+			if token_type == lexer.LPAREN || token_type == lexer.LBRACKET ||
+				token_type == lexer.LBRACE {
+				depth--
+			}
+			is_closed = false
+		} else if stack_item.StackItemType() == PARAM_LIST {
+			closers[lexer.COMMA] = true
+			is_closed = false
+		} else if stack_item.StackItemType() == DECLARATOR_LIST {
+			closers[lexer.COMMA] = true
+			closers[lexer.SEMI] = true
+		} else if stack_item.StackItemType() == STMT {
+			this.AddClauseClosers(stack, i, top, closers)
+		} else if stack_item.StackItemType() == DECL ||
+			stack_item.StackItemType() == DIRECTIVE ||
+			stack_item.StackItemType() == CONDITION {
+			this.AddStmtClosers(top, closers)
+		} else if token_type == lexer.LPAREN {
+			this.AddParenClosers(stack, i, closers)
+		} else if token_type == lexer.LBRACKET {
+			closers[lexer.RBRACKET] = true
+		} else if token_type == lexer.LBRACE {
+			if this.IsInitializer(stack, i) {
+				closers[lexer.COMMA] = true
+				closers[lexer.RBRACE] = true
+			} else {
+				this.AddStmtClosers(top, closers)
+			}
+		} else if token_type == lexer.QUESTION && num_colons > 0 {
+			num_colons--
+			is_closed = false
+		} else if token_type == lexer.QUESTION {
+			closers[lexer.COLON] = true
+		} else if token_type == lexer.COLON {
+			num_colons++
+			is_closed = false
+		} else if token_type == lexer.CASE && num_colons == 0 {
+			closers[lexer.COLON] = true
+		} else if token_type == lexer.RETURN {
+			closers[lexer.SEMI] = true
+		} else if token_type == lexer.DEFINE {
+			return []*Expectation{
+				this.statement,
+				this.declaration,
+				this.Token(lexer.RBRACE),
+				this.operator,
+			}
+		} else if token_type == lexer.CASE || token_type == lexer.DEFAULT ||
+			token_type == lexer.IF || token_type == lexer.FOR || token_type == lexer.WHILE ||
+			token_type == lexer.SWITCH || token_type == lexer.ELSE || token_type == lexer.DO {
+			this.AddStmtClosers(top, closers)
+		} else {
+			is_closed = false
+		}
+	}
+
+	// NOTE: an expression at the bottom of the stack is the initializer of a global
+	if !is_closed {
+		closers[lexer.SEMI] = true
+	}
+
+	closer_types := []lexer.TokenType{
+		lexer.SEMI, lexer.COMMA, lexer.RPAREN, lexer.RBRACKET, lexer.RBRACE, lexer.COLON,
+		lexer.LBRACE, lexer.STRING,
+	}
+
+	expectations := make([]*Expectation, 0)
+	for _, closer_type := range closer_types {
+		if closers[closer_type] {
+			expectations = append(expectations, this.Token(closer_type))
+		}
+	}
+
+	return append(expectations, this.operator)
+}
+
+// NOTE: an argument list is either the arguments of a call or an initializer list
+func (this *ExpectationTable) ArgListExpectations(stack *Stack) []*Expectation {
+	if stack.Length() >= 2 && this.IsToken(stack.Front(2)[0], lexer.LBRACE) {
+		return []*Expectation{this.Token(lexer.COMMA), this.Token(lexer.RBRACE)}
+	} else if stack.Length() >= 2 && this.IsToken(stack.Front(2)[0], lexer.LPAREN) {
+		return []*Expectation{this.Token(lexer.COMMA), this.Token(lexer.RPAREN)}
+	}
+
+	return this.stack_item_expectations[ARG_LIST]
+}
+
+// NOTE: a call that makes a statement may be a DPU_FOREACH, which is followed by its body
+func (this *ExpectationTable) AddStmtClosers(top *StackItem, closers map[lexer.TokenType]bool) {
+	closers[lexer.SEMI] = true
+
+	if top.Expr().ExprType() == expr.POSTFIX &&
+		top.Expr().PostfixExpr().PostfixExprType() == expr.CALL {
+		closers[lexer.LBRACE] = true
+	}
+}
+
+// NOTE: the clauses of a for are reduced to statements once their ';' is shifted, so that the
+// expression after one of them is the condition and the one after two of them is the step
+func (this *ExpectationTable) AddClauseClosers(
+	stack *Stack,
+	pos int,
+	top *StackItem,
+	closers map[lexer.TokenType]bool,
+) {
+	num_clauses := 0
+	for pos-num_clauses >= 0 && num_clauses < 2 &&
+		stack.Get(pos-num_clauses).StackItemType() == STMT {
+		num_clauses++
+	}
+
+	if pos-num_clauses >= 1 && this.IsToken(stack.Get(pos-num_clauses), lexer.LPAREN) &&
+		this.IsToken(stack.Get(pos-num_clauses-1), lexer.FOR) {
+		if num_clauses == 1 {
+			closers[lexer.SEMI] = true
+		} else {
+			closers[lexer.RPAREN] = true
+		}
+	} else {
+		this.AddStmtClosers(top, closers)
+	}
+}
+
+func (this *ExpectationTable) AddParenClosers(
+	stack *Stack,
+	pos int,
+	closers map[lexer.TokenType]bool,
+) {
+	if pos == 0 {
+		closers[lexer.RPAREN] = true
+		return
+	}
+
+	below := stack.Get(pos - 1)
+
+	if this.IsToken(below, lexer.FOR) {
+		closers[lexer.SEMI] = true
+	} else if below.StackItemType() == EXPR {
+		closers[lexer.RPAREN] = true
+		closers[lexer.COMMA] = true
+	} else {
+		closers[lexer.RPAREN] = true
+	}
+}
+
+// NOTE: a brace opens an initializer list after '=' or within another initializer list, and a
+// block otherwise
+func (this *ExpectationTable) IsInitializer(stack *Stack, pos int) bool {
+	if pos == 0 {
+		return false
+	}
+
+	below := stack.Get(pos - 1)
+
+	if this.IsToken(below, lexer.ASSIGN) || this.IsToken(below, lexer.COMMA) {
+		return true
+	} else if this.IsToken(below, lexer.LBRACE) {
+		return this.IsInitializer(stack, pos-1)
+	} else {
+		return false
+	}
+}
+
+func (this *ExpectationTable) IsToken(stack_item *StackItem, token_type lexer.TokenType) bool {
+	return stack_item.StackItemType() == TOKEN && stack_item.Token().TokenType() == token_type
+}
+
+func (this *ExpectationTable) IsExpected(stack *Stack, token *lexer.Token) bool {
+	expectations := this.Expectations(stack)
+
+	if expectations == nil {
+		return true
+	}
+
+	for _, expectation := range expectations {
+		if expectation.IsExpected(token.TokenType()) {
+			return true
+		}
+	}
+	return false
+}
+
+// NOTE: Stringify names the expectations in the way the C compilers do, e.g. "';' or ')'"
+func (this *ExpectationTable) Stringify(expectations []*Expectation) string {
+	names := make([]string, 0)
+	for _, expectation := range expectations {
+		names = append(names, expectation.Name())
+	}
+
+	if len(names) == 1 {
+		return names[0]
+	} else {
+		return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+	}
+}
@@ -1,5 +1,9 @@
 package expr
 
+import (
+	"uPIMulator/src/host/interpreter/lexer"
+)
+
 type ExprType int
 
 const (
@@ -38,6 +42,8 @@ type Expr struct {
 	logical_or_expr     *LogicalOrExpr
 	conditional_expr    *ConditionalExpr
 	assignment_expr     *AssignmentExpr
+
+	first_token *lexer.Token
 }
 
 func (this *Expr) InitPrimaryExpr(primary_expr *PrimaryExpr) {
@@ -193,3 +199,11 @@ func (this *Expr) ConditionalExpr() *ConditionalExpr {
 func (this *Expr) AssignmentExpr() *AssignmentExpr {
 	return this.assignment_expr
 }
+
+func (this *Expr) FirstToken() *lexer.Token {
+	return this.first_token
+}
+
+func (this *Expr) SetFirstToken(first_token *lexer.Token) {
+	this.first_token = first_token
+}
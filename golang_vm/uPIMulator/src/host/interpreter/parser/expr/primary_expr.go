@@ -10,15 +10,19 @@ type PrimaryExprType int
 const (
 	IDENTIFIER PrimaryExprType = iota
 	NUMBER
+	FLOATING
+	CHARACTER
 	STRING
 	NULLPTR
 	PAREN
+	INITIALIZER_LIST
 )
 
 type PrimaryExpr struct {
 	primary_expr_type PrimaryExprType
 	token             *lexer.Token
 	expr              *Expr
+	arg_list          *ArgList
 }
 
 func (this *PrimaryExpr) InitIdentifier(token *lexer.Token) {
@@ -33,6 +37,18 @@ func (this *PrimaryExpr) InitNumber(token *lexer.Token) {
 	this.expr = nil
 }
 
+func (this *PrimaryExpr) InitFloating(token *lexer.Token) {
+	this.primary_expr_type = FLOATING
+	this.token = token
+	this.expr = nil
+}
+
+func (this *PrimaryExpr) InitCharacter(token *lexer.Token) {
+	this.primary_expr_type = CHARACTER
+	this.token = token
+	this.expr = nil
+}
+
 func (this *PrimaryExpr) InitString(token *lexer.Token) {
 	this.primary_expr_type = STRING
 	this.token = token
@@ -51,6 +67,13 @@ func (this *PrimaryExpr) InitParen(expr *Expr) {
 	this.expr = expr
 }
 
+func (this *PrimaryExpr) InitInitializerList(arg_list *ArgList) {
+	this.primary_expr_type = INITIALIZER_LIST
+	this.token = nil
+	this.expr = nil
+	this.arg_list = arg_list
+}
+
 func (this *PrimaryExpr) PrimaryExprType() PrimaryExprType {
 	return this.primary_expr_type
 }
@@ -72,3 +95,12 @@ func (this *PrimaryExpr) Expr() *Expr {
 
 	return this.expr
 }
+
+func (this *PrimaryExpr) ArgList() *ArgList {
+	if this.arg_list == nil {
+		err := errors.New("arg list == nil")
+		panic(err)
+	}
+
+	return this.arg_list
+}
@@ -17,6 +17,7 @@ const (
 	TILDE
 	NOT
 	SIZEOF
+	CAST
 )
 
 type UnaryExpr struct {
@@ -110,6 +111,20 @@ func (this *UnaryExpr) InitSizeof(type_specifier *type_specifier.TypeSpecifier)
 	this.type_specifier = type_specifier
 }
 
+// NOTE: the type of the operand of a sizeof is only known once it is checked, which sets the type
+// specifier that the codegen sizes, since the operand itself is not evaluated
+func (this *UnaryExpr) InitSizeofExpr(base *Expr) {
+	this.unary_expr_type = SIZEOF
+	this.base = base
+	this.type_specifier = nil
+}
+
+func (this *UnaryExpr) InitCast(type_specifier *type_specifier.TypeSpecifier, base *Expr) {
+	this.unary_expr_type = CAST
+	this.base = base
+	this.type_specifier = type_specifier
+}
+
 func (this *UnaryExpr) UnaryExprType() UnaryExprType {
 	return this.unary_expr_type
 }
@@ -119,5 +134,18 @@ func (this *UnaryExpr) Base() *Expr {
 }
 
 func (this *UnaryExpr) TypeSpecifier() *type_specifier.TypeSpecifier {
+	if this.type_specifier == nil {
+		err := errors.New("type specifier == nil")
+		panic(err)
+	}
+
 	return this.type_specifier
 }
+
+func (this *UnaryExpr) HasTypeSpecifier() bool {
+	return this.type_specifier != nil
+}
+
+func (this *UnaryExpr) SetTypeSpecifier(type_specifier *type_specifier.TypeSpecifier) {
+	this.type_specifier = type_specifier
+}
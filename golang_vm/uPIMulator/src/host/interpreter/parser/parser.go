@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 	"uPIMulator/src/host/interpreter/lexer"
 	"uPIMulator/src/host/interpreter/parser/decl"
 	"uPIMulator/src/host/interpreter/parser/directive"
@@ -14,6 +15,11 @@ import (
 type Parser struct {
 	stack *Stack
 	table *Table
+
+	expectation_table *ExpectationTable
+
+	unexpected_token *lexer.Token
+	expectations     []*Expectation
 }
 
 func (this *Parser) Init() {
@@ -23,13 +29,28 @@ func (this *Parser) Init() {
 	this.table = new(Table)
 	this.table.Init(this.stack)
 
+	this.expectation_table = new(ExpectationTable)
+	this.expectation_table.Init()
+
+	this.unexpected_token = nil
+	this.expectations = nil
+
 	this.RegisterTypeSpecifierVoid()
 	this.RegisterTypeSpecifierChar()
 	this.RegisterTypeSpecifierShort()
 	this.RegisterTypeSpecifierInt()
 	this.RegisterTypeSpecifierLong()
+	this.RegisterTypeSpecifierFloat()
+	this.RegisterTypeSpecifierDouble()
 	this.RegisterTypeSpecifierStruct()
 	this.RegisterTypeSpecifierStar()
+	this.RegisterTypeSpecifierUnsigned()
+	this.RegisterTypeSpecifierSigned()
+	this.RegisterTypeSpecifierUnsignedInt()
+	this.RegisterTypeSpecifierSignedInt()
+	this.RegisterTypeSpecifierLongLong()
+	this.RegisterTypeSpecifierLongInt()
+	this.RegisterTypeSpecifierShortInt()
 
 	this.RegisterParamListBegin()
 	this.RegisterParamListAppend()
@@ -39,9 +60,13 @@ func (this *Parser) Init() {
 
 	this.RegisterPrimaryExprIdentifier()
 	this.RegisterPrimaryExprNumber()
+	this.RegisterPrimaryExprFloating()
+	this.RegisterPrimaryExprCharacter()
 	this.RegisterPrimaryExprString()
 	this.RegisterPrimaryExprNullptr()
 	this.RegisterPrimaryExprParen()
+	this.RegisterPrimaryExprInitializerList()
+	this.RegisterPrimaryExprInitializerListComma()
 
 	this.RegisterPostfixExprBracket()
 	this.RegisterPostfixExprCallEmpty()
@@ -61,6 +86,8 @@ func (this *Parser) Init() {
 	this.RegisterUnaryExprTilde()
 	this.RegisterUnaryExprNot()
 	this.RegisterUnaryExprSizeof()
+	this.RegisterUnaryExprSizeofExpr()
+	this.RegisterUnaryExprCast()
 
 	this.RegisterMultiplicativeExprMul()
 	this.RegisterMultiplicativeExprDiv()
@@ -106,15 +133,27 @@ func (this *Parser) Init() {
 	this.RegisterEmptyStmt()
 	this.RegisterVarDeclStmt()
 	this.RegisterVarDeclInitStmt()
+	this.RegisterArrayDeclStmt()
+	this.RegisterArrayDeclInitStmt()
+	this.RegisterArrayDeclInitUnsizedStmt()
+	this.RegisterDeclaratorListBegin()
+	this.RegisterDeclaratorListBeginInit()
+	this.RegisterDeclaratorListBeginArray()
+	this.RegisterDeclaratorListAppend()
+	this.RegisterDeclListStmt()
 	this.RegisterForStmt()
 	this.RegisterDpuForeachStmt()
+	this.RegisterCondition()
 	this.RegisterWhileStmt()
+	this.RegisterDoWhileStmt()
+	this.RegisterSwitchStmt()
+	this.RegisterCaseStmt()
+	this.RegisterDefaultStmt()
 	this.RegisterContinueStmt()
 	this.RegisterBreakStmt()
 
 	this.RegisterIfStmt()
-	this.RegisterElseIfStmt()
-	this.RegisterElseStmt()
+	this.RegisterIfElseStmt()
 
 	this.RegisterReturnStmtWithoutValue()
 	this.RegisterReturnStmtWithValue()
@@ -143,14 +182,89 @@ func (this *Parser) Parse(token_stream *lexer.TokenStream) *Ast {
 			this.table.Reduce(token)
 		}
 
+		if this.unexpected_token == nil && !this.expectation_table.IsExpected(this.stack, token) {
+			this.unexpected_token = token
+			this.expectations = this.expectation_table.Expectations(this.stack)
+		}
+
 		stack_item := new(StackItem)
 		stack_item.InitToken(token)
 		this.stack.Push(stack_item)
 	}
 
+	if !this.stack.CanAccept() {
+		this.ReportSyntaxError()
+	}
+
 	return this.stack.Accept()
 }
 
+// NOTE: a syntax error is reported at the first token that the expectation table does not expect,
+// or else at the bracket that is left open or the first item of the stack that is not accepted
+func (this *Parser) ReportSyntaxError() {
+	tokenizer := new(lexer.Tokenizer)
+	tokenizer.Init()
+
+	diagnostic := new(lexer.Diagnostic)
+
+	if this.unexpected_token != nil {
+		expected := this.expectation_table.Stringify(this.expectations)
+
+		if this.unexpected_token.TokenType() == lexer.END_OF_FILE {
+			diagnostic.InitToken(
+				this.unexpected_token,
+				fmt.Sprintf("expected %s at end of input", expected),
+			)
+		} else {
+			diagnostic.InitToken(
+				this.unexpected_token,
+				fmt.Sprintf(
+					"expected %s before %s",
+					expected,
+					tokenizer.SpellToken(this.unexpected_token),
+				),
+			)
+		}
+	} else if open_bracket := this.OpenBracket(); open_bracket != nil {
+		diagnostic.InitToken(
+			open_bracket,
+			fmt.Sprintf("%s is not closed", tokenizer.SpellToken(open_bracket)),
+		)
+	} else {
+		for i := 0; i < this.stack.Length()-1; i++ {
+			stack_item := this.stack.Get(i)
+
+			if stack_item.StackItemType() != DIRECTIVE && stack_item.StackItemType() != DECL &&
+				!this.stack.IsGlobalVarDecl(stack_item) {
+				diagnostic.InitToken(
+					stack_item.FirstToken(),
+					"expected a declaration or a directive",
+				)
+				break
+			}
+		}
+	}
+
+	err := errors.New(diagnostic.Stringify())
+	panic(err)
+}
+
+func (this *Parser) OpenBracket() *lexer.Token {
+	for i := this.stack.Length() - 1; i >= 0; i-- {
+		stack_item := this.stack.Get(i)
+
+		if stack_item.StackItemType() == TOKEN {
+			token_type := stack_item.Token().TokenType()
+
+			if token_type == lexer.LPAREN || token_type == lexer.LBRACKET ||
+				token_type == lexer.LBRACE {
+				return stack_item.Token()
+			}
+		}
+	}
+	return nil
+}
+
 func (this *Parser) RegisterTypeSpecifierVoid() {
 	precedence := map[lexer.TokenType]bool{}
 
@@ -311,18 +425,15 @@ func (this *Parser) RegisterTypeSpecifierLong() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterTypeSpecifierStruct() {
+func (this *Parser) RegisterTypeSpecifierFloat() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.STRUCT &&
-				stack_items[1].StackItemType() == EXPR &&
-				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
+				stack_items[0].Token().TokenType() == lexer.FLOAT {
 				return true
 			} else {
 				return false
@@ -331,10 +442,8 @@ func (this *Parser) RegisterTypeSpecifierStruct() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		struct_identifier := stack_items[1].Expr().PrimaryExpr().Token()
-
 		type_specifier_ := new(type_specifier.TypeSpecifier)
-		type_specifier_.InitStruct(type_specifier.STRUCT, struct_identifier)
+		type_specifier_.InitPrimitive(type_specifier.FLOAT)
 
 		stack_item := new(StackItem)
 		stack_item.InitTypeSpecifier(type_specifier_)
@@ -348,16 +457,15 @@ func (this *Parser) RegisterTypeSpecifierStruct() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterTypeSpecifierStar() {
+func (this *Parser) RegisterTypeSpecifierDouble() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.STAR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.DOUBLE {
 				return true
 			} else {
 				return false
@@ -366,8 +474,8 @@ func (this *Parser) RegisterTypeSpecifierStar() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		type_specifier_ := stack_items[0].TypeSpecifier()
-		type_specifier_.AddStar()
+		type_specifier_ := new(type_specifier.TypeSpecifier)
+		type_specifier_.InitPrimitive(type_specifier.DOUBLE)
 
 		stack_item := new(StackItem)
 		stack_item.InitTypeSpecifier(type_specifier_)
@@ -381,17 +489,18 @@ func (this *Parser) RegisterTypeSpecifierStar() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterParamListBegin() {
+func (this *Parser) RegisterTypeSpecifierUnsigned() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
-				stack_items[1].StackItemType() == EXPR &&
-				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.UNSIGNED &&
+				stack_items[1].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[1].TypeSpecifier().IsInteger() &&
+				stack_items[1].TypeSpecifier().NumStars() == 0 {
 				return true
 			} else {
 				return false
@@ -400,18 +509,11 @@ func (this *Parser) RegisterParamListBegin() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		type_specifier_ := stack_items[0].TypeSpecifier()
-		identifier := stack_items[1].Expr().PrimaryExpr().Token()
-
-		param := new(param_list.Param)
-		param.Init(type_specifier_, identifier)
-
-		param_list_ := new(param_list.ParamList)
-		param_list_.Init()
-		param_list_.Append(param)
+		type_specifier_ := stack_items[1].TypeSpecifier()
+		type_specifier_.SetUnsigned()
 
 		stack_item := new(StackItem)
-		stack_item.InitParamList(param_list_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -422,20 +524,18 @@ func (this *Parser) RegisterParamListBegin() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterParamListAppend() {
+func (this *Parser) RegisterTypeSpecifierSigned() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 4 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == PARAM_LIST &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.COMMA &&
-				stack_items[2].StackItemType() == TYPE_SPECIFIER &&
-				stack_items[3].StackItemType() == EXPR &&
-				stack_items[3].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[3].Expr().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.SIGNED &&
+				stack_items[1].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[1].TypeSpecifier().IsInteger() &&
+				stack_items[1].TypeSpecifier().NumStars() == 0 {
 				return true
 			} else {
 				return false
@@ -444,17 +544,10 @@ func (this *Parser) RegisterParamListAppend() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		param_list_ := stack_items[0].ParamList()
-		type_specifier_ := stack_items[2].TypeSpecifier()
-		identifier := stack_items[3].Expr().PrimaryExpr().Token()
-
-		param := new(param_list.Param)
-		param.Init(type_specifier_, identifier)
-
-		param_list_.Append(param)
+		type_specifier_ := stack_items[1].TypeSpecifier()
 
 		stack_item := new(StackItem)
-		stack_item.InitParamList(param_list_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -465,52 +558,21 @@ func (this *Parser) RegisterParamListAppend() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterArgListBegin() {
+// NOTE: a bare unsigned or signed is an int, unless it is followed by the integer type it qualifies
+func (this *Parser) RegisterTypeSpecifierUnsignedInt() {
 	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:      true,
-		lexer.LPAREN:        true,
-		lexer.DOT:           true,
-		lexer.ARROW:         true,
-		lexer.STAR:          true,
-		lexer.DIV:           true,
-		lexer.MOD:           true,
-		lexer.PLUS:          true,
-		lexer.MINUS:         true,
-		lexer.LSHIFT:        true,
-		lexer.RSHIFT:        true,
-		lexer.LESS:          true,
-		lexer.LESS_EQ:       true,
-		lexer.GREATER:       true,
-		lexer.GREATER_EQ:    true,
-		lexer.EQ:            true,
-		lexer.NOT_EQ:        true,
-		lexer.AND:           true,
-		lexer.CARET:         true,
-		lexer.OR:            true,
-		lexer.AND_AND:       true,
-		lexer.OR_OR:         true,
-		lexer.QUESTION:      true,
-		lexer.ASSIGN:        true,
-		lexer.STAR_ASSIGN:   true,
-		lexer.DIV_ASSIGN:    true,
-		lexer.MOD_ASSIGN:    true,
-		lexer.PLUS_ASSIGN:   true,
-		lexer.MINUS_ASSIGN:  true,
-		lexer.LSHIFT_ASSIGN: true,
-		lexer.RSHIFT_ASSIGN: true,
-		lexer.AND_ASSIGN:    true,
-		lexer.CARET_ASSIGN:  true,
-		lexer.OR_ASSIGN:     true,
+		lexer.CHAR:  true,
+		lexer.SHORT: true,
+		lexer.INT:   true,
+		lexer.LONG:  true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.COMMA &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.UNSIGNED {
 				return true
 			} else {
 				return false
@@ -519,16 +581,12 @@ func (this *Parser) RegisterArgListBegin() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		arg1 := stack_items[0].Expr()
-		arg2 := stack_items[2].Expr()
-
-		arg_list := new(expr.ArgList)
-		arg_list.Init()
-		arg_list.Append(arg1)
-		arg_list.Append(arg2)
+		type_specifier_ := new(type_specifier.TypeSpecifier)
+		type_specifier_.InitPrimitive(type_specifier.INT)
+		type_specifier_.SetUnsigned()
 
 		stack_item := new(StackItem)
-		stack_item.InitArgList(arg_list)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -539,52 +597,20 @@ func (this *Parser) RegisterArgListBegin() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterArgListAppend() {
+func (this *Parser) RegisterTypeSpecifierSignedInt() {
 	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:      true,
-		lexer.LPAREN:        true,
-		lexer.DOT:           true,
-		lexer.ARROW:         true,
-		lexer.STAR:          true,
-		lexer.DIV:           true,
-		lexer.MOD:           true,
-		lexer.PLUS:          true,
-		lexer.MINUS:         true,
-		lexer.LSHIFT:        true,
-		lexer.RSHIFT:        true,
-		lexer.LESS:          true,
-		lexer.LESS_EQ:       true,
-		lexer.GREATER:       true,
-		lexer.GREATER_EQ:    true,
-		lexer.EQ:            true,
-		lexer.NOT_EQ:        true,
-		lexer.AND:           true,
-		lexer.CARET:         true,
-		lexer.OR:            true,
-		lexer.AND_AND:       true,
-		lexer.OR_OR:         true,
-		lexer.QUESTION:      true,
-		lexer.ASSIGN:        true,
-		lexer.STAR_ASSIGN:   true,
-		lexer.DIV_ASSIGN:    true,
-		lexer.MOD_ASSIGN:    true,
-		lexer.PLUS_ASSIGN:   true,
-		lexer.MINUS_ASSIGN:  true,
-		lexer.LSHIFT_ASSIGN: true,
-		lexer.RSHIFT_ASSIGN: true,
-		lexer.AND_ASSIGN:    true,
-		lexer.CARET_ASSIGN:  true,
-		lexer.OR_ASSIGN:     true,
+		lexer.CHAR:  true,
+		lexer.SHORT: true,
+		lexer.INT:   true,
+		lexer.LONG:  true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == ARG_LIST &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.COMMA &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.SIGNED {
 				return true
 			} else {
 				return false
@@ -593,13 +619,11 @@ func (this *Parser) RegisterArgListAppend() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		arg_list := stack_items[0].ArgList()
-		arg := stack_items[2].Expr()
-
-		arg_list.Append(arg)
+		type_specifier_ := new(type_specifier.TypeSpecifier)
+		type_specifier_.InitPrimitive(type_specifier.INT)
 
 		stack_item := new(StackItem)
-		stack_item.InitArgList(arg_list)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -610,15 +634,19 @@ func (this *Parser) RegisterArgListAppend() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPrimaryExprIdentifier() {
+// NOTE: long long is as wide as long in the VM
+func (this *Parser) RegisterTypeSpecifierLongLong() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 1 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.IDENTIFIER {
+			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[0].TypeSpecifier().TypeSpecifierType() == type_specifier.LONG &&
+				stack_items[0].TypeSpecifier().NumStars() == 0 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LONG {
 				return true
 			} else {
 				return false
@@ -627,16 +655,10 @@ func (this *Parser) RegisterPrimaryExprIdentifier() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		token := stack_items[0].Token()
-
-		primary_expr := new(expr.PrimaryExpr)
-		primary_expr.InitIdentifier(token)
-
-		expr_ := new(expr.Expr)
-		expr_.InitPrimaryExpr(primary_expr)
+		type_specifier_ := stack_items[0].TypeSpecifier()
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -647,15 +669,18 @@ func (this *Parser) RegisterPrimaryExprIdentifier() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPrimaryExprNumber() {
+func (this *Parser) RegisterTypeSpecifierLongInt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 1 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.NUMBER {
+			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[0].TypeSpecifier().TypeSpecifierType() == type_specifier.LONG &&
+				stack_items[0].TypeSpecifier().NumStars() == 0 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.INT {
 				return true
 			} else {
 				return false
@@ -664,16 +689,10 @@ func (this *Parser) RegisterPrimaryExprNumber() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		token := stack_items[0].Token()
-
-		primary_expr := new(expr.PrimaryExpr)
-		primary_expr.InitNumber(token)
-
-		expr_ := new(expr.Expr)
-		expr_.InitPrimaryExpr(primary_expr)
+		type_specifier_ := stack_items[0].TypeSpecifier()
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -684,15 +703,18 @@ func (this *Parser) RegisterPrimaryExprNumber() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPrimaryExprString() {
+func (this *Parser) RegisterTypeSpecifierShortInt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 1 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.STRING {
+			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[0].TypeSpecifier().TypeSpecifierType() == type_specifier.SHORT &&
+				stack_items[0].TypeSpecifier().NumStars() == 0 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.INT {
 				return true
 			} else {
 				return false
@@ -701,16 +723,10 @@ func (this *Parser) RegisterPrimaryExprString() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		token := stack_items[0].Token()
-
-		primary_expr := new(expr.PrimaryExpr)
-		primary_expr.InitString(token)
-
-		expr_ := new(expr.Expr)
-		expr_.InitPrimaryExpr(primary_expr)
+		type_specifier_ := stack_items[0].TypeSpecifier()
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -721,15 +737,18 @@ func (this *Parser) RegisterPrimaryExprString() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPrimaryExprNullptr() {
+func (this *Parser) RegisterTypeSpecifierStruct() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 1 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.NULL {
+				stack_items[0].Token().TokenType() == lexer.STRUCT &&
+				stack_items[1].StackItemType() == EXPR &&
+				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
+				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
 				return true
 			} else {
 				return false
@@ -738,16 +757,13 @@ func (this *Parser) RegisterPrimaryExprNullptr() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		token := stack_items[0].Token()
+		struct_identifier := stack_items[1].Expr().PrimaryExpr().Token()
 
-		primary_expr := new(expr.PrimaryExpr)
-		primary_expr.InitNullptr(token)
-
-		expr_ := new(expr.Expr)
-		expr_.InitPrimaryExpr(primary_expr)
+		type_specifier_ := new(type_specifier.TypeSpecifier)
+		type_specifier_.InitStruct(type_specifier.STRUCT, struct_identifier)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -758,18 +774,16 @@ func (this *Parser) RegisterPrimaryExprNullptr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPrimaryExprParen() {
+func (this *Parser) RegisterTypeSpecifierStar() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.LPAREN &&
-				stack_items[1].StackItemType() == EXPR &&
-				stack_items[2].StackItemType() == TOKEN &&
-				stack_items[2].Token().TokenType() == lexer.RPAREN {
+			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.STAR {
 				return true
 			} else {
 				return false
@@ -778,16 +792,11 @@ func (this *Parser) RegisterPrimaryExprParen() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		e := stack_items[1].Expr()
-
-		primary_expr := new(expr.PrimaryExpr)
-		primary_expr.InitParen(e)
-
-		expr_ := new(expr.Expr)
-		expr_.InitPrimaryExpr(primary_expr)
+		type_specifier_ := stack_items[0].TypeSpecifier()
+		type_specifier_.AddStar()
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitTypeSpecifier(type_specifier_)
 
 		return stack_item
 	}
@@ -798,19 +807,17 @@ func (this *Parser) RegisterPrimaryExprParen() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprBracket() {
+func (this *Parser) RegisterParamListBegin() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 4 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LBRACKET &&
-				stack_items[2].StackItemType() == EXPR &&
-				stack_items[3].StackItemType() == TOKEN &&
-				stack_items[3].Token().TokenType() == lexer.RBRACKET {
+			if stack_items[0].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[1].StackItemType() == EXPR &&
+				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
+				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
 				return true
 			} else {
 				return false
@@ -819,17 +826,18 @@ func (this *Parser) RegisterPostfixExprBracket() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
-		offset_expr := stack_items[2].Expr()
+		type_specifier_ := stack_items[0].TypeSpecifier()
+		identifier := stack_items[1].Expr().PrimaryExpr().Token()
 
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitBracket(base, offset_expr)
+		param := new(param_list.Param)
+		param.Init(type_specifier_, identifier)
 
-		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		param_list_ := new(param_list.ParamList)
+		param_list_.Init()
+		param_list_.Append(param)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitParamList(param_list_)
 
 		return stack_item
 	}
@@ -840,18 +848,20 @@ func (this *Parser) RegisterPostfixExprBracket() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprCallEmpty() {
+func (this *Parser) RegisterParamListAppend() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 4 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
+			if stack_items[0].StackItemType() == PARAM_LIST &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LPAREN &&
-				stack_items[2].StackItemType() == TOKEN &&
-				stack_items[2].Token().TokenType() == lexer.RPAREN {
+				stack_items[1].Token().TokenType() == lexer.COMMA &&
+				stack_items[2].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[3].StackItemType() == EXPR &&
+				stack_items[3].Expr().ExprType() == expr.PRIMARY &&
+				stack_items[3].Expr().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
 				return true
 			} else {
 				return false
@@ -860,19 +870,17 @@ func (this *Parser) RegisterPostfixExprCallEmpty() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
-
-		arg_list := new(expr.ArgList)
-		arg_list.Init()
+		param_list_ := stack_items[0].ParamList()
+		type_specifier_ := stack_items[2].TypeSpecifier()
+		identifier := stack_items[3].Expr().PrimaryExpr().Token()
 
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitCall(base, arg_list)
+		param := new(param_list.Param)
+		param.Init(type_specifier_, identifier)
 
-		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		param_list_.Append(param)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitParamList(param_list_)
 
 		return stack_item
 	}
@@ -883,17 +891,52 @@ func (this *Parser) RegisterPostfixExprCallEmpty() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprCallSingle() {
-	precedence := map[lexer.TokenType]bool{}
+func (this *Parser) RegisterArgListBegin() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:      true,
+		lexer.LPAREN:        true,
+		lexer.DOT:           true,
+		lexer.ARROW:         true,
+		lexer.STAR:          true,
+		lexer.DIV:           true,
+		lexer.MOD:           true,
+		lexer.PLUS:          true,
+		lexer.MINUS:         true,
+		lexer.LSHIFT:        true,
+		lexer.RSHIFT:        true,
+		lexer.LESS:          true,
+		lexer.LESS_EQ:       true,
+		lexer.GREATER:       true,
+		lexer.GREATER_EQ:    true,
+		lexer.EQ:            true,
+		lexer.NOT_EQ:        true,
+		lexer.AND:           true,
+		lexer.CARET:         true,
+		lexer.OR:            true,
+		lexer.AND_AND:       true,
+		lexer.OR_OR:         true,
+		lexer.QUESTION:      true,
+		lexer.ASSIGN:        true,
+		lexer.STAR_ASSIGN:   true,
+		lexer.DIV_ASSIGN:    true,
+		lexer.MOD_ASSIGN:    true,
+		lexer.PLUS_ASSIGN:   true,
+		lexer.MINUS_ASSIGN:  true,
+		lexer.LSHIFT_ASSIGN: true,
+		lexer.RSHIFT_ASSIGN: true,
+		lexer.AND_ASSIGN:    true,
+		lexer.CARET_ASSIGN:  true,
+		lexer.OR_ASSIGN:     true,
+	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == EXPR &&
-				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.PAREN {
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.COMMA &&
+				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -902,20 +945,16 @@ func (this *Parser) RegisterPostfixExprCallSingle() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
+		arg1 := stack_items[0].Expr()
+		arg2 := stack_items[2].Expr()
 
 		arg_list := new(expr.ArgList)
 		arg_list.Init()
-		arg_list.Append(stack_items[1].Expr().PrimaryExpr().Expr())
-
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitCall(base, arg_list)
-
-		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		arg_list.Append(arg1)
+		arg_list.Append(arg2)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitArgList(arg_list)
 
 		return stack_item
 	}
@@ -926,19 +965,52 @@ func (this *Parser) RegisterPostfixExprCallSingle() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprCallMultiple() {
-	precedence := map[lexer.TokenType]bool{}
+func (this *Parser) RegisterArgListAppend() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:      true,
+		lexer.LPAREN:        true,
+		lexer.DOT:           true,
+		lexer.ARROW:         true,
+		lexer.STAR:          true,
+		lexer.DIV:           true,
+		lexer.MOD:           true,
+		lexer.PLUS:          true,
+		lexer.MINUS:         true,
+		lexer.LSHIFT:        true,
+		lexer.RSHIFT:        true,
+		lexer.LESS:          true,
+		lexer.LESS_EQ:       true,
+		lexer.GREATER:       true,
+		lexer.GREATER_EQ:    true,
+		lexer.EQ:            true,
+		lexer.NOT_EQ:        true,
+		lexer.AND:           true,
+		lexer.CARET:         true,
+		lexer.OR:            true,
+		lexer.AND_AND:       true,
+		lexer.OR_OR:         true,
+		lexer.QUESTION:      true,
+		lexer.ASSIGN:        true,
+		lexer.STAR_ASSIGN:   true,
+		lexer.DIV_ASSIGN:    true,
+		lexer.MOD_ASSIGN:    true,
+		lexer.PLUS_ASSIGN:   true,
+		lexer.MINUS_ASSIGN:  true,
+		lexer.LSHIFT_ASSIGN: true,
+		lexer.RSHIFT_ASSIGN: true,
+		lexer.AND_ASSIGN:    true,
+		lexer.CARET_ASSIGN:  true,
+		lexer.OR_ASSIGN:     true,
+	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 4 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
+			if stack_items[0].StackItemType() == ARG_LIST &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LPAREN &&
-				stack_items[2].StackItemType() == ARG_LIST &&
-				stack_items[3].StackItemType() == TOKEN &&
-				stack_items[3].Token().TokenType() == lexer.RPAREN {
+				stack_items[1].Token().TokenType() == lexer.COMMA &&
+				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -947,17 +1019,13 @@ func (this *Parser) RegisterPostfixExprCallMultiple() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
-		arg_list := stack_items[2].ArgList()
-
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitCall(base, arg_list)
+		arg_list := stack_items[0].ArgList()
+		arg := stack_items[2].Expr()
 
-		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		arg_list.Append(arg)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitArgList(arg_list)
 
 		return stack_item
 	}
@@ -968,18 +1036,15 @@ func (this *Parser) RegisterPostfixExprCallMultiple() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprDot() {
+func (this *Parser) RegisterPrimaryExprIdentifier() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.DOT &&
-				stack_items[2].StackItemType() == TOKEN &&
-				stack_items[2].Token().TokenType() == lexer.IDENTIFIER {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.IDENTIFIER {
 				return true
 			} else {
 				return false
@@ -988,14 +1053,13 @@ func (this *Parser) RegisterPostfixExprDot() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
-		offset_token := stack_items[2].Token()
+		token := stack_items[0].Token()
 
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitDot(base, offset_token)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitIdentifier(token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1009,18 +1073,15 @@ func (this *Parser) RegisterPostfixExprDot() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprArrow() {
+func (this *Parser) RegisterPrimaryExprNumber() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.ARROW &&
-				stack_items[2].StackItemType() == TOKEN &&
-				stack_items[2].Token().TokenType() == lexer.IDENTIFIER {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.NUMBER {
 				return true
 			} else {
 				return false
@@ -1029,14 +1090,13 @@ func (this *Parser) RegisterPostfixExprArrow() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
-		offset_token := stack_items[2].Token()
+		token := stack_items[0].Token()
 
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitArrow(base, offset_token)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitNumber(token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1050,16 +1110,15 @@ func (this *Parser) RegisterPostfixExprArrow() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprPlusPlus() {
+func (this *Parser) RegisterPrimaryExprFloating() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.PLUS_PLUS {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.FLOATING {
 				return true
 			} else {
 				return false
@@ -1068,13 +1127,13 @@ func (this *Parser) RegisterPostfixExprPlusPlus() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
+		token := stack_items[0].Token()
 
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitPlusPlus(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitFloating(token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1088,16 +1147,15 @@ func (this *Parser) RegisterPostfixExprPlusPlus() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterPostfixExprMinusMinus() {
+func (this *Parser) RegisterPrimaryExprCharacter() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.MINUS_MINUS {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.CHARACTER {
 				return true
 			} else {
 				return false
@@ -1106,13 +1164,13 @@ func (this *Parser) RegisterPostfixExprMinusMinus() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[0].Expr()
+		token := stack_items[0].Token()
 
-		postfix_expr := new(expr.PostfixExpr)
-		postfix_expr.InitMinusMinus(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitCharacter(token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitPostfixExpr(postfix_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1126,16 +1184,15 @@ func (this *Parser) RegisterPostfixExprMinusMinus() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprPlusPlus() {
+func (this *Parser) RegisterPrimaryExprString() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.PLUS_PLUS &&
-				stack_items[1].StackItemType() == EXPR {
+				stack_items[0].Token().TokenType() == lexer.STRING {
 				return true
 			} else {
 				return false
@@ -1144,13 +1201,13 @@ func (this *Parser) RegisterUnaryExprPlusPlus() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		token := stack_items[0].Token()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitPlusPlus(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitString(token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1164,16 +1221,15 @@ func (this *Parser) RegisterUnaryExprPlusPlus() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprMinusMinus() {
+func (this *Parser) RegisterPrimaryExprNullptr() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 1 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.MINUS_MINUS &&
-				stack_items[1].StackItemType() == EXPR {
+				stack_items[0].Token().TokenType() == lexer.NULL {
 				return true
 			} else {
 				return false
@@ -1182,13 +1238,13 @@ func (this *Parser) RegisterUnaryExprMinusMinus() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		token := stack_items[0].Token()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitMinusMinus(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitNullptr(token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1202,23 +1258,18 @@ func (this *Parser) RegisterUnaryExprMinusMinus() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprAnd() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPrimaryExprParen() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.AND &&
-				stack_items[1].StackItemType() == EXPR {
+				stack_items[0].Token().TokenType() == lexer.LPAREN &&
+				stack_items[1].StackItemType() == EXPR &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.RPAREN {
 				return true
 			} else {
 				return false
@@ -1227,13 +1278,13 @@ func (this *Parser) RegisterUnaryExprAnd() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		e := stack_items[1].Expr()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitAnd(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitParen(e)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1247,23 +1298,19 @@ func (this *Parser) RegisterUnaryExprAnd() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprStar() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPrimaryExprInitializerList() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.STAR &&
-				stack_items[1].StackItemType() == EXPR {
+				stack_items[0].Token().TokenType() == lexer.LBRACE &&
+				(stack_items[1].StackItemType() == EXPR ||
+					stack_items[1].StackItemType() == ARG_LIST) &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.RBRACE {
 				return true
 			} else {
 				return false
@@ -1272,13 +1319,20 @@ func (this *Parser) RegisterUnaryExprStar() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		var arg_list *expr.ArgList
+		if stack_items[1].StackItemType() == ARG_LIST {
+			arg_list = stack_items[1].ArgList()
+		} else {
+			arg_list = new(expr.ArgList)
+			arg_list.Init()
+			arg_list.Append(stack_items[1].Expr())
+		}
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitStar(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitInitializerList(arg_list)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1292,23 +1346,21 @@ func (this *Parser) RegisterUnaryExprStar() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprPlus() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPrimaryExprInitializerListComma() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 4 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.PLUS &&
-				stack_items[1].StackItemType() == EXPR {
+				stack_items[0].Token().TokenType() == lexer.LBRACE &&
+				(stack_items[1].StackItemType() == EXPR ||
+					stack_items[1].StackItemType() == ARG_LIST) &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.COMMA &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RBRACE {
 				return true
 			} else {
 				return false
@@ -1317,13 +1369,20 @@ func (this *Parser) RegisterUnaryExprPlus() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		var arg_list *expr.ArgList
+		if stack_items[1].StackItemType() == ARG_LIST {
+			arg_list = stack_items[1].ArgList()
+		} else {
+			arg_list = new(expr.ArgList)
+			arg_list.Init()
+			arg_list.Append(stack_items[1].Expr())
+		}
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitPlus(base)
+		primary_expr := new(expr.PrimaryExpr)
+		primary_expr.InitInitializerList(arg_list)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPrimaryExpr(primary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1337,23 +1396,19 @@ func (this *Parser) RegisterUnaryExprPlus() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprMinus() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPostfixExprBracket() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 4 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.MINUS &&
-				stack_items[1].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LBRACKET &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RBRACKET {
 				return true
 			} else {
 				return false
@@ -1362,13 +1417,14 @@ func (this *Parser) RegisterUnaryExprMinus() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		base := stack_items[0].Expr()
+		offset_expr := stack_items[2].Expr()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitMinus(base)
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitBracket(base, offset_expr)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1382,23 +1438,18 @@ func (this *Parser) RegisterUnaryExprMinus() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprTilde() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPostfixExprCallEmpty() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.TILDE &&
-				stack_items[1].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LPAREN &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.RPAREN {
 				return true
 			} else {
 				return false
@@ -1407,13 +1458,16 @@ func (this *Parser) RegisterUnaryExprTilde() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		base := stack_items[0].Expr()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitTilde(base)
+		arg_list := new(expr.ArgList)
+		arg_list.Init()
+
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitCall(base, arg_list)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1427,23 +1481,17 @@ func (this *Parser) RegisterUnaryExprTilde() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprNot() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPostfixExprCallSingle() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.NOT &&
-				stack_items[1].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == EXPR &&
+				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
+				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.PAREN {
 				return true
 			} else {
 				return false
@@ -1452,13 +1500,17 @@ func (this *Parser) RegisterUnaryExprNot() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		base := stack_items[1].Expr()
+		base := stack_items[0].Expr()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitNot(base)
+		arg_list := new(expr.ArgList)
+		arg_list.Init()
+		arg_list.Append(stack_items[1].Expr().PrimaryExpr().Expr())
+
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitCall(base, arg_list)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1472,18 +1524,17 @@ func (this *Parser) RegisterUnaryExprNot() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterUnaryExprSizeof() {
+func (this *Parser) RegisterPostfixExprCallMultiple() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 4 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.SIZEOF &&
+			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
 				stack_items[1].Token().TokenType() == lexer.LPAREN &&
-				stack_items[2].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[2].StackItemType() == ARG_LIST &&
 				stack_items[3].StackItemType() == TOKEN &&
 				stack_items[3].Token().TokenType() == lexer.RPAREN {
 				return true
@@ -1494,13 +1545,14 @@ func (this *Parser) RegisterUnaryExprSizeof() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		type_specifier_ := stack_items[2].TypeSpecifier()
+		base := stack_items[0].Expr()
+		arg_list := stack_items[2].ArgList()
 
-		unary_expr := new(expr.UnaryExpr)
-		unary_expr.InitSizeof(type_specifier_)
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitCall(base, arg_list)
 
 		expr_ := new(expr.Expr)
-		expr_.InitUnaryExpr(unary_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1514,15 +1566,8 @@ func (this *Parser) RegisterUnaryExprSizeof() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterMultiplicativeExprMul() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPostfixExprDot() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 3 {
@@ -1530,8 +1575,9 @@ func (this *Parser) RegisterMultiplicativeExprMul() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.STAR &&
-				stack_items[2].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.DOT &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.IDENTIFIER {
 				return true
 			} else {
 				return false
@@ -1540,14 +1586,14 @@ func (this *Parser) RegisterMultiplicativeExprMul() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[0].Expr()
+		offset_token := stack_items[2].Token()
 
-		multiplicative_expr := new(expr.MultiplicativeExpr)
-		multiplicative_expr.Init(expr.MUL, loperand, roperand)
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitDot(base, offset_token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitMultiplicativeExpr(multiplicative_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1561,15 +1607,8 @@ func (this *Parser) RegisterMultiplicativeExprMul() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterMultiplicativeExprDiv() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPostfixExprArrow() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 3 {
@@ -1577,8 +1616,9 @@ func (this *Parser) RegisterMultiplicativeExprDiv() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.DIV &&
-				stack_items[2].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.ARROW &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.IDENTIFIER {
 				return true
 			} else {
 				return false
@@ -1587,14 +1627,14 @@ func (this *Parser) RegisterMultiplicativeExprDiv() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[0].Expr()
+		offset_token := stack_items[2].Token()
 
-		multiplicative_expr := new(expr.MultiplicativeExpr)
-		multiplicative_expr.Init(expr.DIV, loperand, roperand)
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitArrow(base, offset_token)
 
 		expr_ := new(expr.Expr)
-		expr_.InitMultiplicativeExpr(multiplicative_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1608,24 +1648,16 @@ func (this *Parser) RegisterMultiplicativeExprDiv() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterMultiplicativeExprMod() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-	}
+func (this *Parser) RegisterPostfixExprPlusPlus() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.MOD &&
-				stack_items[2].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.PLUS_PLUS {
 				return true
 			} else {
 				return false
@@ -1634,14 +1666,13 @@ func (this *Parser) RegisterMultiplicativeExprMod() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[0].Expr()
 
-		multiplicative_expr := new(expr.MultiplicativeExpr)
-		multiplicative_expr.Init(expr.MOD, loperand, roperand)
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitPlusPlus(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitMultiplicativeExpr(multiplicative_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1655,27 +1686,16 @@ func (this *Parser) RegisterMultiplicativeExprMod() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAdditiveExprAdd() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-	}
+func (this *Parser) RegisterPostfixExprMinusMinus() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.PLUS &&
-				stack_items[2].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.MINUS_MINUS {
 				return true
 			} else {
 				return false
@@ -1684,14 +1704,13 @@ func (this *Parser) RegisterAdditiveExprAdd() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[0].Expr()
 
-		additive_expr := new(expr.AdditiveExpr)
-		additive_expr.Init(expr.ADD, loperand, roperand)
+		postfix_expr := new(expr.PostfixExpr)
+		postfix_expr.InitMinusMinus(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAdditiveExpr(additive_expr)
+		expr_.InitPostfixExpr(postfix_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1705,27 +1724,16 @@ func (this *Parser) RegisterAdditiveExprAdd() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAdditiveExprSub() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-	}
+func (this *Parser) RegisterUnaryExprPlusPlus() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.MINUS &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.PLUS_PLUS &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -1734,14 +1742,13 @@ func (this *Parser) RegisterAdditiveExprSub() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		additive_expr := new(expr.AdditiveExpr)
-		additive_expr.Init(expr.SUB, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitPlusPlus(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAdditiveExpr(additive_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1755,29 +1762,16 @@ func (this *Parser) RegisterAdditiveExprSub() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterShiftExprLshift() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-	}
+func (this *Parser) RegisterUnaryExprMinusMinus() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LSHIFT &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.MINUS_MINUS &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -1786,14 +1780,13 @@ func (this *Parser) RegisterShiftExprLshift() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		shift_expr := new(expr.ShiftExpr)
-		shift_expr.Init(expr.LSHIFT, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitMinusMinus(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitShiftExpr(shift_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1807,7 +1800,7 @@ func (this *Parser) RegisterShiftExprLshift() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterShiftExprRshift() {
+func (this *Parser) RegisterUnaryExprAnd() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -1815,21 +1808,15 @@ func (this *Parser) RegisterShiftExprRshift() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.RSHIFT &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.AND &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -1838,14 +1825,13 @@ func (this *Parser) RegisterShiftExprRshift() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		shift_expr := new(expr.ShiftExpr)
-		shift_expr.Init(expr.RSHIFT, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitAnd(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitShiftExpr(shift_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1859,7 +1845,7 @@ func (this *Parser) RegisterShiftExprRshift() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterRelationalExprLess() {
+func (this *Parser) RegisterUnaryExprStar() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -1867,23 +1853,15 @@ func (this *Parser) RegisterRelationalExprLess() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LESS &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.STAR &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -1892,14 +1870,13 @@ func (this *Parser) RegisterRelationalExprLess() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		relational_expr := new(expr.RelationalExpr)
-		relational_expr.Init(expr.LESS, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitStar(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitRelationalExpr(relational_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1913,7 +1890,7 @@ func (this *Parser) RegisterRelationalExprLess() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterRelationalExprLessEq() {
+func (this *Parser) RegisterUnaryExprPlus() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -1921,23 +1898,15 @@ func (this *Parser) RegisterRelationalExprLessEq() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LESS_EQ &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.PLUS &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -1946,14 +1915,13 @@ func (this *Parser) RegisterRelationalExprLessEq() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		relational_expr := new(expr.RelationalExpr)
-		relational_expr.Init(expr.LESS_EQ, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitPlus(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitRelationalExpr(relational_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -1967,7 +1935,7 @@ func (this *Parser) RegisterRelationalExprLessEq() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterRelationalExprGreater() {
+func (this *Parser) RegisterUnaryExprMinus() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -1975,23 +1943,15 @@ func (this *Parser) RegisterRelationalExprGreater() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.GREATER &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.MINUS &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -2000,14 +1960,13 @@ func (this *Parser) RegisterRelationalExprGreater() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		relational_expr := new(expr.RelationalExpr)
-		relational_expr.Init(expr.GREATER, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitMinus(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitRelationalExpr(relational_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2021,7 +1980,7 @@ func (this *Parser) RegisterRelationalExprGreater() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterRelationalExprGreaterEq() {
+func (this *Parser) RegisterUnaryExprTilde() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2029,23 +1988,15 @@ func (this *Parser) RegisterRelationalExprGreaterEq() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.GREATER_EQ &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.TILDE &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -2054,14 +2005,13 @@ func (this *Parser) RegisterRelationalExprGreaterEq() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		relational_expr := new(expr.RelationalExpr)
-		relational_expr.Init(expr.GREATER_EQ, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitTilde(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitRelationalExpr(relational_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2075,7 +2025,7 @@ func (this *Parser) RegisterRelationalExprGreaterEq() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterEqualityExprEq() {
+func (this *Parser) RegisterUnaryExprNot() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2083,27 +2033,57 @@ func (this *Parser) RegisterEqualityExprEq() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.NOT &&
+				stack_items[1].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		base := stack_items[1].Expr()
+
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitNot(base)
+
+		expr_ := new(expr.Expr)
+		expr_.InitUnaryExpr(unary_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterUnaryExprSizeof() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 4 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.SIZEOF &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.EQ &&
-				stack_items[2].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.LPAREN &&
+				stack_items[2].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RPAREN {
 				return true
 			} else {
 				return false
@@ -2112,14 +2092,13 @@ func (this *Parser) RegisterEqualityExprEq() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		type_specifier_ := stack_items[2].TypeSpecifier()
 
-		equality_expr := new(expr.EqualityExpr)
-		equality_expr.Init(expr.EQ, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitSizeof(type_specifier_)
 
 		expr_ := new(expr.Expr)
-		expr_.InitEqualityExpr(equality_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2133,7 +2112,7 @@ func (this *Parser) RegisterEqualityExprEq() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterEqualityExprNotEq() {
+func (this *Parser) RegisterUnaryExprSizeofExpr() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2141,27 +2120,15 @@ func (this *Parser) RegisterEqualityExprNotEq() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.NOT_EQ &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.SIZEOF &&
+				stack_items[1].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -2170,14 +2137,13 @@ func (this *Parser) RegisterEqualityExprNotEq() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		base := stack_items[1].Expr()
 
-		equality_expr := new(expr.EqualityExpr)
-		equality_expr.Init(expr.NOT_EQ, loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitSizeofExpr(base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitEqualityExpr(equality_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2191,7 +2157,7 @@ func (this *Parser) RegisterEqualityExprNotEq() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterBitwiseAndExpr() {
+func (this *Parser) RegisterUnaryExprCast() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2199,29 +2165,18 @@ func (this *Parser) RegisterBitwiseAndExpr() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 4 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.AND &&
-				stack_items[2].StackItemType() == EXPR {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.LPAREN &&
+				stack_items[1].StackItemType() == TYPE_SPECIFIER &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.RPAREN &&
+				stack_items[3].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -2230,14 +2185,14 @@ func (this *Parser) RegisterBitwiseAndExpr() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		loperand := stack_items[0].Expr()
-		roperand := stack_items[2].Expr()
+		type_specifier_ := stack_items[1].TypeSpecifier()
+		base := stack_items[3].Expr()
 
-		bitwise_and_expr := new(expr.BitwiseAndExpr)
-		bitwise_and_expr.Init(loperand, roperand)
+		unary_expr := new(expr.UnaryExpr)
+		unary_expr.InitCast(type_specifier_, base)
 
 		expr_ := new(expr.Expr)
-		expr_.InitBitwiseAndExpr(bitwise_and_expr)
+		expr_.InitUnaryExpr(unary_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2251,7 +2206,7 @@ func (this *Parser) RegisterBitwiseAndExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterBitwiseXorExpr() {
+func (this *Parser) RegisterMultiplicativeExprMul() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2259,20 +2214,6 @@ func (this *Parser) RegisterBitwiseXorExpr() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2281,7 +2222,7 @@ func (this *Parser) RegisterBitwiseXorExpr() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.CARET &&
+				stack_items[1].Token().TokenType() == lexer.STAR &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2294,11 +2235,11 @@ func (this *Parser) RegisterBitwiseXorExpr() {
 		loperand := stack_items[0].Expr()
 		roperand := stack_items[2].Expr()
 
-		bitwise_xor_expr := new(expr.BitwiseXorExpr)
-		bitwise_xor_expr.Init(loperand, roperand)
+		multiplicative_expr := new(expr.MultiplicativeExpr)
+		multiplicative_expr.Init(expr.MUL, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitBitwiseXorExpr(bitwise_xor_expr)
+		expr_.InitMultiplicativeExpr(multiplicative_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2312,7 +2253,7 @@ func (this *Parser) RegisterBitwiseXorExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterBitwiseOrExpr() {
+func (this *Parser) RegisterMultiplicativeExprDiv() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2320,21 +2261,6 @@ func (this *Parser) RegisterBitwiseOrExpr() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2343,7 +2269,7 @@ func (this *Parser) RegisterBitwiseOrExpr() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.OR &&
+				stack_items[1].Token().TokenType() == lexer.DIV &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2356,11 +2282,11 @@ func (this *Parser) RegisterBitwiseOrExpr() {
 		loperand := stack_items[0].Expr()
 		roperand := stack_items[2].Expr()
 
-		bitwise_or_expr := new(expr.BitwiseOrExpr)
-		bitwise_or_expr.Init(loperand, roperand)
+		multiplicative_expr := new(expr.MultiplicativeExpr)
+		multiplicative_expr.Init(expr.DIV, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitBitwiseOrExpr(bitwise_or_expr)
+		expr_.InitMultiplicativeExpr(multiplicative_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2374,7 +2300,7 @@ func (this *Parser) RegisterBitwiseOrExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterLogicalAndExpr() {
+func (this *Parser) RegisterMultiplicativeExprMod() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2382,22 +2308,6 @@ func (this *Parser) RegisterLogicalAndExpr() {
 		lexer.ARROW:       true,
 		lexer.PLUS_PLUS:   true,
 		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2406,7 +2316,7 @@ func (this *Parser) RegisterLogicalAndExpr() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.AND_AND &&
+				stack_items[1].Token().TokenType() == lexer.MOD &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2419,11 +2329,11 @@ func (this *Parser) RegisterLogicalAndExpr() {
 		loperand := stack_items[0].Expr()
 		roperand := stack_items[2].Expr()
 
-		logical_and_expr := new(expr.LogicalAndExpr)
-		logical_and_expr.Init(loperand, roperand)
+		multiplicative_expr := new(expr.MultiplicativeExpr)
+		multiplicative_expr.Init(expr.MOD, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitLogicalAndExpr(logical_and_expr)
+		expr_.InitMultiplicativeExpr(multiplicative_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2437,7 +2347,7 @@ func (this *Parser) RegisterLogicalAndExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterLogicalOrExpr() {
+func (this *Parser) RegisterAdditiveExprAdd() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2448,20 +2358,6 @@ func (this *Parser) RegisterLogicalOrExpr() {
 		lexer.STAR:        true,
 		lexer.DIV:         true,
 		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2470,7 +2366,7 @@ func (this *Parser) RegisterLogicalOrExpr() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.OR_OR &&
+				stack_items[1].Token().TokenType() == lexer.PLUS &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2483,11 +2379,11 @@ func (this *Parser) RegisterLogicalOrExpr() {
 		loperand := stack_items[0].Expr()
 		roperand := stack_items[2].Expr()
 
-		logical_or_expr := new(expr.LogicalOrExpr)
-		logical_or_expr.Init(loperand, roperand)
+		additive_expr := new(expr.AdditiveExpr)
+		additive_expr.Init(expr.ADD, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitLogicalOrExpr(logical_or_expr)
+		expr_.InitAdditiveExpr(additive_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2501,7 +2397,7 @@ func (this *Parser) RegisterLogicalOrExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterConditionalExpr() {
+func (this *Parser) RegisterAdditiveExprSub() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2512,34 +2408,16 @@ func (this *Parser) RegisterConditionalExpr() {
 		lexer.STAR:        true,
 		lexer.DIV:         true,
 		lexer.MOD:         true,
-		lexer.PLUS:        true,
-		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 5 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.QUESTION &&
-				stack_items[2].StackItemType() == EXPR &&
-				stack_items[3].StackItemType() == TOKEN &&
-				stack_items[3].Token().TokenType() == lexer.COLON &&
-				stack_items[4].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.MINUS &&
+				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
 				return false
@@ -2548,15 +2426,14 @@ func (this *Parser) RegisterConditionalExpr() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		condition_expr := stack_items[0].Expr()
-		true_expr := stack_items[2].Expr()
-		false_expr := stack_items[4].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		conditional_expr := new(expr.ConditionalExpr)
-		conditional_expr.Init(condition_expr, true_expr, false_expr)
+		additive_expr := new(expr.AdditiveExpr)
+		additive_expr.Init(expr.SUB, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitConditionalExpr(conditional_expr)
+		expr_.InitAdditiveExpr(additive_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2570,7 +2447,7 @@ func (this *Parser) RegisterConditionalExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprAssign() {
+func (this *Parser) RegisterShiftExprLshift() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2583,20 +2460,6 @@ func (this *Parser) RegisterAssignmentExprAssign() {
 		lexer.MOD:         true,
 		lexer.PLUS:        true,
 		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2605,7 +2468,7 @@ func (this *Parser) RegisterAssignmentExprAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.LSHIFT &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2615,14 +2478,14 @@ func (this *Parser) RegisterAssignmentExprAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.ASSIGN, lvalue, rvalue)
+		shift_expr := new(expr.ShiftExpr)
+		shift_expr.Init(expr.LSHIFT, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitShiftExpr(shift_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2636,7 +2499,7 @@ func (this *Parser) RegisterAssignmentExprAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprStarAssign() {
+func (this *Parser) RegisterShiftExprRshift() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2649,20 +2512,6 @@ func (this *Parser) RegisterAssignmentExprStarAssign() {
 		lexer.MOD:         true,
 		lexer.PLUS:        true,
 		lexer.MINUS:       true,
-		lexer.LSHIFT:      true,
-		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2671,7 +2520,7 @@ func (this *Parser) RegisterAssignmentExprStarAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.STAR_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.RSHIFT &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2681,14 +2530,14 @@ func (this *Parser) RegisterAssignmentExprStarAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.STAR_ASSIGN, lvalue, rvalue)
+		shift_expr := new(expr.ShiftExpr)
+		shift_expr.Init(expr.RSHIFT, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitShiftExpr(shift_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2702,7 +2551,7 @@ func (this *Parser) RegisterAssignmentExprStarAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprDivAssign() {
+func (this *Parser) RegisterRelationalExprLess() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2717,18 +2566,6 @@ func (this *Parser) RegisterAssignmentExprDivAssign() {
 		lexer.MINUS:       true,
 		lexer.LSHIFT:      true,
 		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2737,7 +2574,7 @@ func (this *Parser) RegisterAssignmentExprDivAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.DIV_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.LESS &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2747,14 +2584,14 @@ func (this *Parser) RegisterAssignmentExprDivAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.DIV_ASSIGN, lvalue, rvalue)
+		relational_expr := new(expr.RelationalExpr)
+		relational_expr.Init(expr.LESS, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitRelationalExpr(relational_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2768,7 +2605,7 @@ func (this *Parser) RegisterAssignmentExprDivAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprModAssign() {
+func (this *Parser) RegisterRelationalExprLessEq() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2783,18 +2620,6 @@ func (this *Parser) RegisterAssignmentExprModAssign() {
 		lexer.MINUS:       true,
 		lexer.LSHIFT:      true,
 		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2803,7 +2628,7 @@ func (this *Parser) RegisterAssignmentExprModAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.MOD_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.LESS_EQ &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2813,14 +2638,14 @@ func (this *Parser) RegisterAssignmentExprModAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.MOD_ASSIGN, lvalue, rvalue)
+		relational_expr := new(expr.RelationalExpr)
+		relational_expr.Init(expr.LESS_EQ, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitRelationalExpr(relational_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2834,7 +2659,7 @@ func (this *Parser) RegisterAssignmentExprModAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprPlusAssign() {
+func (this *Parser) RegisterRelationalExprGreater() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2849,18 +2674,6 @@ func (this *Parser) RegisterAssignmentExprPlusAssign() {
 		lexer.MINUS:       true,
 		lexer.LSHIFT:      true,
 		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2869,7 +2682,7 @@ func (this *Parser) RegisterAssignmentExprPlusAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.PLUS_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.GREATER &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2879,14 +2692,14 @@ func (this *Parser) RegisterAssignmentExprPlusAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.PLUS_ASSIGN, lvalue, rvalue)
+		relational_expr := new(expr.RelationalExpr)
+		relational_expr.Init(expr.GREATER, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitRelationalExpr(relational_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2900,7 +2713,7 @@ func (this *Parser) RegisterAssignmentExprPlusAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprMinusAssign() {
+func (this *Parser) RegisterRelationalExprGreaterEq() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2915,18 +2728,6 @@ func (this *Parser) RegisterAssignmentExprMinusAssign() {
 		lexer.MINUS:       true,
 		lexer.LSHIFT:      true,
 		lexer.RSHIFT:      true,
-		lexer.LESS:        true,
-		lexer.LESS_EQ:     true,
-		lexer.GREATER:     true,
-		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -2935,7 +2736,7 @@ func (this *Parser) RegisterAssignmentExprMinusAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.MINUS_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.GREATER_EQ &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -2945,14 +2746,14 @@ func (this *Parser) RegisterAssignmentExprMinusAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.MINUS_ASSIGN, lvalue, rvalue)
+		relational_expr := new(expr.RelationalExpr)
+		relational_expr.Init(expr.GREATER_EQ, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitRelationalExpr(relational_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -2966,7 +2767,7 @@ func (this *Parser) RegisterAssignmentExprMinusAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprLshiftAssign() {
+func (this *Parser) RegisterEqualityExprEq() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -2985,14 +2786,6 @@ func (this *Parser) RegisterAssignmentExprLshiftAssign() {
 		lexer.LESS_EQ:     true,
 		lexer.GREATER:     true,
 		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -3001,7 +2794,7 @@ func (this *Parser) RegisterAssignmentExprLshiftAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LSHIFT_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.EQ &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -3011,14 +2804,14 @@ func (this *Parser) RegisterAssignmentExprLshiftAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.LSHIFT_ASSIGN, lvalue, rvalue)
+		equality_expr := new(expr.EqualityExpr)
+		equality_expr.Init(expr.EQ, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitEqualityExpr(equality_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -3032,7 +2825,7 @@ func (this *Parser) RegisterAssignmentExprLshiftAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprRshiftAssign() {
+func (this *Parser) RegisterEqualityExprNotEq() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -3051,14 +2844,6 @@ func (this *Parser) RegisterAssignmentExprRshiftAssign() {
 		lexer.LESS_EQ:     true,
 		lexer.GREATER:     true,
 		lexer.GREATER_EQ:  true,
-		lexer.EQ:          true,
-		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -3067,7 +2852,7 @@ func (this *Parser) RegisterAssignmentExprRshiftAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.RSHIFT_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.NOT_EQ &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -3077,14 +2862,14 @@ func (this *Parser) RegisterAssignmentExprRshiftAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.RSHIFT_ASSIGN, lvalue, rvalue)
+		equality_expr := new(expr.EqualityExpr)
+		equality_expr.Init(expr.NOT_EQ, loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitEqualityExpr(equality_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -3098,7 +2883,7 @@ func (this *Parser) RegisterAssignmentExprRshiftAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprAndAssign() {
+func (this *Parser) RegisterBitwiseAndExpr() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -3119,12 +2904,6 @@ func (this *Parser) RegisterAssignmentExprAndAssign() {
 		lexer.GREATER_EQ:  true,
 		lexer.EQ:          true,
 		lexer.NOT_EQ:      true,
-		lexer.AND:         true,
-		lexer.CARET:       true,
-		lexer.OR:          true,
-		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -3133,7 +2912,7 @@ func (this *Parser) RegisterAssignmentExprAndAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.AND_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.AND &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -3143,14 +2922,14 @@ func (this *Parser) RegisterAssignmentExprAndAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.AND_ASSIGN, lvalue, rvalue)
+		bitwise_and_expr := new(expr.BitwiseAndExpr)
+		bitwise_and_expr.Init(loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitBitwiseAndExpr(bitwise_and_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -3164,7 +2943,193 @@ func (this *Parser) RegisterAssignmentExprAndAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprCaretAssign() {
+func (this *Parser) RegisterBitwiseXorExpr() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.CARET &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
+
+		bitwise_xor_expr := new(expr.BitwiseXorExpr)
+		bitwise_xor_expr.Init(loperand, roperand)
+
+		expr_ := new(expr.Expr)
+		expr_.InitBitwiseXorExpr(bitwise_xor_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterBitwiseOrExpr() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.OR &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
+
+		bitwise_or_expr := new(expr.BitwiseOrExpr)
+		bitwise_or_expr.Init(loperand, roperand)
+
+		expr_ := new(expr.Expr)
+		expr_.InitBitwiseOrExpr(bitwise_or_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterLogicalAndExpr() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.AND_AND &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
+
+		logical_and_expr := new(expr.LogicalAndExpr)
+		logical_and_expr.Init(loperand, roperand)
+
+		expr_ := new(expr.Expr)
+		expr_.InitLogicalAndExpr(logical_and_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterLogicalOrExpr() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -3189,8 +3154,6 @@ func (this *Parser) RegisterAssignmentExprCaretAssign() {
 		lexer.CARET:       true,
 		lexer.OR:          true,
 		lexer.AND_AND:     true,
-		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
@@ -3199,7 +3162,7 @@ func (this *Parser) RegisterAssignmentExprCaretAssign() {
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.CARET_ASSIGN &&
+				stack_items[1].Token().TokenType() == lexer.OR_OR &&
 				stack_items[2].StackItemType() == EXPR {
 				return true
 			} else {
@@ -3209,14 +3172,14 @@ func (this *Parser) RegisterAssignmentExprCaretAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		loperand := stack_items[0].Expr()
+		roperand := stack_items[2].Expr()
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.CARET_ASSIGN, lvalue, rvalue)
+		logical_or_expr := new(expr.LogicalOrExpr)
+		logical_or_expr.Init(loperand, roperand)
 
 		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		expr_.InitLogicalOrExpr(logical_or_expr)
 
 		stack_item := new(StackItem)
 		stack_item.InitExpr(expr_)
@@ -3230,7 +3193,7 @@ func (this *Parser) RegisterAssignmentExprCaretAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterAssignmentExprOrAssign() {
+func (this *Parser) RegisterConditionalExpr() {
 	precedence := map[lexer.TokenType]bool{
 		lexer.LBRACKET:    true,
 		lexer.LPAREN:      true,
@@ -3256,17 +3219,1561 @@ func (this *Parser) RegisterAssignmentExprOrAssign() {
 		lexer.OR:          true,
 		lexer.AND_AND:     true,
 		lexer.OR_OR:       true,
-		lexer.QUESTION:    true,
 	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 5 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.QUESTION &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.COLON &&
+				stack_items[4].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		condition_expr := stack_items[0].Expr()
+		true_expr := stack_items[2].Expr()
+		false_expr := stack_items[4].Expr()
+
+		conditional_expr := new(expr.ConditionalExpr)
+		conditional_expr.Init(condition_expr, true_expr, false_expr)
+
+		expr_ := new(expr.Expr)
+		expr_.InitConditionalExpr(conditional_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprStarAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.STAR_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.STAR_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprDivAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.DIV_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.DIV_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprModAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.MOD_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.MOD_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprPlusAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.PLUS_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.PLUS_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprMinusAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.MINUS_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.MINUS_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprLshiftAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LSHIFT_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.LSHIFT_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprRshiftAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.RSHIFT_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.RSHIFT_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprAndAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.AND_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.AND_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprCaretAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.CARET_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.CARET_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterAssignmentExprOrAssign() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+		lexer.PLUS:        true,
+		lexer.MINUS:       true,
+		lexer.LSHIFT:      true,
+		lexer.RSHIFT:      true,
+		lexer.LESS:        true,
+		lexer.LESS_EQ:     true,
+		lexer.GREATER:     true,
+		lexer.GREATER_EQ:  true,
+		lexer.EQ:          true,
+		lexer.NOT_EQ:      true,
+		lexer.AND:         true,
+		lexer.CARET:       true,
+		lexer.OR:          true,
+		lexer.AND_AND:     true,
+		lexer.OR_OR:       true,
+		lexer.QUESTION:    true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.OR_ASSIGN &&
+				stack_items[2].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[2].Expr()
+
+		assignment_expr := new(expr.AssignmentExpr)
+		assignment_expr.Init(expr.OR_ASSIGN, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAssignmentExpr(assignment_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterConcatExpr() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:    true,
+		lexer.LPAREN:      true,
+		lexer.DOT:         true,
+		lexer.ARROW:       true,
+		lexer.PLUS_PLUS:   true,
+		lexer.MINUS_MINUS: true,
+		lexer.STAR:        true,
+		lexer.DIV:         true,
+		lexer.MOD:         true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 2 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == EXPR &&
+				stack_items[1].StackItemType() == EXPR {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		lvalue := stack_items[0].Expr()
+		rvalue := stack_items[1].Expr()
+
+		additive_expr := new(expr.AdditiveExpr)
+		additive_expr.Init(expr.ADD, lvalue, rvalue)
+
+		expr_ := new(expr.Expr)
+		expr_.InitAdditiveExpr(additive_expr)
+
+		stack_item := new(StackItem)
+		stack_item.InitExpr(expr_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterEmptyStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 1 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		empty_stmt := new(stmt.EmptyStmt)
+		empty_stmt.Init()
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitEmptyStmt(empty_stmt)
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterVarDeclStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 2 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+
+		type_specifier_ := param.TypeSpecifier()
+		identifier := param.Identifier()
+
+		var_decl_stmt := new(stmt.VarDeclStmt)
+		var_decl_stmt.Init(type_specifier_, identifier)
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitVarDeclStmt(var_decl_stmt)
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterVarDeclInitStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 4 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.ASSIGN &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		rvalue := stack_items[2].Expr()
+
+		type_specifier_ := param.TypeSpecifier()
+		identifier := param.Identifier()
+
+		var_decl_init_stmt := new(stmt.VarDeclInitStmt)
+		var_decl_init_stmt.Init(type_specifier_, identifier, rvalue)
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitVarDeclInitStmt(var_decl_init_stmt)
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterArrayDeclStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 5 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LBRACKET &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RBRACKET &&
+				stack_items[4].StackItemType() == TOKEN &&
+				stack_items[4].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		size := stack_items[2].Expr()
+
+		type_specifier_ := param.TypeSpecifier()
+		identifier := param.Identifier()
+
+		array_decl_stmt := new(stmt.ArrayDeclStmt)
+		array_decl_stmt.Init(type_specifier_, identifier, size, nil)
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitArrayDeclStmt(array_decl_stmt)
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterArrayDeclInitStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 7 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LBRACKET &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RBRACKET &&
+				stack_items[4].StackItemType() == TOKEN &&
+				stack_items[4].Token().TokenType() == lexer.ASSIGN &&
+				stack_items[5].StackItemType() == EXPR &&
+				stack_items[6].StackItemType() == TOKEN &&
+				stack_items[6].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		size := stack_items[2].Expr()
+		initializer := stack_items[5].Expr()
+
+		type_specifier_ := param.TypeSpecifier()
+		identifier := param.Identifier()
+
+		array_decl_stmt := new(stmt.ArrayDeclStmt)
+		array_decl_stmt.Init(type_specifier_, identifier, size, initializer)
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitArrayDeclStmt(array_decl_stmt)
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterArrayDeclInitUnsizedStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 6 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LBRACKET &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.RBRACKET &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.ASSIGN &&
+				stack_items[4].StackItemType() == EXPR &&
+				stack_items[5].StackItemType() == TOKEN &&
+				stack_items[5].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		initializer := stack_items[4].Expr()
+
+		type_specifier_ := param.TypeSpecifier()
+		identifier := param.Identifier()
+
+		array_decl_stmt := new(stmt.ArrayDeclStmt)
+		array_decl_stmt.Init(type_specifier_, identifier, nil, initializer)
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitArrayDeclStmt(array_decl_stmt)
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+// NOTE: a declarator that follows a comma is parsed as an expression, e.g. *p = &a is parsed as
+// the assignment of &a to *p, so that Declarator takes the declarator back out of the expression
+// and returns nil if the expression is not a declarator
+func (this *Parser) Declarator(
+	type_specifier_ *type_specifier.TypeSpecifier,
+	declarator *expr.Expr,
+) *stmt.Stmt {
+	base_type := type_specifier_.BaseType()
+
+	var initializer *expr.Expr = nil
+	if declarator.ExprType() == expr.ASSIGNMENT &&
+		declarator.AssignmentExpr().AssignmentExprType() == expr.ASSIGN {
+		initializer = declarator.AssignmentExpr().Rvalue()
+		declarator = declarator.AssignmentExpr().Lvalue()
+	}
+
+	for declarator.ExprType() == expr.UNARY &&
+		declarator.UnaryExpr().UnaryExprType() == expr.STAR {
+		base_type.AddStar()
+		declarator = declarator.UnaryExpr().Base()
+	}
+
+	stmt_ := new(stmt.Stmt)
+
+	if declarator.ExprType() == expr.PRIMARY &&
+		declarator.PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
+		identifier := declarator.PrimaryExpr().Token()
+
+		if initializer == nil {
+			var_decl_stmt := new(stmt.VarDeclStmt)
+			var_decl_stmt.Init(base_type, identifier)
+
+			stmt_.InitVarDeclStmt(var_decl_stmt)
+		} else {
+			var_decl_init_stmt := new(stmt.VarDeclInitStmt)
+			var_decl_init_stmt.Init(base_type, identifier, initializer)
+
+			stmt_.InitVarDeclInitStmt(var_decl_init_stmt)
+		}
+
+		stmt_.SetFirstToken(identifier)
+	} else if declarator.ExprType() == expr.POSTFIX &&
+		declarator.PostfixExpr().PostfixExprType() == expr.BRACKET &&
+		declarator.PostfixExpr().Base().ExprType() == expr.PRIMARY &&
+		declarator.PostfixExpr().Base().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER {
+		identifier := declarator.PostfixExpr().Base().PrimaryExpr().Token()
+		size := declarator.PostfixExpr().OffsetExpr()
+
+		array_decl_stmt := new(stmt.ArrayDeclStmt)
+		array_decl_stmt.Init(base_type, identifier, size, initializer)
+
+		stmt_.InitArrayDeclStmt(array_decl_stmt)
+		stmt_.SetFirstToken(identifier)
+	} else {
+		return nil
+	}
+
+	return stmt_
+}
+
+func (this *Parser) BeginDeclaratorList(first *stmt.Stmt, declarator *expr.Expr) *StackItem {
+	type_specifier_ := this.FirstTypeSpecifier(first)
+
+	decl_list_stmt := new(stmt.DeclListStmt)
+	decl_list_stmt.Init()
+	decl_list_stmt.Append(first)
+	decl_list_stmt.Append(this.Declarator(type_specifier_, declarator))
+
+	stmt_ := new(stmt.Stmt)
+	stmt_.InitDeclListStmt(decl_list_stmt)
+
+	stack_item := new(StackItem)
+	stack_item.InitDeclaratorList(type_specifier_, stmt_)
+
+	return stack_item
+}
+
+func (this *Parser) FirstTypeSpecifier(first *stmt.Stmt) *type_specifier.TypeSpecifier {
+	if first.StmtType() == stmt.VAR_DECL {
+		return first.VarDeclStmt().TypeSpecifier()
+	} else if first.StmtType() == stmt.VAR_DECL_INIT {
+		return first.VarDeclInitStmt().TypeSpecifier()
+	} else if first.StmtType() == stmt.ARRAY_DECL {
+		return first.ArrayDeclStmt().TypeSpecifier()
+	} else {
+		err := errors.New("first declarator's stmt type is not a var decl")
+		panic(err)
+	}
+}
+
+func (this *Parser) RegisterDeclaratorListBegin() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:      true,
+		lexer.LPAREN:        true,
+		lexer.DOT:           true,
+		lexer.ARROW:         true,
+		lexer.STAR:          true,
+		lexer.DIV:           true,
+		lexer.MOD:           true,
+		lexer.PLUS:          true,
+		lexer.MINUS:         true,
+		lexer.LSHIFT:        true,
+		lexer.RSHIFT:        true,
+		lexer.LESS:          true,
+		lexer.LESS_EQ:       true,
+		lexer.GREATER:       true,
+		lexer.GREATER_EQ:    true,
+		lexer.EQ:            true,
+		lexer.NOT_EQ:        true,
+		lexer.AND:           true,
+		lexer.CARET:         true,
+		lexer.OR:            true,
+		lexer.AND_AND:       true,
+		lexer.OR_OR:         true,
+		lexer.QUESTION:      true,
+		lexer.ASSIGN:        true,
+		lexer.STAR_ASSIGN:   true,
+		lexer.DIV_ASSIGN:    true,
+		lexer.MOD_ASSIGN:    true,
+		lexer.PLUS_ASSIGN:   true,
+		lexer.MINUS_ASSIGN:  true,
+		lexer.LSHIFT_ASSIGN: true,
+		lexer.RSHIFT_ASSIGN: true,
+		lexer.AND_ASSIGN:    true,
+		lexer.CARET_ASSIGN:  true,
+		lexer.OR_ASSIGN:     true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.COMMA &&
+				stack_items[2].StackItemType() == EXPR {
+				type_specifier_ := stack_items[0].ParamList().Get(0).TypeSpecifier()
+				return this.Declarator(type_specifier_, stack_items[2].Expr()) != nil
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		declarator := stack_items[2].Expr()
+
+		var_decl_stmt := new(stmt.VarDeclStmt)
+		var_decl_stmt.Init(param.TypeSpecifier(), param.Identifier())
+
+		first := new(stmt.Stmt)
+		first.InitVarDeclStmt(var_decl_stmt)
+		first.SetFirstToken(stack_items[0].FirstToken())
+
+		return this.BeginDeclaratorList(first, declarator)
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterDeclaratorListBeginInit() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:      true,
+		lexer.LPAREN:        true,
+		lexer.DOT:           true,
+		lexer.ARROW:         true,
+		lexer.STAR:          true,
+		lexer.DIV:           true,
+		lexer.MOD:           true,
+		lexer.PLUS:          true,
+		lexer.MINUS:         true,
+		lexer.LSHIFT:        true,
+		lexer.RSHIFT:        true,
+		lexer.LESS:          true,
+		lexer.LESS_EQ:       true,
+		lexer.GREATER:       true,
+		lexer.GREATER_EQ:    true,
+		lexer.EQ:            true,
+		lexer.NOT_EQ:        true,
+		lexer.AND:           true,
+		lexer.CARET:         true,
+		lexer.OR:            true,
+		lexer.AND_AND:       true,
+		lexer.OR_OR:         true,
+		lexer.QUESTION:      true,
+		lexer.ASSIGN:        true,
+		lexer.STAR_ASSIGN:   true,
+		lexer.DIV_ASSIGN:    true,
+		lexer.MOD_ASSIGN:    true,
+		lexer.PLUS_ASSIGN:   true,
+		lexer.MINUS_ASSIGN:  true,
+		lexer.LSHIFT_ASSIGN: true,
+		lexer.RSHIFT_ASSIGN: true,
+		lexer.AND_ASSIGN:    true,
+		lexer.CARET_ASSIGN:  true,
+		lexer.OR_ASSIGN:     true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 5 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.ASSIGN &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.COMMA &&
+				stack_items[4].StackItemType() == EXPR {
+				type_specifier_ := stack_items[0].ParamList().Get(0).TypeSpecifier()
+				return this.Declarator(type_specifier_, stack_items[4].Expr()) != nil
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		rvalue := stack_items[2].Expr()
+		declarator := stack_items[4].Expr()
+
+		var_decl_init_stmt := new(stmt.VarDeclInitStmt)
+		var_decl_init_stmt.Init(param.TypeSpecifier(), param.Identifier(), rvalue)
+
+		first := new(stmt.Stmt)
+		first.InitVarDeclInitStmt(var_decl_init_stmt)
+		first.SetFirstToken(stack_items[0].FirstToken())
+
+		return this.BeginDeclaratorList(first, declarator)
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterDeclaratorListBeginArray() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:      true,
+		lexer.LPAREN:        true,
+		lexer.DOT:           true,
+		lexer.ARROW:         true,
+		lexer.STAR:          true,
+		lexer.DIV:           true,
+		lexer.MOD:           true,
+		lexer.PLUS:          true,
+		lexer.MINUS:         true,
+		lexer.LSHIFT:        true,
+		lexer.RSHIFT:        true,
+		lexer.LESS:          true,
+		lexer.LESS_EQ:       true,
+		lexer.GREATER:       true,
+		lexer.GREATER_EQ:    true,
+		lexer.EQ:            true,
+		lexer.NOT_EQ:        true,
+		lexer.AND:           true,
+		lexer.CARET:         true,
+		lexer.OR:            true,
+		lexer.AND_AND:       true,
+		lexer.OR_OR:         true,
+		lexer.QUESTION:      true,
+		lexer.ASSIGN:        true,
+		lexer.STAR_ASSIGN:   true,
+		lexer.DIV_ASSIGN:    true,
+		lexer.MOD_ASSIGN:    true,
+		lexer.PLUS_ASSIGN:   true,
+		lexer.MINUS_ASSIGN:  true,
+		lexer.LSHIFT_ASSIGN: true,
+		lexer.RSHIFT_ASSIGN: true,
+		lexer.AND_ASSIGN:    true,
+		lexer.CARET_ASSIGN:  true,
+		lexer.OR_ASSIGN:     true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 6 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == PARAM_LIST &&
+				stack_items[0].ParamList().Length() == 1 &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LBRACKET &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RBRACKET &&
+				stack_items[4].StackItemType() == TOKEN &&
+				stack_items[4].Token().TokenType() == lexer.COMMA &&
+				stack_items[5].StackItemType() == EXPR {
+				type_specifier_ := stack_items[0].ParamList().Get(0).TypeSpecifier()
+				return this.Declarator(type_specifier_, stack_items[5].Expr()) != nil
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		param := stack_items[0].ParamList().Get(0)
+		size := stack_items[2].Expr()
+		declarator := stack_items[5].Expr()
+
+		array_decl_stmt := new(stmt.ArrayDeclStmt)
+		array_decl_stmt.Init(param.TypeSpecifier(), param.Identifier(), size, nil)
+
+		first := new(stmt.Stmt)
+		first.InitArrayDeclStmt(array_decl_stmt)
+		first.SetFirstToken(stack_items[0].FirstToken())
+
+		return this.BeginDeclaratorList(first, declarator)
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterDeclaratorListAppend() {
+	precedence := map[lexer.TokenType]bool{
+		lexer.LBRACKET:      true,
+		lexer.LPAREN:        true,
+		lexer.DOT:           true,
+		lexer.ARROW:         true,
+		lexer.STAR:          true,
+		lexer.DIV:           true,
+		lexer.MOD:           true,
+		lexer.PLUS:          true,
+		lexer.MINUS:         true,
+		lexer.LSHIFT:        true,
+		lexer.RSHIFT:        true,
+		lexer.LESS:          true,
+		lexer.LESS_EQ:       true,
+		lexer.GREATER:       true,
+		lexer.GREATER_EQ:    true,
+		lexer.EQ:            true,
+		lexer.NOT_EQ:        true,
+		lexer.AND:           true,
+		lexer.CARET:         true,
+		lexer.OR:            true,
+		lexer.AND_AND:       true,
+		lexer.OR_OR:         true,
+		lexer.QUESTION:      true,
+		lexer.ASSIGN:        true,
+		lexer.STAR_ASSIGN:   true,
+		lexer.DIV_ASSIGN:    true,
+		lexer.MOD_ASSIGN:    true,
+		lexer.PLUS_ASSIGN:   true,
+		lexer.MINUS_ASSIGN:  true,
+		lexer.LSHIFT_ASSIGN: true,
+		lexer.RSHIFT_ASSIGN: true,
+		lexer.AND_ASSIGN:    true,
+		lexer.CARET_ASSIGN:  true,
+		lexer.OR_ASSIGN:     true,
+	}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 3 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == DECLARATOR_LIST &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.COMMA &&
+				stack_items[2].StackItemType() == EXPR {
+				type_specifier_ := stack_items[0].TypeSpecifier()
+				return this.Declarator(type_specifier_, stack_items[2].Expr()) != nil
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		type_specifier_ := stack_items[0].TypeSpecifier()
+		stmt_ := stack_items[0].Stmt()
+		declarator := stack_items[2].Expr()
+
+		stmt_.DeclListStmt().Append(this.Declarator(type_specifier_, declarator))
+
+		stack_item := new(StackItem)
+		stack_item.InitDeclaratorList(type_specifier_, stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+func (this *Parser) RegisterDeclListStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 2 {
+			return false
+		} else {
+			if stack_items[0].StackItemType() == DECLARATOR_LIST &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.SEMI {
+				return true
+			} else {
+				return false
+			}
+		}
+	}
+
+	reduce := func(stack_items []*StackItem) *StackItem {
+		stmt_ := stack_items[0].Stmt()
+
+		stack_item := new(StackItem)
+		stack_item.InitStmt(stmt_)
+
+		return stack_item
+	}
+
+	rule := new(Rule)
+	rule.Init(precedence, reducible, reduce)
+
+	this.table.AddRule(rule)
+}
+
+// NOTE: a body that is not a block is wrapped into a block of its own, so that every body of a
+// stmt is a block to the checker and the codegen
+func (this *Parser) Body(body *stmt.Stmt) *stmt.Stmt {
+	if body.StmtType() == stmt.BLOCK {
+		return body
+	}
+
+	block_stmt := new(stmt.BlockStmt)
+	block_stmt.Init()
+	block_stmt.Append(body)
+
+	stmt_ := new(stmt.Stmt)
+	stmt_.InitBlockStmt(block_stmt)
+	stmt_.SetFirstToken(body.FirstToken())
+
+	return stmt_
+}
+
+func (this *Parser) RegisterForStmt() {
+	precedence := map[lexer.TokenType]bool{}
+
+	reducible := func(stack_items []*StackItem) bool {
+		if len(stack_items) != 7 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.FOR &&
 				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.OR_ASSIGN &&
-				stack_items[2].StackItemType() == EXPR {
+				stack_items[1].Token().TokenType() == lexer.LPAREN &&
+				stack_items[2].StackItemType() == STMT &&
+				stack_items[3].StackItemType() == STMT &&
+				stack_items[4].StackItemType() == EXPR &&
+				stack_items[5].StackItemType() == TOKEN &&
+				stack_items[5].Token().TokenType() == lexer.RPAREN &&
+				stack_items[6].StackItemType() == STMT {
 				return true
 			} else {
 				return false
@@ -3275,17 +4782,32 @@ func (this *Parser) RegisterAssignmentExprOrAssign() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[2].Expr()
+		initialization := stack_items[2].Stmt()
+		condition_stmt := stack_items[3].Stmt()
+		update_expr := stack_items[4].Expr()
+		body := this.Body(stack_items[6].Stmt())
 
-		assignment_expr := new(expr.AssignmentExpr)
-		assignment_expr.Init(expr.OR_ASSIGN, lvalue, rvalue)
+		if condition_stmt.StmtType() != stmt.EXPR {
+			err := errors.New("condition stmt type is not expr")
+			panic(err)
+		}
 
-		expr_ := new(expr.Expr)
-		expr_.InitAssignmentExpr(assignment_expr)
+		condition_expr := condition_stmt.ExprStmt().Expr()
+
+		u := new(stmt.ExprStmt)
+		u.Init(update_expr)
+
+		update_stmt := new(stmt.Stmt)
+		update_stmt.InitExprStmt(u)
+
+		for_stmt := new(stmt.ForStmt)
+		for_stmt.Init(initialization, condition_expr, update_stmt, body)
+
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitForStmt(for_stmt)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitStmt(stmt_)
 
 		return stack_item
 	}
@@ -3296,25 +4818,24 @@ func (this *Parser) RegisterAssignmentExprOrAssign() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterConcatExpr() {
-	precedence := map[lexer.TokenType]bool{
-		lexer.LBRACKET:    true,
-		lexer.LPAREN:      true,
-		lexer.DOT:         true,
-		lexer.ARROW:       true,
-		lexer.PLUS_PLUS:   true,
-		lexer.MINUS_MINUS: true,
-		lexer.STAR:        true,
-		lexer.DIV:         true,
-		lexer.MOD:         true,
-	}
+func (this *Parser) RegisterDpuForeachStmt() {
+	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 2 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[1].StackItemType() == EXPR {
+				stack_items[0].Expr().ExprType() == expr.POSTFIX &&
+				stack_items[0].Expr().PostfixExpr().PostfixExprType() == expr.CALL &&
+				stack_items[0].Expr().PostfixExpr().Base().ExprType() == expr.PRIMARY &&
+				stack_items[0].Expr().PostfixExpr().Base().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER &&
+				(stack_items[0].Expr().PostfixExpr().Base().PrimaryExpr().Token().Attribute() == "DPU_FOREACH" ||
+					stack_items[0].Expr().PostfixExpr().Base().PrimaryExpr().Token().Attribute() == "DPU_RANK_FOREACH") &&
+				(stack_items[0].Expr().PostfixExpr().ArgList().Length() == 2 ||
+					stack_items[0].Expr().PostfixExpr().ArgList().Length() == 3) &&
+				stack_items[1].StackItemType() == STMT &&
+				stack_items[1].Stmt().StmtType() == stmt.BLOCK {
 				return true
 			} else {
 				return false
@@ -3323,17 +4844,18 @@ func (this *Parser) RegisterConcatExpr() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		lvalue := stack_items[0].Expr()
-		rvalue := stack_items[1].Expr()
+		foreach := stack_items[0].Expr().PostfixExpr().ArgList()
+		body := stack_items[1].Stmt()
+		is_rank := stack_items[0].Expr().PostfixExpr().Base().PrimaryExpr().Token().Attribute() == "DPU_RANK_FOREACH"
 
-		additive_expr := new(expr.AdditiveExpr)
-		additive_expr.Init(expr.ADD, lvalue, rvalue)
+		dpu_foreach_stmt := new(stmt.DpuForeachStmt)
+		dpu_foreach_stmt.Init(foreach, body, is_rank)
 
-		expr_ := new(expr.Expr)
-		expr_.InitAdditiveExpr(additive_expr)
+		stmt_ := new(stmt.Stmt)
+		stmt_.InitDpuForeachStmt(dpu_foreach_stmt)
 
 		stack_item := new(StackItem)
-		stack_item.InitExpr(expr_)
+		stack_item.InitStmt(stmt_)
 
 		return stack_item
 	}
@@ -3344,15 +4866,22 @@ func (this *Parser) RegisterConcatExpr() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterEmptyStmt() {
+func (this *Parser) RegisterCondition() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 1 {
+		if len(stack_items) != 4 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.SEMI {
+				(stack_items[0].Token().TokenType() == lexer.IF ||
+					stack_items[0].Token().TokenType() == lexer.WHILE ||
+					stack_items[0].Token().TokenType() == lexer.SWITCH) &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.LPAREN &&
+				stack_items[2].StackItemType() == EXPR &&
+				stack_items[3].StackItemType() == TOKEN &&
+				stack_items[3].Token().TokenType() == lexer.RPAREN {
 				return true
 			} else {
 				return false
@@ -3361,14 +4890,11 @@ func (this *Parser) RegisterEmptyStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		empty_stmt := new(stmt.EmptyStmt)
-		empty_stmt.Init()
-
-		stmt_ := new(stmt.Stmt)
-		stmt_.InitEmptyStmt(empty_stmt)
+		token := stack_items[0].Token()
+		condition := stack_items[2].Expr()
 
 		stack_item := new(StackItem)
-		stack_item.InitStmt(stmt_)
+		stack_item.InitCondition(token, condition)
 
 		return stack_item
 	}
@@ -3379,17 +4905,16 @@ func (this *Parser) RegisterEmptyStmt() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterVarDeclStmt() {
+func (this *Parser) RegisterWhileStmt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == PARAM_LIST &&
-				stack_items[0].ParamList().Length() == 1 &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.SEMI {
+			if stack_items[0].StackItemType() == CONDITION &&
+				stack_items[0].Token().TokenType() == lexer.WHILE &&
+				stack_items[1].StackItemType() == STMT {
 				return true
 			} else {
 				return false
@@ -3398,16 +4923,14 @@ func (this *Parser) RegisterVarDeclStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		param := stack_items[0].ParamList().Get(0)
-
-		type_specifier_ := param.TypeSpecifier()
-		identifier := param.Identifier()
+		condition := stack_items[0].Condition()
+		body := this.Body(stack_items[1].Stmt())
 
-		var_decl_stmt := new(stmt.VarDeclStmt)
-		var_decl_stmt.Init(type_specifier_, identifier)
+		while_stmt := new(stmt.WhileStmt)
+		while_stmt.Init(condition, body)
 
 		stmt_ := new(stmt.Stmt)
-		stmt_.InitVarDeclStmt(var_decl_stmt)
+		stmt_.InitWhileStmt(while_stmt)
 
 		stack_item := new(StackItem)
 		stack_item.InitStmt(stmt_)
@@ -3421,18 +4944,18 @@ func (this *Parser) RegisterVarDeclStmt() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterVarDeclInitStmt() {
+func (this *Parser) RegisterDoWhileStmt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
 		if len(stack_items) != 4 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == PARAM_LIST &&
-				stack_items[0].ParamList().Length() == 1 &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.ASSIGN &&
-				stack_items[2].StackItemType() == EXPR &&
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.DO &&
+				stack_items[1].StackItemType() == STMT &&
+				stack_items[2].StackItemType() == CONDITION &&
+				stack_items[2].Token().TokenType() == lexer.WHILE &&
 				stack_items[3].StackItemType() == TOKEN &&
 				stack_items[3].Token().TokenType() == lexer.SEMI {
 				return true
@@ -3443,17 +4966,14 @@ func (this *Parser) RegisterVarDeclInitStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		param := stack_items[0].ParamList().Get(0)
-		rvalue := stack_items[2].Expr()
-
-		type_specifier_ := param.TypeSpecifier()
-		identifier := param.Identifier()
+		body := this.Body(stack_items[1].Stmt())
+		condition := stack_items[2].Condition()
 
-		var_decl_init_stmt := new(stmt.VarDeclInitStmt)
-		var_decl_init_stmt.Init(type_specifier_, identifier, rvalue)
+		do_while_stmt := new(stmt.DoWhileStmt)
+		do_while_stmt.Init(body, condition)
 
 		stmt_ := new(stmt.Stmt)
-		stmt_.InitVarDeclInitStmt(var_decl_init_stmt)
+		stmt_.InitDoWhileStmt(do_while_stmt)
 
 		stack_item := new(StackItem)
 		stack_item.InitStmt(stmt_)
@@ -3467,24 +4987,17 @@ func (this *Parser) RegisterVarDeclInitStmt() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterForStmt() {
+func (this *Parser) RegisterSwitchStmt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 7 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.FOR &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.LPAREN &&
-				stack_items[2].StackItemType() == STMT &&
-				stack_items[3].StackItemType() == STMT &&
-				stack_items[4].StackItemType() == EXPR &&
-				stack_items[5].StackItemType() == TOKEN &&
-				stack_items[5].Token().TokenType() == lexer.RPAREN &&
-				stack_items[6].StackItemType() == STMT &&
-				stack_items[6].Stmt().StmtType() == stmt.BLOCK {
+			if stack_items[0].StackItemType() == CONDITION &&
+				stack_items[0].Token().TokenType() == lexer.SWITCH &&
+				stack_items[1].StackItemType() == STMT &&
+				stack_items[1].Stmt().StmtType() == stmt.BLOCK {
 				return true
 			} else {
 				return false
@@ -3493,29 +5006,14 @@ func (this *Parser) RegisterForStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		initialization := stack_items[2].Stmt()
-		condition_stmt := stack_items[3].Stmt()
-		update_expr := stack_items[4].Expr()
-		body := stack_items[6].Stmt()
-
-		if condition_stmt.StmtType() != stmt.EXPR {
-			err := errors.New("condition stmt type is not expr")
-			panic(err)
-		}
-
-		condition_expr := condition_stmt.ExprStmt().Expr()
-
-		u := new(stmt.ExprStmt)
-		u.Init(update_expr)
-
-		update_stmt := new(stmt.Stmt)
-		update_stmt.InitExprStmt(u)
+		condition := stack_items[0].Condition()
+		body := stack_items[1].Stmt()
 
-		for_stmt := new(stmt.ForStmt)
-		for_stmt.Init(initialization, condition_expr, update_stmt, body)
+		switch_stmt := new(stmt.SwitchStmt)
+		switch_stmt.Init(condition, body)
 
 		stmt_ := new(stmt.Stmt)
-		stmt_.InitForStmt(for_stmt)
+		stmt_.InitSwitchStmt(switch_stmt)
 
 		stack_item := new(StackItem)
 		stack_item.InitStmt(stmt_)
@@ -3529,22 +5027,18 @@ func (this *Parser) RegisterForStmt() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterDpuForeachStmt() {
+func (this *Parser) RegisterCaseStmt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 2 {
+		if len(stack_items) != 3 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[0].Expr().ExprType() == expr.POSTFIX &&
-				stack_items[0].Expr().PostfixExpr().PostfixExprType() == expr.CALL &&
-				stack_items[0].Expr().PostfixExpr().Base().ExprType() == expr.PRIMARY &&
-				stack_items[0].Expr().PostfixExpr().Base().PrimaryExpr().PrimaryExprType() == expr.IDENTIFIER &&
-				stack_items[0].Expr().PostfixExpr().Base().PrimaryExpr().Token().Attribute() == "DPU_FOREACH" &&
-				stack_items[0].Expr().PostfixExpr().ArgList().Length() == 3 &&
-				stack_items[1].StackItemType() == STMT &&
-				stack_items[1].Stmt().StmtType() == stmt.BLOCK {
+			if stack_items[0].StackItemType() == TOKEN &&
+				stack_items[0].Token().TokenType() == lexer.CASE &&
+				stack_items[1].StackItemType() == EXPR &&
+				stack_items[2].StackItemType() == TOKEN &&
+				stack_items[2].Token().TokenType() == lexer.COLON {
 				return true
 			} else {
 				return false
@@ -3553,14 +5047,13 @@ func (this *Parser) RegisterDpuForeachStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		foreach := stack_items[0].Expr().PostfixExpr().ArgList()
-		body := stack_items[1].Stmt()
+		value := stack_items[1].Expr()
 
-		dpu_foreach_stmt := new(stmt.DpuForeachStmt)
-		dpu_foreach_stmt.Init(foreach, body)
+		case_stmt := new(stmt.CaseStmt)
+		case_stmt.Init(value)
 
 		stmt_ := new(stmt.Stmt)
-		stmt_.InitDpuForeachStmt(dpu_foreach_stmt)
+		stmt_.InitCaseStmt(case_stmt)
 
 		stack_item := new(StackItem)
 		stack_item.InitStmt(stmt_)
@@ -3574,20 +5067,17 @@ func (this *Parser) RegisterDpuForeachStmt() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterWhileStmt() {
+func (this *Parser) RegisterDefaultStmt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == TOKEN &&
-				stack_items[0].Token().TokenType() == lexer.WHILE &&
-				stack_items[1].StackItemType() == EXPR &&
-				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.PAREN &&
-				stack_items[2].StackItemType() == STMT &&
-				stack_items[2].Stmt().StmtType() == stmt.BLOCK {
+				stack_items[0].Token().TokenType() == lexer.DEFAULT &&
+				stack_items[1].StackItemType() == TOKEN &&
+				stack_items[1].Token().TokenType() == lexer.COLON {
 				return true
 			} else {
 				return false
@@ -3596,14 +5086,11 @@ func (this *Parser) RegisterWhileStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		condition := stack_items[1].Expr().PrimaryExpr().Expr()
-		body := stack_items[2].Stmt()
-
-		while_stmt := new(stmt.WhileStmt)
-		while_stmt.Init(condition, body)
+		default_stmt := new(stmt.DefaultStmt)
+		default_stmt.Init()
 
 		stmt_ := new(stmt.Stmt)
-		stmt_.InitWhileStmt(while_stmt)
+		stmt_.InitDefaultStmt(default_stmt)
 
 		stack_item := new(StackItem)
 		stack_item.InitStmt(stmt_)
@@ -3691,20 +5178,20 @@ func (this *Parser) RegisterBreakStmt() {
 	this.table.AddRule(rule)
 }
 
+// NOTE: an if does not take its body before the token that follows it, since an else that follows
+// the body belongs to the if, e.g. the else of if (a) if (b) x; else y; belongs to if (b)
 func (this *Parser) RegisterIfStmt() {
-	precedence := map[lexer.TokenType]bool{}
+	precedence := map[lexer.TokenType]bool{
+		lexer.ELSE: true,
+	}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
+		if len(stack_items) != 2 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == TOKEN &&
+			if stack_items[0].StackItemType() == CONDITION &&
 				stack_items[0].Token().TokenType() == lexer.IF &&
-				stack_items[1].StackItemType() == EXPR &&
-				stack_items[1].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[1].Expr().PrimaryExpr().PrimaryExprType() == expr.PAREN &&
-				stack_items[2].StackItemType() == STMT &&
-				stack_items[2].Stmt().StmtType() == stmt.BLOCK {
+				stack_items[1].StackItemType() == STMT {
 				return true
 			} else {
 				return false
@@ -3713,8 +5200,8 @@ func (this *Parser) RegisterIfStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		condition := stack_items[1].Expr().PrimaryExpr().Expr()
-		body := stack_items[2].Stmt()
+		condition := stack_items[0].Condition()
+		body := this.Body(stack_items[1].Stmt())
 
 		if_stmt := new(stmt.IfStmt)
 		if_stmt.Init(condition, body)
@@ -3734,24 +5221,20 @@ func (this *Parser) RegisterIfStmt() {
 	this.table.AddRule(rule)
 }
 
-func (this *Parser) RegisterElseIfStmt() {
+// NOTE: an else if is an if in the else body, which is flattened into the else ifs of the outer if
+func (this *Parser) RegisterIfElseStmt() {
 	precedence := map[lexer.TokenType]bool{}
 
 	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 5 {
+		if len(stack_items) != 4 {
 			return false
 		} else {
-			if stack_items[0].StackItemType() == STMT &&
-				stack_items[0].Stmt().StmtType() == stmt.IF &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.ELSE &&
+			if stack_items[0].StackItemType() == CONDITION &&
+				stack_items[0].Token().TokenType() == lexer.IF &&
+				stack_items[1].StackItemType() == STMT &&
 				stack_items[2].StackItemType() == TOKEN &&
-				stack_items[2].Token().TokenType() == lexer.IF &&
-				stack_items[3].StackItemType() == EXPR &&
-				stack_items[3].Expr().ExprType() == expr.PRIMARY &&
-				stack_items[3].Expr().PrimaryExpr().PrimaryExprType() == expr.PAREN &&
-				stack_items[4].StackItemType() == STMT &&
-				stack_items[4].Stmt().StmtType() == stmt.BLOCK {
+				stack_items[2].Token().TokenType() == lexer.ELSE &&
+				stack_items[3].StackItemType() == STMT {
 				return true
 			} else {
 				return false
@@ -3760,52 +5243,27 @@ func (this *Parser) RegisterElseIfStmt() {
 	}
 
 	reduce := func(stack_items []*StackItem) *StackItem {
-		if_stmt := stack_items[0].Stmt().IfStmt()
-		condition := stack_items[3].Expr().PrimaryExpr().Expr()
-		body := stack_items[4].Stmt()
-
-		if_stmt.AppendElseIf(condition, body)
-
-		stmt_ := new(stmt.Stmt)
-		stmt_.InitIfStmt(if_stmt)
-
-		stack_item := new(StackItem)
-		stack_item.InitStmt(stmt_)
-
-		return stack_item
-	}
+		condition := stack_items[0].Condition()
+		body := this.Body(stack_items[1].Stmt())
+		else_body := stack_items[3].Stmt()
 
-	rule := new(Rule)
-	rule.Init(precedence, reducible, reduce)
+		if_stmt := new(stmt.IfStmt)
+		if_stmt.Init(condition, body)
 
-	this.table.AddRule(rule)
-}
+		if else_body.StmtType() == stmt.IF {
+			else_if_stmt := else_body.IfStmt()
 
-func (this *Parser) RegisterElseStmt() {
-	precedence := map[lexer.TokenType]bool{}
+			if_stmt.AppendElseIf(else_if_stmt.IfCondition(), else_if_stmt.IfBody())
+			for i := 0; i < else_if_stmt.NumElseIfs(); i++ {
+				if_stmt.AppendElseIf(else_if_stmt.ElseIfCondition(i), else_if_stmt.ElseIfBody(i))
+			}
 
-	reducible := func(stack_items []*StackItem) bool {
-		if len(stack_items) != 3 {
-			return false
-		} else {
-			if stack_items[0].StackItemType() == STMT &&
-				stack_items[0].Stmt().StmtType() == stmt.IF &&
-				stack_items[1].StackItemType() == TOKEN &&
-				stack_items[1].Token().TokenType() == lexer.ELSE &&
-				stack_items[2].StackItemType() == STMT &&
-				stack_items[2].Stmt().StmtType() == stmt.BLOCK {
-				return true
-			} else {
-				return false
+			if else_if_stmt.HasElseBody() {
+				if_stmt.SetElseBody(else_if_stmt.ElseBody())
 			}
+		} else {
+			if_stmt.SetElseBody(this.Body(else_body))
 		}
-	}
-
-	reduce := func(stack_items []*StackItem) *StackItem {
-		if_stmt := stack_items[0].Stmt().IfStmt()
-		body := stack_items[2].Stmt()
-
-		if_stmt.SetElseBody(body)
 
 		stmt_ := new(stmt.Stmt)
 		stmt_.InitIfStmt(if_stmt)
@@ -3997,7 +5455,13 @@ func (this *Parser) RegisterStructDef() {
 				for i := 0; i < body.Length(); i++ {
 					stmt_ := body.Get(i)
 
-					if stmt_.StmtType() != stmt.VAR_DECL {
+					if stmt_.StmtType() == stmt.DECL_LIST {
+						for j := 0; j < stmt_.DeclListStmt().Length(); j++ {
+							if !this.IsField(stmt_.DeclListStmt().Get(j)) {
+								return false
+							}
+						}
+					} else if !this.IsField(stmt_) {
 						return false
 					}
 				}
@@ -4008,9 +5472,29 @@ func (this *Parser) RegisterStructDef() {
 		}
 	}
 
+	// NOTE: the fields of a decl list are flattened into the body, so that a field is a var decl or
+	// an array decl
 	reduce := func(stack_items []*StackItem) *StackItem {
 		type_specifier_ := stack_items[0].TypeSpecifier()
-		body := stack_items[1].Stmt()
+
+		block_stmt := new(stmt.BlockStmt)
+		block_stmt.Init()
+
+		for i := 0; i < stack_items[1].Stmt().BlockStmt().Length(); i++ {
+			stmt_ := stack_items[1].Stmt().BlockStmt().Get(i)
+
+			if stmt_.StmtType() == stmt.DECL_LIST {
+				for j := 0; j < stmt_.DeclListStmt().Length(); j++ {
+					block_stmt.Append(stmt_.DeclListStmt().Get(j))
+				}
+			} else {
+				block_stmt.Append(stmt_)
+			}
+		}
+
+		body := new(stmt.Stmt)
+		body.InitBlockStmt(block_stmt)
+		body.SetFirstToken(stack_items[1].Stmt().FirstToken())
 
 		identifier := type_specifier_.StructIdentifier()
 
@@ -4032,6 +5516,17 @@ func (this *Parser) RegisterStructDef() {
 	this.table.AddRule(rule)
 }
 
+// NOTE: a field is either a var decl or an array decl with a size and no initializer
+func (this *Parser) IsField(stmt_ *stmt.Stmt) bool {
+	if stmt_.StmtType() == stmt.VAR_DECL {
+		return true
+	} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+		return stmt_.ArrayDeclStmt().HasSize() && !stmt_.ArrayDeclStmt().HasInitializer()
+	} else {
+		return false
+	}
+}
+
 func (this *Parser) RegisterFuncDeclEmpty() {
 	precedence := map[lexer.TokenType]bool{}
 
@@ -0,0 +1,325 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"uPIMulator/src/host/interpreter/lexer"
+	"uPIMulator/src/host/interpreter/parser/expr"
+	"uPIMulator/src/host/interpreter/parser/stmt"
+)
+
+func writeSource(t *testing.T, source string) string {
+	path := filepath.Join(t.TempDir(), "app.c")
+	if err := os.WriteFile(path, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func parseFile(path string) (*Parser, *Ast) {
+	lexer_ := new(lexer.Lexer)
+	lexer_.Init()
+	lexer_.Predefine("NR_DPUS", "64")
+	lexer_.Predefine("NR_TASKLETS", "16")
+
+	parser_ := new(Parser)
+	parser_.Init()
+
+	return parser_, parser_.Parse(lexer_.Lex(path))
+}
+
+func parseSource(t *testing.T, source string) *Ast {
+	_, ast := parseFile(writeSource(t, source))
+	return ast
+}
+
+func syntaxError(t *testing.T, source string) (message string) {
+	path := writeSource(t, source)
+
+	defer func() {
+		if err := recover(); err != nil {
+			message = strings.TrimPrefix(err.(error).Error(), path+":")
+		}
+	}()
+
+	parseFile(path)
+	return ""
+}
+
+// NOTE: the body of main is the last decl of a test source
+func parseMain(t *testing.T, source string) *stmt.BlockStmt {
+	ast := parseSource(t, source)
+	return ast.Get(ast.Length() - 1).Decl().FuncDef().Body().BlockStmt()
+}
+
+func TestParseNonBlockBodies(t *testing.T) {
+	body := parseMain(t, `
+		int main() {
+			int i;
+			if (i) i++;
+			while (i) i--;
+			do i++; while (i < 3);
+			for (i = 0; i < 3; i++) i += 2;
+			if (i) ++i;
+			if (i) *&i = 1;
+			return 0;
+		}
+	`)
+
+	if body.Length() != 8 {
+		t.Fatalf("main has %d stmts", body.Length())
+	}
+
+	if_stmt := body.Get(1).IfStmt()
+	if if_stmt == nil || if_stmt.IfBody().StmtType() != stmt.BLOCK ||
+		if_stmt.IfBody().BlockStmt().Get(0).StmtType() != stmt.EXPR {
+		t.Errorf("if is not parsed with a non-block body")
+	}
+
+	while_stmt := body.Get(2).WhileStmt()
+	if while_stmt == nil || while_stmt.Body().BlockStmt().Length() != 1 {
+		t.Errorf("while is not parsed with a non-block body")
+	}
+
+	do_while_stmt := body.Get(3).DoWhileStmt()
+	if do_while_stmt == nil || do_while_stmt.Body().BlockStmt().Length() != 1 {
+		t.Errorf("do while is not parsed with a non-block body")
+	}
+
+	for_stmt := body.Get(4).ForStmt()
+	if for_stmt == nil || for_stmt.Body().BlockStmt().Length() != 1 {
+		t.Errorf("for is not parsed with a non-block body")
+	}
+
+	for _, pos := range []int{5, 6} {
+		if body.Get(pos).StmtType() != stmt.IF ||
+			body.Get(pos).IfStmt().IfCondition().ExprType() != expr.PRIMARY {
+			t.Errorf("body of if at %d is taken as an operand of its condition", pos)
+		}
+	}
+}
+
+func TestParseElse(t *testing.T) {
+	body := parseMain(t, `
+		int main() {
+			int i;
+			if (i == 0) i = 1;
+			else if (i == 1) { i = 2; }
+			else if (i == 2) i = 3;
+			else i = 4;
+			if (i) if (i > 1) i = 5; else i = 6;
+			return 0;
+		}
+	`)
+
+	chain := body.Get(1).IfStmt()
+	if chain.NumElseIfs() != 2 || !chain.HasElseBody() ||
+		chain.ElseBody().StmtType() != stmt.BLOCK {
+		t.Errorf("else if chain has %d else ifs", chain.NumElseIfs())
+	}
+
+	// NOTE: the else belongs to the inner if
+	outer := body.Get(2).IfStmt()
+	if outer.HasElseBody() {
+		t.Fatalf("else belongs to the outer if")
+	}
+
+	inner := outer.IfBody().BlockStmt().Get(0)
+	if inner.StmtType() != stmt.IF || !inner.IfStmt().HasElseBody() {
+		t.Errorf("else does not belong to the inner if")
+	}
+}
+
+func TestParseDeclarators(t *testing.T) {
+	ast := parseSource(t, `
+		struct point {
+			int x, y;
+		};
+
+		int g1, *g2 = 0;
+
+		int main() {
+			struct point p, *q = &p;
+			int *a, b, c[4], d = 1, *e = &d;
+			for (int i = 0, j = 1; i < j; i++) {
+			}
+			return 0;
+		}
+	`)
+
+	fields := ast.Get(0).Decl().StructDef().Body().BlockStmt()
+	if fields.Length() != 2 || fields.Get(1).VarDeclStmt().Identifier().Attribute() != "y" {
+		t.Errorf("fields of a struct are not flattened")
+	}
+
+	globals := ast.Get(1).Stmt()
+	if globals.StmtType() != stmt.DECL_LIST || globals.DeclListStmt().Length() != 2 {
+		t.Fatalf("global declarators are not parsed")
+	}
+	if globals.DeclListStmt().Get(1).VarDeclInitStmt().TypeSpecifier().NumStars() != 1 {
+		t.Errorf("*g2 is not a pointer")
+	}
+
+	body := ast.Get(2).Decl().FuncDef().Body().BlockStmt()
+
+	structs := body.Get(0).DeclListStmt()
+	if structs.Length() != 2 ||
+		structs.Get(0).VarDeclStmt().TypeSpecifier().NumStars() != 0 ||
+		structs.Get(1).VarDeclInitStmt().TypeSpecifier().NumStars() != 1 {
+		t.Errorf("struct declarators are not parsed")
+	}
+
+	decl_list_stmt := body.Get(1).DeclListStmt()
+	if decl_list_stmt == nil || decl_list_stmt.Length() != 5 {
+		t.Fatalf("declarators are not parsed")
+	}
+
+	expected_types := []stmt.StmtType{
+		stmt.VAR_DECL,
+		stmt.VAR_DECL,
+		stmt.ARRAY_DECL,
+		stmt.VAR_DECL_INIT,
+		stmt.VAR_DECL_INIT,
+	}
+	expected_stars := []int{1, 0, 0, 0, 1}
+
+	for i := 0; i < decl_list_stmt.Length(); i++ {
+		stmt_ := decl_list_stmt.Get(i)
+
+		num_stars := 0
+		if stmt_.StmtType() == stmt.VAR_DECL {
+			num_stars = stmt_.VarDeclStmt().TypeSpecifier().NumStars()
+		} else if stmt_.StmtType() == stmt.VAR_DECL_INIT {
+			num_stars = stmt_.VarDeclInitStmt().TypeSpecifier().NumStars()
+		} else if stmt_.StmtType() == stmt.ARRAY_DECL {
+			num_stars = stmt_.ArrayDeclStmt().TypeSpecifier().NumStars()
+		}
+
+		if stmt_.StmtType() != expected_types[i] || num_stars != expected_stars[i] {
+			t.Errorf("declarator %d is of stmt type %d with %d stars", i, stmt_.StmtType(), num_stars)
+		}
+	}
+
+	initialization := body.Get(2).ForStmt().Initialization()
+	if initialization.StmtType() != stmt.DECL_LIST {
+		t.Errorf("declarators of a for are not parsed")
+	}
+}
+
+// NOTE: the expectation table never rejects a token of a program that parses, so that the first
+// token it rejects is where a program that does not parse goes wrong
+func TestExpectationsOfValidPrograms(t *testing.T) {
+	paths, glob_err := filepath.Glob(
+		filepath.Join("..", "..", "..", "..", "benchmark", "*", "host", "app.c"),
+	)
+	if glob_err != nil {
+		t.Fatal(glob_err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no benchmark is found")
+	}
+
+	sources := []string{
+		`int g = 1, h[2] = {1, 2}, *p;
+		struct point { int x, y; };
+		struct point points[2] = {{1, 2}, {3, 4}};
+		char *s = "a" "b";
+		int f(int a, int b) { return a - b * -a; }
+		int main() {
+			int i = (int) 1.5, j = sizeof(int), k = i ? j : (i ? 1 : 2);
+			struct point q;
+			q.x = points[0].y;
+			for (int a = 0, b = 1; a < b; a++) i += f(a, (b + 1) * 2);
+			while (i) i--;
+			do { i++; } while (i < 3);
+			switch (i) { case 1: i = 2; break; case 2: { i = 3; } default: i = i > 1 ? 1 : 2; }
+			if (i) i = 1; else if (j) { j = 2; } else k = 3;
+			return f(i, j) + k;
+		}`,
+	}
+	for _, source := range sources {
+		paths = append(paths, writeSource(t, source))
+	}
+
+	for _, path := range paths {
+		parser_, _ := parseFile(path)
+
+		if parser_.unexpected_token != nil {
+			t.Errorf(
+				"%s is expected to be %s at %d:%d",
+				path,
+				parser_.expectation_table.Stringify(parser_.expectations),
+				parser_.unexpected_token.Line(),
+				parser_.unexpected_token.Column(),
+			)
+		}
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{
+			"int main() { int x = 3 return 0; }",
+			"1:24: error: expected ';', ',' or operator before 'return'",
+		},
+		{
+			"int x = 3 int y;",
+			"1:11: error: expected ';', ',' or operator before 'int'",
+		},
+		{
+			"int main() { int x = 1, y = 2 z; return 0; }",
+			"1:31: error: expected ',' or ';' before 'z'",
+		},
+		{
+			"int main() { int a[2] = {1, 2 3}; return 0; }",
+			"1:31: error: expected ',' or '}' before '3'",
+		},
+		{
+			"int main() { int x; x = (int) x + 1 return 0; }",
+			"1:37: error: expected ';' or operator before 'return'",
+		},
+		{
+			"int main() { int x; if (x; x = 1; return 0; }",
+			"1:26: error: expected ')' or operator before ';'",
+		},
+		{
+			"int main() { int x; for (x = 0; x < 3; x++; ) x = 1; return 0; }",
+			"1:43: error: expected ')' or operator before ';'",
+		},
+		{
+			"int main() { int x; for (x = 0; x < 3) x = 1; return 0; }",
+			"1:38: error: expected ';' or operator before ')'",
+		},
+		{
+			"int main() { int x; x = f(x; return 0; }",
+			"1:28: error: expected ',', ')' or operator before ';'",
+		},
+		{
+			"int main() { int x; x = x ? 1 2; return 0; }",
+			"1:31: error: expected ':' or operator before '2'",
+		},
+		{
+			"int main() { int x; x = a[1; return 0; }",
+			"1:28: error: expected ']' or operator before ';'",
+		},
+		{
+			"int main() { int x; x = 1 }",
+			"1:27: error: expected ';' or operator before '}'",
+		},
+		{
+			"int main() { return 0;",
+			"1:12: error: '{' is not closed",
+		},
+	}
+
+	for _, test := range tests {
+		if message := syntaxError(t, test.source); !strings.HasPrefix(message, test.expected) {
+			t.Errorf("%s is reported as %q, want %q", test.source, message, test.expected)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"errors"
 	"uPIMulator/src/host/interpreter/lexer"
+	"uPIMulator/src/host/interpreter/parser/stmt"
 )
 
 type Stack struct {
@@ -25,6 +26,10 @@ func (this *Stack) Pop(num int) {
 	this.stack_items = this.stack_items[:len(this.stack_items)-num]
 }
 
+func (this *Stack) Get(pos int) *StackItem {
+	return this.stack_items[pos]
+}
+
 func (this *Stack) Front(num int) []*StackItem {
 	stack_items := make([]*StackItem, 0)
 	for i := 0; i < num; i++ {
@@ -37,7 +42,8 @@ func (this *Stack) Front(num int) []*StackItem {
 func (this *Stack) CanAccept() bool {
 	for i, stack_item := range this.stack_items {
 		if i < len(this.stack_items)-1 {
-			if stack_item.StackItemType() != DIRECTIVE && stack_item.StackItemType() != DECL {
+			if stack_item.StackItemType() != DIRECTIVE && stack_item.StackItemType() != DECL &&
+				!this.IsGlobalVarDecl(stack_item) {
 				return false
 			}
 		} else {
@@ -49,6 +55,16 @@ func (this *Stack) CanAccept() bool {
 	return true
 }
 
+func (this *Stack) IsGlobalVarDecl(stack_item *StackItem) bool {
+	if stack_item.StackItemType() != STMT {
+		return false
+	}
+
+	stmt_type := stack_item.Stmt().StmtType()
+	return stmt_type == stmt.VAR_DECL || stmt_type == stmt.VAR_DECL_INIT ||
+		stmt_type == stmt.ARRAY_DECL || stmt_type == stmt.DECL_LIST
+}
+
 func (this *Stack) Accept() *Ast {
 	if !this.CanAccept() {
 		err := errors.New("stack cannot be accepted")
@@ -21,6 +21,8 @@ const (
 	STMT
 	DECL
 	DIRECTIVE
+	CONDITION
+	DECLARATOR_LIST
 )
 
 type StackItem struct {
@@ -34,12 +36,16 @@ type StackItem struct {
 	stmt           *stmt.Stmt
 	decl           *decl.Decl
 	directive      *directive.Directive
+	condition      *expr.Expr
+
+	first_token *lexer.Token
 }
 
 func (this *StackItem) InitToken(token *lexer.Token) {
 	this.stack_item_type = TOKEN
 
 	this.token = token
+	this.first_token = token
 }
 
 func (this *StackItem) InitTypeSpecifier(type_specifier *type_specifier.TypeSpecifier) {
@@ -84,6 +90,27 @@ func (this *StackItem) InitDirective(directive *directive.Directive) {
 	this.directive = directive
 }
 
+// NOTE: a condition is the keyword of an if, a while or a switch with its parenthesized condition,
+// which is reduced before the body so that the body cannot be taken as an operand of the condition
+func (this *StackItem) InitCondition(token *lexer.Token, condition *expr.Expr) {
+	this.stack_item_type = CONDITION
+
+	this.token = token
+	this.condition = condition
+}
+
+// NOTE: a declarator list keeps the base type of a declaration with several declarators, and the
+// decl list stmt that its declarators are appended to
+func (this *StackItem) InitDeclaratorList(
+	type_specifier *type_specifier.TypeSpecifier,
+	stmt *stmt.Stmt,
+) {
+	this.stack_item_type = DECLARATOR_LIST
+
+	this.type_specifier = type_specifier
+	this.stmt = stmt
+}
+
 func (this *StackItem) StackItemType() StackItemType {
 	return this.stack_item_type
 }
@@ -119,3 +146,17 @@ func (this *StackItem) Decl() *decl.Decl {
 func (this *StackItem) Directive() *directive.Directive {
 	return this.directive
 }
+
+func (this *StackItem) Condition() *expr.Expr {
+	return this.condition
+}
+
+// NOTE: the first token of a stack item is the token that it starts with in the source, which
+// locates the stack item
+func (this *StackItem) FirstToken() *lexer.Token {
+	return this.first_token
+}
+
+func (this *StackItem) SetFirstToken(first_token *lexer.Token) {
+	this.first_token = first_token
+}
@@ -0,0 +1,70 @@
+package stmt
+
+import (
+	"errors"
+	"uPIMulator/src/host/interpreter/lexer"
+	"uPIMulator/src/host/interpreter/parser/expr"
+	"uPIMulator/src/host/interpreter/parser/type_specifier"
+)
+
+// NOTE: the size of an array can be omitted when it is given by the length of its initializer
+type ArrayDeclStmt struct {
+	type_specifier *type_specifier.TypeSpecifier
+	identifier     *lexer.Token
+	size           *expr.Expr
+	initializer    *expr.Expr
+}
+
+func (this *ArrayDeclStmt) Init(
+	type_specifier *type_specifier.TypeSpecifier,
+	identifier *lexer.Token,
+	size *expr.Expr,
+	initializer *expr.Expr,
+) {
+	if identifier.TokenType() != lexer.IDENTIFIER {
+		err := errors.New("identifier's token type is not identifier")
+		panic(err)
+	} else if size == nil && initializer == nil {
+		err := errors.New("array has neither a size nor an initializer")
+		panic(err)
+	}
+
+	this.type_specifier = type_specifier
+	this.identifier = identifier
+	this.size = size
+	this.initializer = initializer
+}
+
+func (this *ArrayDeclStmt) TypeSpecifier() *type_specifier.TypeSpecifier {
+	return this.type_specifier
+}
+
+func (this *ArrayDeclStmt) Identifier() *lexer.Token {
+	return this.identifier
+}
+
+func (this *ArrayDeclStmt) HasSize() bool {
+	return this.size != nil
+}
+
+func (this *ArrayDeclStmt) Size() *expr.Expr {
+	if this.size == nil {
+		err := errors.New("size == nil")
+		panic(err)
+	}
+
+	return this.size
+}
+
+func (this *ArrayDeclStmt) HasInitializer() bool {
+	return this.initializer != nil
+}
+
+func (this *ArrayDeclStmt) Initializer() *expr.Expr {
+	if this.initializer == nil {
+		err := errors.New("initializer == nil")
+		panic(err)
+	}
+
+	return this.initializer
+}
@@ -0,0 +1,17 @@
+package stmt
+
+import (
+	"uPIMulator/src/host/interpreter/parser/expr"
+)
+
+type CaseStmt struct {
+	value *expr.Expr
+}
+
+func (this *CaseStmt) Init(value *expr.Expr) {
+	this.value = value
+}
+
+func (this *CaseStmt) Value() *expr.Expr {
+	return this.value
+}
@@ -0,0 +1,33 @@
+package stmt
+
+import (
+	"errors"
+)
+
+// NOTE: a declaration of several declarators, e.g. int a, *b = &a, c[4];, declares them one by one
+// in the scope that it appears in, so that it is not a block
+type DeclListStmt struct {
+	stmts []*Stmt
+}
+
+func (this *DeclListStmt) Init() {
+	this.stmts = make([]*Stmt, 0)
+}
+
+func (this *DeclListStmt) Length() int {
+	return len(this.stmts)
+}
+
+func (this *DeclListStmt) Get(pos int) *Stmt {
+	return this.stmts[pos]
+}
+
+func (this *DeclListStmt) Append(stmt *Stmt) {
+	if stmt.StmtType() != VAR_DECL && stmt.StmtType() != VAR_DECL_INIT &&
+		stmt.StmtType() != ARRAY_DECL {
+		err := errors.New("declarator's stmt type is not a var decl")
+		panic(err)
+	}
+
+	this.stmts = append(this.stmts, stmt)
+}
@@ -0,0 +1,7 @@
+package stmt
+
+type DefaultStmt struct {
+}
+
+func (this *DefaultStmt) Init() {
+}
@@ -0,0 +1,29 @@
+package stmt
+
+import (
+	"errors"
+	"uPIMulator/src/host/interpreter/parser/expr"
+)
+
+type DoWhileStmt struct {
+	body      *Stmt
+	condition *expr.Expr
+}
+
+func (this *DoWhileStmt) Init(body *Stmt, condition *expr.Expr) {
+	if body.StmtType() != BLOCK {
+		err := errors.New("body's stmt type is not block")
+		panic(err)
+	}
+
+	this.body = body
+	this.condition = condition
+}
+
+func (this *DoWhileStmt) Body() *Stmt {
+	return this.body
+}
+
+func (this *DoWhileStmt) Condition() *expr.Expr {
+	return this.condition
+}
@@ -5,14 +5,17 @@ import (
 	"uPIMulator/src/host/interpreter/parser/expr"
 )
 
+// NOTE: DPU_FOREACH iterates over the DPUs of a set and DPU_RANK_FOREACH over its ranks, where the
+// index of the iteration is optionally assigned to the third argument
 type DpuForeachStmt struct {
 	foreach *expr.ArgList
 	body    *Stmt
+	is_rank bool
 }
 
-func (this *DpuForeachStmt) Init(foreach *expr.ArgList, body *Stmt) {
-	if foreach.Length() != 3 {
-		err := errors.New("arg list's length != 3")
+func (this *DpuForeachStmt) Init(foreach *expr.ArgList, body *Stmt, is_rank bool) {
+	if foreach.Length() != 2 && foreach.Length() != 3 {
+		err := errors.New("arg list's length is neither 2 nor 3")
 		panic(err)
 	} else if body.StmtType() != BLOCK {
 		err := errors.New("body's stmt type is not block")
@@ -21,6 +24,7 @@ func (this *DpuForeachStmt) Init(foreach *expr.ArgList, body *Stmt) {
 
 	this.foreach = foreach
 	this.body = body
+	this.is_rank = is_rank
 }
 
 func (this *DpuForeachStmt) Foreach() *expr.ArgList {
@@ -30,3 +34,7 @@ func (this *DpuForeachStmt) Foreach() *expr.ArgList {
 func (this *DpuForeachStmt) Body() *Stmt {
 	return this.body
 }
+
+func (this *DpuForeachStmt) IsRank() bool {
+	return this.is_rank
+}
@@ -1,20 +1,30 @@
 package stmt
 
+import (
+	"uPIMulator/src/host/interpreter/lexer"
+)
+
 type StmtType int
 
 const (
 	EMPTY StmtType = iota
 	VAR_DECL
 	VAR_DECL_INIT
+	ARRAY_DECL
 	FOR
 	DPU_FOREACH
 	WHILE
+	DO_WHILE
+	SWITCH
+	CASE
+	DEFAULT
 	CONTINUE
 	BREAK
 	IF
 	RETURN
 	EXPR
 	BLOCK
+	DECL_LIST
 )
 
 type Stmt struct {
@@ -23,15 +33,23 @@ type Stmt struct {
 	empty_stmt         *EmptyStmt
 	var_decl_stmt      *VarDeclStmt
 	var_decl_init_stmt *VarDeclInitStmt
+	array_decl_stmt    *ArrayDeclStmt
 	for_stmt           *ForStmt
 	dpu_foreach_stmt   *DpuForeachStmt
 	while_stmt         *WhileStmt
+	do_while_stmt      *DoWhileStmt
+	switch_stmt        *SwitchStmt
+	case_stmt          *CaseStmt
+	default_stmt       *DefaultStmt
 	continue_stmt      *ContinueStmt
 	break_stmt         *BreakStmt
 	if_stmt            *IfStmt
 	return_stmt        *ReturnStmt
 	expr_stmt          *ExprStmt
 	block_stmt         *BlockStmt
+	decl_list_stmt     *DeclListStmt
+
+	first_token *lexer.Token
 }
 
 func (this *Stmt) InitEmptyStmt(empty_stmt *EmptyStmt) {
@@ -52,6 +70,12 @@ func (this *Stmt) InitVarDeclInitStmt(var_decl_init_stmt *VarDeclInitStmt) {
 	this.var_decl_init_stmt = var_decl_init_stmt
 }
 
+func (this *Stmt) InitArrayDeclStmt(array_decl_stmt *ArrayDeclStmt) {
+	this.stmt_type = ARRAY_DECL
+
+	this.array_decl_stmt = array_decl_stmt
+}
+
 func (this *Stmt) InitForStmt(for_stmt *ForStmt) {
 	this.stmt_type = FOR
 
@@ -70,6 +94,30 @@ func (this *Stmt) InitWhileStmt(while_stmt *WhileStmt) {
 	this.while_stmt = while_stmt
 }
 
+func (this *Stmt) InitDoWhileStmt(do_while_stmt *DoWhileStmt) {
+	this.stmt_type = DO_WHILE
+
+	this.do_while_stmt = do_while_stmt
+}
+
+func (this *Stmt) InitSwitchStmt(switch_stmt *SwitchStmt) {
+	this.stmt_type = SWITCH
+
+	this.switch_stmt = switch_stmt
+}
+
+func (this *Stmt) InitCaseStmt(case_stmt *CaseStmt) {
+	this.stmt_type = CASE
+
+	this.case_stmt = case_stmt
+}
+
+func (this *Stmt) InitDefaultStmt(default_stmt *DefaultStmt) {
+	this.stmt_type = DEFAULT
+
+	this.default_stmt = default_stmt
+}
+
 func (this *Stmt) InitContinueStmt(continue_stmt *ContinueStmt) {
 	this.stmt_type = CONTINUE
 
@@ -106,6 +154,12 @@ func (this *Stmt) InitBlockStmt(block_stmt *BlockStmt) {
 	this.block_stmt = block_stmt
 }
 
+func (this *Stmt) InitDeclListStmt(decl_list_stmt *DeclListStmt) {
+	this.stmt_type = DECL_LIST
+
+	this.decl_list_stmt = decl_list_stmt
+}
+
 func (this *Stmt) StmtType() StmtType {
 	return this.stmt_type
 }
@@ -122,6 +176,10 @@ func (this *Stmt) VarDeclInitStmt() *VarDeclInitStmt {
 	return this.var_decl_init_stmt
 }
 
+func (this *Stmt) ArrayDeclStmt() *ArrayDeclStmt {
+	return this.array_decl_stmt
+}
+
 func (this *Stmt) ForStmt() *ForStmt {
 	return this.for_stmt
 }
@@ -134,6 +192,22 @@ func (this *Stmt) WhileStmt() *WhileStmt {
 	return this.while_stmt
 }
 
+func (this *Stmt) DoWhileStmt() *DoWhileStmt {
+	return this.do_while_stmt
+}
+
+func (this *Stmt) SwitchStmt() *SwitchStmt {
+	return this.switch_stmt
+}
+
+func (this *Stmt) CaseStmt() *CaseStmt {
+	return this.case_stmt
+}
+
+func (this *Stmt) DefaultStmt() *DefaultStmt {
+	return this.default_stmt
+}
+
 func (this *Stmt) ContinueStmt() *ContinueStmt {
 	return this.continue_stmt
 }
@@ -157,3 +231,15 @@ func (this *Stmt) ExprStmt() *ExprStmt {
 func (this *Stmt) BlockStmt() *BlockStmt {
 	return this.block_stmt
 }
+
+func (this *Stmt) DeclListStmt() *DeclListStmt {
+	return this.decl_list_stmt
+}
+
+func (this *Stmt) FirstToken() *lexer.Token {
+	return this.first_token
+}
+
+func (this *Stmt) SetFirstToken(first_token *lexer.Token) {
+	this.first_token = first_token
+}
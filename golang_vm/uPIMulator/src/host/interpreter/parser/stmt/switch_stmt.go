@@ -0,0 +1,29 @@
+package stmt
+
+import (
+	"errors"
+	"uPIMulator/src/host/interpreter/parser/expr"
+)
+
+type SwitchStmt struct {
+	condition *expr.Expr
+	body      *Stmt
+}
+
+func (this *SwitchStmt) Init(condition *expr.Expr, body *Stmt) {
+	if body.StmtType() != BLOCK {
+		err := errors.New("body's stmt type is not block")
+		panic(err)
+	}
+
+	this.condition = condition
+	this.body = body
+}
+
+func (this *SwitchStmt) Condition() *expr.Expr {
+	return this.condition
+}
+
+func (this *SwitchStmt) Body() *Stmt {
+	return this.body
+}
@@ -41,6 +41,7 @@ func (this *Table) Reduce(token *lexer.Token) {
 		for _, rule := range this.rules {
 			if rule.IsReducible(stack_items, token) {
 				stack_item := rule.Reduce(stack_items, token)
+				this.Locate(stack_item, stack_items)
 
 				this.stack.Pop(len(stack_items))
 				this.stack.Push(stack_item)
@@ -53,3 +54,18 @@ func (this *Table) Reduce(token *lexer.Token) {
 	err := errors.New("stack is not reducible")
 	panic(err)
 }
+
+func (this *Table) Locate(stack_item *StackItem, stack_items []*StackItem) {
+	for _, stack_item_ := range stack_items {
+		if stack_item_.FirstToken() != nil {
+			stack_item.SetFirstToken(stack_item_.FirstToken())
+			break
+		}
+	}
+
+	if stack_item.StackItemType() == STMT && stack_item.Stmt().FirstToken() == nil {
+		stack_item.Stmt().SetFirstToken(stack_item.FirstToken())
+	} else if stack_item.StackItemType() == EXPR && stack_item.Expr().FirstToken() == nil {
+		stack_item.Expr().SetFirstToken(stack_item.FirstToken())
+	}
+}
@@ -13,6 +13,8 @@ const (
 	SHORT
 	INT
 	LONG
+	FLOAT
+	DOUBLE
 	STRUCT
 )
 
@@ -20,6 +22,7 @@ type TypeSpecifier struct {
 	type_specifier_type TypeSpecifierType
 	struct_identifier   *lexer.Token
 	num_stars           int
+	is_unsigned         bool
 }
 
 func (this *TypeSpecifier) InitPrimitive(type_specifier_type TypeSpecifierType) {
@@ -31,6 +34,7 @@ func (this *TypeSpecifier) InitPrimitive(type_specifier_type TypeSpecifierType)
 	this.type_specifier_type = type_specifier_type
 	this.struct_identifier = nil
 	this.num_stars = 0
+	this.is_unsigned = false
 }
 
 func (this *TypeSpecifier) InitStruct(
@@ -45,6 +49,7 @@ func (this *TypeSpecifier) InitStruct(
 	this.type_specifier_type = type_specifier_type
 	this.struct_identifier = struct_identifier
 	this.num_stars = 0
+	this.is_unsigned = false
 }
 
 func (this *TypeSpecifier) TypeSpecifierType() TypeSpecifierType {
@@ -67,3 +72,34 @@ func (this *TypeSpecifier) NumStars() int {
 func (this *TypeSpecifier) AddStar() {
 	this.num_stars++
 }
+
+func (this *TypeSpecifier) IsInteger() bool {
+	return this.type_specifier_type == CHAR ||
+		this.type_specifier_type == SHORT ||
+		this.type_specifier_type == INT ||
+		this.type_specifier_type == LONG
+}
+
+func (this *TypeSpecifier) IsUnsigned() bool {
+	return this.is_unsigned
+}
+
+func (this *TypeSpecifier) SetUnsigned() {
+	if !this.IsInteger() {
+		err := errors.New("type specifier type is not an integer")
+		panic(err)
+	}
+
+	this.is_unsigned = true
+}
+
+// NOTE: the declarators of a declaration share its base type, i.e. the type without the stars,
+// since the stars of a declarator belong to it alone, e.g. q of int *p, q; is an int
+func (this *TypeSpecifier) BaseType() *TypeSpecifier {
+	base_type := new(TypeSpecifier)
+	base_type.type_specifier_type = this.type_specifier_type
+	base_type.struct_identifier = this.struct_identifier
+	base_type.num_stars = 0
+	base_type.is_unsigned = this.is_unsigned
+	return base_type
+}
@@ -1,6 +1,7 @@
 package arena
 
 import (
+	"math"
 	"uPIMulator/src/encoding"
 	"uPIMulator/src/host/vm/base"
 	"uPIMulator/src/host/vm/type_system"
@@ -99,6 +100,54 @@ func (this *Arena) NewLong(value int64) *base.Object {
 	return object
 }
 
+func (this *Arena) NewFloat(value float64) *base.Object {
+	object := this.pool.Alloc(base.TEMPORARY, 4)
+
+	bits := int64(math.Float32bits(float32(value)))
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	byte_stream.Append(uint8(bits & 0xFF))
+	byte_stream.Append(uint8((bits >> 8) & 0xFF))
+	byte_stream.Append(uint8((bits >> 16) & 0xFF))
+	byte_stream.Append(uint8((bits >> 24) & 0xFF))
+	this.pool.Memory().Write(object.Address(), 4, byte_stream)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, 0)
+
+	object.SetTypeVariable(type_variable)
+
+	return object
+}
+
+func (this *Arena) NewDouble(value float64) *base.Object {
+	object := this.pool.Alloc(base.TEMPORARY, 8)
+
+	bits := int64(math.Float64bits(value))
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	byte_stream.Append(uint8(bits & 0xFF))
+	byte_stream.Append(uint8((bits >> 8) & 0xFF))
+	byte_stream.Append(uint8((bits >> 16) & 0xFF))
+	byte_stream.Append(uint8((bits >> 24) & 0xFF))
+	byte_stream.Append(uint8((bits >> 32) & 0xFF))
+	byte_stream.Append(uint8((bits >> 40) & 0xFF))
+	byte_stream.Append(uint8((bits >> 48) & 0xFF))
+	byte_stream.Append(uint8((bits >> 56) & 0xFF))
+	this.pool.Memory().Write(object.Address(), 8, byte_stream)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, 0)
+
+	object.SetTypeVariable(type_variable)
+
+	return object
+}
+
 func (this *Arena) NewString(value string) *base.Object {
 	object := this.pool.Alloc(base.TEMPORARY, int64(len(value)))
 
@@ -2,32 +2,96 @@ package arena
 
 import (
 	"errors"
+	"uPIMulator/src/encoding"
 	"uPIMulator/src/host/vm/base"
 	"uPIMulator/src/host/vm/frame"
-	"uPIMulator/src/host/vm/symbol"
 	"uPIMulator/src/host/vm/type_system"
 	"uPIMulator/src/misc"
 )
 
+// NOTE: a host clock counts the cycles of the host that runs the garbage collector and charges it
+// the objects that a pause visits
+type HostClock interface {
+	Cycles() int64
+	Collect(num_objects int64)
+}
+
+// NOTE: the garbage collector frees the TEMPORARY objects that are neither reachable from a symbol
+// nor held by a stack item. A major collection marks every reachable object in steps of a bounded
+// number of objects, interleaved with the bytecodes, while a Dijkstra-style write barrier shades
+// the objects whose addresses are written to the memory and new objects are allocated marked. The
+// roots are marked again before the sweep since the stacks and scopes are not guarded by the
+// barrier. With the generational mode on, the TEMPORARY objects are young until they survive a
+// collection, and most collections are minor ones that only trace and sweep the young objects,
+// taking the young objects whose addresses were written to the memory since as extra roots.
 type GarbageCollector struct {
-	cycle     int64
 	threshold int64
 
+	is_generational bool
+	mark_budget     int64
+	major_interval  int64
+
 	arena       *Arena
 	frame_chain *frame.FrameChain
 	registry    *type_system.TypeRegistry
+
+	is_marking   bool
+	is_minor     bool
+	gray_objects []*base.Object
+
+	young_objects      []*base.Object
+	remembered_objects map[*base.Object]bool
+
+	num_minor_collections int64
+
+	host_clock        HostClock
+	pause_start       int64
+	num_pause_objects int64
+
+	stat_factory *misc.StatFactory
 }
 
-func (this *GarbageCollector) Init() {
+func (this *GarbageCollector) Init(command_line_parser *misc.CommandLineParser) {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
-	this.cycle = 0
 	this.threshold = config_loader.GarbageCollectionThreshold()
+
+	this.is_generational = command_line_parser.BoolParameter("vm_gc_generational")
+	this.mark_budget = command_line_parser.IntParameter("vm_gc_mark_budget")
+	this.major_interval = command_line_parser.IntParameter("vm_gc_major_interval")
+
+	this.is_marking = false
+	this.is_minor = false
+	this.gray_objects = make([]*base.Object, 0)
+
+	this.young_objects = make([]*base.Object, 0)
+	this.remembered_objects = make(map[*base.Object]bool)
+
+	this.num_minor_collections = 0
+
+	this.host_clock = nil
+	this.pause_start = 0
+	this.num_pause_objects = 0
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("GarbageCollector")
 }
 
 func (this *GarbageCollector) ConnectArena(arena *Arena) {
 	this.arena = arena
+
+	this.arena.Pool().ConnectAllocationTracker(this)
+	this.arena.Pool().Memory().ConnectWriteBarrier(this)
+}
+
+func (this *GarbageCollector) ConnectHostClock(host_clock HostClock) {
+	if this.host_clock != nil {
+		err := errors.New("host clock is already set")
+		panic(err)
+	}
+
+	this.host_clock = host_clock
 }
 
 func (this *GarbageCollector) ConnectFrameChain(frame_chain *frame.FrameChain) {
@@ -38,154 +102,282 @@ func (this *GarbageCollector) ConnectRegistry(registry *type_system.TypeRegistry
 	this.registry = registry
 }
 
-func (this *GarbageCollector) MarkAndSweep() {
-	if this.cycle > this.threshold {
-		objects := this.Mark()
-		this.Sweep(objects)
+func (this *GarbageCollector) Threshold() int64 {
+	return this.threshold
+}
+
+func (this *GarbageCollector) IsMarking() bool {
+	return this.is_marking
+}
+
+func (this *GarbageCollector) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// NOTE: the pauses are measured in host cycles, and the throughput is the fraction of the host
+// cycles that were not spent in a pause
+func (this *GarbageCollector) UpdateStats() {
+	if this.host_clock == nil || this.host_clock.Cycles() == 0 {
+		return
+	}
+
+	cycles := this.host_clock.Cycles()
+	throughput := 1000 * (cycles - this.stat_factory.Value("pause_cycles")) / cycles
+	this.stat_factory.Set("throughput_permille", throughput)
+}
+
+func (this *GarbageCollector) Collect() {
+	this.BeginPause()
+
+	if this.is_generational && this.num_minor_collections < this.major_interval {
+		this.num_minor_collections++
+		this.stat_factory.Increment("minor_collections", 1)
+
+		this.is_minor = true
+		this.MarkRoots()
+		for object := range this.remembered_objects {
+			this.Shade(object)
+		}
+		this.Drain(-1)
+		this.SweepYoung()
+		this.is_minor = false
+	} else {
+		this.num_minor_collections = 0
+		this.stat_factory.Increment("major_collections", 1)
+
+		for _, object := range this.young_objects {
+			object.Promote()
+		}
+		this.young_objects = make([]*base.Object, 0)
+		this.remembered_objects = make(map[*base.Object]bool)
+
+		this.is_marking = true
+		this.MarkRoots()
+
+		if this.mark_budget == 0 {
+			this.FinishMarking()
+		}
+	}
+
+	this.EndPause()
+}
+
+func (this *GarbageCollector) Step() {
+	this.BeginPause()
+
+	this.stat_factory.Increment("mark_steps", 1)
 
-		this.cycle = 0
+	if this.Drain(this.mark_budget) {
+		this.FinishMarking()
 	}
 
-	this.cycle++
+	this.EndPause()
 }
 
-func (this *GarbageCollector) Mark() []*base.Object {
-	objects := make([]*base.Object, 0)
+func (this *GarbageCollector) FinishMarking() {
+	this.MarkRoots()
+	this.Drain(-1)
+	this.Sweep()
+
+	this.is_marking = false
+}
+
+func (this *GarbageCollector) MarkRoots() {
 	for _, symbol_ := range this.frame_chain.Symbols() {
-		objects = append(objects, this.ChaseSymbol(symbol_)...)
+		this.Shade(symbol_.Object())
+	}
+
+	for _, stack_item := range this.frame_chain.StackItems() {
+		this.ShadeAddress(stack_item.Address())
 	}
-	return objects
 }
 
-func (this *GarbageCollector) Sweep(objects []*base.Object) {
-	for _, obj := range this.arena.Pool().Objects() {
-		if obj.ObjectType() == base.TEMPORARY && !this.IsMarked(obj, objects) &&
-			!this.frame_chain.HasObject(obj.Address()) &&
-			this.arena.Pool().HasObject(obj.Address()) {
-			this.arena.Free(obj.Address())
+// NOTE: Drain scans at most budget gray objects, or every one of them if budget is negative, and
+// tells whether no gray object is left
+func (this *GarbageCollector) Drain(budget int64) bool {
+	for i := int64(0); (budget < 0 || i < budget) && len(this.gray_objects) > 0; i++ {
+		object := this.gray_objects[len(this.gray_objects)-1]
+		this.gray_objects = this.gray_objects[:len(this.gray_objects)-1]
+
+		if this.IsLive(object) {
+			this.Scan(object)
+			this.num_pause_objects++
 		}
 	}
+
+	return len(this.gray_objects) == 0
 }
 
-func (this *GarbageCollector) ChaseSymbol(symbol_ *symbol.Symbol) []*base.Object {
-	objects := make([]*base.Object, 0)
+func (this *GarbageCollector) Sweep() {
+	for _, object := range this.arena.Pool().Objects() {
+		this.num_pause_objects++
+
+		if object.ObjectType() == base.TEMPORARY && !object.IsMarked() {
+			this.Free(object)
+		} else {
+			object.Unmark()
+		}
+	}
+}
 
-	type_variable := symbol_.TypeVariable()
+func (this *GarbageCollector) SweepYoung() {
+	for _, object := range this.young_objects {
+		this.num_pause_objects++
 
-	if type_variable.TypeVariableType() == type_system.VOID {
-		if type_variable.NumStars() == 0 {
-			err := errors.New("num stars == 0")
-			panic(err)
+		if this.IsLive(object) {
+			if !object.IsMarked() {
+				this.Free(object)
+			} else {
+				object.Unmark()
+				object.Promote()
+
+				this.stat_factory.Increment("promoted_objects", 1)
+			}
 		}
+	}
 
-		objects = append(objects, this.ChaseObject(symbol_.Object())...)
-	} else if type_variable.TypeVariableType() == type_system.CHAR {
-		objects = append(objects, this.ChaseObject(symbol_.Object())...)
-	} else if type_variable.TypeVariableType() == type_system.SHORT {
-		objects = append(objects, this.ChaseObject(symbol_.Object())...)
-	} else if type_variable.TypeVariableType() == type_system.INT {
-		objects = append(objects, this.ChaseObject(symbol_.Object())...)
-	} else if type_variable.TypeVariableType() == type_system.LONG {
-		objects = append(objects, this.ChaseObject(symbol_.Object())...)
-	} else if type_variable.TypeVariableType() == type_system.STRUCT {
-		objects = append(objects, this.ChaseObject(symbol_.Object())...)
-	} else {
-		err := errors.New("type variable type is not valid")
-		panic(err)
+	this.young_objects = make([]*base.Object, 0)
+	this.remembered_objects = make(map[*base.Object]bool)
+}
+
+func (this *GarbageCollector) Free(object *base.Object) {
+	this.stat_factory.Increment("freed_objects", 1)
+	this.stat_factory.Increment("freed_bytes", object.Size())
+
+	this.arena.Free(object.Address())
+}
+
+// NOTE: an object only counts as live while the pool still holds it, since it may have been freed
+// explicitly and its address given to another object
+func (this *GarbageCollector) IsLive(object *base.Object) bool {
+	return this.arena.Pool().HasObject(object.Address()) &&
+		this.arena.Pool().Object(object.Address()) == object
+}
+
+func (this *GarbageCollector) Shade(object *base.Object) {
+	if object.IsMarked() || (this.is_minor && object.Generation() == base.OLD) {
+		return
 	}
 
-	return objects
+	object.Mark()
+	this.gray_objects = append(this.gray_objects, object)
+	this.num_pause_objects++
+
+	this.stat_factory.Increment("marked_objects", 1)
 }
 
-func (this *GarbageCollector) ChaseObject(object *base.Object) []*base.Object {
-	objects := make([]*base.Object, 0)
+func (this *GarbageCollector) ShadeAddress(address int64) {
+	if this.arena.Pool().HasObject(address) {
+		this.Shade(this.arena.Pool().Object(address))
+	}
+}
 
-	objects = append(objects, object)
+func (this *GarbageCollector) ShadePointer(address int64) {
+	this.ShadeAddress(this.arena.Pool().Memory().Peek(address, 4).SignedValue())
+}
 
+func (this *GarbageCollector) Scan(object *base.Object) {
 	if !object.HasTypeVariable() {
-		err := errors.New("object does not have a type variable")
-		panic(err)
+		return
 	}
 
 	type_variable := object.TypeVariable()
 
-	if type_variable.TypeVariableType() == type_system.VOID {
-		if type_variable.NumStars() == 0 {
-			err := errors.New("num stars == 0")
-			panic(err)
-		}
+	if type_variable.NumStars() > 0 {
+		this.ShadePointer(object.Address())
+	} else if type_variable.TypeVariableType() == type_system.STRUCT {
+		this.ScanStruct(object.Address(), type_variable.StructName())
+	}
+}
 
-		if type_variable.NumStars() > 0 {
-			value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-			objects = append(objects, this.ChasePointer(value)...)
-		}
-	} else if type_variable.TypeVariableType() == type_system.CHAR {
-		if type_variable.NumStars() > 0 {
-			value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-			objects = append(objects, this.ChasePointer(value)...)
-		}
-	} else if type_variable.TypeVariableType() == type_system.SHORT {
-		if type_variable.NumStars() > 0 {
-			value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-			objects = append(objects, this.ChasePointer(value)...)
-		}
-	} else if type_variable.TypeVariableType() == type_system.INT {
-		if type_variable.NumStars() > 0 {
-			value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-			objects = append(objects, this.ChasePointer(value)...)
-		}
-	} else if type_variable.TypeVariableType() == type_system.LONG {
-		if type_variable.NumStars() > 0 {
-			value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-			objects = append(objects, this.ChasePointer(value)...)
+func (this *GarbageCollector) ScanStruct(address int64, struct_name string) {
+	skeleton := this.registry.Skeleton(struct_name)
+
+	for _, field := range skeleton.Fields() {
+		field_address := address + this.registry.FieldOffset(struct_name, field.Name())
+
+		length := int64(1)
+		if field.IsArray() {
+			length = field.Length()
 		}
-	} else if type_variable.TypeVariableType() == type_system.STRUCT {
-		if type_variable.NumStars() > 0 {
-			value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-			objects = append(objects, this.ChasePointer(value)...)
-		} else {
-			struct_name := type_variable.StructName()
-
-			skeleton := this.registry.Skeleton(struct_name)
-
-			for i, field := range skeleton.Fields() {
-				if i == 0 {
-					if field.TypeVariable().NumStars() > 0 {
-						value := this.arena.Pool().Memory().Read(object.Address(), 4).SignedValue()
-						objects = append(objects, this.ChasePointer(value)...)
-					} else {
-						objects = append(objects, this.arena.Pool().Object(object.Address()))
-					}
-				} else {
-					field_offset := this.registry.FieldOffset(skeleton.Name(), field.Name())
-
-					field_address := object.Address() + field_offset
-
-					if this.arena.Pool().HasObject(field_address) {
-						field_object := this.arena.Pool().Object(field_address)
-						objects = append(objects, this.ChaseObject(field_object)...)
-					}
-				}
+
+		element_size := this.registry.TypeSize(field.TypeVariable())
+		for i := int64(0); i < length; i++ {
+			element_address := field_address + i*element_size
+
+			if field.TypeVariable().NumStars() > 0 {
+				this.ShadePointer(element_address)
+			} else if field.TypeVariable().TypeVariableType() == type_system.STRUCT {
+				this.ScanStruct(element_address, field.TypeVariable().StructName())
 			}
 		}
+	}
+}
+
+func (this *GarbageCollector) Allocate(object *base.Object) {
+	if this.is_marking {
+		object.Mark()
+	}
+
+	if this.is_generational && object.ObjectType() == base.TEMPORARY {
+		this.young_objects = append(this.young_objects, object)
 	} else {
-		err := errors.New("type variable type is not valid")
-		panic(err)
+		object.Promote()
 	}
+}
+
+// NOTE: every 4 bytes written at any offset are taken as a pointer, so that the pointers of packed
+// structs are found as well
+func (this *GarbageCollector) Barrier(address int64, byte_stream *encoding.ByteStream) {
+	if !this.is_marking && !this.is_generational {
+		return
+	}
+
+	for i := int64(0); i+4 <= byte_stream.Size(); i++ {
+		value := int64(byte_stream.Get(int(i))) |
+			int64(byte_stream.Get(int(i+1)))<<8 |
+			int64(byte_stream.Get(int(i+2)))<<16 |
+			int64(byte_stream.Get(int(i+3)))<<24
 
-	return objects
+		if !this.arena.Pool().HasObject(value) {
+			continue
+		}
+
+		object := this.arena.Pool().Object(value)
+
+		if this.is_marking && !object.IsMarked() {
+			this.Shade(object)
+			this.stat_factory.Increment("barrier_shades", 1)
+		}
+
+		if this.is_generational && object.Generation() == base.YOUNG &&
+			!this.remembered_objects[object] {
+			this.remembered_objects[object] = true
+			this.stat_factory.Increment("remembered_objects", 1)
+		}
+	}
 }
 
-func (this *GarbageCollector) ChasePointer(address int64) []*base.Object {
-	object := this.arena.Pool().Object(address)
-	return this.ChaseObject(object)
+// NOTE: without a host clock, e.g. in unit tests, the pauses take no cycles
+func (this *GarbageCollector) BeginPause() {
+	this.num_pause_objects = 0
+
+	if this.host_clock != nil {
+		this.pause_start = this.host_clock.Cycles()
+	}
 }
 
-func (this *GarbageCollector) IsMarked(object *base.Object, objects []*base.Object) bool {
-	for _, obj := range objects {
-		if obj == object {
-			return true
-		}
+func (this *GarbageCollector) EndPause() {
+	pause_cycles := int64(0)
+	if this.host_clock != nil {
+		this.host_clock.Collect(this.num_pause_objects)
+		pause_cycles = this.host_clock.Cycles() - this.pause_start
+	}
+
+	this.stat_factory.Increment("pauses", 1)
+	this.stat_factory.Increment("pause_cycles", pause_cycles)
+
+	if pause_cycles > this.stat_factory.Value("max_pause_cycles") {
+		this.stat_factory.Set("max_pause_cycles", pause_cycles)
 	}
-	return false
 }
@@ -0,0 +1,216 @@
+package arena
+
+import (
+	"strconv"
+	"testing"
+	"uPIMulator/src/encoding"
+	"uPIMulator/src/host/vm/base"
+	"uPIMulator/src/host/vm/frame"
+	"uPIMulator/src/host/vm/symbol"
+	"uPIMulator/src/host/vm/type_system"
+	"uPIMulator/src/misc"
+)
+
+func newGarbageCollector(
+	is_generational bool,
+	mark_budget int64,
+) (*GarbageCollector, *Arena, *frame.FrameChain) {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"vm_gc_generational",
+		strconv.FormatBool(is_generational),
+		"",
+	)
+	command_line_parser.AddOption(misc.INT, "vm_gc_mark_budget", strconv.FormatInt(mark_budget, 10), "")
+	command_line_parser.AddOption(misc.INT, "vm_gc_major_interval", "8", "")
+
+	arena := new(Arena)
+	arena.Init()
+
+	frame_chain := new(frame.FrameChain)
+	frame_chain.Init()
+
+	registry := new(type_system.TypeRegistry)
+	registry.Init()
+
+	garbage_collector := new(GarbageCollector)
+	garbage_collector.Init(command_line_parser)
+	garbage_collector.ConnectArena(arena)
+	garbage_collector.ConnectFrameChain(frame_chain)
+	garbage_collector.ConnectRegistry(registry)
+
+	return garbage_collector, arena, frame_chain
+}
+
+// NOTE: a global int pointer is a root whose pointee is traced through the memory
+func newRoot(arena *Arena, frame_chain *frame.FrameChain, name string) *base.Object {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.INT, 1)
+
+	object := arena.Pool().Alloc(base.TEMPORARY, 4)
+	object.SetTypeVariable(type_variable)
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(name, type_variable, object)
+	frame_chain.GlobalScope().AddSymbol(symbol_)
+
+	return object
+}
+
+func storePointer(arena *Arena, pointer *base.Object, pointee *base.Object) {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for i := 0; i < 4; i++ {
+		byte_stream.Append(uint8((pointee.Address() >> (8 * i)) & 0xFF))
+	}
+
+	arena.Pool().Memory().Write(pointer.Address(), 4, byte_stream)
+}
+
+func isAlive(arena *Arena, object *base.Object) bool {
+	return arena.Pool().HasObject(object.Address()) &&
+		arena.Pool().Object(object.Address()) == object
+}
+
+func TestGarbageCollectorFreesUnreachable(t *testing.T) {
+	for _, is_generational := range []bool{false, true} {
+		for _, mark_budget := range []int64{0, 1, 64} {
+			garbage_collector, arena, frame_chain := newGarbageCollector(is_generational, mark_budget)
+
+			root := newRoot(arena, frame_chain, "root")
+			pointee := arena.NewInt(1)
+			garbage := arena.NewInt(2)
+			storePointer(arena, root, pointee)
+
+			garbage_collector.Collect()
+			for garbage_collector.IsMarking() {
+				garbage_collector.Step()
+			}
+
+			if !isAlive(arena, root) || !isAlive(arena, pointee) || isAlive(arena, garbage) {
+				t.Errorf(
+					"generational (%t) with mark budget (%d) does not free only the garbage",
+					is_generational,
+					mark_budget,
+				)
+			}
+		}
+	}
+}
+
+// NOTE: one of two roots is scanned before the pointee is stored to it, so that the pointee is
+// only found through the barrier
+func TestGarbageCollectorWriteBarrier(t *testing.T) {
+	garbage_collector, arena, frame_chain := newGarbageCollector(false, 1)
+
+	roots := []*base.Object{newRoot(arena, frame_chain, "root1"), newRoot(arena, frame_chain, "root2")}
+	pointee := arena.NewInt(1)
+
+	garbage_collector.Collect()
+	garbage_collector.Step()
+
+	if !garbage_collector.IsMarking() || len(garbage_collector.gray_objects) != 1 {
+		t.Fatalf("marking is not in progress after a root is scanned")
+	}
+
+	scanned_root := roots[0]
+	if garbage_collector.gray_objects[0] == roots[0] {
+		scanned_root = roots[1]
+	}
+
+	storePointer(arena, scanned_root, pointee)
+
+	for garbage_collector.IsMarking() {
+		garbage_collector.Step()
+	}
+
+	if !isAlive(arena, pointee) {
+		t.Errorf("object stored to a scanned root is freed")
+	}
+	if barrier_shades := garbage_collector.StatFactory().Value("barrier_shades"); barrier_shades != 1 {
+		t.Errorf("%d objects are shaded by the barrier", barrier_shades)
+	}
+}
+
+// NOTE: a minor collection does not trace the old root, so that the young pointee is only kept by
+// the remembered set
+func TestGarbageCollectorRememberedSet(t *testing.T) {
+	garbage_collector, arena, frame_chain := newGarbageCollector(true, 0)
+
+	root := newRoot(arena, frame_chain, "root")
+	garbage_collector.Collect()
+
+	if root.Generation() != base.OLD {
+		t.Fatalf("root is not promoted by a minor collection")
+	}
+
+	pointee := arena.NewInt(1)
+	garbage := arena.NewInt(2)
+	storePointer(arena, root, pointee)
+
+	garbage_collector.Collect()
+
+	if garbage_collector.StatFactory().Value("minor_collections") != 2 {
+		t.Fatalf("%d minor collections are run", garbage_collector.StatFactory().Value("minor_collections"))
+	}
+	if !isAlive(arena, pointee) || isAlive(arena, garbage) {
+		t.Errorf("minor collection does not keep the remembered object alone")
+	}
+	if pointee.Generation() != base.OLD {
+		t.Errorf("remembered object is not promoted")
+	}
+}
+
+type hostClock struct {
+	cycles int64
+}
+
+func (this *hostClock) Cycles() int64 {
+	return this.cycles
+}
+
+func (this *hostClock) Collect(num_objects int64) {
+	this.cycles += 10 * num_objects
+}
+
+// NOTE: the bytecodes between the two collections take 1000 host cycles, and every pause takes 10
+// cycles per object it visits
+func TestGarbageCollectorPauseCycles(t *testing.T) {
+	garbage_collector, arena, frame_chain := newGarbageCollector(false, 0)
+
+	host_clock := new(hostClock)
+	garbage_collector.ConnectHostClock(host_clock)
+
+	root := newRoot(arena, frame_chain, "root")
+	storePointer(arena, root, arena.NewInt(1))
+	arena.NewInt(2)
+
+	garbage_collector.Collect()
+	first_pause_cycles := host_clock.Cycles()
+
+	host_clock.cycles += 1000
+	garbage_collector.Collect()
+	second_pause_cycles := host_clock.Cycles() - 1000 - first_pause_cycles
+
+	stat_factory := garbage_collector.StatFactory()
+	if first_pause_cycles == 0 || first_pause_cycles <= second_pause_cycles {
+		t.Fatalf("pauses take %d and %d cycles", first_pause_cycles, second_pause_cycles)
+	}
+	if pause_cycles := stat_factory.Value("pause_cycles"); pause_cycles != host_clock.Cycles()-1000 {
+		t.Errorf("%d pause cycles are counted", pause_cycles)
+	}
+	max_pause_cycles := stat_factory.Value("max_pause_cycles")
+	if max_pause_cycles != first_pause_cycles {
+		t.Errorf("longest pause takes %d cycles", max_pause_cycles)
+	}
+
+	garbage_collector.UpdateStats()
+	throughput := 1000 * 1000 / host_clock.Cycles()
+	throughput_permille := stat_factory.Value("throughput_permille")
+	if throughput_permille != throughput {
+		t.Errorf("throughput is %d permille, but %d is expected", throughput_permille, throughput)
+	}
+}
@@ -5,8 +5,22 @@ import (
 	"uPIMulator/src/encoding"
 )
 
+// NOTE: a memory tracker observes the reads and writes of the memory, e.g. to time them
+type MemoryTracker interface {
+	Load(address int64, size int64)
+	Store(address int64, size int64)
+}
+
+// NOTE: a write barrier observes the bytes written to the memory, e.g. to find the pointers in them
+type WriteBarrier interface {
+	Barrier(address int64, byte_stream *encoding.ByteStream)
+}
+
 type Memory struct {
 	byte_stream *encoding.ByteStream
+
+	memory_tracker MemoryTracker
+	write_barrier  WriteBarrier
 }
 
 func (this *Memory) Init(size int64) {
@@ -16,6 +30,27 @@ func (this *Memory) Init(size int64) {
 	for i := int64(0); i < size; i++ {
 		this.byte_stream.Append(0)
 	}
+
+	this.memory_tracker = nil
+	this.write_barrier = nil
+}
+
+func (this *Memory) ConnectMemoryTracker(memory_tracker MemoryTracker) {
+	if this.memory_tracker != nil {
+		err := errors.New("memory tracker is already set")
+		panic(err)
+	}
+
+	this.memory_tracker = memory_tracker
+}
+
+func (this *Memory) ConnectWriteBarrier(write_barrier WriteBarrier) {
+	if this.write_barrier != nil {
+		err := errors.New("write barrier is already set")
+		panic(err)
+	}
+
+	this.write_barrier = write_barrier
 }
 
 func (this *Memory) Size() int64 {
@@ -34,6 +69,15 @@ func (this *Memory) Resize(size int64) {
 }
 
 func (this *Memory) Read(address int64, size int64) *encoding.ByteStream {
+	if this.memory_tracker != nil {
+		this.memory_tracker.Load(address, size)
+	}
+
+	return this.Peek(address, size)
+}
+
+// NOTE: Peek reads the memory without notifying the memory tracker
+func (this *Memory) Peek(address int64, size int64) *encoding.ByteStream {
 	for address+size >= this.byte_stream.Size() {
 		this.Resize(2 * this.byte_stream.Size())
 	}
@@ -54,6 +98,14 @@ func (this *Memory) Write(address int64, size int64, byte_stream *encoding.ByteS
 		panic(err)
 	}
 
+	if this.memory_tracker != nil {
+		this.memory_tracker.Store(address, size)
+	}
+
+	if this.write_barrier != nil {
+		this.write_barrier.Barrier(address, byte_stream)
+	}
+
 	for address+size >= this.byte_stream.Size() {
 		this.Resize(2 * this.byte_stream.Size())
 	}
@@ -62,3 +114,18 @@ func (this *Memory) Write(address int64, size int64, byte_stream *encoding.ByteS
 		this.byte_stream.Set(int(address+i), byte_stream.Get(int(i)))
 	}
 }
+
+// NOTE: Zeros clears the memory as a write of zeros without building a byte stream of them
+func (this *Memory) Zeros(address int64, size int64) {
+	if this.memory_tracker != nil {
+		this.memory_tracker.Store(address, size)
+	}
+
+	for address+size >= this.byte_stream.Size() {
+		this.Resize(2 * this.byte_stream.Size())
+	}
+
+	for i := int64(0); i < size; i++ {
+		this.byte_stream.Set(int(address+i), 0)
+	}
+}
@@ -3,16 +3,51 @@ package arena
 import (
 	"errors"
 	"fmt"
+	"math/bits"
 	"slices"
-	"uPIMulator/src/encoding"
 	"uPIMulator/src/host/vm/base"
 	"uPIMulator/src/misc"
 )
 
+const (
+	POOL_ALIGNMENT         = 8
+	NUM_SMALL_SIZE_CLASSES = 32
+	NUM_LARGE_SIZE_CLASSES = 64
+)
+
+// NOTE: an allocation tracker observes the objects that the pool allocates, e.g. to collect them
+type AllocationTracker interface {
+	Allocate(object *base.Object)
+}
+
+// NOTE: the pool is a segregated-fit allocator over a heap that grows upward from the bank offset,
+// so that every object keeps a flat VM address that the memory mapping understands. Blocks are
+// multiples of the alignment. A small block (up to NUM_SMALL_SIZE_CLASSES alignments) is recycled
+// through the exact-fit free list of its size, while a large block is coalesced with its free
+// neighbors and recycled through the free list of its power-of-two size class, which is kept
+// sorted by address so that the placement of objects is deterministic. A free block that reaches
+// the top of the heap shrinks the heap instead, together with the free blocks of either kind that
+// end at the new top, so that the block right below the top is always live.
 type Pool struct {
-	offset  int64
-	objects []*base.Object
-	memory  *Memory
+	offset int64
+	top    int64
+
+	objects map[int64]*base.Object
+	blocks  map[int64]int64
+
+	small_free_lists  [][]int64
+	small_free_blocks map[int64]int64
+	small_free_ends   map[int64]int64
+
+	large_free_lists  [][]int64
+	large_free_blocks map[int64]int64
+	large_free_ends   map[int64]int64
+
+	memory *Memory
+
+	allocation_tracker AllocationTracker
+
+	stat_factory *misc.StatFactory
 }
 
 func (this *Pool) Init() {
@@ -20,62 +55,89 @@ func (this *Pool) Init() {
 	config_loader.Init()
 
 	this.offset = config_loader.VmBankOffset()
+	this.top = this.offset
+
+	this.objects = make(map[int64]*base.Object)
+	this.blocks = make(map[int64]int64)
+
+	this.small_free_lists = make([][]int64, NUM_SMALL_SIZE_CLASSES)
+	for i := 0; i < NUM_SMALL_SIZE_CLASSES; i++ {
+		this.small_free_lists[i] = make([]int64, 0)
+	}
+
+	this.small_free_blocks = make(map[int64]int64)
+	this.small_free_ends = make(map[int64]int64)
+
+	this.large_free_lists = make([][]int64, NUM_LARGE_SIZE_CLASSES)
+	for i := 0; i < NUM_LARGE_SIZE_CLASSES; i++ {
+		this.large_free_lists[i] = make([]int64, 0)
+	}
 
-	this.objects = make([]*base.Object, 0)
+	this.large_free_blocks = make(map[int64]int64)
+	this.large_free_ends = make(map[int64]int64)
 
 	this.memory = new(Memory)
 	this.memory.Init(config_loader.VmMemorySize())
+
+	this.allocation_tracker = nil
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("Pool")
 }
 
 func (this *Pool) Memory() *Memory {
 	return this.memory
 }
 
+func (this *Pool) ConnectAllocationTracker(allocation_tracker AllocationTracker) {
+	if this.allocation_tracker != nil {
+		err := errors.New("allocation tracker is already set")
+		panic(err)
+	}
+
+	this.allocation_tracker = allocation_tracker
+}
+
+func (this *Pool) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
 func (this *Pool) Alloc(object_type base.ObjectType, size int64) *base.Object {
-	cur_offset := this.offset
-	if len(this.objects) == 0 {
-		object := new(base.Object)
-		object.Init(object_type, cur_offset, size)
-		this.objects = append(this.objects, object)
+	if size <= 0 {
+		err := errors.New("size <= 0")
+		panic(err)
+	}
 
-		this.DoZeros(object)
+	block_size := this.BlockSize(size)
 
-		return object
+	var address int64
+	if this.IsSmall(block_size) {
+		address = this.AllocSmall(block_size)
+		this.stat_factory.Increment("small_allocs", 1)
 	} else {
-		for _, obj := range this.objects {
-			if cur_offset+size <= obj.Address() {
-				object := new(base.Object)
-				object.Init(object_type, cur_offset, size)
-				this.objects = append(this.objects, object)
-
-				sort_fn := func(obj1 *base.Object, obj2 *base.Object) int {
-					if obj1.Address() < obj2.Address() {
-						return -1
-					} else if obj1.Address() == obj2.Address() {
-						return 0
-					} else {
-						return 1
-					}
-				}
-
-				slices.SortFunc(this.objects, sort_fn)
+		address = this.AllocLarge(block_size)
+		this.stat_factory.Increment("large_allocs", 1)
+	}
 
-				this.DoZeros(object)
+	object := new(base.Object)
+	object.Init(object_type, address, size)
 
-				return object
-			} else {
-				cur_offset = obj.Address() + obj.Size()
-			}
-		}
+	this.objects[address] = object
+	this.blocks[address] = block_size
 
-		object := new(base.Object)
-		object.Init(object_type, cur_offset, size)
-		this.objects = append(this.objects, object)
+	this.stat_factory.Increment("allocs", 1)
+	this.stat_factory.Increment("alloc_bytes", size)
+	this.stat_factory.Increment("live_objects", 1)
+	this.stat_factory.Increment("live_bytes", size)
+	this.stat_factory.Increment("internal_fragmentation_bytes", block_size-size)
 
-		this.DoZeros(object)
+	this.DoZeros(object)
 
-		return object
+	if this.allocation_tracker != nil {
+		this.allocation_tracker.Allocate(object)
 	}
+
+	return object
 }
 
 func (this *Pool) Free(address int64) {
@@ -85,80 +147,283 @@ func (this *Pool) Free(address int64) {
 		panic(err)
 	}
 
-	for i, object := range this.objects {
-		if object.Address() == address {
-			this.objects = append(this.objects[:i], this.objects[i+1:]...)
-			break
-		}
+	object := this.objects[address]
+	block_size := this.blocks[address]
+
+	delete(this.objects, address)
+	delete(this.blocks, address)
+
+	this.stat_factory.Increment("frees", 1)
+	this.stat_factory.Increment("live_objects", -1)
+	this.stat_factory.Increment("live_bytes", -object.Size())
+	this.stat_factory.Increment("internal_fragmentation_bytes", -(block_size - object.Size()))
+
+	if this.IsSmall(block_size) {
+		this.FreeSmall(address, block_size)
+	} else {
+		this.FreeLarge(address, block_size)
 	}
 }
 
 func (this *Pool) HasObject(address int64) bool {
-	for _, object := range this.objects {
-		if object.Address() == address {
-			return true
-		}
-	}
-	return false
+	_, found := this.objects[address]
+	return found
 }
 
 func (this *Pool) Object(address int64) *base.Object {
-	if !this.HasObject(address) {
+	object, found := this.objects[address]
+
+	if !found {
 		err_msg := fmt.Sprintf("object with address (%d) is not found", address)
 		err := errors.New(err_msg)
 		panic(err)
 	}
 
+	return object
+}
+
+func (this *Pool) NumObjects() int {
+	return len(this.objects)
+}
+
+// NOTE: the objects are sorted by address so that walking them is deterministic
+func (this *Pool) Objects() []*base.Object {
+	objects := make([]*base.Object, 0, len(this.objects))
 	for _, object := range this.objects {
-		if object.Address() == address {
-			return object
+		objects = append(objects, object)
+	}
+
+	sort_fn := func(obj1 *base.Object, obj2 *base.Object) int {
+		if obj1.Address() < obj2.Address() {
+			return -1
+		} else if obj1.Address() == obj2.Address() {
+			return 0
+		} else {
+			return 1
 		}
 	}
 
-	return nil
+	slices.SortFunc(objects, sort_fn)
+
+	return objects
+}
+
+func (this *Pool) HeapSize() int64 {
+	return this.top - this.offset
+}
+
+// NOTE: the fragmentation is the fraction of the heap that is not occupied by the bytes of a live
+// object, i.e. the free blocks and the padding of the live blocks
+func (this *Pool) Fragmentation() float64 {
+	if this.HeapSize() == 0 {
+		return 0
+	}
+
+	live_bytes := this.stat_factory.Value("live_bytes")
+	return float64(this.HeapSize()-live_bytes) / float64(this.HeapSize())
+}
+
+func (this *Pool) BlockSize(size int64) int64 {
+	return (size + POOL_ALIGNMENT - 1) / POOL_ALIGNMENT * POOL_ALIGNMENT
+}
+
+func (this *Pool) IsSmall(block_size int64) bool {
+	return block_size <= NUM_SMALL_SIZE_CLASSES*POOL_ALIGNMENT
+}
+
+func (this *Pool) SmallSizeClass(block_size int64) int {
+	return int(block_size/POOL_ALIGNMENT) - 1
 }
 
-func (this *Pool) HasLastObject(address int64) bool {
-	for i := len(this.objects) - 1; i >= 0; i-- {
-		object := this.objects[i]
+func (this *Pool) LargeSizeClass(block_size int64) int {
+	return bits.Len64(uint64(block_size)) - 1
+}
+
+// NOTE: a small free block that the heap shrinks over is only dropped from the free blocks, so
+// that an address on a free list is stale unless it is still a free block of the same size
+func (this *Pool) AllocSmall(block_size int64) int64 {
+	size_class := this.SmallSizeClass(block_size)
+
+	for len(this.small_free_lists[size_class]) > 0 {
+		free_list := this.small_free_lists[size_class]
+
+		address := free_list[len(free_list)-1]
+		this.small_free_lists[size_class] = free_list[:len(free_list)-1]
 
-		if object.Address() == address {
-			return true
+		if free_block_size, found := this.small_free_blocks[address]; found &&
+			free_block_size == block_size {
+			this.RemoveSmallFreeBlock(address)
+
+			this.stat_factory.Increment("free_list_hits", 1)
+
+			return address
 		}
 	}
 
-	return false
+	return this.Bump(block_size)
 }
 
-func (this *Pool) LastObject(address int64) *base.Object {
-	if !this.HasLastObject(address) {
-		err_msg := fmt.Sprintf("object with address (%d) is not found", address)
+func (this *Pool) FreeSmall(address int64, block_size int64) {
+	if address+block_size == this.top {
+		this.Shrink(address)
+		return
+	}
+
+	size_class := this.SmallSizeClass(block_size)
+	this.small_free_lists[size_class] = append(this.small_free_lists[size_class], address)
+
+	this.small_free_blocks[address] = block_size
+	this.small_free_ends[address+block_size] = address
+
+	this.stat_factory.Increment("free_bytes", block_size)
+}
+
+func (this *Pool) RemoveSmallFreeBlock(address int64) {
+	block_size, found := this.small_free_blocks[address]
+
+	if !found {
+		err_msg := fmt.Sprintf("free block with address (%d) is not found", address)
 		err := errors.New(err_msg)
 		panic(err)
 	}
 
-	for i := len(this.objects) - 1; i >= 0; i-- {
-		object := this.objects[i]
+	delete(this.small_free_blocks, address)
+	delete(this.small_free_ends, address+block_size)
+
+	this.stat_factory.Increment("free_bytes", -block_size)
+}
+
+func (this *Pool) AllocLarge(block_size int64) int64 {
+	first_size_class := this.LargeSizeClass(block_size)
+
+	for size_class := first_size_class; size_class < NUM_LARGE_SIZE_CLASSES; size_class++ {
+		for _, address := range this.large_free_lists[size_class] {
+			free_block_size := this.large_free_blocks[address]
+
+			if free_block_size >= block_size {
+				this.RemoveLargeFreeBlock(address)
+
+				if free_block_size > block_size {
+					this.Split(address+block_size, free_block_size-block_size)
+				}
+
+				this.stat_factory.Increment("free_list_hits", 1)
 
-		if object.Address() == address {
-			return object
+				return address
+			}
 		}
 	}
 
-	return nil
+	return this.Bump(block_size)
 }
 
-func (this *Pool) Objects() []*base.Object {
-	return this.objects
+func (this *Pool) FreeLarge(address int64, block_size int64) {
+	if prev_address, found := this.large_free_ends[address]; found {
+		prev_block_size := this.large_free_blocks[prev_address]
+		this.RemoveLargeFreeBlock(prev_address)
+
+		address = prev_address
+		block_size += prev_block_size
+
+		this.stat_factory.Increment("coalesces", 1)
+	}
+
+	if next_block_size, found := this.large_free_blocks[address+block_size]; found {
+		this.RemoveLargeFreeBlock(address + block_size)
+
+		block_size += next_block_size
+
+		this.stat_factory.Increment("coalesces", 1)
+	}
+
+	if address+block_size == this.top {
+		this.Shrink(address)
+	} else {
+		this.InsertLargeFreeBlock(address, block_size)
+	}
 }
 
-func (this *Pool) DoZeros(object *base.Object) {
-	byte_stream := new(encoding.ByteStream)
-	byte_stream.Init()
+// NOTE: the heap shrinks down to the address of a freed block and then over every free block that
+// ends at the new top
+func (this *Pool) Shrink(address int64) {
+	for {
+		this.stat_factory.Increment("heap_bytes", -(this.top - address))
+		this.top = address
+
+		if prev_address, found := this.large_free_ends[this.top]; found {
+			this.RemoveLargeFreeBlock(prev_address)
+			address = prev_address
+		} else if prev_address, found := this.small_free_ends[this.top]; found {
+			this.RemoveSmallFreeBlock(prev_address)
+			address = prev_address
+		} else {
+			break
+		}
+	}
+}
+
+// NOTE: the remainder of a split large block is small once it is no larger than the largest small
+// size class, in which case it is handed to the free list of its size for good
+func (this *Pool) Split(address int64, block_size int64) {
+	this.stat_factory.Increment("splits", 1)
+
+	if this.IsSmall(block_size) {
+		this.FreeSmall(address, block_size)
+	} else {
+		this.InsertLargeFreeBlock(address, block_size)
+	}
+}
+
+func (this *Pool) InsertLargeFreeBlock(address int64, block_size int64) {
+	size_class := this.LargeSizeClass(block_size)
+	free_list := this.large_free_lists[size_class]
+
+	pos, _ := slices.BinarySearch(free_list, address)
+	this.large_free_lists[size_class] = slices.Insert(free_list, pos, address)
+
+	this.large_free_blocks[address] = block_size
+	this.large_free_ends[address+block_size] = address
+
+	this.stat_factory.Increment("free_bytes", block_size)
+}
+
+func (this *Pool) RemoveLargeFreeBlock(address int64) {
+	block_size, found := this.large_free_blocks[address]
+
+	if !found {
+		err_msg := fmt.Sprintf("free block with address (%d) is not found", address)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	size_class := this.LargeSizeClass(block_size)
+	free_list := this.large_free_lists[size_class]
+
+	pos, _ := slices.BinarySearch(free_list, address)
+	this.large_free_lists[size_class] = slices.Delete(free_list, pos, pos+1)
 
-	for i := int64(0); i < object.Size(); i++ {
-		byte_stream.Append(0)
+	delete(this.large_free_blocks, address)
+	delete(this.large_free_ends, address+block_size)
+
+	this.stat_factory.Increment("free_bytes", -block_size)
+}
+
+func (this *Pool) Bump(block_size int64) int64 {
+	address := this.top
+	this.top += block_size
+
+	this.stat_factory.Increment("heap_bytes", block_size)
+
+	if this.HeapSize() > this.stat_factory.Value("peak_heap_bytes") {
+		this.stat_factory.Increment(
+			"peak_heap_bytes",
+			this.HeapSize()-this.stat_factory.Value("peak_heap_bytes"),
+		)
 	}
 
-	this.memory.Write(object.Address(), object.Size(), byte_stream)
+	return address
+}
+
+func (this *Pool) DoZeros(object *base.Object) {
+	this.memory.Zeros(object.Address(), object.Size())
 }
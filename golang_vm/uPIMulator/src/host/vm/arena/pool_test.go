@@ -0,0 +1,171 @@
+package arena
+
+import (
+	"math/rand"
+	"testing"
+	"uPIMulator/src/host/vm/base"
+)
+
+func isPanicking(function func()) (is_panicking bool) {
+	defer func() {
+		if recover() != nil {
+			is_panicking = true
+		}
+	}()
+
+	function()
+	return false
+}
+
+func newPool() *Pool {
+	pool := new(Pool)
+	pool.Init()
+	return pool
+}
+
+// NOTE: sizes span both small and large blocks, and some large blocks are split on reuse
+func randomSize(random *rand.Rand) int64 {
+	if random.Intn(4) == 0 {
+		return int64(1 + random.Intn(4096))
+	} else {
+		return int64(1 + random.Intn(NUM_SMALL_SIZE_CLASSES*POOL_ALIGNMENT))
+	}
+}
+
+// NOTE: every live block lies in the heap apart from the others, and the heap is made of the live
+// blocks and the free blocks
+func checkBlocks(t *testing.T, pool *Pool) {
+	t.Helper()
+
+	objects := pool.Objects()
+
+	live_block_bytes := int64(0)
+	for i, object := range objects {
+		block_size := pool.BlockSize(object.Size())
+		live_block_bytes += block_size
+
+		if object.Address() < pool.offset || object.Address()+block_size > pool.top {
+			t.Fatalf("object (%d) is out of the heap", object.Address())
+		}
+
+		if i+1 < len(objects) && object.Address()+block_size > objects[i+1].Address() {
+			t.Fatalf("object (%d) overlaps object (%d)", object.Address(), objects[i+1].Address())
+		}
+	}
+
+	free_bytes := pool.StatFactory().Value("free_bytes")
+	if live_block_bytes+free_bytes != pool.HeapSize() {
+		t.Fatalf(
+			"heap of %d bytes holds %d live and %d free bytes",
+			pool.HeapSize(),
+			live_block_bytes,
+			free_bytes,
+		)
+	}
+}
+
+func TestPoolNoOverlaps(t *testing.T) {
+	pool := newPool()
+	random := rand.New(rand.NewSource(0))
+
+	addresses := make([]int64, 0)
+	for i := 0; i < 4096; i++ {
+		if len(addresses) > 0 && random.Intn(3) == 0 {
+			pos := random.Intn(len(addresses))
+			pool.Free(addresses[pos])
+			addresses = append(addresses[:pos], addresses[pos+1:]...)
+		} else {
+			addresses = append(addresses, pool.Alloc(base.UNTEMPORARY, randomSize(random)).Address())
+		}
+
+		checkBlocks(t, pool)
+	}
+}
+
+func TestPoolLookup(t *testing.T) {
+	pool := newPool()
+	random := rand.New(rand.NewSource(1))
+
+	objects := make([]*base.Object, 0)
+	for i := 0; i < 256; i++ {
+		objects = append(objects, pool.Alloc(base.TEMPORARY, randomSize(random)))
+	}
+
+	for i, object := range objects {
+		if !pool.HasObject(object.Address()) || pool.Object(object.Address()) != object {
+			t.Errorf("object %d is not found at its address", i)
+		}
+
+		if i%2 == 0 {
+			pool.Free(object.Address())
+		}
+	}
+
+	if pool.NumObjects() != len(objects)/2 {
+		t.Errorf("%d objects are live, want %d", pool.NumObjects(), len(objects)/2)
+	}
+
+	for i, object := range objects {
+		if i%2 == 1 {
+			if pool.Object(object.Address()) != object {
+				t.Errorf("object %d is not found after its neighbors are freed", i)
+			}
+		} else if pool.HasObject(object.Address()) &&
+			pool.Object(object.Address()) == object {
+			t.Errorf("object %d is found after it is freed", i)
+		}
+	}
+
+	for i := 0; i < len(objects); i += 2 {
+		reused := pool.Alloc(base.TEMPORARY, objects[i].Size())
+		if pool.Object(reused.Address()) != reused {
+			t.Errorf("reused object is not found at its address")
+		}
+		pool.Free(reused.Address())
+	}
+
+	if !isPanicking(func() { pool.Free(objects[0].Address()) }) {
+		t.Errorf("a freed object is freed again")
+	}
+	if !isPanicking(func() { pool.Object(objects[0].Address()) }) {
+		t.Errorf("a freed object is looked up")
+	}
+}
+
+// NOTE: the heap shrinks to nothing whichever order its small and large blocks are freed in
+func TestPoolShrinks(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+
+	orders := map[string]func(addresses []int64){
+		"allocation": func(addresses []int64) {},
+		"reverse": func(addresses []int64) {
+			for i, j := 0, len(addresses)-1; i < j; i, j = i+1, j-1 {
+				addresses[i], addresses[j] = addresses[j], addresses[i]
+			}
+		},
+		"random": func(addresses []int64) {
+			random.Shuffle(len(addresses), func(i int, j int) {
+				addresses[i], addresses[j] = addresses[j], addresses[i]
+			})
+		},
+	}
+
+	for name, order := range orders {
+		pool := newPool()
+
+		addresses := make([]int64, 0)
+		for i := 0; i < 1024; i++ {
+			addresses = append(addresses, pool.Alloc(base.UNTEMPORARY, randomSize(random)).Address())
+		}
+
+		order(addresses)
+
+		for _, address := range addresses {
+			pool.Free(address)
+		}
+
+		if pool.HeapSize() != 0 || pool.StatFactory().Value("free_bytes") != 0 {
+			t.Errorf("heap is %d bytes after every object is freed in %s order", pool.HeapSize(), name)
+		}
+	}
+}
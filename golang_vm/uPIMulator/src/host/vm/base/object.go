@@ -12,12 +12,22 @@ const (
 	UNTEMPORARY
 )
 
+type Generation int
+
+const (
+	YOUNG Generation = iota
+	OLD
+)
+
 type Object struct {
 	object_type   ObjectType
 	type_variable *type_system.TypeVariable
 
 	address int64
 	size    int64
+
+	is_marked  bool
+	generation Generation
 }
 
 func (this *Object) Init(object_type ObjectType, address int64, size int64) {
@@ -33,6 +43,9 @@ func (this *Object) Init(object_type ObjectType, address int64, size int64) {
 
 	this.address = address
 	this.size = size
+
+	this.is_marked = false
+	this.generation = YOUNG
 }
 
 func (this *Object) ObjectType() ObjectType {
@@ -63,3 +76,23 @@ func (this *Object) Address() int64 {
 func (this *Object) Size() int64 {
 	return this.size
 }
+
+func (this *Object) IsMarked() bool {
+	return this.is_marked
+}
+
+func (this *Object) Mark() {
+	this.is_marked = true
+}
+
+func (this *Object) Unmark() {
+	this.is_marked = false
+}
+
+func (this *Object) Generation() Generation {
+	return this.generation
+}
+
+func (this *Object) Promote() {
+	this.generation = OLD
+}
@@ -0,0 +1,182 @@
+package cpu
+
+import (
+	"errors"
+	"fmt"
+	"uPIMulator/src/misc"
+)
+
+type CacheLine struct {
+	line_address int64
+	is_valid     bool
+	is_dirty     bool
+	last_access  int64
+}
+
+// NOTE: a set-associative cache with LRU replacement that only keeps the tags of its lines, since
+// the host core reads and writes the arena memory functionally
+type Cache struct {
+	name string
+
+	size          int64
+	associativity int64
+	line_size     int64
+	latency       int64
+
+	num_sets int64
+	sets     [][]*CacheLine
+
+	num_accesses int64
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *Cache) Init(
+	name string,
+	size int64,
+	associativity int64,
+	line_size int64,
+	latency int64,
+) {
+	if size <= 0 || associativity <= 0 || latency < 0 {
+		err_msg := fmt.Sprintf(
+			"cache (%s) has an invalid size (%d), associativity (%d) or latency (%d)",
+			name,
+			size,
+			associativity,
+			latency,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if size%(associativity*line_size) != 0 {
+		err_msg := fmt.Sprintf(
+			"cache (%s) size (%d) is not a multiple of associativity (%d) * line size (%d)",
+			name,
+			size,
+			associativity,
+			line_size,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.name = name
+
+	this.size = size
+	this.associativity = associativity
+	this.line_size = line_size
+	this.latency = latency
+
+	this.num_sets = size / (associativity * line_size)
+	this.sets = make([][]*CacheLine, 0)
+	for i := int64(0); i < this.num_sets; i++ {
+		set := make([]*CacheLine, 0)
+		for j := int64(0); j < associativity; j++ {
+			cache_line := new(CacheLine)
+			cache_line.line_address = -1
+			cache_line.is_valid = false
+			cache_line.is_dirty = false
+			cache_line.last_access = -1
+
+			set = append(set, cache_line)
+		}
+
+		this.sets = append(this.sets, set)
+	}
+
+	this.num_accesses = 0
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+}
+
+func (this *Cache) Name() string {
+	return this.name
+}
+
+func (this *Cache) Latency() int64 {
+	return this.latency
+}
+
+func (this *Cache) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *Cache) Set(line_address int64) []*CacheLine {
+	return this.sets[(line_address/this.line_size)%this.num_sets]
+}
+
+func (this *Cache) Find(line_address int64) *CacheLine {
+	for _, cache_line := range this.Set(line_address) {
+		if cache_line.is_valid && cache_line.line_address == line_address {
+			return cache_line
+		}
+	}
+	return nil
+}
+
+func (this *Cache) Lookup(line_address int64) bool {
+	this.num_accesses++
+
+	if cache_line := this.Find(line_address); cache_line != nil {
+		cache_line.last_access = this.num_accesses
+		this.stat_factory.Increment("hits", 1)
+		return true
+	} else {
+		this.stat_factory.Increment("misses", 1)
+		return false
+	}
+}
+
+func (this *Cache) MarkDirty(line_address int64) {
+	cache_line := this.Find(line_address)
+
+	if cache_line == nil {
+		err_msg := fmt.Sprintf("cache (%s) does not hold line (%d)", this.name, line_address)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	cache_line.is_dirty = true
+}
+
+// NOTE: Fill returns the address of the evicted line and whether it was dirty, or -1 if an invalid
+// way was filled
+func (this *Cache) Fill(line_address int64) (int64, bool) {
+	if this.Find(line_address) != nil {
+		err_msg := fmt.Sprintf("cache (%s) already holds line (%d)", this.name, line_address)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.num_accesses++
+
+	var victim *CacheLine = nil
+	for _, cache_line := range this.Set(line_address) {
+		if !cache_line.is_valid {
+			victim = cache_line
+			break
+		} else if victim == nil || cache_line.last_access < victim.last_access {
+			victim = cache_line
+		}
+	}
+
+	evicted_line_address := int64(-1)
+	is_evicted_dirty := false
+	if victim.is_valid {
+		evicted_line_address = victim.line_address
+		is_evicted_dirty = victim.is_dirty
+
+		this.stat_factory.Increment("evictions", 1)
+		if victim.is_dirty {
+			this.stat_factory.Increment("dirty_evictions", 1)
+		}
+	}
+
+	victim.line_address = line_address
+	victim.is_valid = true
+	victim.is_dirty = false
+	victim.last_access = this.num_accesses
+
+	return evicted_line_address, is_evicted_dirty
+}
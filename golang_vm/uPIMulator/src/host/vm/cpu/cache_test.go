@@ -0,0 +1,93 @@
+package cpu
+
+import (
+	"testing"
+)
+
+// NOTE: two sets of two ways of 64-byte lines, so that lines 0, 128 and 256 map to the same set
+func newCache() *Cache {
+	cache := new(Cache)
+	cache.Init("TestCache", 256, 2, 64, 4)
+	return cache
+}
+
+func TestCacheHitsAndMisses(t *testing.T) {
+	cache := newCache()
+
+	if cache.Lookup(0) {
+		t.Fatalf("line 0 hits in a cold cache")
+	}
+
+	if evicted_line_address, _ := cache.Fill(0); evicted_line_address != -1 {
+		t.Fatalf("line %d is evicted from a cold cache", evicted_line_address)
+	}
+
+	if !cache.Lookup(0) {
+		t.Fatalf("line 0 misses after it is filled")
+	}
+
+	if cache.Lookup(64) {
+		t.Fatalf("line 64 hits without being filled")
+	}
+
+	if hits := cache.StatFactory().Value("hits"); hits != 1 {
+		t.Fatalf("%d hits are counted", hits)
+	}
+
+	if misses := cache.StatFactory().Value("misses"); misses != 2 {
+		t.Fatalf("%d misses are counted", misses)
+	}
+}
+
+func TestCacheLruEviction(t *testing.T) {
+	cache := newCache()
+
+	cache.Fill(0)
+	cache.Fill(128)
+
+	// NOTE: touching line 0 makes line 128 the least recently used way of the set
+	cache.Lookup(0)
+
+	evicted_line_address, is_evicted_dirty := cache.Fill(256)
+	if evicted_line_address != 128 || is_evicted_dirty {
+		t.Fatalf("line %d (dirty: %t) is evicted instead of line 128", evicted_line_address,
+			is_evicted_dirty)
+	}
+
+	if cache.Find(0) == nil || cache.Find(256) == nil || cache.Find(128) != nil {
+		t.Fatalf("lines 0 and 256 are not the ones held after the eviction")
+	}
+
+	// NOTE: line 64 maps to the other set, which still has invalid ways
+	if evicted_line_address, _ := cache.Fill(64); evicted_line_address != -1 {
+		t.Fatalf("line %d is evicted from a set with invalid ways", evicted_line_address)
+	}
+
+	if evictions := cache.StatFactory().Value("evictions"); evictions != 1 {
+		t.Fatalf("%d evictions are counted", evictions)
+	}
+}
+
+func TestCacheDirtyEviction(t *testing.T) {
+	cache := newCache()
+
+	cache.Fill(0)
+	cache.MarkDirty(0)
+	cache.Fill(128)
+
+	evicted_line_address, is_evicted_dirty := cache.Fill(256)
+	if evicted_line_address != 0 || !is_evicted_dirty {
+		t.Fatalf("line %d (dirty: %t) is evicted instead of the dirty line 0",
+			evicted_line_address, is_evicted_dirty)
+	}
+
+	// NOTE: a refilled line starts clean
+	cache.Fill(0)
+	if cache.Find(0).is_dirty {
+		t.Fatalf("line 0 is dirty after it is refilled")
+	}
+
+	if dirty_evictions := cache.StatFactory().Value("dirty_evictions"); dirty_evictions != 1 {
+		t.Fatalf("%d dirty evictions are counted", dirty_evictions)
+	}
+}
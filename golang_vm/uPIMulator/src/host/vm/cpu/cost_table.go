@@ -0,0 +1,99 @@
+package cpu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"uPIMulator/src/host/abi"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/program"
+)
+
+// NOTE: the cost of a bytecode is the number of host cycles spent on its computation, while the
+// memory accesses of the bytecode are charged separately by the cache hierarchy
+type CostTable struct {
+	default_cost int64
+	object_cost  int64
+	costs        map[abi.OpCode]int64
+}
+
+func (this *CostTable) Init() {
+	this.default_cost = 1
+	this.object_cost = 10
+	this.costs = make(map[abi.OpCode]int64)
+
+	this.costs[abi.NEW_SCOPE] = 0
+	this.costs[abi.DELETE_SCOPE] = 0
+	this.costs[abi.NOP] = 0
+
+	this.costs[abi.GET_SUBSCRIPT] = 2
+	this.costs[abi.GET_ACCESS] = 1
+	this.costs[abi.GET_REFERENCE] = 2
+
+	this.costs[abi.ALLOC] = 100
+	this.costs[abi.FREE] = 50
+
+	this.costs[abi.MUL] = 3
+	this.costs[abi.DIV] = 25
+	this.costs[abi.MOD] = 25
+	this.costs[abi.SQRT] = 20
+
+	this.costs[abi.ASSIGN_STAR] = 3
+	this.costs[abi.ASSIGN_DIV] = 25
+	this.costs[abi.ASSIGN_MOD] = 25
+
+	this.costs[abi.JUMP_IF_ZERO] = 2
+	this.costs[abi.JUMP_IF_NONZERO] = 2
+	this.costs[abi.CALL] = 5
+	this.costs[abi.RETURN] = 5
+}
+
+// NOTE: each line of a cost table file overrides the cost of an op code, e.g. "DIV 40", and lines
+// starting with '#' are comments
+func (this *CostTable) Load(filepath string, app *program.App) {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(filepath)
+
+	for _, line := range file_scanner.ReadLines() {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words := strings.Fields(line)
+		if len(words) != 2 {
+			err_msg := fmt.Sprintf("cost table line (%s) is not an op code and a cost", line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		op_code := app.ConvertToOpCode(words[0])
+
+		cost, parse_err := strconv.ParseInt(words[1], 10, 64)
+		if parse_err != nil {
+			panic(parse_err)
+		} else if cost < 0 {
+			err_msg := fmt.Sprintf("cost of op code (%s) is negative", words[0])
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.costs[op_code] = cost
+	}
+}
+
+func (this *CostTable) Cost(op_code abi.OpCode) int64 {
+	if cost, found := this.costs[op_code]; found {
+		return cost
+	} else {
+		return this.default_cost
+	}
+}
+
+// NOTE: the object cost is the number of host cycles that the garbage collector spends on every
+// object it shades, scans or sweeps
+func (this *CostTable) ObjectCost() int64 {
+	return this.object_cost
+}
@@ -0,0 +1,298 @@
+package cpu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"uPIMulator/src/host/abi"
+	"uPIMulator/src/host/vm/arena"
+	"uPIMulator/src/host/vm/dram"
+	"uPIMulator/src/host/vm/dram/bank"
+	"uPIMulator/src/misc"
+)
+
+// NOTE: the host core is an in-order model that charges every bytecode its cost and every memory
+// access of the bytecode the latency of the cache hierarchy, whose misses and dirty evictions are
+// simulated on the VM DRAM
+type HostCore struct {
+	host_frequency   int64
+	memory_frequency int64
+
+	line_size int64
+
+	cost_table *CostTable
+	caches     []*Cache
+
+	memory            *arena.Memory
+	memory_controller *dram.MemoryController
+
+	is_checked_out bool
+
+	cycles int64
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *HostCore) Init(command_line_parser *misc.CommandLineParser) {
+	this.host_frequency = command_line_parser.IntParameter("host_frequency")
+	this.memory_frequency = command_line_parser.IntParameter("memory_frequency")
+
+	this.line_size = command_line_parser.IntParameter("host_cache_line_size")
+	if this.line_size <= 0 {
+		err := errors.New("host cache line size <= 0")
+		panic(err)
+	}
+
+	this.cost_table = new(CostTable)
+	this.cost_table.Init()
+
+	this.caches = make([]*Cache, 0)
+	host_caches := command_line_parser.StringParameter("host_caches")
+	for i, cache_params := range strings.Split(host_caches, ",") {
+		params := strings.Split(cache_params, ":")
+		if len(params) != 3 {
+			err_msg := fmt.Sprintf(
+				"host cache (%s) is not given as size:associativity:latency",
+				cache_params,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		int_params := make([]int64, 0)
+		for _, param := range params {
+			int_param, parse_err := strconv.ParseInt(param, 10, 64)
+			if parse_err != nil {
+				panic(parse_err)
+			}
+
+			int_params = append(int_params, int_param)
+		}
+
+		cache := new(Cache)
+		cache.Init(
+			fmt.Sprintf("HostL%dCache", i+1),
+			int_params[0],
+			int_params[1],
+			this.line_size,
+			int_params[2],
+		)
+
+		this.caches = append(this.caches, cache)
+	}
+
+	this.memory = nil
+	this.memory_controller = nil
+
+	this.is_checked_out = false
+
+	this.cycles = 0
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("HostCore")
+}
+
+func (this *HostCore) ConnectMemory(memory *arena.Memory) {
+	if this.memory != nil {
+		err := errors.New("memory is already set")
+		panic(err)
+	}
+
+	this.memory = memory
+	this.memory.ConnectMemoryTracker(this)
+}
+
+func (this *HostCore) ConnectMemoryController(memory_controller *dram.MemoryController) {
+	if this.memory_controller != nil {
+		err := errors.New("memory controller is already set")
+		panic(err)
+	}
+
+	this.memory_controller = memory_controller
+}
+
+func (this *HostCore) CostTable() *CostTable {
+	return this.cost_table
+}
+
+func (this *HostCore) Caches() []*Cache {
+	return this.caches
+}
+
+func (this *HostCore) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *HostCore) Cycles() int64 {
+	return this.cycles
+}
+
+func (this *HostCore) TimeNs() int64 {
+	return this.cycles * 1000 / this.host_frequency
+}
+
+// NOTE: only the memory accesses made while a bytecode executes are charged, so that the garbage
+// collector and the dumps of the VM do not perturb the host time
+func (this *HostCore) Checkout() {
+	this.is_checked_out = true
+}
+
+func (this *HostCore) Checkin() {
+	this.is_checked_out = false
+}
+
+func (this *HostCore) Execute(op_code abi.OpCode) {
+	cost := this.cost_table.Cost(op_code)
+
+	this.cycles += cost
+
+	this.stat_factory.Increment("bytecodes", 1)
+	this.stat_factory.Increment("compute_cycles", cost)
+}
+
+// NOTE: the garbage collector runs on the host core between bytecodes, whose memory accesses are
+// not charged, so that a collection costs the host the objects it visits only
+func (this *HostCore) Collect(num_objects int64) {
+	cycles := num_objects * this.cost_table.ObjectCost()
+
+	this.cycles += cycles
+
+	this.stat_factory.Increment("collection_cycles", cycles)
+}
+
+// NOTE: the host core waits for the DPUs without doing anything else
+func (this *HostCore) Stall(cycles int64) {
+	this.cycles += cycles
+
+	this.stat_factory.Increment("stall_cycles", cycles)
+}
+
+// NOTE: the host core waits for its transfers to and from the DPUs, which take the given number of
+// VM DRAM cycles to complete
+func (this *HostCore) Transfer(memory_cycles int64) {
+	cycles := this.HostCycles(memory_cycles)
+
+	this.cycles += cycles
+
+	this.stat_factory.Increment("transfers", 1)
+	this.stat_factory.Increment("transfer_cycles", cycles)
+}
+
+func (this *HostCore) Load(address int64, size int64) {
+	if !this.is_checked_out {
+		return
+	}
+
+	this.Access(address, size, false)
+}
+
+func (this *HostCore) Store(address int64, size int64) {
+	if !this.is_checked_out {
+		return
+	}
+
+	this.Access(address, size, true)
+}
+
+func (this *HostCore) Access(address int64, size int64, is_write bool) {
+	if size <= 0 {
+		return
+	}
+
+	begin_line_address := address - address%this.line_size
+	end_line_address := (address + size - 1) - (address+size-1)%this.line_size
+
+	line_address := begin_line_address
+	for line_address <= end_line_address {
+		this.AccessLine(line_address, is_write)
+
+		line_address += this.line_size
+	}
+}
+
+func (this *HostCore) AccessLine(line_address int64, is_write bool) {
+	cycles := int64(0)
+
+	level := len(this.caches)
+	for i, cache := range this.caches {
+		cycles += cache.Latency()
+
+		if cache.Lookup(line_address) {
+			level = i
+			break
+		}
+	}
+
+	if level == len(this.caches) {
+		cycles += this.DramAccess(bank.HOST_READ, line_address)
+	}
+
+	for i := level - 1; i >= 0; i-- {
+		this.Fill(i, line_address)
+	}
+
+	if is_write {
+		this.caches[0].MarkDirty(line_address)
+	}
+
+	this.cycles += cycles
+
+	this.stat_factory.Increment("memory_accesses", 1)
+	this.stat_factory.Increment("memory_cycles", cycles)
+}
+
+func (this *HostCore) Fill(level int, line_address int64) {
+	evicted_line_address, is_evicted_dirty := this.caches[level].Fill(line_address)
+
+	if is_evicted_dirty {
+		this.WriteBack(level+1, evicted_line_address)
+	}
+}
+
+// NOTE: write-backs are buffered and do not stall the host core, but the ones leaving the last
+// level cache still occupy the VM DRAM
+func (this *HostCore) WriteBack(level int, line_address int64) {
+	if level == len(this.caches) {
+		this.DramAccess(bank.HOST_WRITE, line_address)
+		return
+	}
+
+	if this.caches[level].Find(line_address) == nil {
+		this.Fill(level, line_address)
+	}
+
+	this.caches[level].MarkDirty(line_address)
+}
+
+func (this *HostCore) DramAccess(
+	transfer_command_type bank.TransferCommandType,
+	line_address int64,
+) int64 {
+	transfer_command := new(bank.TransferCommand)
+	transfer_command.InitHost(transfer_command_type, line_address, this.line_size)
+
+	if transfer_command_type == bank.HOST_WRITE {
+		transfer_command.SetByteStream(
+			line_address,
+			this.line_size,
+			this.memory.Peek(line_address, this.line_size),
+		)
+	}
+
+	memory_cycles := this.memory_controller.HostAccess(transfer_command)
+	cycles := this.HostCycles(memory_cycles)
+
+	if transfer_command_type == bank.HOST_READ {
+		this.stat_factory.Increment("dram_reads", 1)
+		this.stat_factory.Increment("dram_read_cycles", cycles)
+	} else {
+		this.stat_factory.Increment("dram_writes", 1)
+	}
+
+	return cycles
+}
+
+func (this *HostCore) HostCycles(memory_cycles int64) int64 {
+	return (memory_cycles*this.host_frequency + this.memory_frequency - 1) / this.memory_frequency
+}
@@ -0,0 +1,219 @@
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/encoding"
+	"uPIMulator/src/host/abi"
+	"uPIMulator/src/host/vm/arena"
+	"uPIMulator/src/host/vm/dram"
+	"uPIMulator/src/host/vm/dram/bank"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/program"
+)
+
+// NOTE: a direct-mapped L1 of two lines backed by a two-way L2 of four sets, so that lines 0, 256
+// and 512 conflict in both levels
+func newHostCore() (*HostCore, *arena.Memory, *dram.MemoryController) {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+
+	command_line_parser.AddOption(misc.INT, "host_frequency", "3200", "")
+	command_line_parser.AddOption(misc.INT, "memory_frequency", "2400", "")
+	command_line_parser.AddOption(misc.STRING, "host_caches", "128:1:4,512:2:12", "")
+	command_line_parser.AddOption(misc.INT, "host_cache_line_size", "64", "")
+
+	command_line_parser.AddOption(misc.INT, "num_vm_channels", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_vm_ranks_per_channel", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_ranks_per_channel", "1", "")
+	command_line_parser.AddOption(misc.INT, "num_vm_banks_per_rank", "2", "")
+	command_line_parser.AddOption(misc.INT, "wordline_size", "1024", "")
+	command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	command_line_parser.AddOption(misc.INT, "reorder_window_size", "256", "")
+	command_line_parser.AddOption(misc.INT, "t_rcd", "32", "")
+	command_line_parser.AddOption(misc.INT, "t_ras", "78", "")
+	command_line_parser.AddOption(misc.INT, "t_rp", "32", "")
+	command_line_parser.AddOption(misc.INT, "t_cl", "32", "")
+	command_line_parser.AddOption(misc.INT, "t_bl", "8", "")
+
+	memory := new(arena.Memory)
+	memory.Init(1024)
+
+	memory_controller := new(dram.MemoryController)
+	memory_controller.Init(command_line_parser)
+
+	host_core := new(HostCore)
+	host_core.Init(command_line_parser)
+	host_core.ConnectMemory(memory)
+	host_core.ConnectMemoryController(memory_controller)
+
+	return host_core, memory, memory_controller
+}
+
+func newByteStream(values ...uint8) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for _, value := range values {
+		byte_stream.Append(value)
+	}
+	return byte_stream
+}
+
+func TestHostCoreCacheHierarchy(t *testing.T) {
+	host_core, _, _ := newHostCore()
+	l1_cache := host_core.Caches()[0]
+	l2_cache := host_core.Caches()[1]
+
+	host_core.Load(0, 4)
+	if host_core.Cycles() != 0 {
+		t.Fatalf("a load outside of a bytecode is charged %d cycles", host_core.Cycles())
+	}
+
+	host_core.Checkout()
+
+	host_core.Load(0, 4)
+	miss_cycles := host_core.Cycles()
+	if dram_read_cycles := host_core.StatFactory().Value("dram_read_cycles"); dram_read_cycles <= 0 ||
+		miss_cycles != 4+12+dram_read_cycles {
+		t.Fatalf("a miss in both levels costs %d cycles of which %d are DRAM cycles", miss_cycles,
+			dram_read_cycles)
+	}
+
+	host_core.Load(4, 4)
+	if host_core.Cycles()-miss_cycles != 4 {
+		t.Fatalf("an L1 hit costs %d cycles", host_core.Cycles()-miss_cycles)
+	}
+
+	// NOTE: line 128 evicts line 0 from the L1 only, so that line 0 then hits in the L2
+	host_core.Load(128, 4)
+	cycles := host_core.Cycles()
+	host_core.Load(0, 4)
+	if host_core.Cycles()-cycles != 4+12 {
+		t.Fatalf("an L2 hit costs %d cycles", host_core.Cycles()-cycles)
+	}
+
+	// NOTE: an access spanning two lines looks up both of them, here an L1 hit and a miss
+	host_core.Load(60, 8)
+
+	if l1_hits := l1_cache.StatFactory().Value("hits"); l1_hits != 2 {
+		t.Fatalf("%d L1 hits are counted", l1_hits)
+	}
+
+	if l2_hits := l2_cache.StatFactory().Value("hits"); l2_hits != 1 {
+		t.Fatalf("%d L2 hits are counted", l2_hits)
+	}
+
+	if dram_reads := host_core.StatFactory().Value("dram_reads"); dram_reads != 3 {
+		t.Fatalf("%d DRAM reads are counted", dram_reads)
+	}
+
+	if memory_accesses := host_core.StatFactory().Value("memory_accesses"); memory_accesses != 6 {
+		t.Fatalf("%d memory accesses are counted", memory_accesses)
+	}
+}
+
+func TestHostCoreDirtyWriteBack(t *testing.T) {
+	host_core, memory, memory_controller := newHostCore()
+
+	host_core.Checkout()
+
+	memory.Write(0, 4, newByteStream(1, 2, 3, 4))
+
+	// NOTE: the dirty line 0 leaves the L1 for the L2 without reaching the DRAM
+	host_core.Load(128, 4)
+	if dram_writes := host_core.StatFactory().Value("dram_writes"); dram_writes != 0 {
+		t.Fatalf("%d DRAM writes are made by an L1 eviction", dram_writes)
+	}
+
+	if !host_core.Caches()[1].Find(0).is_dirty {
+		t.Fatalf("line 0 is not dirty in the L2 after its L1 eviction")
+	}
+
+	// NOTE: lines 256 and 512 fill the set of line 0 in the L2, which evicts line 0 to the DRAM
+	host_core.Load(256, 4)
+	host_core.Load(512, 4)
+	if dram_writes := host_core.StatFactory().Value("dram_writes"); dram_writes != 1 {
+		t.Fatalf("%d DRAM writes are made by an L2 eviction", dram_writes)
+	}
+
+	l2_stat_factory := host_core.Caches()[1].StatFactory()
+	if dirty_evictions := l2_stat_factory.Value("dirty_evictions"); dirty_evictions != 1 {
+		t.Fatalf("%d dirty L2 evictions are counted", dirty_evictions)
+	}
+
+	host_core.Checkin()
+
+	transfer_command := new(bank.TransferCommand)
+	transfer_command.InitHost(bank.HOST_READ, 0, 64)
+	memory_controller.HostAccess(transfer_command)
+
+	for i := 0; i < 4; i++ {
+		if value := transfer_command.ByteStream().Get(i); value != uint8(i+1) {
+			t.Fatalf("byte %d of the written back line is %d", i, value)
+		}
+	}
+}
+
+func TestHostCoreHostCycles(t *testing.T) {
+	host_core, _, _ := newHostCore()
+
+	// NOTE: memory cycles are rounded up to host cycles at 3200 / 2400 MHz
+	for memory_cycles, host_cycles := range map[int64]int64{0: 0, 1: 2, 3: 4, 10: 14} {
+		if cycles := host_core.HostCycles(memory_cycles); cycles != host_cycles {
+			t.Fatalf("%d memory cycles are %d host cycles", memory_cycles, cycles)
+		}
+	}
+
+	host_core.Transfer(3)
+	host_core.Stall(6)
+	if host_core.Cycles() != 10 || host_core.StatFactory().Value("transfer_cycles") != 4 {
+		t.Fatalf("a transfer of 3 memory cycles and a stall of 6 cycles take %d cycles",
+			host_core.Cycles())
+	}
+
+	if time_ns := host_core.TimeNs(); time_ns != 3 {
+		t.Fatalf("10 cycles at 3200 MHz take %d ns", time_ns)
+	}
+}
+
+func TestCostTableLoad(t *testing.T) {
+	cost_table_filepath := filepath.Join(t.TempDir(), "cost_table.txt")
+	cost_table := "# overrides\n\nDIV 40\n  PUSH_INT 7  \nNOP 0\n"
+	if err := os.WriteFile(cost_table_filepath, []byte(cost_table), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	host_core, _, _ := newHostCore()
+	host_core.CostTable().Load(cost_table_filepath, new(program.App))
+
+	costs := map[abi.OpCode]int64{abi.DIV: 40, abi.PUSH_INT: 7, abi.NOP: 0, abi.MUL: 3, abi.ADD: 1}
+	for op_code, cost := range costs {
+		if host_core.CostTable().Cost(op_code) != cost {
+			t.Fatalf("op code (%d) costs %d cycles", op_code, host_core.CostTable().Cost(op_code))
+		}
+	}
+
+	host_core.Execute(abi.DIV)
+	host_core.Execute(abi.PUSH_INT)
+	if host_core.Cycles() != 47 || host_core.StatFactory().Value("bytecodes") != 2 {
+		t.Fatalf("DIV and PUSH_INT take %d cycles", host_core.Cycles())
+	}
+}
+
+func TestCostTableLoadRejectsNegativeCost(t *testing.T) {
+	cost_table_filepath := filepath.Join(t.TempDir(), "cost_table.txt")
+	if err := os.WriteFile(cost_table_filepath, []byte("DIV -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("a negative cost is loaded")
+		}
+	}()
+
+	cost_table := new(CostTable)
+	cost_table.Init()
+	cost_table.Load(cost_table_filepath, new(program.App))
+}
@@ -0,0 +1,90 @@
+package debugger
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type BreakpointType int
+
+const (
+	LINE BreakpointType = iota
+	FUNC
+)
+
+type Breakpoint struct {
+	breakpoint_type BreakpointType
+
+	id   int
+	path string
+	line int
+
+	func_name string
+
+	is_temporary bool
+	hits         int
+}
+
+func (this *Breakpoint) InitLine(id int, path string, line int, is_temporary bool) {
+	this.breakpoint_type = LINE
+
+	this.id = id
+	this.path = path
+	this.line = line
+
+	this.func_name = ""
+
+	this.is_temporary = is_temporary
+	this.hits = 0
+}
+
+func (this *Breakpoint) InitFunc(id int, func_name string, is_temporary bool) {
+	this.breakpoint_type = FUNC
+
+	this.id = id
+	this.path = ""
+	this.line = 0
+
+	this.func_name = func_name
+
+	this.is_temporary = is_temporary
+	this.hits = 0
+}
+
+func (this *Breakpoint) BreakpointType() BreakpointType {
+	return this.breakpoint_type
+}
+
+func (this *Breakpoint) Id() int {
+	return this.id
+}
+
+func (this *Breakpoint) IsTemporary() bool {
+	return this.is_temporary
+}
+
+func (this *Breakpoint) Hits() int {
+	return this.hits
+}
+
+func (this *Breakpoint) Hit() {
+	this.hits++
+}
+
+// NOTE: a line breakpoint is hit by the first bytecode of its line, while a function breakpoint is
+// hit by the first bytecode with a position in a new frame of its function
+func (this *Breakpoint) IsHit(path string, line int, func_name string, is_entry bool) bool {
+	if this.breakpoint_type == LINE {
+		return this.path == path && this.line == line
+	} else {
+		return is_entry && this.func_name == func_name
+	}
+}
+
+func (this *Breakpoint) Stringify() string {
+	if this.breakpoint_type == LINE {
+		return fmt.Sprintf("%s:%d", filepath.Base(this.path), this.line)
+	} else {
+		return this.func_name
+	}
+}
@@ -0,0 +1,499 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"uPIMulator/src/host/abi"
+	"uPIMulator/src/host/vm/arena"
+	"uPIMulator/src/host/vm/frame"
+	"uPIMulator/src/host/vm/type_system"
+	"uPIMulator/src/program"
+)
+
+type Mode int
+
+const (
+	CONTINUE Mode = iota
+	STEP
+	NEXT
+	FINISH
+)
+
+// NOTE: the debugger is trapped before every bytecode and stops at the first bytecode of a host
+// source line, i.e. a bytecode with a position that differs from the last one run in its frame or
+// the first one with a position in a new frame, so that returning to the middle of a line does not
+// stop again. It stops at main to begin with, and then at breakpoints or once a step is done, where
+// next and finish step until the depth of the frame chain is back to or below the depth they were
+// issued at. Commands are read from the standard input, and the debugger lets the VM run to the end
+// once the standard input is closed.
+type Debugger struct {
+	scanner *bufio.Scanner
+
+	app         *program.App
+	frame_chain *frame.FrameChain
+	inspector   *Inspector
+
+	breakpoints    []*Breakpoint
+	breakpoint_brk int
+
+	mode       Mode
+	mode_depth int
+
+	positions []*abi.Bytecode
+
+	is_detached  bool
+	last_command string
+	value_brk    int
+
+	sources map[string][]string
+}
+
+func (this *Debugger) Init() {
+	this.scanner = bufio.NewScanner(os.Stdin)
+
+	this.app = nil
+	this.frame_chain = nil
+
+	this.inspector = new(Inspector)
+	this.inspector.Init()
+
+	this.breakpoints = make([]*Breakpoint, 0)
+	this.breakpoint_brk = 1
+
+	this.mode = CONTINUE
+	this.mode_depth = 0
+
+	this.positions = make([]*abi.Bytecode, 0)
+
+	this.is_detached = false
+	this.last_command = ""
+	this.value_brk = 1
+
+	this.sources = make(map[string][]string)
+}
+
+func (this *Debugger) ConnectApp(app *program.App) {
+	this.app = app
+
+	breakpoint := new(Breakpoint)
+	breakpoint.InitFunc(0, "main", true)
+
+	this.breakpoints = append(this.breakpoints, breakpoint)
+}
+
+func (this *Debugger) ConnectArena(arena_ *arena.Arena) {
+	this.inspector.ConnectMemory(arena_.Pool().Memory())
+}
+
+func (this *Debugger) ConnectFrameChain(frame_chain *frame.FrameChain) {
+	this.frame_chain = frame_chain
+}
+
+func (this *Debugger) ConnectRegistry(registry *type_system.TypeRegistry) {
+	this.inspector.ConnectRegistry(registry)
+}
+
+func (this *Debugger) Trap(bytecode *abi.Bytecode) {
+	if this.is_detached || !bytecode.HasPosition() {
+		return
+	}
+
+	depth := this.frame_chain.Length()
+	is_entry := len(this.positions) < depth
+
+	if len(this.positions) > depth {
+		this.positions = this.positions[:depth]
+	}
+	for len(this.positions) < depth {
+		this.positions = append(this.positions, nil)
+	}
+
+	last_bytecode := this.positions[depth-1]
+	this.positions[depth-1] = bytecode
+
+	if last_bytecode != nil &&
+		last_bytecode.Path() == bytecode.Path() &&
+		last_bytecode.Line() == bytecode.Line() {
+		return
+	}
+
+	func_name := this.frame_chain.LastFrame().Name()
+
+	var hit_breakpoint *Breakpoint
+	for _, breakpoint := range this.breakpoints {
+		if breakpoint.IsHit(bytecode.Path(), bytecode.Line(), func_name, is_entry) {
+			hit_breakpoint = breakpoint
+			break
+		}
+	}
+
+	if hit_breakpoint != nil {
+		hit_breakpoint.Hit()
+
+		if hit_breakpoint.IsTemporary() {
+			this.DeleteBreakpoint(hit_breakpoint.Id())
+		} else {
+			fmt.Printf("\nBreakpoint %d, ", hit_breakpoint.Id())
+		}
+	} else if !this.IsStepDone(depth) {
+		return
+	}
+
+	this.mode = CONTINUE
+	this.PrintLocation(depth - 1)
+	this.Prompt()
+}
+
+func (this *Debugger) IsStepDone(depth int) bool {
+	if this.mode == STEP {
+		return true
+	} else if this.mode == NEXT {
+		return depth <= this.mode_depth
+	} else if this.mode == FINISH {
+		return depth < this.mode_depth
+	} else {
+		return false
+	}
+}
+
+func (this *Debugger) Prompt() {
+	for {
+		fmt.Printf("(vmdb) ")
+
+		if !this.scanner.Scan() {
+			fmt.Printf("\n")
+			this.is_detached = true
+			return
+		}
+
+		command := strings.TrimSpace(this.scanner.Text())
+		if command == "" {
+			command = this.last_command
+		} else {
+			this.last_command = command
+		}
+
+		if this.Execute(command) {
+			return
+		}
+	}
+}
+
+// NOTE: Execute runs a command and tells whether the VM resumes
+func (this *Debugger) Execute(command string) bool {
+	words := strings.Fields(command)
+
+	if len(words) == 0 {
+		return false
+	}
+
+	name := words[0]
+	arg := strings.TrimSpace(command[len(name):])
+
+	if name == "break" || name == "b" {
+		this.Break(arg)
+	} else if name == "delete" || name == "d" {
+		this.Delete(arg)
+	} else if name == "info" || name == "i" {
+		this.Info(arg)
+	} else if name == "continue" || name == "c" {
+		this.mode = CONTINUE
+		return true
+	} else if name == "step" || name == "s" {
+		this.mode = STEP
+		return true
+	} else if name == "next" || name == "n" {
+		this.mode = NEXT
+		this.mode_depth = this.frame_chain.Length()
+		return true
+	} else if name == "finish" {
+		// NOTE: the outermost frame of the host program is main, which is called by __bootstrap
+		if this.frame_chain.Length() <= 2 {
+			fmt.Printf("\"finish\" not meaningful in the outermost frame.\n")
+			return false
+		}
+
+		this.mode = FINISH
+		this.mode_depth = this.frame_chain.Length()
+		fmt.Printf("Run till exit from ")
+		this.PrintFrame(this.frame_chain.Length() - 1)
+		return true
+	} else if name == "print" || name == "p" {
+		this.Print(arg)
+	} else if name == "whatis" {
+		this.Whatis(arg)
+	} else if name == "backtrace" || name == "bt" || name == "where" {
+		this.Backtrace()
+	} else if name == "help" || name == "h" {
+		this.Help()
+	} else if name == "quit" || name == "q" {
+		os.Exit(0)
+	} else {
+		fmt.Printf("Undefined command: \"%s\".  Try \"help\".\n", name)
+	}
+
+	return false
+}
+
+// NOTE: a location is file:line, a line of the current file or a function name, and a line is
+// moved to the next line that has a bytecode, as no bytecode could stop at it otherwise
+func (this *Debugger) Break(location string) {
+	breakpoint := new(Breakpoint)
+
+	if location == "" {
+		position := this.positions[len(this.positions)-1]
+		breakpoint.InitLine(this.breakpoint_brk, position.Path(), position.Line(), false)
+	} else if line, err := strconv.Atoi(location); err == nil {
+		position := this.positions[len(this.positions)-1]
+
+		path, line_, found := this.ResolveLine(position.Path(), line)
+		if !found {
+			fmt.Printf("No line %d in the current file.\n", line)
+			return
+		}
+
+		breakpoint.InitLine(this.breakpoint_brk, path, line_, false)
+	} else if colon := strings.LastIndex(location, ":"); colon >= 0 {
+		line, err := strconv.Atoi(location[colon+1:])
+		if err != nil {
+			fmt.Printf("Malformed line number \"%s\".\n", location[colon+1:])
+			return
+		}
+
+		path, line_, found := this.ResolveLine(location[:colon], line)
+		if !found {
+			fmt.Printf("No line %d in file \"%s\".\n", line, location[:colon])
+			return
+		}
+
+		breakpoint.InitLine(this.breakpoint_brk, path, line_, false)
+	} else {
+		if !this.app.HasLabel(location) || this.app.Label(location).Length() == 0 {
+			fmt.Printf("Function \"%s\" not defined.\n", location)
+			return
+		}
+
+		breakpoint.InitFunc(this.breakpoint_brk, location, false)
+	}
+
+	this.breakpoints = append(this.breakpoints, breakpoint)
+	this.breakpoint_brk++
+
+	fmt.Printf("Breakpoint %d at %s\n", breakpoint.Id(), breakpoint.Stringify())
+}
+
+func (this *Debugger) ResolveLine(pattern string, line int) (string, int, bool) {
+	path := ""
+	resolved_line := 0
+
+	for _, label := range this.app.Labels() {
+		for i := 0; i < label.Length(); i++ {
+			bytecode := label.Get(i)
+
+			if bytecode.HasPosition() && this.MatchesPath(bytecode.Path(), pattern) &&
+				bytecode.Line() >= line && (path == "" || bytecode.Line() < resolved_line) {
+				path = bytecode.Path()
+				resolved_line = bytecode.Line()
+			}
+		}
+	}
+
+	return path, resolved_line, path != ""
+}
+
+// NOTE: a path pattern names a source file by its path, its base name or a trailing part of it
+func (this *Debugger) MatchesPath(path string, pattern string) bool {
+	return path == pattern ||
+		filepath.Base(path) == pattern ||
+		strings.HasSuffix(path, "/"+pattern)
+}
+
+func (this *Debugger) Delete(arg string) {
+	if arg == "" {
+		this.breakpoints = make([]*Breakpoint, 0)
+		return
+	}
+
+	for _, word := range strings.Fields(arg) {
+		id, err := strconv.Atoi(word)
+
+		if err != nil || !this.DeleteBreakpoint(id) {
+			fmt.Printf("No breakpoint number %s.\n", word)
+		}
+	}
+}
+
+func (this *Debugger) DeleteBreakpoint(id int) bool {
+	for i, breakpoint := range this.breakpoints {
+		if breakpoint.Id() == id {
+			this.breakpoints = slices.Delete(this.breakpoints, i, i+1)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (this *Debugger) Info(arg string) {
+	if arg == "breakpoints" || arg == "break" || arg == "b" {
+		this.InfoBreakpoints()
+	} else if arg == "locals" {
+		this.InfoLocals()
+	} else {
+		fmt.Printf("Undefined info command: \"%s\".  Try \"help\".\n", arg)
+	}
+}
+
+func (this *Debugger) InfoBreakpoints() {
+	num_breakpoints := 0
+
+	for _, breakpoint := range this.breakpoints {
+		if breakpoint.IsTemporary() {
+			continue
+		}
+
+		if num_breakpoints == 0 {
+			fmt.Printf("Num     What                           Hits\n")
+		}
+
+		fmt.Printf("%-7d %-30s %d\n", breakpoint.Id(), breakpoint.Stringify(), breakpoint.Hits())
+		num_breakpoints++
+	}
+
+	if num_breakpoints == 0 {
+		fmt.Printf("No breakpoints.\n")
+	}
+}
+
+// NOTE: a local that is shadowed by a local of an inner scope is not shown
+func (this *Debugger) InfoLocals() {
+	symbol_names := make([]string, 0)
+	for _, symbol_ := range this.frame_chain.LastFrame().FastScopeChain().Symbols() {
+		if !slices.Contains(symbol_names, symbol_.Name()) {
+			symbol_names = append(symbol_names, symbol_.Name())
+		}
+	}
+
+	if len(symbol_names) == 0 {
+		fmt.Printf("No locals.\n")
+		return
+	}
+
+	slices.Sort(symbol_names)
+
+	for _, symbol_name := range symbol_names {
+		this.PrintValue(symbol_name, symbol_name+" = ")
+	}
+}
+
+func (this *Debugger) Print(expr string) {
+	value, err := this.inspector.Evaluate(
+		expr,
+		this.frame_chain.LastFrame(),
+		this.frame_chain.GlobalScope(),
+	)
+
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return
+	}
+
+	fmt.Printf("$%d = %s\n", this.value_brk, this.inspector.Format(value))
+	this.value_brk++
+}
+
+func (this *Debugger) PrintValue(expr string, prefix string) {
+	value, err := this.inspector.Evaluate(
+		expr,
+		this.frame_chain.LastFrame(),
+		this.frame_chain.GlobalScope(),
+	)
+
+	if err != nil {
+		fmt.Printf("%s<%s>\n", prefix, err.Error())
+	} else {
+		fmt.Printf("%s%s\n", prefix, this.inspector.Format(value))
+	}
+}
+
+func (this *Debugger) Whatis(expr string) {
+	value, err := this.inspector.Evaluate(
+		expr,
+		this.frame_chain.LastFrame(),
+		this.frame_chain.GlobalScope(),
+	)
+
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return
+	}
+
+	fmt.Printf("type = %s\n", this.inspector.TypeName(value.TypeVariable()))
+}
+
+func (this *Debugger) Backtrace() {
+	for i := this.frame_chain.Length() - 1; i >= 0; i-- {
+		fmt.Printf("#%-2d ", this.frame_chain.Length()-1-i)
+		this.PrintFrame(i)
+	}
+}
+
+func (this *Debugger) PrintFrame(pos int) {
+	fmt.Printf("%s ()", this.frame_chain.Frame(pos).Name())
+
+	if pos < len(this.positions) && this.positions[pos] != nil {
+		position := this.positions[pos]
+		fmt.Printf(" at %s:%d", filepath.Base(position.Path()), position.Line())
+	}
+
+	fmt.Printf("\n")
+}
+
+func (this *Debugger) PrintLocation(pos int) {
+	this.PrintFrame(pos)
+
+	position := this.positions[pos]
+	if source_line, found := this.SourceLine(position.Path(), position.Line()); found {
+		fmt.Printf("%d\t%s\n", position.Line(), source_line)
+	}
+}
+
+// NOTE: the source files are read once they are first shown, and a line that cannot be read is
+// not shown
+func (this *Debugger) SourceLine(path string, line int) (string, bool) {
+	if _, found := this.sources[path]; !found {
+		bytes, err := os.ReadFile(path)
+
+		if err != nil {
+			this.sources[path] = make([]string, 0)
+		} else {
+			this.sources[path] = strings.Split(string(bytes), "\n")
+		}
+	}
+
+	lines := this.sources[path]
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+
+	return strings.TrimRight(lines[line-1], "\r"), true
+}
+
+func (this *Debugger) Help() {
+	fmt.Printf("break [FILE:]LINE | FUNC  set a breakpoint\n")
+	fmt.Printf("delete [N...]             delete breakpoints\n")
+	fmt.Printf("info breakpoints | locals show breakpoints or local variables\n")
+	fmt.Printf("continue                  run until a breakpoint\n")
+	fmt.Printf("step                      run to the next line, into calls\n")
+	fmt.Printf("next                      run to the next line, over calls\n")
+	fmt.Printf("finish                    run until the current function returns\n")
+	fmt.Printf("print EXPR                print a variable, with *, ., -> and []\n")
+	fmt.Printf("whatis EXPR               print the type of a variable\n")
+	fmt.Printf("backtrace                 print the frames\n")
+	fmt.Printf("quit                      exit the simulator\n")
+}
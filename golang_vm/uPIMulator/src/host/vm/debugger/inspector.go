@@ -0,0 +1,582 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"uPIMulator/src/host/vm/arena"
+	"uPIMulator/src/host/vm/frame"
+	"uPIMulator/src/host/vm/stack"
+	"uPIMulator/src/host/vm/symbol"
+	"uPIMulator/src/host/vm/type_system"
+	"unicode"
+)
+
+// NOTE: the inspector evaluates the expressions of the print command, which are names of symbols
+// followed by any number of .field, ->field and [index] and preceded by any number of *. A value is
+// a stack item that locates the bytes of its type in the memory, so that it is read with Peek and
+// never disturbs the memory tracker of the host core. An array field of a struct is a value of its
+// element type that spans all of its elements, whose length is kept aside since no pointer to it is
+// in the memory.
+type Inspector struct {
+	memory   *arena.Memory
+	registry *type_system.TypeRegistry
+
+	words []string
+	pos   int
+
+	lengths map[*stack.StackItem]int64
+}
+
+func (this *Inspector) Init() {
+	this.memory = nil
+	this.registry = nil
+
+	this.words = make([]string, 0)
+	this.pos = 0
+
+	this.lengths = make(map[*stack.StackItem]int64)
+}
+
+func (this *Inspector) ConnectMemory(memory *arena.Memory) {
+	this.memory = memory
+}
+
+func (this *Inspector) ConnectRegistry(registry *type_system.TypeRegistry) {
+	this.registry = registry
+}
+
+func (this *Inspector) Evaluate(expr string, frame_ *frame.Frame, global_scope *symbol.Scope) (
+	*stack.StackItem,
+	error,
+) {
+	words, err := this.Split(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	this.words = words
+	this.pos = 0
+	this.lengths = make(map[*stack.StackItem]int64)
+
+	value, err := this.EvaluateUnary(frame_, global_scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if this.pos != len(this.words) {
+		err_msg := fmt.Sprintf("A syntax error in expression, near `%s'.", this.words[this.pos])
+		return nil, errors.New(err_msg)
+	}
+
+	return value, nil
+}
+
+func (this *Inspector) Split(expr string) ([]string, error) {
+	words := make([]string, 0)
+
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+
+		if unicode.IsSpace(c) {
+			i++
+		} else if this.IsWordChar(c) {
+			j := i
+			for j < len(expr) && this.IsWordChar(rune(expr[j])) {
+				j++
+			}
+
+			words = append(words, expr[i:j])
+			i = j
+		} else if strings.HasPrefix(expr[i:], "->") {
+			words = append(words, "->")
+			i += 2
+		} else if strings.ContainsRune("*.[]()", c) {
+			words = append(words, string(c))
+			i++
+		} else {
+			err_msg := fmt.Sprintf("Invalid character '%c' in expression.", c)
+			return nil, errors.New(err_msg)
+		}
+	}
+
+	if len(words) == 0 {
+		err := errors.New("Argument required (expression to compute).")
+		return nil, err
+	}
+
+	return words, nil
+}
+
+func (this *Inspector) IsWordChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_'
+}
+
+func (this *Inspector) Peek() string {
+	if this.pos < len(this.words) {
+		return this.words[this.pos]
+	} else {
+		return ""
+	}
+}
+
+func (this *Inspector) Expect(word string) error {
+	if this.Peek() != word {
+		err_msg := fmt.Sprintf("A syntax error in expression, expecting `%s'.", word)
+		return errors.New(err_msg)
+	}
+
+	this.pos++
+
+	return nil
+}
+
+func (this *Inspector) EvaluateUnary(frame_ *frame.Frame, global_scope *symbol.Scope) (
+	*stack.StackItem,
+	error,
+) {
+	if this.Peek() == "*" {
+		this.pos++
+
+		value, err := this.EvaluateUnary(frame_, global_scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return this.Dereference(value)
+	}
+
+	return this.EvaluatePostfix(frame_, global_scope)
+}
+
+func (this *Inspector) EvaluatePostfix(frame_ *frame.Frame, global_scope *symbol.Scope) (
+	*stack.StackItem,
+	error,
+) {
+	value, err := this.EvaluatePrimary(frame_, global_scope)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if this.Peek() == "." || this.Peek() == "->" {
+			is_reference := this.Peek() == "->"
+			this.pos++
+
+			field_name := this.Peek()
+			if field_name == "" || !this.IsIdentifier(field_name) {
+				err := errors.New("A syntax error in expression, expecting a field name.")
+				return nil, err
+			}
+			this.pos++
+
+			if is_reference {
+				value, err = this.Dereference(value)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			value, err = this.Access(value, field_name)
+			if err != nil {
+				return nil, err
+			}
+		} else if this.Peek() == "[" {
+			this.pos++
+
+			index, err := this.EvaluateIndex(frame_, global_scope)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := this.Expect("]"); err != nil {
+				return nil, err
+			}
+
+			value, err = this.Subscript(value, index)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			return value, nil
+		}
+	}
+}
+
+func (this *Inspector) EvaluatePrimary(frame_ *frame.Frame, global_scope *symbol.Scope) (
+	*stack.StackItem,
+	error,
+) {
+	word := this.Peek()
+
+	if word == "(" {
+		this.pos++
+
+		value, err := this.EvaluateUnary(frame_, global_scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := this.Expect(")"); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	} else if this.IsIdentifier(word) {
+		this.pos++
+
+		return this.Lookup(word, frame_, global_scope)
+	} else if word == "" {
+		err := errors.New("A syntax error in expression, near `'.")
+		return nil, err
+	} else {
+		err_msg := fmt.Sprintf("A syntax error in expression, near `%s'.", word)
+		return nil, errors.New(err_msg)
+	}
+}
+
+// NOTE: an index is either a number or an expression of an integer type
+func (this *Inspector) EvaluateIndex(frame_ *frame.Frame, global_scope *symbol.Scope) (
+	int64,
+	error,
+) {
+	if index, err := strconv.ParseInt(this.Peek(), 0, 64); err == nil {
+		this.pos++
+		return index, nil
+	}
+
+	value, err := this.EvaluateUnary(frame_, global_scope)
+	if err != nil {
+		return 0, err
+	}
+
+	type_variable := value.TypeVariable()
+
+	if type_variable.NumStars() != 0 ||
+		(type_variable.TypeVariableType() != type_system.CHAR &&
+			type_variable.TypeVariableType() != type_system.SHORT &&
+			type_variable.TypeVariableType() != type_system.INT &&
+			type_variable.TypeVariableType() != type_system.LONG) {
+		err := errors.New("Index is not an integer.")
+		return 0, err
+	}
+
+	return this.IntegerValue(value), nil
+}
+
+func (this *Inspector) IsIdentifier(word string) bool {
+	return word != "" && (unicode.IsLetter(rune(word[0])) || word[0] == '_')
+}
+
+// NOTE: a name is looked up in the scopes of the frame, innermost first, then in its arguments
+// that are not bound yet and finally in the global scope, as the VM does
+func (this *Inspector) Lookup(
+	symbol_name string,
+	frame_ *frame.Frame,
+	global_scope *symbol.Scope,
+) (*stack.StackItem, error) {
+	var symbol_ *symbol.Symbol
+	if frame_.FastScopeChain().HasSymbol(symbol_name) {
+		symbol_ = frame_.FastScopeChain().Symbol(symbol_name)
+	} else if frame_.ArgScope().HasSymbol(symbol_name) {
+		symbol_ = frame_.ArgScope().Symbol(symbol_name)
+	} else if global_scope.HasSymbol(symbol_name) {
+		symbol_ = global_scope.Symbol(symbol_name)
+	} else {
+		err_msg := fmt.Sprintf("No symbol \"%s\" in current context.", symbol_name)
+		return nil, errors.New(err_msg)
+	}
+
+	value := new(stack.StackItem)
+	value.Init(symbol_.TypeVariable(), symbol_.Object().Address(), symbol_.Object().Size())
+
+	return value, nil
+}
+
+func (this *Inspector) Dereference(value *stack.StackItem) (*stack.StackItem, error) {
+	if _, is_array := this.lengths[value]; !is_array && value.TypeVariable().NumStars() == 0 {
+		err := errors.New("Attempt to take contents of a non-pointer value.")
+		return nil, err
+	}
+
+	return this.Subscript(value, 0)
+}
+
+func (this *Inspector) Subscript(value *stack.StackItem, index int64) (*stack.StackItem, error) {
+	if _, is_array := this.lengths[value]; is_array {
+		return this.SubscriptArray(value, index)
+	}
+
+	if value.TypeVariable().NumStars() == 0 {
+		err_msg := fmt.Sprintf(
+			"cannot subscript something of type `%s'",
+			this.TypeName(value.TypeVariable()),
+		)
+		return nil, errors.New(err_msg)
+	}
+
+	if err := this.CheckAddress(value.Address(), value.Size()); err != nil {
+		return nil, err
+	}
+
+	type_variable := this.ElementType(value.TypeVariable())
+
+	size, err := this.TypeSize(type_variable)
+	if err != nil {
+		return nil, err
+	}
+
+	address := this.memory.Peek(value.Address(), value.Size()).SignedValue() + index*size
+
+	if err := this.CheckAddress(address, size); err != nil {
+		return nil, err
+	}
+
+	element := new(stack.StackItem)
+	element.Init(type_variable, address, size)
+
+	return element, nil
+}
+
+// NOTE: the elements of an array field are in the struct itself, so that an index beyond its length
+// is reported rather than read from the next field
+func (this *Inspector) SubscriptArray(
+	value *stack.StackItem,
+	index int64,
+) (*stack.StackItem, error) {
+	length := this.lengths[value]
+
+	if index < 0 || index >= length {
+		err_msg := fmt.Sprintf("Index %d is out of the bounds of an array of %d.", index, length)
+		return nil, errors.New(err_msg)
+	}
+
+	size := value.Size() / length
+
+	element := new(stack.StackItem)
+	element.Init(value.TypeVariable(), value.Address()+index*size, size)
+
+	return element, nil
+}
+
+func (this *Inspector) Access(value *stack.StackItem, field_name string) (*stack.StackItem, error) {
+	type_variable := value.TypeVariable()
+
+	if type_variable.NumStars() != 0 || type_variable.TypeVariableType() != type_system.STRUCT {
+		err_msg := fmt.Sprintf(
+			"Attempt to extract a component of a value that is not a structure (%s).",
+			this.TypeName(type_variable),
+		)
+		return nil, errors.New(err_msg)
+	}
+
+	struct_name := type_variable.StructName()
+
+	if !this.registry.HasSkeleton(struct_name) ||
+		!this.registry.Skeleton(struct_name).HasField(field_name) {
+		err_msg := fmt.Sprintf("There is no member named %s.", field_name)
+		return nil, errors.New(err_msg)
+	}
+
+	field := this.registry.Skeleton(struct_name).Field(field_name)
+
+	size, err := this.TypeSize(field.TypeVariable())
+	if err != nil {
+		return nil, err
+	}
+
+	address := value.Address() + this.registry.FieldOffset(struct_name, field_name)
+
+	member := new(stack.StackItem)
+	if field.IsArray() {
+		member.Init(field.TypeVariable(), address, field.Length()*size)
+		this.lengths[member] = field.Length()
+	} else {
+		member.Init(field.TypeVariable(), address, size)
+	}
+
+	return member, nil
+}
+
+func (this *Inspector) ElementType(
+	type_variable *type_system.TypeVariable,
+) *type_system.TypeVariable {
+	element_type := new(type_system.TypeVariable)
+
+	if type_variable.TypeVariableType() == type_system.STRUCT {
+		element_type.InitStruct(
+			type_system.STRUCT,
+			type_variable.StructName(),
+			type_variable.NumStars()-1,
+		)
+	} else {
+		element_type.InitPrimitive(type_variable.TypeVariableType(), type_variable.NumStars()-1)
+	}
+
+	if type_variable.IsUnsigned() {
+		element_type.SetUnsigned(true)
+	}
+
+	return element_type
+}
+
+func (this *Inspector) TypeSize(type_variable *type_system.TypeVariable) (int64, error) {
+	if type_variable.NumStars() > 0 {
+		return 4, nil
+	} else if type_variable.TypeVariableType() == type_system.CHAR {
+		return 1, nil
+	} else if type_variable.TypeVariableType() == type_system.SHORT {
+		return 2, nil
+	} else if type_variable.TypeVariableType() == type_system.INT {
+		return 4, nil
+	} else if type_variable.TypeVariableType() == type_system.LONG {
+		return 8, nil
+	} else if type_variable.TypeVariableType() == type_system.FLOAT {
+		return 4, nil
+	} else if type_variable.TypeVariableType() == type_system.DOUBLE {
+		return 8, nil
+	} else if type_variable.TypeVariableType() == type_system.STRUCT &&
+		this.registry.HasSkeleton(type_variable.StructName()) {
+		return this.registry.SkeletonSize(type_variable.StructName()), nil
+	} else {
+		err_msg := fmt.Sprintf("Type `%s' has no size.", this.TypeName(type_variable))
+		return 0, errors.New(err_msg)
+	}
+}
+
+// NOTE: an address is only read if it is in the memory that the VM has touched, since peeking
+// beyond it would grow the memory
+func (this *Inspector) CheckAddress(address int64, size int64) error {
+	if address <= 0 || address+size >= this.memory.Size() {
+		err_msg := fmt.Sprintf("Cannot access memory at address 0x%x", address)
+		return errors.New(err_msg)
+	}
+
+	return nil
+}
+
+func (this *Inspector) TypeName(type_variable *type_system.TypeVariable) string {
+	var type_name string
+	if type_variable.TypeVariableType() == type_system.VOID {
+		type_name = "void"
+	} else if type_variable.TypeVariableType() == type_system.CHAR {
+		type_name = "char"
+	} else if type_variable.TypeVariableType() == type_system.SHORT {
+		type_name = "short"
+	} else if type_variable.TypeVariableType() == type_system.INT {
+		type_name = "int"
+	} else if type_variable.TypeVariableType() == type_system.LONG {
+		type_name = "long"
+	} else if type_variable.TypeVariableType() == type_system.STRING {
+		type_name = "string"
+	} else if type_variable.TypeVariableType() == type_system.STRUCT {
+		type_name = "struct " + type_variable.StructName()
+	} else if type_variable.TypeVariableType() == type_system.FLOAT {
+		type_name = "float"
+	} else if type_variable.TypeVariableType() == type_system.DOUBLE {
+		type_name = "double"
+	} else {
+		err := errors.New("type variable type is not valid")
+		panic(err)
+	}
+
+	if type_variable.IsUnsigned() {
+		type_name = "unsigned " + type_name
+	}
+
+	if type_variable.NumStars() > 0 {
+		type_name += " " + strings.Repeat("*", type_variable.NumStars())
+	}
+
+	return type_name
+}
+
+func (this *Inspector) Format(value *stack.StackItem) string {
+	type_variable := value.TypeVariable()
+
+	if err := this.CheckAddress(value.Address(), value.Size()); err != nil {
+		return "<" + err.Error() + ">"
+	}
+
+	if length, is_array := this.lengths[value]; is_array {
+		elements := make([]string, 0)
+		for i := int64(0); i < length; i++ {
+			element, _ := this.SubscriptArray(value, i)
+			elements = append(elements, this.Format(element))
+		}
+
+		return "{" + strings.Join(elements, ", ") + "}"
+	} else if type_variable.NumStars() > 0 {
+		address := this.memory.Peek(value.Address(), value.Size()).SignedValue()
+		return fmt.Sprintf("(%s) 0x%x", this.TypeName(type_variable), address)
+	} else if type_variable.TypeVariableType() == type_system.STRUCT {
+		return this.FormatStruct(value)
+	} else if type_variable.TypeVariableType() == type_system.CHAR {
+		integer_value := this.IntegerValue(value)
+
+		if integer_value >= 32 && integer_value < 127 {
+			return fmt.Sprintf("%d '%c'", integer_value, rune(integer_value))
+		} else {
+			return fmt.Sprintf("%d", integer_value)
+		}
+	} else if type_variable.TypeVariableType() == type_system.SHORT ||
+		type_variable.TypeVariableType() == type_system.INT ||
+		type_variable.TypeVariableType() == type_system.LONG {
+		if type_variable.IsUnsigned() {
+			return fmt.Sprintf("%d", uint64(this.IntegerValue(value)))
+		} else {
+			return fmt.Sprintf("%d", this.IntegerValue(value))
+		}
+	} else if type_variable.TypeVariableType() == type_system.FLOAT {
+		bits := this.memory.Peek(value.Address(), 4).UnsignedValue()
+		return strconv.FormatFloat(float64(math.Float32frombits(uint32(bits))), 'g', -1, 32)
+	} else if type_variable.TypeVariableType() == type_system.DOUBLE {
+		bits := this.memory.Peek(value.Address(), 8).SignedValue()
+		return strconv.FormatFloat(math.Float64frombits(uint64(bits)), 'g', -1, 64)
+	} else if type_variable.TypeVariableType() == type_system.STRING {
+		byte_stream := this.memory.Peek(value.Address(), value.Size())
+
+		bytes := make([]byte, 0)
+		for i := 0; i < int(byte_stream.Size()); i++ {
+			bytes = append(bytes, byte_stream.Get(i))
+		}
+
+		return strconv.Quote(string(bytes))
+	} else {
+		return "void"
+	}
+}
+
+func (this *Inspector) FormatStruct(value *stack.StackItem) string {
+	struct_name := value.TypeVariable().StructName()
+
+	if !this.registry.HasSkeleton(struct_name) {
+		return "<incomplete type>"
+	}
+
+	fields := make([]string, 0)
+	for _, field := range this.registry.Skeleton(struct_name).Fields() {
+		member, err := this.Access(value, field.Name())
+
+		if err != nil {
+			fields = append(fields, field.Name()+" = <"+err.Error()+">")
+		} else {
+			fields = append(fields, field.Name()+" = "+this.Format(member))
+		}
+	}
+
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// NOTE: an integer is sign-extended unless its type is unsigned
+func (this *Inspector) IntegerValue(value *stack.StackItem) int64 {
+	byte_stream := this.memory.Peek(value.Address(), value.Size())
+
+	if value.TypeVariable().IsUnsigned() && value.Size() < 8 {
+		return byte_stream.UnsignedValue()
+	} else {
+		return byte_stream.SignedValue()
+	}
+}
@@ -0,0 +1,133 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"uPIMulator/src/encoding"
+)
+
+// NOTE: a print of a DPU is stored in its stdout buffer as a format string, where every run of
+// literal characters is replaced by %s, followed by the arguments of the format string in order
+// and zero padding up to a multiple of 8 bytes
+type DpuLog struct {
+	byte_stream *encoding.ByteStream
+	pos         int64
+}
+
+func (this *DpuLog) Init(byte_stream *encoding.ByteStream) {
+	this.byte_stream = byte_stream
+	this.pos = 0
+}
+
+func (this *DpuLog) Decode() string {
+	ss := ""
+	for this.pos < this.byte_stream.Size() {
+		format := this.ReadString()
+
+		if format != "" {
+			ss += this.Format(format)
+		}
+
+		this.Align()
+	}
+	return ss
+}
+
+func (this *DpuLog) Format(format string) string {
+	ss := ""
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			ss += string(format[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(format) && (!this.IsLetter(format[j]) || format[j] == 'l') {
+			j++
+		}
+
+		if j == len(format) {
+			err_msg := fmt.Sprintf("format (%s) of DPU log is not valid", format)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		spec := strings.ReplaceAll(format[i:j], "l", "")
+		conversion := format[j]
+
+		size := int64(4)
+		if strings.Contains(format[i:j], "l") {
+			size = 8
+		}
+
+		if conversion == 's' {
+			ss += fmt.Sprintf(spec+"s", this.ReadString())
+		} else if conversion == 'c' {
+			ss += fmt.Sprintf(spec+"c", rune(this.ReadValue(1)))
+		} else if strings.ContainsRune("feEgG", rune(conversion)) {
+			value := math.Float64frombits(uint64(this.ReadValue(8)))
+			ss += fmt.Sprintf(spec+string(conversion), value)
+		} else if conversion == 'd' {
+			value := this.ReadValue(size)
+			if size == 4 {
+				value = int64(int32(value))
+			}
+
+			ss += fmt.Sprintf(spec+"d", value)
+		} else if conversion == 'u' {
+			ss += fmt.Sprintf(spec+"d", uint64(this.ReadValue(size)))
+		} else if strings.ContainsRune("xXo", rune(conversion)) {
+			ss += fmt.Sprintf(spec+string(conversion), uint64(this.ReadValue(size)))
+		} else if conversion == 'p' {
+			ss += fmt.Sprintf("0x%x", uint64(this.ReadValue(size)))
+		} else {
+			err_msg := fmt.Sprintf("conversion (%c) of DPU log is not supported", conversion)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		i = j
+	}
+	return ss
+}
+
+func (this *DpuLog) IsLetter(character byte) bool {
+	return (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+}
+
+func (this *DpuLog) ReadString() string {
+	characters := make([]byte, 0)
+	for this.pos < this.byte_stream.Size() {
+		character := this.byte_stream.Get(int(this.pos))
+		this.pos++
+
+		if character == 0 {
+			break
+		}
+
+		characters = append(characters, character)
+	}
+	return string(characters)
+}
+
+func (this *DpuLog) ReadValue(size int64) int64 {
+	if this.pos+size > this.byte_stream.Size() {
+		err := errors.New("DPU log ends in the middle of an argument")
+		panic(err)
+	}
+
+	value := int64(0)
+	for i := int64(0); i < size; i++ {
+		value |= int64(this.byte_stream.Get(int(this.pos+i))) << (8 * i)
+	}
+
+	this.pos += size
+
+	return value
+}
+
+func (this *DpuLog) Align() {
+	this.pos = (this.pos + 7) / 8 * 8
+}
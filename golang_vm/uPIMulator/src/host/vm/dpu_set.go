@@ -0,0 +1,126 @@
+package vm
+
+import (
+	"errors"
+	"uPIMulator/src/device/simulator/dpu"
+)
+
+type DpuSetType int
+
+const (
+	ALLOCATED DpuSetType = iota
+	RANK
+	SINGLE
+)
+
+// NOTE: a DPU set is referred to by a handle stored in the struct dpu_set_t of the host program,
+// where the ranks and the DPUs of an allocated set are DPU sets of their own that share the
+// allocation of their root
+type DpuSet struct {
+	dpu_set_type DpuSetType
+	dpus         []*dpu.Dpu
+	root         *DpuSet
+
+	dpu_handles  []int64
+	rank_handles []int64
+
+	is_freed bool
+}
+
+func (this *DpuSet) Init(dpu_set_type DpuSetType, dpus []*dpu.Dpu, root *DpuSet) {
+	if len(dpus) == 0 {
+		err := errors.New("DPU set is empty")
+		panic(err)
+	} else if dpu_set_type == ALLOCATED && root != nil {
+		err := errors.New("allocated DPU set has a root")
+		panic(err)
+	} else if dpu_set_type != ALLOCATED && root == nil {
+		err := errors.New("DPU set of a rank or a DPU has no root")
+		panic(err)
+	}
+
+	this.dpu_set_type = dpu_set_type
+	this.dpus = dpus
+
+	if root == nil {
+		this.root = this
+	} else {
+		this.root = root
+	}
+
+	this.dpu_handles = make([]int64, 0)
+	this.rank_handles = make([]int64, 0)
+
+	this.is_freed = false
+}
+
+func (this *DpuSet) DpuSetType() DpuSetType {
+	return this.dpu_set_type
+}
+
+func (this *DpuSet) Dpus() []*dpu.Dpu {
+	return this.dpus
+}
+
+func (this *DpuSet) Root() *DpuSet {
+	return this.root
+}
+
+func (this *DpuSet) Contains(dpu_ *dpu.Dpu) bool {
+	for _, set_dpu := range this.dpus {
+		if set_dpu == dpu_ {
+			return true
+		}
+	}
+	return false
+}
+
+// NOTE: the DPUs of a set are grouped by the rank they belong to, in the order of the set
+func (this *DpuSet) Ranks() [][]*dpu.Dpu {
+	ranks := make([][]*dpu.Dpu, 0)
+	for _, dpu_ := range this.dpus {
+		if len(ranks) > 0 {
+			last_rank := ranks[len(ranks)-1]
+
+			if last_rank[0].ChannelId() == dpu_.ChannelId() && last_rank[0].RankId() == dpu_.RankId() {
+				ranks[len(ranks)-1] = append(last_rank, dpu_)
+				continue
+			}
+		}
+
+		ranks = append(ranks, []*dpu.Dpu{dpu_})
+	}
+	return ranks
+}
+
+func (this *DpuSet) DpuHandles() []int64 {
+	return this.dpu_handles
+}
+
+func (this *DpuSet) RankHandles() []int64 {
+	return this.rank_handles
+}
+
+func (this *DpuSet) AppendDpuHandle(handle int64) {
+	this.dpu_handles = append(this.dpu_handles, handle)
+}
+
+func (this *DpuSet) AppendRankHandle(handle int64) {
+	this.rank_handles = append(this.rank_handles, handle)
+}
+
+func (this *DpuSet) IsFreed() bool {
+	return this.root.is_freed
+}
+
+func (this *DpuSet) Free() {
+	if this.dpu_set_type != ALLOCATED {
+		err := errors.New("DPU set of a rank or a DPU cannot be freed")
+		panic(err)
+	} else if this.is_freed {
+		err := errors.New("DPU set is already freed")
+		panic(err)
+	}
+
+	this.is_freed = true
+}
@@ -0,0 +1,32 @@
+package vm
+
+import (
+	"uPIMulator/src/device/simulator/dpu"
+	"uPIMulator/src/device/simulator/dpu/logic"
+)
+
+type DpuStepCycleJob struct {
+	sys_end    int64
+	num_cycles int64
+
+	dpu *dpu.Dpu
+}
+
+func (this *DpuStepCycleJob) Init(sys_end int64, num_cycles int64, dpu_ *dpu.Dpu) {
+	this.sys_end = sys_end
+	this.num_cycles = num_cycles
+
+	this.dpu = dpu_
+}
+
+func (this *DpuStepCycleJob) Execute() {
+	for i := int64(0); i < this.num_cycles && !this.dpu.IsZombie(); i++ {
+		for _, thread := range this.dpu.Threads() {
+			if thread.RegFile().ReadPcReg() == this.sys_end && thread.ThreadState() == logic.SLEEP {
+				this.dpu.ThreadScheduler().Shutdown(thread.ThreadId())
+			}
+		}
+
+		this.dpu.Cycle()
+	}
+}
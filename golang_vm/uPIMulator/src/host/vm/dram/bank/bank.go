@@ -131,7 +131,7 @@ func (this *Bank) Read(address int64, size int64) *encoding.ByteStream {
 		cur_size := this.Min(cur_wordline_address+this.wordline_size, address+size) - cur_address
 		cur_offset := cur_address % this.wordline_size
 
-		mram_byte_stream := this.array.Read(cur_wordline_address)
+		mram_byte_stream := this.ReadWordline(cur_wordline_address)
 
 		for i := cur_offset; i < cur_offset+cur_size; i++ {
 			byte_stream.Append(mram_byte_stream.Get(int(i)))
@@ -155,13 +155,13 @@ func (this *Bank) Write(address int64, size int64, byte_stream *encoding.ByteStr
 		cur_size := this.Min(cur_wordline_address+this.wordline_size, address+size) - cur_address
 		cur_offset := cur_address % this.wordline_size
 
-		mram_byte_stream := this.array.Read(cur_wordline_address)
+		mram_byte_stream := this.ReadWordline(cur_wordline_address)
 
 		for i := int64(0); i < cur_size; i++ {
 			mram_byte_stream.Set(int(i+cur_offset), byte_stream.Get(int(i+cur_byte_stream_offset)))
 		}
 
-		this.array.Write(cur_wordline_address, mram_byte_stream)
+		this.WriteWordline(cur_wordline_address, mram_byte_stream)
 
 		cur_address += cur_size
 		cur_byte_stream_offset += cur_size
@@ -172,6 +172,24 @@ func (this *Bank) Flush() {
 	this.row_buffer.Flush()
 }
 
+// NOTE: an open row holds the latest copy of its wordline until it is precharged back to the array,
+// so that the functional accesses go through it while it is open
+func (this *Bank) ReadWordline(address int64) *encoding.ByteStream {
+	if this.row_buffer.IsOpen(address) {
+		return this.row_buffer.ReadFromRowBuffer(address, this.wordline_size)
+	} else {
+		return this.array.Read(address)
+	}
+}
+
+func (this *Bank) WriteWordline(address int64, byte_stream *encoding.ByteStream) {
+	if this.row_buffer.IsOpen(address) {
+		this.row_buffer.WriteToRowBuffer(address, this.wordline_size, byte_stream)
+	} else {
+		this.array.Write(address, byte_stream)
+	}
+}
+
 func (this *Bank) Cycle() {
 	this.ServiceInputQ()
 	this.ServiceRowBuffer()
@@ -284,6 +284,10 @@ func (this *RowBuffer) ServicePrechargeQ() {
 	}
 }
 
+func (this *RowBuffer) IsOpen(address int64) bool {
+	return this.row_address != nil && *this.row_address == address
+}
+
 func (this *RowBuffer) ReadFromBank() *encoding.ByteStream {
 	if this.row_address == nil {
 		err := errors.New("row address is not set")
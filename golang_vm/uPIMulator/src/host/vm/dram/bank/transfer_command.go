@@ -11,6 +11,8 @@ type TransferCommandType int
 const (
 	HOST_TO_DEVICE TransferCommandType = iota
 	DEVICE_TO_HOST
+	HOST_READ
+	HOST_WRITE
 )
 
 type TransferCommandState int
@@ -72,6 +74,21 @@ func (this *TransferCommand) Init(
 	this.dma_commands = make(map[*dram.DmaCommand]bool)
 }
 
+// NOTE: host reads and writes only access the VM DRAM, which times the cache line fills and
+// write-backs of the host core
+func (this *TransferCommand) InitHost(
+	transfer_command_type TransferCommandType,
+	vm_address int64,
+	size int64,
+) {
+	if transfer_command_type != HOST_READ && transfer_command_type != HOST_WRITE {
+		err := errors.New("transfer command type is neither host read nor host write")
+		panic(err)
+	}
+
+	this.Init(transfer_command_type, vm_address, -1, -1, -1, -1, size)
+}
+
 func (this *TransferCommand) InitFast(vm_address int64, size int64) {
 	this.vm_address = vm_address
 	this.size = size
@@ -259,6 +259,37 @@ func (this *MemoryController) ServiceInputQ() {
 
 				transfer_command.AppendVmDmaCommand(dma_command)
 
+				this.vm_dma_command_q.Push(dma_command)
+			}
+		} else if transfer_command.TransferCommandType() == bank.HOST_READ ||
+			transfer_command.TransferCommandType() == bank.HOST_WRITE {
+			this.vm_wait_q.Push(transfer_command)
+
+			segments := this.memory_mapping.Map(
+				transfer_command.VmAddress(),
+				transfer_command.Size(),
+			)
+
+			for _, segment := range segments {
+				dma_command := new(bank.DmaCommand)
+
+				if transfer_command.TransferCommandType() == bank.HOST_READ {
+					dma_command.InitRead(segment, transfer_command)
+				} else {
+					dma_command.InitWrite(segment, transfer_command)
+
+					byte_stream := new(encoding.ByteStream)
+					byte_stream.Init()
+					for i := int64(0); i < segment.Size(); i++ {
+						index := transfer_command.Index(segment.VmAddress() + i)
+						byte_stream.Append(transfer_command.ByteStream().Get(index))
+					}
+
+					dma_command.SetByteStream(segment.BankAddress(), segment.Size(), byte_stream)
+				}
+
+				transfer_command.AppendVmDmaCommand(dma_command)
+
 				this.vm_dma_command_q.Push(dma_command)
 			}
 		} else if transfer_command.TransferCommandType() == bank.DEVICE_TO_HOST {
@@ -326,7 +357,8 @@ func (this *MemoryController) ServiceVmChannels() {
 			dma_command := vm_channel_command.MemoryCommand().DmaCommand()
 			transfer_command := dma_command.TransferCommand()
 
-			if transfer_command.TransferCommandType() == bank.HOST_TO_DEVICE {
+			if transfer_command.TransferCommandType() == bank.HOST_TO_DEVICE ||
+				transfer_command.TransferCommandType() == bank.HOST_READ {
 				segment := dma_command.Segment()
 				vm_address := segment.VmAddress()
 				size := segment.Size()
@@ -337,7 +369,8 @@ func (this *MemoryController) ServiceVmChannels() {
 				if dma_command.IsReady() {
 					transfer_command.AckVmDmaCommand(dma_command)
 				}
-			} else if transfer_command.TransferCommandType() == bank.DEVICE_TO_HOST {
+			} else if transfer_command.TransferCommandType() == bank.DEVICE_TO_HOST ||
+				transfer_command.TransferCommandType() == bank.HOST_WRITE {
 				if dma_command.IsReady() {
 					transfer_command.AckVmDmaCommand(dma_command)
 				}
@@ -399,7 +432,16 @@ func (this *MemoryController) ServiceVmWaitQ() {
 	for i := 0; i < this.vm_wait_q.Length(); i++ {
 		transfer_command, _ := this.vm_wait_q.Front(i)
 
-		if transfer_command.IsVmReady() && transfer_command.IsReady() &&
+		if this.IsHostTransferCommand(transfer_command) {
+			if transfer_command.IsVmReady() && this.ready_q.CanPush(1) {
+				this.vm_wait_q.Remove(i)
+
+				transfer_command.SetTransferCommandState(bank.MIDDLE)
+				transfer_command.SetTransferCommandState(bank.END)
+
+				this.ready_q.Push(transfer_command)
+			}
+		} else if transfer_command.IsVmReady() && transfer_command.IsReady() &&
 			transfer_command.TransferCommandState() == bank.MIDDLE &&
 			this.ready_q.CanPush(1) {
 			this.vm_wait_q.Remove(i)
@@ -436,6 +478,44 @@ func (this *MemoryController) ServiceVmWaitQ() {
 	}
 }
 
+func (this *MemoryController) IsHostTransferCommand(transfer_command *bank.TransferCommand) bool {
+	return transfer_command.TransferCommandType() == bank.HOST_READ ||
+		transfer_command.TransferCommandType() == bank.HOST_WRITE
+}
+
+// NOTE: a host access is simulated to completion before the host core continues, and its latency
+// is returned in VM DRAM cycles
+func (this *MemoryController) HostAccess(transfer_command *bank.TransferCommand) int64 {
+	if !this.IsHostTransferCommand(transfer_command) {
+		err := errors.New("transfer command is not a host access")
+		panic(err)
+	}
+
+	this.Push(transfer_command)
+
+	num_cycles := int64(0)
+	for !this.CanPop() {
+		for _, bank_ := range this.Banks() {
+			bank_.Cycle()
+		}
+
+		for _, vm_channel_ := range this.vm_channels {
+			vm_channel_.Cycle()
+		}
+
+		this.Cycle()
+
+		num_cycles++
+	}
+
+	if this.Pop() != transfer_command {
+		err := errors.New("memory controller completed a transfer command other than the host access")
+		panic(err)
+	}
+
+	return num_cycles
+}
+
 func (this *MemoryController) ServiceWaitQ() {
 	for i := 0; i < this.wait_q.Length(); i++ {
 		transfer_command, _ := this.wait_q.Front(i)
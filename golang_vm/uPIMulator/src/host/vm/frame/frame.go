@@ -8,6 +8,8 @@ import (
 )
 
 type Frame struct {
+	name string
+
 	stack            *stack.Stack
 	return_stack     *stack.ReturnStack
 	fast_scope_chain *symbol.ScopeChain
@@ -16,6 +18,8 @@ type Frame struct {
 }
 
 func (this *Frame) Init(label *abi.Label) {
+	this.name = label.Name()
+
 	this.stack = new(stack.Stack)
 	this.stack.Init()
 
@@ -33,6 +37,11 @@ func (this *Frame) Init(label *abi.Label) {
 	this.pc.Jump(label)
 }
 
+// NOTE: the name of a frame is the name of the function it is called for
+func (this *Frame) Name() string {
+	return this.name
+}
+
 func (this *Frame) Stack() *stack.Stack {
 	return this.stack
 }
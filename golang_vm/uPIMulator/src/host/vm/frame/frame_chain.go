@@ -3,6 +3,7 @@ package frame
 import (
 	"errors"
 	"uPIMulator/src/host/abi"
+	"uPIMulator/src/host/vm/stack"
 	"uPIMulator/src/host/vm/symbol"
 )
 
@@ -45,6 +46,23 @@ func (this *FrameChain) Symbols() []*symbol.Symbol {
 	return symbols
 }
 
+// NOTE: the stack items of every frame, including its return stack, are still in use
+func (this *FrameChain) StackItems() []*stack.StackItem {
+	stack_items := make([]*stack.StackItem, 0)
+
+	for _, frame := range this.frames {
+		for i := 0; i < frame.Stack().Length(); i++ {
+			stack_items = append(stack_items, frame.Stack().Front(i))
+		}
+
+		for i := 0; i < frame.ReturnStack().Length(); i++ {
+			stack_items = append(stack_items, frame.ReturnStack().Front(i))
+		}
+	}
+
+	return stack_items
+}
+
 func (this *FrameChain) HasObject(address int64) bool {
 	for _, frame := range this.frames {
 		if frame.Stack().HasObject(address) || frame.ReturnStack().HasObject(address) {
@@ -26,6 +26,10 @@ func (this *Pc) Advance() *abi.Bytecode {
 	return bytecode
 }
 
+func (this *Pc) Peek() *abi.Bytecode {
+	return this.label.Get(this.index)
+}
+
 func (this *Pc) Jump(label *abi.Label) {
 	this.label = label
 	this.index = 0
@@ -1,13 +1,17 @@
 package type_system
 
+// NOTE: a field with a length is an array whose elements are laid out in the struct itself, while a
+// field with no length is a single value
 type Field struct {
 	type_variable *TypeVariable
 	name          string
+	length        int64
 }
 
 func (this *Field) Init(type_variable *TypeVariable, name string) {
 	this.type_variable = type_variable
 	this.name = name
+	this.length = 0
 }
 
 func (this *Field) TypeVariable() *TypeVariable {
@@ -17,3 +21,15 @@ func (this *Field) TypeVariable() *TypeVariable {
 func (this *Field) Name() string {
 	return this.name
 }
+
+func (this *Field) IsArray() bool {
+	return this.length > 0
+}
+
+func (this *Field) Length() int64 {
+	return this.length
+}
+
+func (this *Field) SetLength(length int64) {
+	this.length = length
+}
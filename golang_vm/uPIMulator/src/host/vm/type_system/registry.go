@@ -49,26 +49,7 @@ func (this *TypeRegistry) SkeletonSize(skeleton_name string) int64 {
 
 	offset := int64(0)
 	for i := 0; i < skeleton.Length(); i++ {
-		field := skeleton.Get(i)
-
-		if field.TypeVariable().NumStars() > 0 {
-			offset += 4
-		} else {
-			if field.TypeVariable().TypeVariableType() == VOID {
-				err := errors.New("type variable type is void")
-				panic(err)
-			} else if field.TypeVariable().TypeVariableType() == CHAR {
-				offset += 1
-			} else if field.TypeVariable().TypeVariableType() == SHORT {
-				offset += 2
-			} else if field.TypeVariable().TypeVariableType() == INT {
-				offset += 4
-			} else if field.TypeVariable().TypeVariableType() == LONG {
-				offset += 8
-			} else if field.TypeVariable().TypeVariableType() == STRUCT {
-				offset += this.SkeletonSize(field.TypeVariable().StructName())
-			}
-		}
+		offset += this.FieldSize(skeleton_name, skeleton.Get(i).Name())
 	}
 	return offset
 }
@@ -90,31 +71,12 @@ func (this *TypeRegistry) FieldOffset(skeleton_name string, field_name string) i
 			break
 		}
 
-		if field.TypeVariable().NumStars() > 0 {
-			offset += 4
-		} else {
-			if field.TypeVariable().TypeVariableType() == VOID {
-				err := errors.New("type variable type is void")
-				panic(err)
-			} else if field.TypeVariable().TypeVariableType() == CHAR {
-				offset += 1
-			} else if field.TypeVariable().TypeVariableType() == SHORT {
-				offset += 2
-			} else if field.TypeVariable().TypeVariableType() == INT {
-				offset += 4
-			} else if field.TypeVariable().TypeVariableType() == LONG {
-				offset += 8
-			} else if field.TypeVariable().TypeVariableType() == STRUCT {
-				offset += this.SkeletonSize(field.TypeVariable().StructName())
-			} else {
-				err := errors.New("type variable type is not valid")
-				panic(err)
-			}
-		}
+		offset += this.FieldSize(skeleton_name, field.Name())
 	}
 	return offset
 }
 
+// NOTE: the size of an array field is the size of all of its elements
 func (this *TypeRegistry) FieldSize(skeleton_name string, field_name string) int64 {
 	if !this.HasSkeleton(skeleton_name) {
 		err_msg := fmt.Sprintf("skeleton (%s) is not found", skeleton_name)
@@ -125,22 +87,34 @@ func (this *TypeRegistry) FieldSize(skeleton_name string, field_name string) int
 	skeleton := this.Skeleton(skeleton_name)
 	field := skeleton.Field(field_name)
 
-	if field.TypeVariable().NumStars() > 0 {
+	if field.IsArray() {
+		return field.Length() * this.TypeSize(field.TypeVariable())
+	} else {
+		return this.TypeSize(field.TypeVariable())
+	}
+}
+
+func (this *TypeRegistry) TypeSize(type_variable *TypeVariable) int64 {
+	if type_variable.NumStars() > 0 {
 		return 4
 	} else {
-		if field.TypeVariable().TypeVariableType() == VOID {
+		if type_variable.TypeVariableType() == VOID {
 			err := errors.New("type variable type is void")
 			panic(err)
-		} else if field.TypeVariable().TypeVariableType() == CHAR {
+		} else if type_variable.TypeVariableType() == CHAR {
 			return 1
-		} else if field.TypeVariable().TypeVariableType() == SHORT {
+		} else if type_variable.TypeVariableType() == SHORT {
 			return 2
-		} else if field.TypeVariable().TypeVariableType() == INT {
+		} else if type_variable.TypeVariableType() == INT {
+			return 4
+		} else if type_variable.TypeVariableType() == LONG {
+			return 8
+		} else if type_variable.TypeVariableType() == FLOAT {
 			return 4
-		} else if field.TypeVariable().TypeVariableType() == LONG {
+		} else if type_variable.TypeVariableType() == DOUBLE {
 			return 8
-		} else if field.TypeVariable().TypeVariableType() == STRUCT {
-			return this.SkeletonSize(field.TypeVariable().StructName())
+		} else if type_variable.TypeVariableType() == STRUCT {
+			return this.SkeletonSize(type_variable.StructName())
 		} else {
 			err := errors.New("type variable type is not valid")
 			panic(err)
@@ -14,12 +14,15 @@ const (
 	LONG
 	STRING
 	STRUCT
+	FLOAT
+	DOUBLE
 )
 
 type TypeVariable struct {
 	type_variable_type TypeVariableType
 	struct_name        *string
 	num_stars          int
+	is_unsigned        bool
 }
 
 func (this *TypeVariable) InitPrimitive(type_variable_type TypeVariableType, num_stars int) {
@@ -31,6 +34,7 @@ func (this *TypeVariable) InitPrimitive(type_variable_type TypeVariableType, num
 	this.type_variable_type = type_variable_type
 	this.struct_name = nil
 	this.num_stars = num_stars
+	this.is_unsigned = false
 }
 
 func (this *TypeVariable) InitStruct(
@@ -49,6 +53,7 @@ func (this *TypeVariable) InitStruct(
 	*this.struct_name = struct_name
 
 	this.num_stars = num_stars
+	this.is_unsigned = false
 }
 
 func (this *TypeVariable) TypeVariableType() TypeVariableType {
@@ -67,3 +72,53 @@ func (this *TypeVariable) StructName() string {
 func (this *TypeVariable) NumStars() int {
 	return this.num_stars
 }
+
+func (this *TypeVariable) IsUnsigned() bool {
+	return this.is_unsigned
+}
+
+// NOTE: only the integral types can be unsigned
+func (this *TypeVariable) SetUnsigned(is_unsigned bool) {
+	if is_unsigned && this.type_variable_type != CHAR && this.type_variable_type != SHORT &&
+		this.type_variable_type != INT && this.type_variable_type != LONG {
+		err := errors.New("type variable type cannot be unsigned")
+		panic(err)
+	}
+
+	this.is_unsigned = is_unsigned
+}
+
+// NOTE: Reference gives the type of a pointer to a value of this type
+func (this *TypeVariable) Reference() *TypeVariable {
+	type_variable := new(TypeVariable)
+
+	if this.type_variable_type == STRUCT {
+		type_variable.InitStruct(STRUCT, this.StructName(), this.num_stars+1)
+	} else {
+		type_variable.InitPrimitive(this.type_variable_type, this.num_stars+1)
+	}
+
+	type_variable.is_unsigned = this.is_unsigned
+
+	return type_variable
+}
+
+// NOTE: Dereference gives the type of the value that a pointer of this type points to
+func (this *TypeVariable) Dereference() *TypeVariable {
+	if this.num_stars == 0 {
+		err := errors.New("type variable is not a pointer")
+		panic(err)
+	}
+
+	type_variable := new(TypeVariable)
+
+	if this.type_variable_type == STRUCT {
+		type_variable.InitStruct(STRUCT, this.StructName(), this.num_stars-1)
+	} else {
+		type_variable.InitPrimitive(this.type_variable_type, this.num_stars-1)
+	}
+
+	type_variable.is_unsigned = this.is_unsigned
+
+	return type_variable
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"uPIMulator/src/device/core"
 	"uPIMulator/src/device/simulator/channel"
 	"uPIMulator/src/device/simulator/dpu"
@@ -12,6 +15,8 @@ import (
 	"uPIMulator/src/host/abi"
 	"uPIMulator/src/host/vm/arena"
 	"uPIMulator/src/host/vm/base"
+	"uPIMulator/src/host/vm/cpu"
+	"uPIMulator/src/host/vm/debugger"
 	"uPIMulator/src/host/vm/dram"
 	"uPIMulator/src/host/vm/dram/bank"
 	"uPIMulator/src/host/vm/frame"
@@ -40,14 +45,43 @@ type VirtualMachine struct {
 	frame_chain       *frame.FrameChain
 	type_registry     *type_system.TypeRegistry
 	garbage_collector *arena.GarbageCollector
+	debugger          *debugger.Debugger
 
 	cur_skeleton_name *string
 
+	dispatch_table []func(bytecode *abi.Bytecode)
+	num_bytecodes  int64
+	gc_countdown   int64
+
 	memory_controller *dram.MemoryController
 	channels          []*channel.Channel
 
+	dpu_sets        []*DpuSet
+	allocated_ranks map[int]bool
+
 	prepare_xfer_buf map[*dpu.Dpu]int64
 	push_xfer        map[*bank.TransferCommand]bool
+
+	host_core                *cpu.HostCore
+	host_cost_table_filepath string
+
+	host_frequency  int64
+	logic_frequency int64
+
+	running_dpus       []*dpu.Dpu
+	launch_cycle       int64
+	stepped_dpu_cycles int64
+	dpu_cycles         int64
+
+	host_args []string
+	argv      int64
+	optind    int
+	optpos    int
+
+	rand_next uint32
+	is_exited bool
+
+	stat_factory *misc.StatFactory
 }
 
 func (this *VirtualMachine) Init(command_line_parser *misc.CommandLineParser) {
@@ -74,13 +108,27 @@ func (this *VirtualMachine) Init(command_line_parser *misc.CommandLineParser) {
 	this.type_registry.Init()
 
 	this.garbage_collector = new(arena.GarbageCollector)
-	this.garbage_collector.Init()
+	this.garbage_collector.Init(command_line_parser)
 	this.garbage_collector.ConnectArena(this.arena)
 	this.garbage_collector.ConnectFrameChain(this.frame_chain)
 	this.garbage_collector.ConnectRegistry(this.type_registry)
 
+	if command_line_parser.BoolParameter("vm_debug") {
+		this.debugger = new(debugger.Debugger)
+		this.debugger.Init()
+		this.debugger.ConnectArena(this.arena)
+		this.debugger.ConnectFrameChain(this.frame_chain)
+		this.debugger.ConnectRegistry(this.type_registry)
+	} else {
+		this.debugger = nil
+	}
+
 	this.cur_skeleton_name = nil
 
+	this.InitDispatchTable()
+	this.num_bytecodes = 0
+	this.gc_countdown = this.garbage_collector.Threshold()
+
 	this.memory_controller = new(dram.MemoryController)
 	this.memory_controller.Init(command_line_parser)
 
@@ -94,8 +142,42 @@ func (this *VirtualMachine) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.memory_controller.ConnectChannels(this.channels)
 
+	this.dpu_sets = make([]*DpuSet, 0)
+	this.allocated_ranks = make(map[int]bool)
+
 	this.prepare_xfer_buf = make(map[*dpu.Dpu]int64)
 	this.push_xfer = make(map[*bank.TransferCommand]bool)
+
+	this.host_core = new(cpu.HostCore)
+	this.host_core.Init(command_line_parser)
+	this.host_core.ConnectMemory(this.arena.Pool().Memory())
+	this.host_core.ConnectMemoryController(this.memory_controller)
+
+	this.garbage_collector.ConnectHostClock(this.host_core)
+
+	this.host_cost_table_filepath = command_line_parser.StringParameter("host_cost_table_filepath")
+
+	this.host_frequency = command_line_parser.IntParameter("host_frequency")
+	this.logic_frequency = command_line_parser.IntParameter("logic_frequency")
+
+	this.running_dpus = make([]*dpu.Dpu, 0)
+	this.launch_cycle = 0
+	this.stepped_dpu_cycles = 0
+	this.dpu_cycles = 0
+
+	this.host_args = append(
+		[]string{command_line_parser.StringParameter("benchmark")},
+		strings.Fields(command_line_parser.StringParameter("host_args"))...,
+	)
+	this.argv = 0
+	this.optind = 1
+	this.optpos = 1
+
+	this.rand_next = 1
+	this.is_exited = false
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("VirtualMachine")
 }
 
 func (this *VirtualMachine) Fini() {
@@ -108,6 +190,9 @@ func (this *VirtualMachine) Fini() {
 	if len(this.prepare_xfer_buf) != 0 {
 		err := errors.New("VM's prepare xfer buf is not empty")
 		panic(err)
+	} else if len(this.running_dpus) != 0 {
+		err := errors.New("VM's running DPUs are not synchronized")
+		panic(err)
 	}
 }
 
@@ -117,10 +202,28 @@ func (this *VirtualMachine) Load(app *program.App, task *program.Task) {
 
 	bootstrap := this.app.Label("__bootstrap")
 	this.frame_chain.Bootstrap(bootstrap)
+
+	if this.debugger != nil {
+		this.debugger.ConnectApp(this.app)
+	}
+
+	if this.host_cost_table_filepath != "" {
+		this.host_core.CostTable().Load(this.host_cost_table_filepath, this.app)
+	}
 }
 
+// NOTE: a host program that calls exit stops with the frames of its callers left as they are
 func (this *VirtualMachine) CanAdvance() bool {
-	return this.frame_chain.CanAdvance()
+	return !this.is_exited && this.frame_chain.CanAdvance()
+}
+
+func (this *VirtualMachine) NextBytecode() *abi.Bytecode {
+	if !this.frame_chain.CanAdvance() {
+		err := errors.New("frame chain cannot advance")
+		panic(err)
+	}
+
+	return this.frame_chain.LastFrame().Pc().Peek()
 }
 
 func (this *VirtualMachine) Advance() {
@@ -129,7 +232,21 @@ func (this *VirtualMachine) Advance() {
 		panic(err)
 	}
 
-	this.garbage_collector.MarkAndSweep()
+	if this.debugger != nil {
+		this.debugger.Trap(this.frame_chain.LastFrame().Pc().Peek())
+	}
+
+	// NOTE: the garbage collector is only called once every threshold bytecodes, and a collection
+	// that is still marking takes a step on every bytecode
+	if this.garbage_collector.IsMarking() {
+		this.garbage_collector.Step()
+	} else {
+		this.gc_countdown--
+		if this.gc_countdown < 0 {
+			this.garbage_collector.Collect()
+			this.gc_countdown = this.garbage_collector.Threshold()
+		}
+	}
 
 	bytecode := this.frame_chain.Advance()
 
@@ -137,312 +254,616 @@ func (this *VirtualMachine) Advance() {
 		fmt.Printf("%s\n", bytecode.Stringify())
 	}
 
-	if bytecode.OpCode() == abi.NEW_SCOPE {
+	this.host_core.Checkout()
+	this.host_core.Execute(bytecode.OpCode())
+
+	dispatch := this.dispatch_table[bytecode.OpCode()]
+	if dispatch == nil {
+		err := errors.New("op code is not valid")
+		panic(err)
+	}
+
+	dispatch(bytecode)
+
+	this.host_core.Checkin()
+
+	this.num_bytecodes++
+
+	if len(this.running_dpus) > 0 {
+		this.CycleRunningDpus()
+	}
+
+	if this.verbose >= 2 {
+		fmt.Printf("%s\n", this.Stringify())
+	}
+}
+
+// NOTE: the dispatch table maps every op code to the handler of its bytecodes, so that the VM does
+// not compare an op code against every other op code
+func (this *VirtualMachine) InitDispatchTable() {
+	this.dispatch_table = make([]func(bytecode *abi.Bytecode), abi.NUM_OP_CODES)
+
+	this.dispatch_table[abi.NEW_SCOPE] = func(bytecode *abi.Bytecode) {
 		this.frame_chain.LastFrame().FastScopeChain().NewScope()
-	} else if bytecode.OpCode() == abi.DELETE_SCOPE {
+	}
+	this.dispatch_table[abi.DELETE_SCOPE] = func(bytecode *abi.Bytecode) {
 		this.frame_chain.LastFrame().FastScopeChain().DeleteScope()
-	} else if bytecode.OpCode() == abi.PUSH_CHAR {
+	}
+	this.dispatch_table[abi.PUSH_CHAR] = func(bytecode *abi.Bytecode) {
 		value := bytecode.Arg1()
 		this.PushChar(value)
-	} else if bytecode.OpCode() == abi.PUSH_SHORT {
+	}
+	this.dispatch_table[abi.PUSH_SHORT] = func(bytecode *abi.Bytecode) {
 		value := bytecode.Arg1()
 		this.PushShort(value)
-	} else if bytecode.OpCode() == abi.PUSH_INT {
+	}
+	this.dispatch_table[abi.PUSH_INT] = func(bytecode *abi.Bytecode) {
 		value := bytecode.Arg1()
 		this.PushInt(value)
-	} else if bytecode.OpCode() == abi.PUSH_LONG {
+	}
+	this.dispatch_table[abi.PUSH_LONG] = func(bytecode *abi.Bytecode) {
 		value := bytecode.Arg1()
 		this.PushLong(value)
-	} else if bytecode.OpCode() == abi.PUSH_STRING {
+	}
+	this.dispatch_table[abi.PUSH_FLOAT] = func(bytecode *abi.Bytecode) {
+		value := math.Float64frombits(uint64(bytecode.Arg1()))
+		this.PushFloat(value)
+	}
+	this.dispatch_table[abi.PUSH_DOUBLE] = func(bytecode *abi.Bytecode) {
+		value := math.Float64frombits(uint64(bytecode.Arg1()))
+		this.PushDouble(value)
+	}
+	this.dispatch_table[abi.PUSH_STRING] = func(bytecode *abi.Bytecode) {
 		value := bytecode.Str1()
 		this.PushString(value)
-	} else if bytecode.OpCode() == abi.POP {
+	}
+	this.dispatch_table[abi.POP] = func(bytecode *abi.Bytecode) {
 		this.Pop()
-	} else if bytecode.OpCode() == abi.BEGIN_STRUCT {
+	}
+	this.dispatch_table[abi.DUPLICATE] = func(bytecode *abi.Bytecode) {
+		this.Duplicate()
+	}
+	this.dispatch_table[abi.BEGIN_STRUCT] = func(bytecode *abi.Bytecode) {
 		skeleton_name := bytecode.Str1()
 		this.BeginStruct(skeleton_name)
-	} else if bytecode.OpCode() == abi.APPEND_VOID {
+	}
+	this.dispatch_table[abi.APPEND_VOID] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		field_name := bytecode.Str1()
 		this.AppendVoid(num_stars, field_name)
-	} else if bytecode.OpCode() == abi.APPEND_CHAR {
+	}
+	this.dispatch_table[abi.APPEND_CHAR] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		field_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.AppendChar(num_stars, field_name, is_unsigned)
+	}
+	this.dispatch_table[abi.APPEND_SHORT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		field_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.AppendShort(num_stars, field_name, is_unsigned)
+	}
+	this.dispatch_table[abi.APPEND_INT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		field_name := bytecode.Str1()
-		this.AppendChar(num_stars, field_name)
-	} else if bytecode.OpCode() == abi.APPEND_SHORT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.AppendInt(num_stars, field_name, is_unsigned)
+	}
+	this.dispatch_table[abi.APPEND_LONG] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		field_name := bytecode.Str1()
-		this.AppendShort(num_stars, field_name)
-	} else if bytecode.OpCode() == abi.APPEND_INT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.AppendLong(num_stars, field_name, is_unsigned)
+	}
+	this.dispatch_table[abi.APPEND_FLOAT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		field_name := bytecode.Str1()
-		this.AppendInt(num_stars, field_name)
-	} else if bytecode.OpCode() == abi.APPEND_LONG {
+		this.AppendFloat(num_stars, field_name)
+	}
+	this.dispatch_table[abi.APPEND_DOUBLE] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		field_name := bytecode.Str1()
-		this.AppendLong(num_stars, field_name)
-	} else if bytecode.OpCode() == abi.APPEND_STRUCT {
+		this.AppendDouble(num_stars, field_name)
+	}
+	this.dispatch_table[abi.APPEND_STRUCT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		struct_name := bytecode.Str1()
 		field_name := bytecode.Str2()
 		this.AppendStruct(num_stars, struct_name, field_name)
-	} else if bytecode.OpCode() == abi.END_STRUCT {
+	}
+	this.dispatch_table[abi.APPEND_ARRAY] = func(bytecode *abi.Bytecode) {
+		this.AppendArray()
+	}
+	this.dispatch_table[abi.END_STRUCT] = func(bytecode *abi.Bytecode) {
 		this.EndStruct()
-	} else if bytecode.OpCode() == abi.NEW_GLOBAL_VOID {
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_VOID] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
 		this.NewGlobalVoid(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_GLOBAL_CHAR {
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_CHAR] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		symbol_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewGlobalChar(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_SHORT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewGlobalChar(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_GLOBAL_SHORT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewGlobalShort(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_INT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		symbol_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewGlobalInt(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_LONG] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewGlobalShort(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_GLOBAL_INT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewGlobalLong(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_FLOAT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewGlobalInt(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_GLOBAL_LONG {
+		this.NewGlobalFloat(num_stars, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_DOUBLE] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewGlobalLong(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_FAST_VOID {
+		this.NewGlobalDouble(num_stars, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_GLOBAL_STRUCT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		struct_name := bytecode.Str1()
+		symbol_name := bytecode.Str2()
+		this.NewGlobalStruct(num_stars, struct_name, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_FAST_VOID] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
 		this.NewFastVoid(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_FAST_CHAR {
+	}
+	this.dispatch_table[abi.NEW_FAST_CHAR] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewFastChar(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_FAST_SHORT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewFastChar(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_FAST_SHORT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		symbol_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewFastShort(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_FAST_INT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewFastShort(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_FAST_INT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewFastInt(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_FAST_LONG] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		symbol_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewFastLong(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_FAST_FLOAT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewFastInt(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_FAST_LONG {
+		this.NewFastFloat(num_stars, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_FAST_DOUBLE] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewFastLong(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_FAST_STRUCT {
+		this.NewFastDouble(num_stars, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_FAST_STRUCT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		struct_name := bytecode.Str1()
 		symbol_name := bytecode.Str2()
 		this.NewFastStruct(num_stars, struct_name, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_ARG_VOID {
+	}
+	this.dispatch_table[abi.NEW_ARG_VOID] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
 		this.NewArgVoid(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_ARG_CHAR {
+	}
+	this.dispatch_table[abi.NEW_ARG_CHAR] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		symbol_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewArgChar(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_ARG_SHORT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		symbol_name := bytecode.Str1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewArgShort(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_ARG_INT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewArgChar(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_ARG_SHORT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewArgInt(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_ARG_LONG] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewArgShort(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_ARG_INT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewArgLong(num_stars, symbol_name, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_ARG_FLOAT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewArgInt(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_ARG_LONG {
+		this.NewArgFloat(num_stars, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_ARG_DOUBLE] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		symbol_name := bytecode.Str1()
-		this.NewArgLong(num_stars, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_ARG_STRUCT {
+		this.NewArgDouble(num_stars, symbol_name)
+	}
+	this.dispatch_table[abi.NEW_ARG_STRUCT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		struct_name := bytecode.Str1()
 		symbol_name := bytecode.Str2()
 		this.NewArgStruct(num_stars, struct_name, symbol_name)
-	} else if bytecode.OpCode() == abi.NEW_RETURN_VOID {
+	}
+	this.dispatch_table[abi.NEW_RETURN_VOID] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		this.NewReturnVoid(num_stars)
-	} else if bytecode.OpCode() == abi.NEW_RETURN_CHAR {
+	}
+	this.dispatch_table[abi.NEW_RETURN_CHAR] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
-		this.NewReturnChar(num_stars)
-	} else if bytecode.OpCode() == abi.NEW_RETURN_SHORT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewReturnChar(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_RETURN_SHORT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewReturnShort(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_RETURN_INT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewReturnInt(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_RETURN_LONG] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
-		this.NewReturnShort(num_stars)
-	} else if bytecode.OpCode() == abi.NEW_RETURN_INT {
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.NewReturnLong(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.NEW_RETURN_FLOAT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
-		this.NewReturnInt(num_stars)
-	} else if bytecode.OpCode() == abi.NEW_RETURN_LONG {
+		this.NewReturnFloat(num_stars)
+	}
+	this.dispatch_table[abi.NEW_RETURN_DOUBLE] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
-		this.NewReturnLong(num_stars)
-	} else if bytecode.OpCode() == abi.NEW_RETURN_STRUCT {
+		this.NewReturnDouble(num_stars)
+	}
+	this.dispatch_table[abi.NEW_RETURN_STRUCT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		struct_name := bytecode.Str1()
 		this.NewReturnStruct(num_stars, struct_name)
-	} else if bytecode.OpCode() == abi.SIZEOF_VOID {
+	}
+	this.dispatch_table[abi.SIZEOF_VOID] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		this.SizeofVoid(num_stars)
-	} else if bytecode.OpCode() == abi.SIZEOF_CHAR {
+	}
+	this.dispatch_table[abi.SIZEOF_CHAR] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		this.SizeofChar(num_stars)
-	} else if bytecode.OpCode() == abi.SIZEOF_SHORT {
+	}
+	this.dispatch_table[abi.SIZEOF_SHORT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		this.SizeofShort(num_stars)
-	} else if bytecode.OpCode() == abi.SIZEOF_INT {
+	}
+	this.dispatch_table[abi.SIZEOF_INT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		this.SizeofInt(num_stars)
-	} else if bytecode.OpCode() == abi.SIZEOF_LONG {
+	}
+	this.dispatch_table[abi.SIZEOF_LONG] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		this.SizeofLong(num_stars)
-	} else if bytecode.OpCode() == abi.SIZEOF_STRUCT {
+	}
+	this.dispatch_table[abi.SIZEOF_FLOAT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		this.SizeofFloat(num_stars)
+	}
+	this.dispatch_table[abi.SIZEOF_DOUBLE] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		this.SizeofDouble(num_stars)
+	}
+	this.dispatch_table[abi.SIZEOF_STRUCT] = func(bytecode *abi.Bytecode) {
 		num_stars := bytecode.Arg1()
 		struct_name := bytecode.Str1()
 		this.SizeofStruct(num_stars, struct_name)
-	} else if bytecode.OpCode() == abi.GET_IDENTIFIER {
+	}
+	this.dispatch_table[abi.CAST_VOID] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		this.CastVoid(num_stars)
+	}
+	this.dispatch_table[abi.CAST_CHAR] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.CastChar(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.CAST_SHORT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.CastShort(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.CAST_INT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.CastInt(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.CAST_LONG] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		is_unsigned := bytecode.HasArg2() && bytecode.Arg2() != 0
+		this.CastLong(num_stars, is_unsigned)
+	}
+	this.dispatch_table[abi.CAST_FLOAT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		this.CastFloat(num_stars)
+	}
+	this.dispatch_table[abi.CAST_DOUBLE] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		this.CastDouble(num_stars)
+	}
+	this.dispatch_table[abi.CAST_STRUCT] = func(bytecode *abi.Bytecode) {
+		num_stars := bytecode.Arg1()
+		struct_name := bytecode.Str1()
+		this.CastStruct(num_stars, struct_name)
+	}
+	this.dispatch_table[abi.GET_IDENTIFIER] = func(bytecode *abi.Bytecode) {
 		symbol_name := bytecode.Str1()
 		this.GetIdentifier(symbol_name)
-	} else if bytecode.OpCode() == abi.GET_ARG_IDENTIFIER {
+	}
+	this.dispatch_table[abi.GET_ARG_IDENTIFIER] = func(bytecode *abi.Bytecode) {
 		symbol_name := bytecode.Str1()
 		this.GetArgIdentifier(symbol_name)
-	} else if bytecode.OpCode() == abi.GET_SUBSCRIPT {
+	}
+	this.dispatch_table[abi.GET_SUBSCRIPT] = func(bytecode *abi.Bytecode) {
 		this.GetSubscript()
-	} else if bytecode.OpCode() == abi.GET_ACCESS {
+	}
+	this.dispatch_table[abi.GET_ACCESS] = func(bytecode *abi.Bytecode) {
 		field_name := bytecode.Str1()
 		this.GetAccess(field_name)
-	} else if bytecode.OpCode() == abi.GET_REFERENCE {
+	}
+	this.dispatch_table[abi.GET_REFERENCE] = func(bytecode *abi.Bytecode) {
 		field_name := bytecode.Str1()
 		this.GetReference(field_name)
-	} else if bytecode.OpCode() == abi.GET_ADDRESS {
+	}
+	this.dispatch_table[abi.GET_ADDRESS] = func(bytecode *abi.Bytecode) {
 		this.GetAddress()
-	} else if bytecode.OpCode() == abi.GET_VALUE {
+	}
+	this.dispatch_table[abi.GET_VALUE] = func(bytecode *abi.Bytecode) {
 		this.GetValue()
-	} else if bytecode.OpCode() == abi.ALLOC {
+	}
+	this.dispatch_table[abi.ALLOC] = func(bytecode *abi.Bytecode) {
 		this.Alloc()
-	} else if bytecode.OpCode() == abi.FREE {
+	}
+	this.dispatch_table[abi.FREE] = func(bytecode *abi.Bytecode) {
 		this.Free()
-	} else if bytecode.OpCode() == abi.ASSERT {
+	}
+	this.dispatch_table[abi.ASSERT] = func(bytecode *abi.Bytecode) {
 		this.Assert()
-	} else if bytecode.OpCode() == abi.ADD {
+	}
+	this.dispatch_table[abi.PRINTF] = func(bytecode *abi.Bytecode) {
+		num_values := bytecode.Arg1()
+		this.Printf(num_values)
+	}
+	this.dispatch_table[abi.EXIT] = func(bytecode *abi.Bytecode) {
+		this.Exit()
+	}
+	this.dispatch_table[abi.ATOI] = func(bytecode *abi.Bytecode) {
+		this.Atoi()
+	}
+	this.dispatch_table[abi.RAND] = func(bytecode *abi.Bytecode) {
+		this.Rand()
+	}
+	this.dispatch_table[abi.SRAND] = func(bytecode *abi.Bytecode) {
+		this.Srand()
+	}
+	this.dispatch_table[abi.GETTIMEOFDAY] = func(bytecode *abi.Bytecode) {
+		this.Gettimeofday()
+	}
+	this.dispatch_table[abi.GETOPT] = func(bytecode *abi.Bytecode) {
+		this.Getopt()
+	}
+	this.dispatch_table[abi.GET_ARGC] = func(bytecode *abi.Bytecode) {
+		this.GetArgc()
+	}
+	this.dispatch_table[abi.GET_ARGV] = func(bytecode *abi.Bytecode) {
+		this.GetArgv()
+	}
+	this.dispatch_table[abi.ADD] = func(bytecode *abi.Bytecode) {
 		this.Add()
-	} else if bytecode.OpCode() == abi.SUB {
+	}
+	this.dispatch_table[abi.SUB] = func(bytecode *abi.Bytecode) {
 		this.Sub()
-	} else if bytecode.OpCode() == abi.MUL {
+	}
+	this.dispatch_table[abi.MUL] = func(bytecode *abi.Bytecode) {
 		this.Mul()
-	} else if bytecode.OpCode() == abi.DIV {
+	}
+	this.dispatch_table[abi.DIV] = func(bytecode *abi.Bytecode) {
 		this.Div()
-	} else if bytecode.OpCode() == abi.MOD {
+	}
+	this.dispatch_table[abi.MOD] = func(bytecode *abi.Bytecode) {
 		this.Mod()
-	} else if bytecode.OpCode() == abi.LSHIFT {
+	}
+	this.dispatch_table[abi.LSHIFT] = func(bytecode *abi.Bytecode) {
 		this.Lshift()
-	} else if bytecode.OpCode() == abi.RSHIFT {
+	}
+	this.dispatch_table[abi.RSHIFT] = func(bytecode *abi.Bytecode) {
 		this.Rshift()
-	} else if bytecode.OpCode() == abi.NEGATE {
+	}
+	this.dispatch_table[abi.NEGATE] = func(bytecode *abi.Bytecode) {
 		this.Negate()
-	} else if bytecode.OpCode() == abi.TILDE {
+	}
+	this.dispatch_table[abi.TILDE] = func(bytecode *abi.Bytecode) {
 		this.Tilde()
-	} else if bytecode.OpCode() == abi.SQRT {
+	}
+	this.dispatch_table[abi.SQRT] = func(bytecode *abi.Bytecode) {
 		this.Sqrt()
-	} else if bytecode.OpCode() == abi.BITWISE_AND {
+	}
+	this.dispatch_table[abi.BITWISE_AND] = func(bytecode *abi.Bytecode) {
 		this.BitwiseAnd()
-	} else if bytecode.OpCode() == abi.BITWISE_XOR {
+	}
+	this.dispatch_table[abi.BITWISE_XOR] = func(bytecode *abi.Bytecode) {
 		this.BitwiseXor()
-	} else if bytecode.OpCode() == abi.BITWISE_OR {
+	}
+	this.dispatch_table[abi.BITWISE_OR] = func(bytecode *abi.Bytecode) {
 		this.BitwiseOr()
-	} else if bytecode.OpCode() == abi.LOGICAL_AND {
+	}
+	this.dispatch_table[abi.LOGICAL_AND] = func(bytecode *abi.Bytecode) {
 		this.LogicalAnd()
-	} else if bytecode.OpCode() == abi.LOGICAL_OR {
+	}
+	this.dispatch_table[abi.LOGICAL_OR] = func(bytecode *abi.Bytecode) {
 		this.LogicalOr()
-	} else if bytecode.OpCode() == abi.LOGICAL_NOT {
+	}
+	this.dispatch_table[abi.LOGICAL_NOT] = func(bytecode *abi.Bytecode) {
 		this.LogicalNot()
-	} else if bytecode.OpCode() == abi.EQ {
+	}
+	this.dispatch_table[abi.EQ] = func(bytecode *abi.Bytecode) {
 		this.Eq()
-	} else if bytecode.OpCode() == abi.NOT_EQ {
+	}
+	this.dispatch_table[abi.NOT_EQ] = func(bytecode *abi.Bytecode) {
 		this.NotEq()
-	} else if bytecode.OpCode() == abi.LESS {
+	}
+	this.dispatch_table[abi.LESS] = func(bytecode *abi.Bytecode) {
 		this.Less()
-	} else if bytecode.OpCode() == abi.LESS_EQ {
+	}
+	this.dispatch_table[abi.LESS_EQ] = func(bytecode *abi.Bytecode) {
 		this.LessEq()
-	} else if bytecode.OpCode() == abi.GREATER {
+	}
+	this.dispatch_table[abi.GREATER] = func(bytecode *abi.Bytecode) {
 		this.Greater()
-	} else if bytecode.OpCode() == abi.GREATER_EQ {
+	}
+	this.dispatch_table[abi.GREATER_EQ] = func(bytecode *abi.Bytecode) {
 		this.GreaterEq()
-	} else if bytecode.OpCode() == abi.CONDITIONAL {
+	}
+	this.dispatch_table[abi.CONDITIONAL] = func(bytecode *abi.Bytecode) {
 		this.Conditional()
-	} else if bytecode.OpCode() == abi.ASSIGN {
+	}
+	this.dispatch_table[abi.ASSIGN] = func(bytecode *abi.Bytecode) {
 		this.Assign()
-	} else if bytecode.OpCode() == abi.ASSIGN_STAR {
+	}
+	this.dispatch_table[abi.ASSIGN_STAR] = func(bytecode *abi.Bytecode) {
 		this.AssignStar()
-	} else if bytecode.OpCode() == abi.ASSIGN_DIV {
+	}
+	this.dispatch_table[abi.ASSIGN_DIV] = func(bytecode *abi.Bytecode) {
 		this.AssignDiv()
-	} else if bytecode.OpCode() == abi.ASSIGN_MOD {
+	}
+	this.dispatch_table[abi.ASSIGN_MOD] = func(bytecode *abi.Bytecode) {
 		this.AssignMod()
-	} else if bytecode.OpCode() == abi.ASSIGN_ADD {
+	}
+	this.dispatch_table[abi.ASSIGN_ADD] = func(bytecode *abi.Bytecode) {
 		this.AssignAdd()
-	} else if bytecode.OpCode() == abi.ASSIGN_SUB {
+	}
+	this.dispatch_table[abi.ASSIGN_SUB] = func(bytecode *abi.Bytecode) {
 		this.AssignSub()
-	} else if bytecode.OpCode() == abi.ASSIGN_LSHIFT {
+	}
+	this.dispatch_table[abi.ASSIGN_LSHIFT] = func(bytecode *abi.Bytecode) {
 		this.AssignLshift()
-	} else if bytecode.OpCode() == abi.ASSIGN_RSHIFT {
+	}
+	this.dispatch_table[abi.ASSIGN_RSHIFT] = func(bytecode *abi.Bytecode) {
 		this.AssignRshift()
-	} else if bytecode.OpCode() == abi.ASSIGN_BITWISE_AND {
+	}
+	this.dispatch_table[abi.ASSIGN_BITWISE_AND] = func(bytecode *abi.Bytecode) {
 		this.AssignBitwiseAnd()
-	} else if bytecode.OpCode() == abi.ASSIGN_BITWISE_XOR {
+	}
+	this.dispatch_table[abi.ASSIGN_BITWISE_XOR] = func(bytecode *abi.Bytecode) {
 		this.AssignBitwiseXor()
-	} else if bytecode.OpCode() == abi.ASSIGN_BITWISE_OR {
+	}
+	this.dispatch_table[abi.ASSIGN_BITWISE_OR] = func(bytecode *abi.Bytecode) {
 		this.AssignBitwiseOr()
-	} else if bytecode.OpCode() == abi.ASSIGN_PLUS_PLUS {
+	}
+	this.dispatch_table[abi.ASSIGN_PLUS_PLUS] = func(bytecode *abi.Bytecode) {
 		this.AssignPlusPlus()
-	} else if bytecode.OpCode() == abi.ASSIGN_MINUS_MINUS {
+	}
+	this.dispatch_table[abi.ASSIGN_MINUS_MINUS] = func(bytecode *abi.Bytecode) {
 		this.AssignMinusMinus()
-	} else if bytecode.OpCode() == abi.ASSIGN_RETURN {
+	}
+	this.dispatch_table[abi.ASSIGN_RETURN] = func(bytecode *abi.Bytecode) {
 		this.AssignReturn()
-	} else if bytecode.OpCode() == abi.JUMP {
-		label_name := bytecode.Str1()
-		label := this.app.Label(label_name)
-
-		this.Jump(label)
-	} else if bytecode.OpCode() == abi.JUMP_IF_ZERO {
-		label_name := bytecode.Str1()
-		label := this.app.Label(label_name)
-
-		this.JumpIfZero(label)
-	} else if bytecode.OpCode() == abi.JUMP_IF_NONZERO {
-		label_name := bytecode.Str1()
-		label := this.app.Label(label_name)
-
-		this.JumpIfNonZero(label)
-	} else if bytecode.OpCode() == abi.CALL {
-		label_name := bytecode.Str1()
-		label := this.app.Label(label_name)
-
-		this.Call(label)
-	} else if bytecode.OpCode() == abi.RETURN {
+	}
+	this.dispatch_table[abi.JUMP] = func(bytecode *abi.Bytecode) {
+		this.Jump(bytecode.Target())
+	}
+	this.dispatch_table[abi.JUMP_IF_ZERO] = func(bytecode *abi.Bytecode) {
+		this.JumpIfZero(bytecode.Target())
+	}
+	this.dispatch_table[abi.JUMP_IF_NONZERO] = func(bytecode *abi.Bytecode) {
+		this.JumpIfNonZero(bytecode.Target())
+	}
+	this.dispatch_table[abi.CALL] = func(bytecode *abi.Bytecode) {
+		this.Call(bytecode.Target())
+	}
+	this.dispatch_table[abi.RETURN] = func(bytecode *abi.Bytecode) {
 		this.Return()
-	} else if bytecode.OpCode() == abi.NOP {
+	}
+	this.dispatch_table[abi.NOP] = func(bytecode *abi.Bytecode) {
 		this.Nop()
-	} else if bytecode.OpCode() == abi.DPU_ALLOC {
-		dpu_id := bytecode.Arg1()
-
-		this.DpuAlloc(dpu_id)
-	} else if bytecode.OpCode() == abi.DPU_LOAD {
+	}
+	this.dispatch_table[abi.DPU_ALLOC] = func(bytecode *abi.Bytecode) {
+		this.DpuAlloc()
+	}
+	this.dispatch_table[abi.DPU_ALLOC_RANKS] = func(bytecode *abi.Bytecode) {
+		this.DpuAllocRanks()
+	}
+	this.dispatch_table[abi.DPU_LOAD] = func(bytecode *abi.Bytecode) {
 		this.DpuLoad()
-	} else if bytecode.OpCode() == abi.DPU_PREPARE {
+	}
+	this.dispatch_table[abi.DPU_PREPARE] = func(bytecode *abi.Bytecode) {
 		this.DpuPrepare()
-	} else if bytecode.OpCode() == abi.DPU_TRANSFER {
+	}
+	this.dispatch_table[abi.DPU_TRANSFER] = func(bytecode *abi.Bytecode) {
 		this.DpuTransfer()
-	} else if bytecode.OpCode() == abi.DPU_COPY_TO {
+	}
+	this.dispatch_table[abi.DPU_BROADCAST] = func(bytecode *abi.Bytecode) {
+		this.DpuBroadcast()
+	}
+	this.dispatch_table[abi.DPU_COPY_TO] = func(bytecode *abi.Bytecode) {
 		this.DpuCopyTo()
-	} else if bytecode.OpCode() == abi.DPU_COPY_FROM {
+	}
+	this.dispatch_table[abi.DPU_COPY_FROM] = func(bytecode *abi.Bytecode) {
 		this.DpuCopyFrom()
-	} else if bytecode.OpCode() == abi.DPU_LAUNCH {
+	}
+	this.dispatch_table[abi.DPU_LAUNCH] = func(bytecode *abi.Bytecode) {
 		this.DpuLaunch()
-	} else if bytecode.OpCode() == abi.DPU_FREE {
+	}
+	this.dispatch_table[abi.DPU_SYNC] = func(bytecode *abi.Bytecode) {
+		this.DpuSync()
+	}
+	this.dispatch_table[abi.DPU_FREE] = func(bytecode *abi.Bytecode) {
 		this.DpuFree()
-	} else {
-		err := errors.New("op code is not valid")
-		panic(err)
 	}
-
-	if this.verbose >= 2 {
-		fmt.Printf("%s\n", this.Stringify())
+	this.dispatch_table[abi.DPU_NR_DPUS] = func(bytecode *abi.Bytecode) {
+		this.DpuNrDpus()
+	}
+	this.dispatch_table[abi.DPU_NR_RANKS] = func(bytecode *abi.Bytecode) {
+		this.DpuNrRanks()
+	}
+	this.dispatch_table[abi.DPU_GET_DPU] = func(bytecode *abi.Bytecode) {
+		this.DpuGetDpu()
+	}
+	this.dispatch_table[abi.DPU_GET_RANK] = func(bytecode *abi.Bytecode) {
+		this.DpuGetRank()
+	}
+	this.dispatch_table[abi.DPU_NR_CALLBACKS] = func(bytecode *abi.Bytecode) {
+		this.DpuNrCallbacks()
+	}
+	this.dispatch_table[abi.DPU_GET_CALLBACK] = func(bytecode *abi.Bytecode) {
+		this.DpuGetCallback()
+	}
+	this.dispatch_table[abi.DPU_LOG_READ] = func(bytecode *abi.Bytecode) {
+		this.DpuLogRead()
 	}
 }
 
@@ -494,11 +915,11 @@ func (this *VirtualMachine) PushLong(value int64) {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
-func (this *VirtualMachine) PushString(value string) {
-	object := this.arena.NewString(value)
+func (this *VirtualMachine) PushFloat(value float64) {
+	object := this.arena.NewFloat(value)
 
 	type_variable := new(type_system.TypeVariable)
-	type_variable.InitPrimitive(type_system.STRING, 0)
+	type_variable.InitPrimitive(type_system.FLOAT, 0)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -506,18 +927,51 @@ func (this *VirtualMachine) PushString(value string) {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
-func (this *VirtualMachine) Pop() {
-	this.frame_chain.LastFrame().Stack().Pop()
-}
+func (this *VirtualMachine) PushDouble(value float64) {
+	object := this.arena.NewDouble(value)
 
-func (this *VirtualMachine) BeginStruct(skeleton_name string) {
-	skeleton := new(type_system.Skeleton)
-	skeleton.Init(skeleton_name)
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, 0)
 
-	this.type_registry.AddSkeleton(skeleton)
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
 
-	this.cur_skeleton_name = new(string)
-	*this.cur_skeleton_name = skeleton_name
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) PushString(value string) {
+	object := this.arena.NewString(value)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.STRING, 0)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) Pop() {
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) Duplicate() {
+	top := this.frame_chain.LastFrame().Stack().Front(0)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(top.TypeVariable(), top.Address(), top.Size())
+
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) BeginStruct(skeleton_name string) {
+	skeleton := new(type_system.Skeleton)
+	skeleton.Init(skeleton_name)
+
+	this.type_registry.AddSkeleton(skeleton)
+
+	this.cur_skeleton_name = new(string)
+	*this.cur_skeleton_name = skeleton_name
 }
 
 func (this *VirtualMachine) AppendVoid(num_stars int64, field_name string) {
@@ -532,11 +986,12 @@ func (this *VirtualMachine) AppendVoid(num_stars int64, field_name string) {
 	skeleton.Append(field)
 }
 
-func (this *VirtualMachine) AppendChar(num_stars int64, field_name string) {
+func (this *VirtualMachine) AppendChar(num_stars int64, field_name string, is_unsigned bool) {
 	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.CHAR, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	field := new(type_system.Field)
 	field.Init(type_variable, field_name)
@@ -544,11 +999,12 @@ func (this *VirtualMachine) AppendChar(num_stars int64, field_name string) {
 	skeleton.Append(field)
 }
 
-func (this *VirtualMachine) AppendShort(num_stars int64, field_name string) {
+func (this *VirtualMachine) AppendShort(num_stars int64, field_name string, is_unsigned bool) {
 	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.SHORT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	field := new(type_system.Field)
 	field.Init(type_variable, field_name)
@@ -556,11 +1012,12 @@ func (this *VirtualMachine) AppendShort(num_stars int64, field_name string) {
 	skeleton.Append(field)
 }
 
-func (this *VirtualMachine) AppendInt(num_stars int64, field_name string) {
+func (this *VirtualMachine) AppendInt(num_stars int64, field_name string, is_unsigned bool) {
 	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.INT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	field := new(type_system.Field)
 	field.Init(type_variable, field_name)
@@ -568,11 +1025,36 @@ func (this *VirtualMachine) AppendInt(num_stars int64, field_name string) {
 	skeleton.Append(field)
 }
 
-func (this *VirtualMachine) AppendLong(num_stars int64, field_name string) {
+func (this *VirtualMachine) AppendLong(num_stars int64, field_name string, is_unsigned bool) {
 	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.LONG, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	field := new(type_system.Field)
+	field.Init(type_variable, field_name)
+
+	skeleton.Append(field)
+}
+
+func (this *VirtualMachine) AppendFloat(num_stars int64, field_name string) {
+	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, int(num_stars))
+
+	field := new(type_system.Field)
+	field.Init(type_variable, field_name)
+
+	skeleton.Append(field)
+}
+
+func (this *VirtualMachine) AppendDouble(num_stars int64, field_name string) {
+	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, int(num_stars))
 
 	field := new(type_system.Field)
 	field.Init(type_variable, field_name)
@@ -592,6 +1074,23 @@ func (this *VirtualMachine) AppendStruct(num_stars int64, struct_name string, fi
 	skeleton.Append(field)
 }
 
+// NOTE: an array field is appended as its element type and then given the length on the stack
+func (this *VirtualMachine) AppendArray() {
+	base_ := this.frame_chain.LastFrame().Stack().Front(0)
+
+	length := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
+
+	if length <= 0 {
+		err := errors.New("length of an array field is not positive")
+		panic(err)
+	}
+
+	skeleton := this.type_registry.Skeleton(*this.cur_skeleton_name)
+	skeleton.Get(skeleton.Length() - 1).SetLength(length)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
 func (this *VirtualMachine) EndStruct() {
 	this.cur_skeleton_name = nil
 }
@@ -611,7 +1110,7 @@ func (this *VirtualMachine) NewGlobalVoid(num_stars int64, symbol_name string) {
 	this.frame_chain.GlobalScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewGlobalChar(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewGlobalChar(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -621,6 +1120,7 @@ func (this *VirtualMachine) NewGlobalChar(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.CHAR, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -631,7 +1131,7 @@ func (this *VirtualMachine) NewGlobalChar(num_stars int64, symbol_name string) {
 	this.frame_chain.GlobalScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewGlobalShort(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewGlobalShort(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -641,6 +1141,7 @@ func (this *VirtualMachine) NewGlobalShort(num_stars int64, symbol_name string)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.SHORT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -651,11 +1152,12 @@ func (this *VirtualMachine) NewGlobalShort(num_stars int64, symbol_name string)
 	this.frame_chain.GlobalScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewGlobalInt(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewGlobalInt(num_stars int64, symbol_name string, is_unsigned bool) {
 	object := this.arena.NewInt(0)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.INT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -666,7 +1168,7 @@ func (this *VirtualMachine) NewGlobalInt(num_stars int64, symbol_name string) {
 	this.frame_chain.GlobalScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewGlobalLong(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewGlobalLong(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -676,6 +1178,71 @@ func (this *VirtualMachine) NewGlobalLong(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.LONG, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.GlobalScope().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewGlobalFloat(num_stars int64, symbol_name string) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewFloat(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, int(num_stars))
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.GlobalScope().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewGlobalDouble(num_stars int64, symbol_name string) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewDouble(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, int(num_stars))
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.GlobalScope().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewGlobalStruct(
+	num_stars int64,
+	struct_name string,
+	symbol_name string,
+) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewStruct(struct_name, this.type_registry.SkeletonSize(struct_name))
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitStruct(type_system.STRUCT, struct_name, int(num_stars))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -701,7 +1268,7 @@ func (this *VirtualMachine) NewFastVoid(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().FastScopeChain().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewFastChar(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewFastChar(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -711,6 +1278,7 @@ func (this *VirtualMachine) NewFastChar(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.CHAR, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -721,7 +1289,7 @@ func (this *VirtualMachine) NewFastChar(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().FastScopeChain().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewFastShort(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewFastShort(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -731,6 +1299,7 @@ func (this *VirtualMachine) NewFastShort(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.SHORT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -741,11 +1310,12 @@ func (this *VirtualMachine) NewFastShort(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().FastScopeChain().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewFastInt(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewFastInt(num_stars int64, symbol_name string, is_unsigned bool) {
 	object := this.arena.NewInt(0)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.INT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -756,7 +1326,7 @@ func (this *VirtualMachine) NewFastInt(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().FastScopeChain().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewFastLong(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewFastLong(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -766,6 +1336,47 @@ func (this *VirtualMachine) NewFastLong(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.LONG, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.LastFrame().FastScopeChain().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewFastFloat(num_stars int64, symbol_name string) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewFloat(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, int(num_stars))
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.LastFrame().FastScopeChain().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewFastDouble(num_stars int64, symbol_name string) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewDouble(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, int(num_stars))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -811,7 +1422,7 @@ func (this *VirtualMachine) NewArgVoid(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().ArgScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewArgChar(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewArgChar(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -821,6 +1432,7 @@ func (this *VirtualMachine) NewArgChar(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.CHAR, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -831,7 +1443,7 @@ func (this *VirtualMachine) NewArgChar(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().ArgScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewArgShort(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewArgShort(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -841,6 +1453,7 @@ func (this *VirtualMachine) NewArgShort(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.SHORT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -851,11 +1464,12 @@ func (this *VirtualMachine) NewArgShort(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().ArgScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewArgInt(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewArgInt(num_stars int64, symbol_name string, is_unsigned bool) {
 	object := this.arena.NewInt(0)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.INT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -866,7 +1480,7 @@ func (this *VirtualMachine) NewArgInt(num_stars int64, symbol_name string) {
 	this.frame_chain.LastFrame().ArgScope().AddSymbol(symbol_)
 }
 
-func (this *VirtualMachine) NewArgLong(num_stars int64, symbol_name string) {
+func (this *VirtualMachine) NewArgLong(num_stars int64, symbol_name string, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -876,6 +1490,47 @@ func (this *VirtualMachine) NewArgLong(num_stars int64, symbol_name string) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.LONG, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.LastFrame().ArgScope().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewArgFloat(num_stars int64, symbol_name string) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewFloat(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, int(num_stars))
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	symbol_ := new(symbol.Symbol)
+	symbol_.Init(symbol_name, type_variable, object)
+
+	this.frame_chain.LastFrame().ArgScope().AddSymbol(symbol_)
+}
+
+func (this *VirtualMachine) NewArgDouble(num_stars int64, symbol_name string) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewDouble(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, int(num_stars))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -918,7 +1573,7 @@ func (this *VirtualMachine) NewReturnVoid(num_stars int64) {
 	this.frame_chain.LastFrame().ReturnStack().Push(stack_item)
 }
 
-func (this *VirtualMachine) NewReturnChar(num_stars int64) {
+func (this *VirtualMachine) NewReturnChar(num_stars int64, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -928,6 +1583,7 @@ func (this *VirtualMachine) NewReturnChar(num_stars int64) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.CHAR, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -935,7 +1591,7 @@ func (this *VirtualMachine) NewReturnChar(num_stars int64) {
 	this.frame_chain.LastFrame().ReturnStack().Push(stack_item)
 }
 
-func (this *VirtualMachine) NewReturnShort(num_stars int64) {
+func (this *VirtualMachine) NewReturnShort(num_stars int64, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -945,6 +1601,7 @@ func (this *VirtualMachine) NewReturnShort(num_stars int64) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.SHORT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -952,11 +1609,12 @@ func (this *VirtualMachine) NewReturnShort(num_stars int64) {
 	this.frame_chain.LastFrame().ReturnStack().Push(stack_item)
 }
 
-func (this *VirtualMachine) NewReturnInt(num_stars int64) {
+func (this *VirtualMachine) NewReturnInt(num_stars int64, is_unsigned bool) {
 	object := this.arena.NewInt(0)
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.INT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -964,7 +1622,7 @@ func (this *VirtualMachine) NewReturnInt(num_stars int64) {
 	this.frame_chain.LastFrame().ReturnStack().Push(stack_item)
 }
 
-func (this *VirtualMachine) NewReturnLong(num_stars int64) {
+func (this *VirtualMachine) NewReturnLong(num_stars int64, is_unsigned bool) {
 	var object *base.Object
 	if num_stars > 0 {
 		object = this.arena.NewInt(0)
@@ -974,6 +1632,41 @@ func (this *VirtualMachine) NewReturnLong(num_stars int64) {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_system.LONG, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().ReturnStack().Push(stack_item)
+}
+
+func (this *VirtualMachine) NewReturnFloat(num_stars int64) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewFloat(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, int(num_stars))
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().ReturnStack().Push(stack_item)
+}
+
+func (this *VirtualMachine) NewReturnDouble(num_stars int64) {
+	var object *base.Object
+	if num_stars > 0 {
+		object = this.arena.NewInt(0)
+	} else {
+		object = this.arena.NewDouble(0)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, int(num_stars))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1089,10 +1782,10 @@ func (this *VirtualMachine) SizeofLong(num_stars int64) {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
-func (this *VirtualMachine) SizeofStruct(num_stars int64, struct_name string) {
+func (this *VirtualMachine) SizeofFloat(num_stars int64) {
 	var value int64
 	if num_stars == 0 {
-		value = this.type_registry.SkeletonSize(struct_name)
+		value = 4
 	} else {
 		value = 4
 	}
@@ -1108,20 +1801,18 @@ func (this *VirtualMachine) SizeofStruct(num_stars int64, struct_name string) {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
-func (this *VirtualMachine) GetIdentifier(symbol_name string) {
-	var symbol_ *symbol.Symbol
-	if this.frame_chain.LastFrame().FastScopeChain().HasSymbol(symbol_name) {
-		symbol_ = this.frame_chain.LastFrame().FastScopeChain().Symbol(symbol_name)
-	} else if this.frame_chain.GlobalScope().HasSymbol(symbol_name) {
-		symbol_ = this.frame_chain.GlobalScope().Symbol(symbol_name)
+func (this *VirtualMachine) SizeofDouble(num_stars int64) {
+	var value int64
+	if num_stars == 0 {
+		value = 8
 	} else {
-		err_msg := fmt.Sprintf("symbol (%s) is not found", symbol_name)
-		err := errors.New(err_msg)
-		panic(err)
+		value = 4
 	}
 
-	type_variable := symbol_.TypeVariable()
-	object := symbol_.Object()
+	object := this.arena.NewInt(value)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.INT, 0)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1129,18 +1820,18 @@ func (this *VirtualMachine) GetIdentifier(symbol_name string) {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
-func (this *VirtualMachine) GetArgIdentifier(symbol_name string) {
-	var symbol_ *symbol.Symbol
-	if this.frame_chain.LastFrame().ArgScope().HasSymbol(symbol_name) {
-		symbol_ = this.frame_chain.LastFrame().ArgScope().Symbol(symbol_name)
+func (this *VirtualMachine) SizeofStruct(num_stars int64, struct_name string) {
+	var value int64
+	if num_stars == 0 {
+		value = this.type_registry.SkeletonSize(struct_name)
 	} else {
-		err_msg := fmt.Sprintf("symbol (%s) is not found", symbol_name)
-		err := errors.New(err_msg)
-		panic(err)
+		value = 4
 	}
 
-	type_variable := symbol_.TypeVariable()
-	object := symbol_.Object()
+	object := this.arena.NewInt(value)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.INT, 0)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1148,9 +1839,154 @@ func (this *VirtualMachine) GetArgIdentifier(symbol_name string) {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
-func (this *VirtualMachine) GetSubscript() {
-	base_ := this.frame_chain.LastFrame().Stack().Front(1)
-	index := this.frame_chain.LastFrame().Stack().Front(0)
+// NOTE: a cast to void discards nothing by itself, since the expression statement pops the operand
+func (this *VirtualMachine) CastVoid(num_stars int64) {
+	if num_stars == 0 {
+		return
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.VOID, int(num_stars))
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastChar(num_stars int64, is_unsigned bool) {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.CHAR, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastShort(num_stars int64, is_unsigned bool) {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.SHORT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastInt(num_stars int64, is_unsigned bool) {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.INT, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastLong(num_stars int64, is_unsigned bool) {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.LONG, int(num_stars))
+	type_variable.SetUnsigned(is_unsigned)
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastFloat(num_stars int64) {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.FLOAT, int(num_stars))
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastDouble(num_stars int64) {
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.DOUBLE, int(num_stars))
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) CastStruct(num_stars int64, struct_name string) {
+	if num_stars == 0 {
+		err := errors.New("struct cannot be casted")
+		panic(err)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitStruct(type_system.STRUCT, struct_name, int(num_stars))
+
+	this.Cast(type_variable)
+}
+
+func (this *VirtualMachine) Cast(type_variable *type_system.TypeVariable) {
+	operand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	var object *base.Object
+	if type_variable.NumStars() > 0 {
+		if this.IsFloating(operand) {
+			err := errors.New("floating point operand cannot be casted into a pointer")
+			panic(err)
+		}
+
+		object = this.arena.NewInt(this.ReadInteger(operand))
+	} else if type_variable.TypeVariableType() == type_system.CHAR {
+		object = this.arena.NewChar(this.ReadInteger(operand))
+	} else if type_variable.TypeVariableType() == type_system.SHORT {
+		object = this.arena.NewShort(this.ReadInteger(operand))
+	} else if type_variable.TypeVariableType() == type_system.INT {
+		object = this.arena.NewInt(this.ReadInteger(operand))
+	} else if type_variable.TypeVariableType() == type_system.LONG {
+		object = this.arena.NewLong(this.ReadInteger(operand))
+	} else if type_variable.TypeVariableType() == type_system.FLOAT {
+		object = this.arena.NewFloat(this.ReadFloating(operand))
+	} else if type_variable.TypeVariableType() == type_system.DOUBLE {
+		object = this.arena.NewDouble(this.ReadFloating(operand))
+	} else {
+		err := errors.New("type variable type cannot be casted")
+		panic(err)
+	}
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) GetIdentifier(symbol_name string) {
+	var symbol_ *symbol.Symbol
+	if this.frame_chain.LastFrame().FastScopeChain().HasSymbol(symbol_name) {
+		symbol_ = this.frame_chain.LastFrame().FastScopeChain().Symbol(symbol_name)
+	} else if this.frame_chain.GlobalScope().HasSymbol(symbol_name) {
+		symbol_ = this.frame_chain.GlobalScope().Symbol(symbol_name)
+	} else {
+		err_msg := fmt.Sprintf("symbol (%s) is not found", symbol_name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	type_variable := symbol_.TypeVariable()
+	object := symbol_.Object()
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) GetArgIdentifier(symbol_name string) {
+	var symbol_ *symbol.Symbol
+	if this.frame_chain.LastFrame().ArgScope().HasSymbol(symbol_name) {
+		symbol_ = this.frame_chain.LastFrame().ArgScope().Symbol(symbol_name)
+	} else {
+		err_msg := fmt.Sprintf("symbol (%s) is not found", symbol_name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	type_variable := symbol_.TypeVariable()
+	object := symbol_.Object()
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) GetSubscript() {
+	base_ := this.frame_chain.LastFrame().Stack().Front(1)
+	index := this.frame_chain.LastFrame().Stack().Front(0)
 
 	base_value := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
 	index_value := this.arena.Pool().Memory().Read(index.Address(), index.Size()).SignedValue()
@@ -1189,6 +2025,18 @@ func (this *VirtualMachine) GetSubscript() {
 			size = 8
 
 			type_variable.InitPrimitive(type_system.LONG, base_.TypeVariable().NumStars()-1)
+		} else if base_.TypeVariable().TypeVariableType() == type_system.FLOAT {
+			offset = index_value * 4
+
+			size = 4
+
+			type_variable.InitPrimitive(type_system.FLOAT, base_.TypeVariable().NumStars()-1)
+		} else if base_.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+			offset = index_value * 8
+
+			size = 8
+
+			type_variable.InitPrimitive(type_system.DOUBLE, base_.TypeVariable().NumStars()-1)
 		} else if base_.TypeVariable().TypeVariableType() == type_system.STRUCT {
 			struct_name := base_.TypeVariable().StructName()
 
@@ -1215,6 +2063,10 @@ func (this *VirtualMachine) GetSubscript() {
 			type_variable.InitPrimitive(type_system.INT, base_.TypeVariable().NumStars()-1)
 		} else if base_.TypeVariable().TypeVariableType() == type_system.LONG {
 			type_variable.InitPrimitive(type_system.LONG, base_.TypeVariable().NumStars()-1)
+		} else if base_.TypeVariable().TypeVariableType() == type_system.FLOAT {
+			type_variable.InitPrimitive(type_system.FLOAT, base_.TypeVariable().NumStars()-1)
+		} else if base_.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+			type_variable.InitPrimitive(type_system.DOUBLE, base_.TypeVariable().NumStars()-1)
 		} else if base_.TypeVariable().TypeVariableType() == type_system.STRUCT {
 			struct_name := base_.TypeVariable().StructName()
 
@@ -1225,6 +2077,10 @@ func (this *VirtualMachine) GetSubscript() {
 		}
 	}
 
+	if base_.TypeVariable().IsUnsigned() {
+		type_variable.SetUnsigned(true)
+	}
+
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, base_value+offset, size)
 
@@ -1248,51 +2104,7 @@ func (this *VirtualMachine) GetAccess(field_name string) {
 
 	offset := this.type_registry.FieldOffset(struct_name, field_name)
 
-	field := this.type_registry.Skeleton(struct_name).Field(field_name)
-
-	type_variable := field.TypeVariable()
-
-	var size int64
-	if field.TypeVariable().TypeVariableType() == type_system.VOID {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			err := errors.New("void type has no star")
-			panic(err)
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.CHAR {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = 1
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.SHORT {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = 2
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.INT {
-		size = 4
-	} else if field.TypeVariable().TypeVariableType() == type_system.LONG {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = 8
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.STRUCT {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = this.type_registry.SkeletonSize(base_.TypeVariable().StructName())
-		}
-	} else {
-		err := errors.New("type variable type is not valid")
-		panic(err)
-	}
-
-	stack_item := new(stack.StackItem)
-	stack_item.Init(type_variable, base_.Address()+offset, size)
+	stack_item := this.Field(struct_name, field_name, base_.Address()+offset)
 
 	this.frame_chain.LastFrame().Stack().Pop()
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
@@ -1316,56 +2128,34 @@ func (this *VirtualMachine) GetReference(field_name string) {
 
 	offset := this.type_registry.FieldOffset(struct_name, field_name)
 
-	field := this.type_registry.Skeleton(struct_name).Field(field_name)
+	address := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
 
-	type_variable := field.TypeVariable()
+	stack_item := this.Field(struct_name, field_name, address+offset)
 
-	var size int64
-	if field.TypeVariable().TypeVariableType() == type_system.VOID {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			err := errors.New("void type has no star")
-			panic(err)
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.CHAR {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = 1
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.SHORT {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = 2
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.INT {
-		size = 4
-	} else if field.TypeVariable().TypeVariableType() == type_system.LONG {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = 8
-		}
-	} else if field.TypeVariable().TypeVariableType() == type_system.STRUCT {
-		if field.TypeVariable().NumStars() > 0 {
-			size = 4
-		} else {
-			size = this.type_registry.SkeletonSize(base_.TypeVariable().StructName())
-		}
-	} else {
-		err := errors.New("type variable type is not valid")
-		panic(err)
-	}
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
 
-	address := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
+// NOTE: an array field decays to a pointer to its first element as an array does, so that its
+// elements are subscripted in place
+func (this *VirtualMachine) Field(
+	struct_name string,
+	field_name string,
+	address int64,
+) *stack.StackItem {
+	field := this.type_registry.Skeleton(struct_name).Field(field_name)
 
 	stack_item := new(stack.StackItem)
-	stack_item.Init(type_variable, address+offset, size)
+	if field.IsArray() {
+		object := this.arena.NewInt(address)
 
-	this.frame_chain.LastFrame().Stack().Pop()
-	this.frame_chain.LastFrame().Stack().Push(stack_item)
+		stack_item.Init(field.TypeVariable().Reference(), object.Address(), object.Size())
+	} else {
+		size := this.type_registry.FieldSize(struct_name, field_name)
+
+		stack_item.Init(field.TypeVariable(), address, size)
+	}
+	return stack_item
 }
 
 func (this *VirtualMachine) GetAddress() {
@@ -1384,6 +2174,10 @@ func (this *VirtualMachine) GetAddress() {
 		type_variable.InitPrimitive(type_system.INT, base_.TypeVariable().NumStars()+1)
 	} else if base_.TypeVariable().TypeVariableType() == type_system.LONG {
 		type_variable.InitPrimitive(type_system.LONG, base_.TypeVariable().NumStars()+1)
+	} else if base_.TypeVariable().TypeVariableType() == type_system.FLOAT {
+		type_variable.InitPrimitive(type_system.FLOAT, base_.TypeVariable().NumStars()+1)
+	} else if base_.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+		type_variable.InitPrimitive(type_system.DOUBLE, base_.TypeVariable().NumStars()+1)
 	} else if base_.TypeVariable().TypeVariableType() == type_system.STRUCT {
 		type_variable.InitStruct(type_system.STRUCT, base_.TypeVariable().StructName(), base_.TypeVariable().NumStars()+1)
 	} else {
@@ -1391,6 +2185,10 @@ func (this *VirtualMachine) GetAddress() {
 		panic(err)
 	}
 
+	if base_.TypeVariable().IsUnsigned() {
+		type_variable.SetUnsigned(true)
+	}
+
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
 
@@ -1421,6 +2219,10 @@ func (this *VirtualMachine) GetValue() {
 		type_variable.InitPrimitive(type_system.INT, base_.TypeVariable().NumStars()-1)
 	} else if base_.TypeVariable().TypeVariableType() == type_system.LONG {
 		type_variable.InitPrimitive(type_system.LONG, base_.TypeVariable().NumStars()-1)
+	} else if base_.TypeVariable().TypeVariableType() == type_system.FLOAT {
+		type_variable.InitPrimitive(type_system.FLOAT, base_.TypeVariable().NumStars()-1)
+	} else if base_.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+		type_variable.InitPrimitive(type_system.DOUBLE, base_.TypeVariable().NumStars()-1)
 	} else if base_.TypeVariable().TypeVariableType() == type_system.STRUCT {
 		type_variable.InitStruct(type_system.STRUCT, base_.TypeVariable().StructName(), base_.TypeVariable().NumStars()-1)
 	} else {
@@ -1428,6 +2230,10 @@ func (this *VirtualMachine) GetValue() {
 		panic(err)
 	}
 
+	if base_.TypeVariable().IsUnsigned() {
+		type_variable.SetUnsigned(true)
+	}
+
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
 
@@ -1476,88 +2282,480 @@ func (this *VirtualMachine) Assert() {
 	this.frame_chain.LastFrame().Stack().Pop()
 }
 
-func (this *VirtualMachine) Add() {
-	loperand := this.frame_chain.LastFrame().Stack().Front(1)
-	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+// NOTE: the stream is 2 for stderr and 1 for stdout, below the format and the values on the stack
+func (this *VirtualMachine) Printf(num_values int64) {
+	stream := this.frame_chain.LastFrame().Stack().Front(int(num_values) + 1)
+	format := this.frame_chain.LastFrame().Stack().Front(int(num_values))
 
-	if loperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("loperand's num stars != 0")
-		panic(err)
-	} else if roperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("roperand's num stars != 0")
-		panic(err)
+	values := make([]*stack.StackItem, 0)
+	for i := num_values - 1; i >= 0; i-- {
+		values = append(values, this.frame_chain.LastFrame().Stack().Front(int(i)))
 	}
 
-	type_variable_type := this.TypeCast(loperand, roperand)
-
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
-
-	value := loperand_value + roperand_value
+	ss := this.Format(this.ReadString(format), values)
 
-	var object *base.Object
-	if type_variable_type == type_system.CHAR {
-		object = this.arena.NewChar(value)
-	} else if type_variable_type == type_system.SHORT {
-		object = this.arena.NewShort(value)
-	} else if type_variable_type == type_system.INT {
-		object = this.arena.NewInt(value)
-	} else if type_variable_type == type_system.LONG {
-		object = this.arena.NewLong(value)
+	if this.ReadInteger(stream) == 2 {
+		fmt.Fprint(os.Stderr, ss)
 	} else {
-		err := errors.New("loperand and roperand cannot be added")
-		panic(err)
+		fmt.Print(ss)
 	}
 
-	type_variable := new(type_system.TypeVariable)
-	type_variable.InitPrimitive(type_variable_type, 0)
+	for i := int64(0); i < num_values+2; i++ {
+		this.frame_chain.LastFrame().Stack().Pop()
+	}
+}
 
-	stack_item := new(stack.StackItem)
-	stack_item.Init(type_variable, object.Address(), object.Size())
+// NOTE: the conversions of printf are those of Go once the length modifiers are dropped, since
+// the values are read with the sizes of their types, except that %g takes the precision of C
+func (this *VirtualMachine) Format(format string, values []*stack.StackItem) string {
+	ss := ""
+	num_values := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			ss += string(format[i])
+			continue
+		}
 
-	this.frame_chain.LastFrame().Stack().Pop()
-	this.frame_chain.LastFrame().Stack().Pop()
-	this.frame_chain.LastFrame().Stack().Push(stack_item)
-}
+		j := i + 1
+		for j < len(format) && strings.ContainsRune("-+ #0123456789.", rune(format[j])) {
+			j++
+		}
 
-func (this *VirtualMachine) Sub() {
-	loperand := this.frame_chain.LastFrame().Stack().Front(1)
-	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+		spec := format[i:j]
 
-	if loperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("loperand's num stars != 0")
-		panic(err)
-	} else if roperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("roperand's num stars != 0")
-		panic(err)
-	}
+		for j < len(format) && strings.ContainsRune("hlLqjzt", rune(format[j])) {
+			j++
+		}
 
-	type_variable_type := this.TypeCast(loperand, roperand)
+		if j == len(format) {
+			err_msg := fmt.Sprintf("format (%s) of printf is not valid", format)
+			err := errors.New(err_msg)
+			panic(err)
+		}
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+		conversion := format[j]
+		i = j
 
-	value := loperand_value - roperand_value
+		if conversion == '%' {
+			ss += "%"
+			continue
+		} else if num_values == len(values) {
+			err_msg := fmt.Sprintf("format (%s) of printf has more conversions than values", format)
+			err := errors.New(err_msg)
+			panic(err)
+		}
 
-	var object *base.Object
-	if type_variable_type == type_system.CHAR {
-		object = this.arena.NewChar(value)
-	} else if type_variable_type == type_system.SHORT {
-		object = this.arena.NewShort(value)
-	} else if type_variable_type == type_system.INT {
-		object = this.arena.NewInt(value)
+		value := values[num_values]
+		num_values++
+
+		if conversion == 's' {
+			ss += fmt.Sprintf(spec+"s", this.ReadString(value))
+		} else if conversion == 'c' {
+			ss += fmt.Sprintf(spec+"c", rune(uint8(this.ReadInteger(value))))
+		} else if strings.ContainsRune("fFeEgG", rune(conversion)) {
+			if strings.ContainsRune("gG", rune(conversion)) && !strings.Contains(spec, ".") {
+				spec += ".6"
+			}
+
+			ss += fmt.Sprintf(spec+string(conversion), this.ReadFloating(value))
+		} else if conversion == 'd' || conversion == 'i' {
+			ss += fmt.Sprintf(spec+"d", this.ReadPrintfValue(value, true))
+		} else if conversion == 'u' {
+			ss += fmt.Sprintf(spec+"d", uint64(this.ReadPrintfValue(value, false)))
+		} else if strings.ContainsRune("xXo", rune(conversion)) {
+			ss += fmt.Sprintf(spec+string(conversion), uint64(this.ReadPrintfValue(value, false)))
+		} else if conversion == 'p' {
+			ss += fmt.Sprintf("0x%x", uint64(this.ReadPrintfValue(value, false)))
+		} else {
+			err_msg := fmt.Sprintf("conversion (%c) of printf is not supported", conversion)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+	return ss
+}
+
+// NOTE: an integer is printed at the size of its type, e.g. -1 of an int is 4294967295 by %u
+func (this *VirtualMachine) ReadPrintfValue(stack_item *stack.StackItem, is_signed bool) int64 {
+	if this.IsFloating(stack_item) {
+		return int64(this.ReadFloating(stack_item))
+	}
+
+	byte_stream := this.arena.Pool().Memory().Read(stack_item.Address(), stack_item.Size())
+
+	if is_signed {
+		return byte_stream.SignedValue()
+	} else {
+		return byte_stream.UnsignedValue()
+	}
+}
+
+// NOTE: a string is either a literal, which is kept with its quotes and escapes, or a pointer to
+// characters that end with a zero
+func (this *VirtualMachine) ReadString(stack_item *stack.StackItem) string {
+	if stack_item.TypeVariable().TypeVariableType() == type_system.STRING {
+		literal := this.DecodeString(
+			this.arena.Pool().Memory().Read(stack_item.Address(), stack_item.Size()),
+		)
+
+		str, unquote_err := strconv.Unquote(literal)
+		if unquote_err != nil {
+			panic(unquote_err)
+		}
+		return str
+	}
+
+	return this.ReadCharacters(this.ReadInteger(stack_item))
+}
+
+func (this *VirtualMachine) ReadCharacters(address int64) string {
+	characters := make([]byte, 0)
+	for {
+		character := this.arena.Pool().Memory().Read(address, 1).Get(0)
+		if character == 0 {
+			break
+		}
+
+		characters = append(characters, character)
+		address++
+	}
+	return string(characters)
+}
+
+func (this *VirtualMachine) Exit() {
+	status := this.frame_chain.LastFrame().Stack().Front(0)
+
+	this.stat_factory.Set("exit_status", this.ReadInteger(status))
+	this.is_exited = true
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+// NOTE: atoi skips the leading white spaces and reads the digits after an optional sign up to the
+// first character that is not a digit, wrapping around as an int
+func (this *VirtualMachine) Atoi() {
+	str := this.ReadString(this.frame_chain.LastFrame().Stack().Front(0))
+	str = strings.TrimLeft(str, " \t\n\v\f\r")
+
+	sign := int64(1)
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		if str[0] == '-' {
+			sign = -1
+		}
+		str = str[1:]
+	}
+
+	value := int64(0)
+	for i := 0; i < len(str) && str[i] >= '0' && str[i] <= '9'; i++ {
+		value = value*10 + int64(str[i]-'0')
+	}
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.PushInt(int64(int32(sign * value)))
+}
+
+// NOTE: rand is the generator of the C standard, whose RAND_MAX is 32767, so that the numbers of a
+// seed are the same on every host
+func (this *VirtualMachine) Rand() {
+	this.rand_next = this.rand_next*1103515245 + 12345
+
+	this.PushInt(int64((this.rand_next / 65536) % 32768))
+}
+
+func (this *VirtualMachine) Srand() {
+	seed := this.frame_chain.LastFrame().Stack().Front(0)
+
+	this.rand_next = uint32(this.ReadInteger(seed))
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) GetArgc() {
+	this.PushInt(int64(len(this.host_args)))
+}
+
+// NOTE: the host args are copied to the memory on the first use of argv, as an array of pointers
+// to their characters that ends with NULL, and are never freed
+func (this *VirtualMachine) GetArgv() {
+	if this.argv == 0 {
+		argv := this.arena.NewPointer(int64(4 * (len(this.host_args) + 1)))
+
+		for i, host_arg := range this.host_args {
+			byte_stream := new(encoding.ByteStream)
+			byte_stream.Init()
+			for j := 0; j < len(host_arg); j++ {
+				byte_stream.Append(host_arg[j])
+			}
+			byte_stream.Append(0)
+
+			characters := this.arena.NewPointer(byte_stream.Size())
+			this.arena.Pool().Memory().Write(characters.Address(), byte_stream.Size(), byte_stream)
+
+			pointer := this.EncodeValue(characters.Address(), 4)
+			this.arena.Pool().Memory().Write(argv.Address()+int64(4*i), 4, pointer)
+		}
+
+		null := this.EncodeValue(0, 4)
+		this.arena.Pool().Memory().Write(argv.Address()+int64(4*len(this.host_args)), 4, null)
+
+		this.argv = argv.Address()
+	}
+
+	pointer := this.arena.NewInt(this.argv)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.CHAR, 2)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, pointer.Address(), pointer.Size())
+
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+// NOTE: getopt is the one of POSIX without the long options, which scans the args from optind on
+// and stops at the first arg that is not an option, and optind is kept by the VM since the host
+// programs only read optarg
+func (this *VirtualMachine) Getopt() {
+	argc := this.ReadInteger(this.frame_chain.LastFrame().Stack().Front(2))
+	argv := this.ReadInteger(this.frame_chain.LastFrame().Stack().Front(1))
+	optstring := this.ReadString(this.frame_chain.LastFrame().Stack().Front(0))
+
+	for i := 0; i < 3; i++ {
+		this.frame_chain.LastFrame().Stack().Pop()
+	}
+
+	this.PushInt(this.NextOption(int(argc), argv, optstring))
+}
+
+func (this *VirtualMachine) NextOption(argc int, argv int64, optstring string) int64 {
+	if this.optind >= argc {
+		return -1
+	}
+
+	arg_address := this.arena.Pool().Memory().Read(argv+int64(4*this.optind), 4).SignedValue()
+	arg := this.ReadCharacters(arg_address)
+
+	if this.optpos == 1 {
+		if len(arg) < 2 || arg[0] != '-' {
+			return -1
+		} else if arg == "--" {
+			this.optind++
+			return -1
+		}
+	}
+
+	option := arg[this.optpos]
+	this.optpos++
+
+	index := strings.IndexByte(optstring, option)
+	if option == ':' || index < 0 {
+		this.NextArg(len(arg))
+
+		fmt.Fprintf(os.Stderr, "%s: invalid option -- '%c'\n", this.host_args[0], option)
+		return '?'
+	} else if index+1 == len(optstring) || optstring[index+1] != ':' {
+		this.NextArg(len(arg))
+		return int64(option)
+	}
+
+	optarg := this.frame_chain.GlobalScope().Symbol("optarg").Object()
+
+	if this.optpos < len(arg) {
+		this.arena.Pool().Memory().Write(
+			optarg.Address(),
+			4,
+			this.EncodeValue(arg_address+int64(this.optpos), 4),
+		)
+
+		this.optind++
+	} else if this.optind+1 < argc {
+		optarg_address := argv + int64(4*(this.optind+1))
+		this.arena.Pool().Memory().Write(
+			optarg.Address(),
+			4,
+			this.arena.Pool().Memory().Read(optarg_address, 4),
+		)
+
+		this.optind += 2
+	} else {
+		this.optind++
+		this.optpos = 1
+
+		fmt.Fprintf(
+			os.Stderr,
+			"%s: option requires an argument -- '%c'\n",
+			this.host_args[0],
+			option,
+		)
+		return '?'
+	}
+
+	this.optpos = 1
+	return int64(option)
+}
+
+// NOTE: NextArg moves on to the next arg once the options of the current one are all parsed
+func (this *VirtualMachine) NextArg(arg_length int) {
+	if this.optpos >= arg_length {
+		this.optind++
+		this.optpos = 1
+	}
+}
+
+// NOTE: the time of day is the host time of the VM, so that the host programs time themselves in
+// simulated time
+func (this *VirtualMachine) Gettimeofday() {
+	pointer := this.frame_chain.LastFrame().Stack().Front(0)
+	address := this.ReadInteger(pointer)
+
+	time_ns := this.host_core.TimeNs()
+
+	sec_address := address + this.type_registry.FieldOffset("timeval", "tv_sec")
+	usec_address := address + this.type_registry.FieldOffset("timeval", "tv_usec")
+
+	this.arena.Pool().Memory().Write(sec_address, 8, this.EncodeValue(time_ns/1000000000, 8))
+	this.arena.Pool().Memory().Write(usec_address, 8, this.EncodeValue(time_ns%1000000000/1000, 8))
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) Add() {
+	loperand := this.frame_chain.LastFrame().Stack().Front(1)
+	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	if loperand.TypeVariable().NumStars() != 0 || roperand.TypeVariable().NumStars() != 0 {
+		this.PointerAdd(1)
+		return
+	}
+
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.ADD)
+		return
+	}
+
+	type_variable_type := this.TypeCast(loperand, roperand)
+
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	value := loperand_value + roperand_value
+
+	var object *base.Object
+	if type_variable_type == type_system.CHAR {
+		object = this.arena.NewChar(value)
+	} else if type_variable_type == type_system.SHORT {
+		object = this.arena.NewShort(value)
+	} else if type_variable_type == type_system.INT {
+		object = this.arena.NewInt(value)
+	} else if type_variable_type == type_system.LONG {
+		object = this.arena.NewLong(value)
+	} else {
+		err := errors.New("loperand and roperand cannot be added")
+		panic(err)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+// NOTE: an integer added to or subtracted from a pointer moves it by whole elements of the type
+// that it points to, as in C
+func (this *VirtualMachine) PointerAdd(sign int64) {
+	loperand := this.frame_chain.LastFrame().Stack().Front(1)
+	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	pointer, integer := loperand, roperand
+	if roperand.TypeVariable().NumStars() != 0 {
+		if loperand.TypeVariable().NumStars() != 0 {
+			err := errors.New("two pointers cannot be added")
+			panic(err)
+		}
+
+		pointer, integer = roperand, loperand
+	}
+
+	if this.IsFloating(integer) {
+		err := errors.New("floating point value cannot be added to a pointer")
+		panic(err)
+	}
+
+	element_size := this.type_registry.TypeSize(pointer.TypeVariable().Dereference())
+
+	value := this.ReadInteger(pointer) + sign*this.ReadInteger(integer)*element_size
+
+	object := this.arena.NewInt(value)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(pointer.TypeVariable(), object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) PointerDifference() {
+	loperand := this.frame_chain.LastFrame().Stack().Front(1)
+	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	element_size := this.type_registry.TypeSize(loperand.TypeVariable().Dereference())
+
+	value := (this.ReadInteger(loperand) - this.ReadInteger(roperand)) / element_size
+
+	object := this.arena.NewLong(value)
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_system.LONG, 0)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) Sub() {
+	loperand := this.frame_chain.LastFrame().Stack().Front(1)
+	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	if loperand.TypeVariable().NumStars() != 0 && roperand.TypeVariable().NumStars() != 0 {
+		this.PointerDifference()
+		return
+	} else if loperand.TypeVariable().NumStars() != 0 {
+		this.PointerAdd(-1)
+		return
+	} else if roperand.TypeVariable().NumStars() != 0 {
+		err := errors.New("roperand's num stars != 0")
+		panic(err)
+	}
+
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.SUB)
+		return
+	}
+
+	type_variable_type := this.TypeCast(loperand, roperand)
+
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	value := loperand_value - roperand_value
+
+	var object *base.Object
+	if type_variable_type == type_system.CHAR {
+		object = this.arena.NewChar(value)
+	} else if type_variable_type == type_system.SHORT {
+		object = this.arena.NewShort(value)
+	} else if type_variable_type == type_system.INT {
+		object = this.arena.NewInt(value)
 	} else if type_variable_type == type_system.LONG {
 		object = this.arena.NewLong(value)
 	} else {
@@ -1567,6 +2765,7 @@ func (this *VirtualMachine) Sub() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1588,16 +2787,15 @@ func (this *VirtualMachine) Mul() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.MUL)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
 	value := loperand_value * roperand_value
 
@@ -1617,6 +2815,7 @@ func (this *VirtualMachine) Mul() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1638,18 +2837,25 @@ func (this *VirtualMachine) Div() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.DIV)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
-	value := loperand_value / roperand_value
+	var value int64
+	if this.IsUnsignedCast(loperand, roperand) {
+		value = int64(
+			this.ReadUnsigned(loperand, type_variable_type) /
+				this.ReadUnsigned(roperand, type_variable_type),
+		)
+	} else {
+		value = loperand_value / roperand_value
+	}
 
 	var object *base.Object
 	if type_variable_type == type_system.CHAR {
@@ -1667,6 +2873,7 @@ func (this *VirtualMachine) Div() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1690,16 +2897,18 @@ func (this *VirtualMachine) Mod() {
 
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
-	value := loperand_value % roperand_value
+	var value int64
+	if this.IsUnsignedCast(loperand, roperand) {
+		value = int64(
+			this.ReadUnsigned(loperand, type_variable_type) %
+				this.ReadUnsigned(roperand, type_variable_type),
+		)
+	} else {
+		value = loperand_value % roperand_value
+	}
 
 	var object *base.Object
 	if type_variable_type == type_system.CHAR {
@@ -1717,6 +2926,7 @@ func (this *VirtualMachine) Mod() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1740,14 +2950,8 @@ func (this *VirtualMachine) Lshift() {
 
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
 	value := loperand_value << roperand_value
 
@@ -1767,6 +2971,7 @@ func (this *VirtualMachine) Lshift() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1790,16 +2995,15 @@ func (this *VirtualMachine) Rshift() {
 
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
-	value := loperand_value >> roperand_value
+	var value int64
+	if this.IsUnsignedCast(loperand, roperand) {
+		value = int64(this.ReadUnsigned(loperand, type_variable_type) >> roperand_value)
+	} else {
+		value = loperand_value >> roperand_value
+	}
 
 	var object *base.Object
 	if type_variable_type == type_system.CHAR {
@@ -1817,6 +3021,7 @@ func (this *VirtualMachine) Rshift() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -1834,6 +3039,11 @@ func (this *VirtualMachine) Negate() {
 		panic(err)
 	}
 
+	if this.IsFloating(operand) {
+		this.FloatingUnary(abi.NEGATE)
+		return
+	}
+
 	operand_value := this.arena.Pool().
 		Memory().
 		Read(operand.Address(), operand.Size()).
@@ -1912,6 +3122,11 @@ func (this *VirtualMachine) Sqrt() {
 		panic(err)
 	}
 
+	if this.IsFloating(operand) {
+		this.FloatingUnary(abi.SQRT)
+		return
+	}
+
 	operand_value := this.arena.Pool().
 		Memory().
 		Read(operand.Address(), operand.Size()).
@@ -1957,14 +3172,8 @@ func (this *VirtualMachine) BitwiseAnd() {
 
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
 	value := loperand_value & roperand_value
 
@@ -1984,6 +3193,7 @@ func (this *VirtualMachine) BitwiseAnd() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -2007,14 +3217,8 @@ func (this *VirtualMachine) BitwiseXor() {
 
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
 	value := loperand_value ^ roperand_value
 
@@ -2034,6 +3238,7 @@ func (this *VirtualMachine) BitwiseXor() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -2057,14 +3262,8 @@ func (this *VirtualMachine) BitwiseOr() {
 
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
 
 	value := loperand_value | roperand_value
 
@@ -2084,6 +3283,7 @@ func (this *VirtualMachine) BitwiseOr() {
 
 	type_variable := new(type_system.TypeVariable)
 	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.SetUnsigned(this.IsUnsignedCast(loperand, roperand))
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -2097,103 +3297,36 @@ func (this *VirtualMachine) LogicalAnd() {
 	loperand := this.frame_chain.LastFrame().Stack().Front(1)
 	roperand := this.frame_chain.LastFrame().Stack().Front(0)
 
-	if loperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("loperand's num stars != 0")
-		panic(err)
-	} else if roperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("roperand's num stars != 0")
-		panic(err)
-	}
-
-	type_variable_type := this.TypeCast(loperand, roperand)
-
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
-
 	var value int64
-	if loperand_value != 0 && roperand_value != 0 {
+	if this.IsTrue(loperand) && this.IsTrue(roperand) {
 		value = 1
 	} else {
 		value = 0
 	}
 
-	var object *base.Object
-	if type_variable_type == type_system.CHAR {
-		object = this.arena.NewChar(value)
-	} else if type_variable_type == type_system.SHORT {
-		object = this.arena.NewShort(value)
-	} else if type_variable_type == type_system.INT {
-		object = this.arena.NewInt(value)
-	} else if type_variable_type == type_system.LONG {
-		object = this.arena.NewLong(value)
-	} else {
-		err := errors.New("loperand and roperand cannot conduct the bitwise or operation")
-		panic(err)
-	}
-
-	type_variable := new(type_system.TypeVariable)
-	type_variable.InitPrimitive(type_variable_type, 0)
-
-	stack_item := new(stack.StackItem)
-	stack_item.Init(type_variable, object.Address(), object.Size())
-
-	this.frame_chain.LastFrame().Stack().Pop()
-	this.frame_chain.LastFrame().Stack().Pop()
-	this.frame_chain.LastFrame().Stack().Push(stack_item)
+	this.PushLogical(value)
 }
 
 func (this *VirtualMachine) LogicalOr() {
 	loperand := this.frame_chain.LastFrame().Stack().Front(1)
 	roperand := this.frame_chain.LastFrame().Stack().Front(0)
 
-	if loperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("loperand's num stars != 0")
-		panic(err)
-	} else if roperand.TypeVariable().NumStars() != 0 {
-		err := errors.New("roperand's num stars != 0")
-		panic(err)
-	}
-
-	type_variable_type := this.TypeCast(loperand, roperand)
-
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
-
 	var value int64
-	if loperand_value != 0 || roperand_value != 0 {
+	if this.IsTrue(loperand) || this.IsTrue(roperand) {
 		value = 1
 	} else {
 		value = 0
 	}
 
-	var object *base.Object
-	if type_variable_type == type_system.CHAR {
-		object = this.arena.NewChar(value)
-	} else if type_variable_type == type_system.SHORT {
-		object = this.arena.NewShort(value)
-	} else if type_variable_type == type_system.INT {
-		object = this.arena.NewInt(value)
-	} else if type_variable_type == type_system.LONG {
-		object = this.arena.NewLong(value)
-	} else {
-		err := errors.New("loperand and roperand cannot conduct the bitwise or operation")
-		panic(err)
-	}
+	this.PushLogical(value)
+}
+
+// NOTE: as in C, a logical operation yields an int of either 0 or 1 whatever its operands are
+func (this *VirtualMachine) PushLogical(value int64) {
+	object := this.arena.NewInt(value)
 
 	type_variable := new(type_system.TypeVariable)
-	type_variable.InitPrimitive(type_variable_type, 0)
+	type_variable.InitPrimitive(type_system.INT, 0)
 
 	stack_item := new(stack.StackItem)
 	stack_item.Init(type_variable, object.Address(), object.Size())
@@ -2203,6 +3336,19 @@ func (this *VirtualMachine) LogicalOr() {
 	this.frame_chain.LastFrame().Stack().Push(stack_item)
 }
 
+// NOTE: a string literal is never NULL, so that it is true as in assert(x && "message"), and a
+// pointer is true unless it is NULL
+func (this *VirtualMachine) IsTrue(stack_item *stack.StackItem) bool {
+	if stack_item.TypeVariable().NumStars() == 0 &&
+		stack_item.TypeVariable().TypeVariableType() == type_system.STRING {
+		return true
+	} else if this.IsFloating(stack_item) {
+		return this.ReadFloating(stack_item) != 0
+	} else {
+		return this.ReadInteger(stack_item) != 0
+	}
+}
+
 func (this *VirtualMachine) LogicalNot() {
 	operand := this.frame_chain.LastFrame().Stack().Front(0)
 
@@ -2259,19 +3405,24 @@ func (this *VirtualMachine) Eq() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.EQ)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	is_unsigned := this.IsUnsignedCast(loperand, roperand)
 
 	var value int64
-	if loperand_value == roperand_value {
+	if is_unsigned &&
+		this.ReadUnsigned(loperand, type_variable_type) ==
+			this.ReadUnsigned(roperand, type_variable_type) {
+		value = 1
+	} else if !is_unsigned && loperand_value == roperand_value {
 		value = 1
 	} else {
 		value = 0
@@ -2314,19 +3465,24 @@ func (this *VirtualMachine) NotEq() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.NOT_EQ)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	is_unsigned := this.IsUnsignedCast(loperand, roperand)
 
 	var value int64
-	if loperand_value != roperand_value {
+	if is_unsigned &&
+		this.ReadUnsigned(loperand, type_variable_type) !=
+			this.ReadUnsigned(roperand, type_variable_type) {
+		value = 1
+	} else if !is_unsigned && loperand_value != roperand_value {
 		value = 1
 	} else {
 		value = 0
@@ -2369,19 +3525,24 @@ func (this *VirtualMachine) Less() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.LESS)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	is_unsigned := this.IsUnsignedCast(loperand, roperand)
 
 	var value int64
-	if loperand_value < roperand_value {
+	if is_unsigned &&
+		this.ReadUnsigned(loperand, type_variable_type) <
+			this.ReadUnsigned(roperand, type_variable_type) {
+		value = 1
+	} else if !is_unsigned && loperand_value < roperand_value {
 		value = 1
 	} else {
 		value = 0
@@ -2424,19 +3585,24 @@ func (this *VirtualMachine) LessEq() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.LESS_EQ)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	is_unsigned := this.IsUnsignedCast(loperand, roperand)
 
 	var value int64
-	if loperand_value <= roperand_value {
+	if is_unsigned &&
+		this.ReadUnsigned(loperand, type_variable_type) <=
+			this.ReadUnsigned(roperand, type_variable_type) {
+		value = 1
+	} else if !is_unsigned && loperand_value <= roperand_value {
 		value = 1
 	} else {
 		value = 0
@@ -2479,19 +3645,24 @@ func (this *VirtualMachine) Greater() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.GREATER)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	is_unsigned := this.IsUnsignedCast(loperand, roperand)
 
 	var value int64
-	if loperand_value > roperand_value {
+	if is_unsigned &&
+		this.ReadUnsigned(loperand, type_variable_type) >
+			this.ReadUnsigned(roperand, type_variable_type) {
+		value = 1
+	} else if !is_unsigned && loperand_value > roperand_value {
 		value = 1
 	} else {
 		value = 0
@@ -2534,19 +3705,24 @@ func (this *VirtualMachine) GreaterEq() {
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		this.FloatingBinary(abi.GREATER_EQ)
+		return
+	}
+
 	type_variable_type := this.TypeCast(loperand, roperand)
 
-	loperand_value := this.arena.Pool().
-		Memory().
-		Read(loperand.Address(), loperand.Size()).
-		SignedValue()
-	roperand_value := this.arena.Pool().
-		Memory().
-		Read(roperand.Address(), roperand.Size()).
-		SignedValue()
+	loperand_value := this.ReadInteger(loperand)
+	roperand_value := this.ReadInteger(roperand)
+
+	is_unsigned := this.IsUnsignedCast(loperand, roperand)
 
 	var value int64
-	if loperand_value >= roperand_value {
+	if is_unsigned &&
+		this.ReadUnsigned(loperand, type_variable_type) >=
+			this.ReadUnsigned(roperand, type_variable_type) {
+		value = 1
+	} else if !is_unsigned && loperand_value >= roperand_value {
 		value = 1
 	} else {
 		value = 0
@@ -2602,21 +3778,7 @@ func (this *VirtualMachine) Assign() {
 	lvalue := this.frame_chain.LastFrame().Stack().Front(1)
 	rvalue := this.frame_chain.LastFrame().Stack().Front(0)
 
-	rvalue_byte_stream := this.arena.Pool().Memory().Read(rvalue.Address(), rvalue.Size())
-
-	if lvalue.Size() > rvalue_byte_stream.Size() {
-		for lvalue.Size() != rvalue_byte_stream.Size() {
-			if rvalue_byte_stream.Signbit() {
-				rvalue_byte_stream.Append(255)
-			} else {
-				rvalue_byte_stream.Append(0)
-			}
-		}
-	} else if lvalue.Size() < rvalue_byte_stream.Size() {
-		for lvalue.Size() != rvalue_byte_stream.Size() {
-			rvalue_byte_stream.Remove(int(rvalue_byte_stream.Size() - 1))
-		}
-	}
+	rvalue_byte_stream := this.ConvertByteStream(rvalue, lvalue)
 
 	this.arena.Pool().Memory().Write(lvalue.Address(), lvalue.Size(), rvalue_byte_stream)
 
@@ -2644,6 +3806,11 @@ func (this *VirtualMachine) AssignStar() {
 		panic(err)
 	}
 
+	if this.IsFloating(lvalue) || this.IsFloating(rvalue) {
+		this.FloatingAssign(abi.MUL)
+		return
+	}
+
 	type_variable_type := this.TypeCast(lvalue, rvalue)
 
 	lvalue_value := this.arena.Pool().Memory().Read(lvalue.Address(), lvalue.Size()).SignedValue()
@@ -2705,6 +3872,11 @@ func (this *VirtualMachine) AssignDiv() {
 		panic(err)
 	}
 
+	if this.IsFloating(lvalue) || this.IsFloating(rvalue) {
+		this.FloatingAssign(abi.DIV)
+		return
+	}
+
 	type_variable_type := this.TypeCast(lvalue, rvalue)
 
 	lvalue_value := this.arena.Pool().Memory().Read(lvalue.Address(), lvalue.Size()).SignedValue()
@@ -2827,6 +3999,11 @@ func (this *VirtualMachine) AssignAdd() {
 		panic(err)
 	}
 
+	if this.IsFloating(lvalue) || this.IsFloating(rvalue) {
+		this.FloatingAssign(abi.ADD)
+		return
+	}
+
 	type_variable_type := this.TypeCast(lvalue, rvalue)
 
 	lvalue_value := this.arena.Pool().Memory().Read(lvalue.Address(), lvalue.Size()).SignedValue()
@@ -2888,6 +4065,11 @@ func (this *VirtualMachine) AssignSub() {
 		panic(err)
 	}
 
+	if this.IsFloating(lvalue) || this.IsFloating(rvalue) {
+		this.FloatingAssign(abi.SUB)
+		return
+	}
+
 	type_variable_type := this.TypeCast(lvalue, rvalue)
 
 	lvalue_value := this.arena.Pool().Memory().Read(lvalue.Address(), lvalue.Size()).SignedValue()
@@ -3250,6 +4432,11 @@ func (this *VirtualMachine) AssignPlusPlus() {
 		panic(err)
 	}
 
+	if this.IsFloating(lvalue) {
+		this.FloatingIncrement(1)
+		return
+	}
+
 	lvalue_value := this.arena.Pool().Memory().Read(lvalue.Address(), lvalue.Size()).SignedValue()
 
 	value := lvalue_value + 1
@@ -3303,6 +4490,11 @@ func (this *VirtualMachine) AssignMinusMinus() {
 		panic(err)
 	}
 
+	if this.IsFloating(lvalue) {
+		this.FloatingIncrement(-1)
+		return
+	}
+
 	lvalue_value := this.arena.Pool().Memory().Read(lvalue.Address(), lvalue.Size()).SignedValue()
 
 	value := lvalue_value - 1
@@ -3352,21 +4544,7 @@ func (this *VirtualMachine) AssignReturn() {
 	lvalue := this.frame_chain.LastFrame().ReturnStack().Front(0)
 	rvalue := this.frame_chain.LastFrame().Stack().Front(0)
 
-	rvalue_byte_stream := this.arena.Pool().Memory().Read(rvalue.Address(), rvalue.Size())
-
-	if lvalue.Size() > rvalue_byte_stream.Size() {
-		for lvalue.Size() != rvalue_byte_stream.Size() {
-			if rvalue_byte_stream.Signbit() {
-				rvalue_byte_stream.Append(255)
-			} else {
-				rvalue_byte_stream.Append(0)
-			}
-		}
-	} else if lvalue.Size() < rvalue_byte_stream.Size() {
-		for lvalue.Size() != rvalue_byte_stream.Size() {
-			rvalue_byte_stream.Remove(int(rvalue_byte_stream.Size() - 1))
-		}
-	}
+	rvalue_byte_stream := this.ConvertByteStream(rvalue, lvalue)
 
 	this.arena.Pool().Memory().Write(lvalue.Address(), lvalue.Size(), rvalue_byte_stream)
 
@@ -3412,18 +4590,88 @@ func (this *VirtualMachine) Return() {
 func (this *VirtualMachine) Nop() {
 }
 
-func (this *VirtualMachine) DpuAlloc(dpu_id int64) {
-	if int(dpu_id) > this.num_dpus {
-		err := errors.New("DpuAlloc allocates more number of DPUs than available")
+// NOTE: the SDK allocates DPUs by whole ranks, so that the DPUs left over in the last rank of a set
+// are not available to other sets until the set is freed
+func (this *VirtualMachine) DpuAlloc() {
+	num_dpus := this.frame_chain.LastFrame().Stack().Front(2)
+	pointer := this.frame_chain.LastFrame().Stack().Front(0)
+
+	num_dpus_value := this.ReadInteger(num_dpus)
+
+	free_ranks := this.FreeRanks()
+
+	num_free_dpus := int64(len(free_ranks) * this.num_dpus_per_rank)
+	if num_dpus_value == -1 {
+		num_dpus_value = num_free_dpus
+	}
+
+	if num_dpus_value <= 0 {
+		err := errors.New("DpuAlloc allocates no DPU")
+		panic(err)
+	} else if num_dpus_value > num_free_dpus {
+		err_msg := fmt.Sprintf(
+			"DpuAlloc allocates %d DPUs while %d DPUs are available",
+			num_dpus_value,
+			num_free_dpus,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	num_ranks := (int(num_dpus_value) + this.num_dpus_per_rank - 1) / this.num_dpus_per_rank
+
+	handle := this.Allocate(free_ranks[:num_ranks], num_dpus_value)
+	this.WriteDpuSet(pointer, handle)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) DpuAllocRanks() {
+	num_ranks := this.frame_chain.LastFrame().Stack().Front(2)
+	pointer := this.frame_chain.LastFrame().Stack().Front(0)
+
+	num_ranks_value := this.ReadInteger(num_ranks)
+
+	free_ranks := this.FreeRanks()
+
+	if num_ranks_value == -1 {
+		num_ranks_value = int64(len(free_ranks))
+	}
+
+	if num_ranks_value <= 0 {
+		err := errors.New("DpuAllocRanks allocates no rank")
+		panic(err)
+	} else if num_ranks_value > int64(len(free_ranks)) {
+		err_msg := fmt.Sprintf(
+			"DpuAllocRanks allocates %d ranks while %d ranks are available",
+			num_ranks_value,
+			len(free_ranks),
+		)
+		err := errors.New(err_msg)
 		panic(err)
 	}
+
+	num_dpus := num_ranks_value * int64(this.num_dpus_per_rank)
+
+	handle := this.Allocate(free_ranks[:num_ranks_value], num_dpus)
+	this.WriteDpuSet(pointer, handle)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
 }
 
 func (this *VirtualMachine) DpuLoad() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(0))
+
+	this.SyncDpus(dpu_set.Dpus())
+
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init()
 
-	for _, dpu_ := range this.Dpus() {
+	for _, dpu_ := range dpu_set.Dpus() {
 		dpu_load_job := new(DpuLoadJob)
 		dpu_load_job.Init(this.task, dpu_)
 
@@ -3431,91 +4679,60 @@ func (this *VirtualMachine) DpuLoad() {
 	}
 
 	thread_pool.Start()
+
+	this.frame_chain.LastFrame().Stack().Pop()
 }
 
 func (this *VirtualMachine) DpuPrepare() {
-	dpu_id := this.frame_chain.LastFrame().Stack().Front(1)
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(1))
 	pointer := this.frame_chain.LastFrame().Stack().Front(0)
 
-	dpu_id_value := this.arena.Pool().Memory().Read(dpu_id.Address(), dpu_id.Size()).SignedValue()
-	pointer_value := this.arena.Pool().
-		Memory().
-		Read(pointer.Address(), pointer.Size()).
-		SignedValue()
-
-	dpu_ := this.Dpus()[dpu_id_value]
+	pointer_value := this.ReadInteger(pointer)
 
-	this.prepare_xfer_buf[dpu_] = pointer_value
+	for _, dpu_ := range dpu_set.Dpus() {
+		this.prepare_xfer_buf[dpu_] = pointer_value
+	}
 
 	this.frame_chain.LastFrame().Stack().Pop()
 	this.frame_chain.LastFrame().Stack().Pop()
 }
 
+// NOTE: the buffers prepared for the DPUs of the set are reset after the transfer unless the
+// DPU_XFER_NO_RESET flag is given
 func (this *VirtualMachine) DpuTransfer() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(5))
 	direction := this.frame_chain.LastFrame().Stack().Front(4)
-	base_ := this.frame_chain.LastFrame().Stack().Front(3)
+	symbol_ := this.frame_chain.LastFrame().Stack().Front(3)
 	offset := this.frame_chain.LastFrame().Stack().Front(2)
 	size := this.frame_chain.LastFrame().Stack().Front(1)
+	flags := this.frame_chain.LastFrame().Stack().Front(0)
 
-	direction_value := this.arena.Pool().
-		Memory().
-		Read(direction.Address(), direction.Size()).
-		SignedValue()
-	offset_value := this.arena.Pool().Memory().Read(offset.Address(), offset.Size()).SignedValue()
-	size_value := this.arena.Pool().Memory().Read(size.Address(), size.Size()).SignedValue()
+	direction_value := this.ReadInteger(direction)
+	address, is_mram := this.SymbolAddress(symbol_)
+	offset_value := this.ReadInteger(offset)
+	size_value := this.ReadInteger(size)
+	flags_value := this.ReadInteger(flags)
 
-	for _, dpu_ := range this.Dpus() {
-		if pointer_value, pointer_found := this.prepare_xfer_buf[dpu_]; pointer_found {
-			delete(this.prepare_xfer_buf, dpu_)
+	dpus := make([]*dpu.Dpu, 0)
+	for _, dpu_ := range dpu_set.Dpus() {
+		if _, pointer_found := this.prepare_xfer_buf[dpu_]; pointer_found {
+			dpus = append(dpus, dpu_)
+		}
+	}
 
-			if direction_value == 0 {
-				if base_.TypeVariable().TypeVariableType() == type_system.STRING {
-					base_string := this.DecodeString(
-						this.arena.Pool().Memory().Read(base_.Address(), base_.Size()),
-					)
-					base_string = base_string[1 : len(base_string)-1]
-
-					wram_address, wram_address_found := this.task.Addresses()[base_string]
-					if !wram_address_found {
-						err_msg := fmt.Sprintf("WRAM address (%s) is not found", base_string)
-						err := errors.New(err_msg)
-						panic(err)
-					}
-
-					byte_stream := this.PrepareByteStream(pointer_value, size_value)
-					dpu_.Dma().
-						TransferToWram(wram_address+offset_value, byte_stream.Size(), byte_stream)
-				} else {
-					base_value := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
-
-					byte_stream := this.PrepareByteStream(pointer_value, size_value)
-
-					dpu_.Dma().TransferToMram(base_value+offset_value, size_value, byte_stream)
-				}
-			} else if direction_value == 1 {
-				if base_.TypeVariable().TypeVariableType() == type_system.STRING {
-					base_string := this.DecodeString(this.arena.Pool().Memory().Read(base_.Address(), base_.Size()))
-					base_string = base_string[1 : len(base_string)-1]
-
-					wram_address, wram_address_found := this.task.Addresses()[base_string]
-					if !wram_address_found {
-						err_msg := fmt.Sprintf("WRAM address (%s) is not found", base_string)
-						err := errors.New(err_msg)
-						panic(err)
-					}
-
-					byte_stream := dpu_.Dma().TransferFromWram(wram_address+offset_value, size_value)
-					this.arena.Pool().Memory().Write(pointer_value, size_value, byte_stream)
-				} else {
-					base_value := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
-
-					byte_stream := dpu_.Dma().TransferFromMram(base_value+offset_value, size_value)
-					this.arena.Pool().Memory().Write(pointer_value, size_value, byte_stream)
-				}
-			} else {
-				err := errors.New("direction value is not 0 nor 1")
-				panic(err)
-			}
+	this.SyncTransfer(dpus)
+
+	for _, dpu_ := range dpus {
+		pointer_value := this.prepare_xfer_buf[dpu_]
+
+		this.PushTransfer(dpu_, direction_value, address+offset_value, is_mram, pointer_value, size_value)
+	}
+
+	this.Transfer()
+
+	if flags_value&1 == 0 {
+		for _, dpu_ := range dpu_set.Dpus() {
+			delete(this.prepare_xfer_buf, dpu_)
 		}
 	}
 
@@ -3527,50 +4744,31 @@ func (this *VirtualMachine) DpuTransfer() {
 	this.frame_chain.LastFrame().Stack().Pop()
 }
 
+func (this *VirtualMachine) DpuBroadcast() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(5))
+	symbol_ := this.frame_chain.LastFrame().Stack().Front(4)
+	offset := this.frame_chain.LastFrame().Stack().Front(3)
+	pointer := this.frame_chain.LastFrame().Stack().Front(2)
+	size := this.frame_chain.LastFrame().Stack().Front(1)
+
+	this.CopyTo(dpu_set, symbol_, offset, pointer, size)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
 func (this *VirtualMachine) DpuCopyTo() {
-	dpu_ := this.frame_chain.LastFrame().Stack().Front(4)
-	base_ := this.frame_chain.LastFrame().Stack().Front(3)
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(4))
+	symbol_ := this.frame_chain.LastFrame().Stack().Front(3)
 	offset := this.frame_chain.LastFrame().Stack().Front(2)
 	pointer := this.frame_chain.LastFrame().Stack().Front(1)
 	size := this.frame_chain.LastFrame().Stack().Front(0)
 
-	dpu_value := this.arena.Pool().Memory().Read(dpu_.Address(), dpu_.Size()).SignedValue()
-	offset_value := this.arena.Pool().Memory().Read(offset.Address(), offset.Size()).SignedValue()
-	pointer_value := this.arena.Pool().
-		Memory().
-		Read(pointer.Address(), pointer.Size()).
-		SignedValue()
-	size_value := this.arena.Pool().Memory().Read(size.Address(), size.Size()).SignedValue()
-
-	if base_.TypeVariable().TypeVariableType() == type_system.STRING {
-		base_string := this.DecodeString(
-			this.arena.Pool().Memory().Read(base_.Address(), base_.Size()),
-		)
-		base_string = base_string[1 : len(base_string)-1]
-
-		wram_address, wram_address_found := this.task.Addresses()[base_string]
-		if !wram_address_found {
-			err_msg := fmt.Sprintf("WRAM address (%s) is not found", base_string)
-			err := errors.New(err_msg)
-			panic(err)
-		}
-
-		byte_stream := this.PrepareByteStream(pointer_value, size_value)
-		this.Dpus()[dpu_value].Dma().
-			TransferToWram(wram_address+offset_value, byte_stream.Size(), byte_stream)
-	} else {
-		base_value := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
-
-		channel_id := int(dpu_value) / (this.num_ranks_per_channel * this.num_dpus_per_rank)
-		rank_id := (int(dpu_value) % (this.num_ranks_per_channel * this.num_dpus_per_rank)) / this.num_dpus_per_rank
-		dpu_id := int(dpu_value) % this.num_dpus_per_rank
-
-		simulated_dpu := this.channels[channel_id].Ranks()[rank_id].Dpus()[dpu_id]
-
-		byte_stream := this.PrepareByteStream(pointer_value, size_value)
-
-		simulated_dpu.Dma().TransferToMram(base_value+offset_value, size_value, byte_stream)
-	}
+	this.CopyTo(dpu_set, symbol_, offset, pointer, size)
 
 	this.frame_chain.LastFrame().Stack().Pop()
 	this.frame_chain.LastFrame().Stack().Pop()
@@ -3580,48 +4778,26 @@ func (this *VirtualMachine) DpuCopyTo() {
 }
 
 func (this *VirtualMachine) DpuCopyFrom() {
-	dpu_ := this.frame_chain.LastFrame().Stack().Front(4)
-	base_ := this.frame_chain.LastFrame().Stack().Front(3)
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(4))
+	symbol_ := this.frame_chain.LastFrame().Stack().Front(3)
 	offset := this.frame_chain.LastFrame().Stack().Front(2)
 	pointer := this.frame_chain.LastFrame().Stack().Front(1)
 	size := this.frame_chain.LastFrame().Stack().Front(0)
 
-	dpu_value := this.arena.Pool().Memory().Read(dpu_.Address(), dpu_.Size()).SignedValue()
-	offset_value := this.arena.Pool().Memory().Read(offset.Address(), offset.Size()).SignedValue()
-	pointer_value := this.arena.Pool().
-		Memory().
-		Read(pointer.Address(), pointer.Size()).
-		SignedValue()
-	size_value := this.arena.Pool().Memory().Read(size.Address(), size.Size()).SignedValue()
+	if len(dpu_set.Dpus()) != 1 {
+		err := errors.New("DpuCopyFrom copies from a DPU set of more than one DPU")
+		panic(err)
+	}
 
-	if base_.TypeVariable().TypeVariableType() == type_system.STRING {
-		base_string := this.DecodeString(
-			this.arena.Pool().Memory().Read(base_.Address(), base_.Size()),
-		)
-		base_string = base_string[1 : len(base_string)-1]
-
-		wram_address, wram_address_found := this.task.Addresses()[base_string]
-		if !wram_address_found {
-			err_msg := fmt.Sprintf("WRAM address (%s) is not found", base_string)
-			err := errors.New(err_msg)
-			panic(err)
-		}
-
-		byte_stream := this.Dpus()[dpu_value].Dma().
-			TransferFromWram(wram_address+offset_value, size_value)
-		this.arena.Pool().Memory().Write(pointer_value, size_value, byte_stream)
-	} else {
-		base_value := this.arena.Pool().Memory().Read(base_.Address(), base_.Size()).SignedValue()
+	address, is_mram := this.SymbolAddress(symbol_)
+	offset_value := this.ReadInteger(offset)
+	pointer_value := this.ReadInteger(pointer)
+	size_value := this.ReadInteger(size)
 
-		channel_id := int(dpu_value) / (this.num_ranks_per_channel * this.num_dpus_per_rank)
-		rank_id := (int(dpu_value) % (this.num_ranks_per_channel * this.num_dpus_per_rank)) / this.num_dpus_per_rank
-		dpu_id := int(dpu_value) % this.num_dpus_per_rank
+	this.SyncTransfer(dpu_set.Dpus())
 
-		simulated_dpu := this.channels[channel_id].Ranks()[rank_id].Dpus()[dpu_id]
-
-		byte_stream := simulated_dpu.Dma().TransferFromMram(base_value+offset_value, size_value)
-		this.arena.Pool().Memory().Write(pointer_value, size_value, byte_stream)
-	}
+	this.PushTransfer(dpu_set.Dpus()[0], 1, address+offset_value, is_mram, pointer_value, size_value)
+	this.Transfer()
 
 	this.frame_chain.LastFrame().Stack().Pop()
 	this.frame_chain.LastFrame().Stack().Pop()
@@ -3630,11 +4806,32 @@ func (this *VirtualMachine) DpuCopyFrom() {
 	this.frame_chain.LastFrame().Stack().Pop()
 }
 
+// NOTE: an asynchronous launch leaves the DPUs running in the background, where they advance by
+// as many logic cycles as the host core spends until they finish or the host synchronizes, while a
+// synchronous launch stalls the host core until every DPU of the set has finished
 func (this *VirtualMachine) DpuLaunch() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(1))
+	policy := this.frame_chain.LastFrame().Stack().Front(0)
+
+	policy_value := this.ReadInteger(policy)
+
+	if policy_value != 0 && policy_value != 1 {
+		err := errors.New("launch policy is neither DPU_SYNCHRONOUS nor DPU_ASYNCHRONOUS")
+		panic(err)
+	}
+
+	this.SyncDpus(dpu_set.Dpus())
+
+	// NOTE: the DPUs already running catch up with the host core, so that all of the running DPUs
+	// advance from the launch cycle on
+	if len(this.running_dpus) > 0 {
+		this.CycleRunningDpus()
+	}
+
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
-	for _, dpu_ := range this.Dpus() {
+	for _, dpu_ := range dpu_set.Dpus() {
 		threads := dpu_.Threads()
 
 		for _, thread := range threads {
@@ -3645,10 +4842,561 @@ func (this *VirtualMachine) DpuLaunch() {
 		dpu_.Boot()
 	}
 
+	this.running_dpus = append(this.running_dpus, dpu_set.Dpus()...)
+	this.launch_cycle = this.host_core.Cycles()
+	this.stepped_dpu_cycles = 0
+
+	if policy_value == 0 {
+		this.SyncDpus(dpu_set.Dpus())
+	}
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) DpuSync() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(0))
+
+	this.SyncDpus(dpu_set.Dpus())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+// NOTE: the set is freed before its ranks are released, so that freeing the set of a rank or a DPU
+// panics without releasing the ranks of the allocation it belongs to
+func (this *VirtualMachine) DpuFree() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(0))
+
+	dpu_set.Free()
+
+	this.SyncDpus(dpu_set.Dpus())
+
+	for _, dpu_ := range dpu_set.Dpus() {
+		delete(this.prepare_xfer_buf, dpu_)
+
+		this.allocated_ranks[this.RankIndex(dpu_)] = false
+	}
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) DpuNrDpus() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(0))
+
+	this.frame_chain.LastFrame().Stack().Pop()
+
+	this.PushInt(int64(len(dpu_set.Dpus())))
+}
+
+func (this *VirtualMachine) DpuNrRanks() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(0))
+
+	this.frame_chain.LastFrame().Stack().Pop()
+
+	this.PushInt(int64(len(dpu_set.RankHandles())))
+}
+
+func (this *VirtualMachine) DpuGetDpu() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(1))
+	index := this.frame_chain.LastFrame().Stack().Front(0)
+
+	index_value := this.ReadInteger(index)
+
+	if index_value < 0 || index_value >= int64(len(dpu_set.DpuHandles())) {
+		err := errors.New("DPU index is out of the DPU set")
+		panic(err)
+	}
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+
+	this.PushInt(dpu_set.DpuHandles()[index_value])
+}
+
+func (this *VirtualMachine) DpuGetRank() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(1))
+	index := this.frame_chain.LastFrame().Stack().Front(0)
+
+	index_value := this.ReadInteger(index)
+
+	if index_value < 0 || index_value >= int64(len(dpu_set.RankHandles())) {
+		err := errors.New("rank index is out of the DPU set")
+		panic(err)
+	}
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+
+	this.PushInt(dpu_set.RankHandles()[index_value])
+}
+
+// NOTE: a callback is called once for the whole set with DPU_CALLBACK_SINGLE_CALL, and once for
+// every rank of the set otherwise
+func (this *VirtualMachine) DpuNrCallbacks() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(1))
+	flags := this.frame_chain.LastFrame().Stack().Front(0)
+
+	flags_value := this.ReadInteger(flags)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+
+	if flags_value&4 != 0 {
+		this.PushInt(1)
+	} else {
+		this.PushInt(int64(len(dpu_set.RankHandles())))
+	}
+}
+
+func (this *VirtualMachine) DpuGetCallback() {
+	handle := this.ReadHandle(this.frame_chain.LastFrame().Stack().Front(2))
+	flags := this.frame_chain.LastFrame().Stack().Front(1)
+	index := this.frame_chain.LastFrame().Stack().Front(0)
+
+	dpu_set := this.DpuSet(handle)
+	flags_value := this.ReadInteger(flags)
+	index_value := this.ReadInteger(index)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+
+	if flags_value&4 != 0 {
+		this.PushInt(handle)
+	} else if index_value >= 0 && index_value < int64(len(dpu_set.RankHandles())) {
+		this.PushInt(dpu_set.RankHandles()[index_value])
+	} else {
+		err := errors.New("callback index is out of the DPU set")
+		panic(err)
+	}
+}
+
+// NOTE: the stdout buffer of the DPU and its state are transferred to the host like any other
+// symbol before they are decoded
+func (this *VirtualMachine) DpuLogRead() {
+	dpu_set := this.ReadDpuSet(this.frame_chain.LastFrame().Stack().Front(0))
+
+	if len(dpu_set.Dpus()) != 1 {
+		err := errors.New("DpuLogRead reads the log of a DPU set of more than one DPU")
+		panic(err)
+	}
+
+	dpu_ := dpu_set.Dpus()[0]
+
+	this.SyncTransfer(dpu_set.Dpus())
+
+	state_address := this.TaskAddress("__stdout_buffer_state")
+	buffer_size_address := this.TaskAddress("__stdout_buffer_size")
+	buffer_address := this.TaskAddress("__stdout_buffer")
+
+	state := this.arena.Pool().Alloc(base.TEMPORARY, 12)
+
+	this.PushTransfer(dpu_, 1, state_address, false, state.Address(), 8)
+	this.PushTransfer(dpu_, 1, buffer_size_address, false, state.Address()+8, 4)
+	this.Transfer()
+
+	state_byte_stream := this.arena.Pool().Memory().Read(state.Address(), 12)
+	this.arena.Free(state.Address())
+
+	write_pointer := int64(0)
+	has_wrapped := int64(0)
+	buffer_size := int64(0)
+	for i := 0; i < 4; i++ {
+		write_pointer |= int64(state_byte_stream.Get(i)) << (8 * i)
+		has_wrapped |= int64(state_byte_stream.Get(4+i)) << (8 * i)
+		buffer_size |= int64(state_byte_stream.Get(8+i)) << (8 * i)
+	}
+
+	size := write_pointer
+	if has_wrapped != 0 {
+		size = buffer_size
+	}
+
+	if size > 0 {
+		buffer := this.arena.Pool().Alloc(base.TEMPORARY, size)
+
+		this.PushTransfer(dpu_, 1, buffer_address, true, buffer.Address(), size)
+		this.Transfer()
+
+		buffer_byte_stream := this.arena.Pool().Memory().Read(buffer.Address(), size)
+		this.arena.Free(buffer.Address())
+
+		// NOTE: the oldest prints of a wrapped buffer start at the write pointer
+		byte_stream := new(encoding.ByteStream)
+		byte_stream.Init()
+		for i := int64(0); i < size; i++ {
+			byte_stream.Append(buffer_byte_stream.Get(int((write_pointer + i) % size)))
+		}
+
+		dpu_log := new(DpuLog)
+		dpu_log.Init(byte_stream)
+
+		fmt.Print(dpu_log.Decode())
+	}
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) FreeRanks() [][]*dpu.Dpu {
+	free_ranks := make([][]*dpu.Dpu, 0)
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			if !this.allocated_ranks[this.RankIndex(rank_.Dpus()[0])] {
+				free_ranks = append(free_ranks, rank_.Dpus())
+			}
+		}
+	}
+	return free_ranks
+}
+
+func (this *VirtualMachine) RankIndex(dpu_ *dpu.Dpu) int {
+	return dpu_.ChannelId()*this.num_ranks_per_channel + dpu_.RankId()
+}
+
+// NOTE: the ranks and the DPUs of an allocated set are registered along with the set, so that
+// iterating over them hands out the same handles every time
+func (this *VirtualMachine) Allocate(ranks [][]*dpu.Dpu, num_dpus int64) int64 {
+	dpus := make([]*dpu.Dpu, 0)
+	for _, rank_dpus := range ranks {
+		this.allocated_ranks[this.RankIndex(rank_dpus[0])] = true
+
+		dpus = append(dpus, rank_dpus...)
+	}
+	dpus = dpus[:num_dpus]
+
+	dpu_set := new(DpuSet)
+	dpu_set.Init(ALLOCATED, dpus, nil)
+
+	handle := this.RegisterDpuSet(dpu_set)
+
+	dpu_handles := make(map[*dpu.Dpu]int64)
+	for _, dpu_ := range dpus {
+		single_dpu_set := new(DpuSet)
+		single_dpu_set.Init(SINGLE, []*dpu.Dpu{dpu_}, dpu_set)
+
+		dpu_handle := this.RegisterDpuSet(single_dpu_set)
+		single_dpu_set.AppendDpuHandle(dpu_handle)
+		single_dpu_set.AppendRankHandle(dpu_handle)
+
+		dpu_set.AppendDpuHandle(dpu_handle)
+		dpu_handles[dpu_] = dpu_handle
+	}
+
+	for _, rank_dpus := range dpu_set.Ranks() {
+		rank_dpu_set := new(DpuSet)
+		rank_dpu_set.Init(RANK, rank_dpus, dpu_set)
+
+		rank_handle := this.RegisterDpuSet(rank_dpu_set)
+		for _, dpu_ := range rank_dpus {
+			rank_dpu_set.AppendDpuHandle(dpu_handles[dpu_])
+		}
+		rank_dpu_set.AppendRankHandle(rank_handle)
+
+		dpu_set.AppendRankHandle(rank_handle)
+	}
+
+	return handle
+}
+
+func (this *VirtualMachine) RegisterDpuSet(dpu_set *DpuSet) int64 {
+	this.dpu_sets = append(this.dpu_sets, dpu_set)
+	return int64(len(this.dpu_sets))
+}
+
+func (this *VirtualMachine) DpuSet(handle int64) *DpuSet {
+	if handle <= 0 || handle > int64(len(this.dpu_sets)) {
+		err_msg := fmt.Sprintf("DPU set (%d) is not allocated", handle)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	dpu_set := this.dpu_sets[handle-1]
+
+	if dpu_set.IsFreed() {
+		err_msg := fmt.Sprintf("DPU set (%d) is already freed", handle)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return dpu_set
+}
+
+// NOTE: the handle of a DPU set is the only field of struct dpu_set_t
+func (this *VirtualMachine) ReadHandle(stack_item *stack.StackItem) int64 {
+	if stack_item.TypeVariable().TypeVariableType() != type_system.STRUCT ||
+		stack_item.TypeVariable().StructName() != "dpu_set_t" ||
+		stack_item.TypeVariable().NumStars() != 0 {
+		err := errors.New("DPU set is not a struct dpu_set_t")
+		panic(err)
+	}
+
+	return this.arena.Pool().Memory().Read(stack_item.Address(), 4).SignedValue()
+}
+
+func (this *VirtualMachine) ReadDpuSet(stack_item *stack.StackItem) *DpuSet {
+	return this.DpuSet(this.ReadHandle(stack_item))
+}
+
+func (this *VirtualMachine) WriteDpuSet(pointer *stack.StackItem, handle int64) {
+	if pointer.TypeVariable().TypeVariableType() != type_system.STRUCT ||
+		pointer.TypeVariable().StructName() != "dpu_set_t" ||
+		pointer.TypeVariable().NumStars() != 1 {
+		err := errors.New("DPU set is not allocated into a struct dpu_set_t pointer")
+		panic(err)
+	}
+
+	this.arena.Pool().Memory().Write(this.ReadInteger(pointer), 4, this.EncodeValue(handle, 4))
+}
+
+func (this *VirtualMachine) TaskAddress(symbol_name string) int64 {
+	address, address_found := this.task.Addresses()[symbol_name]
+	if !address_found {
+		err_msg := fmt.Sprintf("DPU symbol (%s) is not found", symbol_name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return address
+}
+
+// NOTE: a symbol is either given by its name, whose address tells whether it lives in the WRAM or
+// the MRAM, or by an MRAM address such as DPU_MRAM_HEAP_POINTER_NAME
+func (this *VirtualMachine) SymbolAddress(symbol_ *stack.StackItem) (int64, bool) {
+	if symbol_.TypeVariable().TypeVariableType() == type_system.STRING {
+		symbol_name := this.DecodeString(
+			this.arena.Pool().Memory().Read(symbol_.Address(), symbol_.Size()),
+		)
+		symbol_name = symbol_name[1 : len(symbol_name)-1]
+
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		address := this.TaskAddress(symbol_name)
+
+		return address, address >= config_loader.MramOffset()
+	} else {
+		return this.ReadInteger(symbol_), true
+	}
+}
+
+func (this *VirtualMachine) CopyTo(
+	dpu_set *DpuSet,
+	symbol_ *stack.StackItem,
+	offset *stack.StackItem,
+	pointer *stack.StackItem,
+	size *stack.StackItem,
+) {
+	address, is_mram := this.SymbolAddress(symbol_)
+	offset_value := this.ReadInteger(offset)
+	pointer_value := this.ReadInteger(pointer)
+	size_value := this.ReadInteger(size)
+
+	this.SyncTransfer(dpu_set.Dpus())
+
+	for _, dpu_ := range dpu_set.Dpus() {
+		this.PushTransfer(dpu_, 0, address+offset_value, is_mram, pointer_value, size_value)
+	}
+
+	this.Transfer()
+}
+
+// NOTE: a transfer to or from the MRAM moves the host buffer between the VM DRAM and the DPU over
+// its channel, while a transfer to or from the WRAM is performed functionally and only its access
+// to the host buffer on the VM DRAM is timed
+func (this *VirtualMachine) PushTransfer(
+	dpu_ *dpu.Dpu,
+	direction int64,
+	address int64,
+	is_mram bool,
+	pointer int64,
+	size int64,
+) {
+	if size < 0 {
+		err := errors.New("transfer size < 0")
+		panic(err)
+	} else if size == 0 {
+		return
+	}
+
+	// NOTE: the host buffer is accessed by the transfer rather than by the host core
+	this.host_core.Checkin()
+
+	transfer_command := new(bank.TransferCommand)
+
+	if direction == 0 {
+		byte_stream := this.PrepareByteStream(pointer, size)
+
+		if is_mram {
+			this.memory_controller.VmWrite(pointer, size, byte_stream)
+
+			transfer_command.Init(
+				bank.HOST_TO_DEVICE,
+				pointer,
+				dpu_.ChannelId(),
+				dpu_.RankId(),
+				dpu_.DpuId(),
+				address,
+				size,
+			)
+		} else {
+			dpu_.Dma().TransferToWram(address, size, byte_stream)
+
+			transfer_command.InitHost(bank.HOST_READ, pointer, size)
+		}
+	} else if direction == 1 {
+		if is_mram {
+			transfer_command.Init(
+				bank.DEVICE_TO_HOST,
+				pointer,
+				dpu_.ChannelId(),
+				dpu_.RankId(),
+				dpu_.DpuId(),
+				address,
+				size,
+			)
+		} else {
+			byte_stream := dpu_.Dma().TransferFromWram(address, size)
+			this.arena.Pool().Memory().Write(pointer, size, byte_stream)
+
+			transfer_command.InitHost(bank.HOST_WRITE, pointer, size)
+			transfer_command.SetByteStream(pointer, size, byte_stream)
+		}
+	} else {
+		err := errors.New("direction value is not 0 nor 1")
+		panic(err)
+	}
+
+	this.push_xfer[transfer_command] = true
+	this.memory_controller.Push(transfer_command)
+
+	this.host_core.Checkout()
+}
+
+// NOTE: the host core waits until every pushed transfer has completed
+func (this *VirtualMachine) Transfer() {
+	if len(this.push_xfer) == 0 {
+		return
+	}
+
+	memory_cycles := this.SimulateMemory()
+
+	this.host_core.Transfer(memory_cycles)
+}
+
+// NOTE: the SDK queues an operation on a rank behind its running launch, so a transfer to running
+// DPUs waits for them to finish before it is performed
+func (this *VirtualMachine) SyncTransfer(dpus []*dpu.Dpu) {
+	for _, dpu_ := range dpus {
+		if this.IsRunning(dpu_) {
+			this.stat_factory.Increment("serialized_transfers", 1)
+			break
+		}
+	}
+
+	this.SyncDpus(dpus)
+}
+
+// NOTE: the running DPUs catch up with the host core, which may have spent less than a logic cycle
+// since they last advanced
+func (this *VirtualMachine) CycleRunningDpus() {
+	host_cycles := this.host_core.Cycles() - this.launch_cycle
+	target_dpu_cycles := host_cycles * this.logic_frequency / this.host_frequency
+
+	num_cycles := target_dpu_cycles - this.stepped_dpu_cycles
+	if num_cycles == 0 {
+		return
+	}
+
+	this.stepped_dpu_cycles = target_dpu_cycles
+
+	cycles := make(map[*dpu.Dpu]int64)
+
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init()
 
-	for _, dpu_ := range this.Dpus() {
+	for _, dpu_ := range this.running_dpus {
+		cycles[dpu_] = dpu_.Cycles()
+
+		dpu_step_cycle_job := new(DpuStepCycleJob)
+		dpu_step_cycle_job.Init(this.task.SysEnd(), num_cycles, dpu_)
+
+		thread_pool.Enque(dpu_step_cycle_job)
+	}
+
+	thread_pool.Start()
+
+	num_overlapped_cycles := int64(0)
+	for _, dpu_ := range this.running_dpus {
+		if dpu_.Cycles()-cycles[dpu_] > num_overlapped_cycles {
+			num_overlapped_cycles = dpu_.Cycles() - cycles[dpu_]
+		}
+	}
+
+	this.dpu_cycles += num_overlapped_cycles
+
+	this.stat_factory.Increment("overlapped_dpu_cycles", num_overlapped_cycles)
+
+	running_dpus := make([]*dpu.Dpu, 0)
+	for _, dpu_ := range this.running_dpus {
+		if dpu_.IsZombie() {
+			dpu_.Unboot()
+		} else {
+			running_dpus = append(running_dpus, dpu_)
+		}
+	}
+	this.running_dpus = running_dpus
+}
+
+func (this *VirtualMachine) IsRunning(dpu_ *dpu.Dpu) bool {
+	for _, running_dpu := range this.running_dpus {
+		if running_dpu == dpu_ {
+			return true
+		}
+	}
+	return false
+}
+
+// NOTE: the host core stalls until the running DPUs among the given ones finish, while the other
+// running DPUs advance for as long
+func (this *VirtualMachine) SyncDpus(dpus []*dpu.Dpu) {
+	waited_dpus := make([]*dpu.Dpu, 0)
+	running_dpus := make([]*dpu.Dpu, 0)
+	for _, running_dpu := range this.running_dpus {
+		is_waited := false
+		for _, dpu_ := range dpus {
+			if dpu_ == running_dpu {
+				is_waited = true
+				break
+			}
+		}
+
+		if is_waited {
+			waited_dpus = append(waited_dpus, running_dpu)
+		} else {
+			running_dpus = append(running_dpus, running_dpu)
+		}
+	}
+
+	if len(waited_dpus) == 0 {
+		return
+	} else if len(running_dpus) == 0 {
+		this.Sync()
+		return
+	}
+
+	this.stat_factory.Increment("partial_syncs", 1)
+
+	this.CycleRunningDpus()
+
+	cycles := make(map[*dpu.Dpu]int64)
+	for _, dpu_ := range waited_dpus {
+		cycles[dpu_] = dpu_.Cycles()
+	}
+
+	thread_pool := new(core.ThreadPool)
+	thread_pool.Init()
+
+	for _, dpu_ := range waited_dpus {
 		dpu_cycle_job := new(DpuComputeCycleJob)
 		dpu_cycle_job.Init(this.task.SysEnd(), dpu_)
 
@@ -3657,15 +5405,74 @@ func (this *VirtualMachine) DpuLaunch() {
 
 	thread_pool.Start()
 
-	for _, dpu_ := range this.Dpus() {
+	num_cycles := int64(0)
+	for _, dpu_ := range waited_dpus {
 		dpu_.Unboot()
+
+		if dpu_.Cycles()-cycles[dpu_] > num_cycles {
+			num_cycles = dpu_.Cycles() - cycles[dpu_]
+		}
 	}
 
-	this.frame_chain.LastFrame().Stack().Pop()
-	this.frame_chain.LastFrame().Stack().Pop()
+	this.running_dpus = running_dpus
+
+	// NOTE: the other running DPUs advance while the host core stalls
+	this.host_core.Stall(this.HostCycles(num_cycles))
+	this.CycleRunningDpus()
 }
 
-func (this *VirtualMachine) DpuFree() {
+// NOTE: the host core stalls for as long as the slowest running DPU takes to finish
+func (this *VirtualMachine) Sync() {
+	if len(this.running_dpus) == 0 {
+		return
+	}
+
+	this.stat_factory.Increment("syncs", 1)
+
+	this.CycleRunningDpus()
+
+	cycles := make(map[*dpu.Dpu]int64)
+	for _, dpu_ := range this.running_dpus {
+		cycles[dpu_] = dpu_.Cycles()
+	}
+
+	thread_pool := new(core.ThreadPool)
+	thread_pool.Init()
+
+	for _, dpu_ := range this.running_dpus {
+		dpu_cycle_job := new(DpuComputeCycleJob)
+		dpu_cycle_job.Init(this.task.SysEnd(), dpu_)
+
+		thread_pool.Enque(dpu_cycle_job)
+	}
+
+	thread_pool.Start()
+
+	num_cycles := int64(0)
+	for _, dpu_ := range this.running_dpus {
+		dpu_.Unboot()
+
+		if dpu_.Cycles()-cycles[dpu_] > num_cycles {
+			num_cycles = dpu_.Cycles() - cycles[dpu_]
+		}
+	}
+
+	this.running_dpus = make([]*dpu.Dpu, 0)
+
+	this.host_core.Stall(this.HostCycles(num_cycles))
+	this.dpu_cycles += num_cycles
+}
+
+func (this *VirtualMachine) HostCycles(dpu_cycles int64) int64 {
+	return (dpu_cycles*this.host_frequency + this.logic_frequency - 1) / this.logic_frequency
+}
+
+func (this *VirtualMachine) GarbageCollector() *arena.GarbageCollector {
+	return this.garbage_collector
+}
+
+func (this *VirtualMachine) StatFactory() *misc.StatFactory {
+	return this.stat_factory
 }
 
 func (this *VirtualMachine) Banks() []*bank.Bank {
@@ -3692,6 +5499,10 @@ func (this *VirtualMachine) TypeCast(
 		panic(err)
 	}
 
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		return this.FloatingTypeCast(loperand, roperand)
+	}
+
 	if loperand.TypeVariable().TypeVariableType() == type_system.VOID {
 		err := errors.New("loperand's type variable type == void")
 		panic(err)
@@ -3780,6 +5591,311 @@ func (this *VirtualMachine) TypeCast(
 	}
 }
 
+func (this *VirtualMachine) FloatingTypeCast(
+	loperand *stack.StackItem,
+	roperand *stack.StackItem,
+) type_system.TypeVariableType {
+	if loperand.TypeVariable().TypeVariableType() == type_system.DOUBLE ||
+		roperand.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+		return type_system.DOUBLE
+	} else {
+		return type_system.FLOAT
+	}
+}
+
+// NOTE: char and short operands are promoted to a signed int, so that only unsigned int and
+// unsigned long operands make the usual arithmetic conversions unsigned
+func (this *VirtualMachine) IsUnsignedCast(
+	loperand *stack.StackItem,
+	roperand *stack.StackItem,
+) bool {
+	if this.IsFloating(loperand) || this.IsFloating(roperand) {
+		return false
+	}
+
+	lrank := this.IntegerRank(loperand)
+	rrank := this.IntegerRank(roperand)
+
+	if lrank > rrank {
+		return loperand.TypeVariable().IsUnsigned()
+	} else if lrank < rrank {
+		return roperand.TypeVariable().IsUnsigned()
+	} else {
+		return loperand.TypeVariable().IsUnsigned() || roperand.TypeVariable().IsUnsigned()
+	}
+}
+
+func (this *VirtualMachine) IntegerRank(stack_item *stack.StackItem) int {
+	if stack_item.TypeVariable().TypeVariableType() == type_system.LONG {
+		return 2
+	} else if stack_item.TypeVariable().TypeVariableType() == type_system.INT {
+		return 1
+	} else {
+		return 0
+	}
+}
+
+func (this *VirtualMachine) IsFloating(stack_item *stack.StackItem) bool {
+	if stack_item.TypeVariable().NumStars() != 0 {
+		return false
+	}
+
+	return stack_item.TypeVariable().TypeVariableType() == type_system.FLOAT ||
+		stack_item.TypeVariable().TypeVariableType() == type_system.DOUBLE
+}
+
+func (this *VirtualMachine) ReadInteger(stack_item *stack.StackItem) int64 {
+	byte_stream := this.arena.Pool().Memory().Read(stack_item.Address(), stack_item.Size())
+
+	if this.IsFloating(stack_item) {
+		return int64(this.ReadFloating(stack_item))
+	} else if stack_item.TypeVariable().NumStars() == 0 && stack_item.TypeVariable().IsUnsigned() {
+		return byte_stream.UnsignedValue()
+	} else {
+		return byte_stream.SignedValue()
+	}
+}
+
+// NOTE: unsigned operations wrap around at the width of the promoted type
+func (this *VirtualMachine) ReadUnsigned(
+	stack_item *stack.StackItem,
+	type_variable_type type_system.TypeVariableType,
+) uint64 {
+	value := uint64(this.ReadInteger(stack_item))
+
+	if type_variable_type == type_system.LONG {
+		return value
+	} else {
+		return value & 0xFFFFFFFF
+	}
+}
+
+func (this *VirtualMachine) ReadFloating(stack_item *stack.StackItem) float64 {
+	byte_stream := this.arena.Pool().Memory().Read(stack_item.Address(), stack_item.Size())
+
+	if stack_item.TypeVariable().NumStars() != 0 {
+		err := errors.New("pointer cannot be read as a floating point value")
+		panic(err)
+	} else if stack_item.TypeVariable().TypeVariableType() == type_system.FLOAT {
+		return float64(math.Float32frombits(uint32(byte_stream.UnsignedValue())))
+	} else if stack_item.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+		return math.Float64frombits(uint64(byte_stream.UnsignedValue()))
+	} else if stack_item.TypeVariable().IsUnsigned() {
+		return float64(uint64(byte_stream.UnsignedValue()))
+	} else {
+		return float64(byte_stream.SignedValue())
+	}
+}
+
+func (this *VirtualMachine) NewFloating(
+	type_variable_type type_system.TypeVariableType,
+	value float64,
+) *base.Object {
+	if type_variable_type == type_system.FLOAT {
+		return this.arena.NewFloat(value)
+	} else if type_variable_type == type_system.DOUBLE {
+		return this.arena.NewDouble(value)
+	} else {
+		err := errors.New("type variable type is not a floating point type")
+		panic(err)
+	}
+}
+
+func (this *VirtualMachine) FloatingBinary(op_code abi.OpCode) {
+	loperand := this.frame_chain.LastFrame().Stack().Front(1)
+	roperand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	type_variable_type := this.FloatingTypeCast(loperand, roperand)
+
+	loperand_value := this.ReadFloating(loperand)
+	roperand_value := this.ReadFloating(roperand)
+
+	var condition bool
+	var object *base.Object
+	if op_code == abi.ADD {
+		object = this.NewFloating(type_variable_type, loperand_value+roperand_value)
+	} else if op_code == abi.SUB {
+		object = this.NewFloating(type_variable_type, loperand_value-roperand_value)
+	} else if op_code == abi.MUL {
+		object = this.NewFloating(type_variable_type, loperand_value*roperand_value)
+	} else if op_code == abi.DIV {
+		object = this.NewFloating(type_variable_type, loperand_value/roperand_value)
+	} else if op_code == abi.EQ {
+		condition = loperand_value == roperand_value
+	} else if op_code == abi.NOT_EQ {
+		condition = loperand_value != roperand_value
+	} else if op_code == abi.LESS {
+		condition = loperand_value < roperand_value
+	} else if op_code == abi.LESS_EQ {
+		condition = loperand_value <= roperand_value
+	} else if op_code == abi.GREATER {
+		condition = loperand_value > roperand_value
+	} else if op_code == abi.GREATER_EQ {
+		condition = loperand_value >= roperand_value
+	} else {
+		err := errors.New("op code is not a floating point operation")
+		panic(err)
+	}
+
+	// NOTE: comparisons of floating point operands yield an int as in C
+	if object == nil {
+		type_variable_type = type_system.INT
+
+		if condition {
+			object = this.arena.NewInt(1)
+		} else {
+			object = this.arena.NewInt(0)
+		}
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_variable_type, 0)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) FloatingUnary(op_code abi.OpCode) {
+	operand := this.frame_chain.LastFrame().Stack().Front(0)
+
+	type_variable_type := operand.TypeVariable().TypeVariableType()
+
+	operand_value := this.ReadFloating(operand)
+
+	var object *base.Object
+	if op_code == abi.NEGATE {
+		object = this.NewFloating(type_variable_type, -operand_value)
+	} else if op_code == abi.SQRT {
+		object = this.NewFloating(type_variable_type, math.Sqrt(operand_value))
+	} else {
+		err := errors.New("op code is not a floating point operation")
+		panic(err)
+	}
+
+	type_variable := new(type_system.TypeVariable)
+	type_variable.InitPrimitive(type_variable_type, 0)
+
+	stack_item := new(stack.StackItem)
+	stack_item.Init(type_variable, object.Address(), object.Size())
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Push(stack_item)
+}
+
+func (this *VirtualMachine) FloatingAssign(op_code abi.OpCode) {
+	lvalue := this.frame_chain.LastFrame().Stack().Front(1)
+	rvalue := this.frame_chain.LastFrame().Stack().Front(0)
+
+	lvalue_value := this.ReadFloating(lvalue)
+	rvalue_value := this.ReadFloating(rvalue)
+
+	var value float64
+	if op_code == abi.ADD {
+		value = lvalue_value + rvalue_value
+	} else if op_code == abi.SUB {
+		value = lvalue_value - rvalue_value
+	} else if op_code == abi.MUL {
+		value = lvalue_value * rvalue_value
+	} else if op_code == abi.DIV {
+		value = lvalue_value / rvalue_value
+	} else {
+		err := errors.New("op code is not a floating point operation")
+		panic(err)
+	}
+
+	this.WriteFloating(lvalue, value)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) FloatingIncrement(delta float64) {
+	lvalue := this.frame_chain.LastFrame().Stack().Front(0)
+
+	this.WriteFloating(lvalue, this.ReadFloating(lvalue)+delta)
+
+	this.frame_chain.LastFrame().Stack().Pop()
+}
+
+func (this *VirtualMachine) WriteFloating(lvalue *stack.StackItem, value float64) {
+	var bits int64
+	if lvalue.TypeVariable().TypeVariableType() == type_system.FLOAT {
+		bits = int64(math.Float32bits(float32(value)))
+	} else if lvalue.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+		bits = int64(math.Float64bits(value))
+	} else {
+		bits = int64(value)
+	}
+
+	byte_stream := this.EncodeValue(bits, lvalue.Size())
+
+	this.arena.Pool().Memory().Write(lvalue.Address(), lvalue.Size(), byte_stream)
+}
+
+func (this *VirtualMachine) EncodeValue(value int64, size int64) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for i := int64(0); i < size; i++ {
+		byte_stream.Append(uint8((value >> (8 * i)) & 0xFF))
+	}
+
+	return byte_stream
+}
+
+// NOTE: integers are sign or zero extended to the size of the lvalue, while floating point values
+// are converted to or from the type of the lvalue
+func (this *VirtualMachine) ConvertByteStream(
+	rvalue *stack.StackItem,
+	lvalue *stack.StackItem,
+) *encoding.ByteStream {
+	if this.IsFloating(lvalue) || this.IsFloating(rvalue) {
+		if lvalue.TypeVariable().TypeVariableType() == type_system.STRUCT ||
+			rvalue.TypeVariable().TypeVariableType() == type_system.STRUCT {
+			err := errors.New("struct cannot be converted into a floating point value")
+			panic(err)
+		}
+
+		var bits int64
+		if lvalue.TypeVariable().NumStars() != 0 {
+			err := errors.New("floating point value cannot be assigned to a pointer")
+			panic(err)
+		} else if lvalue.TypeVariable().TypeVariableType() == type_system.FLOAT {
+			bits = int64(math.Float32bits(float32(this.ReadFloating(rvalue))))
+		} else if lvalue.TypeVariable().TypeVariableType() == type_system.DOUBLE {
+			bits = int64(math.Float64bits(this.ReadFloating(rvalue)))
+		} else {
+			bits = this.ReadInteger(rvalue)
+		}
+
+		return this.EncodeValue(bits, lvalue.Size())
+	}
+
+	rvalue_byte_stream := this.arena.Pool().Memory().Read(rvalue.Address(), rvalue.Size())
+
+	is_unsigned := rvalue.TypeVariable().NumStars() == 0 && rvalue.TypeVariable().IsUnsigned()
+
+	if lvalue.Size() > rvalue_byte_stream.Size() {
+		for lvalue.Size() != rvalue_byte_stream.Size() {
+			if rvalue_byte_stream.Signbit() && !is_unsigned {
+				rvalue_byte_stream.Append(255)
+			} else {
+				rvalue_byte_stream.Append(0)
+			}
+		}
+	} else if lvalue.Size() < rvalue_byte_stream.Size() {
+		for lvalue.Size() != rvalue_byte_stream.Size() {
+			rvalue_byte_stream.Remove(int(rvalue_byte_stream.Size() - 1))
+		}
+	}
+
+	return rvalue_byte_stream
+}
+
 func (this *VirtualMachine) DecodeString(byte_stream *encoding.ByteStream) string {
 	ascii_encoder := new(encoding.AsciiEncoder)
 	ascii_encoder.Init()
@@ -3816,7 +5932,28 @@ func (this *VirtualMachine) PrepareByteStream(
 	return byte_stream
 }
 
-func (this *VirtualMachine) SimulateMemory() {
+// NOTE: the pushed transfers are simulated until every one of them completes, where only the DPUs
+// they involve are cycled, and the number of VM DRAM cycles they take is returned
+func (this *VirtualMachine) SimulateMemory() int64 {
+	is_involved := make(map[*dpu.Dpu]bool)
+	for transfer_command := range this.push_xfer {
+		if transfer_command.TransferCommandType() == bank.HOST_TO_DEVICE ||
+			transfer_command.TransferCommandType() == bank.DEVICE_TO_HOST {
+			channel_ := this.channels[transfer_command.ChannelId()]
+			rank_ := channel_.Ranks()[transfer_command.RankId()]
+
+			is_involved[rank_.Dpus()[transfer_command.DpuId()]] = true
+		}
+	}
+
+	dpus := make([]*dpu.Dpu, 0)
+	for _, dpu_ := range this.Dpus() {
+		if is_involved[dpu_] {
+			dpus = append(dpus, dpu_)
+		}
+	}
+
+	num_cycles := int64(0)
 	for len(this.push_xfer) > 0 {
 		thread_pool := new(core.ThreadPool)
 		thread_pool.Init()
@@ -3828,7 +5965,7 @@ func (this *VirtualMachine) SimulateMemory() {
 			thread_pool.Enque(bank_cycle_job)
 		}
 
-		for _, dpu_ := range this.Dpus() {
+		for _, dpu_ := range dpus {
 			dpu_cycle_job := new(DpuCycleJob)
 			dpu_cycle_job.Init(dpu_)
 
@@ -3841,10 +5978,6 @@ func (this *VirtualMachine) SimulateMemory() {
 			vm_channel_.Cycle()
 		}
 
-		for _, channel_ := range this.channels {
-			channel_.Cycle()
-		}
-
 		this.memory_controller.Cycle()
 
 		if this.memory_controller.CanPop() {
@@ -3861,14 +5994,22 @@ func (this *VirtualMachine) SimulateMemory() {
 			delete(this.push_xfer, transfer_command)
 
 			if transfer_command.TransferCommandType() == bank.DEVICE_TO_HOST {
+				this.host_core.Checkin()
+
 				this.arena.Pool().Memory().Write(
 					transfer_command.VmAddress(),
 					transfer_command.Size(),
 					transfer_command.ByteStream(),
 				)
+
+				this.host_core.Checkout()
 			}
 		}
+
+		num_cycles++
 	}
+
+	return num_cycles
 }
 
 func (this *VirtualMachine) Dump() {
@@ -3877,6 +6018,23 @@ func (this *VirtualMachine) Dump() {
 
 	lines := make([]string, 0)
 
+	this.stat_factory.Increment("host_bytecodes", this.num_bytecodes)
+	this.stat_factory.Increment("host_cycles", this.host_core.Cycles())
+	this.stat_factory.Increment("host_time_ns", this.host_core.TimeNs())
+	this.stat_factory.Increment("dpu_cycles", this.dpu_cycles)
+	this.stat_factory.Increment("dpu_time_ns", this.dpu_cycles*1000/this.logic_frequency)
+
+	lines = append(lines, this.stat_factory.ToLines()...)
+	lines = append(lines, this.arena.Pool().StatFactory().ToLines()...)
+
+	this.garbage_collector.UpdateStats()
+	lines = append(lines, this.garbage_collector.StatFactory().ToLines()...)
+
+	lines = append(lines, this.host_core.StatFactory().ToLines()...)
+	for _, cache := range this.host_core.Caches() {
+		lines = append(lines, cache.StatFactory().ToLines()...)
+	}
+
 	for _, dpu_ := range this.Dpus() {
 		lines = append(lines, dpu_.StatFactory().ToLines()...)
 		lines = append(lines, dpu_.ThreadScheduler().StatFactory().ToLines()...)
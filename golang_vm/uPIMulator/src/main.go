@@ -130,6 +130,27 @@ func InitCommandLineParser() *misc.CommandLineParser {
 	command_line_parser.AddOption(misc.STRING, "bin_dirpath",
 		"/home/via/uPIMulator/golang_vm/uPIMulator/bin", "path to the bin directory")
 
+	command_line_parser.AddOption(misc.INT, "host_frequency", "3200", "host core frequency in MHz")
+	command_line_parser.AddOption(misc.STRING, "host_caches", "32768:8:4,262144:8:12,8388608:16:40",
+		"host cache levels as size:associativity:latency [cycle], from L1 to the last level")
+	command_line_parser.AddOption(misc.INT, "host_cache_line_size", "64",
+		"host cache line size in bytes")
+	command_line_parser.AddOption(misc.STRING, "host_cost_table_filepath", "",
+		"path to a file overriding the host cycles of op codes, one \"OP_CODE cycles\" per line")
+	command_line_parser.AddOption(misc.STRING, "host_args", "",
+		"args of the host program after its name, separated by spaces")
+	command_line_parser.AddOption(misc.STRING, "host_defines", "",
+		"macros of the host program as NAME or NAME=VALUE, separated by commas")
+
+	command_line_parser.AddOption(misc.BOOL, "vm_gc_generational", "false",
+		"whether the VM garbage collector collects young temporaries apart from old ones")
+	command_line_parser.AddOption(misc.INT, "vm_gc_mark_budget", "64",
+		"number of objects the VM garbage collector marks per bytecode, 0 to mark all at once")
+	command_line_parser.AddOption(misc.INT, "vm_gc_major_interval", "8",
+		"number of minor collections per major one of the generational VM collector")
+	command_line_parser.AddOption(misc.BOOL, "vm_debug", "false",
+		"whether the VM stops at main and takes debugger commands on the standard input")
+
 	command_line_parser.AddOption(misc.INT, "logic_frequency", "350", "DPU logic frequency in MHz")
 	command_line_parser.AddOption(misc.INT, "memory_frequency", "2400",
 		"DPU MRAM frequency in MHz")
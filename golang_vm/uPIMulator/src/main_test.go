@@ -0,0 +1,1603 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"uPIMulator/src/device/linker"
+	"uPIMulator/src/host/abi"
+	"uPIMulator/src/host/interpreter"
+	"uPIMulator/src/host/vm"
+	"uPIMulator/src/host/vm/arena"
+	"uPIMulator/src/host/vm/base"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/program"
+)
+
+// NOTE: the data preparation of a benchmark is its host program from the start up to the first
+// dpu_push_xfer, which runs on the VM alone, so that the DPU task is replaced by an empty one
+//
+// NOTE: with -benchtime 3x, GEMV took 1.32 s and MLP 1.29 s per run when the bytecodes were parsed
+// from their text form and dispatched through a switch, and 1.11 s and 1.23 s once they were
+// decoded from the binary format and dispatched through a table; the text path was removed since
+func BenchmarkDataPrepGemv(b *testing.B) {
+	RunDataPrep(b, "GEMV", "256")
+}
+
+func BenchmarkDataPrepMlp(b *testing.B) {
+	RunDataPrep(b, "MLP", "64")
+}
+
+// NOTE: the temporaries of an expression come and go while the arrays of the program stay alive
+func BenchmarkPoolTemporaries(b *testing.B) {
+	pool := new(arena.Pool)
+	pool.Init()
+
+	for i := 0; i < 1024; i++ {
+		pool.Alloc(base.UNTEMPORARY, 4096)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		addresses := make([]int64, 0)
+		for j := 0; j < 256; j++ {
+			addresses = append(addresses, pool.Alloc(base.TEMPORARY, int64(1+j%8)).Address())
+		}
+
+		for _, address := range addresses {
+			pool.Free(address)
+		}
+	}
+
+	b.ReportMetric(pool.Fragmentation(), "fragmentation")
+}
+
+// NOTE: arrays of mixed sizes are allocated and every other one is freed, so that the pool has to
+// reuse the holes they leave behind
+func BenchmarkPoolArrays(b *testing.B) {
+	pool := new(arena.Pool)
+	pool.Init()
+
+	for i := 0; i < b.N; i++ {
+		addresses := make([]int64, 0)
+		for j := 0; j < 1024; j++ {
+			addresses = append(addresses, pool.Alloc(base.UNTEMPORARY, int64(64+j%16*64)).Address())
+		}
+
+		for j := 0; j < len(addresses); j += 2 {
+			pool.Free(addresses[j])
+		}
+	}
+
+	b.ReportMetric(pool.Fragmentation(), "fragmentation")
+}
+
+func RunDataPrep(b *testing.B, benchmark string, data_prep_params string) {
+	root_dirpath, err := filepath.Abs("..")
+	if err != nil {
+		panic(err)
+	}
+
+	bin_dirpath := b.TempDir()
+
+	for _, filename := range []string{
+		"addresses.txt",
+		"values.txt",
+		"atomic.bin",
+		"iram.bin",
+		"wram.bin",
+		"mram.bin",
+	} {
+		write_err := os.WriteFile(filepath.Join(bin_dirpath, filename), []byte{}, 0666)
+		if write_err != nil {
+			panic(write_err)
+		}
+	}
+
+	command_line_parser := InitCommandLineParser()
+	command_line_parser.Parse([]string{
+		"uPIMulator",
+		"--verbose", "0",
+		"--benchmark", benchmark,
+		"--data_prep_params", data_prep_params,
+		"--root_dirpath", root_dirpath,
+		"--bin_dirpath", bin_dirpath,
+	})
+
+	task := new(program.Task)
+	task.Init(command_line_parser)
+
+	interpreter_ := new(interpreter.Interpreter)
+	interpreter_.Init(command_line_parser, 0)
+	interpreter_.Interpret()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		runtime.GC()
+
+		vm_ := new(vm.VirtualMachine)
+		vm_.Init(command_line_parser)
+		b.StartTimer()
+
+		app := new(program.App)
+		app.Init(command_line_parser)
+
+		vm_.Load(app, task)
+
+		for vm_.CanAdvance() && vm_.NextBytecode().OpCode() != abi.DPU_TRANSFER {
+			vm_.Advance()
+		}
+	}
+}
+
+// NOTE: a test program is interpreted as the host program of a benchmark of its own and runs on
+// the VM to the end, where a failed assert panics, while a single DPU and two VM banks keep the
+// set up of the memories short
+func RunProgram(t *testing.T, source string, args ...string) *vm.VirtualMachine {
+	root_dirpath := t.TempDir()
+	bin_dirpath := t.TempDir()
+
+	for _, filename := range []string{
+		"addresses.txt",
+		"values.txt",
+		"atomic.bin",
+		"iram.bin",
+		"wram.bin",
+		"mram.bin",
+	} {
+		WriteTestFile(t, filepath.Join(bin_dirpath, filename), "")
+	}
+
+	command_line_parser := ParseTestCommandLine(root_dirpath, bin_dirpath, args)
+
+	return RunHost(t, command_line_parser, source)
+}
+
+// NOTE: the crt0 of a test kernel calls its main on the first tasklet and stops once it returns
+const test_crt0 = `
+	.section	.text.__bootstrap,"ax",@progbits
+	.globl	__bootstrap
+	.type	__bootstrap,@function
+__bootstrap:
+	call r23, main
+	jump __sys_end
+	.section	.text.__sys_end,"ax",@progbits
+	.globl	__sys_end
+	.type	__sys_end,@function
+__sys_end:
+	stop true, __sys_end
+`
+
+// NOTE: a test kernel is hand-written DPU assembly, which is linked as the task of the benchmark
+// along with a crt0 of its own, so that neither of them has to be compiled, and runs on a single
+// tasklet
+func RunKernelProgram(t *testing.T, source string, kernel string, args ...string) *vm.VirtualMachine {
+	root_dirpath := t.TempDir()
+	bin_dirpath := t.TempDir()
+
+	command_line_parser := LinkKernel(t, root_dirpath, bin_dirpath, kernel, args)
+
+	return RunHost(t, command_line_parser, source)
+}
+
+func LinkKernel(
+	t *testing.T,
+	root_dirpath string,
+	bin_dirpath string,
+	kernel string,
+	args []string,
+) *misc.CommandLineParser {
+	WriteTestFile(
+		t,
+		filepath.Join(root_dirpath, "sdk", "build", "misc", "CMakeFiles", "misc.dir", "crt0.c.o"),
+		test_crt0,
+	)
+	WriteTestFile(
+		t,
+		filepath.Join(
+			root_dirpath,
+			"benchmark",
+			"build",
+			"TEST",
+			"dpu",
+			"CMakeFiles",
+			"TEST_device.dir",
+			"task.c.o",
+		),
+		kernel,
+	)
+
+	command_line_parser := ParseTestCommandLine(
+		root_dirpath,
+		bin_dirpath,
+		append([]string{"--num_tasklets", "1"}, args...),
+	)
+
+	linker_ := new(linker.Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	return command_line_parser
+}
+
+func WriteTestFile(t *testing.T, path string, content string) {
+	if mkdir_err := os.MkdirAll(filepath.Dir(path), 0777); mkdir_err != nil {
+		t.Fatal(mkdir_err)
+	}
+
+	if write_err := os.WriteFile(path, []byte(content), 0666); write_err != nil {
+		t.Fatal(write_err)
+	}
+}
+
+func ParseTestCommandLine(
+	root_dirpath string,
+	bin_dirpath string,
+	args []string,
+) *misc.CommandLineParser {
+	// NOTE: a default is only given to the options that the test does not give itself
+	defaults := [][]string{
+		{"--verbose", "0"},
+		{"--benchmark", "TEST"},
+		{"--num_dpus_per_rank", "1"},
+		{"--num_vm_ranks_per_channel", "1"},
+		{"--num_vm_banks_per_rank", "2"},
+		{"--root_dirpath", root_dirpath},
+		{"--bin_dirpath", bin_dirpath},
+	}
+
+	command_line := []string{"uPIMulator"}
+	for _, default_ := range defaults {
+		if !slices.Contains(args, default_[0]) {
+			command_line = append(command_line, default_...)
+		}
+	}
+
+	command_line_parser := InitCommandLineParser()
+	command_line_parser.Parse(append(command_line, args...))
+
+	return command_line_parser
+}
+
+func RunHost(
+	t *testing.T,
+	command_line_parser *misc.CommandLineParser,
+	source string,
+) *vm.VirtualMachine {
+	WriteTestFile(
+		t,
+		filepath.Join(
+			command_line_parser.StringParameter("root_dirpath"),
+			"benchmark",
+			"TEST",
+			"host",
+			"app.c",
+		),
+		source,
+	)
+
+	task := new(program.Task)
+	task.Init(command_line_parser)
+
+	// NOTE: the MRAM heap of a linked kernel starts where its used MRAM ends, as in main
+	dpu_mram_heap_pointer_name := int64(0)
+	if _, found := task.Values()["__sys_used_mram_end"]; found {
+		dpu_mram_heap_pointer_name = task.SysUsedMramEnd()
+	}
+
+	interpreter_ := new(interpreter.Interpreter)
+	interpreter_.Init(command_line_parser, dpu_mram_heap_pointer_name)
+	interpreter_.Interpret()
+
+	app := new(program.App)
+	app.Init(command_line_parser)
+
+	vm_ := new(vm.VirtualMachine)
+	vm_.Init(command_line_parser)
+	vm_.Load(app, task)
+
+	for vm_.CanAdvance() {
+		vm_.Advance()
+	}
+
+	return vm_
+}
+
+func TestProgramUnsignedWraparound(t *testing.T) {
+	RunProgram(t, `
+		int main() {
+			unsigned int x = 0;
+			x--;
+			assert(x == 4294967295);
+			x++;
+			assert(x == 0);
+
+			unsigned char c = 255;
+			c++;
+			assert(c == 0);
+
+			unsigned short s = 0;
+			s -= 1;
+			assert(s == 65535);
+
+			int i = 2147483647;
+			i++;
+			assert(i == -2147483647 - 1);
+
+			uint32_t u = 1;
+			u <<= 31;
+			u *= 2;
+			assert(u == 0);
+			return 0;
+		}
+	`)
+}
+
+func TestProgramFloat(t *testing.T) {
+	RunProgram(t, `
+		int main() {
+			float f = 1.5;
+			f = f * 2.0 + 0.25;
+			assert(f == 3.25);
+
+			double d = 7.0;
+			d /= 2.0;
+			assert(d == 3.5);
+			assert(d > 3.0 && d < 4.0);
+
+			int i = (int)d;
+			assert(i == 3);
+
+			float g = (float)i / 2;
+			assert(g == 1.5);
+			return 0;
+		}
+	`)
+}
+
+func TestProgramSwitch(t *testing.T) {
+	RunProgram(t, `
+		int classify(int x) {
+			int y = 0;
+			switch (x) {
+			case 0:
+				y = 10;
+				break;
+			case 1:
+				y += 1;
+			case 2:
+				y += 2;
+				break;
+			default:
+				y = -1;
+			}
+			return y;
+		}
+
+		int main() {
+			assert(classify(0) == 10);
+			assert(classify(1) == 3);
+			assert(classify(2) == 2);
+			assert(classify(5) == -1);
+			return 0;
+		}
+	`)
+}
+
+func TestProgramMacros(t *testing.T) {
+	RunProgram(t, `
+		#define N 4
+		#define SQUARE(x) ((x) * (x))
+		#define MAX(a, b) ((a) > (b) ? (a) : (b))
+		#define DIVCEIL(n, d) (((n) - 1) / (d) + 1)
+
+		#ifdef N
+		#define HAS_N 1
+		#else
+		#define HAS_N 0
+		#endif
+
+		int main() {
+			int a[N];
+			for (int i = 0; i < N; i++) a[i] = SQUARE(i + 1);
+			assert(a[N - 1] == 16);
+			assert(MAX(a[0], a[1]) == 4);
+			assert(DIVCEIL(10, N) == 3);
+			assert(HAS_N == 1);
+			return 0;
+		}
+	`)
+}
+
+func TestProgramStatements(t *testing.T) {
+	RunProgram(t, `
+		int g1, g2 = 5, *gp;
+
+		struct point {
+			int x, y;
+		};
+
+		int main() {
+			int a = 3, *b = &a, c[4], d;
+			*b = 4;
+			assert(a == 4);
+
+			gp = &g2;
+			assert(*gp == 5 && g1 == 0);
+
+			struct point p, *q = &p;
+			q->x = 1;
+			q->y = 2;
+			assert(p.x + p.y == 3);
+
+			d = 0;
+			for (int i = 0, j = 3; i < 4; i++) c[i] = j - i;
+			for (int i = 0; i < 4; i++)
+				if (c[i] % 2 == 0) d += c[i];
+				else if (c[i] == 1) d += 10;
+				else d += 100;
+			assert(d == 112);
+
+			while (d > 100) d--;
+			do d++; while (d < 105);
+			assert(d == 105);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: the assignments of expr stmts leave nothing on the stack, so that a loop of them does not
+// grow the stack, and the condition of a loop is evaluated once per check since it may assign
+func TestProgramAssignmentValues(t *testing.T) {
+	RunProgram(t, `
+		int count = 3;
+
+		int next() {
+			count--;
+			return count;
+		}
+
+		int main() {
+			int a = 3, b, c[4];
+			c[1] = (c[0] = a + a) + 1;
+			assert(c[0] == 6 && c[1] == 7);
+
+			a += (b = 2);
+			assert(a == 5 && b == 2);
+
+			if ((b = a * 2) == 10) b++;
+			assert(b == 11);
+
+			int n, sum = 0;
+			while ((n = next()) > 0) sum += n;
+			assert(sum == 3 && n == 0);
+
+			long l = (a = 7);
+			assert(l == 7);
+
+			for (int i = 0; i < 1000; i++) a = i;
+			assert(a == 999);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: an offset pointer is used as the buffer of a DPU in the PrIM hosts, e.g. bufferA + size * i
+func TestProgramPointerArithmetic(t *testing.T) {
+	RunProgram(t, `
+		struct point {
+			int x;
+			long y;
+		};
+
+		int main() {
+			int *a = malloc(8 * sizeof(int));
+			for (int i = 0; i < 8; i++) a[i] = 10 * i;
+
+			int *p = a + 3;
+			assert(p[0] == 30 && p[1] == 40);
+			assert((2 + a)[0] == 20);
+			p = p - 1;
+			assert(p[0] == 20);
+			assert(p - a == 2 && a - p == -2);
+
+			long *l = malloc(4 * sizeof(long));
+			(l + 3)[0] = 7;
+			assert(l[3] == 7);
+
+			char *c = malloc(4);
+			(c + 1)[0] = 'x';
+			assert(c[1] == 'x');
+
+			struct point *s = malloc(4 * sizeof(struct point));
+			(s + 2)->y = 5;
+			assert(s[2].y == 5);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: a string literal is true as in the asserts of the PrIM hosts, and a floating point operand
+// is not truncated before it is tested
+func TestProgramLogicalOperands(t *testing.T) {
+	RunProgram(t, `
+		int main() {
+			int a = 3;
+			int *p = &a;
+			double d = 0.5;
+			long l = 0;
+
+			assert(a > 0 && "a should be positive");
+			assert(p && a);
+			assert(d && 1);
+			assert((l || d) == 1);
+			assert((l && "never") == 0);
+			return 0;
+		}
+	`)
+}
+
+func TestProgramTypedefs(t *testing.T) {
+	RunProgram(t, `
+		typedef unsigned int uint32_t;
+		typedef int bool;
+		typedef struct {
+			uint32_t size;
+			bool flag;
+		} params_t;
+
+		int main() {
+			uint32_t x = 0;
+			x--;
+			assert(x == 4294967295);
+
+			bool b = 300;
+			assert(b == 300);
+
+			params_t p;
+			p.size = 7;
+			assert(p.size == 7);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: an enum may be tagged, typedef'd or nested in a struct, and its constants may be negative
+func TestProgramEnums(t *testing.T) {
+	RunProgram(t, `
+		enum color { RED, GREEN = 5, BLUE };
+		typedef enum { LOW = -1, HIGH = 0x10 } level_t;
+
+		struct args {
+			unsigned int size;
+			enum kernels { kernel1 = 0, nr_kernels = 1 } kernel;
+		};
+
+		int main() {
+			enum color c = BLUE;
+			assert(RED == 0 && GREEN == 5 && c == 6);
+			level_t l = LOW;
+			assert(l == -1 && HIGH == 16);
+			struct args a;
+			a.kernel = nr_kernels;
+			assert(a.kernel == 1);
+			return 0;
+		}
+	`)
+}
+
+func TestProgramLiterals(t *testing.T) {
+	RunProgram(t, `
+		int main() {
+			assert(0x1F == 31 && 0XfF == 255 && 0x0 == 0);
+			unsigned int u = 0xFFFFFFFFu;
+			assert(u == 4294967295);
+			long long l = 0x100000000LL;
+			assert(l == 4294967296);
+			assert(10UL == 10);
+			assert('A' == 65 && 'a' - 'A' == 32);
+			assert('\n' == 10 && '\0' == 0 && '\\' == 92 && '\'' == 39);
+			char c = 'z';
+			assert(c - 'a' == 25);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: the elements and fields that an initializer list leaves out are zeroed
+func TestProgramInitializers(t *testing.T) {
+	RunProgram(t, `
+		struct point { int x; int y; };
+
+		int main() {
+			int a[4] = {1, 2, 3, 4};
+			assert(a[0] + a[3] == 5);
+			int b[3] = {7};
+			assert(b[0] == 7 && b[1] == 0 && b[2] == 0);
+			struct point p = {3, -4};
+			assert(p.x == 3 && p.y == -4);
+			int s = {9};
+			assert(s == 9);
+			char str[6] = "hello";
+			assert(str[0] == 'h' && str[4] == 'o' && str[5] == 0);
+			double d[2] = {0.5, 1.5};
+			assert(d[0] + d[1] == 2.0);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: global arrays are zeroed before main runs, and may be sized by a macro or an initializer
+func TestProgramGlobalArrays(t *testing.T) {
+	RunProgram(t, `
+		#define N 8
+		int table[N];
+		unsigned int counts[N / 2];
+		int primes[4] = {2, 3, 5, 7};
+		int total = 10;
+
+		void fill() {
+			for (int i = 0; i < N; i++) table[i] = i * i;
+		}
+
+		int main() {
+			assert(table[N - 1] == 0 && counts[0] == 0);
+			fill();
+			assert(table[N - 1] == 49);
+			counts[3] = table[2];
+			assert(counts[3] == 4);
+			assert(primes[0] + primes[3] == 9 && total == 10);
+			primes[1] = total;
+			assert(primes[1] == 10);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: the operand of a sizeof is typed but not evaluated, where a pointer is 4 bytes on the VM
+func TestProgramSizeof(t *testing.T) {
+	RunProgram(t, `
+		struct point {
+			int x;
+			double y;
+		};
+
+		int main() {
+			struct point p;
+			struct point *q = &p;
+			int a[4];
+			double d = 0.5;
+			assert(sizeof(p) == 12 && sizeof(*q) == 12 && sizeof(q) == sizeof(int *));
+			assert(sizeof(a[0]) == 4 && sizeof d == 8 && sizeof(p.x + d) == 8);
+			assert(sizeof(q->x) * 2 == 8 && sizeof("abc") == sizeof(char *));
+
+			int i = 0;
+			int n = sizeof(i++);
+			assert(i == 0 && n == 4);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: the elements of an array field are laid out in the struct, so that they are sized, copied
+// and subscripted in place
+func TestProgramArrayFields(t *testing.T) {
+	RunProgram(t, `
+		#define N 4
+		struct span { int begin; int end; };
+		struct timer {
+			struct span spans[N];
+			double times[N];
+			char name[3], tag;
+		};
+		struct pair { char c; struct span span; };
+
+		void start(struct timer *timer, int i) {
+			timer->spans[i].begin = 10 * i;
+			timer->times[i] = 0.0;
+		}
+
+		void stop(struct timer *timer, int i) {
+			struct span *span = &timer->spans[i];
+			span->end = span->begin + i + 1;
+			timer->times[i] += span->end - span->begin;
+		}
+
+		int main() {
+			assert(sizeof(struct timer) == N * 8 + N * 8 + 3 + 1);
+
+			struct timer timer;
+			for (int i = 0; i < N; i++) {
+				start(&timer, i);
+				stop(&timer, i);
+			}
+			assert(timer.spans[3].begin == 30 && timer.spans[3].end == 34);
+			assert(timer.times[0] + timer.times[3] == 5.0);
+
+			timer.name[0] = 'a';
+			timer.name[2] = 'c';
+			timer.tag = 'z';
+			struct timer copy = timer;
+			timer.name[0] = 'b';
+			assert(copy.name[0] == 'a' && copy.name[2] == 'c' && copy.tag == 'z');
+			assert(copy.spans[2].end == 23);
+
+			double *times = timer.times;
+			assert(times[1] == 2.0);
+
+			struct pair pair;
+			pair.span = timer.spans[1];
+			struct span span = pair.span;
+			assert(span.begin == 10 && span.end == 12);
+			return 0;
+		}
+	`)
+}
+
+// NOTE: adjacent string literals are concatenated after the macros of the strings are expanded
+func TestProgramStringConcatenation(t *testing.T) {
+	RunProgram(t, `
+		#define GREEN "\x1b[32m"
+		#define RESET "\x1b[0m"
+
+		int main() {
+			char s[] = "[" GREEN "OK" RESET "]";
+			assert(s[0] == '[' && s[1] == 27 && s[6] == 'O' && s[12] == ']' && s[13] == 0);
+
+			char t[] = "a" "b"
+				"c";
+			assert(t[2] == 'c' && t[3] == 0);
+			return 0;
+		}
+	`)
+}
+
+func PrintProgram(t *testing.T, source string, args ...string) (string, *vm.VirtualMachine) {
+	var vm_ *vm.VirtualMachine
+	output := CaptureOutput(t, func() {
+		vm_ = RunProgram(t, source, args...)
+	})
+
+	return output, vm_
+}
+
+// NOTE: the standard output and the standard error of a printing program are swapped for a single
+// file while the program runs
+func CaptureOutput(t *testing.T, run func()) string {
+	output_filepath := filepath.Join(t.TempDir(), "output.txt")
+
+	output_file, create_err := os.Create(output_filepath)
+	if create_err != nil {
+		t.Fatal(create_err)
+	}
+	defer output_file.Close()
+
+	stdout, stderr := os.Stdout, os.Stderr
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+	}()
+	os.Stdout, os.Stderr = output_file, output_file
+
+	run()
+
+	output, read_err := os.ReadFile(output_filepath)
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	return string(output)
+}
+
+func TestProgramPrintf(t *testing.T) {
+	output, _ := PrintProgram(t, `
+		int main() {
+			char name[] = "va";
+			unsigned int u = 4294967295;
+			long l = -5;
+			float f = 0.5;
+
+			printf("%s: %d %i %u %ld\n", name, -3, 7, u, l);
+			printf("[%5d|%-5d|%05d|%x|%#X|%o|%c|%%]\n", 42, 42, 42, 255, 255, 8, 'A');
+			printf("%f %.2f %e %g %g\n", f, 3.14159, 1500.0, 0.1, 1234567.0);
+			printf("%10.3f|%s\n", 2.0, "tail");
+			fprintf(stderr, "err %d\n", 1);
+			fprintf(stdout, "out\n");
+			printf("no values\n");
+			return 0;
+		}
+	`)
+
+	expected := "va: -3 7 4294967295 -5\n" +
+		"[   42|42   |00042|ff|0XFF|10|A|%]\n" +
+		"0.500000 3.14 1.500000e+03 0.1 1.23457e+06\n" +
+		"     2.000|tail\n" +
+		"err 1\n" +
+		"out\n" +
+		"no values\n"
+
+	if output != expected {
+		t.Errorf("output is %q, expected %q", output, expected)
+	}
+}
+
+// NOTE: rand is the example generator of the C standard, whose first numbers of the seed 1 are
+// 16838 and 5758
+func TestProgramStdlib(t *testing.T) {
+	output, vm_ := PrintProgram(t, `
+		int main() {
+			assert(atoi("42") == 42);
+			assert(atoi("  -17abc") == -17);
+			assert(atoi("+8") == 8);
+			assert(atoi("x1") == 0);
+
+			char digits[] = "123";
+			assert(atoi(digits) == 123);
+
+			int first = rand();
+			assert(first == 16838);
+			assert(rand() == 5758);
+			srand(1);
+			assert(rand() == first);
+			assert(rand() <= RAND_MAX);
+
+			struct timeval start;
+			struct timeval end;
+			gettimeofday(&start, NULL);
+			int sum = 0;
+			for (int i = 0; i < 1000; i++) sum += i;
+			gettimeofday(&end, NULL);
+
+			long start_us = start.tv_sec * 1000000 + start.tv_usec;
+			long end_us = end.tv_sec * 1000000 + end.tv_usec;
+			assert(end_us > start_us);
+			assert(start.tv_usec < 1000000 && end.tv_usec < 1000000);
+
+			printf("before exit\n");
+			exit(3);
+			printf("after exit\n");
+			assert(0);
+			return 0;
+		}
+	`)
+
+	if output != "before exit\n" {
+		t.Errorf("output is %q, expected only the print before exit", output)
+	}
+	if vm_.StatFactory().Value("exit_status") != 3 {
+		t.Errorf("exit status is %d, expected 3", vm_.StatFactory().Value("exit_status"))
+	}
+}
+
+// NOTE: the host args are parsed as the PrIM host programs parse them, where the options of an arg
+// may be given together and the value of an option either in its arg or in the next one
+func TestProgramHostArgs(t *testing.T) {
+	output, _ := PrintProgram(t, `
+		#ifndef BL
+		#define BL 8
+		#endif
+
+		int main(int argc, char **argv) {
+			assert(BL == 10 && INT32 == 1);
+			#if ENERGY
+			assert(0);
+			#endif
+
+			int input = 0;
+			int warmup = 0;
+			int verbose = 0;
+			int num_invalid = 0;
+			int opt;
+			while ((opt = getopt(argc, argv, "hi:w:v")) >= 0) {
+				switch (opt) {
+				case 'i': input = atoi(optarg); break;
+				case 'w': warmup = atoi(optarg); break;
+				case 'v': verbose++; break;
+				default: num_invalid++;
+				}
+			}
+
+			assert(input == 64 && warmup == 2 && verbose == 2 && num_invalid == 1);
+
+			for (int i = 0; i < argc; i++) printf("%d %s\n", i, argv[i]);
+			return 0;
+		}
+	`, "--host_args", "-i 64 -vw2 -v -x", "--host_defines", "BL=10,INT32,ENERGY=0")
+
+	expected := "TEST: invalid option -- 'x'\n" +
+		"0 TEST\n" +
+		"1 -i\n" +
+		"2 64\n" +
+		"3 -vw2\n" +
+		"4 -v\n" +
+		"5 -x\n"
+
+	if output != expected {
+		t.Errorf("output is %q, expected %q", output, expected)
+	}
+}
+
+// NOTE: the list is built, walked and freed with a temporary for every step, so that collections
+// of every mode run while pointers to the nodes only live in the memory
+func TestProgramGarbageCollection(t *testing.T) {
+	source := `
+		struct node {
+			int value;
+			struct node *next;
+		};
+
+		struct node *push(struct node *head, int value) {
+			struct node *node = (struct node *)malloc(sizeof(struct node));
+			node->value = value;
+			node->next = head;
+			return node;
+		}
+
+		int main() {
+			struct node *head = NULL;
+			for (int i = 0; i < 64; i++) head = push(head, i);
+
+			int sum = 0;
+			struct node *node = head;
+			for (int i = 0; i < 64; i++) {
+				sum += node->value;
+				node = node->next;
+			}
+			assert(sum == 2016);
+
+			for (int i = 0; i < 64; i++) {
+				struct node *next = head->next;
+				free(head);
+				head = next;
+			}
+			return 0;
+		}
+	`
+
+	for _, is_generational := range []string{"false", "true"} {
+		for _, mark_budget := range []string{"0", "1", "64"} {
+			vm_ := RunProgram(
+				t,
+				source,
+				"--vm_gc_generational", is_generational,
+				"--vm_gc_mark_budget", mark_budget,
+			)
+
+			stat_factory := vm_.GarbageCollector().StatFactory()
+
+			if stat_factory.Value("freed_objects") == 0 {
+				t.Errorf("generational (%s) with mark budget (%s) frees nothing", is_generational, mark_budget)
+			}
+			if is_generational == "true" && stat_factory.Value("minor_collections") == 0 {
+				t.Errorf("generational mode with mark budget (%s) runs no minor collection", mark_budget)
+			}
+			if mark_budget != "0" && stat_factory.Value("mark_steps") == 0 {
+				t.Errorf("mark budget (%s) takes no incremental step", mark_budget)
+			}
+		}
+	}
+}
+
+// NOTE: the debugger reads its commands from the standard input and writes to the standard output,
+// which are swapped for files while the program runs
+func DebugProgram(t *testing.T, source string, commands []string) string {
+	dirpath := t.TempDir()
+
+	input_filepath := filepath.Join(dirpath, "input.txt")
+	output_filepath := filepath.Join(dirpath, "output.txt")
+
+	input := []byte(strings.Join(commands, "\n") + "\n")
+	if write_err := os.WriteFile(input_filepath, input, 0666); write_err != nil {
+		t.Fatal(write_err)
+	}
+
+	input_file, open_err := os.Open(input_filepath)
+	if open_err != nil {
+		t.Fatal(open_err)
+	}
+	defer input_file.Close()
+
+	output_file, create_err := os.Create(output_filepath)
+	if create_err != nil {
+		t.Fatal(create_err)
+	}
+	defer output_file.Close()
+
+	stdin, stdout := os.Stdin, os.Stdout
+	defer func() {
+		os.Stdin, os.Stdout = stdin, stdout
+	}()
+	os.Stdin, os.Stdout = input_file, output_file
+
+	RunProgram(t, source, "--vm_debug", "true")
+
+	output, read_err := os.ReadFile(output_filepath)
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	return string(output)
+}
+
+func TestProgramDebugger(t *testing.T) {
+	source := `struct point {
+	int x;
+	int y;
+};
+
+int scale(struct point *p, int k) {
+	int x = p->x * k;
+	int y = p->y * k;
+	return x + y;
+}
+
+int main() {
+	struct point p;
+	p.x = 1;
+	p.y = 2;
+	struct point *q = &p;
+	int s = scale(q, 3);
+	s = s + 1;
+	assert(s == 10);
+	return 0;
+}
+`
+
+	output := DebugProgram(t, source, []string{
+		"break scale",
+		"next",
+		"next",
+		"next",
+		"next",
+		"print p",
+		"next",
+		"print q",
+		"print *q",
+		"print q->y",
+		"step",
+		"print *p",
+		"print p->x",
+		"next",
+		"finish",
+		"print s",
+		"continue",
+	})
+
+	// NOTE: finish returns to the middle of line 17, which does not stop, so that it stops at 18
+	expected := []string{
+		"main () at app.c:12\n12\tint main() {",
+		"Breakpoint 1 at scale",
+		"main () at app.c:13",
+		"main () at app.c:16\n16\t\tstruct point *q = &p;",
+		"$1 = {x = 1, y = 2}",
+		"main () at app.c:17",
+		"$2 = (struct point *) 0x",
+		"$3 = {x = 1, y = 2}",
+		"$4 = 2",
+		"Breakpoint 1, scale () at app.c:6",
+		"$5 = {x = 1, y = 2}",
+		"$6 = 1",
+		"scale () at app.c:7\n7\t\tint x = p->x * k;",
+		"Run till exit from scale () at app.c:7",
+		"main () at app.c:18",
+		"$7 = 9",
+	}
+
+	pos := 0
+	for _, line := range expected {
+		found := strings.Index(output[pos:], line)
+		if found < 0 {
+			t.Fatalf("%q is not printed in order in\n%s", line, output)
+		}
+		pos += found + len(line)
+	}
+
+	if strings.Contains(output, "Breakpoint 1, main") || strings.Count(output, "Breakpoint 1,") != 1 {
+		t.Errorf("breakpoint is not hit once in\n%s", output)
+	}
+}
+
+func TestProgramDebuggerArrayFields(t *testing.T) {
+	source := `struct timer {
+	int counts[3];
+	double time;
+};
+
+int main() {
+	struct timer timer;
+	timer.counts[0] = 4;
+	timer.counts[2] = 6;
+	timer.time = 0.5;
+	assert(timer.counts[0] + timer.counts[2] == 10);
+	return 0;
+}
+`
+
+	output := DebugProgram(t, source, []string{
+		"break 11",
+		"continue",
+		"print timer",
+		"print timer.counts",
+		"print timer.counts[2]",
+		"print *timer.counts",
+		"print timer.counts[3]",
+		"continue",
+	})
+
+	expected := []string{
+		"$1 = {counts = {4, 0, 6}, time = 0.5}",
+		"$2 = {4, 0, 6}",
+		"$3 = 6",
+		"$4 = 4",
+		"Index 3 is out of the bounds of an array of 3.",
+	}
+
+	pos := 0
+	for _, line := range expected {
+		found := strings.Index(output[pos:], line)
+		if found < 0 {
+			t.Fatalf("%q is not printed in order in\n%s", line, output)
+		}
+		pos += found + len(line)
+	}
+}
+
+// NOTE: the kernel counts up to the bound given by the host before it adds the count to its input,
+// so that the host works while the DPU is still running
+const count_kernel = `
+	.section	.text.main,"ax",@progbits
+	.globl	main
+	.type	main,@function
+main:
+	lw r0, zero, input
+	lw r2, zero, bound
+	move r1, 0
+.LBB0_1:
+	add r1, r1, 1
+	jneq r1, r2, .LBB0_1
+	add r0, r0, r1
+	sw zero, output, r0
+	jump r23
+	.section	.data.input,"aw",@progbits
+	.globl	input
+	.p2align	2
+input:
+	.long	0
+	.section	.data.bound,"aw",@progbits
+	.globl	bound
+	.p2align	2
+bound:
+	.long	0
+	.section	.data.output,"aw",@progbits
+	.globl	output
+	.p2align	2
+output:
+	.long	0
+`
+
+// NOTE: the output is copied from the DPU either while it is still running, where the transfer
+// waits for the kernel to finish, or after the host has synchronized with it
+func TestProgramAsynchronousLaunch(t *testing.T) {
+	source := `
+		int main() {
+			struct dpu_set_t dpu_set;
+			dpu_alloc(1, NULL, &dpu_set);
+			dpu_load(dpu_set, "kernel", NULL);
+
+			int input = 5;
+			int bound = 2000;
+			dpu_copy_to(dpu_set, "input", 0, &input, sizeof(int));
+			dpu_copy_to(dpu_set, "bound", 0, &bound, sizeof(int));
+
+			dpu_launch(dpu_set, DPU_ASYNCHRONOUS);
+
+			int sum = 0;
+			for (int i = 0; i < 100; i++) sum += i;
+			assert(sum == 4950);
+
+			%s
+
+			int output = 0;
+			dpu_copy_from(dpu_set, "output", 0, &output, sizeof(int));
+			assert(output == 2005);
+
+			dpu_sync(dpu_set);
+			dpu_free(dpu_set);
+			return 0;
+		}
+	`
+
+	for _, is_synchronized := range []bool{false, true} {
+		sync := ""
+		if is_synchronized {
+			sync = "dpu_sync(dpu_set);"
+		}
+
+		vm_ := RunKernelProgram(t, fmt.Sprintf(source, sync), count_kernel)
+
+		stat_factory := vm_.StatFactory()
+
+		if stat_factory.Value("overlapped_dpu_cycles") == 0 {
+			t.Errorf("DPU does not run while the host works (synchronized: %t)", is_synchronized)
+		}
+
+		if is_synchronized && stat_factory.Value("serialized_transfers") != 0 {
+			t.Errorf("transfer after dpu_sync waits for a running DPU")
+		} else if !is_synchronized && stat_factory.Value("serialized_transfers") != 1 {
+			t.Errorf("transfer from a running DPU does not wait for it to finish")
+		}
+	}
+}
+
+// NOTE: every DPU adds the broadcast bias to its own input and keeps a print of 2718 with the
+// format "log %d" in its stdout buffer, which lives in MRAM like the one of the SDK
+const bias_kernel = `
+	.section	.text.main,"ax",@progbits
+	.globl	main
+	.type	main,@function
+main:
+	lw r0, zero, input
+	lw r1, zero, bias
+	add r0, r0, r1
+	sw zero, output, r0
+	jump r23
+	.section	.data.input,"aw",@progbits
+	.globl	input
+	.p2align	3
+input:
+	.long	0
+	.section	.data.bias,"aw",@progbits
+	.globl	bias
+	.p2align	3
+bias:
+	.long	0
+	.section	.data.output,"aw",@progbits
+	.globl	output
+	.p2align	3
+output:
+	.long	0
+	.section	.data.__stdout_buffer_state,"aw",@progbits
+	.globl	__stdout_buffer_state
+	.p2align	3
+__stdout_buffer_state:
+	.long	16
+	.long	0
+	.section	.data.__stdout_buffer_size,"aw",@progbits
+	.globl	__stdout_buffer_size
+	.p2align	3
+__stdout_buffer_size:
+	.long	16
+	.section	.mram.__stdout_buffer,"aw",@progbits
+	.globl	__stdout_buffer
+	.p2align	3
+__stdout_buffer:
+	.byte	108
+	.byte	111
+	.byte	103
+	.byte	32
+	.byte	37
+	.byte	100
+	.byte	0
+	.byte	158
+	.byte	10
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	0
+`
+
+// NOTE: 3 DPUs are allocated out of 2 ranks of 2 DPUs, so that the set spans a full rank and a
+// partial one, and every DPU is free again once the set is freed
+func TestProgramDpuSet(t *testing.T) {
+	source := `
+		int count_rank(struct dpu_set_t rank, int index, void *args) {
+			int nr_dpus = 0;
+			dpu_get_nr_dpus(rank, &nr_dpus);
+
+			int *counts = (int *) args;
+			counts[index] = nr_dpus;
+			return 0;
+		}
+
+		int main() {
+			struct dpu_set_t dpu_set;
+			dpu_alloc(3, NULL, &dpu_set);
+
+			int nr_dpus = 0;
+			int nr_ranks = 0;
+			dpu_get_nr_dpus(dpu_set, &nr_dpus);
+			dpu_get_nr_ranks(dpu_set, &nr_ranks);
+			assert(nr_dpus == 3);
+			assert(nr_ranks == 2);
+
+			dpu_load(dpu_set, "kernel", NULL);
+
+			int bias = 100;
+			dpu_broadcast_to(dpu_set, "bias", 0, &bias, sizeof(int), DPU_XFER_DEFAULT);
+
+			int inputs[3];
+			DPU_FOREACH(dpu_set, dpu, i) {
+				inputs[i] = 10 * i;
+				dpu_prepare_xfer(dpu, &inputs[i]);
+			}
+			dpu_push_xfer(dpu_set, DPU_XFER_TO_DPU, "input", 0, sizeof(int), DPU_XFER_DEFAULT);
+
+			dpu_launch(dpu_set, DPU_SYNCHRONOUS);
+
+			int outputs[3];
+			DPU_FOREACH(dpu_set, dpu, i) {
+				dpu_prepare_xfer(dpu, &outputs[i]);
+			}
+			dpu_push_xfer(dpu_set, DPU_XFER_FROM_DPU, "output", 0, sizeof(int), DPU_XFER_NO_RESET);
+			for (int i = 0; i < 3; i++) {
+				assert(outputs[i] == 10 * i + 100);
+				outputs[i] = -1;
+			}
+
+			dpu_push_xfer(dpu_set, DPU_XFER_FROM_DPU, "output", 0, sizeof(int), DPU_XFER_DEFAULT);
+			for (int i = 0; i < 3; i++) {
+				assert(outputs[i] == 10 * i + 100);
+				outputs[i] = -1;
+			}
+
+			dpu_push_xfer(dpu_set, DPU_XFER_FROM_DPU, "output", 0, sizeof(int), DPU_XFER_DEFAULT);
+			for (int i = 0; i < 3; i++) {
+				assert(outputs[i] == -1);
+			}
+
+			int rank_dpus[2];
+			int nr_iterations = 0;
+			DPU_RANK_FOREACH(dpu_set, rank, i) {
+				int nr_rank_dpus = 0;
+				dpu_get_nr_dpus(rank, &nr_rank_dpus);
+				rank_dpus[i] = nr_rank_dpus;
+				nr_iterations++;
+			}
+			assert(nr_iterations == 2);
+			assert(rank_dpus[0] == 2);
+			assert(rank_dpus[1] == 1);
+
+			int counts[2];
+			counts[0] = 0;
+			counts[1] = 0;
+			dpu_callback(dpu_set, count_rank, counts, DPU_CALLBACK_DEFAULT);
+			assert(counts[0] == 2);
+			assert(counts[1] == 1);
+
+			dpu_callback(dpu_set, count_rank, counts, DPU_CALLBACK_SINGLE_CALL);
+			assert(counts[0] == 3);
+
+			DPU_FOREACH(dpu_set, dpu) {
+				dpu_log_read(dpu, NULL);
+			}
+
+			dpu_free(dpu_set);
+
+			dpu_alloc(DPU_ALLOCATE_ALL, NULL, &dpu_set);
+			dpu_get_nr_dpus(dpu_set, &nr_dpus);
+			assert(nr_dpus == 4);
+			dpu_free(dpu_set);
+			return 0;
+		}
+	`
+
+	stdout := os.Stdout
+	reader, writer, pipe_err := os.Pipe()
+	if pipe_err != nil {
+		t.Fatal(pipe_err)
+	}
+
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	output := make(chan string)
+	go func() {
+		bytes, _ := io.ReadAll(reader)
+		output <- string(bytes)
+	}()
+
+	RunKernelProgram(
+		t,
+		source,
+		bias_kernel,
+		"--num_ranks_per_channel",
+		"2",
+		"--num_dpus_per_rank",
+		"2",
+	)
+
+	writer.Close()
+	os.Stdout = stdout
+
+	if num_logs := strings.Count(<-output, "log 2718"); num_logs != 3 {
+		t.Errorf("logs of the DPUs are printed %d times", num_logs)
+	}
+}
+
+// NOTE: the set of a rank belongs to the allocation of its root, which alone can be freed
+func TestProgramDpuSetFreeRank(t *testing.T) {
+	source := `
+		int main() {
+			struct dpu_set_t dpu_set;
+			dpu_alloc(DPU_ALLOCATE_ALL, NULL, &dpu_set);
+
+			DPU_RANK_FOREACH(dpu_set, rank) {
+				dpu_free(rank);
+			}
+			return 0;
+		}
+	`
+
+	message := func() (message string) {
+		defer func() {
+			message = fmt.Sprint(recover())
+		}()
+
+		RunKernelProgram(t, source, bias_kernel, "--num_ranks_per_channel", "2")
+		return ""
+	}()
+
+	if !strings.Contains(message, "DPU set of a rank or a DPU cannot be freed") {
+		t.Errorf("freeing the set of a rank panics with %q", message)
+	}
+}
+
+// NOTE: the buffers are written to the VM DRAM right before they are pushed to the MRAM heap, while
+// the host has just initialized them through the rows that are still open
+func TestProgramMramHeap(t *testing.T) {
+	RunKernelProgram(t, `
+		int main() {
+			struct dpu_set_t dpu_set, dpu;
+			dpu_alloc(1, NULL, &dpu_set);
+			dpu_load(dpu_set, "kernel", NULL);
+
+			int* a = malloc(16 * sizeof(int));
+			int* b = malloc(16 * sizeof(int));
+			int* c = malloc(16 * sizeof(int));
+			for (int i = 0; i < 16; i++) {
+				a[i] = i;
+				b[i] = 100 + i;
+			}
+
+			DPU_FOREACH(dpu_set, dpu) {
+				dpu_prepare_xfer(dpu, a);
+			}
+			dpu_push_xfer(dpu_set, DPU_XFER_TO_DPU, DPU_MRAM_HEAP_POINTER_NAME, 0, 64, DPU_XFER_DEFAULT);
+			DPU_FOREACH(dpu_set, dpu) {
+				dpu_prepare_xfer(dpu, b);
+			}
+			dpu_push_xfer(dpu_set, DPU_XFER_TO_DPU, DPU_MRAM_HEAP_POINTER_NAME, 64, 64, DPU_XFER_DEFAULT);
+
+			DPU_FOREACH(dpu_set, dpu) {
+				dpu_prepare_xfer(dpu, c);
+			}
+			dpu_push_xfer(dpu_set, DPU_XFER_FROM_DPU, DPU_MRAM_HEAP_POINTER_NAME, 64, 64, DPU_XFER_DEFAULT);
+			for (int i = 0; i < 16; i++) {
+				assert(c[i] == 100 + i);
+			}
+
+			DPU_FOREACH(dpu_set, dpu) {
+				dpu_prepare_xfer(dpu, c);
+			}
+			dpu_push_xfer(dpu_set, DPU_XFER_FROM_DPU, DPU_MRAM_HEAP_POINTER_NAME, 0, 64, DPU_XFER_DEFAULT);
+			for (int i = 0; i < 16; i++) {
+				assert(c[i] == i);
+			}
+
+			dpu_free(dpu_set);
+			return 0;
+		}
+	`, bias_kernel)
+}
+
+// NOTE: the VA kernel adds the vector at the start of the MRAM heap to the one that follows it
+// after DPU_INPUT_ARGUMENTS.transfer_size bytes, 8 bytes at a time
+const va_kernel = `
+	.section	.text.main,"ax",@progbits
+	.globl	main
+	.type	main,@function
+main:
+	move r0, DPU_INPUT_ARGUMENTS
+	lw r1, r0, 0
+	lw r2, r0, 4
+	move r3, __sys_used_mram_end
+	add r4, r3, r2
+	move r5, 0
+	move r6, buffer_a
+	move r7, buffer_b
+.LBB0_1:
+	jgeu r5, r1, .LBB0_2
+	ldma r6, r3, 0
+	ldma r7, r4, 0
+	lw r8, r6, 0
+	lw r9, r7, 0
+	add r8, r8, r9
+	sw r7, 0, r8
+	lw r8, r6, 4
+	lw r9, r7, 4
+	add r8, r8, r9
+	sw r7, 4, r8
+	sdma r7, r4, 0
+	add r3, r3, 8
+	add r4, r4, 8
+	add r5, r5, 8
+	jump .LBB0_1
+.LBB0_2:
+	jump r23
+	.section	.data.DPU_INPUT_ARGUMENTS,"aw",@progbits
+	.globl	DPU_INPUT_ARGUMENTS
+	.p2align	3
+DPU_INPUT_ARGUMENTS:
+	.long	0
+	.long	0
+	.long	0
+	.long	0
+	.section	.bss.buffer_a,"aw",@nobits
+	.globl	buffer_a
+	.p2align	3
+buffer_a:
+	.zero	8
+	.section	.bss.buffer_b,"aw",@nobits
+	.globl	buffer_b
+	.p2align	3
+buffer_b:
+	.zero	8
+`
+
+// NOTE: the host program of VA is the original one of PrIM, which the golang tree keeps along with
+// the support headers that the VA benchmark of this tree shares with it, and is given its command
+// line and the defines of its Makefile as it is built for the SDK
+func TestPrimVectorAddition(t *testing.T) {
+	root_dirpath := t.TempDir()
+	bin_dirpath := t.TempDir()
+
+	for _, filename := range []string{"common.h", "params.h", "timer.h"} {
+		header, read_err := os.ReadFile(filepath.Join("..", "benchmark", "VA", "support", filename))
+		if read_err != nil {
+			t.Fatal(read_err)
+		}
+
+		WriteTestFile(
+			t,
+			filepath.Join(root_dirpath, "benchmark", "TEST", "support", filename),
+			string(header),
+		)
+	}
+
+	source, read_err := os.ReadFile(
+		filepath.Join("..", "..", "..", "golang", "uPIMulator", "benchmark", "VA", "host", "app.c"),
+	)
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	command_line_parser := LinkKernel(t, root_dirpath, bin_dirpath, va_kernel, []string{
+		"--num_dpus_per_rank", "2",
+		"--host_args", "-i 256 -w 1 -e 2",
+		"--host_defines", "BL=10,INT32,ENERGY=0",
+	})
+
+	output := CaptureOutput(t, func() {
+		RunHost(t, command_line_parser, string(source))
+	})
+
+	if !strings.Contains(output, "Allocated 2 DPU(s)") {
+		t.Errorf("VA does not allocate 2 DPUs")
+	}
+
+	if !strings.Contains(output, "Outputs are equal") {
+		t.Errorf("outputs of VA differ from the ones of the host")
+	}
+}
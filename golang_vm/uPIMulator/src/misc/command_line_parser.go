@@ -25,7 +25,7 @@ func (this *CommandLineParser) AddOption(
 	help_msg string,
 ) {
 	if _, found := this.command_line_options[option]; found {
-		err_msg := fmt.Sprintf("option (%s) is already added to the parser")
+		err_msg := fmt.Sprintf("option (%s) is already added to the parser", option)
 		err := errors.New(err_msg)
 		panic(err)
 	}
@@ -61,6 +61,30 @@ func (this *CommandLineValidator) Validate() {
 		panic(err)
 	}
 
+	if this.command_line_parser.IntParameter("host_frequency") <= 0 {
+		err := errors.New("host_frequency <= 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("host_cache_line_size") <= 0 {
+		err := errors.New("host_cache_line_size <= 0")
+		panic(err)
+	}
+
+	// NOTE: BoolParameter panics unless the parameter is true or false
+	this.command_line_parser.BoolParameter("vm_gc_generational")
+	this.command_line_parser.BoolParameter("vm_debug")
+
+	if this.command_line_parser.IntParameter("vm_gc_mark_budget") < 0 {
+		err := errors.New("vm_gc_mark_budget < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("vm_gc_major_interval") < 0 {
+		err := errors.New("vm_gc_major_interval < 0")
+		panic(err)
+	}
+
 	if this.command_line_parser.IntParameter("logic_frequency") <= 0 {
 		err := errors.New("logic_frequency <= 0")
 		panic(err)
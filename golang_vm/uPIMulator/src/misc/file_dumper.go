@@ -40,3 +40,10 @@ func (this *FileDumper) WriteLines(lines []string) {
 		panic(close_err)
 	}
 }
+
+func (this *FileDumper) WriteBytes(bytes []byte) {
+	write_err := os.WriteFile(this.path, bytes, 0666)
+	if write_err != nil {
+		panic(write_err)
+	}
+}
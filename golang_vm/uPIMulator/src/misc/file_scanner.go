@@ -37,3 +37,12 @@ func (this *FileScanner) ReadLines() []string {
 
 	return lines
 }
+
+func (this *FileScanner) ReadBytes() []byte {
+	bytes, read_err := os.ReadFile(this.path)
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	return bytes
+}
@@ -37,6 +37,11 @@ func (this *StatFactory) Increment(stat string, value int64) {
 	this.stats[stat] += value
 }
 
+// NOTE: Set overwrites a stat that is a level rather than a count, e.g. a maximum or a ratio
+func (this *StatFactory) Set(stat string, value int64) {
+	this.stats[stat] = value
+}
+
 func (this *StatFactory) ToLines() []string {
 	lines := make([]string, 0)
 	for stat, value := range this.stats {
@@ -4,8 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"uPIMulator/src/host/abi"
 	"uPIMulator/src/misc"
 )
@@ -17,7 +15,8 @@ type App struct {
 	num_dpus     int
 	num_tasklets int
 
-	labels []*abi.Label
+	labels        []*abi.Label
+	label_indices map[string]int
 }
 
 func (this *App) Init(command_line_parser *misc.CommandLineParser) {
@@ -31,16 +30,13 @@ func (this *App) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_tasklets = num_channels * num_ranks_per_channel * num_dpus_per_rank
 
 	this.labels = make([]*abi.Label, 0)
+	this.label_indices = make(map[string]int)
 	this.LoadLabels()
 }
 
 func (this *App) HasLabel(label_name string) bool {
-	for _, label := range this.labels {
-		if label.Name() == label_name {
-			return true
-		}
-	}
-	return false
+	_, found := this.label_indices[label_name]
+	return found
 }
 
 func (this *App) Label(label_name string) *abi.Label {
@@ -50,52 +46,26 @@ func (this *App) Label(label_name string) *abi.Label {
 		panic(err)
 	}
 
-	for _, label := range this.labels {
-		if label.Name() == label_name {
-			return label
-		}
-	}
+	return this.labels[this.label_indices[label_name]]
+}
 
-	return nil
+func (this *App) Labels() []*abi.Label {
+	return this.labels
 }
 
 func (this *App) LoadLabels() {
-	path := filepath.Join(this.bin_dirpath, "bytecode.txt")
+	path := filepath.Join(this.bin_dirpath, "bytecode.bin")
 
 	file_scanner := new(misc.FileScanner)
 	file_scanner.Init(path)
 
-	for _, line := range file_scanner.ReadLines() {
-		if strings.Contains(line, ":") {
-			label_name := line[:len(line)-1]
-
-			label := new(abi.Label)
-			label.Init(label_name)
-
-			this.labels = append(this.labels, label)
-		} else {
-			words := strings.Split(line, " ")
-
-			var op_code abi.OpCode
-			args := make([]int64, 0)
-			strs := make([]string, 0)
-			for i, word := range words {
-				if i == 0 {
-					op_code = this.ConvertToOpCode(word[1:])
-				} else {
-					if arg, err := strconv.ParseInt(word, 10, 64); err == nil {
-						args = append(args, arg)
-					} else {
-						strs = append(strs, word)
-					}
-				}
-			}
+	bytecode_decoder := new(abi.BytecodeDecoder)
+	bytecode_decoder.Init(file_scanner.ReadBytes())
 
-			bytecode := new(abi.Bytecode)
-			bytecode.Init(op_code, args, strs)
+	this.labels = bytecode_decoder.Decode()
 
-			this.labels[len(this.labels)-1].Append(bytecode)
-		}
+	for i, label := range this.labels {
+		this.label_indices[label.Name()] = i
 	}
 }
 
@@ -112,10 +82,16 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.PUSH_INT
 	} else if op_code == "PUSH_LONG" {
 		return abi.PUSH_LONG
+	} else if op_code == "PUSH_FLOAT" {
+		return abi.PUSH_FLOAT
+	} else if op_code == "PUSH_DOUBLE" {
+		return abi.PUSH_DOUBLE
 	} else if op_code == "PUSH_STRING" {
 		return abi.PUSH_STRING
 	} else if op_code == "POP" {
 		return abi.POP
+	} else if op_code == "DUPLICATE" {
+		return abi.DUPLICATE
 	} else if op_code == "BEGIN_STRUCT" {
 		return abi.BEGIN_STRUCT
 	} else if op_code == "APPEND_VOID" {
@@ -128,8 +104,14 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.APPEND_INT
 	} else if op_code == "APPEND_LONG" {
 		return abi.APPEND_LONG
+	} else if op_code == "APPEND_FLOAT" {
+		return abi.APPEND_FLOAT
+	} else if op_code == "APPEND_DOUBLE" {
+		return abi.APPEND_DOUBLE
 	} else if op_code == "APPEND_STRUCT" {
 		return abi.APPEND_STRUCT
+	} else if op_code == "APPEND_ARRAY" {
+		return abi.APPEND_ARRAY
 	} else if op_code == "END_STRUCT" {
 		return abi.END_STRUCT
 	} else if op_code == "NEW_GLOBAL_VOID" {
@@ -142,6 +124,12 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.NEW_GLOBAL_INT
 	} else if op_code == "NEW_GLOBAL_LONG" {
 		return abi.NEW_GLOBAL_LONG
+	} else if op_code == "NEW_GLOBAL_FLOAT" {
+		return abi.NEW_GLOBAL_FLOAT
+	} else if op_code == "NEW_GLOBAL_DOUBLE" {
+		return abi.NEW_GLOBAL_DOUBLE
+	} else if op_code == "NEW_GLOBAL_STRUCT" {
+		return abi.NEW_GLOBAL_STRUCT
 	} else if op_code == "NEW_FAST_VOID" {
 		return abi.NEW_FAST_VOID
 	} else if op_code == "NEW_FAST_CHAR" {
@@ -152,6 +140,10 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.NEW_FAST_INT
 	} else if op_code == "NEW_FAST_LONG" {
 		return abi.NEW_FAST_LONG
+	} else if op_code == "NEW_FAST_FLOAT" {
+		return abi.NEW_FAST_FLOAT
+	} else if op_code == "NEW_FAST_DOUBLE" {
+		return abi.NEW_FAST_DOUBLE
 	} else if op_code == "NEW_FAST_STRUCT" {
 		return abi.NEW_FAST_STRUCT
 	} else if op_code == "NEW_ARG_VOID" {
@@ -164,6 +156,10 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.NEW_ARG_INT
 	} else if op_code == "NEW_ARG_LONG" {
 		return abi.NEW_ARG_LONG
+	} else if op_code == "NEW_ARG_FLOAT" {
+		return abi.NEW_ARG_FLOAT
+	} else if op_code == "NEW_ARG_DOUBLE" {
+		return abi.NEW_ARG_DOUBLE
 	} else if op_code == "NEW_ARG_STRUCT" {
 		return abi.NEW_ARG_STRUCT
 	} else if op_code == "NEW_RETURN_VOID" {
@@ -176,6 +172,10 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.NEW_RETURN_INT
 	} else if op_code == "NEW_RETURN_LONG" {
 		return abi.NEW_RETURN_LONG
+	} else if op_code == "NEW_RETURN_FLOAT" {
+		return abi.NEW_RETURN_FLOAT
+	} else if op_code == "NEW_RETURN_DOUBLE" {
+		return abi.NEW_RETURN_DOUBLE
 	} else if op_code == "NEW_RETURN_STRUCT" {
 		return abi.NEW_RETURN_STRUCT
 	} else if op_code == "SIZEOF_VOID" {
@@ -188,8 +188,28 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.SIZEOF_INT
 	} else if op_code == "SIZEOF_LONG" {
 		return abi.SIZEOF_LONG
+	} else if op_code == "SIZEOF_FLOAT" {
+		return abi.SIZEOF_FLOAT
+	} else if op_code == "SIZEOF_DOUBLE" {
+		return abi.SIZEOF_DOUBLE
 	} else if op_code == "SIZEOF_STRUCT" {
 		return abi.SIZEOF_STRUCT
+	} else if op_code == "CAST_VOID" {
+		return abi.CAST_VOID
+	} else if op_code == "CAST_CHAR" {
+		return abi.CAST_CHAR
+	} else if op_code == "CAST_SHORT" {
+		return abi.CAST_SHORT
+	} else if op_code == "CAST_INT" {
+		return abi.CAST_INT
+	} else if op_code == "CAST_LONG" {
+		return abi.CAST_LONG
+	} else if op_code == "CAST_FLOAT" {
+		return abi.CAST_FLOAT
+	} else if op_code == "CAST_DOUBLE" {
+		return abi.CAST_DOUBLE
+	} else if op_code == "CAST_STRUCT" {
+		return abi.CAST_STRUCT
 	} else if op_code == "GET_IDENTIFIER" {
 		return abi.GET_IDENTIFIER
 	} else if op_code == "GET_ARG_IDENTIFIER" {
@@ -210,6 +230,24 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.FREE
 	} else if op_code == "ASSERT" {
 		return abi.ASSERT
+	} else if op_code == "PRINTF" {
+		return abi.PRINTF
+	} else if op_code == "EXIT" {
+		return abi.EXIT
+	} else if op_code == "ATOI" {
+		return abi.ATOI
+	} else if op_code == "RAND" {
+		return abi.RAND
+	} else if op_code == "SRAND" {
+		return abi.SRAND
+	} else if op_code == "GETTIMEOFDAY" {
+		return abi.GETTIMEOFDAY
+	} else if op_code == "GETOPT" {
+		return abi.GETOPT
+	} else if op_code == "GET_ARGC" {
+		return abi.GET_ARGC
+	} else if op_code == "GET_ARGV" {
+		return abi.GET_ARGV
 	} else if op_code == "ADD" {
 		return abi.ADD
 	} else if op_code == "SUB" {
@@ -298,20 +336,40 @@ func (this *App) ConvertToOpCode(op_code string) abi.OpCode {
 		return abi.NOP
 	} else if op_code == "DPU_ALLOC" {
 		return abi.DPU_ALLOC
+	} else if op_code == "DPU_ALLOC_RANKS" {
+		return abi.DPU_ALLOC_RANKS
 	} else if op_code == "DPU_LOAD" {
 		return abi.DPU_LOAD
 	} else if op_code == "DPU_PREPARE" {
 		return abi.DPU_PREPARE
 	} else if op_code == "DPU_TRANSFER" {
 		return abi.DPU_TRANSFER
+	} else if op_code == "DPU_BROADCAST" {
+		return abi.DPU_BROADCAST
 	} else if op_code == "DPU_COPY_TO" {
 		return abi.DPU_COPY_TO
 	} else if op_code == "DPU_COPY_FROM" {
 		return abi.DPU_COPY_FROM
 	} else if op_code == "DPU_LAUNCH" {
 		return abi.DPU_LAUNCH
+	} else if op_code == "DPU_SYNC" {
+		return abi.DPU_SYNC
 	} else if op_code == "DPU_FREE" {
 		return abi.DPU_FREE
+	} else if op_code == "DPU_NR_DPUS" {
+		return abi.DPU_NR_DPUS
+	} else if op_code == "DPU_NR_RANKS" {
+		return abi.DPU_NR_RANKS
+	} else if op_code == "DPU_GET_DPU" {
+		return abi.DPU_GET_DPU
+	} else if op_code == "DPU_GET_RANK" {
+		return abi.DPU_GET_RANK
+	} else if op_code == "DPU_NR_CALLBACKS" {
+		return abi.DPU_NR_CALLBACKS
+	} else if op_code == "DPU_GET_CALLBACK" {
+		return abi.DPU_GET_CALLBACK
+	} else if op_code == "DPU_LOG_READ" {
+		return abi.DPU_LOG_READ
 	} else {
 		err_msg := fmt.Sprintf("op code (%s) is not valid", op_code)
 		err := errors.New(err_msg)
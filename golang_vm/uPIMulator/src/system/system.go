@@ -25,6 +25,8 @@ func (this *System) Simulate(app *program.App, task *program.Task) {
 	for this.vm.CanAdvance() {
 		this.vm.Advance()
 	}
+
+	this.vm.Sync()
 }
 
 func (this *System) Dump() {